@@ -2,6 +2,7 @@ package main
 
 import (
 	"log"
+	_ "time/tzdata"
 
 	"github.com/kirillidk/pvz-service/internal/app"
 	"github.com/kirillidk/pvz-service/internal/config"
@@ -11,6 +11,7 @@ import (
 	"github.com/kirillidk/pvz-service/internal/config"
 	grpcserver "github.com/kirillidk/pvz-service/internal/grpc"
 	"github.com/kirillidk/pvz-service/internal/handler"
+	"github.com/kirillidk/pvz-service/internal/middleware"
 	"github.com/kirillidk/pvz-service/internal/repository"
 	"github.com/kirillidk/pvz-service/internal/route"
 	"github.com/kirillidk/pvz-service/internal/service"
@@ -35,13 +36,20 @@ func NewApp(cfg *config.Config) (*App, error) {
 	}
 
 	repo := repository.NewRepository(db)
-	serv := service.NewService(repo, cfg.JWT.JWTSecret)
+	serv, err := service.NewService(repo, cfg)
+	if err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to initialize services: %w", err)
+	}
 	handl := handler.NewHandler(serv, cfg)
 
 	rtr := gin.Default()
-	route.SetupRoutes(rtr, handl, cfg.JWT.JWTSecret)
+	rtr.Use(middleware.RequestIDMiddleware())
+	authMiddleware := middleware.AuthMiddleware(cfg.JWT.JWTSecret, serv.APIKeyService)
+	idempotencyMiddleware := middleware.IdempotencyMiddleware(repo.IdempotencyRepository, cfg.Idempotency)
+	route.SetupRoutes(rtr, handl, cfg.JWT.JWTSecret, authMiddleware, idempotencyMiddleware)
 
-	grpcPVZService := grpcservice.NewPVZService(repo.PVZRepository)
+	grpcPVZService := grpcservice.NewPVZService(repo.PVZRepository, serv.PVZService)
 	grpcSrv := grpcserver.NewServer(cfg, grpcPVZService)
 
 	return &App{
@@ -71,6 +79,18 @@ func (a *App) Run() error {
 		}
 	}()
 
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		a.Service.OverdueService.RunReturnScheduler(ctx)
+	}()
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		a.Service.IdempotencyService.RunPurgeScheduler(ctx)
+	}()
+
 	wg.Add(1)
 	go func() {
 		defer wg.Done()
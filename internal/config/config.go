@@ -1,12 +1,24 @@
 package config
 
-import "os"
+import (
+	"os"
+	"strconv"
+	"time"
+)
 
 type Config struct {
-	Server   ServerConfig
-	Database DatabaseConfig
-	JWT      JWTConfig
-	GRPC     GRPCConfig
+	Server    ServerConfig
+	Database  DatabaseConfig
+	JWT       JWTConfig
+	GRPC      GRPCConfig
+	TwoFactor TwoFactorConfig
+
+	Idempotency IdempotencyConfig
+	Barcode     BarcodeConfig
+	Pickup      PickupConfig
+	Storage     StorageConfig
+	Attachment  AttachmentConfig
+	Reception   ReceptionConfig
 }
 
 type ServerConfig struct {
@@ -30,6 +42,66 @@ type GRPCConfig struct {
 	Port string
 }
 
+// TwoFactorConfig.MaxAttempts is the number of wrong second-factor codes
+// in a row after which the user cannot complete two-factor authentication
+// for LockoutDuration.
+type TwoFactorConfig struct {
+	Issuer                string
+	RequiredForModerators bool
+	MaxAttempts           int
+	LockoutDuration       time.Duration
+}
+
+// IdempotencyConfig.TTL is how long a completed response is replayed.
+// Lease is how long a key stays claimed by a request that has not
+// completed, so that a crashed request does not block retries for the
+// whole TTL. MaxBodyMB limits the size of request bodies read for hashing,
+// and expired keys are purged every PurgeInterval.
+type IdempotencyConfig struct {
+	TTL           time.Duration
+	Lease         time.Duration
+	MaxBodyMB     int
+	PurgeInterval time.Duration
+}
+
+// BarcodeConfig.Pattern is a regular expression for product barcodes
+// accepted in addition to EAN-13, such as carrier tracking numbers.
+type BarcodeConfig struct {
+	Pattern string
+}
+
+// PickupConfig.MaxAttempts is the number of wrong pickup codes after which
+// an order can only be issued with a newly generated code. MaxRegenerations
+// limits how many new codes may be sent for one order. Notifier selects how
+// codes reach recipients: "log" writes notifications with the code masked,
+// "log-plaintext" includes the code and is meant for local development only.
+type PickupConfig struct {
+	MaxAttempts      int
+	MaxRegenerations int
+	Notifier         string
+}
+
+// StorageConfig.DefaultDays is the storage period of product types without
+// their own one. ReturnCheckInterval is how often overdue products are
+// looked for.
+type StorageConfig struct {
+	DefaultDays         int
+	ReturnCheckInterval time.Duration
+}
+
+// AttachmentConfig.Dir is the directory product attachments are kept in.
+// MaxSizeMB limits the size of a single uploaded file.
+type AttachmentConfig struct {
+	Dir       string
+	MaxSizeMB int
+}
+
+// ReceptionConfig.ReopenWindow is how long after closing a moderator may
+// still reopen a reception.
+type ReceptionConfig struct {
+	ReopenWindow time.Duration
+}
+
 func NewConfig() *Config {
 	return &Config{
 		Server: ServerConfig{
@@ -49,5 +121,67 @@ func NewConfig() *Config {
 		GRPC: GRPCConfig{
 			Port: os.Getenv("GRPC_PORT"),
 		},
+		TwoFactor: TwoFactorConfig{
+			Issuer:                getEnv("TOTP_ISSUER", "PVZ Service"),
+			RequiredForModerators: getEnvBool("MFA_REQUIRED_FOR_MODERATORS", false),
+			MaxAttempts:           getEnvInt("MFA_MAX_ATTEMPTS", 5),
+			LockoutDuration:       getEnvDuration("MFA_LOCKOUT_DURATION", 15*time.Minute),
+		},
+		Idempotency: IdempotencyConfig{
+			TTL:           getEnvDuration("IDEMPOTENCY_KEY_TTL", 24*time.Hour),
+			Lease:         getEnvDuration("IDEMPOTENCY_KEY_LEASE", time.Minute),
+			MaxBodyMB:     getEnvInt("IDEMPOTENCY_MAX_BODY_MB", 16),
+			PurgeInterval: getEnvDuration("IDEMPOTENCY_PURGE_INTERVAL", time.Hour),
+		},
+		Barcode: BarcodeConfig{
+			Pattern: os.Getenv("BARCODE_PATTERN"),
+		},
+		Pickup: PickupConfig{
+			MaxAttempts:      getEnvInt("PICKUP_CODE_MAX_ATTEMPTS", 5),
+			MaxRegenerations: getEnvInt("PICKUP_CODE_MAX_REGENERATIONS", 3),
+			Notifier:         getEnv("PICKUP_NOTIFIER", "log"),
+		},
+		Storage: StorageConfig{
+			DefaultDays:         getEnvInt("STORAGE_PERIOD_DAYS", 14),
+			ReturnCheckInterval: getEnvDuration("RETURN_CHECK_INTERVAL", time.Hour),
+		},
+		Attachment: AttachmentConfig{
+			Dir:       getEnv("ATTACHMENT_DIR", "attachments"),
+			MaxSizeMB: getEnvInt("ATTACHMENT_MAX_SIZE_MB", 10),
+		},
+		Reception: ReceptionConfig{
+			ReopenWindow: getEnvDuration("RECEPTION_REOPEN_WINDOW", 24*time.Hour),
+		},
+	}
+}
+
+func getEnv(key, defaultValue string) string {
+	if value, ok := os.LookupEnv(key); ok && value != "" {
+		return value
+	}
+	return defaultValue
+}
+
+func getEnvBool(key string, defaultValue bool) bool {
+	value, err := strconv.ParseBool(os.Getenv(key))
+	if err != nil {
+		return defaultValue
+	}
+	return value
+}
+
+func getEnvInt(key string, defaultValue int) int {
+	value, err := strconv.Atoi(os.Getenv(key))
+	if err != nil || value <= 0 {
+		return defaultValue
+	}
+	return value
+}
+
+func getEnvDuration(key string, defaultValue time.Duration) time.Duration {
+	value, err := time.ParseDuration(os.Getenv(key))
+	if err != nil || value <= 0 {
+		return defaultValue
 	}
+	return value
 }
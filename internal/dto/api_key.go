@@ -0,0 +1,19 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/kirillidk/pvz-service/internal/model"
+)
+
+type APIKeyCreateRequest struct {
+	Name      string         `json:"name" binding:"required,max=255"`
+	Role      model.UserRole `json:"role" binding:"required,oneof=employee moderator"`
+	PVZIDs    []string       `json:"pvzIds" binding:"omitempty,dive,uuid"`
+	ExpiresAt *time.Time     `json:"expiresAt" format:"date-time"`
+}
+
+type APIKeyCreateResponse struct {
+	APIKey model.APIKey `json:"apiKey"`
+	Key    string       `json:"key"`
+}
@@ -0,0 +1,15 @@
+package dto
+
+import (
+	"time"
+)
+
+type AuditFilterQuery struct {
+	EntityType string     `form:"entityType" binding:"omitempty,oneof=pvz reception product order storage_cell transfer attachment expected_delivery"`
+	EntityID   string     `form:"entityId" binding:"omitempty,uuid"`
+	ActorID    string     `form:"actorId" binding:"omitempty"`
+	StartDate  *time.Time `form:"startDate"`
+	EndDate    *time.Time `form:"endDate"`
+	Page       int32      `form:"page,default=1" binding:"min=1"`
+	Limit      int32      `form:"limit,default=10" binding:"min=1,max=100"`
+}
@@ -16,3 +16,28 @@ type LoginRequest struct {
 	Email    string `json:"email" binding:"required,email"`
 	Password string `json:"password" binding:"required,min=6"`
 }
+
+// LoginResponse carries either a full access token or, when a second factor
+// is needed, a short-lived MFA token to be exchanged at /login/2fa or used
+// for enrollment at /2fa/enroll.
+type LoginResponse struct {
+	Token                 string `json:"token,omitempty"`
+	MFARequired           bool   `json:"mfaRequired,omitempty"`
+	MFAEnrollmentRequired bool   `json:"mfaEnrollmentRequired,omitempty"`
+	MFAToken              string `json:"mfaToken,omitempty"`
+}
+
+type TwoFactorLoginRequest struct {
+	MFAToken string `json:"mfaToken" binding:"required"`
+	Code     string `json:"code" binding:"required"`
+}
+
+type TwoFactorConfirmRequest struct {
+	Code string `json:"code" binding:"required,len=6,numeric"`
+}
+
+type TwoFactorEnrollResponse struct {
+	Secret          string   `json:"secret"`
+	ProvisioningURI string   `json:"provisioningUri"`
+	RecoveryCodes   []string `json:"recoveryCodes"`
+}
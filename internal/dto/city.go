@@ -0,0 +1,15 @@
+package dto
+
+type CityCreateRequest struct {
+	Name     string `json:"name" binding:"required,max=50"`
+	Region   string `json:"region" binding:"max=100"`
+	Timezone string `json:"timezone" binding:"required,max=64"`
+	Active   *bool  `json:"active"`
+}
+
+type CityUpdateRequest struct {
+	Name     *string `json:"name" binding:"omitempty,min=1,max=50"`
+	Region   *string `json:"region" binding:"omitempty,max=100"`
+	Timezone *string `json:"timezone" binding:"omitempty,min=1,max=64"`
+	Active   *bool   `json:"active"`
+}
@@ -0,0 +1,10 @@
+package dto
+
+type ExpectedDeliveryCreateRequest struct {
+	Items []ExpectedDeliveryItemRequest `json:"items" binding:"required,min=1,max=1000,dive"`
+}
+
+type ExpectedDeliveryItemRequest struct {
+	Barcode string `json:"barcode" binding:"required,max=64"`
+	Type    string `json:"type" binding:"required,max=20"`
+}
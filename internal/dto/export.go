@@ -0,0 +1,9 @@
+package dto
+
+// ReceptionExportQuery accepts the filters and sort of GET /pvz. Page and
+// limit are ignored, the export covers every matching row.
+type ReceptionExportQuery struct {
+	PVZFilterQuery
+
+	Format string `form:"format" binding:"required,oneof=csv xlsx"`
+}
@@ -0,0 +1,20 @@
+package dto
+
+import "github.com/kirillidk/pvz-service/internal/model"
+
+type OrderCreateRequest struct {
+	Recipient  string   `json:"recipient" binding:"required,max=255"`
+	ProductIDs []string `json:"productIds" binding:"required,min=1,max=100,dive,uuid"`
+}
+
+type OrderIssueRequest struct {
+	OrderID string `json:"orderId" binding:"required,uuid"`
+	Code    string `json:"code" binding:"required,len=6,numeric"`
+}
+
+// OrderIssueResponse is an issued order together with its products in
+// their new status.
+type OrderIssueResponse struct {
+	Order    model.Order     `json:"order"`
+	Products []model.Product `json:"products"`
+}
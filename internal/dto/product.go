@@ -1,6 +1,61 @@
 package dto
 
+import "github.com/kirillidk/pvz-service/internal/model"
+
 type ProductCreateRequest struct {
-	Type  string `json:"type" binding:"required,oneof=электроника одежда обувь"`
-	PVZID string `json:"pvzId" binding:"required,uuid"`
+	Type         string `json:"type" binding:"required,max=20"`
+	PVZID        string `json:"pvzId" binding:"required,uuid"`
+	SerialNumber string `json:"serialNumber" binding:"omitempty,max=100"`
+	Barcode      string `json:"barcode" binding:"omitempty,max=64"`
+	CellID       string `json:"cellId" binding:"omitempty,uuid"`
+
+	// AllowedPVZIDs limits the PVZs that barcode warnings may name. It is
+	// not part of the body: handlers fill it from the allow-list of the
+	// API key.
+	AllowedPVZIDs []string `json:"-"`
+}
+
+func (r ProductCreateRequest) Attributes() ProductAttributes {
+	return ProductAttributes{
+		Type:         r.Type,
+		SerialNumber: r.SerialNumber,
+		Barcode:      r.Barcode,
+		CellID:       r.CellID,
+	}
+}
+
+type ProductBatchCreateRequest struct {
+	PVZID    string              `json:"pvzId" binding:"required,uuid"`
+	Products []ProductAttributes `json:"products" binding:"required,min=1,max=1000,dive"`
+
+	// AllowedPVZIDs limits the PVZs that barcode warnings may name, as in
+	// ProductCreateRequest.
+	AllowedPVZIDs []string `json:"-"`
+}
+
+// ProductAttributes describes a single product to be added to a reception.
+type ProductAttributes struct {
+	Type         string `json:"type" binding:"required,max=20"`
+	SerialNumber string `json:"serialNumber" binding:"omitempty,max=100"`
+	Barcode      string `json:"barcode" binding:"omitempty,max=64"`
+	CellID       string `json:"cellId" binding:"omitempty,uuid"`
+}
+
+type ProductSearchQuery struct {
+	Barcode string `form:"barcode" binding:"required,max=64"`
+}
+
+// ProductCreateResponse is a created product with warnings that did not
+// prevent its creation, such as a barcode already received at another PVZ.
+// SuggestedCell is the least full cell that fits a product created without
+// a cell.
+type ProductCreateResponse struct {
+	model.Product
+	Warnings      []string           `json:"warnings,omitempty"`
+	SuggestedCell *model.StorageCell `json:"suggestedCell,omitempty"`
+}
+
+type ProductConditionRequest struct {
+	Condition model.ProductCondition `json:"condition" binding:"required,oneof=ok damaged opened"`
+	Note      string                 `json:"note" binding:"omitempty,max=1000"`
 }
@@ -0,0 +1,31 @@
+package dto
+
+import "encoding/json"
+
+type ProductTypeCreateRequest struct {
+	Code                 string `json:"code" binding:"required,max=20"`
+	Name                 string `json:"name" binding:"required,max=100"`
+	Active               *bool  `json:"active"`
+	RequiresSerialNumber bool   `json:"requiresSerialNumber"`
+	StorageDays          *int   `json:"storageDays" binding:"omitempty,min=1,max=365"`
+}
+
+type ProductTypeUpdateRequest struct {
+	Name                 *string     `json:"name" binding:"omitempty,min=1,max=100"`
+	Active               *bool       `json:"active"`
+	RequiresSerialNumber *bool       `json:"requiresSerialNumber"`
+	StorageDays          NullableInt `json:"storageDays"`
+}
+
+// NullableInt is an optional JSON number that can also be explicitly null.
+// Set reports whether the field was present in the request; a null value
+// leaves Value nil.
+type NullableInt struct {
+	Set   bool
+	Value *int
+}
+
+func (n *NullableInt) UnmarshalJSON(data []byte) error {
+	n.Set = true
+	return json.Unmarshal(data, &n.Value)
+}
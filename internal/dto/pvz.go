@@ -7,15 +7,94 @@ import (
 )
 
 type PVZCreateRequest struct {
-	RegistrationDate time.Time `json:"registrationDate" format:"date-time"`
-	City             string    `json:"city" binding:"required,oneof=Москва Санкт-Петербург Казань"`
+	RegistrationDate time.Time           `json:"registrationDate" format:"date-time"`
+	City             string              `json:"city" binding:"required,max=50"`
+	Address          string              `json:"address" binding:"max=255"`
+	Latitude         *float64            `json:"latitude" binding:"omitempty,min=-90,max=90"`
+	Longitude        *float64            `json:"longitude" binding:"omitempty,min=-180,max=180"`
+	Phone            string              `json:"phone" binding:"omitempty,e164"`
+	WorkingHours     *model.WorkingHours `json:"workingHours"`
 }
 
+type PVZUpdateRequest struct {
+	Address      *string             `json:"address" binding:"omitempty,max=255"`
+	Latitude     *float64            `json:"latitude" binding:"omitempty,min=-90,max=90"`
+	Longitude    *float64            `json:"longitude" binding:"omitempty,min=-180,max=180"`
+	Phone        *string             `json:"phone" binding:"omitempty,e164"`
+	WorkingHours *model.WorkingHours `json:"workingHours"`
+}
+
+const (
+	PVZSortRegistrationDate = "registrationDate"
+	PVZSortCity             = "city"
+	PVZSortLastReception    = "lastReception"
+)
+
 type PVZFilterQuery struct {
 	StartDate *time.Time `form:"startDate"`
 	EndDate   *time.Time `form:"endDate"`
 	Page      int32      `form:"page,default=1" binding:"min=1"`
 	Limit     int32      `form:"limit,default=10" binding:"min=1,max=30"`
+
+	IncludeArchived bool `form:"includeArchived"`
+
+	City             []string `form:"city" binding:"omitempty,dive,max=50"`
+	HasOpenReception bool     `form:"hasOpenReception"`
+	ProductType      string   `form:"productType" binding:"max=50"`
+	MinProducts      int32    `form:"minProducts" binding:"min=0"`
+	Sort             string   `form:"sort,default=registrationDate" binding:"oneof=registrationDate city lastReception"`
+
+	// PVZIDs restricts the list to the given PVZs. It is not a query
+	// parameter: handlers fill it from the allow-list of the API key.
+	PVZIDs []string `form:"-"`
+}
+
+// ReceptionFilter returns the reception-level part of the filter. A PVZ
+// matches when at least one of its receptions satisfies all conditions,
+// and only such receptions are returned for it. MinProducts is not part of
+// it: the product count is a condition on the PVZ as a whole.
+func (q PVZFilterQuery) ReceptionFilter() ReceptionFilter {
+	return ReceptionFilter{
+		StartDate:   q.StartDate,
+		EndDate:     q.EndDate,
+		InProgress:  q.HasOpenReception,
+		ProductType: q.ProductType,
+	}
+}
+
+type ReceptionFilter struct {
+	StartDate   *time.Time
+	EndDate     *time.Time
+	InProgress  bool
+	ProductType string
+}
+
+func (f ReceptionFilter) IsEmpty() bool {
+	return f.StartDate == nil && f.EndDate == nil && !f.InProgress && f.ProductType == ""
+}
+
+type PVZNearbyQuery struct {
+	Latitude  *float64 `form:"lat" binding:"required,min=-90,max=90"`
+	Longitude *float64 `form:"lon" binding:"required,min=-180,max=180"`
+	Radius    float64  `form:"radius,default=5000" binding:"gt=0,max=50000"`
+	Limit     int32    `form:"limit,default=10" binding:"min=1,max=100"`
+	OpenNow   bool     `form:"openNow"`
+
+	// PVZIDs is filled from the API key allow-list, as in PVZFilterQuery.
+	PVZIDs []string `form:"-"`
+}
+
+type PVZURI struct {
+	PVZID string `uri:"pvzId" binding:"required,uuid"`
+}
+
+type PVZDetailsQuery struct {
+	IncludeReceptions bool `form:"includeReceptions"`
+}
+
+type PVZDetailsResponse struct {
+	PVZ        model.PVZ                       `json:"pvz"`
+	Receptions []ReceptionWithProductsResponse `json:"receptions,omitempty"`
 }
 
 type PVZWithReceptionsResponse struct {
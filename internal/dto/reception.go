@@ -1,5 +1,51 @@
 package dto
 
+import (
+	"time"
+
+	"github.com/kirillidk/pvz-service/internal/model"
+)
+
+// ReceptionCreateRequest.ExpectedCount is the number of products in the
+// courier's manifest, when it is known before the reception starts.
 type ReceptionCreateRequest struct {
-	PVZID string `json:"pvzId" binding:"required,uuid"`
+	PVZID         string `json:"pvzId" binding:"required,uuid"`
+	ExpectedCount *int   `json:"expectedCount" binding:"omitempty,min=0,max=100000"`
+}
+
+type ReceptionURI struct {
+	ReceptionID string `uri:"receptionId" binding:"required,uuid"`
+}
+
+type ReceptionManifestRequest struct {
+	ExpectedCount *int `json:"expectedCount" binding:"required,min=0,max=100000"`
+}
+
+// ReceptionReopenRequest.Reason justifies reopening a closed reception and
+// is kept in its status history.
+type ReceptionReopenRequest struct {
+	Reason string `json:"reason" binding:"required,max=1000"`
+}
+
+type DiscrepancyFilterQuery struct {
+	PVZID     string     `form:"pvzId" binding:"omitempty,uuid"`
+	StartDate *time.Time `form:"startDate"`
+	EndDate   *time.Time `form:"endDate"`
+	Page      int32      `form:"page,default=1" binding:"min=1"`
+	Limit     int32      `form:"limit,default=10" binding:"min=1,max=100"`
+}
+
+// ReceptionDiscrepancyReport lists the damaged and opened products of a
+// reception together with its counts.
+type ReceptionDiscrepancyReport struct {
+	model.ReceptionDiscrepancy
+	HasDiscrepancy bool            `json:"hasDiscrepancy"`
+	Products       []model.Product `json:"products"`
+}
+
+// ReceptionCloseResponse is a closed reception. Reconciliation is set when
+// an expected delivery was linked to the reception.
+type ReceptionCloseResponse struct {
+	model.Reception
+	Reconciliation *model.DeliveryReconciliation `json:"reconciliation,omitempty"`
 }
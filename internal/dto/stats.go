@@ -0,0 +1,27 @@
+package dto
+
+import (
+	"time"
+)
+
+type DailyStatsQuery struct {
+	From time.Time `form:"from" time_format:"2006-01-02" binding:"required"`
+	To   time.Time `form:"to" time_format:"2006-01-02" binding:"required"`
+
+	// IncludeIssued adds the number of products issued to customers on
+	// each day.
+	IncludeIssued bool `form:"includeIssued"`
+
+	PVZID string `form:"-"`
+}
+
+const (
+	ThroughputGroupByPVZ  = "pvz"
+	ThroughputGroupByCity = "city"
+)
+
+type ReceptionThroughputQuery struct {
+	From    time.Time `form:"from" time_format:"2006-01-02" binding:"required"`
+	To      time.Time `form:"to" time_format:"2006-01-02" binding:"required"`
+	GroupBy string    `form:"groupBy,default=pvz" binding:"oneof=pvz city"`
+}
@@ -0,0 +1,17 @@
+package dto
+
+type StorageCellCreateRequest struct {
+	Code        string `json:"code" binding:"required,max=20"`
+	Capacity    int    `json:"capacity" binding:"required,min=1,max=10000"`
+	ProductType string `json:"productType" binding:"omitempty,max=20"`
+}
+
+type StorageCellUpdateRequest struct {
+	Code        *string `json:"code" binding:"omitempty,min=1,max=20"`
+	Capacity    *int    `json:"capacity" binding:"omitempty,min=1,max=10000"`
+	ProductType *string `json:"productType" binding:"omitempty,max=20"`
+}
+
+type ProductCellAssignRequest struct {
+	CellID string `json:"cellId" binding:"required,uuid"`
+}
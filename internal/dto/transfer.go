@@ -0,0 +1,6 @@
+package dto
+
+type TransferCreateRequest struct {
+	DestinationPVZID string   `json:"destinationPvzId" binding:"required,uuid"`
+	ProductIDs       []string `json:"productIds" binding:"required,min=1,max=1000,dive,uuid"`
+}
@@ -0,0 +1,119 @@
+// Package filestorage keeps uploaded files, such as photos attached to
+// products. The service depends only on the Storage interface, so the local
+// disk can be replaced with an object store without touching the business
+// logic.
+package filestorage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+var (
+	ErrNotFound   = errors.New("file not found")
+	ErrInvalidKey = errors.New("invalid file key")
+)
+
+type Storage interface {
+	// Save writes the content of r under key and returns the number of bytes
+	// written. An existing file with the same key is replaced.
+	Save(ctx context.Context, key string, r io.Reader) (int64, error)
+	Open(ctx context.Context, key string) (io.ReadCloser, error)
+	Delete(ctx context.Context, key string) error
+}
+
+// LocalStorage keeps files in a directory of the local filesystem. Keys are
+// slash-separated paths relative to that directory.
+type LocalStorage struct {
+	root string
+}
+
+func NewLocalStorage(root string) *LocalStorage {
+	return &LocalStorage{root: root}
+}
+
+func (s *LocalStorage) Save(ctx context.Context, key string, r io.Reader) (int64, error) {
+	path, err := s.path(key)
+	if err != nil {
+		return 0, err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return 0, fmt.Errorf("failed to create directory: %w", err)
+	}
+
+	// The file is written under a temporary name and renamed once complete,
+	// so that a failed upload never leaves a truncated file behind.
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".upload-*")
+	if err != nil {
+		return 0, fmt.Errorf("failed to create file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	written, err := io.Copy(tmp, r)
+	if err != nil {
+		tmp.Close()
+		return 0, fmt.Errorf("failed to write file: %w", err)
+	}
+
+	if err := tmp.Close(); err != nil {
+		return 0, fmt.Errorf("failed to write file: %w", err)
+	}
+
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return 0, fmt.Errorf("failed to save file: %w", err)
+	}
+
+	return written, nil
+}
+
+func (s *LocalStorage) Open(ctx context.Context, key string) (io.ReadCloser, error) {
+	path, err := s.path(key)
+	if err != nil {
+		return nil, err
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, ErrNotFound
+		}
+		return nil, fmt.Errorf("failed to open file: %w", err)
+	}
+
+	return file, nil
+}
+
+func (s *LocalStorage) Delete(ctx context.Context, key string) error {
+	path, err := s.path(key)
+	if err != nil {
+		return err
+	}
+
+	if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("failed to delete file: %w", err)
+	}
+
+	return nil
+}
+
+// path resolves key inside the root directory and rejects keys that would
+// point outside of it.
+func (s *LocalStorage) path(key string) (string, error) {
+	if key == "" || strings.HasPrefix(key, "/") || strings.Contains(key, "\\") {
+		return "", ErrInvalidKey
+	}
+
+	for _, part := range strings.Split(key, "/") {
+		if part == "" || part == "." || part == ".." {
+			return "", ErrInvalidKey
+		}
+	}
+
+	return filepath.Join(s.root, filepath.FromSlash(key)), nil
+}
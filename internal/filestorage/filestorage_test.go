@@ -0,0 +1,67 @@
+package filestorage_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/kirillidk/pvz-service/internal/filestorage"
+)
+
+func TestLocalStorage_SaveOpenDelete(t *testing.T) {
+	ctx := context.Background()
+	s := filestorage.NewLocalStorage(t.TempDir())
+
+	written, err := s.Save(ctx, "products/photo.jpg", strings.NewReader("content"))
+	if err != nil {
+		t.Fatalf("Save() unexpected error = %v", err)
+	}
+	if written != int64(len("content")) {
+		t.Errorf("Expected %d bytes written, got %d", len("content"), written)
+	}
+
+	file, err := s.Open(ctx, "products/photo.jpg")
+	if err != nil {
+		t.Fatalf("Open() unexpected error = %v", err)
+	}
+	data, err := io.ReadAll(file)
+	file.Close()
+	if err != nil {
+		t.Fatalf("ReadAll() unexpected error = %v", err)
+	}
+	if string(data) != "content" {
+		t.Errorf("Expected content %q, got %q", "content", data)
+	}
+
+	if err := s.Delete(ctx, "products/photo.jpg"); err != nil {
+		t.Fatalf("Delete() unexpected error = %v", err)
+	}
+
+	if _, err := s.Open(ctx, "products/photo.jpg"); !errors.Is(err, filestorage.ErrNotFound) {
+		t.Errorf("Expected error %v after delete, got %v", filestorage.ErrNotFound, err)
+	}
+
+	if err := s.Delete(ctx, "products/photo.jpg"); err != nil {
+		t.Errorf("Delete() of a missing file unexpected error = %v", err)
+	}
+}
+
+func TestLocalStorage_InvalidKey(t *testing.T) {
+	ctx := context.Background()
+	s := filestorage.NewLocalStorage(t.TempDir())
+
+	keys := []string{"", "/etc/passwd", "../outside", "products/../../outside", "products//photo.jpg", `products\photo.jpg`}
+
+	for _, key := range keys {
+		t.Run(key, func(t *testing.T) {
+			if _, err := s.Save(ctx, key, strings.NewReader("content")); !errors.Is(err, filestorage.ErrInvalidKey) {
+				t.Errorf("Save() expected error %v, got %v", filestorage.ErrInvalidKey, err)
+			}
+			if _, err := s.Open(ctx, key); !errors.Is(err, filestorage.ErrInvalidKey) {
+				t.Errorf("Open() expected error %v, got %v", filestorage.ErrInvalidKey, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,67 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/kirillidk/pvz-service/internal/dto"
+	"github.com/kirillidk/pvz-service/internal/model"
+	service "github.com/kirillidk/pvz-service/internal/service/apikey"
+)
+
+type APIKeyHandler struct {
+	apiKeyService service.APIKeyServiceInterface
+}
+
+func NewAPIKeyHandler(apiKeyService service.APIKeyServiceInterface) *APIKeyHandler {
+	return &APIKeyHandler{
+		apiKeyService: apiKeyService,
+	}
+}
+
+func (h *APIKeyHandler) CreateAPIKey(c *gin.Context) {
+	var apiKeyReq dto.APIKeyCreateRequest
+	if err := c.ShouldBindJSON(&apiKeyReq); err != nil {
+		c.JSON(http.StatusBadRequest, model.Error{Message: "Invalid request data"})
+		return
+	}
+
+	createdKey, err := h.apiKeyService.CreateAPIKey(c.Request.Context(), apiKeyReq)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, model.Error{Message: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, createdKey)
+}
+
+func (h *APIKeyHandler) GetAPIKeyList(c *gin.Context) {
+	apiKeys, err := h.apiKeyService.GetAPIKeyList(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, model.Error{Message: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, apiKeys)
+}
+
+func (h *APIKeyHandler) RevokeAPIKey(c *gin.Context) {
+	apiKeyID := c.Param("apiKeyId")
+	if apiKeyID == "" {
+		c.JSON(http.StatusBadRequest, model.Error{Message: "API key ID is required"})
+		return
+	}
+
+	revokedKey, err := h.apiKeyService.RevokeAPIKey(c.Request.Context(), apiKeyID)
+	if err != nil {
+		if errors.Is(err, model.ErrAPIKeyNotFound) {
+			c.JSON(http.StatusNotFound, model.Error{Message: err.Error()})
+			return
+		}
+		c.JSON(http.StatusBadRequest, model.Error{Message: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, revokedKey)
+}
@@ -0,0 +1,183 @@
+package handler_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/kirillidk/pvz-service/internal/dto"
+	"github.com/kirillidk/pvz-service/internal/handler"
+	"github.com/kirillidk/pvz-service/internal/model"
+)
+
+type MockAPIKeyService struct {
+	CreateAPIKeyFunc  func(ctx context.Context, req dto.APIKeyCreateRequest) (*dto.APIKeyCreateResponse, error)
+	GetAPIKeyListFunc func(ctx context.Context) ([]model.APIKey, error)
+	RevokeAPIKeyFunc  func(ctx context.Context, apiKeyID string) (*model.APIKey, error)
+	AuthenticateFunc  func(ctx context.Context, rawKey string) (*model.APIKey, error)
+}
+
+func (m *MockAPIKeyService) CreateAPIKey(ctx context.Context, req dto.APIKeyCreateRequest) (*dto.APIKeyCreateResponse, error) {
+	return m.CreateAPIKeyFunc(ctx, req)
+}
+
+func (m *MockAPIKeyService) GetAPIKeyList(ctx context.Context) ([]model.APIKey, error) {
+	return m.GetAPIKeyListFunc(ctx)
+}
+
+func (m *MockAPIKeyService) RevokeAPIKey(ctx context.Context, apiKeyID string) (*model.APIKey, error) {
+	return m.RevokeAPIKeyFunc(ctx, apiKeyID)
+}
+
+func (m *MockAPIKeyService) Authenticate(ctx context.Context, rawKey string) (*model.APIKey, error) {
+	return m.AuthenticateFunc(ctx, rawKey)
+}
+
+func TestAPIKeyHandler_CreateAPIKey(t *testing.T) {
+	tests := []struct {
+		name           string
+		mockService    MockAPIKeyService
+		requestBody    map[string]any
+		expectedStatus int
+		expectedBody   any
+	}{
+		{
+			name: "Success",
+			mockService: MockAPIKeyService{
+				CreateAPIKeyFunc: func(ctx context.Context, req dto.APIKeyCreateRequest) (*dto.APIKeyCreateResponse, error) {
+					return &dto.APIKeyCreateResponse{
+						APIKey: model.APIKey{ID: "123e4567-e89b-12d3-a456-426614174001", Name: req.Name, Role: req.Role, PVZIDs: req.PVZIDs},
+						Key:    "pvz_secret",
+					}, nil
+				},
+			},
+			requestBody: map[string]any{
+				"name":   "partner",
+				"role":   "employee",
+				"pvzIds": []string{"123e4567-e89b-12d3-a456-426614174002"},
+			},
+			expectedStatus: http.StatusCreated,
+			expectedBody: dto.APIKeyCreateResponse{
+				APIKey: model.APIKey{
+					ID:     "123e4567-e89b-12d3-a456-426614174001",
+					Name:   "partner",
+					Role:   model.EmployeeRole,
+					PVZIDs: []string{"123e4567-e89b-12d3-a456-426614174002"},
+				},
+				Key: "pvz_secret",
+			},
+		},
+		{
+			name:        "Invalid PVZ ID",
+			mockService: MockAPIKeyService{},
+			requestBody: map[string]any{
+				"name":   "partner",
+				"role":   "employee",
+				"pvzIds": []string{"not-a-uuid"},
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   model.Error{Message: "Invalid request data"},
+		},
+		{
+			name: "Service Error",
+			mockService: MockAPIKeyService{
+				CreateAPIKeyFunc: func(ctx context.Context, req dto.APIKeyCreateRequest) (*dto.APIKeyCreateResponse, error) {
+					return nil, errors.New("expiration time must be in the future")
+				},
+			},
+			requestBody: map[string]any{
+				"name": "partner",
+				"role": "moderator",
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   model.Error{Message: "expiration time must be in the future"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := gin.New()
+			apiKeyHandler := handler.NewAPIKeyHandler(&tt.mockService)
+
+			router.POST("/api-keys", apiKeyHandler.CreateAPIKey)
+
+			requestBody, _ := json.Marshal(tt.requestBody)
+			req, _ := http.NewRequest(http.MethodPost, "/api-keys", bytes.NewBuffer(requestBody))
+			req.Header.Set("Content-Type", "application/json")
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			if w.Code != tt.expectedStatus {
+				t.Errorf("Expected status %d, got %d", tt.expectedStatus, w.Code)
+			}
+
+			var response any
+			if tt.expectedStatus == http.StatusCreated {
+				var createdKey dto.APIKeyCreateResponse
+				json.Unmarshal(w.Body.Bytes(), &createdKey)
+				response = createdKey
+			} else {
+				var errResponse model.Error
+				json.Unmarshal(w.Body.Bytes(), &errResponse)
+				response = errResponse
+			}
+
+			if !reflect.DeepEqual(tt.expectedBody, response) {
+				t.Errorf("Expected body %v, got %v", tt.expectedBody, response)
+			}
+		})
+	}
+}
+
+func TestAPIKeyHandler_RevokeAPIKey(t *testing.T) {
+	tests := []struct {
+		name           string
+		mockService    MockAPIKeyService
+		expectedStatus int
+	}{
+		{
+			name: "Success",
+			mockService: MockAPIKeyService{
+				RevokeAPIKeyFunc: func(ctx context.Context, apiKeyID string) (*model.APIKey, error) {
+					return &model.APIKey{ID: apiKeyID}, nil
+				},
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name: "Not Found",
+			mockService: MockAPIKeyService{
+				RevokeAPIKeyFunc: func(ctx context.Context, apiKeyID string) (*model.APIKey, error) {
+					return nil, fmt.Errorf("failed to revoke api key: %w", model.ErrAPIKeyNotFound)
+				},
+			},
+			expectedStatus: http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := gin.New()
+			apiKeyHandler := handler.NewAPIKeyHandler(&tt.mockService)
+
+			router.DELETE("/api-keys/:apiKeyId", apiKeyHandler.RevokeAPIKey)
+
+			req, _ := http.NewRequest(http.MethodDelete, "/api-keys/123e4567-e89b-12d3-a456-426614174001", nil)
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			if w.Code != tt.expectedStatus {
+				t.Errorf("Expected status %d, got %d", tt.expectedStatus, w.Code)
+			}
+		})
+	}
+}
@@ -0,0 +1,36 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/kirillidk/pvz-service/internal/dto"
+	"github.com/kirillidk/pvz-service/internal/model"
+	service "github.com/kirillidk/pvz-service/internal/service/audit"
+)
+
+type AuditHandler struct {
+	auditService service.AuditServiceInterface
+}
+
+func NewAuditHandler(auditService service.AuditServiceInterface) *AuditHandler {
+	return &AuditHandler{
+		auditService: auditService,
+	}
+}
+
+func (h *AuditHandler) GetAuditLog(c *gin.Context) {
+	var filter dto.AuditFilterQuery
+	if err := c.ShouldBindQuery(&filter); err != nil {
+		c.JSON(http.StatusBadRequest, model.Error{Message: "Invalid query parameters"})
+		return
+	}
+
+	entries, err := h.auditService.GetAuditLog(c.Request.Context(), filter)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, model.Error{Message: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, entries)
+}
@@ -0,0 +1,107 @@
+package handler_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/kirillidk/pvz-service/internal/dto"
+	"github.com/kirillidk/pvz-service/internal/handler"
+	"github.com/kirillidk/pvz-service/internal/model"
+)
+
+type MockAuditService struct {
+	RecordFunc      func(ctx context.Context, action model.AuditAction, entityType model.AuditEntityType, entityID string, before, after any) error
+	GetAuditLogFunc func(ctx context.Context, filter dto.AuditFilterQuery) ([]model.AuditEntry, error)
+}
+
+func (m *MockAuditService) Record(
+	ctx context.Context,
+	action model.AuditAction,
+	entityType model.AuditEntityType,
+	entityID string,
+	before, after any,
+) error {
+	return m.RecordFunc(ctx, action, entityType, entityID, before, after)
+}
+
+func (m *MockAuditService) GetAuditLog(ctx context.Context, filter dto.AuditFilterQuery) ([]model.AuditEntry, error) {
+	return m.GetAuditLogFunc(ctx, filter)
+}
+
+func TestAuditHandler_GetAuditLog(t *testing.T) {
+	tests := []struct {
+		name           string
+		mockService    MockAuditService
+		query          string
+		expectedStatus int
+		expectedCount  int
+	}{
+		{
+			name: "Success",
+			mockService: MockAuditService{
+				GetAuditLogFunc: func(ctx context.Context, filter dto.AuditFilterQuery) ([]model.AuditEntry, error) {
+					if filter.EntityType != "product" || filter.ActorID != "actor" || filter.Page != 1 || filter.Limit != 10 {
+						t.Errorf("unexpected filter: %+v", filter)
+					}
+					return []model.AuditEntry{{ID: 2}, {ID: 1}}, nil
+				},
+			},
+			query:          "?entityType=product&actorId=actor",
+			expectedStatus: http.StatusOK,
+			expectedCount:  2,
+		},
+		{
+			name:           "Invalid Entity Type",
+			mockService:    MockAuditService{},
+			query:          "?entityType=user",
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "Invalid Entity ID",
+			mockService:    MockAuditService{},
+			query:          "?entityId=not-a-uuid",
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name: "Service Error",
+			mockService: MockAuditService{
+				GetAuditLogFunc: func(ctx context.Context, filter dto.AuditFilterQuery) ([]model.AuditEntry, error) {
+					return nil, errors.New("failed to get audit log")
+				},
+			},
+			expectedStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := gin.New()
+			auditHandler := handler.NewAuditHandler(&tt.mockService)
+
+			router.GET("/audit", auditHandler.GetAuditLog)
+
+			req, _ := http.NewRequest(http.MethodGet, "/audit"+tt.query, nil)
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			if w.Code != tt.expectedStatus {
+				t.Errorf("Expected status %d, got %d", tt.expectedStatus, w.Code)
+			}
+
+			if tt.expectedStatus == http.StatusOK {
+				var entries []model.AuditEntry
+				json.Unmarshal(w.Body.Bytes(), &entries)
+
+				if len(entries) != tt.expectedCount {
+					t.Errorf("Expected %d entries, got %d", tt.expectedCount, len(entries))
+				}
+			}
+		})
+	}
+}
@@ -1,23 +1,27 @@
 package handler
 
 import (
+	"errors"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
+	"github.com/kirillidk/pvz-service/internal/config"
 	"github.com/kirillidk/pvz-service/internal/dto"
 	"github.com/kirillidk/pvz-service/internal/model"
 	"github.com/kirillidk/pvz-service/internal/service/auth"
 )
 
 type AuthHandler struct {
-	authService auth.AuthServiceInterface
-	jwtSecret   string
+	authService     auth.AuthServiceInterface
+	jwtSecret       string
+	twoFactorConfig config.TwoFactorConfig
 }
 
-func NewAuthHandler(authService auth.AuthServiceInterface, jwtSecret string) *AuthHandler {
+func NewAuthHandler(authService auth.AuthServiceInterface, jwtSecret string, twoFactorCfg config.TwoFactorConfig) *AuthHandler {
 	return &AuthHandler{
-		authService: authService,
-		jwtSecret:   jwtSecret,
+		authService:     authService,
+		jwtSecret:       jwtSecret,
+		twoFactorConfig: twoFactorCfg,
 	}
 }
 
@@ -28,6 +32,12 @@ func (authHandler *AuthHandler) DummyLogin(c *gin.Context) {
 		return
 	}
 
+	// A dummy token would bypass the second factor required for moderators.
+	if req.Role == model.ModeratorRole && authHandler.twoFactorConfig.RequiredForModerators {
+		c.JSON(http.StatusForbidden, model.Error{Message: "Dummy login is disabled for moderators while two-factor authentication is required"})
+		return
+	}
+
 	token, err := auth.GenerateToken(req.Role, authHandler.jwtSecret)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, model.Error{Message: "Failed to generate token"})
@@ -61,12 +71,66 @@ func (h *AuthHandler) Login(c *gin.Context) {
 		return
 	}
 
-	token, err := h.authService.Login(c.Request.Context(), req)
+	loginResp, err := h.authService.Login(c.Request.Context(), req)
 	if err != nil {
 		c.JSON(http.StatusUnauthorized, model.Error{Message: err.Error()})
 		return
 	}
 
+	if loginResp.Token != "" {
+		c.Header("Authorization", "Bearer "+loginResp.Token)
+	}
+	c.JSON(http.StatusOK, loginResp)
+}
+
+func (h *AuthHandler) VerifyTwoFactor(c *gin.Context) {
+	var req dto.TwoFactorLoginRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, model.Error{Message: "Invalid request data"})
+		return
+	}
+
+	token, err := h.authService.VerifyTwoFactor(c.Request.Context(), req)
+	if err != nil {
+		if errors.Is(err, model.ErrTwoFactorLocked) {
+			c.JSON(http.StatusTooManyRequests, model.Error{Message: err.Error()})
+			return
+		}
+		c.JSON(http.StatusUnauthorized, model.Error{Message: err.Error()})
+		return
+	}
+
+	c.Header("Authorization", "Bearer "+token)
+	c.JSON(http.StatusOK, model.Token{Value: token})
+}
+
+func (h *AuthHandler) EnrollTwoFactor(c *gin.Context) {
+	enrollResp, err := h.authService.EnrollTwoFactor(c.Request.Context(), c.GetString("userID"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, model.Error{Message: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, enrollResp)
+}
+
+func (h *AuthHandler) ConfirmTwoFactor(c *gin.Context) {
+	var req dto.TwoFactorConfirmRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, model.Error{Message: "Invalid request data"})
+		return
+	}
+
+	token, err := h.authService.ConfirmTwoFactor(c.Request.Context(), c.GetString("userID"), req.Code)
+	if err != nil {
+		if errors.Is(err, model.ErrTwoFactorLocked) {
+			c.JSON(http.StatusTooManyRequests, model.Error{Message: err.Error()})
+			return
+		}
+		c.JSON(http.StatusBadRequest, model.Error{Message: err.Error()})
+		return
+	}
+
 	c.Header("Authorization", "Bearer "+token)
 	c.JSON(http.StatusOK, model.Token{Value: token})
 }
@@ -11,6 +11,7 @@ import (
 	"testing"
 
 	"github.com/gin-gonic/gin"
+	"github.com/kirillidk/pvz-service/internal/config"
 	"github.com/kirillidk/pvz-service/internal/dto"
 	"github.com/kirillidk/pvz-service/internal/handler"
 	"github.com/kirillidk/pvz-service/internal/model"
@@ -21,21 +22,37 @@ func init() {
 }
 
 type MockAuthService struct {
-	RegisterFunc func(ctx context.Context, req dto.RegisterRequest) (*model.User, error)
-	LoginFunc    func(ctx context.Context, req dto.LoginRequest) (string, error)
+	RegisterFunc         func(ctx context.Context, req dto.RegisterRequest) (*model.User, error)
+	LoginFunc            func(ctx context.Context, req dto.LoginRequest) (*dto.LoginResponse, error)
+	VerifyTwoFactorFunc  func(ctx context.Context, req dto.TwoFactorLoginRequest) (string, error)
+	EnrollTwoFactorFunc  func(ctx context.Context, userID string) (*dto.TwoFactorEnrollResponse, error)
+	ConfirmTwoFactorFunc func(ctx context.Context, userID string, code string) (string, error)
 }
 
 func (m *MockAuthService) Register(ctx context.Context, req dto.RegisterRequest) (*model.User, error) {
 	return m.RegisterFunc(ctx, req)
 }
 
-func (m *MockAuthService) Login(ctx context.Context, req dto.LoginRequest) (string, error) {
+func (m *MockAuthService) Login(ctx context.Context, req dto.LoginRequest) (*dto.LoginResponse, error) {
 	return m.LoginFunc(ctx, req)
 }
 
+func (m *MockAuthService) VerifyTwoFactor(ctx context.Context, req dto.TwoFactorLoginRequest) (string, error) {
+	return m.VerifyTwoFactorFunc(ctx, req)
+}
+
+func (m *MockAuthService) EnrollTwoFactor(ctx context.Context, userID string) (*dto.TwoFactorEnrollResponse, error) {
+	return m.EnrollTwoFactorFunc(ctx, userID)
+}
+
+func (m *MockAuthService) ConfirmTwoFactor(ctx context.Context, userID string, code string) (string, error) {
+	return m.ConfirmTwoFactorFunc(ctx, userID, code)
+}
+
 func TestAuthHandler_DummyLogin(t *testing.T) {
 	tests := []struct {
 		name           string
+		twoFactorCfg   config.TwoFactorConfig
 		requestBody    map[string]any
 		expectedStatus int
 		expectedBody   any
@@ -60,6 +77,17 @@ func TestAuthHandler_DummyLogin(t *testing.T) {
 				Value: "mock-token",
 			},
 		},
+		{
+			name:         "Moderator Role With Required 2FA",
+			twoFactorCfg: config.TwoFactorConfig{RequiredForModerators: true},
+			requestBody: map[string]any{
+				"role": "moderator",
+			},
+			expectedStatus: http.StatusForbidden,
+			expectedBody: model.Error{
+				Message: "Dummy login is disabled for moderators while two-factor authentication is required",
+			},
+		},
 		{
 			name: "Invalid Role",
 			requestBody: map[string]any{
@@ -75,7 +103,7 @@ func TestAuthHandler_DummyLogin(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			router := gin.New()
-			authHandler := handler.NewAuthHandler(&MockAuthService{}, "test-secret")
+			authHandler := handler.NewAuthHandler(&MockAuthService{}, "test-secret", tt.twoFactorCfg)
 
 			router.POST("/dummy-login", authHandler.DummyLogin)
 
@@ -184,7 +212,7 @@ func TestAuthHandler_Register(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			router := gin.New()
-			authHandler := handler.NewAuthHandler(&tt.mockService, "test-secret")
+			authHandler := handler.NewAuthHandler(&tt.mockService, "test-secret", config.TwoFactorConfig{})
 
 			router.POST("/register", authHandler.Register)
 
@@ -228,8 +256,8 @@ func TestAuthHandler_Login(t *testing.T) {
 		{
 			name: "Success",
 			mockService: MockAuthService{
-				LoginFunc: func(ctx context.Context, req dto.LoginRequest) (string, error) {
-					return "valid-token", nil
+				LoginFunc: func(ctx context.Context, req dto.LoginRequest) (*dto.LoginResponse, error) {
+					return &dto.LoginResponse{Token: "valid-token"}, nil
 				},
 			},
 			requestBody: map[string]any{
@@ -237,15 +265,15 @@ func TestAuthHandler_Login(t *testing.T) {
 				"password": "password123",
 			},
 			expectedStatus: http.StatusOK,
-			expectedBody: model.Token{
-				Value: "valid-token",
+			expectedBody: dto.LoginResponse{
+				Token: "valid-token",
 			},
 		},
 		{
 			name: "Invalid Request Data",
 			mockService: MockAuthService{
-				LoginFunc: func(ctx context.Context, req dto.LoginRequest) (string, error) {
-					return "", nil
+				LoginFunc: func(ctx context.Context, req dto.LoginRequest) (*dto.LoginResponse, error) {
+					return nil, nil
 				},
 			},
 			requestBody: map[string]any{
@@ -260,8 +288,8 @@ func TestAuthHandler_Login(t *testing.T) {
 		{
 			name: "Invalid Credentials",
 			mockService: MockAuthService{
-				LoginFunc: func(ctx context.Context, req dto.LoginRequest) (string, error) {
-					return "", errors.New("invalid email or password")
+				LoginFunc: func(ctx context.Context, req dto.LoginRequest) (*dto.LoginResponse, error) {
+					return nil, errors.New("invalid email or password")
 				},
 			},
 			requestBody: map[string]any{
@@ -273,12 +301,29 @@ func TestAuthHandler_Login(t *testing.T) {
 				Message: "invalid email or password",
 			},
 		},
+		{
+			name: "Second Factor Required",
+			mockService: MockAuthService{
+				LoginFunc: func(ctx context.Context, req dto.LoginRequest) (*dto.LoginResponse, error) {
+					return &dto.LoginResponse{MFARequired: true, MFAToken: "mfa-token"}, nil
+				},
+			},
+			requestBody: map[string]any{
+				"email":    "test@example.com",
+				"password": "password123",
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody: dto.LoginResponse{
+				MFARequired: true,
+				MFAToken:    "mfa-token",
+			},
+		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			router := gin.New()
-			authHandler := handler.NewAuthHandler(&tt.mockService, "test-secret")
+			authHandler := handler.NewAuthHandler(&tt.mockService, "test-secret", config.TwoFactorConfig{})
 
 			router.POST("/login", authHandler.Login)
 
@@ -295,14 +340,114 @@ func TestAuthHandler_Login(t *testing.T) {
 
 			var response any
 			if tt.expectedStatus == http.StatusOK {
-				var token model.Token
-				json.Unmarshal(w.Body.Bytes(), &token)
-				response = token
+				var loginResp dto.LoginResponse
+				json.Unmarshal(w.Body.Bytes(), &loginResp)
+				response = loginResp
 
 				authHeader := w.Header().Get("Authorization")
-				if authHeader == "" || authHeader != "Bearer "+token.Value {
-					t.Errorf("Expected Authorization header to be 'Bearer %s', got %s", token.Value, authHeader)
+				if loginResp.Token != "" && authHeader != "Bearer "+loginResp.Token {
+					t.Errorf("Expected Authorization header to be 'Bearer %s', got %s", loginResp.Token, authHeader)
 				}
+				if loginResp.Token == "" && authHeader != "" {
+					t.Errorf("Expected no Authorization header for MFA step, got %s", authHeader)
+				}
+			} else {
+				var errResponse model.Error
+				json.Unmarshal(w.Body.Bytes(), &errResponse)
+				response = errResponse
+			}
+
+			if !reflect.DeepEqual(tt.expectedBody, response) {
+				t.Errorf("Expected body %v, got %v", tt.expectedBody, response)
+			}
+		})
+	}
+}
+
+func TestAuthHandler_VerifyTwoFactor(t *testing.T) {
+	tests := []struct {
+		name           string
+		mockService    MockAuthService
+		requestBody    map[string]any
+		expectedStatus int
+		expectedBody   any
+	}{
+		{
+			name: "Success",
+			mockService: MockAuthService{
+				VerifyTwoFactorFunc: func(ctx context.Context, req dto.TwoFactorLoginRequest) (string, error) {
+					return "valid-token", nil
+				},
+			},
+			requestBody: map[string]any{
+				"mfaToken": "mfa-token",
+				"code":     "123456",
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   model.Token{Value: "valid-token"},
+		},
+		{
+			name:        "Missing Code",
+			mockService: MockAuthService{},
+			requestBody: map[string]any{
+				"mfaToken": "mfa-token",
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   model.Error{Message: "Invalid request data"},
+		},
+		{
+			name: "Invalid Code",
+			mockService: MockAuthService{
+				VerifyTwoFactorFunc: func(ctx context.Context, req dto.TwoFactorLoginRequest) (string, error) {
+					return "", errors.New("invalid two-factor code")
+				},
+			},
+			requestBody: map[string]any{
+				"mfaToken": "mfa-token",
+				"code":     "000000",
+			},
+			expectedStatus: http.StatusUnauthorized,
+			expectedBody:   model.Error{Message: "invalid two-factor code"},
+		},
+		{
+			name: "Locked",
+			mockService: MockAuthService{
+				VerifyTwoFactorFunc: func(ctx context.Context, req dto.TwoFactorLoginRequest) (string, error) {
+					return "", model.ErrTwoFactorLocked
+				},
+			},
+			requestBody: map[string]any{
+				"mfaToken": "mfa-token",
+				"code":     "000000",
+			},
+			expectedStatus: http.StatusTooManyRequests,
+			expectedBody:   model.Error{Message: model.ErrTwoFactorLocked.Error()},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := gin.New()
+			authHandler := handler.NewAuthHandler(&tt.mockService, "test-secret", config.TwoFactorConfig{})
+
+			router.POST("/login/2fa", authHandler.VerifyTwoFactor)
+
+			requestBody, _ := json.Marshal(tt.requestBody)
+			req, _ := http.NewRequest(http.MethodPost, "/login/2fa", bytes.NewBuffer(requestBody))
+			req.Header.Set("Content-Type", "application/json")
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			if w.Code != tt.expectedStatus {
+				t.Errorf("Expected status %d, got %d", tt.expectedStatus, w.Code)
+			}
+
+			var response any
+			if tt.expectedStatus == http.StatusOK {
+				var token model.Token
+				json.Unmarshal(w.Body.Bytes(), &token)
+				response = token
 			} else {
 				var errResponse model.Error
 				json.Unmarshal(w.Body.Bytes(), &errResponse)
@@ -0,0 +1,90 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/kirillidk/pvz-service/internal/dto"
+	"github.com/kirillidk/pvz-service/internal/model"
+	service "github.com/kirillidk/pvz-service/internal/service/city"
+)
+
+type CityHandler struct {
+	cityService service.CityServiceInterface
+}
+
+func NewCityHandler(cityService service.CityServiceInterface) *CityHandler {
+	return &CityHandler{
+		cityService: cityService,
+	}
+}
+
+func (h *CityHandler) CreateCity(c *gin.Context) {
+	var cityReq dto.CityCreateRequest
+	if err := c.ShouldBindJSON(&cityReq); err != nil {
+		c.JSON(http.StatusBadRequest, model.Error{Message: "Invalid request data"})
+		return
+	}
+
+	createdCity, err := h.cityService.CreateCity(c.Request.Context(), cityReq)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, model.Error{Message: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, createdCity)
+}
+
+func (h *CityHandler) GetCityList(c *gin.Context) {
+	cities, err := h.cityService.GetCityList(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, model.Error{Message: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, cities)
+}
+
+func (h *CityHandler) GetCity(c *gin.Context) {
+	city, err := h.cityService.GetCityByID(c.Request.Context(), c.Param("cityId"))
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, city)
+}
+
+func (h *CityHandler) UpdateCity(c *gin.Context) {
+	var cityReq dto.CityUpdateRequest
+	if err := c.ShouldBindJSON(&cityReq); err != nil {
+		c.JSON(http.StatusBadRequest, model.Error{Message: "Invalid request data"})
+		return
+	}
+
+	updatedCity, err := h.cityService.UpdateCity(c.Request.Context(), c.Param("cityId"), cityReq)
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, updatedCity)
+}
+
+func (h *CityHandler) DeleteCity(c *gin.Context) {
+	if err := h.cityService.DeleteCity(c.Request.Context(), c.Param("cityId")); err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+func (h *CityHandler) respondError(c *gin.Context, err error) {
+	if errors.Is(err, model.ErrCityNotFound) {
+		c.JSON(http.StatusNotFound, model.Error{Message: err.Error()})
+		return
+	}
+	c.JSON(http.StatusBadRequest, model.Error{Message: err.Error()})
+}
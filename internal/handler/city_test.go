@@ -0,0 +1,151 @@
+package handler_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/kirillidk/pvz-service/internal/dto"
+	"github.com/kirillidk/pvz-service/internal/handler"
+	"github.com/kirillidk/pvz-service/internal/model"
+)
+
+type MockCityService struct {
+	CreateCityFunc      func(ctx context.Context, req dto.CityCreateRequest) (*model.City, error)
+	GetCityListFunc     func(ctx context.Context) ([]model.City, error)
+	GetCityByIDFunc     func(ctx context.Context, cityID string) (*model.City, error)
+	UpdateCityFunc      func(ctx context.Context, cityID string, req dto.CityUpdateRequest) (*model.City, error)
+	DeleteCityFunc      func(ctx context.Context, cityID string) error
+	IsCityAvailableFunc func(ctx context.Context, name string) (bool, error)
+}
+
+func (m *MockCityService) CreateCity(ctx context.Context, req dto.CityCreateRequest) (*model.City, error) {
+	return m.CreateCityFunc(ctx, req)
+}
+
+func (m *MockCityService) GetCityList(ctx context.Context) ([]model.City, error) {
+	return m.GetCityListFunc(ctx)
+}
+
+func (m *MockCityService) GetCityByID(ctx context.Context, cityID string) (*model.City, error) {
+	return m.GetCityByIDFunc(ctx, cityID)
+}
+
+func (m *MockCityService) UpdateCity(ctx context.Context, cityID string, req dto.CityUpdateRequest) (*model.City, error) {
+	return m.UpdateCityFunc(ctx, cityID, req)
+}
+
+func (m *MockCityService) DeleteCity(ctx context.Context, cityID string) error {
+	return m.DeleteCityFunc(ctx, cityID)
+}
+
+func (m *MockCityService) IsCityAvailable(ctx context.Context, name string) (bool, error) {
+	return m.IsCityAvailableFunc(ctx, name)
+}
+
+func TestCityHandler_CreateCity(t *testing.T) {
+	tests := []struct {
+		name           string
+		mockService    MockCityService
+		requestBody    map[string]any
+		expectedStatus int
+	}{
+		{
+			name: "Success",
+			mockService: MockCityService{
+				CreateCityFunc: func(ctx context.Context, req dto.CityCreateRequest) (*model.City, error) {
+					return &model.City{ID: "123e4567-e89b-12d3-a456-426614174001", Name: req.Name, Timezone: req.Timezone, Active: true}, nil
+				},
+			},
+			requestBody:    map[string]any{"name": "Екатеринбург", "timezone": "Asia/Yekaterinburg"},
+			expectedStatus: http.StatusCreated,
+		},
+		{
+			name:           "Missing Timezone",
+			mockService:    MockCityService{},
+			requestBody:    map[string]any{"name": "Екатеринбург"},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name: "Duplicate City",
+			mockService: MockCityService{
+				CreateCityFunc: func(ctx context.Context, req dto.CityCreateRequest) (*model.City, error) {
+					return nil, fmt.Errorf("failed to create city: %w", model.ErrCityAlreadyExists)
+				},
+			},
+			requestBody:    map[string]any{"name": "Москва", "timezone": "Europe/Moscow"},
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := gin.New()
+			cityHandler := handler.NewCityHandler(&tt.mockService)
+
+			router.POST("/cities", cityHandler.CreateCity)
+
+			requestBody, _ := json.Marshal(tt.requestBody)
+			req, _ := http.NewRequest(http.MethodPost, "/cities", bytes.NewBuffer(requestBody))
+			req.Header.Set("Content-Type", "application/json")
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			if w.Code != tt.expectedStatus {
+				t.Errorf("Expected status %d, got %d", tt.expectedStatus, w.Code)
+			}
+		})
+	}
+}
+
+func TestCityHandler_UpdateCity(t *testing.T) {
+	tests := []struct {
+		name           string
+		mockService    MockCityService
+		expectedStatus int
+	}{
+		{
+			name: "Success",
+			mockService: MockCityService{
+				UpdateCityFunc: func(ctx context.Context, cityID string, req dto.CityUpdateRequest) (*model.City, error) {
+					return &model.City{ID: cityID, Name: "Москва", Active: *req.Active}, nil
+				},
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name: "Not Found",
+			mockService: MockCityService{
+				UpdateCityFunc: func(ctx context.Context, cityID string, req dto.CityUpdateRequest) (*model.City, error) {
+					return nil, fmt.Errorf("failed to update city: %w", model.ErrCityNotFound)
+				},
+			},
+			expectedStatus: http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := gin.New()
+			cityHandler := handler.NewCityHandler(&tt.mockService)
+
+			router.PATCH("/cities/:cityId", cityHandler.UpdateCity)
+
+			req, _ := http.NewRequest(http.MethodPatch, "/cities/123e4567-e89b-12d3-a456-426614174001", bytes.NewBufferString(`{"active":false}`))
+			req.Header.Set("Content-Type", "application/json")
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			if w.Code != tt.expectedStatus {
+				t.Errorf("Expected status %d, got %d", tt.expectedStatus, w.Code)
+			}
+		})
+	}
+}
@@ -0,0 +1,65 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/kirillidk/pvz-service/internal/dto"
+	"github.com/kirillidk/pvz-service/internal/model"
+	service "github.com/kirillidk/pvz-service/internal/service/expecteddelivery"
+)
+
+type ExpectedDeliveryHandler struct {
+	expectedDeliveryService service.ExpectedDeliveryServiceInterface
+}
+
+func NewExpectedDeliveryHandler(expectedDeliveryService service.ExpectedDeliveryServiceInterface) *ExpectedDeliveryHandler {
+	return &ExpectedDeliveryHandler{
+		expectedDeliveryService: expectedDeliveryService,
+	}
+}
+
+func (h *ExpectedDeliveryHandler) CreateExpectedDelivery(c *gin.Context) {
+	var deliveryCreateReq dto.ExpectedDeliveryCreateRequest
+	if err := c.ShouldBindJSON(&deliveryCreateReq); err != nil {
+		c.JSON(http.StatusBadRequest, model.Error{Message: "Invalid request data"})
+		return
+	}
+
+	delivery, err := h.expectedDeliveryService.CreateExpectedDelivery(c.Request.Context(), c.Param("pvzId"), deliveryCreateReq)
+	if err != nil {
+		if errors.Is(err, model.ErrPVZNotFound) {
+			c.JSON(http.StatusNotFound, model.Error{Message: err.Error()})
+			return
+		}
+		c.JSON(http.StatusBadRequest, model.Error{Message: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, delivery)
+}
+
+func (h *ExpectedDeliveryHandler) GetExpectedDeliveries(c *gin.Context) {
+	deliveries, err := h.expectedDeliveryService.GetExpectedDeliveries(c.Request.Context(), c.Param("pvzId"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, model.Error{Message: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, deliveries)
+}
+
+func (h *ExpectedDeliveryHandler) GetExpectedDelivery(c *gin.Context) {
+	delivery, err := h.expectedDeliveryService.GetExpectedDelivery(c.Request.Context(), c.Param("pvzId"), c.Param("deliveryId"))
+	if err != nil {
+		if errors.Is(err, model.ErrExpectedDeliveryNotFound) {
+			c.JSON(http.StatusNotFound, model.Error{Message: err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, model.Error{Message: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, delivery)
+}
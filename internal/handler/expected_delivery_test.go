@@ -0,0 +1,200 @@
+package handler_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/kirillidk/pvz-service/internal/dto"
+	"github.com/kirillidk/pvz-service/internal/handler"
+	"github.com/kirillidk/pvz-service/internal/model"
+)
+
+type MockExpectedDeliveryService struct {
+	CreateExpectedDeliveryFunc func(ctx context.Context, pvzID string, req dto.ExpectedDeliveryCreateRequest) (*model.ExpectedDelivery, error)
+	GetExpectedDeliveriesFunc  func(ctx context.Context, pvzID string) ([]model.ExpectedDelivery, error)
+	GetExpectedDeliveryFunc    func(ctx context.Context, pvzID, deliveryID string) (*model.ExpectedDelivery, error)
+}
+
+func (m *MockExpectedDeliveryService) CreateExpectedDelivery(
+	ctx context.Context,
+	pvzID string,
+	req dto.ExpectedDeliveryCreateRequest,
+) (*model.ExpectedDelivery, error) {
+	return m.CreateExpectedDeliveryFunc(ctx, pvzID, req)
+}
+
+func (m *MockExpectedDeliveryService) GetExpectedDeliveries(ctx context.Context, pvzID string) ([]model.ExpectedDelivery, error) {
+	return m.GetExpectedDeliveriesFunc(ctx, pvzID)
+}
+
+func (m *MockExpectedDeliveryService) GetExpectedDelivery(ctx context.Context, pvzID, deliveryID string) (*model.ExpectedDelivery, error) {
+	return m.GetExpectedDeliveryFunc(ctx, pvzID, deliveryID)
+}
+
+func (m *MockExpectedDeliveryService) LinkReception(ctx context.Context, reception *model.Reception) (*model.ExpectedDelivery, error) {
+	return nil, nil
+}
+
+func (m *MockExpectedDeliveryService) MatchProduct(ctx context.Context, product model.Product) ([]string, error) {
+	return nil, nil
+}
+
+func (m *MockExpectedDeliveryService) ReconcileReception(
+	ctx context.Context,
+	reception *model.Reception,
+) (*model.DeliveryReconciliation, error) {
+	return nil, nil
+}
+
+func (m *MockExpectedDeliveryService) ReopenReception(ctx context.Context, reception *model.Reception) error {
+	return nil
+}
+
+func TestExpectedDeliveryHandler_CreateExpectedDelivery(t *testing.T) {
+	pvzID := "123e4567-e89b-12d3-a456-426614174003"
+	createdDelivery := model.ExpectedDelivery{
+		ID:     "123e4567-e89b-12d3-a456-426614174030",
+		PVZID:  pvzID,
+		Status: model.ExpectedDeliveryStatusPending,
+		Items:  []model.ExpectedDeliveryItem{{Barcode: "4006381333931", Type: "electronics"}},
+	}
+	validRequest := dto.ExpectedDeliveryCreateRequest{
+		Items: []dto.ExpectedDeliveryItemRequest{{Barcode: "4006381333931", Type: "electronics"}},
+	}
+
+	tests := []struct {
+		name           string
+		requestBody    any
+		mockService    MockExpectedDeliveryService
+		expectedStatus int
+		expectedBody   any
+	}{
+		{
+			name:        "Success",
+			requestBody: validRequest,
+			mockService: MockExpectedDeliveryService{
+				CreateExpectedDeliveryFunc: func(ctx context.Context, pvzID string, req dto.ExpectedDeliveryCreateRequest) (*model.ExpectedDelivery, error) {
+					return &createdDelivery, nil
+				},
+			},
+			expectedStatus: http.StatusCreated,
+			expectedBody:   createdDelivery,
+		},
+		{
+			name:           "No Items",
+			requestBody:    dto.ExpectedDeliveryCreateRequest{},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   model.Error{Message: "Invalid request data"},
+		},
+		{
+			name:        "PVZ Not Active",
+			requestBody: validRequest,
+			mockService: MockExpectedDeliveryService{
+				CreateExpectedDeliveryFunc: func(ctx context.Context, pvzID string, req dto.ExpectedDeliveryCreateRequest) (*model.ExpectedDelivery, error) {
+					return nil, model.ErrPVZNotActive
+				},
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   model.Error{Message: model.ErrPVZNotActive.Error()},
+		},
+		{
+			name:        "PVZ Not Found",
+			requestBody: validRequest,
+			mockService: MockExpectedDeliveryService{
+				CreateExpectedDeliveryFunc: func(ctx context.Context, pvzID string, req dto.ExpectedDeliveryCreateRequest) (*model.ExpectedDelivery, error) {
+					return nil, model.ErrPVZNotFound
+				},
+			},
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   model.Error{Message: model.ErrPVZNotFound.Error()},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := gin.New()
+			deliveryHandler := handler.NewExpectedDeliveryHandler(&tt.mockService)
+
+			router.POST("/pvz/:pvzId/expected-deliveries", deliveryHandler.CreateExpectedDelivery)
+
+			body, _ := json.Marshal(tt.requestBody)
+			req, _ := http.NewRequest(http.MethodPost, "/pvz/"+pvzID+"/expected-deliveries", bytes.NewBuffer(body))
+			req.Header.Set("Content-Type", "application/json")
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			if w.Code != tt.expectedStatus {
+				t.Errorf("Expected status %d, got %d", tt.expectedStatus, w.Code)
+			}
+
+			var response any
+			if tt.expectedStatus == http.StatusCreated {
+				var delivery model.ExpectedDelivery
+				json.Unmarshal(w.Body.Bytes(), &delivery)
+				response = delivery
+			} else {
+				var errResponse model.Error
+				json.Unmarshal(w.Body.Bytes(), &errResponse)
+				response = errResponse
+			}
+
+			if !reflect.DeepEqual(tt.expectedBody, response) {
+				t.Errorf("Expected body %v, got %v", tt.expectedBody, response)
+			}
+		})
+	}
+}
+
+func TestExpectedDeliveryHandler_GetExpectedDelivery(t *testing.T) {
+	const path = "/pvz/123e4567-e89b-12d3-a456-426614174003/expected-deliveries/123e4567-e89b-12d3-a456-426614174030"
+
+	tests := []struct {
+		name           string
+		mockService    MockExpectedDeliveryService
+		expectedStatus int
+	}{
+		{
+			name: "Success",
+			mockService: MockExpectedDeliveryService{
+				GetExpectedDeliveryFunc: func(ctx context.Context, pvzID, deliveryID string) (*model.ExpectedDelivery, error) {
+					return &model.ExpectedDelivery{ID: deliveryID, PVZID: pvzID}, nil
+				},
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name: "Not Found",
+			mockService: MockExpectedDeliveryService{
+				GetExpectedDeliveryFunc: func(ctx context.Context, pvzID, deliveryID string) (*model.ExpectedDelivery, error) {
+					return nil, model.ErrExpectedDeliveryNotFound
+				},
+			},
+			expectedStatus: http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := gin.New()
+			deliveryHandler := handler.NewExpectedDeliveryHandler(&tt.mockService)
+
+			router.GET("/pvz/:pvzId/expected-deliveries/:deliveryId", deliveryHandler.GetExpectedDelivery)
+
+			req, _ := http.NewRequest(http.MethodGet, path, nil)
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			if w.Code != tt.expectedStatus {
+				t.Errorf("Expected status %d, got %d", tt.expectedStatus, w.Code)
+			}
+		})
+	}
+}
@@ -0,0 +1,38 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/kirillidk/pvz-service/internal/dto"
+	"github.com/kirillidk/pvz-service/internal/middleware"
+	"github.com/kirillidk/pvz-service/internal/model"
+	service "github.com/kirillidk/pvz-service/internal/service/export"
+	"github.com/kirillidk/pvz-service/internal/spreadsheet"
+)
+
+type ExportHandler struct {
+	exportService service.ExportServiceInterface
+}
+
+func NewExportHandler(exportService service.ExportServiceInterface) *ExportHandler {
+	return &ExportHandler{
+		exportService: exportService,
+	}
+}
+
+func (h *ExportHandler) ExportReceptions(c *gin.Context) {
+	var query dto.ReceptionExportQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.JSON(http.StatusBadRequest, model.Error{Message: "Invalid query parameters"})
+		return
+	}
+	query.PVZIDs = middleware.AllowedPVZIDs(c)
+
+	c.Header("Content-Type", spreadsheet.ContentType(query.Format))
+	c.Header("Content-Disposition", `attachment; filename="receptions.`+query.Format+`"`)
+
+	if err := h.exportService.ExportReceptions(c.Request.Context(), query, c.Writer); err != nil {
+		abortStream(c, err)
+	}
+}
@@ -0,0 +1,131 @@
+package handler_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/kirillidk/pvz-service/internal/dto"
+	"github.com/kirillidk/pvz-service/internal/handler"
+	"github.com/kirillidk/pvz-service/internal/model"
+)
+
+type MockExportService struct {
+	ExportReceptionsFunc func(ctx context.Context, query dto.ReceptionExportQuery, w io.Writer) error
+}
+
+func (m *MockExportService) ExportReceptions(ctx context.Context, query dto.ReceptionExportQuery, w io.Writer) error {
+	return m.ExportReceptionsFunc(ctx, query, w)
+}
+
+func TestExportHandler_ExportReceptions(t *testing.T) {
+	tests := []struct {
+		name                string
+		mockService         MockExportService
+		queryParams         string
+		expectedStatus      int
+		expectedContentType string
+		expectedBody        string
+	}{
+		{
+			name: "Success",
+			mockService: MockExportService{
+				ExportReceptionsFunc: func(ctx context.Context, query dto.ReceptionExportQuery, w io.Writer) error {
+					if len(query.City) != 2 || !query.HasOpenReception || query.Format != "csv" {
+						return errors.New("unexpected query")
+					}
+					_, err := io.WriteString(w, "data")
+					return err
+				},
+			},
+			queryParams:         "?format=csv&city=Kazan&city=Moscow&hasOpenReception=true",
+			expectedStatus:      http.StatusOK,
+			expectedContentType: "text/csv; charset=utf-8",
+			expectedBody:        "data",
+		},
+		{
+			name:           "Missing Format",
+			mockService:    MockExportService{},
+			queryParams:    "",
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "Unsupported Format",
+			mockService:    MockExportService{},
+			queryParams:    "?format=pdf",
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name: "Service Error Before Writing",
+			mockService: MockExportService{
+				ExportReceptionsFunc: func(ctx context.Context, query dto.ReceptionExportQuery, w io.Writer) error {
+					return errors.New("failed to export receptions")
+				},
+			},
+			queryParams:         "?format=xlsx",
+			expectedStatus:      http.StatusInternalServerError,
+			expectedContentType: "application/json; charset=utf-8",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := gin.New()
+			exportHandler := handler.NewExportHandler(&tt.mockService)
+
+			router.GET("/export/receptions", exportHandler.ExportReceptions)
+
+			req, _ := http.NewRequest(http.MethodGet, "/export/receptions"+tt.queryParams, nil)
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			if w.Code != tt.expectedStatus {
+				t.Errorf("Expected status %d, got %d", tt.expectedStatus, w.Code)
+			}
+
+			if tt.expectedContentType != "" && w.Header().Get("Content-Type") != tt.expectedContentType {
+				t.Errorf("Expected content type %q, got %q", tt.expectedContentType, w.Header().Get("Content-Type"))
+			}
+
+			if tt.expectedBody != "" && w.Body.String() != tt.expectedBody {
+				t.Errorf("Expected body %q, got %q", tt.expectedBody, w.Body.String())
+			}
+		})
+	}
+}
+
+func TestExportHandler_ExportReceptions_APIKeyAllowList(t *testing.T) {
+	allowed := []string{"123e4567-e89b-12d3-a456-426614174001"}
+
+	mockService := MockExportService{
+		ExportReceptionsFunc: func(ctx context.Context, query dto.ReceptionExportQuery, w io.Writer) error {
+			if !reflect.DeepEqual(query.PVZIDs, allowed) {
+				t.Errorf("Expected export limited to %v, got %v", allowed, query.PVZIDs)
+			}
+			return nil
+		},
+	}
+
+	router := gin.New()
+	router.Use(func(c *gin.Context) {
+		c.Set("apiKey", &model.APIKey{PVZIDs: allowed})
+	})
+	exportHandler := handler.NewExportHandler(&mockService)
+
+	router.GET("/export/receptions", exportHandler.ExportReceptions)
+
+	req, _ := http.NewRequest(http.MethodGet, "/export/receptions?format=csv&pvzIds=other", nil)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Errorf("Expected status %d, got %d", http.StatusOK, w.Code)
+	}
+}
@@ -1,22 +1,69 @@
 package handler
 
 import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
 	"github.com/kirillidk/pvz-service/internal/config"
+	"github.com/kirillidk/pvz-service/internal/model"
 	"github.com/kirillidk/pvz-service/internal/service"
 )
 
+const ndjsonContentType = "application/x-ndjson"
+
 type Handler struct {
-	AuthHandler      *AuthHandler
-	PVZHandler       *PVZHandler
-	ReceptionHandler *ReceptionHandler
-	ProductHandler   *ProductHandler
+	AuthHandler              *AuthHandler
+	PVZHandler               *PVZHandler
+	ReceptionHandler         *ReceptionHandler
+	ProductHandler           *ProductHandler
+	ProductLifecycleHandler  *ProductLifecycleHandler
+	ProductInspectionHandler *ProductInspectionHandler
+	OrderHandler             *OrderHandler
+	OverdueHandler           *OverdueHandler
+	StorageCellHandler       *StorageCellHandler
+	TransferHandler          *TransferHandler
+	ExpectedDeliveryHandler  *ExpectedDeliveryHandler
+	APIKeyHandler            *APIKeyHandler
+	AuditHandler             *AuditHandler
+	CityHandler              *CityHandler
+	ProductTypeHandler       *ProductTypeHandler
+	StatsHandler             *StatsHandler
+	ExportHandler            *ExportHandler
 }
 
 func NewHandler(serv *service.Service, cfg *config.Config) *Handler {
 	return &Handler{
-		AuthHandler:      NewAuthHandler(serv.AuthService, cfg.JWT.JWTSecret),
-		PVZHandler:       NewPVZHandler(serv.PVZService),
-		ReceptionHandler: NewReceptionHandler(serv.ReceptionService),
-		ProductHandler:   NewProductHandler(serv.ProductService),
+		AuthHandler:              NewAuthHandler(serv.AuthService, cfg.JWT.JWTSecret, cfg.TwoFactor),
+		PVZHandler:               NewPVZHandler(serv.PVZService),
+		ReceptionHandler:         NewReceptionHandler(serv.ReceptionService),
+		ProductHandler:           NewProductHandler(serv.ProductService),
+		ProductLifecycleHandler:  NewProductLifecycleHandler(serv.ProductLifecycleService),
+		ProductInspectionHandler: NewProductInspectionHandler(serv.ProductInspectionService, cfg.Attachment),
+		OrderHandler:             NewOrderHandler(serv.OrderService),
+		OverdueHandler:           NewOverdueHandler(serv.OverdueService),
+		StorageCellHandler:       NewStorageCellHandler(serv.StorageCellService),
+		TransferHandler:          NewTransferHandler(serv.TransferService),
+		ExpectedDeliveryHandler:  NewExpectedDeliveryHandler(serv.ExpectedDeliveryService),
+		APIKeyHandler:            NewAPIKeyHandler(serv.APIKeyService),
+		AuditHandler:             NewAuditHandler(serv.AuditService),
+		CityHandler:              NewCityHandler(serv.CityService),
+		ProductTypeHandler:       NewProductTypeHandler(serv.ProductTypeService),
+		StatsHandler:             NewStatsHandler(serv.StatsService),
+		ExportHandler:            NewExportHandler(serv.ExportService),
+	}
+}
+
+// abortStream reports an error of a streamed response. Once part of the body
+// has been sent the status can no longer change, so the response is cut
+// short and the client sees a truncated body.
+func abortStream(c *gin.Context, err error) {
+	if c.Writer.Written() {
+		c.Error(err)
+		c.Abort()
+		return
 	}
+
+	c.Writer.Header().Del("Content-Type")
+	c.Writer.Header().Del("Content-Disposition")
+	c.JSON(http.StatusInternalServerError, model.Error{Message: err.Error()})
 }
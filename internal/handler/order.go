@@ -0,0 +1,76 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/kirillidk/pvz-service/internal/dto"
+	"github.com/kirillidk/pvz-service/internal/model"
+	service "github.com/kirillidk/pvz-service/internal/service/order"
+)
+
+type OrderHandler struct {
+	orderService service.OrderServiceInterface
+}
+
+func NewOrderHandler(orderService service.OrderServiceInterface) *OrderHandler {
+	return &OrderHandler{
+		orderService: orderService,
+	}
+}
+
+func (h *OrderHandler) CreateOrder(c *gin.Context) {
+	var orderCreateReq dto.OrderCreateRequest
+
+	if err := c.ShouldBindJSON(&orderCreateReq); err != nil {
+		c.JSON(http.StatusBadRequest, model.Error{Message: "Invalid request data"})
+		return
+	}
+
+	order, err := h.orderService.CreateOrder(c.Request.Context(), c.Param("pvzId"), orderCreateReq)
+	if err != nil {
+		respondOrderError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, order)
+}
+
+func (h *OrderHandler) RegeneratePickupCode(c *gin.Context) {
+	order, err := h.orderService.RegeneratePickupCode(c.Request.Context(), c.Param("pvzId"), c.Param("orderId"))
+	if err != nil {
+		respondOrderError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, order)
+}
+
+func (h *OrderHandler) IssueOrder(c *gin.Context) {
+	var orderIssueReq dto.OrderIssueRequest
+
+	if err := c.ShouldBindJSON(&orderIssueReq); err != nil {
+		c.JSON(http.StatusBadRequest, model.Error{Message: "Invalid request data"})
+		return
+	}
+
+	response, err := h.orderService.IssueOrder(c.Request.Context(), c.Param("pvzId"), orderIssueReq)
+	if err != nil {
+		respondOrderError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+func respondOrderError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, model.ErrOrderNotFound), errors.Is(err, model.ErrProductNotFound):
+		c.JSON(http.StatusNotFound, model.Error{Message: err.Error()})
+	case errors.Is(err, model.ErrPickupCodeLocked), errors.Is(err, model.ErrPickupCodeResendLimit):
+		c.JSON(http.StatusTooManyRequests, model.Error{Message: err.Error()})
+	default:
+		c.JSON(http.StatusBadRequest, model.Error{Message: err.Error()})
+	}
+}
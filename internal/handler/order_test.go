@@ -0,0 +1,243 @@
+package handler_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/kirillidk/pvz-service/internal/dto"
+	"github.com/kirillidk/pvz-service/internal/handler"
+	"github.com/kirillidk/pvz-service/internal/model"
+)
+
+type MockOrderService struct {
+	CreateOrderFunc          func(ctx context.Context, pvzID string, orderCreateReq dto.OrderCreateRequest) (*model.Order, error)
+	RegeneratePickupCodeFunc func(ctx context.Context, pvzID, orderID string) (*model.Order, error)
+	IssueOrderFunc           func(ctx context.Context, pvzID string, orderIssueReq dto.OrderIssueRequest) (*dto.OrderIssueResponse, error)
+}
+
+func (m *MockOrderService) CreateOrder(ctx context.Context, pvzID string, orderCreateReq dto.OrderCreateRequest) (*model.Order, error) {
+	return m.CreateOrderFunc(ctx, pvzID, orderCreateReq)
+}
+
+func (m *MockOrderService) RegeneratePickupCode(ctx context.Context, pvzID, orderID string) (*model.Order, error) {
+	return m.RegeneratePickupCodeFunc(ctx, pvzID, orderID)
+}
+
+func (m *MockOrderService) IssueOrder(ctx context.Context, pvzID string, orderIssueReq dto.OrderIssueRequest) (*dto.OrderIssueResponse, error) {
+	return m.IssueOrderFunc(ctx, pvzID, orderIssueReq)
+}
+
+func TestOrderHandler_CreateOrder(t *testing.T) {
+	pvzID := "123e4567-e89b-12d3-a456-426614174003"
+	createdOrder := model.Order{
+		ID:         "123e4567-e89b-12d3-a456-426614174005",
+		PVZID:      pvzID,
+		Recipient:  "+79990000000",
+		ProductIDs: []string{"123e4567-e89b-12d3-a456-426614174001"},
+	}
+
+	tests := []struct {
+		name           string
+		requestBody    any
+		mockService    MockOrderService
+		expectedStatus int
+		expectedBody   any
+	}{
+		{
+			name: "Success",
+			requestBody: dto.OrderCreateRequest{
+				Recipient:  "+79990000000",
+				ProductIDs: []string{"123e4567-e89b-12d3-a456-426614174001"},
+			},
+			mockService: MockOrderService{
+				CreateOrderFunc: func(ctx context.Context, pvzID string, orderCreateReq dto.OrderCreateRequest) (*model.Order, error) {
+					return &createdOrder, nil
+				},
+			},
+			expectedStatus: http.StatusCreated,
+			expectedBody:   createdOrder,
+		},
+		{
+			name:           "Invalid Product ID",
+			requestBody:    map[string]any{"recipient": "+79990000000", "productIds": []string{"not-a-uuid"}},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   model.Error{Message: "Invalid request data"},
+		},
+		{
+			name: "Product Not Found",
+			requestBody: dto.OrderCreateRequest{
+				Recipient:  "+79990000000",
+				ProductIDs: []string{"123e4567-e89b-12d3-a456-426614174001"},
+			},
+			mockService: MockOrderService{
+				CreateOrderFunc: func(ctx context.Context, pvzID string, orderCreateReq dto.OrderCreateRequest) (*model.Order, error) {
+					return nil, model.ErrProductNotFound
+				},
+			},
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   model.Error{Message: model.ErrProductNotFound.Error()},
+		},
+		{
+			name: "Product Already In Order",
+			requestBody: dto.OrderCreateRequest{
+				Recipient:  "+79990000000",
+				ProductIDs: []string{"123e4567-e89b-12d3-a456-426614174001"},
+			},
+			mockService: MockOrderService{
+				CreateOrderFunc: func(ctx context.Context, pvzID string, orderCreateReq dto.OrderCreateRequest) (*model.Order, error) {
+					return nil, model.ErrProductInOrder
+				},
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   model.Error{Message: model.ErrProductInOrder.Error()},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := gin.New()
+			orderHandler := handler.NewOrderHandler(&tt.mockService)
+
+			router.POST("/pvz/:pvzId/orders", orderHandler.CreateOrder)
+
+			body, _ := json.Marshal(tt.requestBody)
+			req, _ := http.NewRequest(http.MethodPost, "/pvz/"+pvzID+"/orders", bytes.NewBuffer(body))
+			req.Header.Set("Content-Type", "application/json")
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			if w.Code != tt.expectedStatus {
+				t.Errorf("Expected status %d, got %d", tt.expectedStatus, w.Code)
+			}
+
+			var response any
+			if tt.expectedStatus == http.StatusCreated {
+				var order model.Order
+				json.Unmarshal(w.Body.Bytes(), &order)
+				response = order
+			} else {
+				var errResponse model.Error
+				json.Unmarshal(w.Body.Bytes(), &errResponse)
+				response = errResponse
+			}
+
+			if !reflect.DeepEqual(tt.expectedBody, response) {
+				t.Errorf("Expected body %v, got %v", tt.expectedBody, response)
+			}
+		})
+	}
+}
+
+func TestOrderHandler_IssueOrder(t *testing.T) {
+	pvzID := "123e4567-e89b-12d3-a456-426614174003"
+	orderID := "123e4567-e89b-12d3-a456-426614174005"
+	issueResponse := dto.OrderIssueResponse{
+		Order:    model.Order{ID: orderID, PVZID: pvzID, ProductIDs: []string{"123e4567-e89b-12d3-a456-426614174001"}},
+		Products: []model.Product{{ID: "123e4567-e89b-12d3-a456-426614174001", Status: model.ProductStatusIssued}},
+	}
+
+	issueErr := func(err error) MockOrderService {
+		return MockOrderService{
+			IssueOrderFunc: func(ctx context.Context, pvzID string, orderIssueReq dto.OrderIssueRequest) (*dto.OrderIssueResponse, error) {
+				return nil, err
+			},
+		}
+	}
+
+	tests := []struct {
+		name           string
+		requestBody    any
+		mockService    MockOrderService
+		expectedStatus int
+		expectedBody   any
+	}{
+		{
+			name:        "Success",
+			requestBody: dto.OrderIssueRequest{OrderID: orderID, Code: "042137"},
+			mockService: MockOrderService{
+				IssueOrderFunc: func(ctx context.Context, pvzID string, orderIssueReq dto.OrderIssueRequest) (*dto.OrderIssueResponse, error) {
+					return &issueResponse, nil
+				},
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   issueResponse,
+		},
+		{
+			name:           "Malformed Code",
+			requestBody:    dto.OrderIssueRequest{OrderID: orderID, Code: "42"},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   model.Error{Message: "Invalid request data"},
+		},
+		{
+			name:           "Wrong Code",
+			requestBody:    dto.OrderIssueRequest{OrderID: orderID, Code: "000000"},
+			mockService:    issueErr(model.ErrInvalidPickupCode),
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   model.Error{Message: model.ErrInvalidPickupCode.Error()},
+		},
+		{
+			name:           "Locked",
+			requestBody:    dto.OrderIssueRequest{OrderID: orderID, Code: "042137"},
+			mockService:    issueErr(model.ErrPickupCodeLocked),
+			expectedStatus: http.StatusTooManyRequests,
+			expectedBody:   model.Error{Message: model.ErrPickupCodeLocked.Error()},
+		},
+		{
+			name:           "Order Not Found",
+			requestBody:    dto.OrderIssueRequest{OrderID: orderID, Code: "042137"},
+			mockService:    issueErr(model.ErrOrderNotFound),
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   model.Error{Message: model.ErrOrderNotFound.Error()},
+		},
+		{
+			name:           "Service Error",
+			requestBody:    dto.OrderIssueRequest{OrderID: orderID, Code: "042137"},
+			mockService:    issueErr(errors.New("cannot change product status from received to issued")),
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   model.Error{Message: "cannot change product status from received to issued"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := gin.New()
+			orderHandler := handler.NewOrderHandler(&tt.mockService)
+
+			router.POST("/pvz/:pvzId/issue", orderHandler.IssueOrder)
+
+			body, _ := json.Marshal(tt.requestBody)
+			req, _ := http.NewRequest(http.MethodPost, "/pvz/"+pvzID+"/issue", bytes.NewBuffer(body))
+			req.Header.Set("Content-Type", "application/json")
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			if w.Code != tt.expectedStatus {
+				t.Errorf("Expected status %d, got %d", tt.expectedStatus, w.Code)
+			}
+
+			var response any
+			if tt.expectedStatus == http.StatusOK {
+				var issued dto.OrderIssueResponse
+				json.Unmarshal(w.Body.Bytes(), &issued)
+				response = issued
+			} else {
+				var errResponse model.Error
+				json.Unmarshal(w.Body.Bytes(), &errResponse)
+				response = errResponse
+			}
+
+			if !reflect.DeepEqual(tt.expectedBody, response) {
+				t.Errorf("Expected body %v, got %v", tt.expectedBody, response)
+			}
+		})
+	}
+}
@@ -0,0 +1,29 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/kirillidk/pvz-service/internal/model"
+	service "github.com/kirillidk/pvz-service/internal/service/overdue"
+)
+
+type OverdueHandler struct {
+	overdueService service.OverdueServiceInterface
+}
+
+func NewOverdueHandler(overdueService service.OverdueServiceInterface) *OverdueHandler {
+	return &OverdueHandler{
+		overdueService: overdueService,
+	}
+}
+
+func (h *OverdueHandler) GetOverdueProducts(c *gin.Context) {
+	tasks, err := h.overdueService.GetOverdueProducts(c.Request.Context(), c.Param("pvzId"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, model.Error{Message: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, tasks)
+}
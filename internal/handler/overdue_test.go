@@ -0,0 +1,103 @@
+package handler_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/kirillidk/pvz-service/internal/handler"
+	"github.com/kirillidk/pvz-service/internal/model"
+)
+
+type MockOverdueService struct {
+	GetOverdueProductsFunc func(ctx context.Context, pvzID string) ([]model.ReturnTask, error)
+}
+
+func (m *MockOverdueService) ScheduleReturns(ctx context.Context) (int64, error) {
+	return 0, nil
+}
+
+func (m *MockOverdueService) GetOverdueProducts(ctx context.Context, pvzID string) ([]model.ReturnTask, error) {
+	return m.GetOverdueProductsFunc(ctx, pvzID)
+}
+
+func TestOverdueHandler_GetOverdueProducts(t *testing.T) {
+	pvzID := "123e4567-e89b-12d3-a456-426614174003"
+	deadline := time.Date(2025, 4, 15, 12, 0, 0, 0, time.UTC)
+	tasks := []model.ReturnTask{
+		{
+			ID:              "123e4567-e89b-12d3-a456-426614174007",
+			PVZID:           pvzID,
+			Product:         model.Product{ID: "123e4567-e89b-12d3-a456-426614174001", Type: "обувь", Status: model.ProductStatusStored},
+			StorageDeadline: deadline,
+			CreatedAt:       deadline.Add(time.Hour),
+		},
+	}
+
+	tests := []struct {
+		name           string
+		mockService    MockOverdueService
+		expectedStatus int
+		expectedBody   any
+	}{
+		{
+			name: "Success",
+			mockService: MockOverdueService{
+				GetOverdueProductsFunc: func(ctx context.Context, id string) ([]model.ReturnTask, error) {
+					return tasks, nil
+				},
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   tasks,
+		},
+		{
+			name: "Service Error",
+			mockService: MockOverdueService{
+				GetOverdueProductsFunc: func(ctx context.Context, id string) ([]model.ReturnTask, error) {
+					return nil, errors.New("failed to get overdue products")
+				},
+			},
+			expectedStatus: http.StatusInternalServerError,
+			expectedBody:   model.Error{Message: "failed to get overdue products"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := gin.New()
+			overdueHandler := handler.NewOverdueHandler(&tt.mockService)
+
+			router.GET("/pvz/:pvzId/overdue", overdueHandler.GetOverdueProducts)
+
+			req, _ := http.NewRequest(http.MethodGet, "/pvz/"+pvzID+"/overdue", nil)
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			if w.Code != tt.expectedStatus {
+				t.Errorf("Expected status %d, got %d", tt.expectedStatus, w.Code)
+			}
+
+			var response any
+			if tt.expectedStatus == http.StatusOK {
+				var got []model.ReturnTask
+				json.Unmarshal(w.Body.Bytes(), &got)
+				response = got
+			} else {
+				var errResponse model.Error
+				json.Unmarshal(w.Body.Bytes(), &errResponse)
+				response = errResponse
+			}
+
+			if !reflect.DeepEqual(tt.expectedBody, response) {
+				t.Errorf("Expected body %v, got %v", tt.expectedBody, response)
+			}
+		})
+	}
+}
@@ -1,10 +1,12 @@
 package handler
 
 import (
+	"errors"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
 	"github.com/kirillidk/pvz-service/internal/dto"
+	"github.com/kirillidk/pvz-service/internal/middleware"
 	"github.com/kirillidk/pvz-service/internal/model"
 	service "github.com/kirillidk/pvz-service/internal/service/product"
 )
@@ -26,15 +28,86 @@ func (h *ProductHandler) CreateProduct(c *gin.Context) {
 		return
 	}
 
+	if !middleware.HasPVZAccess(c, productCreateReq.PVZID) {
+		c.JSON(http.StatusForbidden, model.Error{Message: "Access to this PVZ is not permitted"})
+		return
+	}
+
+	productCreateReq.AllowedPVZIDs = middleware.AllowedPVZIDs(c)
+
 	product, err := h.productService.CreateProduct(c.Request.Context(), productCreateReq)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, model.Error{Message: err.Error()})
+		respondProductCreateError(c, err)
 		return
 	}
 
 	c.JSON(http.StatusCreated, product)
 }
 
+func (h *ProductHandler) CreateProducts(c *gin.Context) {
+	var batchCreateReq dto.ProductBatchCreateRequest
+	if err := c.ShouldBindJSON(&batchCreateReq); err != nil {
+		c.JSON(http.StatusBadRequest, model.Error{Message: "Invalid request data"})
+		return
+	}
+
+	if !middleware.HasPVZAccess(c, batchCreateReq.PVZID) {
+		c.JSON(http.StatusForbidden, model.Error{Message: "Access to this PVZ is not permitted"})
+		return
+	}
+
+	batchCreateReq.AllowedPVZIDs = middleware.AllowedPVZIDs(c)
+
+	products, err := h.productService.CreateProducts(c.Request.Context(), batchCreateReq)
+	if err != nil {
+		respondProductCreateError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, products)
+}
+
+// respondProductCreateError maps errors of adding one product or a batch, so
+// that both endpoints answer with the same status codes.
+func respondProductCreateError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, model.ErrPVZNotFound), errors.Is(err, model.ErrNoOpenReception),
+		errors.Is(err, model.ErrStorageCellNotFound):
+		c.JSON(http.StatusNotFound, model.Error{Message: err.Error()})
+	case errors.Is(err, model.ErrPVZNotActive), errors.Is(err, model.ErrDuplicateBarcode),
+		errors.Is(err, model.ErrStorageCellFull), errors.Is(err, model.ErrStorageCellTypeMismatch):
+		c.JSON(http.StatusConflict, model.Error{Message: err.Error()})
+	case errors.Is(err, model.ErrProductTypeNotAvailable), errors.Is(err, model.ErrSerialNumberRequired),
+		errors.Is(err, model.ErrInvalidBarcode):
+		c.JSON(http.StatusBadRequest, model.Error{Message: err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, model.Error{Message: err.Error()})
+	}
+}
+
+func (h *ProductHandler) FindProducts(c *gin.Context) {
+	var query dto.ProductSearchQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.JSON(http.StatusBadRequest, model.Error{Message: "Invalid query parameters"})
+		return
+	}
+
+	locations, err := h.productService.FindProductsByBarcode(c.Request.Context(), query.Barcode)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, model.Error{Message: err.Error()})
+		return
+	}
+
+	visible := make([]model.ProductLocation, 0, len(locations))
+	for _, location := range locations {
+		if middleware.HasPVZAccess(c, location.PVZID) {
+			visible = append(visible, location)
+		}
+	}
+
+	c.JSON(http.StatusOK, visible)
+}
+
 func (h *ProductHandler) DeleteLastProduct(c *gin.Context) {
 	pvzID := c.Param("pvzId")
 	if pvzID == "" {
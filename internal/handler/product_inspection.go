@@ -0,0 +1,134 @@
+package handler
+
+import (
+	"errors"
+	"mime"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/kirillidk/pvz-service/internal/config"
+	"github.com/kirillidk/pvz-service/internal/dto"
+	"github.com/kirillidk/pvz-service/internal/model"
+	service "github.com/kirillidk/pvz-service/internal/service/productinspection"
+)
+
+// attachmentFormField is the multipart form field carrying an uploaded
+// attachment.
+const attachmentFormField = "file"
+
+// multipartOverhead is the room left in an upload body for multipart
+// boundaries and headers on top of the file itself.
+const multipartOverhead = 64 << 10
+
+type ProductInspectionHandler struct {
+	productInspectionService service.ProductInspectionServiceInterface
+	maxUploadSize            int64
+}
+
+func NewProductInspectionHandler(
+	productInspectionService service.ProductInspectionServiceInterface,
+	attachmentCfg config.AttachmentConfig,
+) *ProductInspectionHandler {
+	return &ProductInspectionHandler{
+		productInspectionService: productInspectionService,
+		maxUploadSize:            int64(attachmentCfg.MaxSizeMB)<<20 + multipartOverhead,
+	}
+}
+
+func (h *ProductInspectionHandler) SetProductCondition(c *gin.Context) {
+	var conditionReq dto.ProductConditionRequest
+	if err := c.ShouldBindJSON(&conditionReq); err != nil {
+		c.JSON(http.StatusBadRequest, model.Error{Message: "Invalid request data"})
+		return
+	}
+
+	product, err := h.productInspectionService.SetProductCondition(
+		c.Request.Context(), c.Param("pvzId"), c.Param("productId"), conditionReq,
+	)
+	if err != nil {
+		if errors.Is(err, model.ErrProductNotFound) {
+			c.JSON(http.StatusNotFound, model.Error{Message: err.Error()})
+			return
+		}
+		c.JSON(http.StatusBadRequest, model.Error{Message: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, product)
+}
+
+func (h *ProductInspectionHandler) AddProductAttachment(c *gin.Context) {
+	// The body is limited before parsing, since the multipart reader would
+	// otherwise spool a file of any size to disk.
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, h.maxUploadSize)
+
+	fileHeader, err := c.FormFile(attachmentFormField)
+	if err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			c.JSON(http.StatusRequestEntityTooLarge, model.Error{Message: model.ErrAttachmentTooLarge.Error()})
+			return
+		}
+		c.JSON(http.StatusBadRequest, model.Error{Message: "Invalid request data"})
+		return
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, model.Error{Message: "Invalid request data"})
+		return
+	}
+	defer file.Close()
+
+	attachment, err := h.productInspectionService.AddProductAttachment(
+		c.Request.Context(), c.Param("pvzId"), c.Param("productId"), fileHeader.Filename, file,
+	)
+	if err != nil {
+		switch {
+		case errors.Is(err, model.ErrProductNotFound):
+			c.JSON(http.StatusNotFound, model.Error{Message: err.Error()})
+		case errors.Is(err, model.ErrUnsupportedAttachment):
+			c.JSON(http.StatusUnsupportedMediaType, model.Error{Message: err.Error()})
+		case errors.Is(err, model.ErrAttachmentTooLarge):
+			c.JSON(http.StatusRequestEntityTooLarge, model.Error{Message: err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, model.Error{Message: err.Error()})
+		}
+		return
+	}
+
+	c.JSON(http.StatusCreated, attachment)
+}
+
+func (h *ProductInspectionHandler) GetProductAttachments(c *gin.Context) {
+	attachments, err := h.productInspectionService.GetProductAttachments(c.Request.Context(), c.Param("pvzId"), c.Param("productId"))
+	if err != nil {
+		if errors.Is(err, model.ErrProductNotFound) {
+			c.JSON(http.StatusNotFound, model.Error{Message: err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, model.Error{Message: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, attachments)
+}
+
+func (h *ProductInspectionHandler) DownloadProductAttachment(c *gin.Context) {
+	attachment, content, err := h.productInspectionService.OpenProductAttachment(
+		c.Request.Context(), c.Param("pvzId"), c.Param("productId"), c.Param("attachmentId"),
+	)
+	if err != nil {
+		if errors.Is(err, model.ErrProductNotFound) || errors.Is(err, model.ErrAttachmentNotFound) {
+			c.JSON(http.StatusNotFound, model.Error{Message: err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, model.Error{Message: err.Error()})
+		return
+	}
+	defer content.Close()
+
+	c.DataFromReader(http.StatusOK, attachment.Size, attachment.ContentType, content, map[string]string{
+		"Content-Disposition": mime.FormatMediaType("inline", map[string]string{"filename": attachment.FileName}),
+	})
+}
@@ -0,0 +1,351 @@
+package handler_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/kirillidk/pvz-service/internal/config"
+	"github.com/kirillidk/pvz-service/internal/dto"
+	"github.com/kirillidk/pvz-service/internal/handler"
+	"github.com/kirillidk/pvz-service/internal/model"
+)
+
+type MockProductInspectionService struct {
+	SetProductConditionFunc   func(ctx context.Context, pvzID, productID string, req dto.ProductConditionRequest) (*model.Product, error)
+	AddProductAttachmentFunc  func(ctx context.Context, pvzID, productID, fileName string, content io.Reader) (*model.ProductAttachment, error)
+	GetProductAttachmentsFunc func(ctx context.Context, pvzID, productID string) ([]model.ProductAttachment, error)
+	OpenProductAttachmentFunc func(ctx context.Context, pvzID, productID, attachmentID string) (*model.ProductAttachment, io.ReadCloser, error)
+}
+
+func (m *MockProductInspectionService) SetProductCondition(
+	ctx context.Context,
+	pvzID, productID string,
+	req dto.ProductConditionRequest,
+) (*model.Product, error) {
+	return m.SetProductConditionFunc(ctx, pvzID, productID, req)
+}
+
+func (m *MockProductInspectionService) AddProductAttachment(
+	ctx context.Context,
+	pvzID, productID, fileName string,
+	content io.Reader,
+) (*model.ProductAttachment, error) {
+	return m.AddProductAttachmentFunc(ctx, pvzID, productID, fileName, content)
+}
+
+func (m *MockProductInspectionService) GetProductAttachments(ctx context.Context, pvzID, productID string) ([]model.ProductAttachment, error) {
+	return m.GetProductAttachmentsFunc(ctx, pvzID, productID)
+}
+
+func (m *MockProductInspectionService) OpenProductAttachment(
+	ctx context.Context,
+	pvzID, productID, attachmentID string,
+) (*model.ProductAttachment, io.ReadCloser, error) {
+	return m.OpenProductAttachmentFunc(ctx, pvzID, productID, attachmentID)
+}
+
+const inspectionProductPath = "/pvz/123e4567-e89b-12d3-a456-426614174003/products/123e4567-e89b-12d3-a456-426614174001"
+
+var attachmentConfig = config.AttachmentConfig{MaxSizeMB: 1}
+
+func TestProductInspectionHandler_SetProductCondition(t *testing.T) {
+	tests := []struct {
+		name           string
+		mockService    MockProductInspectionService
+		requestBody    map[string]any
+		expectedStatus int
+		expectedBody   any
+	}{
+		{
+			name: "Success",
+			mockService: MockProductInspectionService{
+				SetProductConditionFunc: func(ctx context.Context, pvzID, productID string, req dto.ProductConditionRequest) (*model.Product, error) {
+					return &model.Product{ID: productID, Status: model.ProductStatusReceived, Condition: req.Condition, ConditionNote: req.Note}, nil
+				},
+			},
+			requestBody:    map[string]any{"condition": "damaged", "note": "box torn"},
+			expectedStatus: http.StatusOK,
+			expectedBody: model.Product{
+				ID:            "123e4567-e89b-12d3-a456-426614174001",
+				Status:        model.ProductStatusReceived,
+				Condition:     model.ProductConditionDamaged,
+				ConditionNote: "box torn",
+			},
+		},
+		{
+			name:           "Invalid Condition",
+			requestBody:    map[string]any{"condition": "wet"},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   model.Error{Message: "Invalid request data"},
+		},
+		{
+			name: "Product Not Found",
+			mockService: MockProductInspectionService{
+				SetProductConditionFunc: func(ctx context.Context, pvzID, productID string, req dto.ProductConditionRequest) (*model.Product, error) {
+					return nil, model.ErrProductNotFound
+				},
+			},
+			requestBody:    map[string]any{"condition": "opened"},
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   model.Error{Message: model.ErrProductNotFound.Error()},
+		},
+		{
+			name: "Product Already Issued",
+			mockService: MockProductInspectionService{
+				SetProductConditionFunc: func(ctx context.Context, pvzID, productID string, req dto.ProductConditionRequest) (*model.Product, error) {
+					return nil, errors.New("cannot change condition of issued product")
+				},
+			},
+			requestBody:    map[string]any{"condition": "ok"},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   model.Error{Message: "cannot change condition of issued product"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := gin.New()
+			inspectionHandler := handler.NewProductInspectionHandler(&tt.mockService, attachmentConfig)
+
+			router.PUT("/pvz/:pvzId/products/:productId/condition", inspectionHandler.SetProductCondition)
+
+			jsonBody, _ := json.Marshal(tt.requestBody)
+			req, _ := http.NewRequest(http.MethodPut, inspectionProductPath+"/condition", bytes.NewBuffer(jsonBody))
+			req.Header.Set("Content-Type", "application/json")
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			if w.Code != tt.expectedStatus {
+				t.Errorf("Expected status %d, got %d", tt.expectedStatus, w.Code)
+			}
+
+			var response any
+			if tt.expectedStatus == http.StatusOK {
+				var product model.Product
+				json.Unmarshal(w.Body.Bytes(), &product)
+				response = product
+			} else {
+				var errResponse model.Error
+				json.Unmarshal(w.Body.Bytes(), &errResponse)
+				response = errResponse
+			}
+
+			if !reflect.DeepEqual(tt.expectedBody, response) {
+				t.Errorf("Expected body %v, got %v", tt.expectedBody, response)
+			}
+		})
+	}
+}
+
+func TestProductInspectionHandler_AddProductAttachment(t *testing.T) {
+	attachment := model.ProductAttachment{
+		ID:          "123e4567-e89b-12d3-a456-426614174020",
+		ProductID:   "123e4567-e89b-12d3-a456-426614174001",
+		FileName:    "box.png",
+		ContentType: "image/png",
+		Size:        5,
+	}
+
+	tests := []struct {
+		name           string
+		mockService    MockProductInspectionService
+		withFile       bool
+		expectedStatus int
+		expectedBody   any
+	}{
+		{
+			name: "Success",
+			mockService: MockProductInspectionService{
+				AddProductAttachmentFunc: func(ctx context.Context, pvzID, productID, fileName string, content io.Reader) (*model.ProductAttachment, error) {
+					data, _ := io.ReadAll(content)
+					if fileName != "box.png" || string(data) != "image" {
+						return nil, errors.New("unexpected upload")
+					}
+					return &attachment, nil
+				},
+			},
+			withFile:       true,
+			expectedStatus: http.StatusCreated,
+			expectedBody:   attachment,
+		},
+		{
+			name:           "Missing File",
+			withFile:       false,
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   model.Error{Message: "Invalid request data"},
+		},
+		{
+			name: "Unsupported Type",
+			mockService: MockProductInspectionService{
+				AddProductAttachmentFunc: func(ctx context.Context, pvzID, productID, fileName string, content io.Reader) (*model.ProductAttachment, error) {
+					return nil, model.ErrUnsupportedAttachment
+				},
+			},
+			withFile:       true,
+			expectedStatus: http.StatusUnsupportedMediaType,
+			expectedBody:   model.Error{Message: model.ErrUnsupportedAttachment.Error()},
+		},
+		{
+			name: "Too Large",
+			mockService: MockProductInspectionService{
+				AddProductAttachmentFunc: func(ctx context.Context, pvzID, productID, fileName string, content io.Reader) (*model.ProductAttachment, error) {
+					return nil, model.ErrAttachmentTooLarge
+				},
+			},
+			withFile:       true,
+			expectedStatus: http.StatusRequestEntityTooLarge,
+			expectedBody:   model.Error{Message: model.ErrAttachmentTooLarge.Error()},
+		},
+		{
+			name: "Product Not Found",
+			mockService: MockProductInspectionService{
+				AddProductAttachmentFunc: func(ctx context.Context, pvzID, productID, fileName string, content io.Reader) (*model.ProductAttachment, error) {
+					return nil, model.ErrProductNotFound
+				},
+			},
+			withFile:       true,
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   model.Error{Message: model.ErrProductNotFound.Error()},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := gin.New()
+			inspectionHandler := handler.NewProductInspectionHandler(&tt.mockService, attachmentConfig)
+
+			router.POST("/pvz/:pvzId/products/:productId/attachments", inspectionHandler.AddProductAttachment)
+
+			var body bytes.Buffer
+			writer := multipart.NewWriter(&body)
+			if tt.withFile {
+				part, _ := writer.CreateFormFile("file", "box.png")
+				part.Write([]byte("image"))
+			}
+			writer.Close()
+
+			req, _ := http.NewRequest(http.MethodPost, inspectionProductPath+"/attachments", &body)
+			req.Header.Set("Content-Type", writer.FormDataContentType())
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			if w.Code != tt.expectedStatus {
+				t.Errorf("Expected status %d, got %d", tt.expectedStatus, w.Code)
+			}
+
+			var response any
+			if tt.expectedStatus == http.StatusCreated {
+				var created model.ProductAttachment
+				json.Unmarshal(w.Body.Bytes(), &created)
+				response = created
+			} else {
+				var errResponse model.Error
+				json.Unmarshal(w.Body.Bytes(), &errResponse)
+				response = errResponse
+			}
+
+			if !reflect.DeepEqual(tt.expectedBody, response) {
+				t.Errorf("Expected body %v, got %v", tt.expectedBody, response)
+			}
+		})
+	}
+}
+
+func TestProductInspectionHandler_AddProductAttachment_BodyTooLarge(t *testing.T) {
+	router := gin.New()
+	inspectionHandler := handler.NewProductInspectionHandler(&MockProductInspectionService{
+		AddProductAttachmentFunc: func(ctx context.Context, pvzID, productID, fileName string, content io.Reader) (*model.ProductAttachment, error) {
+			t.Error("service must not be called for an oversized body")
+			return nil, nil
+		},
+	}, attachmentConfig)
+
+	router.POST("/pvz/:pvzId/products/:productId/attachments", inspectionHandler.AddProductAttachment)
+
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+	part, _ := writer.CreateFormFile("file", "box.png")
+	part.Write(bytes.Repeat([]byte("a"), 2<<20))
+	writer.Close()
+
+	req, _ := http.NewRequest(http.MethodPost, inspectionProductPath+"/attachments", &body)
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusRequestEntityTooLarge {
+		t.Errorf("Expected status %d, got %d", http.StatusRequestEntityTooLarge, w.Code)
+	}
+}
+
+func TestProductInspectionHandler_DownloadProductAttachment(t *testing.T) {
+	attachment := &model.ProductAttachment{
+		ID:          "123e4567-e89b-12d3-a456-426614174020",
+		ProductID:   "123e4567-e89b-12d3-a456-426614174001",
+		FileName:    "коробка.png",
+		ContentType: "image/png",
+		Size:        5,
+	}
+
+	t.Run("Success", func(t *testing.T) {
+		router := gin.New()
+		inspectionHandler := handler.NewProductInspectionHandler(&MockProductInspectionService{
+			OpenProductAttachmentFunc: func(ctx context.Context, pvzID, productID, attachmentID string) (*model.ProductAttachment, io.ReadCloser, error) {
+				return attachment, io.NopCloser(strings.NewReader("image")), nil
+			},
+		}, attachmentConfig)
+
+		router.GET("/pvz/:pvzId/products/:productId/attachments/:attachmentId", inspectionHandler.DownloadProductAttachment)
+
+		req, _ := http.NewRequest(http.MethodGet, inspectionProductPath+"/attachments/"+attachment.ID, nil)
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		if w.Code != http.StatusOK {
+			t.Errorf("Expected status %d, got %d", http.StatusOK, w.Code)
+		}
+		if w.Body.String() != "image" {
+			t.Errorf("Expected body %q, got %q", "image", w.Body.String())
+		}
+		if contentType := w.Header().Get("Content-Type"); contentType != "image/png" {
+			t.Errorf("Expected Content-Type %q, got %q", "image/png", contentType)
+		}
+		if disposition := w.Header().Get("Content-Disposition"); !strings.HasPrefix(disposition, "inline; filename*=utf-8''") {
+			t.Errorf("Expected inline Content-Disposition with an encoded file name, got %q", disposition)
+		}
+	})
+
+	t.Run("Attachment Not Found", func(t *testing.T) {
+		router := gin.New()
+		inspectionHandler := handler.NewProductInspectionHandler(&MockProductInspectionService{
+			OpenProductAttachmentFunc: func(ctx context.Context, pvzID, productID, attachmentID string) (*model.ProductAttachment, io.ReadCloser, error) {
+				return nil, nil, model.ErrAttachmentNotFound
+			},
+		}, attachmentConfig)
+
+		router.GET("/pvz/:pvzId/products/:productId/attachments/:attachmentId", inspectionHandler.DownloadProductAttachment)
+
+		req, _ := http.NewRequest(http.MethodGet, inspectionProductPath+"/attachments/"+attachment.ID, nil)
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		if w.Code != http.StatusNotFound {
+			t.Errorf("Expected status %d, got %d", http.StatusNotFound, w.Code)
+		}
+	})
+}
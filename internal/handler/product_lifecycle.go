@@ -0,0 +1,62 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/kirillidk/pvz-service/internal/model"
+	service "github.com/kirillidk/pvz-service/internal/service/productlifecycle"
+)
+
+type ProductLifecycleHandler struct {
+	productLifecycleService service.ProductLifecycleServiceInterface
+}
+
+func NewProductLifecycleHandler(productLifecycleService service.ProductLifecycleServiceInterface) *ProductLifecycleHandler {
+	return &ProductLifecycleHandler{
+		productLifecycleService: productLifecycleService,
+	}
+}
+
+func (h *ProductLifecycleHandler) StoreProduct(c *gin.Context) {
+	h.changeProductStatus(c, model.ProductStatusStored)
+}
+
+func (h *ProductLifecycleHandler) IssueProduct(c *gin.Context) {
+	h.changeProductStatus(c, model.ProductStatusIssued)
+}
+
+func (h *ProductLifecycleHandler) ReturnProduct(c *gin.Context) {
+	h.changeProductStatus(c, model.ProductStatusReturnedToSender)
+}
+
+func (h *ProductLifecycleHandler) changeProductStatus(c *gin.Context, status model.ProductStatus) {
+	updatedProduct, err := h.productLifecycleService.ChangeProductStatus(
+		c.Request.Context(), c.Param("pvzId"), c.Param("productId"), status,
+	)
+	if err != nil {
+		if errors.Is(err, model.ErrProductNotFound) {
+			c.JSON(http.StatusNotFound, model.Error{Message: err.Error()})
+			return
+		}
+		c.JSON(http.StatusBadRequest, model.Error{Message: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, updatedProduct)
+}
+
+func (h *ProductLifecycleHandler) GetProductStatusHistory(c *gin.Context) {
+	history, err := h.productLifecycleService.GetProductStatusHistory(c.Request.Context(), c.Param("pvzId"), c.Param("productId"))
+	if err != nil {
+		if errors.Is(err, model.ErrProductNotFound) {
+			c.JSON(http.StatusNotFound, model.Error{Message: err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, model.Error{Message: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, history)
+}
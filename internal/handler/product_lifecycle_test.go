@@ -0,0 +1,224 @@
+package handler_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/kirillidk/pvz-service/internal/handler"
+	"github.com/kirillidk/pvz-service/internal/model"
+)
+
+type MockProductLifecycleService struct {
+	ChangeProductStatusFunc     func(ctx context.Context, pvzID, productID string, status model.ProductStatus) (*model.Product, error)
+	GetProductStatusHistoryFunc func(ctx context.Context, pvzID, productID string) ([]model.ProductStatusChange, error)
+}
+
+func (m *MockProductLifecycleService) ChangeProductStatus(
+	ctx context.Context,
+	pvzID, productID string,
+	status model.ProductStatus,
+) (*model.Product, error) {
+	return m.ChangeProductStatusFunc(ctx, pvzID, productID, status)
+}
+
+func (m *MockProductLifecycleService) GetProductStatusHistory(
+	ctx context.Context,
+	pvzID, productID string,
+) ([]model.ProductStatusChange, error) {
+	return m.GetProductStatusHistoryFunc(ctx, pvzID, productID)
+}
+
+func (m *MockProductLifecycleService) IssueOrderProducts(
+	ctx context.Context,
+	pvzID, orderID string,
+	productIDs []string,
+) ([]model.Product, error) {
+	return nil, nil
+}
+
+func TestProductLifecycleHandler_ChangeProductStatus(t *testing.T) {
+	const basePath = "/pvz/123e4567-e89b-12d3-a456-426614174003/products/123e4567-e89b-12d3-a456-426614174001"
+
+	changeStatus := func(ctx context.Context, pvzID, productID string, status model.ProductStatus) (*model.Product, error) {
+		return &model.Product{ID: productID, Type: "обувь", Status: status}, nil
+	}
+
+	tests := []struct {
+		name           string
+		path           string
+		mockService    MockProductLifecycleService
+		expectedStatus int
+		expectedBody   any
+	}{
+		{
+			name:           "Store",
+			path:           basePath + "/store",
+			mockService:    MockProductLifecycleService{ChangeProductStatusFunc: changeStatus},
+			expectedStatus: http.StatusOK,
+			expectedBody:   model.Product{ID: "123e4567-e89b-12d3-a456-426614174001", Type: "обувь", Status: model.ProductStatusStored},
+		},
+		{
+			name:           "Issue",
+			path:           basePath + "/issue",
+			mockService:    MockProductLifecycleService{ChangeProductStatusFunc: changeStatus},
+			expectedStatus: http.StatusOK,
+			expectedBody:   model.Product{ID: "123e4567-e89b-12d3-a456-426614174001", Type: "обувь", Status: model.ProductStatusIssued},
+		},
+		{
+			name:           "Return",
+			path:           basePath + "/return",
+			mockService:    MockProductLifecycleService{ChangeProductStatusFunc: changeStatus},
+			expectedStatus: http.StatusOK,
+			expectedBody:   model.Product{ID: "123e4567-e89b-12d3-a456-426614174001", Type: "обувь", Status: model.ProductStatusReturnedToSender},
+		},
+		{
+			name: "Invalid Transition",
+			path: basePath + "/issue",
+			mockService: MockProductLifecycleService{
+				ChangeProductStatusFunc: func(ctx context.Context, pvzID, productID string, status model.ProductStatus) (*model.Product, error) {
+					return nil, errors.New("cannot change product status from received to issued")
+				},
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   model.Error{Message: "cannot change product status from received to issued"},
+		},
+		{
+			name: "Product Not Found",
+			path: basePath + "/store",
+			mockService: MockProductLifecycleService{
+				ChangeProductStatusFunc: func(ctx context.Context, pvzID, productID string, status model.ProductStatus) (*model.Product, error) {
+					return nil, model.ErrProductNotFound
+				},
+			},
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   model.Error{Message: model.ErrProductNotFound.Error()},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := gin.New()
+			productLifecycleHandler := handler.NewProductLifecycleHandler(&tt.mockService)
+
+			router.POST("/pvz/:pvzId/products/:productId/store", productLifecycleHandler.StoreProduct)
+			router.POST("/pvz/:pvzId/products/:productId/issue", productLifecycleHandler.IssueProduct)
+			router.POST("/pvz/:pvzId/products/:productId/return", productLifecycleHandler.ReturnProduct)
+
+			req, _ := http.NewRequest(http.MethodPost, tt.path, nil)
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			if w.Code != tt.expectedStatus {
+				t.Errorf("Expected status %d, got %d", tt.expectedStatus, w.Code)
+			}
+
+			var response any
+			if tt.expectedStatus == http.StatusOK {
+				var product model.Product
+				json.Unmarshal(w.Body.Bytes(), &product)
+				response = product
+			} else {
+				var errResponse model.Error
+				json.Unmarshal(w.Body.Bytes(), &errResponse)
+				response = errResponse
+			}
+
+			if !reflect.DeepEqual(tt.expectedBody, response) {
+				t.Errorf("Expected body %v, got %v", tt.expectedBody, response)
+			}
+		})
+	}
+}
+
+func TestProductLifecycleHandler_GetProductStatusHistory(t *testing.T) {
+	changedAt := time.Date(2025, 4, 15, 12, 0, 0, 0, time.UTC)
+	history := []model.ProductStatusChange{
+		{
+			ID:         1,
+			ProductID:  "123e4567-e89b-12d3-a456-426614174001",
+			FromStatus: model.ProductStatusReceived,
+			ToStatus:   model.ProductStatusStored,
+			ChangedAt:  changedAt,
+			Actor:      model.Actor{ID: "123e4567-e89b-12d3-a456-426614174009", Type: model.UserActor, Role: model.EmployeeRole},
+		},
+	}
+
+	tests := []struct {
+		name           string
+		mockService    MockProductLifecycleService
+		expectedStatus int
+		expectedBody   any
+	}{
+		{
+			name: "Success",
+			mockService: MockProductLifecycleService{
+				GetProductStatusHistoryFunc: func(ctx context.Context, pvzID, productID string) ([]model.ProductStatusChange, error) {
+					return history, nil
+				},
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   history,
+		},
+		{
+			name: "Product Not Found",
+			mockService: MockProductLifecycleService{
+				GetProductStatusHistoryFunc: func(ctx context.Context, pvzID, productID string) ([]model.ProductStatusChange, error) {
+					return nil, model.ErrProductNotFound
+				},
+			},
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   model.Error{Message: model.ErrProductNotFound.Error()},
+		},
+		{
+			name: "Service Error",
+			mockService: MockProductLifecycleService{
+				GetProductStatusHistoryFunc: func(ctx context.Context, pvzID, productID string) ([]model.ProductStatusChange, error) {
+					return nil, errors.New("failed to get product status history")
+				},
+			},
+			expectedStatus: http.StatusInternalServerError,
+			expectedBody:   model.Error{Message: "failed to get product status history"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := gin.New()
+			productLifecycleHandler := handler.NewProductLifecycleHandler(&tt.mockService)
+
+			router.GET("/pvz/:pvzId/products/:productId/history", productLifecycleHandler.GetProductStatusHistory)
+
+			req, _ := http.NewRequest(http.MethodGet, "/pvz/123e4567-e89b-12d3-a456-426614174003/products/123e4567-e89b-12d3-a456-426614174001/history", nil)
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			if w.Code != tt.expectedStatus {
+				t.Errorf("Expected status %d, got %d", tt.expectedStatus, w.Code)
+			}
+
+			var response any
+			if tt.expectedStatus == http.StatusOK {
+				var changes []model.ProductStatusChange
+				json.Unmarshal(w.Body.Bytes(), &changes)
+				response = changes
+			} else {
+				var errResponse model.Error
+				json.Unmarshal(w.Body.Bytes(), &errResponse)
+				response = errResponse
+			}
+
+			if !reflect.DeepEqual(tt.expectedBody, response) {
+				t.Errorf("Expected body %v, got %v", tt.expectedBody, response)
+			}
+		})
+	}
+}
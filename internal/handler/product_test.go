@@ -5,6 +5,7 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"reflect"
@@ -17,11 +18,13 @@ import (
 )
 
 type MockProductService struct {
-	CreateProductFunc     func(ctx context.Context, req dto.ProductCreateRequest) (*model.Product, error)
+	CreateProductFunc     func(ctx context.Context, req dto.ProductCreateRequest) (*dto.ProductCreateResponse, error)
 	DeleteLastProductFunc func(ctx context.Context, pvzID string) error
+	CreateProductsFunc    func(ctx context.Context, req dto.ProductBatchCreateRequest) ([]dto.ProductCreateResponse, error)
+	FindProductsFunc      func(ctx context.Context, barcode string) ([]model.ProductLocation, error)
 }
 
-func (m *MockProductService) CreateProduct(ctx context.Context, req dto.ProductCreateRequest) (*model.Product, error) {
+func (m *MockProductService) CreateProduct(ctx context.Context, req dto.ProductCreateRequest) (*dto.ProductCreateResponse, error) {
 	return m.CreateProductFunc(ctx, req)
 }
 
@@ -29,6 +32,14 @@ func (m *MockProductService) DeleteLastProduct(ctx context.Context, pvzID string
 	return m.DeleteLastProductFunc(ctx, pvzID)
 }
 
+func (m *MockProductService) CreateProducts(ctx context.Context, req dto.ProductBatchCreateRequest) ([]dto.ProductCreateResponse, error) {
+	return m.CreateProductsFunc(ctx, req)
+}
+
+func (m *MockProductService) FindProductsByBarcode(ctx context.Context, barcode string) ([]model.ProductLocation, error) {
+	return m.FindProductsFunc(ctx, barcode)
+}
+
 func TestProductHandler_CreateProduct(t *testing.T) {
 	tests := []struct {
 		name           string
@@ -40,11 +51,13 @@ func TestProductHandler_CreateProduct(t *testing.T) {
 		{
 			name: "Success",
 			mockService: MockProductService{
-				CreateProductFunc: func(ctx context.Context, req dto.ProductCreateRequest) (*model.Product, error) {
-					return &model.Product{
-						ID:          "123e4567-e89b-12d3-a456-426614174001",
-						Type:        req.Type,
-						ReceptionID: "123e4567-e89b-12d3-a456-426614174002",
+				CreateProductFunc: func(ctx context.Context, req dto.ProductCreateRequest) (*dto.ProductCreateResponse, error) {
+					return &dto.ProductCreateResponse{
+						Product: model.Product{
+							ID:          "123e4567-e89b-12d3-a456-426614174001",
+							Type:        req.Type,
+							ReceptionID: "123e4567-e89b-12d3-a456-426614174002",
+						},
 					}, nil
 				},
 			},
@@ -53,16 +66,49 @@ func TestProductHandler_CreateProduct(t *testing.T) {
 				"pvzId": "123e4567-e89b-12d3-a456-426614174003",
 			},
 			expectedStatus: http.StatusCreated,
-			expectedBody: model.Product{
-				ID:          "123e4567-e89b-12d3-a456-426614174001",
-				Type:        "электроника",
-				ReceptionID: "123e4567-e89b-12d3-a456-426614174002",
+			expectedBody: dto.ProductCreateResponse{
+				Product: model.Product{
+					ID:          "123e4567-e89b-12d3-a456-426614174001",
+					Type:        "электроника",
+					ReceptionID: "123e4567-e89b-12d3-a456-426614174002",
+				},
+			},
+		},
+		{
+			name: "Success With Barcode Warning",
+			mockService: MockProductService{
+				CreateProductFunc: func(ctx context.Context, req dto.ProductCreateRequest) (*dto.ProductCreateResponse, error) {
+					return &dto.ProductCreateResponse{
+						Product: model.Product{
+							ID:          "123e4567-e89b-12d3-a456-426614174001",
+							Type:        req.Type,
+							ReceptionID: "123e4567-e89b-12d3-a456-426614174002",
+							Barcode:     req.Barcode,
+						},
+						Warnings: []string{"barcode was already received at another PVZ"},
+					}, nil
+				},
+			},
+			requestBody: map[string]any{
+				"type":    "электроника",
+				"pvzId":   "123e4567-e89b-12d3-a456-426614174003",
+				"barcode": "4006381333931",
+			},
+			expectedStatus: http.StatusCreated,
+			expectedBody: dto.ProductCreateResponse{
+				Product: model.Product{
+					ID:          "123e4567-e89b-12d3-a456-426614174001",
+					Type:        "электроника",
+					ReceptionID: "123e4567-e89b-12d3-a456-426614174002",
+					Barcode:     "4006381333931",
+				},
+				Warnings: []string{"barcode was already received at another PVZ"},
 			},
 		},
 		{
 			name: "Invalid Request Data",
 			mockService: MockProductService{
-				CreateProductFunc: func(ctx context.Context, req dto.ProductCreateRequest) (*model.Product, error) {
+				CreateProductFunc: func(ctx context.Context, req dto.ProductCreateRequest) (*dto.ProductCreateResponse, error) {
 					return nil, nil
 				},
 			},
@@ -77,7 +123,7 @@ func TestProductHandler_CreateProduct(t *testing.T) {
 		{
 			name: "Service Error",
 			mockService: MockProductService{
-				CreateProductFunc: func(ctx context.Context, req dto.ProductCreateRequest) (*model.Product, error) {
+				CreateProductFunc: func(ctx context.Context, req dto.ProductCreateRequest) (*dto.ProductCreateResponse, error) {
 					return nil, errors.New("failed to create product")
 				},
 			},
@@ -85,11 +131,76 @@ func TestProductHandler_CreateProduct(t *testing.T) {
 				"type":  "электроника",
 				"pvzId": "123e4567-e89b-12d3-a456-426614174003",
 			},
-			expectedStatus: http.StatusBadRequest,
+			expectedStatus: http.StatusInternalServerError,
 			expectedBody: model.Error{
 				Message: "failed to create product",
 			},
 		},
+		{
+			name: "No Open Reception",
+			mockService: MockProductService{
+				CreateProductFunc: func(ctx context.Context, req dto.ProductCreateRequest) (*dto.ProductCreateResponse, error) {
+					return nil, fmt.Errorf("failed to find open reception: %w", model.ErrNoOpenReception)
+				},
+			},
+			requestBody: map[string]any{
+				"type":  "электроника",
+				"pvzId": "123e4567-e89b-12d3-a456-426614174003",
+			},
+			expectedStatus: http.StatusNotFound,
+			expectedBody: model.Error{
+				Message: "failed to find open reception: " + model.ErrNoOpenReception.Error(),
+			},
+		},
+		{
+			name: "PVZ Not Active",
+			mockService: MockProductService{
+				CreateProductFunc: func(ctx context.Context, req dto.ProductCreateRequest) (*dto.ProductCreateResponse, error) {
+					return nil, model.ErrPVZNotActive
+				},
+			},
+			requestBody: map[string]any{
+				"type":  "электроника",
+				"pvzId": "123e4567-e89b-12d3-a456-426614174003",
+			},
+			expectedStatus: http.StatusConflict,
+			expectedBody: model.Error{
+				Message: model.ErrPVZNotActive.Error(),
+			},
+		},
+		{
+			name: "Product Type Not Available",
+			mockService: MockProductService{
+				CreateProductFunc: func(ctx context.Context, req dto.ProductCreateRequest) (*dto.ProductCreateResponse, error) {
+					return nil, fmt.Errorf("%w: игрушки", model.ErrProductTypeNotAvailable)
+				},
+			},
+			requestBody: map[string]any{
+				"type":  "игрушки",
+				"pvzId": "123e4567-e89b-12d3-a456-426614174003",
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody: model.Error{
+				Message: model.ErrProductTypeNotAvailable.Error() + ": игрушки",
+			},
+		},
+		{
+			name: "Duplicate Barcode",
+			mockService: MockProductService{
+				CreateProductFunc: func(ctx context.Context, req dto.ProductCreateRequest) (*dto.ProductCreateResponse, error) {
+					return nil, fmt.Errorf("%w: 4006381333931", model.ErrDuplicateBarcode)
+				},
+			},
+			requestBody: map[string]any{
+				"type":    "электроника",
+				"pvzId":   "123e4567-e89b-12d3-a456-426614174003",
+				"barcode": "4006381333931",
+			},
+			expectedStatus: http.StatusConflict,
+			expectedBody: model.Error{
+				Message: model.ErrDuplicateBarcode.Error() + ": 4006381333931",
+			},
+		},
 	}
 
 	for _, tt := range tests {
@@ -112,7 +223,7 @@ func TestProductHandler_CreateProduct(t *testing.T) {
 
 			var response any
 			if tt.expectedStatus == http.StatusCreated {
-				var product model.Product
+				var product dto.ProductCreateResponse
 				json.Unmarshal(w.Body.Bytes(), &product)
 				response = product
 			} else {
@@ -128,6 +239,305 @@ func TestProductHandler_CreateProduct(t *testing.T) {
 	}
 }
 
+func TestProductHandler_CreateProduct_PassesAllowedPVZs(t *testing.T) {
+	pvzIDs := []string{"123e4567-e89b-12d3-a456-426614174003"}
+
+	router := gin.New()
+	router.Use(func(c *gin.Context) {
+		c.Set("apiKey", &model.APIKey{PVZIDs: pvzIDs})
+	})
+	productHandler := handler.NewProductHandler(&MockProductService{
+		CreateProductFunc: func(ctx context.Context, req dto.ProductCreateRequest) (*dto.ProductCreateResponse, error) {
+			if !reflect.DeepEqual(req.AllowedPVZIDs, pvzIDs) {
+				t.Errorf("Expected allowed PVZs %v, got %v", pvzIDs, req.AllowedPVZIDs)
+			}
+			return &dto.ProductCreateResponse{Product: model.Product{ID: "123e4567-e89b-12d3-a456-426614174001"}}, nil
+		},
+	})
+
+	router.POST("/products", productHandler.CreateProduct)
+
+	requestBody, _ := json.Marshal(map[string]any{"type": "электроника", "pvzId": pvzIDs[0]})
+	req, _ := http.NewRequest(http.MethodPost, "/products", bytes.NewBuffer(requestBody))
+	req.Header.Set("Content-Type", "application/json")
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusCreated {
+		t.Errorf("Expected status %d, got %d", http.StatusCreated, w.Code)
+	}
+}
+
+func TestProductHandler_CreateProducts(t *testing.T) {
+	products := []dto.ProductCreateResponse{
+		{
+			Product: model.Product{
+				ID:          "123e4567-e89b-12d3-a456-426614174001",
+				Type:        "электроника",
+				ReceptionID: "123e4567-e89b-12d3-a456-426614174002",
+			},
+		},
+		{
+			Product: model.Product{
+				ID:          "123e4567-e89b-12d3-a456-426614174004",
+				Type:        "обувь",
+				ReceptionID: "123e4567-e89b-12d3-a456-426614174002",
+			},
+		},
+	}
+
+	tests := []struct {
+		name           string
+		mockService    MockProductService
+		requestBody    map[string]any
+		expectedStatus int
+		expectedBody   any
+	}{
+		{
+			name: "Success",
+			mockService: MockProductService{
+				CreateProductsFunc: func(ctx context.Context, req dto.ProductBatchCreateRequest) ([]dto.ProductCreateResponse, error) {
+					if len(req.Products) != 2 || req.Products[0].Type != "электроника" || req.Products[1].Type != "обувь" {
+						return nil, errors.New("unexpected request")
+					}
+					return products, nil
+				},
+			},
+			requestBody: map[string]any{
+				"pvzId": "123e4567-e89b-12d3-a456-426614174003",
+				"products": []map[string]any{
+					{"type": "электроника"},
+					{"type": "обувь"},
+				},
+			},
+			expectedStatus: http.StatusCreated,
+			expectedBody:   products,
+		},
+		{
+			name: "Empty Batch",
+			mockService: MockProductService{
+				CreateProductsFunc: func(ctx context.Context, req dto.ProductBatchCreateRequest) ([]dto.ProductCreateResponse, error) {
+					return nil, nil
+				},
+			},
+			requestBody: map[string]any{
+				"pvzId":    "123e4567-e89b-12d3-a456-426614174003",
+				"products": []map[string]any{},
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody: model.Error{
+				Message: "Invalid request data",
+			},
+		},
+		{
+			name: "Invalid Item",
+			mockService: MockProductService{
+				CreateProductsFunc: func(ctx context.Context, req dto.ProductBatchCreateRequest) ([]dto.ProductCreateResponse, error) {
+					return nil, nil
+				},
+			},
+			requestBody: map[string]any{
+				"pvzId": "123e4567-e89b-12d3-a456-426614174003",
+				"products": []map[string]any{
+					{"type": "электроника"},
+					{"serialNumber": "SN-1"},
+				},
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody: model.Error{
+				Message: "Invalid request data",
+			},
+		},
+		{
+			name: "No Open Reception",
+			mockService: MockProductService{
+				CreateProductsFunc: func(ctx context.Context, req dto.ProductBatchCreateRequest) ([]dto.ProductCreateResponse, error) {
+					return nil, model.ErrNoOpenReception
+				},
+			},
+			requestBody: map[string]any{
+				"pvzId":    "123e4567-e89b-12d3-a456-426614174003",
+				"products": []map[string]any{{"type": "электроника"}},
+			},
+			expectedStatus: http.StatusNotFound,
+			expectedBody: model.Error{
+				Message: model.ErrNoOpenReception.Error(),
+			},
+		},
+		{
+			name: "Duplicate Barcode",
+			mockService: MockProductService{
+				CreateProductsFunc: func(ctx context.Context, req dto.ProductBatchCreateRequest) ([]dto.ProductCreateResponse, error) {
+					return nil, fmt.Errorf("product 1: %w: same barcode as product 0", model.ErrDuplicateBarcode)
+				},
+			},
+			requestBody: map[string]any{
+				"pvzId":    "123e4567-e89b-12d3-a456-426614174003",
+				"products": []map[string]any{{"type": "электроника"}},
+			},
+			expectedStatus: http.StatusConflict,
+			expectedBody: model.Error{
+				Message: "product 1: " + model.ErrDuplicateBarcode.Error() + ": same barcode as product 0",
+			},
+		},
+		{
+			name: "Unknown Product Type",
+			mockService: MockProductService{
+				CreateProductsFunc: func(ctx context.Context, req dto.ProductBatchCreateRequest) ([]dto.ProductCreateResponse, error) {
+					return nil, fmt.Errorf("product 0: %w: мебель", model.ErrProductTypeNotAvailable)
+				},
+			},
+			requestBody: map[string]any{
+				"pvzId":    "123e4567-e89b-12d3-a456-426614174003",
+				"products": []map[string]any{{"type": "мебель"}},
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody: model.Error{
+				Message: "product 0: " + model.ErrProductTypeNotAvailable.Error() + ": мебель",
+			},
+		},
+		{
+			name: "Service Error",
+			mockService: MockProductService{
+				CreateProductsFunc: func(ctx context.Context, req dto.ProductBatchCreateRequest) ([]dto.ProductCreateResponse, error) {
+					return nil, errors.New("failed to create products: db error")
+				},
+			},
+			requestBody: map[string]any{
+				"pvzId":    "123e4567-e89b-12d3-a456-426614174003",
+				"products": []map[string]any{{"type": "электроника"}},
+			},
+			expectedStatus: http.StatusInternalServerError,
+			expectedBody: model.Error{
+				Message: "failed to create products: db error",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := gin.New()
+			productHandler := handler.NewProductHandler(&tt.mockService)
+
+			router.POST("/products/batch", productHandler.CreateProducts)
+
+			requestBody, _ := json.Marshal(tt.requestBody)
+			req, _ := http.NewRequest(http.MethodPost, "/products/batch", bytes.NewBuffer(requestBody))
+			req.Header.Set("Content-Type", "application/json")
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			if w.Code != tt.expectedStatus {
+				t.Errorf("Expected status %d, got %d", tt.expectedStatus, w.Code)
+			}
+
+			var response any
+			if tt.expectedStatus == http.StatusCreated {
+				var created []dto.ProductCreateResponse
+				json.Unmarshal(w.Body.Bytes(), &created)
+				response = created
+			} else {
+				var errResponse model.Error
+				json.Unmarshal(w.Body.Bytes(), &errResponse)
+				response = errResponse
+			}
+
+			if !reflect.DeepEqual(tt.expectedBody, response) {
+				t.Errorf("Expected body %v, got %v", tt.expectedBody, response)
+			}
+		})
+	}
+}
+
+func TestProductHandler_FindProducts(t *testing.T) {
+	location := model.ProductLocation{
+		Product: model.Product{
+			ID:          "123e4567-e89b-12d3-a456-426614174001",
+			Type:        "электроника",
+			ReceptionID: "123e4567-e89b-12d3-a456-426614174002",
+			Barcode:     "4006381333931",
+		},
+		PVZID:           "123e4567-e89b-12d3-a456-426614174003",
+		City:            "Москва",
+		ReceptionStatus: "close",
+	}
+
+	tests := []struct {
+		name           string
+		mockService    MockProductService
+		queryParams    string
+		expectedStatus int
+		expectedBody   any
+	}{
+		{
+			name: "Success",
+			mockService: MockProductService{
+				FindProductsFunc: func(ctx context.Context, barcode string) ([]model.ProductLocation, error) {
+					if barcode != "4006381333931" {
+						return nil, errors.New("unexpected barcode")
+					}
+					return []model.ProductLocation{location}, nil
+				},
+			},
+			queryParams:    "?barcode=4006381333931",
+			expectedStatus: http.StatusOK,
+			expectedBody:   []model.ProductLocation{location},
+		},
+		{
+			name:           "Missing Barcode",
+			mockService:    MockProductService{},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   model.Error{Message: "Invalid query parameters"},
+		},
+		{
+			name: "Service Error",
+			mockService: MockProductService{
+				FindProductsFunc: func(ctx context.Context, barcode string) ([]model.ProductLocation, error) {
+					return nil, errors.New("failed to find products")
+				},
+			},
+			queryParams:    "?barcode=4006381333931",
+			expectedStatus: http.StatusInternalServerError,
+			expectedBody:   model.Error{Message: "failed to find products"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := gin.New()
+			productHandler := handler.NewProductHandler(&tt.mockService)
+
+			router.GET("/products", productHandler.FindProducts)
+
+			req, _ := http.NewRequest(http.MethodGet, "/products"+tt.queryParams, nil)
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			if w.Code != tt.expectedStatus {
+				t.Errorf("Expected status %d, got %d", tt.expectedStatus, w.Code)
+			}
+
+			var response any
+			if tt.expectedStatus == http.StatusOK {
+				var locations []model.ProductLocation
+				json.Unmarshal(w.Body.Bytes(), &locations)
+				response = locations
+			} else {
+				var errResponse model.Error
+				json.Unmarshal(w.Body.Bytes(), &errResponse)
+				response = errResponse
+			}
+
+			if !reflect.DeepEqual(tt.expectedBody, response) {
+				t.Errorf("Expected body %v, got %v", tt.expectedBody, response)
+			}
+		})
+	}
+}
+
 func TestProductHandler_DeleteLastProduct(t *testing.T) {
 	tests := []struct {
 		name            string
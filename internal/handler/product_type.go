@@ -0,0 +1,90 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/kirillidk/pvz-service/internal/dto"
+	"github.com/kirillidk/pvz-service/internal/model"
+	service "github.com/kirillidk/pvz-service/internal/service/producttype"
+)
+
+type ProductTypeHandler struct {
+	productTypeService service.ProductTypeServiceInterface
+}
+
+func NewProductTypeHandler(productTypeService service.ProductTypeServiceInterface) *ProductTypeHandler {
+	return &ProductTypeHandler{
+		productTypeService: productTypeService,
+	}
+}
+
+func (h *ProductTypeHandler) CreateProductType(c *gin.Context) {
+	var productTypeReq dto.ProductTypeCreateRequest
+	if err := c.ShouldBindJSON(&productTypeReq); err != nil {
+		c.JSON(http.StatusBadRequest, model.Error{Message: "Invalid request data"})
+		return
+	}
+
+	createdProductType, err := h.productTypeService.CreateProductType(c.Request.Context(), productTypeReq)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, model.Error{Message: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, createdProductType)
+}
+
+func (h *ProductTypeHandler) GetProductTypeList(c *gin.Context) {
+	productTypes, err := h.productTypeService.GetProductTypeList(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, model.Error{Message: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, productTypes)
+}
+
+func (h *ProductTypeHandler) GetProductType(c *gin.Context) {
+	productType, err := h.productTypeService.GetProductType(c.Request.Context(), c.Param("code"))
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, productType)
+}
+
+func (h *ProductTypeHandler) UpdateProductType(c *gin.Context) {
+	var productTypeReq dto.ProductTypeUpdateRequest
+	if err := c.ShouldBindJSON(&productTypeReq); err != nil {
+		c.JSON(http.StatusBadRequest, model.Error{Message: "Invalid request data"})
+		return
+	}
+
+	updatedProductType, err := h.productTypeService.UpdateProductType(c.Request.Context(), c.Param("code"), productTypeReq)
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, updatedProductType)
+}
+
+func (h *ProductTypeHandler) DeleteProductType(c *gin.Context) {
+	if err := h.productTypeService.DeleteProductType(c.Request.Context(), c.Param("code")); err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+func (h *ProductTypeHandler) respondError(c *gin.Context, err error) {
+	if errors.Is(err, model.ErrProductTypeNotFound) {
+		c.JSON(http.StatusNotFound, model.Error{Message: err.Error()})
+		return
+	}
+	c.JSON(http.StatusBadRequest, model.Error{Message: err.Error()})
+}
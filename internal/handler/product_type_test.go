@@ -0,0 +1,101 @@
+package handler_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/kirillidk/pvz-service/internal/dto"
+	"github.com/kirillidk/pvz-service/internal/handler"
+	"github.com/kirillidk/pvz-service/internal/model"
+)
+
+type MockProductTypeService struct {
+	CreateProductTypeFunc    func(ctx context.Context, req dto.ProductTypeCreateRequest) (*model.ProductType, error)
+	GetProductTypeListFunc   func(ctx context.Context) ([]model.ProductType, error)
+	GetProductTypeFunc       func(ctx context.Context, code string) (*model.ProductType, error)
+	UpdateProductTypeFunc    func(ctx context.Context, code string, req dto.ProductTypeUpdateRequest) (*model.ProductType, error)
+	DeleteProductTypeFunc    func(ctx context.Context, code string) error
+	GetActiveProductTypeFunc func(ctx context.Context, code string) (*model.ProductType, error)
+}
+
+func (m *MockProductTypeService) CreateProductType(ctx context.Context, req dto.ProductTypeCreateRequest) (*model.ProductType, error) {
+	return m.CreateProductTypeFunc(ctx, req)
+}
+
+func (m *MockProductTypeService) GetProductTypeList(ctx context.Context) ([]model.ProductType, error) {
+	return m.GetProductTypeListFunc(ctx)
+}
+
+func (m *MockProductTypeService) GetProductType(ctx context.Context, code string) (*model.ProductType, error) {
+	return m.GetProductTypeFunc(ctx, code)
+}
+
+func (m *MockProductTypeService) UpdateProductType(ctx context.Context, code string, req dto.ProductTypeUpdateRequest) (*model.ProductType, error) {
+	return m.UpdateProductTypeFunc(ctx, code, req)
+}
+
+func (m *MockProductTypeService) DeleteProductType(ctx context.Context, code string) error {
+	return m.DeleteProductTypeFunc(ctx, code)
+}
+
+func (m *MockProductTypeService) GetActiveProductType(ctx context.Context, code string) (*model.ProductType, error) {
+	return m.GetActiveProductTypeFunc(ctx, code)
+}
+
+func TestProductTypeHandler_DeleteProductType(t *testing.T) {
+	tests := []struct {
+		name           string
+		mockService    MockProductTypeService
+		expectedStatus int
+	}{
+		{
+			name: "Success",
+			mockService: MockProductTypeService{
+				DeleteProductTypeFunc: func(ctx context.Context, code string) error {
+					return nil
+				},
+			},
+			expectedStatus: http.StatusNoContent,
+		},
+		{
+			name: "Not Found",
+			mockService: MockProductTypeService{
+				DeleteProductTypeFunc: func(ctx context.Context, code string) error {
+					return fmt.Errorf("failed to delete product type: %w", model.ErrProductTypeNotFound)
+				},
+			},
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name: "In Use",
+			mockService: MockProductTypeService{
+				DeleteProductTypeFunc: func(ctx context.Context, code string) error {
+					return fmt.Errorf("failed to delete product type: %w", model.ErrProductTypeInUse)
+				},
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := gin.New()
+			productTypeHandler := handler.NewProductTypeHandler(&tt.mockService)
+
+			router.DELETE("/product-types/:code", productTypeHandler.DeleteProductType)
+
+			req, _ := http.NewRequest(http.MethodDelete, "/product-types/обувь", nil)
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			if w.Code != tt.expectedStatus {
+				t.Errorf("Expected status %d, got %d", tt.expectedStatus, w.Code)
+			}
+		})
+	}
+}
@@ -20,6 +20,12 @@ import (
 type MockPVZService struct {
 	CreatePVZFunc  func(ctx context.Context, req dto.PVZCreateRequest) (*model.PVZ, error)
 	GetPVZListFunc func(ctx context.Context, filter dto.PVZFilterQuery) (*dto.PaginatedResponse, error)
+	GetPVZFunc     func(ctx context.Context, pvzID string, query dto.PVZDetailsQuery) (*dto.PVZDetailsResponse, error)
+	UpdatePVZFunc  func(ctx context.Context, pvzID string, req dto.PVZUpdateRequest) (*model.PVZ, error)
+
+	GetNearbyPVZListFunc func(ctx context.Context, query dto.PVZNearbyQuery) ([]model.NearbyPVZ, error)
+	ChangePVZStatusFunc  func(ctx context.Context, pvzID string, status model.PVZStatus) (*model.PVZ, error)
+	StreamPVZListFunc    func(ctx context.Context, filter dto.PVZFilterQuery, fn func(dto.PVZWithReceptionsResponse) error) error
 }
 
 func (m *MockPVZService) CreatePVZ(ctx context.Context, req dto.PVZCreateRequest) (*model.PVZ, error) {
@@ -30,6 +36,26 @@ func (m *MockPVZService) GetPVZList(ctx context.Context, filter dto.PVZFilterQue
 	return m.GetPVZListFunc(ctx, filter)
 }
 
+func (m *MockPVZService) GetPVZ(ctx context.Context, pvzID string, query dto.PVZDetailsQuery) (*dto.PVZDetailsResponse, error) {
+	return m.GetPVZFunc(ctx, pvzID, query)
+}
+
+func (m *MockPVZService) UpdatePVZ(ctx context.Context, pvzID string, req dto.PVZUpdateRequest) (*model.PVZ, error) {
+	return m.UpdatePVZFunc(ctx, pvzID, req)
+}
+
+func (m *MockPVZService) GetNearbyPVZList(ctx context.Context, query dto.PVZNearbyQuery) ([]model.NearbyPVZ, error) {
+	return m.GetNearbyPVZListFunc(ctx, query)
+}
+
+func (m *MockPVZService) ChangePVZStatus(ctx context.Context, pvzID string, status model.PVZStatus) (*model.PVZ, error) {
+	return m.ChangePVZStatusFunc(ctx, pvzID, status)
+}
+
+func (m *MockPVZService) StreamPVZList(ctx context.Context, filter dto.PVZFilterQuery, fn func(dto.PVZWithReceptionsResponse) error) error {
+	return m.StreamPVZListFunc(ctx, filter, fn)
+}
+
 func TestPVZHandler_CreatePVZ(t *testing.T) {
 	testTime := time.Date(2025, 4, 15, 12, 0, 0, 0, time.UTC)
 
@@ -66,7 +92,7 @@ func TestPVZHandler_CreatePVZ(t *testing.T) {
 			name: "Invalid City",
 			mockService: MockPVZService{
 				CreatePVZFunc: func(ctx context.Context, req dto.PVZCreateRequest) (*model.PVZ, error) {
-					return nil, nil
+					return nil, errors.New("city Новосибирск is not available for PVZ")
 				},
 			},
 			requestBody: map[string]any{
@@ -75,7 +101,7 @@ func TestPVZHandler_CreatePVZ(t *testing.T) {
 			},
 			expectedStatus: http.StatusBadRequest,
 			expectedBody: model.Error{
-				Message: "Invalid request data",
+				Message: "city Новосибирск is not available for PVZ",
 			},
 		},
 		{
@@ -147,6 +173,118 @@ func TestPVZHandler_CreatePVZ(t *testing.T) {
 	}
 }
 
+func TestPVZHandler_UpdatePVZ(t *testing.T) {
+	testTime := time.Date(2025, 4, 15, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name           string
+		mockService    MockPVZService
+		requestBody    map[string]any
+		expectedStatus int
+		expectedBody   any
+	}{
+		{
+			name: "Success",
+			mockService: MockPVZService{
+				UpdatePVZFunc: func(ctx context.Context, pvzID string, req dto.PVZUpdateRequest) (*model.PVZ, error) {
+					return &model.PVZ{
+						ID:               pvzID,
+						RegistrationDate: testTime,
+						City:             "Москва",
+						Address:          *req.Address,
+						Phone:            *req.Phone,
+					}, nil
+				},
+			},
+			requestBody: map[string]any{
+				"address": "ул. Тверская, 1",
+				"phone":   "+74951234567",
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody: model.PVZ{
+				ID:               "123e4567-e89b-12d3-a456-426614174001",
+				RegistrationDate: testTime,
+				City:             "Москва",
+				Address:          "ул. Тверская, 1",
+				Phone:            "+74951234567",
+			},
+		},
+		{
+			name:        "Invalid Phone",
+			mockService: MockPVZService{},
+			requestBody: map[string]any{
+				"phone": "not a phone",
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody: model.Error{
+				Message: "Invalid request data",
+			},
+		},
+		{
+			name:        "Latitude Out Of Range",
+			mockService: MockPVZService{},
+			requestBody: map[string]any{
+				"latitude":  91,
+				"longitude": 37.6,
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody: model.Error{
+				Message: "Invalid request data",
+			},
+		},
+		{
+			name: "PVZ Not Found",
+			mockService: MockPVZService{
+				UpdatePVZFunc: func(ctx context.Context, pvzID string, req dto.PVZUpdateRequest) (*model.PVZ, error) {
+					return nil, model.ErrPVZNotFound
+				},
+			},
+			requestBody: map[string]any{
+				"address": "ул. Тверская, 1",
+			},
+			expectedStatus: http.StatusNotFound,
+			expectedBody: model.Error{
+				Message: model.ErrPVZNotFound.Error(),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := gin.New()
+			pvzHandler := handler.NewPVZHandler(&tt.mockService)
+
+			router.PATCH("/pvz/:pvzId", pvzHandler.UpdatePVZ)
+
+			requestBody, _ := json.Marshal(tt.requestBody)
+			req, _ := http.NewRequest(http.MethodPatch, "/pvz/123e4567-e89b-12d3-a456-426614174001", bytes.NewBuffer(requestBody))
+			req.Header.Set("Content-Type", "application/json")
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			if w.Code != tt.expectedStatus {
+				t.Errorf("Expected status %d, got %d", tt.expectedStatus, w.Code)
+			}
+
+			var response any
+			if tt.expectedStatus == http.StatusOK {
+				var pvz model.PVZ
+				json.Unmarshal(w.Body.Bytes(), &pvz)
+				response = pvz
+			} else {
+				var errResponse model.Error
+				json.Unmarshal(w.Body.Bytes(), &errResponse)
+				response = errResponse
+			}
+
+			if !reflect.DeepEqual(tt.expectedBody, response) {
+				t.Errorf("Expected body %v, got %v", tt.expectedBody, response)
+			}
+		})
+	}
+}
+
 func TestPVZHandler_GetPVZList(t *testing.T) {
 	testTime := time.Date(2025, 4, 15, 12, 0, 0, 0, time.UTC)
 
@@ -212,6 +350,33 @@ func TestPVZHandler_GetPVZList(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "Success With Filters And Sort",
+			mockService: MockPVZService{
+				GetPVZListFunc: func(ctx context.Context, filter dto.PVZFilterQuery) (*dto.PaginatedResponse, error) {
+					if len(filter.City) != 2 || !filter.HasOpenReception || filter.MinProducts != 3 || filter.Sort != dto.PVZSortLastReception {
+						return nil, errors.New("unexpected filter")
+					}
+					return &dto.PaginatedResponse{Data: []dto.PVZWithReceptionsResponse{}}, nil
+				},
+			},
+			queryParams:    "?city=Kazan&city=Moscow&hasOpenReception=true&minProducts=3&sort=lastReception",
+			expectedStatus: http.StatusOK,
+			expectedBody:   &dto.PaginatedResponse{Data: []dto.PVZWithReceptionsResponse{}},
+		},
+		{
+			name: "Invalid Sort",
+			mockService: MockPVZService{
+				GetPVZListFunc: func(ctx context.Context, filter dto.PVZFilterQuery) (*dto.PaginatedResponse, error) {
+					return nil, nil
+				},
+			},
+			queryParams:    "?sort=name",
+			expectedStatus: http.StatusBadRequest,
+			expectedBody: model.Error{
+				Message: "Invalid query parameters",
+			},
+		},
 		{
 			name: "Invalid Query Parameters",
 			mockService: MockPVZService{
@@ -274,3 +439,522 @@ func TestPVZHandler_GetPVZList(t *testing.T) {
 		})
 	}
 }
+
+func TestPVZHandler_GetPVZList_NDJSON(t *testing.T) {
+	testTime := time.Date(2025, 4, 15, 12, 0, 0, 0, time.UTC)
+
+	first := dto.PVZWithReceptionsResponse{
+		PVZ: model.PVZ{
+			ID:               "123e4567-e89b-12d3-a456-426614174001",
+			RegistrationDate: testTime,
+			City:             "Москва",
+		},
+		Receptions: []dto.ReceptionWithProductsResponse{
+			{
+				Reception: model.Reception{
+					ID:       "123e4567-e89b-12d3-a456-426614174002",
+					DateTime: testTime,
+					PVZID:    "123e4567-e89b-12d3-a456-426614174001",
+					Status:   "close",
+				},
+				Products: []model.Product{},
+			},
+		},
+	}
+
+	second := dto.PVZWithReceptionsResponse{
+		PVZ: model.PVZ{
+			ID:               "123e4567-e89b-12d3-a456-426614174004",
+			RegistrationDate: testTime,
+			City:             "Казань",
+		},
+		Receptions: []dto.ReceptionWithProductsResponse{},
+	}
+
+	tests := []struct {
+		name           string
+		mockService    MockPVZService
+		queryParams    string
+		expectedStatus int
+		expectedItems  []dto.PVZWithReceptionsResponse
+		expectedError  *model.Error
+	}{
+		{
+			name: "Success",
+			mockService: MockPVZService{
+				StreamPVZListFunc: func(ctx context.Context, filter dto.PVZFilterQuery, fn func(dto.PVZWithReceptionsResponse) error) error {
+					if err := fn(first); err != nil {
+						return err
+					}
+					return fn(second)
+				},
+			},
+			expectedStatus: http.StatusOK,
+			expectedItems:  []dto.PVZWithReceptionsResponse{first, second},
+		},
+		{
+			name: "Success Empty",
+			mockService: MockPVZService{
+				StreamPVZListFunc: func(ctx context.Context, filter dto.PVZFilterQuery, fn func(dto.PVZWithReceptionsResponse) error) error {
+					return nil
+				},
+			},
+			expectedStatus: http.StatusOK,
+			expectedItems:  []dto.PVZWithReceptionsResponse{},
+		},
+		{
+			name: "Success With Filters",
+			mockService: MockPVZService{
+				StreamPVZListFunc: func(ctx context.Context, filter dto.PVZFilterQuery, fn func(dto.PVZWithReceptionsResponse) error) error {
+					if len(filter.City) != 1 || filter.City[0] != "Казань" {
+						return errors.New("unexpected filter")
+					}
+					return fn(second)
+				},
+			},
+			queryParams:    "?city=Казань",
+			expectedStatus: http.StatusOK,
+			expectedItems:  []dto.PVZWithReceptionsResponse{second},
+		},
+		{
+			name: "Service Error Before First Line",
+			mockService: MockPVZService{
+				StreamPVZListFunc: func(ctx context.Context, filter dto.PVZFilterQuery, fn func(dto.PVZWithReceptionsResponse) error) error {
+					return errors.New("failed to stream PVZ list")
+				},
+			},
+			expectedStatus: http.StatusInternalServerError,
+			expectedError:  &model.Error{Message: "failed to stream PVZ list"},
+		},
+		{
+			name: "Service Error After First Line",
+			mockService: MockPVZService{
+				StreamPVZListFunc: func(ctx context.Context, filter dto.PVZFilterQuery, fn func(dto.PVZWithReceptionsResponse) error) error {
+					if err := fn(first); err != nil {
+						return err
+					}
+					return errors.New("connection lost")
+				},
+			},
+			expectedStatus: http.StatusOK,
+			expectedItems:  []dto.PVZWithReceptionsResponse{first},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := gin.New()
+			pvzHandler := handler.NewPVZHandler(&tt.mockService)
+
+			router.GET("/pvz", pvzHandler.GetPVZList)
+
+			req, _ := http.NewRequest(http.MethodGet, "/pvz"+tt.queryParams, nil)
+			req.Header.Set("Accept", "application/x-ndjson")
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			if w.Code != tt.expectedStatus {
+				t.Errorf("Expected status %d, got %d", tt.expectedStatus, w.Code)
+			}
+
+			if tt.expectedError != nil {
+				var errResponse model.Error
+				json.Unmarshal(w.Body.Bytes(), &errResponse)
+				if !reflect.DeepEqual(*tt.expectedError, errResponse) {
+					t.Errorf("Expected error %v, got %v", *tt.expectedError, errResponse)
+				}
+				return
+			}
+
+			if contentType := w.Header().Get("Content-Type"); contentType != "application/x-ndjson" {
+				t.Errorf("Expected content type application/x-ndjson, got %s", contentType)
+			}
+
+			items := []dto.PVZWithReceptionsResponse{}
+			decoder := json.NewDecoder(w.Body)
+			for decoder.More() {
+				var item dto.PVZWithReceptionsResponse
+				if err := decoder.Decode(&item); err != nil {
+					t.Fatalf("Failed to decode line: %v", err)
+				}
+				items = append(items, item)
+			}
+
+			if !reflect.DeepEqual(tt.expectedItems, items) {
+				t.Errorf("Expected items %v, got %v", tt.expectedItems, items)
+			}
+		})
+	}
+}
+
+func TestPVZHandler_GetNearbyPVZList(t *testing.T) {
+	testTime := time.Date(2025, 4, 15, 12, 0, 0, 0, time.UTC)
+	latitude, longitude := 55.7575, 37.6136
+
+	nearby := model.NearbyPVZ{
+		PVZ: model.PVZ{
+			ID:               "123e4567-e89b-12d3-a456-426614174001",
+			RegistrationDate: testTime,
+			City:             "Москва",
+			Latitude:         &latitude,
+			Longitude:        &longitude,
+		},
+		Distance: 420.5,
+	}
+
+	tests := []struct {
+		name           string
+		mockService    MockPVZService
+		queryParams    string
+		expectedStatus int
+		expectedBody   any
+	}{
+		{
+			name: "Success",
+			mockService: MockPVZService{
+				GetNearbyPVZListFunc: func(ctx context.Context, query dto.PVZNearbyQuery) ([]model.NearbyPVZ, error) {
+					if *query.Latitude != 55.76 || *query.Longitude != 37.61 || query.Radius != 5000 || query.Limit != 10 || !query.OpenNow {
+						t.Errorf("Unexpected query %+v", query)
+					}
+					return []model.NearbyPVZ{nearby}, nil
+				},
+			},
+			queryParams:    "?lat=55.76&lon=37.61&openNow=true",
+			expectedStatus: http.StatusOK,
+			expectedBody:   []model.NearbyPVZ{nearby},
+		},
+		{
+			name:           "Missing Coordinates",
+			mockService:    MockPVZService{},
+			queryParams:    "?lat=55.76",
+			expectedStatus: http.StatusBadRequest,
+			expectedBody: model.Error{
+				Message: "Invalid query parameters",
+			},
+		},
+		{
+			name:           "Radius Too Large",
+			mockService:    MockPVZService{},
+			queryParams:    "?lat=55.76&lon=37.61&radius=100000",
+			expectedStatus: http.StatusBadRequest,
+			expectedBody: model.Error{
+				Message: "Invalid query parameters",
+			},
+		},
+		{
+			name: "Service Error",
+			mockService: MockPVZService{
+				GetNearbyPVZListFunc: func(ctx context.Context, query dto.PVZNearbyQuery) ([]model.NearbyPVZ, error) {
+					return nil, errors.New("failed to get nearby PVZ list")
+				},
+			},
+			queryParams:    "?lat=55.76&lon=37.61",
+			expectedStatus: http.StatusInternalServerError,
+			expectedBody: model.Error{
+				Message: "failed to get nearby PVZ list",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := gin.New()
+			pvzHandler := handler.NewPVZHandler(&tt.mockService)
+
+			router.GET("/pvz/nearby", pvzHandler.GetNearbyPVZList)
+
+			req, _ := http.NewRequest(http.MethodGet, "/pvz/nearby"+tt.queryParams, nil)
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			if w.Code != tt.expectedStatus {
+				t.Errorf("Expected status %d, got %d", tt.expectedStatus, w.Code)
+			}
+
+			var response any
+			if tt.expectedStatus == http.StatusOK {
+				var nearbyList []model.NearbyPVZ
+				json.Unmarshal(w.Body.Bytes(), &nearbyList)
+				response = nearbyList
+			} else {
+				var errResponse model.Error
+				json.Unmarshal(w.Body.Bytes(), &errResponse)
+				response = errResponse
+			}
+
+			if !reflect.DeepEqual(tt.expectedBody, response) {
+				t.Errorf("Expected body %v, got %v", tt.expectedBody, response)
+			}
+		})
+	}
+}
+
+func TestPVZHandler_ChangePVZStatus(t *testing.T) {
+	tests := []struct {
+		name           string
+		path           string
+		mockService    MockPVZService
+		expectedStatus int
+		expectedBody   any
+	}{
+		{
+			name: "Suspend",
+			path: "/pvz/123e4567-e89b-12d3-a456-426614174001/suspend",
+			mockService: MockPVZService{
+				ChangePVZStatusFunc: func(ctx context.Context, pvzID string, status model.PVZStatus) (*model.PVZ, error) {
+					return &model.PVZ{ID: pvzID, City: "Москва", Status: status}, nil
+				},
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody: model.PVZ{
+				ID:     "123e4567-e89b-12d3-a456-426614174001",
+				City:   "Москва",
+				Status: model.PVZStatusSuspended,
+			},
+		},
+		{
+			name: "Reopen",
+			path: "/pvz/123e4567-e89b-12d3-a456-426614174001/reopen",
+			mockService: MockPVZService{
+				ChangePVZStatusFunc: func(ctx context.Context, pvzID string, status model.PVZStatus) (*model.PVZ, error) {
+					return &model.PVZ{ID: pvzID, City: "Москва", Status: status}, nil
+				},
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody: model.PVZ{
+				ID:     "123e4567-e89b-12d3-a456-426614174001",
+				City:   "Москва",
+				Status: model.PVZStatusActive,
+			},
+		},
+		{
+			name: "Archive With Open Reception",
+			path: "/pvz/123e4567-e89b-12d3-a456-426614174001/archive",
+			mockService: MockPVZService{
+				ChangePVZStatusFunc: func(ctx context.Context, pvzID string, status model.PVZStatus) (*model.PVZ, error) {
+					return nil, errors.New("cannot archive PVZ with a reception in progress")
+				},
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody: model.Error{
+				Message: "cannot archive PVZ with a reception in progress",
+			},
+		},
+		{
+			name: "PVZ Not Found",
+			path: "/pvz/123e4567-e89b-12d3-a456-426614174001/archive",
+			mockService: MockPVZService{
+				ChangePVZStatusFunc: func(ctx context.Context, pvzID string, status model.PVZStatus) (*model.PVZ, error) {
+					return nil, model.ErrPVZNotFound
+				},
+			},
+			expectedStatus: http.StatusNotFound,
+			expectedBody: model.Error{
+				Message: model.ErrPVZNotFound.Error(),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := gin.New()
+			pvzHandler := handler.NewPVZHandler(&tt.mockService)
+
+			router.POST("/pvz/:pvzId/suspend", pvzHandler.SuspendPVZ)
+			router.POST("/pvz/:pvzId/reopen", pvzHandler.ReopenPVZ)
+			router.POST("/pvz/:pvzId/archive", pvzHandler.ArchivePVZ)
+
+			req, _ := http.NewRequest(http.MethodPost, tt.path, nil)
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			if w.Code != tt.expectedStatus {
+				t.Errorf("Expected status %d, got %d", tt.expectedStatus, w.Code)
+			}
+
+			var response any
+			if tt.expectedStatus == http.StatusOK {
+				var pvz model.PVZ
+				json.Unmarshal(w.Body.Bytes(), &pvz)
+				response = pvz
+			} else {
+				var errResponse model.Error
+				json.Unmarshal(w.Body.Bytes(), &errResponse)
+				response = errResponse
+			}
+
+			if !reflect.DeepEqual(tt.expectedBody, response) {
+				t.Errorf("Expected body %v, got %v", tt.expectedBody, response)
+			}
+		})
+	}
+}
+
+func TestPVZHandler_GetPVZList_APIKeyAllowList(t *testing.T) {
+	allowed := []string{"123e4567-e89b-12d3-a456-426614174001"}
+
+	checkFilter := func(pvzIDs []string) {
+		if !reflect.DeepEqual(pvzIDs, allowed) {
+			t.Errorf("Expected list limited to %v, got %v", allowed, pvzIDs)
+		}
+	}
+
+	mockService := MockPVZService{
+		GetPVZListFunc: func(ctx context.Context, filter dto.PVZFilterQuery) (*dto.PaginatedResponse, error) {
+			checkFilter(filter.PVZIDs)
+			return &dto.PaginatedResponse{}, nil
+		},
+		StreamPVZListFunc: func(ctx context.Context, filter dto.PVZFilterQuery, fn func(dto.PVZWithReceptionsResponse) error) error {
+			checkFilter(filter.PVZIDs)
+			return nil
+		},
+		GetNearbyPVZListFunc: func(ctx context.Context, query dto.PVZNearbyQuery) ([]model.NearbyPVZ, error) {
+			checkFilter(query.PVZIDs)
+			return []model.NearbyPVZ{}, nil
+		},
+	}
+
+	tests := []struct {
+		name   string
+		path   string
+		accept string
+	}{
+		{name: "JSON", path: "/pvz"},
+		{name: "NDJSON", path: "/pvz", accept: "application/x-ndjson"},
+		{name: "Nearby", path: "/pvz/nearby?lat=55.75&lon=37.61"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := gin.New()
+			router.Use(func(c *gin.Context) {
+				c.Set("apiKey", &model.APIKey{PVZIDs: allowed})
+			})
+			pvzHandler := handler.NewPVZHandler(&mockService)
+
+			router.GET("/pvz", pvzHandler.GetPVZList)
+			router.GET("/pvz/nearby", pvzHandler.GetNearbyPVZList)
+
+			req, _ := http.NewRequest(http.MethodGet, tt.path, nil)
+			if tt.accept != "" {
+				req.Header.Set("Accept", tt.accept)
+			}
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			if w.Code != http.StatusOK {
+				t.Errorf("Expected status %d, got %d", http.StatusOK, w.Code)
+			}
+		})
+	}
+}
+
+func TestPVZHandler_GetPVZ(t *testing.T) {
+	testTime := time.Date(2025, 4, 15, 12, 0, 0, 0, time.UTC)
+
+	pvz := model.PVZ{
+		ID:               "123e4567-e89b-12d3-a456-426614174001",
+		RegistrationDate: testTime,
+		City:             "Москва",
+		Status:           model.PVZStatusActive,
+	}
+
+	tests := []struct {
+		name           string
+		mockService    MockPVZService
+		pvzID          string
+		queryParams    string
+		expectedStatus int
+		expectedBody   any
+	}{
+		{
+			name: "Success",
+			mockService: MockPVZService{
+				GetPVZFunc: func(ctx context.Context, pvzID string, query dto.PVZDetailsQuery) (*dto.PVZDetailsResponse, error) {
+					if !query.IncludeReceptions {
+						t.Error("Expected receptions to be requested")
+					}
+					return &dto.PVZDetailsResponse{
+						PVZ:        pvz,
+						Receptions: []dto.ReceptionWithProductsResponse{},
+					}, nil
+				},
+			},
+			queryParams:    "?includeReceptions=true",
+			expectedStatus: http.StatusOK,
+			expectedBody:   &dto.PVZDetailsResponse{PVZ: pvz},
+		},
+		{
+			name: "PVZ Not Found",
+			mockService: MockPVZService{
+				GetPVZFunc: func(ctx context.Context, pvzID string, query dto.PVZDetailsQuery) (*dto.PVZDetailsResponse, error) {
+					return nil, model.ErrPVZNotFound
+				},
+			},
+			expectedStatus: http.StatusNotFound,
+			expectedBody: model.Error{
+				Message: model.ErrPVZNotFound.Error(),
+			},
+		},
+		{
+			name:           "Invalid Query Parameters",
+			mockService:    MockPVZService{},
+			queryParams:    "?includeReceptions=maybe",
+			expectedStatus: http.StatusBadRequest,
+			expectedBody: model.Error{
+				Message: "Invalid query parameters",
+			},
+		},
+		{
+			name:           "Invalid PVZ ID",
+			mockService:    MockPVZService{},
+			pvzID:          "not-a-uuid",
+			expectedStatus: http.StatusBadRequest,
+			expectedBody: model.Error{
+				Message: "Invalid path parameters",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := gin.New()
+			pvzHandler := handler.NewPVZHandler(&tt.mockService)
+
+			router.GET("/pvz/:pvzId", pvzHandler.GetPVZ)
+
+			pvzID := tt.pvzID
+			if pvzID == "" {
+				pvzID = pvz.ID
+			}
+
+			req, _ := http.NewRequest(http.MethodGet, "/pvz/"+pvzID+tt.queryParams, nil)
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			if w.Code != tt.expectedStatus {
+				t.Errorf("Expected status %d, got %d", tt.expectedStatus, w.Code)
+			}
+
+			var response any
+			if tt.expectedStatus == http.StatusOK {
+				var details dto.PVZDetailsResponse
+				json.Unmarshal(w.Body.Bytes(), &details)
+				response = &details
+			} else {
+				var errResponse model.Error
+				json.Unmarshal(w.Body.Bytes(), &errResponse)
+				response = errResponse
+			}
+
+			if !reflect.DeepEqual(tt.expectedBody, response) {
+				t.Errorf("Expected body %v, got %v", tt.expectedBody, response)
+			}
+		})
+	}
+}
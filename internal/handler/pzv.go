@@ -1,10 +1,14 @@
 package handler
 
 import (
+	"encoding/json"
+	"errors"
 	"net/http"
+	"strings"
 
 	"github.com/gin-gonic/gin"
 	"github.com/kirillidk/pvz-service/internal/dto"
+	"github.com/kirillidk/pvz-service/internal/middleware"
 	"github.com/kirillidk/pvz-service/internal/model"
 	service "github.com/kirillidk/pvz-service/internal/service/pvz"
 )
@@ -26,13 +30,6 @@ func (h *PVZHandler) CreatePVZ(c *gin.Context) {
 		return
 	}
 
-	if _, ok := model.ValidCities[pvzReq.City]; !ok {
-		c.JSON(http.StatusBadRequest, model.Error{
-			Message: "City must be one of: Москва, Санкт-Петербург, Казань",
-		})
-		return
-	}
-
 	createdPVZ, err := h.pvzService.CreatePVZ(c.Request.Context(), pvzReq)
 	if err != nil {
 		c.JSON(http.StatusBadRequest, model.Error{Message: err.Error()})
@@ -48,6 +45,12 @@ func (h *PVZHandler) GetPVZList(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, model.Error{Message: "Invalid query parameters"})
 		return
 	}
+	filter.PVZIDs = middleware.AllowedPVZIDs(c)
+
+	if strings.Contains(c.GetHeader("Accept"), ndjsonContentType) {
+		h.streamPVZList(c, filter)
+		return
+	}
 
 	result, err := h.pvzService.GetPVZList(c.Request.Context(), filter)
 	if err != nil {
@@ -57,3 +60,114 @@ func (h *PVZHandler) GetPVZList(c *gin.Context) {
 
 	c.JSON(http.StatusOK, result)
 }
+
+// streamPVZList writes every matching PVZ as a separate JSON line, ignoring
+// pagination. The request context is canceled when the client disconnects,
+// which also cancels the database query.
+func (h *PVZHandler) streamPVZList(c *gin.Context, filter dto.PVZFilterQuery) {
+	c.Header("Content-Type", ndjsonContentType)
+
+	encoder := json.NewEncoder(c.Writer)
+	err := h.pvzService.StreamPVZList(c.Request.Context(), filter, func(pvz dto.PVZWithReceptionsResponse) error {
+		if err := encoder.Encode(pvz); err != nil {
+			return err
+		}
+		c.Writer.Flush()
+		return nil
+	})
+	if err != nil {
+		abortStream(c, err)
+		return
+	}
+
+	c.Status(http.StatusOK)
+}
+
+func (h *PVZHandler) GetPVZ(c *gin.Context) {
+	var uri dto.PVZURI
+	if err := c.ShouldBindUri(&uri); err != nil {
+		c.JSON(http.StatusBadRequest, model.Error{Message: "Invalid path parameters"})
+		return
+	}
+
+	var query dto.PVZDetailsQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.JSON(http.StatusBadRequest, model.Error{Message: "Invalid query parameters"})
+		return
+	}
+
+	result, err := h.pvzService.GetPVZ(c.Request.Context(), uri.PVZID, query)
+	if err != nil {
+		if errors.Is(err, model.ErrPVZNotFound) {
+			c.JSON(http.StatusNotFound, model.Error{Message: err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, model.Error{Message: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+func (h *PVZHandler) UpdatePVZ(c *gin.Context) {
+	var pvzReq dto.PVZUpdateRequest
+	if err := c.ShouldBindJSON(&pvzReq); err != nil {
+		c.JSON(http.StatusBadRequest, model.Error{Message: "Invalid request data"})
+		return
+	}
+
+	updatedPVZ, err := h.pvzService.UpdatePVZ(c.Request.Context(), c.Param("pvzId"), pvzReq)
+	if err != nil {
+		if errors.Is(err, model.ErrPVZNotFound) {
+			c.JSON(http.StatusNotFound, model.Error{Message: err.Error()})
+			return
+		}
+		c.JSON(http.StatusBadRequest, model.Error{Message: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, updatedPVZ)
+}
+
+func (h *PVZHandler) SuspendPVZ(c *gin.Context) {
+	h.changePVZStatus(c, model.PVZStatusSuspended)
+}
+
+func (h *PVZHandler) ReopenPVZ(c *gin.Context) {
+	h.changePVZStatus(c, model.PVZStatusActive)
+}
+
+func (h *PVZHandler) ArchivePVZ(c *gin.Context) {
+	h.changePVZStatus(c, model.PVZStatusArchived)
+}
+
+func (h *PVZHandler) changePVZStatus(c *gin.Context, status model.PVZStatus) {
+	updatedPVZ, err := h.pvzService.ChangePVZStatus(c.Request.Context(), c.Param("pvzId"), status)
+	if err != nil {
+		if errors.Is(err, model.ErrPVZNotFound) {
+			c.JSON(http.StatusNotFound, model.Error{Message: err.Error()})
+			return
+		}
+		c.JSON(http.StatusBadRequest, model.Error{Message: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, updatedPVZ)
+}
+
+func (h *PVZHandler) GetNearbyPVZList(c *gin.Context) {
+	var query dto.PVZNearbyQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.JSON(http.StatusBadRequest, model.Error{Message: "Invalid query parameters"})
+		return
+	}
+	query.PVZIDs = middleware.AllowedPVZIDs(c)
+
+	result, err := h.pvzService.GetNearbyPVZList(c.Request.Context(), query)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, model.Error{Message: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
@@ -1,10 +1,12 @@
 package handler
 
 import (
+	"errors"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
 	"github.com/kirillidk/pvz-service/internal/dto"
+	"github.com/kirillidk/pvz-service/internal/middleware"
 	"github.com/kirillidk/pvz-service/internal/model"
 	service "github.com/kirillidk/pvz-service/internal/service/reception"
 )
@@ -26,6 +28,11 @@ func (h *ReceptionHandler) CreateReception(c *gin.Context) {
 		return
 	}
 
+	if !middleware.HasPVZAccess(c, receptionCreateReq.PVZID) {
+		c.JSON(http.StatusForbidden, model.Error{Message: "Access to this PVZ is not permitted"})
+		return
+	}
+
 	reception, err := h.receptionService.CreateReception(c.Request.Context(), receptionCreateReq)
 	if err != nil {
 		c.JSON(http.StatusBadRequest, model.Error{Message: err.Error()})
@@ -50,3 +57,153 @@ func (h *ReceptionHandler) CloseLastReception(c *gin.Context) {
 
 	c.JSON(http.StatusOK, closedReception)
 }
+
+func (h *ReceptionHandler) ReopenReception(c *gin.Context) {
+	var reopenReq dto.ReceptionReopenRequest
+	if err := c.ShouldBindJSON(&reopenReq); err != nil {
+		c.JSON(http.StatusBadRequest, model.Error{Message: "Invalid request data"})
+		return
+	}
+
+	current, err := h.receptionService.GetReception(c.Request.Context(), c.Param("receptionId"))
+	if err != nil {
+		if errors.Is(err, model.ErrReceptionNotFound) {
+			c.JSON(http.StatusNotFound, model.Error{Message: err.Error()})
+			return
+		}
+		c.JSON(http.StatusBadRequest, model.Error{Message: err.Error()})
+		return
+	}
+
+	if !middleware.HasPVZAccess(c, current.Reception.PVZID) {
+		c.JSON(http.StatusForbidden, model.Error{Message: "Access to this PVZ is not permitted"})
+		return
+	}
+
+	reception, err := h.receptionService.ReopenReception(c.Request.Context(), c.Param("receptionId"), reopenReq.Reason)
+	if err != nil {
+		if errors.Is(err, model.ErrReceptionNotFound) {
+			c.JSON(http.StatusNotFound, model.Error{Message: err.Error()})
+			return
+		}
+		c.JSON(http.StatusBadRequest, model.Error{Message: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, reception)
+}
+
+func (h *ReceptionHandler) GetReception(c *gin.Context) {
+	var uri dto.ReceptionURI
+	if err := c.ShouldBindUri(&uri); err != nil {
+		c.JSON(http.StatusBadRequest, model.Error{Message: "Invalid path parameters"})
+		return
+	}
+
+	result, err := h.receptionService.GetReception(c.Request.Context(), uri.ReceptionID)
+	if err != nil {
+		if errors.Is(err, model.ErrReceptionNotFound) {
+			c.JSON(http.StatusNotFound, model.Error{Message: err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, model.Error{Message: err.Error()})
+		return
+	}
+
+	if !middleware.HasPVZAccess(c, result.Reception.PVZID) {
+		c.JSON(http.StatusForbidden, model.Error{Message: "Access to this PVZ is not permitted"})
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+func (h *ReceptionHandler) GetCurrentReception(c *gin.Context) {
+	var uri dto.PVZURI
+	if err := c.ShouldBindUri(&uri); err != nil {
+		c.JSON(http.StatusBadRequest, model.Error{Message: "Invalid path parameters"})
+		return
+	}
+
+	result, err := h.receptionService.GetCurrentReception(c.Request.Context(), uri.PVZID)
+	if err != nil {
+		if errors.Is(err, model.ErrNoOpenReception) {
+			c.JSON(http.StatusNotFound, model.Error{Message: err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, model.Error{Message: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+func (h *ReceptionHandler) SetExpectedCount(c *gin.Context) {
+	var manifestReq dto.ReceptionManifestRequest
+	if err := c.ShouldBindJSON(&manifestReq); err != nil {
+		c.JSON(http.StatusBadRequest, model.Error{Message: "Invalid request data"})
+		return
+	}
+
+	reception, err := h.receptionService.SetExpectedCount(
+		c.Request.Context(), c.Param("pvzId"), c.Param("receptionId"), *manifestReq.ExpectedCount,
+	)
+	if err != nil {
+		if errors.Is(err, model.ErrReceptionNotFound) {
+			c.JSON(http.StatusNotFound, model.Error{Message: err.Error()})
+			return
+		}
+		c.JSON(http.StatusBadRequest, model.Error{Message: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, reception)
+}
+
+func (h *ReceptionHandler) GetDiscrepancyReport(c *gin.Context) {
+	report, err := h.receptionService.GetDiscrepancyReport(c.Request.Context(), c.Param("pvzId"), c.Param("receptionId"))
+	if err != nil {
+		if errors.Is(err, model.ErrReceptionNotFound) {
+			c.JSON(http.StatusNotFound, model.Error{Message: err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, model.Error{Message: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, report)
+}
+
+func (h *ReceptionHandler) GetDiscrepancies(c *gin.Context) {
+	var filter dto.DiscrepancyFilterQuery
+	if err := c.ShouldBindQuery(&filter); err != nil {
+		c.JSON(http.StatusBadRequest, model.Error{Message: "Invalid query parameters"})
+		return
+	}
+
+	discrepancies, err := h.receptionService.GetDiscrepancies(c.Request.Context(), filter)
+	if err != nil {
+		if errors.Is(err, model.ErrInvalidDateRange) {
+			c.JSON(http.StatusBadRequest, model.Error{Message: err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, model.Error{Message: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, discrepancies)
+}
+
+func (h *ReceptionHandler) GetReceptionStatusHistory(c *gin.Context) {
+	history, err := h.receptionService.GetReceptionStatusHistory(c.Request.Context(), c.Param("pvzId"), c.Param("receptionId"))
+	if err != nil {
+		if errors.Is(err, model.ErrReceptionNotFound) {
+			c.JSON(http.StatusNotFound, model.Error{Message: err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, model.Error{Message: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, history)
+}
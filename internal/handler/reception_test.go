@@ -5,6 +5,7 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"reflect"
@@ -19,17 +20,58 @@ import (
 
 type MockReceptionService struct {
 	CreateReceptionFunc    func(ctx context.Context, req dto.ReceptionCreateRequest) (*model.Reception, error)
-	CloseLastReceptionFunc func(ctx context.Context, pvzID string) (*model.Reception, error)
+	CloseLastReceptionFunc func(ctx context.Context, pvzID string) (*dto.ReceptionCloseResponse, error)
+	ReopenReceptionFunc    func(ctx context.Context, receptionID, reason string) (*model.Reception, error)
+
+	GetReceptionFunc        func(ctx context.Context, receptionID string) (*dto.ReceptionWithProductsResponse, error)
+	GetCurrentReceptionFunc func(ctx context.Context, pvzID string) (*dto.ReceptionWithProductsResponse, error)
+
+	SetExpectedCountFunc     func(ctx context.Context, pvzID, receptionID string, expectedCount int) (*model.Reception, error)
+	GetDiscrepancyReportFunc func(ctx context.Context, pvzID, receptionID string) (*dto.ReceptionDiscrepancyReport, error)
+	GetDiscrepanciesFunc     func(ctx context.Context, filter dto.DiscrepancyFilterQuery) ([]model.ReceptionDiscrepancy, error)
+
+	GetReceptionStatusHistoryFunc func(ctx context.Context, pvzID, receptionID string) ([]model.ReceptionStatusChange, error)
 }
 
 func (m *MockReceptionService) CreateReception(ctx context.Context, req dto.ReceptionCreateRequest) (*model.Reception, error) {
 	return m.CreateReceptionFunc(ctx, req)
 }
 
-func (m *MockReceptionService) CloseLastReception(ctx context.Context, pvzID string) (*model.Reception, error) {
+func (m *MockReceptionService) CloseLastReception(ctx context.Context, pvzID string) (*dto.ReceptionCloseResponse, error) {
 	return m.CloseLastReceptionFunc(ctx, pvzID)
 }
 
+func (m *MockReceptionService) ReopenReception(ctx context.Context, receptionID, reason string) (*model.Reception, error) {
+	return m.ReopenReceptionFunc(ctx, receptionID, reason)
+}
+
+func (m *MockReceptionService) GetReception(ctx context.Context, receptionID string) (*dto.ReceptionWithProductsResponse, error) {
+	return m.GetReceptionFunc(ctx, receptionID)
+}
+
+func (m *MockReceptionService) GetCurrentReception(ctx context.Context, pvzID string) (*dto.ReceptionWithProductsResponse, error) {
+	return m.GetCurrentReceptionFunc(ctx, pvzID)
+}
+
+func (m *MockReceptionService) SetExpectedCount(ctx context.Context, pvzID, receptionID string, expectedCount int) (*model.Reception, error) {
+	return m.SetExpectedCountFunc(ctx, pvzID, receptionID, expectedCount)
+}
+
+func (m *MockReceptionService) GetDiscrepancyReport(ctx context.Context, pvzID, receptionID string) (*dto.ReceptionDiscrepancyReport, error) {
+	return m.GetDiscrepancyReportFunc(ctx, pvzID, receptionID)
+}
+
+func (m *MockReceptionService) GetDiscrepancies(ctx context.Context, filter dto.DiscrepancyFilterQuery) ([]model.ReceptionDiscrepancy, error) {
+	return m.GetDiscrepanciesFunc(ctx, filter)
+}
+
+func (m *MockReceptionService) GetReceptionStatusHistory(
+	ctx context.Context,
+	pvzID, receptionID string,
+) ([]model.ReceptionStatusChange, error) {
+	return m.GetReceptionStatusHistoryFunc(ctx, pvzID, receptionID)
+}
+
 func TestReceptionHandler_CreateReception(t *testing.T) {
 	testTime := time.Now()
 
@@ -149,28 +191,71 @@ func TestReceptionHandler_CloseLastReception(t *testing.T) {
 		{
 			name: "Success",
 			mockService: MockReceptionService{
-				CloseLastReceptionFunc: func(ctx context.Context, pvzID string) (*model.Reception, error) {
-					return &model.Reception{
-						ID:       "123e4567-e89b-12d3-a456-426614174001",
-						DateTime: testTime,
-						PVZID:    pvzID,
-						Status:   "close",
+				CloseLastReceptionFunc: func(ctx context.Context, pvzID string) (*dto.ReceptionCloseResponse, error) {
+					return &dto.ReceptionCloseResponse{
+						Reception: model.Reception{
+							ID:       "123e4567-e89b-12d3-a456-426614174001",
+							DateTime: testTime,
+							PVZID:    pvzID,
+							Status:   "close",
+						},
 					}, nil
 				},
 			},
 			pvzID:          "123e4567-e89b-12d3-a456-426614174002",
 			expectedStatus: http.StatusOK,
-			expectedBody: model.Reception{
-				ID:       "123e4567-e89b-12d3-a456-426614174001",
-				DateTime: testTime,
-				PVZID:    "123e4567-e89b-12d3-a456-426614174002",
-				Status:   "close",
+			expectedBody: dto.ReceptionCloseResponse{
+				Reception: model.Reception{
+					ID:       "123e4567-e89b-12d3-a456-426614174001",
+					DateTime: testTime,
+					PVZID:    "123e4567-e89b-12d3-a456-426614174002",
+					Status:   "close",
+				},
+			},
+		},
+		{
+			name: "Success With Reconciliation",
+			mockService: MockReceptionService{
+				CloseLastReceptionFunc: func(ctx context.Context, pvzID string) (*dto.ReceptionCloseResponse, error) {
+					return &dto.ReceptionCloseResponse{
+						Reception: model.Reception{
+							ID:       "123e4567-e89b-12d3-a456-426614174001",
+							DateTime: testTime,
+							PVZID:    pvzID,
+							Status:   "close",
+						},
+						Reconciliation: &model.DeliveryReconciliation{
+							DeliveryID:    "123e4567-e89b-12d3-a456-426614174030",
+							ExpectedCount: 2,
+							MatchedCount:  1,
+							Missing:       []model.ExpectedDeliveryItem{{Barcode: "4600000000002", Type: "обувь"}},
+							Unexpected:    []model.Product{{ID: "123e4567-e89b-12d3-a456-426614174010", Type: "одежда"}},
+						},
+					}, nil
+				},
+			},
+			pvzID:          "123e4567-e89b-12d3-a456-426614174002",
+			expectedStatus: http.StatusOK,
+			expectedBody: dto.ReceptionCloseResponse{
+				Reception: model.Reception{
+					ID:       "123e4567-e89b-12d3-a456-426614174001",
+					DateTime: testTime,
+					PVZID:    "123e4567-e89b-12d3-a456-426614174002",
+					Status:   "close",
+				},
+				Reconciliation: &model.DeliveryReconciliation{
+					DeliveryID:    "123e4567-e89b-12d3-a456-426614174030",
+					ExpectedCount: 2,
+					MatchedCount:  1,
+					Missing:       []model.ExpectedDeliveryItem{{Barcode: "4600000000002", Type: "обувь"}},
+					Unexpected:    []model.Product{{ID: "123e4567-e89b-12d3-a456-426614174010", Type: "одежда"}},
+				},
 			},
 		},
 		{
 			name: "Missing PVZ ID",
 			mockService: MockReceptionService{
-				CloseLastReceptionFunc: func(ctx context.Context, pvzID string) (*model.Reception, error) {
+				CloseLastReceptionFunc: func(ctx context.Context, pvzID string) (*dto.ReceptionCloseResponse, error) {
 					return nil, nil
 				},
 			},
@@ -183,7 +268,7 @@ func TestReceptionHandler_CloseLastReception(t *testing.T) {
 		{
 			name: "Service Error",
 			mockService: MockReceptionService{
-				CloseLastReceptionFunc: func(ctx context.Context, pvzID string) (*model.Reception, error) {
+				CloseLastReceptionFunc: func(ctx context.Context, pvzID string) (*dto.ReceptionCloseResponse, error) {
 					return nil, errors.New("failed to close reception")
 				},
 			},
@@ -211,15 +296,598 @@ func TestReceptionHandler_CloseLastReception(t *testing.T) {
 				t.Errorf("Expected status %d, got %d", tt.expectedStatus, w.Code)
 			}
 
+			var response any
+			if tt.expectedStatus == http.StatusOK {
+				var closeResponse dto.ReceptionCloseResponse
+				json.Unmarshal(w.Body.Bytes(), &closeResponse)
+
+				if !closeResponse.DateTime.IsZero() {
+					closeResponse.DateTime = testTime
+				}
+
+				response = closeResponse
+			} else {
+				var errResponse model.Error
+				json.Unmarshal(w.Body.Bytes(), &errResponse)
+				response = errResponse
+			}
+
+			if !reflect.DeepEqual(tt.expectedBody, response) {
+				t.Errorf("Expected body %v, got %v", tt.expectedBody, response)
+			}
+		})
+	}
+}
+
+func TestReceptionHandler_GetReception(t *testing.T) {
+	testTime := time.Date(2025, 4, 15, 12, 0, 0, 0, time.UTC)
+
+	response := &dto.ReceptionWithProductsResponse{
+		Reception: model.Reception{
+			ID:       "123e4567-e89b-12d3-a456-426614174002",
+			DateTime: testTime,
+			PVZID:    "123e4567-e89b-12d3-a456-426614174001",
+			Status:   "close",
+		},
+		Products: []model.Product{
+			{
+				ID:          "123e4567-e89b-12d3-a456-426614174003",
+				DateTime:    testTime,
+				Type:        "electronics",
+				ReceptionID: "123e4567-e89b-12d3-a456-426614174002",
+			},
+		},
+	}
+
+	tests := []struct {
+		name           string
+		mockService    MockReceptionService
+		receptionID    string
+		expectedStatus int
+		expectedBody   any
+	}{
+		{
+			name: "Success",
+			mockService: MockReceptionService{
+				GetReceptionFunc: func(ctx context.Context, receptionID string) (*dto.ReceptionWithProductsResponse, error) {
+					return response, nil
+				},
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   response,
+		},
+		{
+			name: "Reception Not Found",
+			mockService: MockReceptionService{
+				GetReceptionFunc: func(ctx context.Context, receptionID string) (*dto.ReceptionWithProductsResponse, error) {
+					return nil, model.ErrReceptionNotFound
+				},
+			},
+			expectedStatus: http.StatusNotFound,
+			expectedBody: model.Error{
+				Message: model.ErrReceptionNotFound.Error(),
+			},
+		},
+		{
+			name: "Service Error",
+			mockService: MockReceptionService{
+				GetReceptionFunc: func(ctx context.Context, receptionID string) (*dto.ReceptionWithProductsResponse, error) {
+					return nil, errors.New("failed to get reception")
+				},
+			},
+			expectedStatus: http.StatusInternalServerError,
+			expectedBody: model.Error{
+				Message: "failed to get reception",
+			},
+		},
+		{
+			name:           "Invalid Reception ID",
+			mockService:    MockReceptionService{},
+			receptionID:    "not-a-uuid",
+			expectedStatus: http.StatusBadRequest,
+			expectedBody: model.Error{
+				Message: "Invalid path parameters",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := gin.New()
+			receptionHandler := handler.NewReceptionHandler(&tt.mockService)
+
+			router.GET("/receptions/:receptionId", receptionHandler.GetReception)
+
+			receptionID := tt.receptionID
+			if receptionID == "" {
+				receptionID = response.Reception.ID
+			}
+
+			req, _ := http.NewRequest(http.MethodGet, "/receptions/"+receptionID, nil)
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			if w.Code != tt.expectedStatus {
+				t.Errorf("Expected status %d, got %d", tt.expectedStatus, w.Code)
+			}
+
+			var got any
+			if tt.expectedStatus == http.StatusOK {
+				var receptionResponse dto.ReceptionWithProductsResponse
+				json.Unmarshal(w.Body.Bytes(), &receptionResponse)
+				got = &receptionResponse
+			} else {
+				var errResponse model.Error
+				json.Unmarshal(w.Body.Bytes(), &errResponse)
+				got = errResponse
+			}
+
+			if !reflect.DeepEqual(tt.expectedBody, got) {
+				t.Errorf("Expected body %v, got %v", tt.expectedBody, got)
+			}
+		})
+	}
+}
+
+func TestReceptionHandler_GetCurrentReception(t *testing.T) {
+	tests := []struct {
+		name           string
+		mockService    MockReceptionService
+		pvzID          string
+		expectedStatus int
+	}{
+		{
+			name: "Success",
+			mockService: MockReceptionService{
+				GetCurrentReceptionFunc: func(ctx context.Context, pvzID string) (*dto.ReceptionWithProductsResponse, error) {
+					return &dto.ReceptionWithProductsResponse{
+						Reception: model.Reception{ID: "123e4567-e89b-12d3-a456-426614174002", PVZID: pvzID, Status: "in_progress"},
+						Products:  []model.Product{},
+					}, nil
+				},
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name: "No Open Reception",
+			mockService: MockReceptionService{
+				GetCurrentReceptionFunc: func(ctx context.Context, pvzID string) (*dto.ReceptionWithProductsResponse, error) {
+					return nil, model.ErrNoOpenReception
+				},
+			},
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:           "Invalid PVZ ID",
+			mockService:    MockReceptionService{},
+			pvzID:          "not-a-uuid",
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := gin.New()
+			receptionHandler := handler.NewReceptionHandler(&tt.mockService)
+
+			router.GET("/pvz/:pvzId/receptions/current", receptionHandler.GetCurrentReception)
+
+			pvzID := tt.pvzID
+			if pvzID == "" {
+				pvzID = "123e4567-e89b-12d3-a456-426614174001"
+			}
+
+			req, _ := http.NewRequest(http.MethodGet, "/pvz/"+pvzID+"/receptions/current", nil)
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			if w.Code != tt.expectedStatus {
+				t.Errorf("Expected status %d, got %d", tt.expectedStatus, w.Code)
+			}
+		})
+	}
+}
+
+func TestReceptionHandler_SetExpectedCount(t *testing.T) {
+	expectedCount := 12
+
+	tests := []struct {
+		name           string
+		mockService    MockReceptionService
+		requestBody    map[string]any
+		expectedStatus int
+		expectedBody   any
+	}{
+		{
+			name: "Success",
+			mockService: MockReceptionService{
+				SetExpectedCountFunc: func(ctx context.Context, pvzID, receptionID string, count int) (*model.Reception, error) {
+					return &model.Reception{ID: receptionID, PVZID: pvzID, Status: "close", ExpectedCount: &count}, nil
+				},
+			},
+			requestBody:    map[string]any{"expectedCount": expectedCount},
+			expectedStatus: http.StatusOK,
+			expectedBody: model.Reception{
+				ID:            "123e4567-e89b-12d3-a456-426614174001",
+				PVZID:         "123e4567-e89b-12d3-a456-426614174000",
+				Status:        "close",
+				ExpectedCount: &expectedCount,
+			},
+		},
+		{
+			name:           "Zero Expected Count",
+			requestBody:    map[string]any{"expectedCount": 0},
+			expectedStatus: http.StatusOK,
+			mockService: MockReceptionService{
+				SetExpectedCountFunc: func(ctx context.Context, pvzID, receptionID string, count int) (*model.Reception, error) {
+					return &model.Reception{ID: receptionID, PVZID: pvzID, Status: "close", ExpectedCount: &count}, nil
+				},
+			},
+			expectedBody: model.Reception{
+				ID:            "123e4567-e89b-12d3-a456-426614174001",
+				PVZID:         "123e4567-e89b-12d3-a456-426614174000",
+				Status:        "close",
+				ExpectedCount: new(int),
+			},
+		},
+		{
+			name:           "Missing Expected Count",
+			requestBody:    map[string]any{},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   model.Error{Message: "Invalid request data"},
+		},
+		{
+			name:           "Negative Expected Count",
+			requestBody:    map[string]any{"expectedCount": -1},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   model.Error{Message: "Invalid request data"},
+		},
+		{
+			name: "Reception Not Found",
+			mockService: MockReceptionService{
+				SetExpectedCountFunc: func(ctx context.Context, pvzID, receptionID string, count int) (*model.Reception, error) {
+					return nil, model.ErrReceptionNotFound
+				},
+			},
+			requestBody:    map[string]any{"expectedCount": expectedCount},
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   model.Error{Message: model.ErrReceptionNotFound.Error()},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := gin.New()
+			receptionHandler := handler.NewReceptionHandler(&tt.mockService)
+
+			router.PUT("/pvz/:pvzId/receptions/:receptionId/manifest", receptionHandler.SetExpectedCount)
+
+			jsonBody, _ := json.Marshal(tt.requestBody)
+			req, _ := http.NewRequest(
+				http.MethodPut,
+				"/pvz/123e4567-e89b-12d3-a456-426614174000/receptions/123e4567-e89b-12d3-a456-426614174001/manifest",
+				bytes.NewBuffer(jsonBody),
+			)
+			req.Header.Set("Content-Type", "application/json")
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			if w.Code != tt.expectedStatus {
+				t.Errorf("Expected status %d, got %d", tt.expectedStatus, w.Code)
+			}
+
 			var response any
 			if tt.expectedStatus == http.StatusOK {
 				var reception model.Reception
 				json.Unmarshal(w.Body.Bytes(), &reception)
+				response = reception
+			} else {
+				var errResponse model.Error
+				json.Unmarshal(w.Body.Bytes(), &errResponse)
+				response = errResponse
+			}
 
-				if !reception.DateTime.IsZero() {
-					reception.DateTime = testTime
-				}
+			if !reflect.DeepEqual(tt.expectedBody, response) {
+				t.Errorf("Expected body %v, got %v", tt.expectedBody, response)
+			}
+		})
+	}
+}
+
+func TestReceptionHandler_GetDiscrepancyReport(t *testing.T) {
+	report := &dto.ReceptionDiscrepancyReport{
+		ReceptionDiscrepancy: model.ReceptionDiscrepancy{
+			ReceptionID:   "123e4567-e89b-12d3-a456-426614174001",
+			PVZID:         "123e4567-e89b-12d3-a456-426614174000",
+			DateTime:      time.Date(2025, 4, 15, 12, 0, 0, 0, time.UTC),
+			Status:        "close",
+			ReceivedCount: 2,
+			OpenedCount:   1,
+		},
+		HasDiscrepancy: true,
+		Products: []model.Product{
+			{ID: "123e4567-e89b-12d3-a456-426614174002", Type: "обувь", Condition: model.ProductConditionOpened},
+		},
+	}
+
+	tests := []struct {
+		name           string
+		mockService    MockReceptionService
+		expectedStatus int
+		expectedBody   any
+	}{
+		{
+			name: "Success",
+			mockService: MockReceptionService{
+				GetDiscrepancyReportFunc: func(ctx context.Context, pvzID, receptionID string) (*dto.ReceptionDiscrepancyReport, error) {
+					return report, nil
+				},
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   *report,
+		},
+		{
+			name: "Reception Not Found",
+			mockService: MockReceptionService{
+				GetDiscrepancyReportFunc: func(ctx context.Context, pvzID, receptionID string) (*dto.ReceptionDiscrepancyReport, error) {
+					return nil, model.ErrReceptionNotFound
+				},
+			},
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   model.Error{Message: model.ErrReceptionNotFound.Error()},
+		},
+		{
+			name: "Service Error",
+			mockService: MockReceptionService{
+				GetDiscrepancyReportFunc: func(ctx context.Context, pvzID, receptionID string) (*dto.ReceptionDiscrepancyReport, error) {
+					return nil, errors.New("failed to get reception discrepancy")
+				},
+			},
+			expectedStatus: http.StatusInternalServerError,
+			expectedBody:   model.Error{Message: "failed to get reception discrepancy"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := gin.New()
+			receptionHandler := handler.NewReceptionHandler(&tt.mockService)
+
+			router.GET("/pvz/:pvzId/receptions/:receptionId/discrepancies", receptionHandler.GetDiscrepancyReport)
+
+			req, _ := http.NewRequest(
+				http.MethodGet,
+				"/pvz/123e4567-e89b-12d3-a456-426614174000/receptions/123e4567-e89b-12d3-a456-426614174001/discrepancies",
+				nil,
+			)
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			if w.Code != tt.expectedStatus {
+				t.Errorf("Expected status %d, got %d", tt.expectedStatus, w.Code)
+			}
+
+			var response any
+			if tt.expectedStatus == http.StatusOK {
+				var got dto.ReceptionDiscrepancyReport
+				json.Unmarshal(w.Body.Bytes(), &got)
+				response = got
+			} else {
+				var errResponse model.Error
+				json.Unmarshal(w.Body.Bytes(), &errResponse)
+				response = errResponse
+			}
+
+			if !reflect.DeepEqual(tt.expectedBody, response) {
+				t.Errorf("Expected body %v, got %v", tt.expectedBody, response)
+			}
+		})
+	}
+}
+
+func TestReceptionHandler_GetDiscrepancies(t *testing.T) {
+	discrepancies := []model.ReceptionDiscrepancy{
+		{
+			ReceptionID:   "123e4567-e89b-12d3-a456-426614174001",
+			PVZID:         "123e4567-e89b-12d3-a456-426614174000",
+			DateTime:      time.Date(2025, 4, 15, 12, 0, 0, 0, time.UTC),
+			Status:        "close",
+			ReceivedCount: 3,
+			DamagedCount:  1,
+		},
+	}
+
+	tests := []struct {
+		name           string
+		query          string
+		mockService    MockReceptionService
+		expectedStatus int
+		expectedBody   any
+	}{
+		{
+			name:  "Success",
+			query: "?pvzId=123e4567-e89b-12d3-a456-426614174000&page=2&limit=5",
+			mockService: MockReceptionService{
+				GetDiscrepanciesFunc: func(ctx context.Context, filter dto.DiscrepancyFilterQuery) ([]model.ReceptionDiscrepancy, error) {
+					if filter.PVZID != "123e4567-e89b-12d3-a456-426614174000" || filter.Page != 2 || filter.Limit != 5 {
+						return nil, errors.New("unexpected filter")
+					}
+					return discrepancies, nil
+				},
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   discrepancies,
+		},
+		{
+			name:           "Invalid PVZ ID",
+			query:          "?pvzId=invalid",
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   model.Error{Message: "Invalid query parameters"},
+		},
+		{
+			name:  "Invalid Date Range",
+			query: "?startDate=2025-04-16T00:00:00Z&endDate=2025-04-15T00:00:00Z",
+			mockService: MockReceptionService{
+				GetDiscrepanciesFunc: func(ctx context.Context, filter dto.DiscrepancyFilterQuery) ([]model.ReceptionDiscrepancy, error) {
+					return nil, model.ErrInvalidDateRange
+				},
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   model.Error{Message: model.ErrInvalidDateRange.Error()},
+		},
+		{
+			name: "Service Error",
+			mockService: MockReceptionService{
+				GetDiscrepanciesFunc: func(ctx context.Context, filter dto.DiscrepancyFilterQuery) ([]model.ReceptionDiscrepancy, error) {
+					return nil, errors.New("failed to get reception discrepancies")
+				},
+			},
+			expectedStatus: http.StatusInternalServerError,
+			expectedBody:   model.Error{Message: "failed to get reception discrepancies"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := gin.New()
+			receptionHandler := handler.NewReceptionHandler(&tt.mockService)
+
+			router.GET("/receptions/discrepancies", receptionHandler.GetDiscrepancies)
+
+			req, _ := http.NewRequest(http.MethodGet, "/receptions/discrepancies"+tt.query, nil)
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			if w.Code != tt.expectedStatus {
+				t.Errorf("Expected status %d, got %d", tt.expectedStatus, w.Code)
+			}
+
+			var response any
+			if tt.expectedStatus == http.StatusOK {
+				var got []model.ReceptionDiscrepancy
+				json.Unmarshal(w.Body.Bytes(), &got)
+				response = got
+			} else {
+				var errResponse model.Error
+				json.Unmarshal(w.Body.Bytes(), &errResponse)
+				response = errResponse
+			}
+
+			if !reflect.DeepEqual(tt.expectedBody, response) {
+				t.Errorf("Expected body %v, got %v", tt.expectedBody, response)
+			}
+		})
+	}
+}
 
+func TestReceptionHandler_ReopenReception(t *testing.T) {
+	receptionID := "123e4567-e89b-12d3-a456-426614174001"
+	pvzID := "123e4567-e89b-12d3-a456-426614174000"
+	getReception := func(ctx context.Context, id string) (*dto.ReceptionWithProductsResponse, error) {
+		return &dto.ReceptionWithProductsResponse{Reception: model.Reception{ID: id, PVZID: pvzID, Status: "close"}}, nil
+	}
+
+	tests := []struct {
+		name           string
+		mockService    MockReceptionService
+		apiKey         *model.APIKey
+		requestBody    map[string]any
+		expectedStatus int
+		expectedBody   any
+	}{
+		{
+			name: "Success",
+			mockService: MockReceptionService{
+				GetReceptionFunc: getReception,
+				ReopenReceptionFunc: func(ctx context.Context, id, reason string) (*model.Reception, error) {
+					if reason != "products scanned after closing" {
+						return nil, errors.New("unexpected reason " + reason)
+					}
+					return &model.Reception{ID: id, PVZID: "123e4567-e89b-12d3-a456-426614174000", Status: "in_progress"}, nil
+				},
+			},
+			requestBody:    map[string]any{"reason": "products scanned after closing"},
+			expectedStatus: http.StatusOK,
+			expectedBody: model.Reception{
+				ID:     receptionID,
+				PVZID:  "123e4567-e89b-12d3-a456-426614174000",
+				Status: "in_progress",
+			},
+		},
+		{
+			name:           "Missing Reason",
+			requestBody:    map[string]any{},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   model.Error{Message: "Invalid request data"},
+		},
+		{
+			name: "Reception Not Found",
+			mockService: MockReceptionService{
+				GetReceptionFunc: func(ctx context.Context, id string) (*dto.ReceptionWithProductsResponse, error) {
+					return nil, fmt.Errorf("failed to get reception: %w", model.ErrReceptionNotFound)
+				},
+			},
+			requestBody:    map[string]any{"reason": "wrong reception closed"},
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   model.Error{Message: "failed to get reception: " + model.ErrReceptionNotFound.Error()},
+		},
+		{
+			name: "Window Expired",
+			mockService: MockReceptionService{
+				GetReceptionFunc: getReception,
+				ReopenReceptionFunc: func(ctx context.Context, id, reason string) (*model.Reception, error) {
+					return nil, model.ErrReceptionReopenExpired
+				},
+			},
+			requestBody:    map[string]any{"reason": "wrong reception closed"},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   model.Error{Message: model.ErrReceptionReopenExpired.Error()},
+		},
+		{
+			name: "PVZ Not Allowed",
+			mockService: MockReceptionService{
+				GetReceptionFunc: getReception,
+			},
+			apiKey:         &model.APIKey{Role: model.ModeratorRole, PVZIDs: []string{"123e4567-e89b-12d3-a456-426614174009"}},
+			requestBody:    map[string]any{"reason": "wrong reception closed"},
+			expectedStatus: http.StatusForbidden,
+			expectedBody:   model.Error{Message: "Access to this PVZ is not permitted"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := gin.New()
+			receptionHandler := handler.NewReceptionHandler(&tt.mockService)
+
+			if tt.apiKey != nil {
+				router.Use(func(c *gin.Context) {
+					c.Set("apiKey", tt.apiKey)
+				})
+			}
+
+			router.POST("/receptions/:receptionId/reopen", receptionHandler.ReopenReception)
+
+			jsonBody, _ := json.Marshal(tt.requestBody)
+			req, _ := http.NewRequest(http.MethodPost, "/receptions/"+receptionID+"/reopen", bytes.NewBuffer(jsonBody))
+			req.Header.Set("Content-Type", "application/json")
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			if w.Code != tt.expectedStatus {
+				t.Errorf("Expected status %d, got %d", tt.expectedStatus, w.Code)
+			}
+
+			var response any
+			if tt.expectedStatus == http.StatusOK {
+				var reception model.Reception
+				json.Unmarshal(w.Body.Bytes(), &reception)
 				response = reception
 			} else {
 				var errResponse model.Error
@@ -233,3 +901,94 @@ func TestReceptionHandler_CloseLastReception(t *testing.T) {
 		})
 	}
 }
+
+func TestReceptionHandler_GetReceptionStatusHistory(t *testing.T) {
+	changedAt := time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC)
+	history := []model.ReceptionStatusChange{
+		{ID: 1, ReceptionID: "123e4567-e89b-12d3-a456-426614174001", ToStatus: "in_progress", ChangedAt: changedAt, Actor: model.Actor{Type: model.UserActor}},
+		{
+			ID:          2,
+			ReceptionID: "123e4567-e89b-12d3-a456-426614174001",
+			FromStatus:  "close",
+			ToStatus:    "in_progress",
+			Reason:      "products scanned after closing",
+			ChangedAt:   changedAt.Add(time.Hour),
+			Actor:       model.Actor{ID: "123e4567-e89b-12d3-a456-426614174050", Type: model.UserActor, Role: model.ModeratorRole},
+		},
+	}
+
+	tests := []struct {
+		name           string
+		mockService    MockReceptionService
+		expectedStatus int
+		expectedBody   any
+	}{
+		{
+			name: "Success",
+			mockService: MockReceptionService{
+				GetReceptionStatusHistoryFunc: func(ctx context.Context, pvzID, receptionID string) ([]model.ReceptionStatusChange, error) {
+					return history, nil
+				},
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   history,
+		},
+		{
+			name: "Reception Not Found",
+			mockService: MockReceptionService{
+				GetReceptionStatusHistoryFunc: func(ctx context.Context, pvzID, receptionID string) ([]model.ReceptionStatusChange, error) {
+					return nil, model.ErrReceptionNotFound
+				},
+			},
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   model.Error{Message: model.ErrReceptionNotFound.Error()},
+		},
+		{
+			name: "Service Error",
+			mockService: MockReceptionService{
+				GetReceptionStatusHistoryFunc: func(ctx context.Context, pvzID, receptionID string) ([]model.ReceptionStatusChange, error) {
+					return nil, errors.New("database error")
+				},
+			},
+			expectedStatus: http.StatusInternalServerError,
+			expectedBody:   model.Error{Message: "database error"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := gin.New()
+			receptionHandler := handler.NewReceptionHandler(&tt.mockService)
+
+			router.GET("/pvz/:pvzId/receptions/:receptionId/history", receptionHandler.GetReceptionStatusHistory)
+
+			req, _ := http.NewRequest(
+				http.MethodGet,
+				"/pvz/123e4567-e89b-12d3-a456-426614174000/receptions/123e4567-e89b-12d3-a456-426614174001/history",
+				nil,
+			)
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			if w.Code != tt.expectedStatus {
+				t.Errorf("Expected status %d, got %d", tt.expectedStatus, w.Code)
+			}
+
+			var response any
+			if tt.expectedStatus == http.StatusOK {
+				var changes []model.ReceptionStatusChange
+				json.Unmarshal(w.Body.Bytes(), &changes)
+				response = changes
+			} else {
+				var errResponse model.Error
+				json.Unmarshal(w.Body.Bytes(), &errResponse)
+				response = errResponse
+			}
+
+			if !reflect.DeepEqual(tt.expectedBody, response) {
+				t.Errorf("Expected body %v, got %v", tt.expectedBody, response)
+			}
+		})
+	}
+}
@@ -0,0 +1,83 @@
+package handler
+
+import (
+	"errors"
+	"log"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/kirillidk/pvz-service/internal/dto"
+	"github.com/kirillidk/pvz-service/internal/model"
+	service "github.com/kirillidk/pvz-service/internal/service/stats"
+)
+
+type StatsHandler struct {
+	statsService service.StatsServiceInterface
+}
+
+func NewStatsHandler(statsService service.StatsServiceInterface) *StatsHandler {
+	return &StatsHandler{
+		statsService: statsService,
+	}
+}
+
+func (h *StatsHandler) GetDailyStats(c *gin.Context) {
+	var query dto.DailyStatsQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.JSON(http.StatusBadRequest, model.Error{Message: "Invalid query parameters"})
+		return
+	}
+
+	stats, err := h.statsService.GetDailyStats(c.Request.Context(), query)
+	if err != nil {
+		respondStatsError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, stats)
+}
+
+func (h *StatsHandler) GetPVZDailyStats(c *gin.Context) {
+	var query dto.DailyStatsQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.JSON(http.StatusBadRequest, model.Error{Message: "Invalid query parameters"})
+		return
+	}
+
+	stats, err := h.statsService.GetPVZDailyStats(c.Request.Context(), c.Param("pvzId"), query)
+	if err != nil {
+		respondStatsError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, stats)
+}
+
+func (h *StatsHandler) GetReceptionThroughput(c *gin.Context) {
+	var query dto.ReceptionThroughputQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.JSON(http.StatusBadRequest, model.Error{Message: "Invalid query parameters"})
+		return
+	}
+
+	stats, err := h.statsService.GetReceptionThroughput(c.Request.Context(), query)
+	if err != nil {
+		respondStatsError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, stats)
+}
+
+func respondStatsError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, model.ErrInvalidDateRange):
+		c.JSON(http.StatusBadRequest, model.Error{Message: err.Error()})
+	case errors.Is(err, model.ErrPVZNotFound):
+		c.JSON(http.StatusNotFound, model.Error{Message: err.Error()})
+	default:
+		// The error may carry SQL details, so it is only logged.
+		log.Printf("failed to get stats: %v", err)
+		c.JSON(http.StatusInternalServerError, model.Error{Message: "Failed to get statistics"})
+	}
+}
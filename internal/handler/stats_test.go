@@ -0,0 +1,231 @@
+package handler_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/kirillidk/pvz-service/internal/dto"
+	"github.com/kirillidk/pvz-service/internal/handler"
+	"github.com/kirillidk/pvz-service/internal/model"
+)
+
+type MockStatsService struct {
+	GetDailyStatsFunc    func(ctx context.Context, query dto.DailyStatsQuery) ([]model.DailyReceptionStats, error)
+	GetPVZDailyStatsFunc func(ctx context.Context, pvzID string, query dto.DailyStatsQuery) ([]model.DailyReceptionStats, error)
+
+	GetReceptionThroughputFunc func(ctx context.Context, query dto.ReceptionThroughputQuery) ([]model.ReceptionThroughputStats, error)
+}
+
+func (m *MockStatsService) GetDailyStats(ctx context.Context, query dto.DailyStatsQuery) ([]model.DailyReceptionStats, error) {
+	return m.GetDailyStatsFunc(ctx, query)
+}
+
+func (m *MockStatsService) GetPVZDailyStats(ctx context.Context, pvzID string, query dto.DailyStatsQuery) ([]model.DailyReceptionStats, error) {
+	return m.GetPVZDailyStatsFunc(ctx, pvzID, query)
+}
+
+func (m *MockStatsService) GetReceptionThroughput(ctx context.Context, query dto.ReceptionThroughputQuery) ([]model.ReceptionThroughputStats, error) {
+	return m.GetReceptionThroughputFunc(ctx, query)
+}
+
+func TestStatsHandler_GetDailyStats(t *testing.T) {
+	dailyStats := []model.DailyReceptionStats{
+		{
+			Date:           "2025-04-15",
+			PVZID:          "123e4567-e89b-12d3-a456-426614174001",
+			ReceptionCount: 2,
+			ProductCount:   3,
+			ProductsByType: map[string]int64{"электроника": 3},
+		},
+	}
+
+	tests := []struct {
+		name           string
+		mockService    MockStatsService
+		queryParams    string
+		expectedStatus int
+	}{
+		{
+			name: "Success",
+			mockService: MockStatsService{
+				GetDailyStatsFunc: func(ctx context.Context, query dto.DailyStatsQuery) ([]model.DailyReceptionStats, error) {
+					if query.From.Format("2006-01-02") != "2025-04-01" || query.To.Format("2006-01-02") != "2025-04-30" {
+						return nil, errors.New("unexpected period")
+					}
+					return dailyStats, nil
+				},
+			},
+			queryParams:    "?from=2025-04-01&to=2025-04-30",
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "Missing Period",
+			mockService:    MockStatsService{},
+			queryParams:    "?from=2025-04-01",
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "Malformed Date",
+			mockService:    MockStatsService{},
+			queryParams:    "?from=01.04.2025&to=2025-04-30",
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name: "Invalid Range",
+			mockService: MockStatsService{
+				GetDailyStatsFunc: func(ctx context.Context, query dto.DailyStatsQuery) ([]model.DailyReceptionStats, error) {
+					return nil, fmt.Errorf("%w: 'to' must not be before 'from'", model.ErrInvalidDateRange)
+				},
+			},
+			queryParams:    "?from=2025-04-30&to=2025-04-01",
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name: "Service Error",
+			mockService: MockStatsService{
+				GetDailyStatsFunc: func(ctx context.Context, query dto.DailyStatsQuery) ([]model.DailyReceptionStats, error) {
+					return nil, errors.New("failed to get daily stats")
+				},
+			},
+			queryParams:    "?from=2025-04-01&to=2025-04-30",
+			expectedStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := gin.New()
+			statsHandler := handler.NewStatsHandler(&tt.mockService)
+
+			router.GET("/pvz/stats/daily", statsHandler.GetDailyStats)
+
+			req, _ := http.NewRequest(http.MethodGet, "/pvz/stats/daily"+tt.queryParams, nil)
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			if w.Code != tt.expectedStatus {
+				t.Errorf("Expected status %d, got %d", tt.expectedStatus, w.Code)
+			}
+
+			if tt.expectedStatus == http.StatusOK {
+				var response []model.DailyReceptionStats
+				json.Unmarshal(w.Body.Bytes(), &response)
+
+				if !reflect.DeepEqual(dailyStats, response) {
+					t.Errorf("Expected body %v, got %v", dailyStats, response)
+				}
+			}
+
+			if tt.expectedStatus == http.StatusInternalServerError {
+				var response model.Error
+				json.Unmarshal(w.Body.Bytes(), &response)
+
+				if response.Message != "Failed to get statistics" {
+					t.Errorf("Expected a generic error message, got %q", response.Message)
+				}
+			}
+		})
+	}
+}
+
+func TestStatsHandler_GetPVZDailyStats(t *testing.T) {
+	pvzID := "123e4567-e89b-12d3-a456-426614174001"
+
+	tests := []struct {
+		name           string
+		mockService    MockStatsService
+		expectedStatus int
+	}{
+		{
+			name: "Success",
+			mockService: MockStatsService{
+				GetPVZDailyStatsFunc: func(ctx context.Context, id string, query dto.DailyStatsQuery) ([]model.DailyReceptionStats, error) {
+					return []model.DailyReceptionStats{{Date: "2025-04-15", PVZID: id}}, nil
+				},
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name: "PVZ Not Found",
+			mockService: MockStatsService{
+				GetPVZDailyStatsFunc: func(ctx context.Context, id string, query dto.DailyStatsQuery) ([]model.DailyReceptionStats, error) {
+					return nil, fmt.Errorf("failed to get PVZ: %w", model.ErrPVZNotFound)
+				},
+			},
+			expectedStatus: http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := gin.New()
+			statsHandler := handler.NewStatsHandler(&tt.mockService)
+
+			router.GET("/pvz/:pvzId/stats/daily", statsHandler.GetPVZDailyStats)
+
+			req, _ := http.NewRequest(http.MethodGet, "/pvz/"+pvzID+"/stats/daily?from=2025-04-01&to=2025-04-30", nil)
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			if w.Code != tt.expectedStatus {
+				t.Errorf("Expected status %d, got %d", tt.expectedStatus, w.Code)
+			}
+		})
+	}
+}
+
+func TestStatsHandler_GetReceptionThroughput(t *testing.T) {
+	tests := []struct {
+		name           string
+		mockService    MockStatsService
+		queryParams    string
+		expectedStatus int
+	}{
+		{
+			name: "Success",
+			mockService: MockStatsService{
+				GetReceptionThroughputFunc: func(ctx context.Context, query dto.ReceptionThroughputQuery) ([]model.ReceptionThroughputStats, error) {
+					if query.GroupBy != dto.ThroughputGroupByPVZ {
+						return nil, errors.New("unexpected grouping")
+					}
+					return []model.ReceptionThroughputStats{}, nil
+				},
+			},
+			queryParams:    "?from=2025-04-01&to=2025-04-30",
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "Invalid Grouping",
+			mockService:    MockStatsService{},
+			queryParams:    "?from=2025-04-01&to=2025-04-30&groupBy=region",
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := gin.New()
+			statsHandler := handler.NewStatsHandler(&tt.mockService)
+
+			router.GET("/pvz/stats/throughput", statsHandler.GetReceptionThroughput)
+
+			req, _ := http.NewRequest(http.MethodGet, "/pvz/stats/throughput"+tt.queryParams, nil)
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			if w.Code != tt.expectedStatus {
+				t.Errorf("Expected status %d, got %d", tt.expectedStatus, w.Code)
+			}
+		})
+	}
+}
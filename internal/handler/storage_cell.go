@@ -0,0 +1,109 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/kirillidk/pvz-service/internal/dto"
+	"github.com/kirillidk/pvz-service/internal/model"
+	service "github.com/kirillidk/pvz-service/internal/service/storagecell"
+)
+
+type StorageCellHandler struct {
+	storageCellService service.StorageCellServiceInterface
+}
+
+func NewStorageCellHandler(storageCellService service.StorageCellServiceInterface) *StorageCellHandler {
+	return &StorageCellHandler{
+		storageCellService: storageCellService,
+	}
+}
+
+func (h *StorageCellHandler) CreateStorageCell(c *gin.Context) {
+	var cellReq dto.StorageCellCreateRequest
+	if err := c.ShouldBindJSON(&cellReq); err != nil {
+		c.JSON(http.StatusBadRequest, model.Error{Message: "Invalid request data"})
+		return
+	}
+
+	cell, err := h.storageCellService.CreateStorageCell(c.Request.Context(), c.Param("pvzId"), cellReq)
+	if err != nil {
+		respondStorageCellError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, cell)
+}
+
+func (h *StorageCellHandler) GetStorageCells(c *gin.Context) {
+	cells, err := h.storageCellService.GetStorageCells(c.Request.Context(), c.Param("pvzId"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, model.Error{Message: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, cells)
+}
+
+func (h *StorageCellHandler) GetStorageCellOccupancy(c *gin.Context) {
+	occupancy, err := h.storageCellService.GetStorageCellOccupancy(c.Request.Context(), c.Param("pvzId"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, model.Error{Message: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, occupancy)
+}
+
+func (h *StorageCellHandler) UpdateStorageCell(c *gin.Context) {
+	var cellReq dto.StorageCellUpdateRequest
+	if err := c.ShouldBindJSON(&cellReq); err != nil {
+		c.JSON(http.StatusBadRequest, model.Error{Message: "Invalid request data"})
+		return
+	}
+
+	cell, err := h.storageCellService.UpdateStorageCell(c.Request.Context(), c.Param("pvzId"), c.Param("cellId"), cellReq)
+	if err != nil {
+		respondStorageCellError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, cell)
+}
+
+func (h *StorageCellHandler) DeleteStorageCell(c *gin.Context) {
+	if err := h.storageCellService.DeleteStorageCell(c.Request.Context(), c.Param("pvzId"), c.Param("cellId")); err != nil {
+		respondStorageCellError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+func (h *StorageCellHandler) AssignProductToCell(c *gin.Context) {
+	var assignReq dto.ProductCellAssignRequest
+	if err := c.ShouldBindJSON(&assignReq); err != nil {
+		c.JSON(http.StatusBadRequest, model.Error{Message: "Invalid request data"})
+		return
+	}
+
+	product, err := h.storageCellService.AssignProductToCell(
+		c.Request.Context(), c.Param("pvzId"), c.Param("productId"), assignReq.CellID,
+	)
+	if err != nil {
+		respondStorageCellError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, product)
+}
+
+func respondStorageCellError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, model.ErrStorageCellNotFound), errors.Is(err, model.ErrProductNotFound), errors.Is(err, model.ErrPVZNotFound):
+		c.JSON(http.StatusNotFound, model.Error{Message: err.Error()})
+	default:
+		c.JSON(http.StatusBadRequest, model.Error{Message: err.Error()})
+	}
+}
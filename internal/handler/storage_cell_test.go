@@ -0,0 +1,325 @@
+package handler_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/kirillidk/pvz-service/internal/dto"
+	"github.com/kirillidk/pvz-service/internal/handler"
+	"github.com/kirillidk/pvz-service/internal/model"
+)
+
+type MockStorageCellService struct {
+	CreateStorageCellFunc       func(ctx context.Context, pvzID string, req dto.StorageCellCreateRequest) (*model.StorageCell, error)
+	GetStorageCellsFunc         func(ctx context.Context, pvzID string) ([]model.StorageCell, error)
+	UpdateStorageCellFunc       func(ctx context.Context, pvzID, cellID string, req dto.StorageCellUpdateRequest) (*model.StorageCell, error)
+	DeleteStorageCellFunc       func(ctx context.Context, pvzID, cellID string) error
+	GetStorageCellOccupancyFunc func(ctx context.Context, pvzID string) ([]model.StorageCellOccupancy, error)
+	AssignProductToCellFunc     func(ctx context.Context, pvzID, productID, cellID string) (*model.Product, error)
+}
+
+func (m *MockStorageCellService) CreateStorageCell(ctx context.Context, pvzID string, req dto.StorageCellCreateRequest) (*model.StorageCell, error) {
+	return m.CreateStorageCellFunc(ctx, pvzID, req)
+}
+
+func (m *MockStorageCellService) GetStorageCells(ctx context.Context, pvzID string) ([]model.StorageCell, error) {
+	return m.GetStorageCellsFunc(ctx, pvzID)
+}
+
+func (m *MockStorageCellService) UpdateStorageCell(
+	ctx context.Context,
+	pvzID, cellID string,
+	req dto.StorageCellUpdateRequest,
+) (*model.StorageCell, error) {
+	return m.UpdateStorageCellFunc(ctx, pvzID, cellID, req)
+}
+
+func (m *MockStorageCellService) DeleteStorageCell(ctx context.Context, pvzID, cellID string) error {
+	return m.DeleteStorageCellFunc(ctx, pvzID, cellID)
+}
+
+func (m *MockStorageCellService) GetStorageCellOccupancy(ctx context.Context, pvzID string) ([]model.StorageCellOccupancy, error) {
+	return m.GetStorageCellOccupancyFunc(ctx, pvzID)
+}
+
+func (m *MockStorageCellService) AssignProductToCell(ctx context.Context, pvzID, productID, cellID string) (*model.Product, error) {
+	return m.AssignProductToCellFunc(ctx, pvzID, productID, cellID)
+}
+
+func (m *MockStorageCellService) CheckCellPlacement(ctx context.Context, pvzID, cellID string, productTypes ...string) error {
+	return nil
+}
+
+func (m *MockStorageCellService) SuggestStorageCell(ctx context.Context, pvzID, productType string) (*model.StorageCell, error) {
+	return nil, nil
+}
+
+func TestStorageCellHandler_CreateStorageCell(t *testing.T) {
+	pvzID := "123e4567-e89b-12d3-a456-426614174003"
+	cell := model.StorageCell{
+		ID:        "123e4567-e89b-12d3-a456-426614174005",
+		PVZID:     pvzID,
+		Code:      "A-01",
+		Capacity:  20,
+		CreatedAt: time.Date(2025, 4, 15, 12, 0, 0, 0, time.UTC),
+	}
+
+	tests := []struct {
+		name           string
+		body           any
+		mockService    MockStorageCellService
+		expectedStatus int
+		expectedBody   any
+	}{
+		{
+			name: "Success",
+			body: dto.StorageCellCreateRequest{Code: "A-01", Capacity: 20},
+			mockService: MockStorageCellService{
+				CreateStorageCellFunc: func(ctx context.Context, id string, req dto.StorageCellCreateRequest) (*model.StorageCell, error) {
+					return &cell, nil
+				},
+			},
+			expectedStatus: http.StatusCreated,
+			expectedBody:   cell,
+		},
+		{
+			name:           "Invalid Capacity",
+			body:           map[string]any{"code": "A-01", "capacity": 0},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   model.Error{Message: "Invalid request data"},
+		},
+		{
+			name: "Duplicate Code",
+			body: dto.StorageCellCreateRequest{Code: "A-01", Capacity: 20},
+			mockService: MockStorageCellService{
+				CreateStorageCellFunc: func(ctx context.Context, id string, req dto.StorageCellCreateRequest) (*model.StorageCell, error) {
+					return nil, model.ErrStorageCellAlreadyExists
+				},
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   model.Error{Message: model.ErrStorageCellAlreadyExists.Error()},
+		},
+		{
+			name: "PVZ Not Found",
+			body: dto.StorageCellCreateRequest{Code: "A-01", Capacity: 20},
+			mockService: MockStorageCellService{
+				CreateStorageCellFunc: func(ctx context.Context, id string, req dto.StorageCellCreateRequest) (*model.StorageCell, error) {
+					return nil, model.ErrPVZNotFound
+				},
+			},
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   model.Error{Message: model.ErrPVZNotFound.Error()},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := gin.New()
+			storageCellHandler := handler.NewStorageCellHandler(&tt.mockService)
+
+			router.POST("/pvz/:pvzId/cells", storageCellHandler.CreateStorageCell)
+
+			body, _ := json.Marshal(tt.body)
+			req, _ := http.NewRequest(http.MethodPost, "/pvz/"+pvzID+"/cells", bytes.NewBuffer(body))
+			req.Header.Set("Content-Type", "application/json")
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			if w.Code != tt.expectedStatus {
+				t.Errorf("Expected status %d, got %d", tt.expectedStatus, w.Code)
+			}
+
+			var response any
+			if tt.expectedStatus == http.StatusCreated {
+				var created model.StorageCell
+				json.Unmarshal(w.Body.Bytes(), &created)
+				response = created
+			} else {
+				var errResponse model.Error
+				json.Unmarshal(w.Body.Bytes(), &errResponse)
+				response = errResponse
+			}
+
+			if !reflect.DeepEqual(tt.expectedBody, response) {
+				t.Errorf("Expected body %v, got %v", tt.expectedBody, response)
+			}
+		})
+	}
+}
+
+func TestStorageCellHandler_GetStorageCellOccupancy(t *testing.T) {
+	pvzID := "123e4567-e89b-12d3-a456-426614174003"
+	occupancy := []model.StorageCellOccupancy{
+		{
+			StorageCell: model.StorageCell{
+				ID:        "123e4567-e89b-12d3-a456-426614174005",
+				PVZID:     pvzID,
+				Code:      "A-01",
+				Capacity:  20,
+				CreatedAt: time.Date(2025, 4, 15, 12, 0, 0, 0, time.UTC),
+			},
+			Occupied: 5,
+			Free:     15,
+		},
+	}
+
+	tests := []struct {
+		name           string
+		mockService    MockStorageCellService
+		expectedStatus int
+		expectedBody   any
+	}{
+		{
+			name: "Success",
+			mockService: MockStorageCellService{
+				GetStorageCellOccupancyFunc: func(ctx context.Context, id string) ([]model.StorageCellOccupancy, error) {
+					return occupancy, nil
+				},
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   occupancy,
+		},
+		{
+			name: "Service Error",
+			mockService: MockStorageCellService{
+				GetStorageCellOccupancyFunc: func(ctx context.Context, id string) ([]model.StorageCellOccupancy, error) {
+					return nil, errors.New("failed to get storage cell occupancy")
+				},
+			},
+			expectedStatus: http.StatusInternalServerError,
+			expectedBody:   model.Error{Message: "failed to get storage cell occupancy"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := gin.New()
+			storageCellHandler := handler.NewStorageCellHandler(&tt.mockService)
+
+			router.GET("/pvz/:pvzId/cells/occupancy", storageCellHandler.GetStorageCellOccupancy)
+
+			req, _ := http.NewRequest(http.MethodGet, "/pvz/"+pvzID+"/cells/occupancy", nil)
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			if w.Code != tt.expectedStatus {
+				t.Errorf("Expected status %d, got %d", tt.expectedStatus, w.Code)
+			}
+
+			var response any
+			if tt.expectedStatus == http.StatusOK {
+				var got []model.StorageCellOccupancy
+				json.Unmarshal(w.Body.Bytes(), &got)
+				response = got
+			} else {
+				var errResponse model.Error
+				json.Unmarshal(w.Body.Bytes(), &errResponse)
+				response = errResponse
+			}
+
+			if !reflect.DeepEqual(tt.expectedBody, response) {
+				t.Errorf("Expected body %v, got %v", tt.expectedBody, response)
+			}
+		})
+	}
+}
+
+func TestStorageCellHandler_AssignProductToCell(t *testing.T) {
+	const path = "/pvz/123e4567-e89b-12d3-a456-426614174003/products/123e4567-e89b-12d3-a456-426614174001/cell"
+	cellID := "123e4567-e89b-12d3-a456-426614174005"
+
+	tests := []struct {
+		name           string
+		body           any
+		mockService    MockStorageCellService
+		expectedStatus int
+		expectedBody   any
+	}{
+		{
+			name: "Success",
+			body: dto.ProductCellAssignRequest{CellID: cellID},
+			mockService: MockStorageCellService{
+				AssignProductToCellFunc: func(ctx context.Context, pvzID, productID, cellID string) (*model.Product, error) {
+					return &model.Product{ID: productID, Type: "обувь", Status: model.ProductStatusStored, CellID: cellID}, nil
+				},
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody: model.Product{
+				ID: "123e4567-e89b-12d3-a456-426614174001", Type: "обувь", Status: model.ProductStatusStored, CellID: cellID,
+			},
+		},
+		{
+			name:           "Invalid Cell ID",
+			body:           dto.ProductCellAssignRequest{CellID: "A-01"},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   model.Error{Message: "Invalid request data"},
+		},
+		{
+			name: "Cell Full",
+			body: dto.ProductCellAssignRequest{CellID: cellID},
+			mockService: MockStorageCellService{
+				AssignProductToCellFunc: func(ctx context.Context, pvzID, productID, cellID string) (*model.Product, error) {
+					return nil, model.ErrStorageCellFull
+				},
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   model.Error{Message: model.ErrStorageCellFull.Error()},
+		},
+		{
+			name: "Cell Not Found",
+			body: dto.ProductCellAssignRequest{CellID: cellID},
+			mockService: MockStorageCellService{
+				AssignProductToCellFunc: func(ctx context.Context, pvzID, productID, cellID string) (*model.Product, error) {
+					return nil, model.ErrStorageCellNotFound
+				},
+			},
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   model.Error{Message: model.ErrStorageCellNotFound.Error()},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := gin.New()
+			storageCellHandler := handler.NewStorageCellHandler(&tt.mockService)
+
+			router.PUT("/pvz/:pvzId/products/:productId/cell", storageCellHandler.AssignProductToCell)
+
+			body, _ := json.Marshal(tt.body)
+			req, _ := http.NewRequest(http.MethodPut, path, bytes.NewBuffer(body))
+			req.Header.Set("Content-Type", "application/json")
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			if w.Code != tt.expectedStatus {
+				t.Errorf("Expected status %d, got %d", tt.expectedStatus, w.Code)
+			}
+
+			var response any
+			if tt.expectedStatus == http.StatusOK {
+				var product model.Product
+				json.Unmarshal(w.Body.Bytes(), &product)
+				response = product
+			} else {
+				var errResponse model.Error
+				json.Unmarshal(w.Body.Bytes(), &errResponse)
+				response = errResponse
+			}
+
+			if !reflect.DeepEqual(tt.expectedBody, response) {
+				t.Errorf("Expected body %v, got %v", tt.expectedBody, response)
+			}
+		})
+	}
+}
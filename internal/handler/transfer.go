@@ -0,0 +1,104 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/kirillidk/pvz-service/internal/dto"
+	"github.com/kirillidk/pvz-service/internal/model"
+	service "github.com/kirillidk/pvz-service/internal/service/transfer"
+)
+
+type TransferHandler struct {
+	transferService service.TransferServiceInterface
+}
+
+func NewTransferHandler(transferService service.TransferServiceInterface) *TransferHandler {
+	return &TransferHandler{
+		transferService: transferService,
+	}
+}
+
+func (h *TransferHandler) CreateTransfer(c *gin.Context) {
+	var transferCreateReq dto.TransferCreateRequest
+	if err := c.ShouldBindJSON(&transferCreateReq); err != nil {
+		c.JSON(http.StatusBadRequest, model.Error{Message: "Invalid request data"})
+		return
+	}
+
+	transfer, err := h.transferService.CreateTransfer(c.Request.Context(), c.Param("pvzId"), transferCreateReq)
+	if err != nil {
+		respondTransferError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, transfer)
+}
+
+func (h *TransferHandler) ShipTransfer(c *gin.Context) {
+	transfer, err := h.transferService.ShipTransfer(c.Request.Context(), c.Param("pvzId"), c.Param("transferId"))
+	if err != nil {
+		respondTransferError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, transfer)
+}
+
+func (h *TransferHandler) ReceiveTransfer(c *gin.Context) {
+	transfer, err := h.transferService.ReceiveTransfer(c.Request.Context(), c.Param("pvzId"), c.Param("transferId"))
+	if err != nil {
+		respondTransferError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, transfer)
+}
+
+func (h *TransferHandler) GetTransfers(c *gin.Context) {
+	transfers, err := h.transferService.GetTransfers(c.Request.Context(), c.Param("pvzId"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, model.Error{Message: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, transfers)
+}
+
+func (h *TransferHandler) GetTransfer(c *gin.Context) {
+	transfer, err := h.transferService.GetTransfer(c.Request.Context(), c.Param("pvzId"), c.Param("transferId"))
+	if err != nil {
+		if errors.Is(err, model.ErrTransferNotFound) {
+			c.JSON(http.StatusNotFound, model.Error{Message: err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, model.Error{Message: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, transfer)
+}
+
+func (h *TransferHandler) GetProductTransfers(c *gin.Context) {
+	transfers, err := h.transferService.GetProductTransfers(c.Request.Context(), c.Param("pvzId"), c.Param("productId"))
+	if err != nil {
+		if errors.Is(err, model.ErrProductNotFound) {
+			c.JSON(http.StatusNotFound, model.Error{Message: err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, model.Error{Message: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, transfers)
+}
+
+func respondTransferError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, model.ErrTransferNotFound), errors.Is(err, model.ErrProductNotFound), errors.Is(err, model.ErrPVZNotFound):
+		c.JSON(http.StatusNotFound, model.Error{Message: err.Error()})
+	default:
+		c.JSON(http.StatusBadRequest, model.Error{Message: err.Error()})
+	}
+}
@@ -0,0 +1,315 @@
+package handler_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/kirillidk/pvz-service/internal/dto"
+	"github.com/kirillidk/pvz-service/internal/handler"
+	"github.com/kirillidk/pvz-service/internal/model"
+)
+
+type MockTransferService struct {
+	CreateTransferFunc      func(ctx context.Context, pvzID string, req dto.TransferCreateRequest) (*model.Transfer, error)
+	ShipTransferFunc        func(ctx context.Context, pvzID, transferID string) (*model.Transfer, error)
+	ReceiveTransferFunc     func(ctx context.Context, pvzID, transferID string) (*model.Transfer, error)
+	GetTransfersFunc        func(ctx context.Context, pvzID string) ([]model.Transfer, error)
+	GetTransferFunc         func(ctx context.Context, pvzID, transferID string) (*model.Transfer, error)
+	GetProductTransfersFunc func(ctx context.Context, pvzID, productID string) ([]model.Transfer, error)
+}
+
+func (m *MockTransferService) CreateTransfer(ctx context.Context, pvzID string, req dto.TransferCreateRequest) (*model.Transfer, error) {
+	return m.CreateTransferFunc(ctx, pvzID, req)
+}
+
+func (m *MockTransferService) ShipTransfer(ctx context.Context, pvzID, transferID string) (*model.Transfer, error) {
+	return m.ShipTransferFunc(ctx, pvzID, transferID)
+}
+
+func (m *MockTransferService) ReceiveTransfer(ctx context.Context, pvzID, transferID string) (*model.Transfer, error) {
+	return m.ReceiveTransferFunc(ctx, pvzID, transferID)
+}
+
+func (m *MockTransferService) GetTransfers(ctx context.Context, pvzID string) ([]model.Transfer, error) {
+	return m.GetTransfersFunc(ctx, pvzID)
+}
+
+func (m *MockTransferService) GetTransfer(ctx context.Context, pvzID, transferID string) (*model.Transfer, error) {
+	return m.GetTransferFunc(ctx, pvzID, transferID)
+}
+
+func (m *MockTransferService) GetProductTransfers(ctx context.Context, pvzID, productID string) ([]model.Transfer, error) {
+	return m.GetProductTransfersFunc(ctx, pvzID, productID)
+}
+
+func TestTransferHandler_CreateTransfer(t *testing.T) {
+	pvzID := "123e4567-e89b-12d3-a456-426614174003"
+	createdTransfer := model.Transfer{
+		ID:               "123e4567-e89b-12d3-a456-426614174008",
+		SourcePVZID:      pvzID,
+		DestinationPVZID: "223e4567-e89b-12d3-a456-426614174003",
+		Status:           model.TransferStatusCreated,
+		ProductIDs:       []string{"123e4567-e89b-12d3-a456-426614174001"},
+	}
+	validRequest := dto.TransferCreateRequest{
+		DestinationPVZID: "223e4567-e89b-12d3-a456-426614174003",
+		ProductIDs:       []string{"123e4567-e89b-12d3-a456-426614174001"},
+	}
+
+	tests := []struct {
+		name           string
+		requestBody    any
+		mockService    MockTransferService
+		expectedStatus int
+		expectedBody   any
+	}{
+		{
+			name:        "Success",
+			requestBody: validRequest,
+			mockService: MockTransferService{
+				CreateTransferFunc: func(ctx context.Context, pvzID string, req dto.TransferCreateRequest) (*model.Transfer, error) {
+					return &createdTransfer, nil
+				},
+			},
+			expectedStatus: http.StatusCreated,
+			expectedBody:   createdTransfer,
+		},
+		{
+			name:           "No Products",
+			requestBody:    dto.TransferCreateRequest{DestinationPVZID: "223e4567-e89b-12d3-a456-426614174003"},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   model.Error{Message: "Invalid request data"},
+		},
+		{
+			name:        "Product In Order",
+			requestBody: validRequest,
+			mockService: MockTransferService{
+				CreateTransferFunc: func(ctx context.Context, pvzID string, req dto.TransferCreateRequest) (*model.Transfer, error) {
+					return nil, model.ErrProductInOrder
+				},
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   model.Error{Message: model.ErrProductInOrder.Error()},
+		},
+		{
+			name:        "Destination Not Found",
+			requestBody: validRequest,
+			mockService: MockTransferService{
+				CreateTransferFunc: func(ctx context.Context, pvzID string, req dto.TransferCreateRequest) (*model.Transfer, error) {
+					return nil, model.ErrPVZNotFound
+				},
+			},
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   model.Error{Message: model.ErrPVZNotFound.Error()},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := gin.New()
+			transferHandler := handler.NewTransferHandler(&tt.mockService)
+
+			router.POST("/pvz/:pvzId/transfers", transferHandler.CreateTransfer)
+
+			body, _ := json.Marshal(tt.requestBody)
+			req, _ := http.NewRequest(http.MethodPost, "/pvz/"+pvzID+"/transfers", bytes.NewBuffer(body))
+			req.Header.Set("Content-Type", "application/json")
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			if w.Code != tt.expectedStatus {
+				t.Errorf("Expected status %d, got %d", tt.expectedStatus, w.Code)
+			}
+
+			var response any
+			if tt.expectedStatus == http.StatusCreated {
+				var transfer model.Transfer
+				json.Unmarshal(w.Body.Bytes(), &transfer)
+				response = transfer
+			} else {
+				var errResponse model.Error
+				json.Unmarshal(w.Body.Bytes(), &errResponse)
+				response = errResponse
+			}
+
+			if !reflect.DeepEqual(tt.expectedBody, response) {
+				t.Errorf("Expected body %v, got %v", tt.expectedBody, response)
+			}
+		})
+	}
+}
+
+func TestTransferHandler_ReceiveTransfer(t *testing.T) {
+	const path = "/pvz/223e4567-e89b-12d3-a456-426614174003/transfers/123e4567-e89b-12d3-a456-426614174008/receive"
+	receivedTransfer := model.Transfer{
+		ID:               "123e4567-e89b-12d3-a456-426614174008",
+		SourcePVZID:      "123e4567-e89b-12d3-a456-426614174003",
+		DestinationPVZID: "223e4567-e89b-12d3-a456-426614174003",
+		Status:           model.TransferStatusReceived,
+		ProductIDs:       []string{"123e4567-e89b-12d3-a456-426614174001"},
+		ReceptionID:      "223e4567-e89b-12d3-a456-426614174002",
+	}
+
+	tests := []struct {
+		name           string
+		mockService    MockTransferService
+		expectedStatus int
+		expectedBody   any
+	}{
+		{
+			name: "Success",
+			mockService: MockTransferService{
+				ReceiveTransferFunc: func(ctx context.Context, pvzID, transferID string) (*model.Transfer, error) {
+					return &receivedTransfer, nil
+				},
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   receivedTransfer,
+		},
+		{
+			name: "No Open Reception",
+			mockService: MockTransferService{
+				ReceiveTransferFunc: func(ctx context.Context, pvzID, transferID string) (*model.Transfer, error) {
+					return nil, model.ErrNoOpenReception
+				},
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   model.Error{Message: model.ErrNoOpenReception.Error()},
+		},
+		{
+			name: "Transfer Not Found",
+			mockService: MockTransferService{
+				ReceiveTransferFunc: func(ctx context.Context, pvzID, transferID string) (*model.Transfer, error) {
+					return nil, model.ErrTransferNotFound
+				},
+			},
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   model.Error{Message: model.ErrTransferNotFound.Error()},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := gin.New()
+			transferHandler := handler.NewTransferHandler(&tt.mockService)
+
+			router.POST("/pvz/:pvzId/transfers/:transferId/receive", transferHandler.ReceiveTransfer)
+
+			req, _ := http.NewRequest(http.MethodPost, path, nil)
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			if w.Code != tt.expectedStatus {
+				t.Errorf("Expected status %d, got %d", tt.expectedStatus, w.Code)
+			}
+
+			var response any
+			if tt.expectedStatus == http.StatusOK {
+				var transfer model.Transfer
+				json.Unmarshal(w.Body.Bytes(), &transfer)
+				response = transfer
+			} else {
+				var errResponse model.Error
+				json.Unmarshal(w.Body.Bytes(), &errResponse)
+				response = errResponse
+			}
+
+			if !reflect.DeepEqual(tt.expectedBody, response) {
+				t.Errorf("Expected body %v, got %v", tt.expectedBody, response)
+			}
+		})
+	}
+}
+
+func TestTransferHandler_GetProductTransfers(t *testing.T) {
+	const path = "/pvz/123e4567-e89b-12d3-a456-426614174003/products/123e4567-e89b-12d3-a456-426614174001/transfers"
+	transfers := []model.Transfer{
+		{
+			ID:               "123e4567-e89b-12d3-a456-426614174008",
+			SourcePVZID:      "123e4567-e89b-12d3-a456-426614174003",
+			DestinationPVZID: "223e4567-e89b-12d3-a456-426614174003",
+			Status:           model.TransferStatusInTransit,
+			ProductIDs:       []string{"123e4567-e89b-12d3-a456-426614174001"},
+		},
+	}
+
+	tests := []struct {
+		name           string
+		mockService    MockTransferService
+		expectedStatus int
+		expectedBody   any
+	}{
+		{
+			name: "Success",
+			mockService: MockTransferService{
+				GetProductTransfersFunc: func(ctx context.Context, pvzID, productID string) ([]model.Transfer, error) {
+					return transfers, nil
+				},
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   transfers,
+		},
+		{
+			name: "Product Not Found",
+			mockService: MockTransferService{
+				GetProductTransfersFunc: func(ctx context.Context, pvzID, productID string) ([]model.Transfer, error) {
+					return nil, model.ErrProductNotFound
+				},
+			},
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   model.Error{Message: model.ErrProductNotFound.Error()},
+		},
+		{
+			name: "Service Error",
+			mockService: MockTransferService{
+				GetProductTransfersFunc: func(ctx context.Context, pvzID, productID string) ([]model.Transfer, error) {
+					return nil, errors.New("failed to get product transfers")
+				},
+			},
+			expectedStatus: http.StatusInternalServerError,
+			expectedBody:   model.Error{Message: "failed to get product transfers"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := gin.New()
+			transferHandler := handler.NewTransferHandler(&tt.mockService)
+
+			router.GET("/pvz/:pvzId/products/:productId/transfers", transferHandler.GetProductTransfers)
+
+			req, _ := http.NewRequest(http.MethodGet, path, nil)
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			if w.Code != tt.expectedStatus {
+				t.Errorf("Expected status %d, got %d", tt.expectedStatus, w.Code)
+			}
+
+			var response any
+			if tt.expectedStatus == http.StatusOK {
+				var got []model.Transfer
+				json.Unmarshal(w.Body.Bytes(), &got)
+				response = got
+			} else {
+				var errResponse model.Error
+				json.Unmarshal(w.Body.Bytes(), &errResponse)
+				response = errResponse
+			}
+
+			if !reflect.DeepEqual(tt.expectedBody, response) {
+				t.Errorf("Expected body %v, got %v", tt.expectedBody, response)
+			}
+		})
+	}
+}
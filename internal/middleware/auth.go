@@ -6,38 +6,115 @@ import (
 
 	"github.com/gin-gonic/gin"
 	"github.com/kirillidk/pvz-service/internal/model"
+	"github.com/kirillidk/pvz-service/internal/requestctx"
+	"github.com/kirillidk/pvz-service/internal/service/apikey"
 	service "github.com/kirillidk/pvz-service/internal/service/auth"
 )
 
-func AuthMiddleware(jwtSecret string) gin.HandlerFunc {
+const (
+	APIKeyHeader = "X-API-Key"
+
+	apiKeyContextKey = "apiKey"
+)
+
+func AuthMiddleware(jwtSecret string, apiKeyService apikey.APIKeyServiceInterface) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		authHeader := c.GetHeader("Authorization")
-		if authHeader == "" {
-			c.JSON(http.StatusUnauthorized, model.Error{Message: "Authorization header is required"})
-			c.Abort()
+		if rawKey := c.GetHeader(APIKeyHeader); rawKey != "" {
+			apiKey, err := apiKeyService.Authenticate(c.Request.Context(), rawKey)
+			if err != nil {
+				c.JSON(http.StatusUnauthorized, model.Error{Message: "Invalid or expired API key"})
+				c.Abort()
+				return
+			}
+
+			c.Set("userRole", apiKey.Role)
+			c.Set(apiKeyContextKey, apiKey)
+			setActor(c, model.Actor{ID: apiKey.ID, Type: model.APIKeyActor, Role: apiKey.Role})
+
+			c.Next()
+			return
+		}
+
+		token, ok := bearerToken(c)
+		if !ok {
 			return
 		}
 
-		parts := strings.Split(authHeader, " ")
-		if len(parts) != 2 || parts[0] != "Bearer" {
-			c.JSON(http.StatusUnauthorized, model.Error{Message: "Authorization header must be in format: Bearer {token}"})
+		claims, err := service.ValidateToken(token, jwtSecret)
+		if err != nil {
+			c.JSON(http.StatusUnauthorized, model.Error{Message: "Invalid or expired token"})
 			c.Abort()
 			return
 		}
 
-		claims, err := service.ValidateToken(parts[1], jwtSecret)
+		c.Set("userRole", claims.Role)
+		if claims.UserID != "" {
+			c.Set("userID", claims.UserID)
+		}
+		setActor(c, model.Actor{ID: claims.UserID, Type: model.UserActor, Role: claims.Role})
+
+		c.Next()
+	}
+}
+
+// setActor exposes the authenticated caller to services through the request context.
+func setActor(c *gin.Context, actor model.Actor) {
+	c.Request = c.Request.WithContext(requestctx.WithActor(c.Request.Context(), actor))
+}
+
+// TwoFactorEnrollmentMiddleware authenticates registered users for 2FA setup.
+// Besides regular access tokens it accepts the enrollment token issued at login
+// when 2FA is mandatory for the user's role.
+func TwoFactorEnrollmentMiddleware(jwtSecret string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		token, ok := bearerToken(c)
+		if !ok {
+			return
+		}
+
+		claims, err := service.ValidateToken(token, jwtSecret)
+		if err != nil {
+			claims, err = service.ValidateMFAToken(token, jwtSecret, service.PurposeMFAEnroll)
+		}
 		if err != nil {
 			c.JSON(http.StatusUnauthorized, model.Error{Message: "Invalid or expired token"})
 			c.Abort()
 			return
 		}
 
+		if claims.UserID == "" {
+			c.JSON(http.StatusForbidden, model.Error{Message: "Two-factor authentication is available only for registered users"})
+			c.Abort()
+			return
+		}
+
 		c.Set("userRole", claims.Role)
+		c.Set("userID", claims.UserID)
 
 		c.Next()
 	}
 }
 
+// bearerToken extracts the token from the Authorization header,
+// aborting the request with 401 when the header is missing or malformed.
+func bearerToken(c *gin.Context) (string, bool) {
+	authHeader := c.GetHeader("Authorization")
+	if authHeader == "" {
+		c.JSON(http.StatusUnauthorized, model.Error{Message: "Authorization header is required"})
+		c.Abort()
+		return "", false
+	}
+
+	parts := strings.Split(authHeader, " ")
+	if len(parts) != 2 || parts[0] != "Bearer" {
+		c.JSON(http.StatusUnauthorized, model.Error{Message: "Authorization header must be in format: Bearer {token}"})
+		c.Abort()
+		return "", false
+	}
+
+	return parts[1], true
+}
+
 func RoleMiddleware(requiredRoles ...model.UserRole) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		userRole, exists := c.Get("userRole")
@@ -58,3 +135,76 @@ func RoleMiddleware(requiredRoles ...model.UserRole) gin.HandlerFunc {
 		c.Abort()
 	}
 }
+
+// UserOnlyMiddleware rejects requests authenticated with an API key.
+func UserOnlyMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if _, exists := c.Get(apiKeyContextKey); exists {
+			c.JSON(http.StatusForbidden, model.Error{Message: "Operation not permitted for API keys"})
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// AllPVZAccessMiddleware rejects API keys restricted to some PVZs on routes
+// that operate across all PVZs and cannot be narrowed to the allow-list.
+func AllPVZAccessMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if len(AllowedPVZIDs(c)) > 0 {
+			c.JSON(http.StatusForbidden, model.Error{Message: "Operation not permitted for API keys restricted to some PVZs"})
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// PVZAccessMiddleware enforces the API key PVZ allow-list for routes
+// that carry the PVZ ID in the pvzId path parameter.
+func PVZAccessMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !HasPVZAccess(c, c.Param("pvzId")) {
+			c.JSON(http.StatusForbidden, model.Error{Message: "Access to this PVZ is not permitted"})
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// HasPVZAccess reports whether the authenticated caller may operate on the PVZ.
+// Users authenticated with a JWT are not restricted.
+func HasPVZAccess(c *gin.Context, pvzID string) bool {
+	value, exists := c.Get(apiKeyContextKey)
+	if !exists {
+		return true
+	}
+
+	apiKey, ok := value.(*model.APIKey)
+	if !ok {
+		return false
+	}
+
+	return apiKey.AllowsPVZ(pvzID)
+}
+
+// AllowedPVZIDs returns the PVZ allow-list of the API key the request was
+// authenticated with, or nil when the caller may see every PVZ.
+func AllowedPVZIDs(c *gin.Context) []string {
+	value, exists := c.Get(apiKeyContextKey)
+	if !exists {
+		return nil
+	}
+
+	apiKey, ok := value.(*model.APIKey)
+	if !ok {
+		return nil
+	}
+
+	return apiKey.PVZIDs
+}
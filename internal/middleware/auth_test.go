@@ -1,12 +1,15 @@
 package middleware_test
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"net/http"
 	"net/http/httptest"
 	"testing"
 
 	"github.com/gin-gonic/gin"
+	"github.com/kirillidk/pvz-service/internal/dto"
 	"github.com/kirillidk/pvz-service/internal/middleware"
 	"github.com/kirillidk/pvz-service/internal/model"
 	"github.com/kirillidk/pvz-service/internal/service/auth"
@@ -16,12 +19,51 @@ func init() {
 	gin.SetMode(gin.ReleaseMode)
 }
 
+type MockAPIKeyService struct {
+	CreateAPIKeyFunc  func(ctx context.Context, req dto.APIKeyCreateRequest) (*dto.APIKeyCreateResponse, error)
+	GetAPIKeyListFunc func(ctx context.Context) ([]model.APIKey, error)
+	RevokeAPIKeyFunc  func(ctx context.Context, apiKeyID string) (*model.APIKey, error)
+	AuthenticateFunc  func(ctx context.Context, rawKey string) (*model.APIKey, error)
+}
+
+func (m *MockAPIKeyService) CreateAPIKey(ctx context.Context, req dto.APIKeyCreateRequest) (*dto.APIKeyCreateResponse, error) {
+	return m.CreateAPIKeyFunc(ctx, req)
+}
+
+func (m *MockAPIKeyService) GetAPIKeyList(ctx context.Context) ([]model.APIKey, error) {
+	return m.GetAPIKeyListFunc(ctx)
+}
+
+func (m *MockAPIKeyService) RevokeAPIKey(ctx context.Context, apiKeyID string) (*model.APIKey, error) {
+	return m.RevokeAPIKeyFunc(ctx, apiKeyID)
+}
+
+func (m *MockAPIKeyService) Authenticate(ctx context.Context, rawKey string) (*model.APIKey, error) {
+	return m.AuthenticateFunc(ctx, rawKey)
+}
+
+func newMockAPIKeyService() *MockAPIKeyService {
+	return &MockAPIKeyService{
+		AuthenticateFunc: func(ctx context.Context, rawKey string) (*model.APIKey, error) {
+			if rawKey != "valid-key" {
+				return nil, errors.New("invalid api key")
+			}
+			return &model.APIKey{
+				ID:     "123e4567-e89b-12d3-a456-426614174000",
+				Role:   model.EmployeeRole,
+				PVZIDs: []string{"123e4567-e89b-12d3-a456-426614174001"},
+			}, nil
+		},
+	}
+}
+
 func TestAuthMiddleware(t *testing.T) {
 	jwtSecret := "test-secret"
 
 	tests := []struct {
 		name           string
 		authHeader     string
+		apiKeyHeader   string
 		expectedStatus int
 		expectedBody   any
 	}{
@@ -61,6 +103,20 @@ func TestAuthMiddleware(t *testing.T) {
 			expectedStatus: http.StatusOK,
 			expectedBody:   nil,
 		},
+		{
+			name:           "Valid API Key",
+			apiKeyHeader:   "valid-key",
+			expectedStatus: http.StatusOK,
+			expectedBody:   nil,
+		},
+		{
+			name:           "Invalid API Key",
+			apiKeyHeader:   "invalid-key",
+			expectedStatus: http.StatusUnauthorized,
+			expectedBody: model.Error{
+				Message: "Invalid or expired API key",
+			},
+		},
 	}
 
 	employeeToken, _ := auth.GenerateToken(model.EmployeeRole, jwtSecret)
@@ -72,7 +128,7 @@ func TestAuthMiddleware(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			router := gin.New()
-			router.Use(middleware.AuthMiddleware(jwtSecret))
+			router.Use(middleware.AuthMiddleware(jwtSecret, newMockAPIKeyService()))
 
 			router.GET("/protected", func(c *gin.Context) {
 				c.Status(http.StatusOK)
@@ -82,6 +138,9 @@ func TestAuthMiddleware(t *testing.T) {
 			if tt.authHeader != "" {
 				req.Header.Set("Authorization", tt.authHeader)
 			}
+			if tt.apiKeyHeader != "" {
+				req.Header.Set(middleware.APIKeyHeader, tt.apiKeyHeader)
+			}
 
 			w := httptest.NewRecorder()
 			router.ServeHTTP(w, req)
@@ -201,3 +260,104 @@ func TestRoleMiddleware(t *testing.T) {
 		})
 	}
 }
+
+func TestPVZAccessMiddleware(t *testing.T) {
+	tests := []struct {
+		name           string
+		apiKeyHeader   string
+		pvzID          string
+		expectedStatus int
+	}{
+		{
+			name:           "JWT User Has Access To Any PVZ",
+			apiKeyHeader:   "",
+			pvzID:          "123e4567-e89b-12d3-a456-426614174009",
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "API Key With Allowed PVZ",
+			apiKeyHeader:   "valid-key",
+			pvzID:          "123e4567-e89b-12d3-a456-426614174001",
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "API Key With Foreign PVZ",
+			apiKeyHeader:   "valid-key",
+			pvzID:          "123e4567-e89b-12d3-a456-426614174009",
+			expectedStatus: http.StatusForbidden,
+		},
+	}
+
+	employeeToken, _ := auth.GenerateToken(model.EmployeeRole, "test-secret")
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := gin.New()
+			router.Use(middleware.AuthMiddleware("test-secret", newMockAPIKeyService()))
+
+			router.GET("/pvz/:pvzId", middleware.PVZAccessMiddleware(), func(c *gin.Context) {
+				c.Status(http.StatusOK)
+			})
+
+			req, _ := http.NewRequest(http.MethodGet, "/pvz/"+tt.pvzID, nil)
+			if tt.apiKeyHeader != "" {
+				req.Header.Set(middleware.APIKeyHeader, tt.apiKeyHeader)
+			} else {
+				req.Header.Set("Authorization", "Bearer "+employeeToken)
+			}
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			if w.Code != tt.expectedStatus {
+				t.Errorf("Expected status %d, got %d", tt.expectedStatus, w.Code)
+			}
+		})
+	}
+}
+
+func TestAllPVZAccessMiddleware(t *testing.T) {
+	tests := []struct {
+		name           string
+		apiKeyHeader   string
+		expectedStatus int
+	}{
+		{
+			name:           "JWT User",
+			apiKeyHeader:   "",
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "API Key Restricted To Some PVZs",
+			apiKeyHeader:   "valid-key",
+			expectedStatus: http.StatusForbidden,
+		},
+	}
+
+	employeeToken, _ := auth.GenerateToken(model.EmployeeRole, "test-secret")
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := gin.New()
+			router.Use(middleware.AuthMiddleware("test-secret", newMockAPIKeyService()))
+
+			router.GET("/pvz/stats/daily", middleware.AllPVZAccessMiddleware(), func(c *gin.Context) {
+				c.Status(http.StatusOK)
+			})
+
+			req, _ := http.NewRequest(http.MethodGet, "/pvz/stats/daily", nil)
+			if tt.apiKeyHeader != "" {
+				req.Header.Set(middleware.APIKeyHeader, tt.apiKeyHeader)
+			} else {
+				req.Header.Set("Authorization", "Bearer "+employeeToken)
+			}
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			if w.Code != tt.expectedStatus {
+				t.Errorf("Expected status %d, got %d", tt.expectedStatus, w.Code)
+			}
+		})
+	}
+}
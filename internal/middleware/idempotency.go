@@ -0,0 +1,172 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/kirillidk/pvz-service/internal/config"
+	"github.com/kirillidk/pvz-service/internal/model"
+	"github.com/kirillidk/pvz-service/internal/repository"
+	"github.com/kirillidk/pvz-service/internal/requestctx"
+)
+
+const (
+	IdempotencyKeyHeader     = "Idempotency-Key"
+	IdempotentReplayedHeader = "Idempotent-Replayed"
+
+	maxIdempotencyKeyLength = 255
+)
+
+// IdempotencyMiddleware makes POST requests carrying an Idempotency-Key
+// header safe to retry. The first response is stored per caller and key
+// for cfg.TTL and replayed for later requests with the same key and
+// payload. A retry that arrives while the first request is still running
+// gets 409, a request reusing the key with a different payload gets 422.
+// Server errors are not stored, so the request can be retried, and a
+// request that never completes only holds the key for cfg.Lease. Once the
+// lease runs out the key may be reserved by a retry, and the outcome of the
+// original request is then discarded.
+//
+// It must run after AuthMiddleware, which identifies the caller.
+func IdempotencyMiddleware(store repository.IdempotencyRepositoryInterface, cfg config.IdempotencyConfig) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		key := c.GetHeader(IdempotencyKeyHeader)
+		if c.Request.Method != http.MethodPost || key == "" {
+			c.Next()
+			return
+		}
+
+		if len(key) > maxIdempotencyKeyLength {
+			c.JSON(http.StatusBadRequest, model.Error{Message: "Idempotency-Key must be at most 255 characters"})
+			c.Abort()
+			return
+		}
+
+		body, err := io.ReadAll(http.MaxBytesReader(c.Writer, c.Request.Body, int64(cfg.MaxBodyMB)<<20))
+		if err != nil {
+			var maxBytesErr *http.MaxBytesError
+			if errors.As(err, &maxBytesErr) {
+				c.JSON(http.StatusRequestEntityTooLarge, model.Error{Message: "Request body is too large"})
+			} else {
+				c.JSON(http.StatusBadRequest, model.Error{Message: "Failed to read request body"})
+			}
+			c.Abort()
+			return
+		}
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+		ctx := c.Request.Context()
+		scope := idempotencyScope(requestctx.ActorFromContext(ctx))
+		hash := requestHash(c.Request, body)
+		token := newRandomID()
+		now := time.Now().UTC()
+
+		if token == "" {
+			c.JSON(http.StatusInternalServerError, model.Error{Message: "Failed to check idempotency key"})
+			c.Abort()
+			return
+		}
+
+		existing, reserved, err := store.Reserve(ctx, model.IdempotencyRecord{
+			Scope:       scope,
+			Key:         key,
+			Token:       token,
+			RequestHash: hash,
+			CreatedAt:   now,
+			ExpiresAt:   now.Add(cfg.Lease),
+		})
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, model.Error{Message: "Failed to check idempotency key"})
+			c.Abort()
+			return
+		}
+
+		if !reserved {
+			switch {
+			case existing.RequestHash != hash:
+				c.JSON(http.StatusUnprocessableEntity, model.Error{Message: "Idempotency-Key was already used with a different request"})
+			case !existing.Completed():
+				c.JSON(http.StatusConflict, model.Error{Message: "A request with this Idempotency-Key is already in progress"})
+			default:
+				c.Header(IdempotentReplayedHeader, "true")
+				c.Data(existing.StatusCode, existing.ContentType, existing.ResponseBody)
+			}
+			c.Abort()
+			return
+		}
+
+		// The client may have gone away already, the outcome must be stored
+		// regardless.
+		storeCtx := context.WithoutCancel(ctx)
+
+		defer func() {
+			if r := recover(); r != nil {
+				store.Release(storeCtx, scope, key, token)
+				panic(r)
+			}
+		}()
+
+		recorder := &responseRecorder{ResponseWriter: c.Writer}
+		c.Writer = recorder
+
+		c.Next()
+
+		if c.Writer.Status() < http.StatusInternalServerError {
+			err = store.Complete(
+				storeCtx, scope, key, token, c.Writer.Status(), c.Writer.Header().Get("Content-Type"), recorder.body.Bytes(), now.Add(cfg.TTL),
+			)
+			if err == nil || errors.Is(err, model.ErrIdempotencyKeyReclaimed) {
+				// A reclaimed key belongs to a retry that has run since the
+				// lease of this request ran out; its record is left alone.
+				return
+			}
+			log.Printf("failed to store idempotency key %q: %v", key, err)
+		}
+
+		// A key that cannot be completed is released, so that retries are
+		// not answered with 409 until the lease runs out.
+		if err := store.Release(storeCtx, scope, key, token); err != nil {
+			log.Printf("failed to release idempotency key %q: %v", key, err)
+		}
+	}
+}
+
+// idempotencyScope separates keys of different callers. Dummy-login tokens
+// carry no user ID and share a scope per role.
+func idempotencyScope(actor model.Actor) string {
+	if actor.ID == "" {
+		return string(actor.Type) + ":" + string(actor.Role)
+	}
+	return string(actor.Type) + ":" + actor.ID
+}
+
+func requestHash(r *http.Request, body []byte) string {
+	hash := sha256.New()
+	hash.Write([]byte(r.Method + " " + r.URL.RequestURI() + "\n"))
+	hash.Write(body)
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+// responseRecorder keeps a copy of the response body written by handlers.
+type responseRecorder struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (w *responseRecorder) Write(b []byte) (int, error) {
+	w.body.Write(b)
+	return w.ResponseWriter.Write(b)
+}
+
+func (w *responseRecorder) WriteString(s string) (int, error) {
+	w.body.WriteString(s)
+	return w.ResponseWriter.WriteString(s)
+}
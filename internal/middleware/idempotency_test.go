@@ -0,0 +1,304 @@
+package middleware_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/kirillidk/pvz-service/internal/config"
+	"github.com/kirillidk/pvz-service/internal/middleware"
+	"github.com/kirillidk/pvz-service/internal/model"
+	"github.com/kirillidk/pvz-service/internal/repository"
+	"github.com/kirillidk/pvz-service/internal/requestctx"
+)
+
+// newIdempotentRouter returns a router whose POST /products handler is
+// wrapped by the idempotency middleware. The caller is taken from the
+// X-Test-User header.
+func newIdempotentRouter(ttl time.Duration, handler gin.HandlerFunc) *gin.Engine {
+	return newIdempotentRouterWithStore(repository.NewMemoryIdempotencyRepository(), ttl, handler)
+}
+
+func newIdempotentRouterWithStore(store repository.IdempotencyRepositoryInterface, ttl time.Duration, handler gin.HandlerFunc) *gin.Engine {
+	return newIdempotentRouterWithConfig(store, config.IdempotencyConfig{TTL: ttl, Lease: time.Minute, MaxBodyMB: 1}, handler)
+}
+
+func newIdempotentRouterWithConfig(store repository.IdempotencyRepositoryInterface, cfg config.IdempotencyConfig, handler gin.HandlerFunc) *gin.Engine {
+	router := gin.New()
+	router.Use(func(c *gin.Context) {
+		actor := model.Actor{ID: c.GetHeader("X-Test-User"), Type: model.UserActor, Role: model.EmployeeRole}
+		c.Request = c.Request.WithContext(requestctx.WithActor(c.Request.Context(), actor))
+		c.Next()
+	})
+	router.Use(middleware.IdempotencyMiddleware(store, cfg))
+	router.POST("/products", handler)
+	return router
+}
+
+func sendIdempotent(router *gin.Engine, user, key, body string) *httptest.ResponseRecorder {
+	req, _ := http.NewRequest(http.MethodPost, "/products", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Test-User", user)
+	if key != "" {
+		req.Header.Set(middleware.IdempotencyKeyHeader, key)
+	}
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestIdempotencyMiddleware(t *testing.T) {
+	type request struct {
+		user           string
+		key            string
+		body           string
+		expectedStatus int
+		expectReplay   bool
+	}
+
+	tests := []struct {
+		name          string
+		ttl           time.Duration
+		handlerStatus int
+		requests      []request
+		expectedCalls int32
+	}{
+		{
+			name:          "Replays First Response",
+			ttl:           time.Hour,
+			handlerStatus: http.StatusCreated,
+			requests: []request{
+				{user: "user-1", key: "key-1", body: `{"type":"обувь"}`, expectedStatus: http.StatusCreated},
+				{user: "user-1", key: "key-1", body: `{"type":"обувь"}`, expectedStatus: http.StatusCreated, expectReplay: true},
+			},
+			expectedCalls: 1,
+		},
+		{
+			name:          "Mismatched Payload",
+			ttl:           time.Hour,
+			handlerStatus: http.StatusCreated,
+			requests: []request{
+				{user: "user-1", key: "key-1", body: `{"type":"обувь"}`, expectedStatus: http.StatusCreated},
+				{user: "user-1", key: "key-1", body: `{"type":"одежда"}`, expectedStatus: http.StatusUnprocessableEntity},
+			},
+			expectedCalls: 1,
+		},
+		{
+			name:          "Keys Are Per User",
+			ttl:           time.Hour,
+			handlerStatus: http.StatusCreated,
+			requests: []request{
+				{user: "user-1", key: "key-1", body: `{}`, expectedStatus: http.StatusCreated},
+				{user: "user-2", key: "key-1", body: `{}`, expectedStatus: http.StatusCreated},
+			},
+			expectedCalls: 2,
+		},
+		{
+			name:          "Without Key",
+			ttl:           time.Hour,
+			handlerStatus: http.StatusCreated,
+			requests: []request{
+				{user: "user-1", body: `{}`, expectedStatus: http.StatusCreated},
+				{user: "user-1", body: `{}`, expectedStatus: http.StatusCreated},
+			},
+			expectedCalls: 2,
+		},
+		{
+			name:          "Client Errors Are Replayed",
+			ttl:           time.Hour,
+			handlerStatus: http.StatusBadRequest,
+			requests: []request{
+				{user: "user-1", key: "key-1", body: `{}`, expectedStatus: http.StatusBadRequest},
+				{user: "user-1", key: "key-1", body: `{}`, expectedStatus: http.StatusBadRequest, expectReplay: true},
+			},
+			expectedCalls: 1,
+		},
+		{
+			name:          "Server Errors Are Not Stored",
+			ttl:           time.Hour,
+			handlerStatus: http.StatusInternalServerError,
+			requests: []request{
+				{user: "user-1", key: "key-1", body: `{}`, expectedStatus: http.StatusInternalServerError},
+				{user: "user-1", key: "key-1", body: `{}`, expectedStatus: http.StatusInternalServerError},
+			},
+			expectedCalls: 2,
+		},
+		{
+			name:          "Expired Key",
+			ttl:           time.Nanosecond,
+			handlerStatus: http.StatusCreated,
+			requests: []request{
+				{user: "user-1", key: "key-1", body: `{}`, expectedStatus: http.StatusCreated},
+				{user: "user-1", key: "key-1", body: `{"type":"обувь"}`, expectedStatus: http.StatusCreated},
+			},
+			expectedCalls: 2,
+		},
+		{
+			name:          "Too Long Key",
+			ttl:           time.Hour,
+			handlerStatus: http.StatusCreated,
+			requests: []request{
+				{user: "user-1", key: strings.Repeat("k", 256), body: `{}`, expectedStatus: http.StatusBadRequest},
+			},
+			expectedCalls: 0,
+		},
+		{
+			name:          "Too Large Body",
+			ttl:           time.Hour,
+			handlerStatus: http.StatusCreated,
+			requests: []request{
+				{user: "user-1", key: "key-1", body: strings.Repeat("x", 1<<20+1), expectedStatus: http.StatusRequestEntityTooLarge},
+			},
+			expectedCalls: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var calls atomic.Int32
+			router := newIdempotentRouter(tt.ttl, func(c *gin.Context) {
+				n := calls.Add(1)
+				c.JSON(tt.handlerStatus, gin.H{"call": n})
+			})
+
+			var firstBody string
+			for i, r := range tt.requests {
+				if tt.ttl < time.Millisecond {
+					time.Sleep(time.Millisecond)
+				}
+
+				w := sendIdempotent(router, r.user, r.key, r.body)
+
+				if w.Code != r.expectedStatus {
+					t.Errorf("Request %d: expected status %d, got %d", i, r.expectedStatus, w.Code)
+				}
+
+				replayed := w.Header().Get(middleware.IdempotentReplayedHeader) == "true"
+				if replayed != r.expectReplay {
+					t.Errorf("Request %d: expected replayed %v, got %v", i, r.expectReplay, replayed)
+				}
+
+				if i == 0 {
+					firstBody = w.Body.String()
+				} else if r.expectReplay && w.Body.String() != firstBody {
+					t.Errorf("Request %d: expected replayed body %q, got %q", i, firstBody, w.Body.String())
+				}
+			}
+
+			if calls.Load() != tt.expectedCalls {
+				t.Errorf("Expected %d handler calls, got %d", tt.expectedCalls, calls.Load())
+			}
+		})
+	}
+}
+
+func TestIdempotencyMiddleware_ConcurrentRequest(t *testing.T) {
+	started := make(chan struct{})
+	release := make(chan struct{})
+
+	router := newIdempotentRouter(time.Hour, func(c *gin.Context) {
+		close(started)
+		<-release
+		c.JSON(http.StatusCreated, gin.H{})
+	})
+
+	done := make(chan *httptest.ResponseRecorder)
+	go func() {
+		done <- sendIdempotent(router, "user-1", "key-1", `{}`)
+	}()
+
+	<-started
+	w := sendIdempotent(router, "user-1", "key-1", `{}`)
+	if w.Code != http.StatusConflict {
+		t.Errorf("Expected status %d for concurrent request, got %d", http.StatusConflict, w.Code)
+	}
+
+	close(release)
+	if first := <-done; first.Code != http.StatusCreated {
+		t.Errorf("Expected status %d for first request, got %d", http.StatusCreated, first.Code)
+	}
+
+	w = sendIdempotent(router, "user-1", "key-1", `{}`)
+	if w.Code != http.StatusCreated || w.Header().Get(middleware.IdempotentReplayedHeader) != "true" {
+		t.Errorf("Expected replayed %d after completion, got %d", http.StatusCreated, w.Code)
+	}
+}
+
+func TestIdempotencyMiddleware_ExpiredLease(t *testing.T) {
+	store := repository.NewMemoryIdempotencyRepository()
+	now := time.Now()
+
+	// A request that crashed before completing leaves a pending record
+	// behind until its lease runs out.
+	_, _, err := store.Reserve(context.Background(), model.IdempotencyRecord{
+		Scope:     "user:user-1",
+		Key:       "key-1",
+		CreatedAt: now.Add(-2 * time.Minute),
+		ExpiresAt: now.Add(-time.Minute),
+	})
+	if err != nil {
+		t.Fatalf("Reserve() unexpected error = %v", err)
+	}
+
+	router := newIdempotentRouterWithStore(store, time.Hour, func(c *gin.Context) {
+		c.JSON(http.StatusCreated, gin.H{})
+	})
+
+	w := sendIdempotent(router, "user-1", "key-1", `{}`)
+	if w.Code != http.StatusCreated {
+		t.Errorf("Expected status %d after the lease ran out, got %d", http.StatusCreated, w.Code)
+	}
+}
+
+func TestIdempotencyMiddleware_LeaseExpiresBeforeCompletion(t *testing.T) {
+	const lease = 50 * time.Millisecond
+
+	started := make(chan struct{})
+	release := make(chan struct{})
+	var calls atomic.Int32
+
+	router := newIdempotentRouterWithConfig(
+		repository.NewMemoryIdempotencyRepository(),
+		config.IdempotencyConfig{TTL: time.Hour, Lease: lease, MaxBodyMB: 1},
+		func(c *gin.Context) {
+			call := calls.Add(1)
+			if call == 1 {
+				close(started)
+				<-release
+			}
+			c.JSON(http.StatusCreated, gin.H{"call": call})
+		},
+	)
+
+	done := make(chan *httptest.ResponseRecorder)
+	go func() {
+		done <- sendIdempotent(router, "user-1", "key-1", `{}`)
+	}()
+
+	// The first request outlives its lease, so a retry reserves the key
+	// again and completes before it.
+	<-started
+	time.Sleep(2 * lease)
+
+	retry := sendIdempotent(router, "user-1", "key-1", `{}`)
+	if retry.Code != http.StatusCreated || retry.Body.String() != `{"call":2}` {
+		t.Fatalf("Expected the retry to run after the lease ran out, got %d %s", retry.Code, retry.Body.String())
+	}
+
+	close(release)
+	<-done
+
+	w := sendIdempotent(router, "user-1", "key-1", `{}`)
+	if w.Header().Get(middleware.IdempotentReplayedHeader) != "true" || w.Body.String() != `{"call":2}` {
+		t.Errorf("Expected the response of the retry to be replayed, got %d %s", w.Code, w.Body.String())
+	}
+	if calls.Load() != 2 {
+		t.Errorf("Expected 2 handler calls, got %d", calls.Load())
+	}
+}
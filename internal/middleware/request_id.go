@@ -0,0 +1,41 @@
+package middleware
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+
+	"github.com/gin-gonic/gin"
+	"github.com/kirillidk/pvz-service/internal/requestctx"
+)
+
+const (
+	RequestIDHeader = "X-Request-ID"
+
+	maxRequestIDLength = 64
+)
+
+// RequestIDMiddleware propagates the client supplied X-Request-ID or generates
+// a new one, echoing it in the response and storing it in the request context.
+func RequestIDMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		requestID := c.GetHeader(RequestIDHeader)
+		if requestID == "" || len(requestID) > maxRequestIDLength {
+			requestID = newRandomID()
+		}
+
+		c.Header(RequestIDHeader, requestID)
+		c.Request = c.Request.WithContext(requestctx.WithRequestID(c.Request.Context(), requestID))
+
+		c.Next()
+	}
+}
+
+// newRandomID returns 32 random hex characters, or an empty string if the
+// random source fails.
+func newRandomID() string {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return ""
+	}
+	return hex.EncodeToString(b)
+}
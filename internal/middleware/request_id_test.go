@@ -0,0 +1,66 @@
+package middleware_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/kirillidk/pvz-service/internal/middleware"
+	"github.com/kirillidk/pvz-service/internal/requestctx"
+)
+
+func TestRequestIDMiddleware(t *testing.T) {
+	tests := []struct {
+		name       string
+		requestID  string
+		expectSame bool
+	}{
+		{
+			name:       "Client Request ID",
+			requestID:  "client-request-id",
+			expectSame: true,
+		},
+		{
+			name:       "Generated Request ID",
+			requestID:  "",
+			expectSame: false,
+		},
+		{
+			name:       "Too Long Request ID",
+			requestID:  strings.Repeat("a", 100),
+			expectSame: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := gin.New()
+			router.Use(middleware.RequestIDMiddleware())
+
+			var contextRequestID string
+			router.GET("/", func(c *gin.Context) {
+				contextRequestID = requestctx.RequestIDFromContext(c.Request.Context())
+				c.Status(http.StatusOK)
+			})
+
+			req, _ := http.NewRequest(http.MethodGet, "/", nil)
+			if tt.requestID != "" {
+				req.Header.Set(middleware.RequestIDHeader, tt.requestID)
+			}
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			responseRequestID := w.Header().Get(middleware.RequestIDHeader)
+			if responseRequestID == "" || responseRequestID != contextRequestID {
+				t.Errorf("Expected matching non-empty request IDs, got header %q and context %q", responseRequestID, contextRequestID)
+			}
+
+			if (responseRequestID == tt.requestID) != tt.expectSame {
+				t.Errorf("Unexpected request ID %q for input %q", responseRequestID, tt.requestID)
+			}
+		})
+	}
+}
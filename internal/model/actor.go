@@ -0,0 +1,17 @@
+package model
+
+type ActorType string
+
+const (
+	UserActor   ActorType = "user"
+	APIKeyActor ActorType = "api_key"
+	SystemActor ActorType = "system"
+)
+
+// Actor identifies who performed an operation. ID is empty for
+// dummy-login tokens, which are not bound to a stored user.
+type Actor struct {
+	ID   string    `json:"id,omitempty"`
+	Type ActorType `json:"type"`
+	Role UserRole  `json:"role,omitempty"`
+}
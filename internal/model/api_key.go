@@ -0,0 +1,30 @@
+package model
+
+import "time"
+
+type APIKey struct {
+	ID         string     `json:"id,omitempty" format:"uuid"`
+	Name       string     `json:"name"`
+	Role       UserRole   `json:"role"`
+	PVZIDs     []string   `json:"pvzIds"`
+	ExpiresAt  *time.Time `json:"expiresAt,omitempty" format:"date-time"`
+	LastUsedAt *time.Time `json:"lastUsedAt,omitempty" format:"date-time"`
+	RevokedAt  *time.Time `json:"revokedAt,omitempty" format:"date-time"`
+	CreatedAt  time.Time  `json:"createdAt" format:"date-time"`
+}
+
+// AllowsPVZ reports whether the key may operate on the given PVZ.
+// An empty allow-list grants access to every PVZ.
+func (k *APIKey) AllowsPVZ(pvzID string) bool {
+	if len(k.PVZIDs) == 0 {
+		return true
+	}
+
+	for _, id := range k.PVZIDs {
+		if id == pvzID {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,45 @@
+package model
+
+import (
+	"encoding/json"
+	"time"
+)
+
+type AuditAction string
+
+const (
+	AuditActionCreate       AuditAction = "create"
+	AuditActionUpdate       AuditAction = "update"
+	AuditActionStatusChange AuditAction = "status_change"
+	AuditActionClose        AuditAction = "close"
+	AuditActionIssue        AuditAction = "issue"
+	AuditActionDelete       AuditAction = "delete"
+	AuditActionAddProducts  AuditAction = "add_products"
+)
+
+type AuditEntityType string
+
+const (
+	AuditEntityPVZ              AuditEntityType = "pvz"
+	AuditEntityReception        AuditEntityType = "reception"
+	AuditEntityProduct          AuditEntityType = "product"
+	AuditEntityOrder            AuditEntityType = "order"
+	AuditEntityStorageCell      AuditEntityType = "storage_cell"
+	AuditEntityTransfer         AuditEntityType = "transfer"
+	AuditEntityAttachment       AuditEntityType = "attachment"
+	AuditEntityExpectedDelivery AuditEntityType = "expected_delivery"
+)
+
+type AuditEntry struct {
+	ID         int64           `json:"id"`
+	CreatedAt  time.Time       `json:"createdAt" format:"date-time"`
+	Actor      Actor           `json:"actor"`
+	Action     AuditAction     `json:"action"`
+	EntityType AuditEntityType `json:"entityType"`
+	EntityID   string          `json:"entityId"`
+	Before     json.RawMessage `json:"before,omitempty"`
+	After      json.RawMessage `json:"after,omitempty"`
+	RequestID  string          `json:"requestId,omitempty"`
+	PrevHash   string          `json:"prevHash"`
+	Hash       string          `json:"hash"`
+}
@@ -0,0 +1,12 @@
+package model
+
+import "time"
+
+type City struct {
+	ID        string    `json:"id" format:"uuid"`
+	Name      string    `json:"name"`
+	Region    string    `json:"region"`
+	Timezone  string    `json:"timezone"`
+	Active    bool      `json:"active"`
+	CreatedAt time.Time `json:"createdAt" format:"date-time"`
+}
@@ -1,5 +1,50 @@
 package model
 
+import "errors"
+
+var (
+	ErrAPIKeyNotFound           = errors.New("api key not found")
+	ErrPVZNotFound              = errors.New("pvz not found")
+	ErrPVZNotActive             = errors.New("pvz is not active")
+	ErrReceptionNotFound        = errors.New("reception not found")
+	ErrProductNotFound          = errors.New("product not found")
+	ErrProductInOrder           = errors.New("product belongs to an order")
+	ErrProductInTransfer        = errors.New("product belongs to a transfer")
+	ErrProductNotDeletable      = errors.New("product has already been processed and cannot be deleted")
+	ErrTransferNotFound         = errors.New("transfer not found")
+	ErrExpectedDeliveryNotFound = errors.New("expected delivery not found")
+	ErrAttachmentNotFound       = errors.New("attachment not found")
+	ErrUnsupportedAttachment    = errors.New("only JPEG, PNG and WebP images can be attached")
+	ErrAttachmentTooLarge       = errors.New("attachment is too large")
+	ErrOrderNotFound            = errors.New("order not found")
+	ErrOrderAlreadyIssued       = errors.New("order has already been issued")
+	ErrInvalidPickupCode        = errors.New("invalid pickup code")
+	ErrTwoFactorLocked          = errors.New("too many wrong two-factor codes, try again later")
+	ErrPickupCodeLocked         = errors.New("too many wrong pickup codes, a new code must be sent")
+	ErrPickupCodeResendLimit    = errors.New("too many pickup codes have been sent for this order")
+	ErrNoOpenReception          = errors.New("no open reception found for this PVZ")
+	ErrReceptionNotClosed       = errors.New("reception is not closed")
+	ErrReceptionReopenExpired   = errors.New("reception was closed too long ago to be reopened")
+	ErrNewerReceptionExists     = errors.New("a newer reception exists for this PVZ")
+	ErrReceptionProcessed       = errors.New("products of the reception have already been processed")
+	ErrCityNotFound             = errors.New("city not found")
+	ErrCityAlreadyExists        = errors.New("city with this name already exists")
+	ErrCityInUse                = errors.New("city is used by existing PVZ")
+	ErrProductTypeNotFound      = errors.New("product type not found")
+	ErrProductTypeAlreadyExists = errors.New("product type with this code already exists")
+	ErrProductTypeInUse         = errors.New("product type is used by existing products")
+	ErrProductTypeNotAvailable  = errors.New("product type is not available")
+	ErrSerialNumberRequired     = errors.New("serial number is required for this product type")
+	ErrInvalidDateRange         = errors.New("invalid date range")
+	ErrInvalidBarcode           = errors.New("invalid barcode")
+	ErrDuplicateBarcode         = errors.New("product with this barcode has already been scanned in this reception")
+	ErrStorageCellNotFound      = errors.New("storage cell not found")
+	ErrStorageCellAlreadyExists = errors.New("storage cell with this code already exists in this PVZ")
+	ErrStorageCellFull          = errors.New("storage cell is full")
+	ErrStorageCellTypeMismatch  = errors.New("storage cell does not take products of this type")
+	ErrIdempotencyKeyReclaimed  = errors.New("idempotency key was reclaimed by another request")
+)
+
 type Error struct {
 	Message string `json:"message" binding:"required"`
 }
@@ -0,0 +1,45 @@
+package model
+
+import "time"
+
+type ExpectedDeliveryStatus string
+
+const (
+	ExpectedDeliveryStatusPending    ExpectedDeliveryStatus = "pending"
+	ExpectedDeliveryStatusReceiving  ExpectedDeliveryStatus = "receiving"
+	ExpectedDeliveryStatusReconciled ExpectedDeliveryStatus = "reconciled"
+)
+
+// ExpectedDelivery is an advance shipping notice: the parcels a PVZ is
+// about to receive. It is linked to the reception they arrive with and
+// reconciled against it when the reception is closed.
+type ExpectedDelivery struct {
+	ID           string                 `json:"id" format:"uuid"`
+	PVZID        string                 `json:"pvzId" format:"uuid"`
+	Status       ExpectedDeliveryStatus `json:"status"`
+	ReceptionID  string                 `json:"receptionId,omitempty" format:"uuid"`
+	Items        []ExpectedDeliveryItem `json:"items"`
+	CreatedAt    time.Time              `json:"createdAt" format:"date-time"`
+	ReconciledAt *time.Time             `json:"reconciledAt,omitempty" format:"date-time"`
+}
+
+// ExpectedDeliveryItem is a parcel of an expected delivery. ProductID is
+// set once a product with the same barcode is scanned into the reception.
+type ExpectedDeliveryItem struct {
+	Barcode   string `json:"barcode"`
+	Type      string `json:"type"`
+	ProductID string `json:"productId,omitempty" format:"uuid"`
+}
+
+// DeliveryReconciliation compares an expected delivery with its reception:
+// Missing are the items that were not scanned, Unexpected the products
+// scanned into the reception that are not in the delivery. Products that
+// arrived by a transfer keep their original reception and are not part of
+// the comparison.
+type DeliveryReconciliation struct {
+	DeliveryID    string                 `json:"deliveryId" format:"uuid"`
+	ExpectedCount int                    `json:"expectedCount"`
+	MatchedCount  int                    `json:"matchedCount"`
+	Missing       []ExpectedDeliveryItem `json:"missing"`
+	Unexpected    []Product              `json:"unexpected"`
+}
@@ -0,0 +1,20 @@
+package model
+
+import "time"
+
+// ReceptionExportRow is a product together with its reception and PVZ.
+type ReceptionExportRow struct {
+	PVZID             string
+	City              string
+	Address           string
+	PVZStatus         PVZStatus
+	ReceptionID       string
+	ReceptionDateTime time.Time
+	ReceptionStatus   string
+	ReceptionClosedAt *time.Time
+	ProductID         string
+	ProductDateTime   time.Time
+	ProductType       string
+	SerialNumber      string
+	Barcode           string
+}
@@ -0,0 +1,23 @@
+package model
+
+import "time"
+
+// IdempotencyRecord is the stored outcome of a request made with an
+// Idempotency-Key. StatusCode is zero while the first request is still
+// being processed. Token identifies the reservation, so that a request whose
+// lease ran out cannot complete or release the reservation of a retry.
+type IdempotencyRecord struct {
+	Scope        string
+	Key          string
+	Token        string
+	RequestHash  string
+	StatusCode   int
+	ContentType  string
+	ResponseBody []byte
+	CreatedAt    time.Time
+	ExpiresAt    time.Time
+}
+
+func (r *IdempotencyRecord) Completed() bool {
+	return r.StatusCode != 0
+}
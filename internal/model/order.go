@@ -0,0 +1,19 @@
+package model
+
+import "time"
+
+// Order groups products handed to a customer together against a single
+// pickup code. Only the hash of the code is stored. CodeRegenerations
+// counts the codes sent after the first one.
+type Order struct {
+	ID                string     `json:"id" format:"uuid"`
+	PVZID             string     `json:"pvzId" format:"uuid"`
+	Recipient         string     `json:"recipient"`
+	ProductIDs        []string   `json:"productIds"`
+	FailedAttempts    int        `json:"failedAttempts"`
+	CodeRegenerations int        `json:"codeRegenerations"`
+	CreatedAt         time.Time  `json:"createdAt" format:"date-time"`
+	IssuedAt          *time.Time `json:"issuedAt,omitempty" format:"date-time"`
+
+	PickupCodeHash string `json:"-"`
+}
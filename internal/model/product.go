@@ -1,10 +1,98 @@
 package model
 
-import "time"
+import (
+	"fmt"
+	"time"
+)
+
+type ProductStatus string
+
+const (
+	ProductStatusReceived         ProductStatus = "received"
+	ProductStatusStored           ProductStatus = "stored"
+	ProductStatusIssued           ProductStatus = "issued"
+	ProductStatusReturnedToSender ProductStatus = "returned_to_sender"
+	ProductStatusInTransit        ProductStatus = "in_transit"
+)
+
+// CanTransitionTo reports whether a product may move from s to next. A
+// stored product leaves the PVZ either with the customer or back to the
+// sender, both are final, or travels to another PVZ, where it is received
+// again.
+func (s ProductStatus) CanTransitionTo(next ProductStatus) bool {
+	switch s {
+	case ProductStatusReceived:
+		return next == ProductStatusStored
+	case ProductStatusStored:
+		return next == ProductStatusIssued || next == ProductStatusReturnedToSender || next == ProductStatusInTransit
+	case ProductStatusInTransit:
+		return next == ProductStatusReceived
+	default:
+		return false
+	}
+}
+
+// ProductCondition is the state a product arrived in. Damaged and opened
+// products are reported as discrepancies of their reception.
+type ProductCondition string
+
+const (
+	ProductConditionOK      ProductCondition = "ok"
+	ProductConditionDamaged ProductCondition = "damaged"
+	ProductConditionOpened  ProductCondition = "opened"
+)
 
 type Product struct {
-	ID          string    `json:"id,omitempty" format:"uuid"`
-	DateTime    time.Time `json:"dateTime" binding:"required" format:"date-time"`
-	Type        string    `json:"type" binding:"required,oneof=электроника одежда обувь"`
-	ReceptionID string    `json:"receptionId" binding:"required,uuid"`
+	ID           string        `json:"id,omitempty" format:"uuid"`
+	DateTime     time.Time     `json:"dateTime" binding:"required" format:"date-time"`
+	Type         string        `json:"type" binding:"required"`
+	ReceptionID  string        `json:"receptionId" binding:"required,uuid"`
+	SerialNumber string        `json:"serialNumber,omitempty"`
+	Barcode      string        `json:"barcode,omitempty"`
+	Status       ProductStatus `json:"status,omitempty"`
+	CellID       string        `json:"cellId,omitempty" format:"uuid"`
+
+	Condition     ProductCondition `json:"condition,omitempty"`
+	ConditionNote string           `json:"conditionNote,omitempty"`
+}
+
+// ProductLocation is a product together with the PVZ that received it.
+type ProductLocation struct {
+	Product
+	PVZID           string `json:"pvzId" format:"uuid"`
+	City            string `json:"city"`
+	ReceptionStatus string `json:"receptionStatus"`
+	OrderID         string `json:"orderId,omitempty" format:"uuid"`
+	TransferID      string `json:"transferId,omitempty" format:"uuid"`
+}
+
+// CheckPVZ reports products received at other PVZs as not found, so that a
+// PVZ cannot operate on, or learn about, products of another one.
+func (l ProductLocation) CheckPVZ(pvzID string) error {
+	if l.PVZID != pvzID {
+		return fmt.Errorf("failed to get product: %w", ErrProductNotFound)
+	}
+	return nil
+}
+
+// ProductStatusChange records a transition of a product between statuses.
+type ProductStatusChange struct {
+	ID         int64         `json:"id"`
+	ProductID  string        `json:"productId" format:"uuid"`
+	FromStatus ProductStatus `json:"fromStatus"`
+	ToStatus   ProductStatus `json:"toStatus"`
+	ChangedAt  time.Time     `json:"changedAt" format:"date-time"`
+	Actor      Actor         `json:"actor"`
+}
+
+// ProductAttachment is a file, such as a photo of a damaged parcel, kept
+// with a product. StorageKey locates the file in the attachment storage.
+type ProductAttachment struct {
+	ID          string    `json:"id" format:"uuid"`
+	ProductID   string    `json:"productId" format:"uuid"`
+	FileName    string    `json:"fileName"`
+	ContentType string    `json:"contentType"`
+	Size        int64     `json:"size"`
+	StorageKey  string    `json:"-"`
+	CreatedAt   time.Time `json:"createdAt" format:"date-time"`
 }
@@ -0,0 +1,36 @@
+package model_test
+
+import (
+	"testing"
+
+	"github.com/kirillidk/pvz-service/internal/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProductStatus_CanTransitionTo(t *testing.T) {
+	tests := []struct {
+		from     model.ProductStatus
+		to       model.ProductStatus
+		expected bool
+	}{
+		{from: model.ProductStatusReceived, to: model.ProductStatusStored, expected: true},
+		{from: model.ProductStatusStored, to: model.ProductStatusIssued, expected: true},
+		{from: model.ProductStatusStored, to: model.ProductStatusReturnedToSender, expected: true},
+		{from: model.ProductStatusStored, to: model.ProductStatusInTransit, expected: true},
+		{from: model.ProductStatusInTransit, to: model.ProductStatusReceived, expected: true},
+		{from: model.ProductStatusReceived, to: model.ProductStatusIssued, expected: false},
+		{from: model.ProductStatusReceived, to: model.ProductStatusReturnedToSender, expected: false},
+		{from: model.ProductStatusStored, to: model.ProductStatusReceived, expected: false},
+		{from: model.ProductStatusIssued, to: model.ProductStatusStored, expected: false},
+		{from: model.ProductStatusIssued, to: model.ProductStatusReturnedToSender, expected: false},
+		{from: model.ProductStatusReturnedToSender, to: model.ProductStatusStored, expected: false},
+		{from: model.ProductStatusReceived, to: model.ProductStatusInTransit, expected: false},
+		{from: model.ProductStatusInTransit, to: model.ProductStatusIssued, expected: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.from)+" to "+string(tt.to), func(t *testing.T) {
+			assert.Equal(t, tt.expected, tt.from.CanTransitionTo(tt.to))
+		})
+	}
+}
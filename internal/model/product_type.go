@@ -0,0 +1,14 @@
+package model
+
+import "time"
+
+type ProductType struct {
+	Code                 string    `json:"code"`
+	Name                 string    `json:"name"`
+	Active               bool      `json:"active"`
+	RequiresSerialNumber bool      `json:"requiresSerialNumber"`
+	CreatedAt            time.Time `json:"createdAt" format:"date-time"`
+	// StorageDays overrides the default storage period for products of
+	// this type.
+	StorageDays *int `json:"storageDays,omitempty"`
+}
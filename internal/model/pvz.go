@@ -2,14 +2,44 @@ package model
 
 import "time"
 
-var ValidCities = map[string]struct{}{
-	"Москва":          {},
-	"Санкт-Петербург": {},
-	"Казань":          {},
+type PVZStatus string
+
+const (
+	PVZStatusActive    PVZStatus = "active"
+	PVZStatusSuspended PVZStatus = "suspended"
+	PVZStatusArchived  PVZStatus = "archived"
+)
+
+// CanTransitionTo reports whether a PVZ may move from s to next. Suspended
+// PVZs can be reopened, while archiving is final.
+func (s PVZStatus) CanTransitionTo(next PVZStatus) bool {
+	switch s {
+	case PVZStatusActive:
+		return next == PVZStatusSuspended || next == PVZStatusArchived
+	case PVZStatusSuspended:
+		return next == PVZStatusActive || next == PVZStatusArchived
+	default:
+		return false
+	}
 }
 
 type PVZ struct {
-	ID               string    `json:"id,omitempty" format:"uuid"`
-	RegistrationDate time.Time `json:"registrationDate" format:"date-time"`
-	City             string    `json:"city" binding:"required,oneof=Москва Санкт-Петербург Казань"`
+	ID               string        `json:"id,omitempty" format:"uuid"`
+	RegistrationDate time.Time     `json:"registrationDate" format:"date-time"`
+	City             string        `json:"city" binding:"required"`
+	Address          string        `json:"address,omitempty"`
+	Latitude         *float64      `json:"latitude,omitempty"`
+	Longitude        *float64      `json:"longitude,omitempty"`
+	Phone            string        `json:"phone,omitempty"`
+	WorkingHours     *WorkingHours `json:"workingHours,omitempty"`
+	Status           PVZStatus     `json:"status,omitempty"`
+}
+
+// NearbyPVZ is a PVZ found by geo search together with its distance in
+// meters from the search point. Timezone is the timezone of the PVZ city and
+// is empty when the city is missing from the catalog.
+type NearbyPVZ struct {
+	PVZ      PVZ     `json:"pvz"`
+	Distance float64 `json:"distance"`
+	Timezone string  `json:"-"`
 }
@@ -0,0 +1,30 @@
+package model_test
+
+import (
+	"testing"
+
+	"github.com/kirillidk/pvz-service/internal/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPVZStatus_CanTransitionTo(t *testing.T) {
+	tests := []struct {
+		from     model.PVZStatus
+		to       model.PVZStatus
+		expected bool
+	}{
+		{from: model.PVZStatusActive, to: model.PVZStatusSuspended, expected: true},
+		{from: model.PVZStatusActive, to: model.PVZStatusArchived, expected: true},
+		{from: model.PVZStatusSuspended, to: model.PVZStatusActive, expected: true},
+		{from: model.PVZStatusSuspended, to: model.PVZStatusArchived, expected: true},
+		{from: model.PVZStatusActive, to: model.PVZStatusActive, expected: false},
+		{from: model.PVZStatusArchived, to: model.PVZStatusActive, expected: false},
+		{from: model.PVZStatusArchived, to: model.PVZStatusSuspended, expected: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.from)+" to "+string(tt.to), func(t *testing.T) {
+			assert.Equal(t, tt.expected, tt.from.CanTransitionTo(tt.to))
+		})
+	}
+}
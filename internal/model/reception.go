@@ -7,4 +7,44 @@ type Reception struct {
 	DateTime time.Time `json:"dateTime" binding:"required" format:"date-time"`
 	PVZID    string    `json:"pvzId" binding:"required,uuid"`
 	Status   string    `json:"status" binding:"required,oneof=in_progress close"`
+
+	ClosedAt       *time.Time `json:"closedAt,omitempty" format:"date-time"`
+	FirstProductAt *time.Time `json:"firstProductAt,omitempty" format:"date-time"`
+	LastProductAt  *time.Time `json:"lastProductAt,omitempty" format:"date-time"`
+
+	ExpectedCount *int `json:"expectedCount,omitempty"`
+}
+
+// ReceptionStatusChange records a transition of a reception between
+// statuses. FromStatus is empty for the opening of the reception; Reason is
+// set when a moderator reopens it.
+type ReceptionStatusChange struct {
+	ID          int64     `json:"id"`
+	ReceptionID string    `json:"receptionId" format:"uuid"`
+	FromStatus  string    `json:"fromStatus,omitempty"`
+	ToStatus    string    `json:"toStatus"`
+	Reason      string    `json:"reason,omitempty"`
+	ChangedAt   time.Time `json:"changedAt" format:"date-time"`
+	Actor       Actor     `json:"actor"`
+}
+
+// ReceptionDiscrepancy compares a reception with the courier's manifest.
+// Difference is the number of products received minus the number expected
+// and is only set when the expected count is known.
+type ReceptionDiscrepancy struct {
+	ReceptionID   string    `json:"receptionId" format:"uuid"`
+	PVZID         string    `json:"pvzId" format:"uuid"`
+	DateTime      time.Time `json:"dateTime" format:"date-time"`
+	Status        string    `json:"status"`
+	ExpectedCount *int      `json:"expectedCount,omitempty"`
+	ReceivedCount int       `json:"receivedCount"`
+	Difference    *int      `json:"difference,omitempty"`
+	DamagedCount  int       `json:"damagedCount"`
+	OpenedCount   int       `json:"openedCount"`
+}
+
+// HasDiscrepancy reports whether the count differs from the manifest or any
+// product arrived damaged or opened.
+func (d ReceptionDiscrepancy) HasDiscrepancy() bool {
+	return (d.Difference != nil && *d.Difference != 0) || d.DamagedCount > 0 || d.OpenedCount > 0
 }
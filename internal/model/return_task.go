@@ -0,0 +1,13 @@
+package model
+
+import "time"
+
+// ReturnTask asks the staff of a PVZ to send an unclaimed product back. It
+// is created once the storage period of the product has expired.
+type ReturnTask struct {
+	ID              string    `json:"id" format:"uuid"`
+	PVZID           string    `json:"pvzId" format:"uuid"`
+	Product         Product   `json:"product"`
+	StorageDeadline time.Time `json:"storageDeadline" format:"date-time"`
+	CreatedAt       time.Time `json:"createdAt" format:"date-time"`
+}
@@ -0,0 +1,28 @@
+package model
+
+type DailyReceptionStats struct {
+	Date           string           `json:"date" example:"2025-04-15"`
+	PVZID          string           `json:"pvzId" format:"uuid"`
+	ReceptionCount int64            `json:"receptionCount"`
+	ProductCount   int64            `json:"productCount"`
+	ProductsByType map[string]int64 `json:"productsByType"`
+	// AverageDurationSeconds covers closed receptions and is nil when
+	// there are none.
+	AverageDurationSeconds *float64 `json:"averageDurationSeconds"`
+	// IssuedCount is only set when requested. Products are counted on the
+	// day they were issued, not the day of their reception.
+	IssuedCount *int64 `json:"issuedCount,omitempty"`
+}
+
+// ReceptionThroughputStats describes closed receptions of a PVZ, or of all
+// PVZs of a city when PVZID is empty.
+type ReceptionThroughputStats struct {
+	PVZID                 string  `json:"pvzId,omitempty" format:"uuid"`
+	City                  string  `json:"city"`
+	ReceptionCount        int64   `json:"receptionCount"`
+	ProductCount          int64   `json:"productCount"`
+	MedianDurationSeconds float64 `json:"medianDurationSeconds"`
+	P95DurationSeconds    float64 `json:"p95DurationSeconds"`
+	// ProductsPerMinute is nil when the receptions took no time at all.
+	ProductsPerMinute *float64 `json:"productsPerMinute"`
+}
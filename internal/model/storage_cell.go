@@ -0,0 +1,22 @@
+package model
+
+import "time"
+
+// StorageCell is a shelf of a PVZ. A cell with a product type only takes
+// products of that type.
+type StorageCell struct {
+	ID          string    `json:"id" format:"uuid"`
+	PVZID       string    `json:"pvzId" format:"uuid"`
+	Code        string    `json:"code"`
+	Capacity    int       `json:"capacity"`
+	ProductType string    `json:"productType,omitempty"`
+	CreatedAt   time.Time `json:"createdAt" format:"date-time"`
+}
+
+// StorageCellOccupancy counts the products still kept in a cell; issued and
+// returned products no longer take up space.
+type StorageCellOccupancy struct {
+	StorageCell
+	Occupied int `json:"occupied"`
+	Free     int `json:"free"`
+}
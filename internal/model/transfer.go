@@ -0,0 +1,25 @@
+package model
+
+import "time"
+
+type TransferStatus string
+
+const (
+	TransferStatusCreated   TransferStatus = "created"
+	TransferStatusInTransit TransferStatus = "in_transit"
+	TransferStatusReceived  TransferStatus = "received"
+)
+
+// Transfer forwards stored products from one PVZ to another. Once received,
+// the products belong to ReceptionID, a reception of the destination PVZ.
+type Transfer struct {
+	ID               string         `json:"id" format:"uuid"`
+	SourcePVZID      string         `json:"sourcePvzId" format:"uuid"`
+	DestinationPVZID string         `json:"destinationPvzId" format:"uuid"`
+	Status           TransferStatus `json:"status"`
+	ProductIDs       []string       `json:"productIds"`
+	ReceptionID      string         `json:"receptionId,omitempty" format:"uuid"`
+	CreatedAt        time.Time      `json:"createdAt" format:"date-time"`
+	ShippedAt        *time.Time     `json:"shippedAt,omitempty" format:"date-time"`
+	ReceivedAt       *time.Time     `json:"receivedAt,omitempty" format:"date-time"`
+}
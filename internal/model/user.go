@@ -1,5 +1,7 @@
 package model
 
+import "time"
+
 type UserRole string
 
 const (
@@ -8,7 +10,12 @@ const (
 )
 
 type User struct {
-	ID    string   `json:"id,omitempty" format:"uuid"`
-	Email string   `json:"email" binding:"required,email"`
-	Role  UserRole `json:"role" binding:"required,oneof=employee moderator"`
+	ID               string   `json:"id,omitempty" format:"uuid"`
+	Email            string   `json:"email" binding:"required,email"`
+	Role             UserRole `json:"role" binding:"required,oneof=employee moderator"`
+	TwoFactorEnabled bool     `json:"twoFactorEnabled"`
+
+	// TwoFactorLockedUntil is set when too many wrong second-factor codes
+	// were entered in a row.
+	TwoFactorLockedUntil *time.Time `json:"-"`
 }
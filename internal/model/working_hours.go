@@ -0,0 +1,146 @@
+package model
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+const (
+	hoursLayout = "15:04"
+	dateLayout  = "2006-01-02"
+
+	// endOfDay may be used as a closing time for PVZs that work until
+	// midnight or around the clock.
+	endOfDay = "24:00"
+)
+
+var weekdays = map[string]time.Weekday{
+	"monday":    time.Monday,
+	"tuesday":   time.Tuesday,
+	"wednesday": time.Wednesday,
+	"thursday":  time.Thursday,
+	"friday":    time.Friday,
+	"saturday":  time.Saturday,
+	"sunday":    time.Sunday,
+}
+
+// WorkingHours is the weekly schedule of a PVZ. Days missing from Weekly
+// are days off. Exceptions override the weekly schedule for specific dates,
+// e.g. holidays.
+type WorkingHours struct {
+	Weekly     []DailyHours     `json:"weekly"`
+	Exceptions []HoursException `json:"exceptions,omitempty"`
+}
+
+type DailyHours struct {
+	Day   string `json:"day" example:"monday"`
+	Open  string `json:"open" example:"09:00"`
+	Close string `json:"close" example:"21:00"`
+}
+
+type HoursException struct {
+	Date   string `json:"date" example:"2025-01-01"`
+	Closed bool   `json:"closed"`
+	Open   string `json:"open,omitempty"`
+	Close  string `json:"close,omitempty"`
+}
+
+func (wh WorkingHours) Validate() error {
+	seen := make(map[string]struct{}, len(wh.Weekly))
+	for _, daily := range wh.Weekly {
+		day := strings.ToLower(daily.Day)
+		if _, ok := weekdays[day]; !ok {
+			return fmt.Errorf("unknown day %q", daily.Day)
+		}
+		if _, ok := seen[day]; ok {
+			return fmt.Errorf("duplicate day %q", daily.Day)
+		}
+		seen[day] = struct{}{}
+
+		if err := validateInterval(daily.Open, daily.Close); err != nil {
+			return fmt.Errorf("invalid hours for %s: %w", daily.Day, err)
+		}
+	}
+
+	for _, exception := range wh.Exceptions {
+		if _, err := time.Parse(dateLayout, exception.Date); err != nil {
+			return fmt.Errorf("invalid exception date %q", exception.Date)
+		}
+
+		if exception.Closed {
+			continue
+		}
+
+		if err := validateInterval(exception.Open, exception.Close); err != nil {
+			return fmt.Errorf("invalid hours for %s: %w", exception.Date, err)
+		}
+	}
+
+	return nil
+}
+
+// IsOpenAt reports whether the PVZ is open at t. The schedule is interpreted
+// in the location of t.
+func (wh WorkingHours) IsOpenAt(t time.Time) bool {
+	date := t.Format(dateLayout)
+	clock := t.Format(hoursLayout)
+
+	for _, exception := range wh.Exceptions {
+		if exception.Date == date {
+			return !exception.Closed && exception.Open <= clock && clock < exception.Close
+		}
+	}
+
+	for _, daily := range wh.Weekly {
+		if weekdays[strings.ToLower(daily.Day)] == t.Weekday() {
+			return daily.Open <= clock && clock < daily.Close
+		}
+	}
+
+	return false
+}
+
+func (wh WorkingHours) Value() (driver.Value, error) {
+	b, err := json.Marshal(wh)
+	if err != nil {
+		return nil, err
+	}
+	return string(b), nil
+}
+
+func (wh *WorkingHours) Scan(src any) error {
+	switch v := src.(type) {
+	case []byte:
+		return json.Unmarshal(v, wh)
+	case string:
+		return json.Unmarshal([]byte(v), wh)
+	default:
+		return fmt.Errorf("unsupported working hours type %T", src)
+	}
+}
+
+func validateInterval(openAt, closeAt string) error {
+	openTime, err := time.Parse(hoursLayout, openAt)
+	if err != nil {
+		return fmt.Errorf("invalid open time %q", openAt)
+	}
+
+	if closeAt == endOfDay {
+		return nil
+	}
+
+	closeTime, err := time.Parse(hoursLayout, closeAt)
+	if err != nil {
+		return fmt.Errorf("invalid close time %q", closeAt)
+	}
+
+	if !openTime.Before(closeTime) {
+		return errors.New("open time must be before close time")
+	}
+
+	return nil
+}
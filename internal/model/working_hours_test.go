@@ -0,0 +1,145 @@
+package model_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/kirillidk/pvz-service/internal/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWorkingHours_Validate(t *testing.T) {
+	tests := []struct {
+		name          string
+		workingHours  model.WorkingHours
+		expectedError string
+	}{
+		{
+			name: "Valid",
+			workingHours: model.WorkingHours{
+				Weekly: []model.DailyHours{
+					{Day: "monday", Open: "09:00", Close: "21:00"},
+					{Day: "Saturday", Open: "10:00", Close: "18:00"},
+					{Day: "sunday", Open: "00:00", Close: "24:00"},
+				},
+				Exceptions: []model.HoursException{
+					{Date: "2025-01-01", Closed: true},
+					{Date: "2025-01-02", Open: "12:00", Close: "16:00"},
+				},
+			},
+		},
+		{
+			name: "Unknown Day",
+			workingHours: model.WorkingHours{
+				Weekly: []model.DailyHours{{Day: "someday", Open: "09:00", Close: "21:00"}},
+			},
+			expectedError: `unknown day "someday"`,
+		},
+		{
+			name: "Duplicate Day",
+			workingHours: model.WorkingHours{
+				Weekly: []model.DailyHours{
+					{Day: "monday", Open: "09:00", Close: "21:00"},
+					{Day: "Monday", Open: "10:00", Close: "20:00"},
+				},
+			},
+			expectedError: `duplicate day "Monday"`,
+		},
+		{
+			name: "Open After Close",
+			workingHours: model.WorkingHours{
+				Weekly: []model.DailyHours{{Day: "monday", Open: "21:00", Close: "09:00"}},
+			},
+			expectedError: "open time must be before close time",
+		},
+		{
+			name: "Malformed Time",
+			workingHours: model.WorkingHours{
+				Weekly: []model.DailyHours{{Day: "monday", Open: "9am", Close: "21:00"}},
+			},
+			expectedError: `invalid open time "9am"`,
+		},
+		{
+			name: "Malformed Exception Date",
+			workingHours: model.WorkingHours{
+				Exceptions: []model.HoursException{{Date: "01.01.2025", Closed: true}},
+			},
+			expectedError: `invalid exception date "01.01.2025"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.workingHours.Validate()
+
+			if tt.expectedError != "" {
+				assert.Error(t, err)
+				assert.Contains(t, err.Error(), tt.expectedError)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestWorkingHours_IsOpenAt(t *testing.T) {
+	workingHours := model.WorkingHours{
+		Weekly: []model.DailyHours{
+			{Day: "monday", Open: "09:00", Close: "21:00"},
+			{Day: "tuesday", Open: "09:00", Close: "21:00"},
+			{Day: "sunday", Open: "00:00", Close: "24:00"},
+		},
+		Exceptions: []model.HoursException{
+			{Date: "2025-04-15", Open: "12:00", Close: "16:00"},
+			{Date: "2025-04-21", Closed: true},
+		},
+	}
+
+	tests := []struct {
+		name     string
+		at       time.Time
+		expected bool
+	}{
+		{
+			name:     "Within Weekly Hours",
+			at:       time.Date(2025, 4, 14, 10, 0, 0, 0, time.UTC),
+			expected: true,
+		},
+		{
+			name:     "At Closing Time",
+			at:       time.Date(2025, 4, 14, 21, 0, 0, 0, time.UTC),
+			expected: false,
+		},
+		{
+			name:     "Around The Clock",
+			at:       time.Date(2025, 4, 13, 23, 59, 30, 0, time.UTC),
+			expected: true,
+		},
+		{
+			name:     "Day Off",
+			at:       time.Date(2025, 4, 16, 10, 0, 0, 0, time.UTC),
+			expected: false,
+		},
+		{
+			name:     "Shortened Day Exception",
+			at:       time.Date(2025, 4, 15, 10, 0, 0, 0, time.UTC),
+			expected: false,
+		},
+		{
+			name:     "Within Exception Hours",
+			at:       time.Date(2025, 4, 15, 13, 0, 0, 0, time.UTC),
+			expected: true,
+		},
+		{
+			name:     "Closed Exception",
+			at:       time.Date(2025, 4, 21, 10, 0, 0, 0, time.UTC),
+			expected: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, workingHours.IsOpenAt(tt.at))
+		})
+	}
+}
@@ -0,0 +1,67 @@
+// Package notifier delivers messages to customers. The service depends only
+// on the Notifier interface, so SMS or e-mail gateways can be plugged in
+// without touching the business logic.
+package notifier
+
+import (
+	"context"
+	"fmt"
+	"log"
+)
+
+const (
+	// KindLog writes notifications to the log with the code masked.
+	KindLog = "log"
+	// KindLogPlaintext writes notifications to the log including the code.
+	KindLogPlaintext = "log-plaintext"
+)
+
+// PickupCodeNotification tells the recipient of an order the code to
+// present at the PVZ.
+type PickupCodeNotification struct {
+	OrderID   string
+	PVZID     string
+	Recipient string
+	Code      string
+}
+
+type Notifier interface {
+	NotifyPickupCode(ctx context.Context, notification PickupCodeNotification) error
+}
+
+// New returns the notifier of the given kind.
+func New(kind string, logger *log.Logger) (Notifier, error) {
+	switch kind {
+	case KindLog:
+		return NewLogNotifier(logger, false), nil
+	case KindLogPlaintext:
+		return NewLogNotifier(logger, true), nil
+	default:
+		return nil, fmt.Errorf("unknown notifier %q", kind)
+	}
+}
+
+// LogNotifier writes notifications to a logger instead of sending them.
+// Pickup codes are masked unless revealCodes is set, which is only meant for
+// local development.
+type LogNotifier struct {
+	logger      *log.Logger
+	revealCodes bool
+}
+
+func NewLogNotifier(logger *log.Logger, revealCodes bool) *LogNotifier {
+	return &LogNotifier{logger: logger, revealCodes: revealCodes}
+}
+
+func (n *LogNotifier) NotifyPickupCode(ctx context.Context, notification PickupCodeNotification) error {
+	code := "******"
+	if n.revealCodes {
+		code = notification.Code
+	}
+
+	n.logger.Printf(
+		"pickup code for order %s at PVZ %s sent to %s: %s",
+		notification.OrderID, notification.PVZID, notification.Recipient, code,
+	)
+	return nil
+}
@@ -0,0 +1,62 @@
+package notifier_test
+
+import (
+	"bytes"
+	"context"
+	"log"
+	"testing"
+
+	"github.com/kirillidk/pvz-service/internal/notifier"
+)
+
+func TestLogNotifier_NotifyPickupCode(t *testing.T) {
+	notification := notifier.PickupCodeNotification{
+		OrderID:   "123e4567-e89b-12d3-a456-426614174005",
+		PVZID:     "123e4567-e89b-12d3-a456-426614174003",
+		Recipient: "+79990000000",
+		Code:      "042137",
+	}
+
+	tests := []struct {
+		name        string
+		revealCodes bool
+		expected    string
+	}{
+		{
+			name:     "Masked",
+			expected: "pickup code for order 123e4567-e89b-12d3-a456-426614174005 at PVZ 123e4567-e89b-12d3-a456-426614174003 sent to +79990000000: ******\n",
+		},
+		{
+			name:        "Plaintext",
+			revealCodes: true,
+			expected:    "pickup code for order 123e4567-e89b-12d3-a456-426614174005 at PVZ 123e4567-e89b-12d3-a456-426614174003 sent to +79990000000: 042137\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			n := notifier.NewLogNotifier(log.New(&buf, "", 0), tt.revealCodes)
+
+			if err := n.NotifyPickupCode(context.Background(), notification); err != nil {
+				t.Fatalf("NotifyPickupCode() unexpected error = %v", err)
+			}
+
+			if buf.String() != tt.expected {
+				t.Errorf("Expected log %q, got %q", tt.expected, buf.String())
+			}
+		})
+	}
+}
+
+func TestNew(t *testing.T) {
+	for _, kind := range []string{notifier.KindLog, notifier.KindLogPlaintext} {
+		if _, err := notifier.New(kind, log.Default()); err != nil {
+			t.Errorf("New(%q) unexpected error = %v", kind, err)
+		}
+	}
+
+	if _, err := notifier.New("sms", log.Default()); err == nil {
+		t.Error("New() expected an error for an unknown notifier")
+	}
+}
@@ -0,0 +1,192 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/kirillidk/pvz-service/internal/dto"
+	"github.com/kirillidk/pvz-service/internal/model"
+	"github.com/lib/pq"
+)
+
+const (
+	apiKeyTableName = "api_keys"
+)
+
+var apiKeyColumns = []string{"id", "name", "role", "pvz_ids", "expires_at", "last_used_at", "revoked_at", "created_at"}
+
+type APIKeyRepositoryInterface interface {
+	CreateAPIKey(ctx context.Context, apiKeyReq dto.APIKeyCreateRequest, keyHash string) (*model.APIKey, error)
+	GetAPIKeyByHash(ctx context.Context, keyHash string) (*model.APIKey, error)
+	GetAPIKeyList(ctx context.Context) ([]model.APIKey, error)
+	RevokeAPIKey(ctx context.Context, apiKeyID string) (*model.APIKey, error)
+	UpdateLastUsed(ctx context.Context, apiKeyID string, usedAt time.Time) error
+}
+
+type APIKeyRepository struct {
+	db   *sql.DB
+	psql sq.StatementBuilderType
+}
+
+func NewAPIKeyRepository(db *sql.DB) *APIKeyRepository {
+	return &APIKeyRepository{
+		db:   db,
+		psql: sq.StatementBuilder.PlaceholderFormat(sq.Dollar),
+	}
+}
+
+func (r *APIKeyRepository) CreateAPIKey(ctx context.Context, apiKeyReq dto.APIKeyCreateRequest, keyHash string) (*model.APIKey, error) {
+	createdAt := time.Now().UTC()
+
+	pvzIDs := apiKeyReq.PVZIDs
+	if pvzIDs == nil {
+		pvzIDs = []string{}
+	}
+
+	expiresAt := apiKeyReq.ExpiresAt
+	if expiresAt != nil {
+		utc := expiresAt.UTC()
+		expiresAt = &utc
+	}
+
+	query, args, err := r.psql.
+		Insert(apiKeyTableName).
+		Columns("name", "key_hash", "role", "pvz_ids", "expires_at", "created_at").
+		Values(apiKeyReq.Name, keyHash, apiKeyReq.Role, pq.Array(pvzIDs), expiresAt, createdAt).
+		Suffix("RETURNING id, name, role, pvz_ids, expires_at, last_used_at, revoked_at, created_at").
+		ToSql()
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to build sql query: %w", err)
+	}
+
+	apiKey, err := scanAPIKey(r.db.QueryRowContext(ctx, query, args...))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create api key: %w", err)
+	}
+
+	return apiKey, nil
+}
+
+func (r *APIKeyRepository) GetAPIKeyByHash(ctx context.Context, keyHash string) (*model.APIKey, error) {
+	query, args, err := r.psql.
+		Select(apiKeyColumns...).
+		From(apiKeyTableName).
+		Where(sq.Eq{"key_hash": keyHash}).
+		ToSql()
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to build sql query: %w", err)
+	}
+
+	apiKey, err := scanAPIKey(r.db.QueryRowContext(ctx, query, args...))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, model.ErrAPIKeyNotFound
+		}
+		return nil, fmt.Errorf("failed to get api key: %w", err)
+	}
+
+	return apiKey, nil
+}
+
+func (r *APIKeyRepository) GetAPIKeyList(ctx context.Context) ([]model.APIKey, error) {
+	query, args, err := r.psql.
+		Select(apiKeyColumns...).
+		From(apiKeyTableName).
+		OrderBy("created_at DESC").
+		ToSql()
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to build sql query: %w", err)
+	}
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query api keys: %w", err)
+	}
+	defer rows.Close()
+
+	var apiKeys []model.APIKey
+	for rows.Next() {
+		apiKey, err := scanAPIKey(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan api key row: %w", err)
+		}
+		apiKeys = append(apiKeys, *apiKey)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating api key rows: %w", err)
+	}
+
+	return apiKeys, nil
+}
+
+func (r *APIKeyRepository) RevokeAPIKey(ctx context.Context, apiKeyID string) (*model.APIKey, error) {
+	query, args, err := r.psql.
+		Update(apiKeyTableName).
+		Set("revoked_at", time.Now().UTC()).
+		Where(sq.Eq{"id": apiKeyID, "revoked_at": nil}).
+		Suffix("RETURNING id, name, role, pvz_ids, expires_at, last_used_at, revoked_at, created_at").
+		ToSql()
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to build sql query: %w", err)
+	}
+
+	apiKey, err := scanAPIKey(r.db.QueryRowContext(ctx, query, args...))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, model.ErrAPIKeyNotFound
+		}
+		return nil, fmt.Errorf("failed to revoke api key: %w", err)
+	}
+
+	return apiKey, nil
+}
+
+func (r *APIKeyRepository) UpdateLastUsed(ctx context.Context, apiKeyID string, usedAt time.Time) error {
+	query, args, err := r.psql.
+		Update(apiKeyTableName).
+		Set("last_used_at", usedAt).
+		Where(sq.Eq{"id": apiKeyID}).
+		ToSql()
+
+	if err != nil {
+		return fmt.Errorf("failed to build sql query: %w", err)
+	}
+
+	if _, err := r.db.ExecContext(ctx, query, args...); err != nil {
+		return fmt.Errorf("failed to update api key last used time: %w", err)
+	}
+
+	return nil
+}
+
+func scanAPIKey(row rowScanner) (*model.APIKey, error) {
+	var apiKey model.APIKey
+	var pvzIDs pq.StringArray
+	var expiresAt, lastUsedAt, revokedAt sql.NullTime
+
+	err := row.Scan(
+		&apiKey.ID, &apiKey.Name, &apiKey.Role, &pvzIDs,
+		&expiresAt, &lastUsedAt, &revokedAt, &apiKey.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	apiKey.PVZIDs = []string(pvzIDs)
+	if apiKey.PVZIDs == nil {
+		apiKey.PVZIDs = []string{}
+	}
+	apiKey.ExpiresAt = nullTimePtr(expiresAt)
+	apiKey.LastUsedAt = nullTimePtr(lastUsedAt)
+	apiKey.RevokedAt = nullTimePtr(revokedAt)
+
+	return &apiKey, nil
+}
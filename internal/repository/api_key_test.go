@@ -0,0 +1,192 @@
+package repository_test
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/kirillidk/pvz-service/internal/dto"
+	"github.com/kirillidk/pvz-service/internal/model"
+	"github.com/kirillidk/pvz-service/internal/repository"
+	"github.com/stretchr/testify/assert"
+)
+
+var apiKeyRowColumns = []string{"id", "name", "role", "pvz_ids", "expires_at", "last_used_at", "revoked_at", "created_at"}
+
+func TestAPIKeyRepository_CreateAPIKey(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	apiKeyRepo := repository.NewAPIKeyRepository(db)
+	ctx := context.Background()
+	testTime := time.Now()
+
+	tests := []struct {
+		name           string
+		apiKeyReq      dto.APIKeyCreateRequest
+		mockBehavior   func()
+		expectedAPIKey *model.APIKey
+		expectedError  error
+	}{
+		{
+			name: "Success",
+			apiKeyReq: dto.APIKeyCreateRequest{
+				Name:   "partner",
+				Role:   model.EmployeeRole,
+				PVZIDs: []string{"b0eebc99-9c0b-4ef8-bb6d-6bb9bd380a11"},
+			},
+			mockBehavior: func() {
+				rows := sqlmock.NewRows(apiKeyRowColumns).
+					AddRow("e0eebc99-9c0b-4ef8-bb6d-6bb9bd380a11", "partner", "employee", "{b0eebc99-9c0b-4ef8-bb6d-6bb9bd380a11}", nil, nil, nil, testTime)
+
+				mock.ExpectQuery(`INSERT INTO api_keys`).
+					WithArgs("partner", "hash", model.EmployeeRole, sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg()).
+					WillReturnRows(rows)
+			},
+			expectedAPIKey: &model.APIKey{
+				ID:        "e0eebc99-9c0b-4ef8-bb6d-6bb9bd380a11",
+				Name:      "partner",
+				Role:      model.EmployeeRole,
+				PVZIDs:    []string{"b0eebc99-9c0b-4ef8-bb6d-6bb9bd380a11"},
+				CreatedAt: testTime,
+			},
+			expectedError: nil,
+		},
+		{
+			name: "DB Error",
+			apiKeyReq: dto.APIKeyCreateRequest{
+				Name: "partner",
+				Role: model.EmployeeRole,
+			},
+			mockBehavior: func() {
+				mock.ExpectQuery(`INSERT INTO api_keys`).
+					WillReturnError(errors.New("db error"))
+			},
+			expectedAPIKey: nil,
+			expectedError:  errors.New("failed to create api key: db error"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockBehavior()
+
+			apiKey, err := apiKeyRepo.CreateAPIKey(ctx, tt.apiKeyReq, "hash")
+
+			if tt.expectedError != nil {
+				assert.Error(t, err)
+				assert.Contains(t, err.Error(), tt.expectedError.Error())
+				assert.Nil(t, apiKey)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expectedAPIKey, apiKey)
+			}
+
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("there were unfulfilled expectations: %s", err)
+			}
+		})
+	}
+}
+
+func TestAPIKeyRepository_GetAPIKeyByHash(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	apiKeyRepo := repository.NewAPIKeyRepository(db)
+	ctx := context.Background()
+	testTime := time.Now()
+
+	tests := []struct {
+		name           string
+		mockBehavior   func()
+		expectedAPIKey *model.APIKey
+		expectedError  error
+	}{
+		{
+			name: "Success",
+			mockBehavior: func() {
+				rows := sqlmock.NewRows(apiKeyRowColumns).
+					AddRow("e0eebc99-9c0b-4ef8-bb6d-6bb9bd380a11", "partner", "moderator", "{}", testTime, nil, nil, testTime)
+
+				mock.ExpectQuery(regexp.QuoteMeta(`SELECT id, name, role, pvz_ids, expires_at, last_used_at, revoked_at, created_at FROM api_keys WHERE key_hash = $1`)).
+					WithArgs("hash").
+					WillReturnRows(rows)
+			},
+			expectedAPIKey: &model.APIKey{
+				ID:        "e0eebc99-9c0b-4ef8-bb6d-6bb9bd380a11",
+				Name:      "partner",
+				Role:      model.ModeratorRole,
+				PVZIDs:    []string{},
+				ExpiresAt: &testTime,
+				CreatedAt: testTime,
+			},
+			expectedError: nil,
+		},
+		{
+			name: "Not Found",
+			mockBehavior: func() {
+				mock.ExpectQuery(`SELECT (.+) FROM api_keys`).
+					WithArgs("hash").
+					WillReturnError(sql.ErrNoRows)
+			},
+			expectedAPIKey: nil,
+			expectedError:  model.ErrAPIKeyNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockBehavior()
+
+			apiKey, err := apiKeyRepo.GetAPIKeyByHash(ctx, "hash")
+
+			if tt.expectedError != nil {
+				assert.ErrorIs(t, err, tt.expectedError)
+				assert.Nil(t, apiKey)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expectedAPIKey, apiKey)
+			}
+
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("there were unfulfilled expectations: %s", err)
+			}
+		})
+	}
+}
+
+func TestAPIKeyRepository_RevokeAPIKey(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	apiKeyRepo := repository.NewAPIKeyRepository(db)
+	ctx := context.Background()
+	apiKeyID := "e0eebc99-9c0b-4ef8-bb6d-6bb9bd380a11"
+
+	mock.ExpectQuery(`UPDATE api_keys SET revoked_at`).
+		WithArgs(sqlmock.AnyArg(), apiKeyID).
+		WillReturnError(sql.ErrNoRows)
+
+	apiKey, err := apiKeyRepo.RevokeAPIKey(ctx, apiKeyID)
+
+	assert.ErrorIs(t, err, model.ErrAPIKeyNotFound)
+	assert.Nil(t, apiKey)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
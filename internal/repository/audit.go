@@ -0,0 +1,207 @@
+package repository
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/kirillidk/pvz-service/internal/dto"
+	"github.com/kirillidk/pvz-service/internal/model"
+)
+
+const (
+	auditLogTableName = "audit_log"
+
+	genesisAuditHash = "0000000000000000000000000000000000000000000000000000000000000000"
+
+	// auditLogLockKey is the advisory lock that serializes appends to the
+	// audit log chain.
+	auditLogLockKey = 7_204_112_028
+)
+
+var auditLogColumns = []string{
+	"id", "created_at", "actor_id", "actor_type", "actor_role", "action",
+	"entity_type", "entity_id", "before_state", "after_state", "request_id", "prev_hash", "hash",
+}
+
+type AuditRepositoryInterface interface {
+	CreateAuditEntry(ctx context.Context, entry model.AuditEntry) (*model.AuditEntry, error)
+	GetAuditLog(ctx context.Context, filter dto.AuditFilterQuery) ([]model.AuditEntry, error)
+}
+
+type AuditRepository struct {
+	db   *sql.DB
+	psql sq.StatementBuilderType
+}
+
+func NewAuditRepository(db *sql.DB) *AuditRepository {
+	return &AuditRepository{
+		db:   db,
+		psql: sq.StatementBuilder.PlaceholderFormat(sq.Dollar),
+	}
+}
+
+// CreateAuditEntry appends the entry to the hash chain of the whole log.
+// With a single chain, removing the records of one entity or any records in
+// the middle breaks the hashes of the records that follow. Appends are
+// serialized by an advisory lock, so it must run inside a transaction that
+// holds the lock until the entry commits.
+func (r *AuditRepository) CreateAuditEntry(ctx context.Context, entry model.AuditEntry) (*model.AuditEntry, error) {
+	q := getQuerier(ctx, r.db)
+
+	if _, err := q.ExecContext(ctx, "SELECT pg_advisory_xact_lock($1)", auditLogLockKey); err != nil {
+		return nil, fmt.Errorf("failed to lock audit log: %w", err)
+	}
+
+	query, args, err := r.psql.
+		Select("hash").
+		From(auditLogTableName).
+		OrderBy("id DESC").
+		Limit(1).
+		ToSql()
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to build sql query: %w", err)
+	}
+
+	prevHash := genesisAuditHash
+	err = q.QueryRowContext(ctx, query, args...).Scan(&prevHash)
+	if err != nil && err != sql.ErrNoRows {
+		return nil, fmt.Errorf("failed to get last audit hash: %w", err)
+	}
+
+	entry.PrevHash = prevHash
+	entry.Hash = AuditEntryHash(entry)
+
+	query, args, err = r.psql.
+		Insert(auditLogTableName).
+		Columns(auditLogColumns[1:]...).
+		Values(
+			entry.CreatedAt, nullString(entry.Actor.ID), entry.Actor.Type, nullString(string(entry.Actor.Role)), entry.Action,
+			entry.EntityType, entry.EntityID, nullJSON(entry.Before), nullJSON(entry.After), nullString(entry.RequestID),
+			entry.PrevHash, entry.Hash,
+		).
+		Suffix("RETURNING id").
+		ToSql()
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to build sql query: %w", err)
+	}
+
+	if err := q.QueryRowContext(ctx, query, args...).Scan(&entry.ID); err != nil {
+		return nil, fmt.Errorf("failed to create audit entry: %w", err)
+	}
+
+	return &entry, nil
+}
+
+func (r *AuditRepository) GetAuditLog(ctx context.Context, filter dto.AuditFilterQuery) ([]model.AuditEntry, error) {
+	queryBuilder := r.psql.
+		Select(auditLogColumns...).
+		From(auditLogTableName)
+
+	if filter.EntityType != "" {
+		queryBuilder = queryBuilder.Where(sq.Eq{"entity_type": filter.EntityType})
+	}
+
+	if filter.EntityID != "" {
+		queryBuilder = queryBuilder.Where(sq.Eq{"entity_id": filter.EntityID})
+	}
+
+	if filter.ActorID != "" {
+		queryBuilder = queryBuilder.Where(sq.Eq{"actor_id": filter.ActorID})
+	}
+
+	if filter.StartDate != nil {
+		queryBuilder = queryBuilder.Where(sq.GtOrEq{"created_at": filter.StartDate})
+	}
+
+	if filter.EndDate != nil {
+		queryBuilder = queryBuilder.Where(sq.LtOrEq{"created_at": filter.EndDate})
+	}
+
+	offset := (filter.Page - 1) * filter.Limit
+	query, args, err := queryBuilder.
+		OrderBy("id DESC").
+		Offset(uint64(offset)).
+		Limit(uint64(filter.Limit)).
+		ToSql()
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to build sql query: %w", err)
+	}
+
+	rows, err := getQuerier(ctx, r.db).QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query audit log: %w", err)
+	}
+	defer rows.Close()
+
+	entries := make([]model.AuditEntry, 0)
+	for rows.Next() {
+		var (
+			entry                     model.AuditEntry
+			actorID, actorRole, reqID sql.NullString
+			beforeState, afterState   []byte
+		)
+
+		err := rows.Scan(
+			&entry.ID, &entry.CreatedAt, &actorID, &entry.Actor.Type, &actorRole, &entry.Action,
+			&entry.EntityType, &entry.EntityID, &beforeState, &afterState, &reqID, &entry.PrevHash, &entry.Hash,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan audit entry row: %w", err)
+		}
+
+		entry.Actor.ID = actorID.String
+		entry.Actor.Role = model.UserRole(actorRole.String)
+		entry.RequestID = reqID.String
+		entry.Before = beforeState
+		entry.After = afterState
+
+		entries = append(entries, entry)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating audit entry rows: %w", err)
+	}
+
+	return entries, nil
+}
+
+// AuditEntryHash returns the SHA-256 of the entry content chained to
+// its PrevHash. Changing any stored field breaks every following hash.
+func AuditEntryHash(entry model.AuditEntry) string {
+	content := strings.Join([]string{
+		entry.PrevHash,
+		entry.CreatedAt.UTC().Format(time.RFC3339Nano),
+		entry.Actor.ID,
+		string(entry.Actor.Type),
+		string(entry.Actor.Role),
+		string(entry.Action),
+		string(entry.EntityType),
+		entry.EntityID,
+		string(entry.Before),
+		string(entry.After),
+		entry.RequestID,
+	}, "\x1f")
+
+	sum := sha256.Sum256([]byte(content))
+	return hex.EncodeToString(sum[:])
+}
+
+func nullString(s string) sql.NullString {
+	return sql.NullString{String: s, Valid: s != ""}
+}
+
+func nullJSON(raw []byte) any {
+	if len(raw) == 0 {
+		return nil
+	}
+	return string(raw)
+}
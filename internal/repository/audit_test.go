@@ -0,0 +1,181 @@
+package repository_test
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/kirillidk/pvz-service/internal/dto"
+	"github.com/kirillidk/pvz-service/internal/model"
+	"github.com/kirillidk/pvz-service/internal/repository"
+	"github.com/stretchr/testify/assert"
+)
+
+var auditLogRowColumns = []string{
+	"id", "created_at", "actor_id", "actor_type", "actor_role", "action",
+	"entity_type", "entity_id", "before_state", "after_state", "request_id", "prev_hash", "hash",
+}
+
+func TestAuditRepository_CreateAuditEntry(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	auditRepo := repository.NewAuditRepository(db)
+	ctx := context.Background()
+	testTime := time.Date(2025, 4, 1, 12, 0, 0, 0, time.UTC)
+
+	entry := model.AuditEntry{
+		CreatedAt:  testTime,
+		Actor:      model.Actor{ID: "c0eebc99-9c0b-4ef8-bb6d-6bb9bd380a11", Type: model.UserActor, Role: model.ModeratorRole},
+		Action:     model.AuditActionCreate,
+		EntityType: model.AuditEntityPVZ,
+		EntityID:   "a0eebc99-9c0b-4ef8-bb6d-6bb9bd380a11",
+		After:      []byte(`{"city":"Москва"}`),
+		RequestID:  "req-1",
+	}
+	prevHash := "1111111111111111111111111111111111111111111111111111111111111111"
+
+	tests := []struct {
+		name             string
+		mockBehavior     func()
+		expectedPrevHash string
+		expectedError    error
+	}{
+		{
+			name: "Success",
+			mockBehavior: func() {
+				mock.ExpectExec(regexp.QuoteMeta("SELECT pg_advisory_xact_lock($1)")).
+					WithArgs(7204112028).
+					WillReturnResult(sqlmock.NewResult(0, 0))
+				// The previous entry of the whole log, whatever its entity.
+				mock.ExpectQuery(regexp.QuoteMeta(`SELECT hash FROM audit_log ORDER BY id DESC LIMIT 1`)).
+					WillReturnRows(sqlmock.NewRows([]string{"hash"}).AddRow(prevHash))
+				mock.ExpectQuery(`INSERT INTO audit_log`).
+					WithArgs(testTime, sqlmock.AnyArg(), model.UserActor, sqlmock.AnyArg(), model.AuditActionCreate,
+						model.AuditEntityPVZ, entry.EntityID, nil, `{"city":"Москва"}`, sqlmock.AnyArg(), prevHash, sqlmock.AnyArg()).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(42))
+			},
+			expectedPrevHash: prevHash,
+			expectedError:    nil,
+		},
+		{
+			name: "First Entry",
+			mockBehavior: func() {
+				mock.ExpectExec(regexp.QuoteMeta("SELECT pg_advisory_xact_lock($1)")).
+					WithArgs(7204112028).
+					WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectQuery(`SELECT hash FROM audit_log`).
+					WillReturnRows(sqlmock.NewRows([]string{"hash"}))
+				mock.ExpectQuery(`INSERT INTO audit_log`).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+			},
+			expectedPrevHash: "0000000000000000000000000000000000000000000000000000000000000000",
+			expectedError:    nil,
+		},
+		{
+			name: "Lock Error",
+			mockBehavior: func() {
+				mock.ExpectExec(regexp.QuoteMeta("SELECT pg_advisory_xact_lock($1)")).
+					WillReturnError(errors.New("db error"))
+			},
+			expectedError: errors.New("failed to lock audit log: db error"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockBehavior()
+
+			created, err := auditRepo.CreateAuditEntry(ctx, entry)
+
+			if tt.expectedError != nil {
+				assert.EqualError(t, err, tt.expectedError.Error())
+				assert.Nil(t, created)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expectedPrevHash, created.PrevHash)
+				assert.Equal(t, repository.AuditEntryHash(*created), created.Hash)
+			}
+
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestAuditEntryHash(t *testing.T) {
+	entry := model.AuditEntry{
+		CreatedAt:  time.Date(2025, 4, 1, 12, 0, 0, 0, time.UTC),
+		Actor:      model.Actor{Type: model.SystemActor},
+		Action:     model.AuditActionDelete,
+		EntityType: model.AuditEntityProduct,
+		EntityID:   "d0eebc99-9c0b-4ef8-bb6d-6bb9bd380a11",
+		Before:     []byte(`{"type":"обувь"}`),
+		PrevHash:   "0000000000000000000000000000000000000000000000000000000000000000",
+	}
+
+	hash := repository.AuditEntryHash(entry)
+	assert.Len(t, hash, 64)
+
+	tampered := entry
+	tampered.Before = []byte(`{"type":"одежда"}`)
+	assert.NotEqual(t, hash, repository.AuditEntryHash(tampered))
+
+	rechained := entry
+	rechained.PrevHash = hash
+	assert.NotEqual(t, hash, repository.AuditEntryHash(rechained))
+}
+
+func TestAuditRepository_GetAuditLog(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	auditRepo := repository.NewAuditRepository(db)
+	ctx := context.Background()
+	testTime := time.Now()
+
+	t.Run("Filter By Entity", func(t *testing.T) {
+		rows := sqlmock.NewRows(auditLogRowColumns).
+			AddRow(1, testTime, nil, "api_key", "employee", "delete", "product", "d0eebc99-9c0b-4ef8-bb6d-6bb9bd380a11",
+				[]byte(`{"type":"обувь"}`), nil, nil, "prev", "hash")
+
+		mock.ExpectQuery(`SELECT .+ FROM audit_log WHERE entity_type = \$1 AND entity_id = \$2 ORDER BY id DESC LIMIT 10 OFFSET 0`).
+			WithArgs("product", "d0eebc99-9c0b-4ef8-bb6d-6bb9bd380a11").
+			WillReturnRows(rows)
+
+		entries, err := auditRepo.GetAuditLog(ctx, dto.AuditFilterQuery{
+			EntityType: "product",
+			EntityID:   "d0eebc99-9c0b-4ef8-bb6d-6bb9bd380a11",
+			Page:       1,
+			Limit:      10,
+		})
+
+		assert.NoError(t, err)
+		assert.Len(t, entries, 1)
+		assert.Equal(t, model.APIKeyActor, entries[0].Actor.Type)
+		assert.Equal(t, model.EmployeeRole, entries[0].Actor.Role)
+		assert.Empty(t, entries[0].Actor.ID)
+		assert.JSONEq(t, `{"type":"обувь"}`, string(entries[0].Before))
+		assert.Nil(t, entries[0].After)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("DB Error", func(t *testing.T) {
+		mock.ExpectQuery(`SELECT .+ FROM audit_log`).
+			WillReturnError(errors.New("db error"))
+
+		entries, err := auditRepo.GetAuditLog(ctx, dto.AuditFilterQuery{Page: 1, Limit: 10})
+
+		assert.EqualError(t, err, "failed to query audit log: db error")
+		assert.Nil(t, entries)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
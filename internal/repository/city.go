@@ -0,0 +1,236 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/kirillidk/pvz-service/internal/dto"
+	"github.com/kirillidk/pvz-service/internal/model"
+)
+
+const (
+	cityTableName = "cities"
+)
+
+var cityColumns = []string{"id", "name", "region", "timezone", "active", "created_at"}
+
+type CityRepositoryInterface interface {
+	CreateCity(ctx context.Context, cityReq dto.CityCreateRequest) (*model.City, error)
+	GetCityList(ctx context.Context) ([]model.City, error)
+	GetCityByID(ctx context.Context, cityID string) (*model.City, error)
+	UpdateCity(ctx context.Context, cityID string, cityReq dto.CityUpdateRequest) (*model.City, error)
+	DeleteCity(ctx context.Context, cityID string) error
+	GetActiveCityNames(ctx context.Context) ([]string, error)
+}
+
+type CityRepository struct {
+	db   *sql.DB
+	psql sq.StatementBuilderType
+}
+
+func NewCityRepository(db *sql.DB) *CityRepository {
+	return &CityRepository{
+		db:   db,
+		psql: sq.StatementBuilder.PlaceholderFormat(sq.Dollar),
+	}
+}
+
+func (r *CityRepository) CreateCity(ctx context.Context, cityReq dto.CityCreateRequest) (*model.City, error) {
+	active := true
+	if cityReq.Active != nil {
+		active = *cityReq.Active
+	}
+
+	query, args, err := r.psql.
+		Insert(cityTableName).
+		Columns("name", "region", "timezone", "active", "created_at").
+		Values(cityReq.Name, cityReq.Region, cityReq.Timezone, active, time.Now().UTC()).
+		Suffix("RETURNING id, name, region, timezone, active, created_at").
+		ToSql()
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to build sql query: %w", err)
+	}
+
+	city, err := scanCity(getQuerier(ctx, r.db).QueryRowContext(ctx, query, args...))
+	if err != nil {
+		if isPQError(err, pqUniqueViolation) {
+			return nil, model.ErrCityAlreadyExists
+		}
+		return nil, fmt.Errorf("failed to create city: %w", err)
+	}
+
+	return city, nil
+}
+
+func (r *CityRepository) GetCityList(ctx context.Context) ([]model.City, error) {
+	query, args, err := r.psql.
+		Select(cityColumns...).
+		From(cityTableName).
+		OrderBy("name").
+		ToSql()
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to build sql query: %w", err)
+	}
+
+	rows, err := getQuerier(ctx, r.db).QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query cities: %w", err)
+	}
+	defer rows.Close()
+
+	cities := make([]model.City, 0)
+	for rows.Next() {
+		city, err := scanCity(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan city row: %w", err)
+		}
+		cities = append(cities, *city)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating city rows: %w", err)
+	}
+
+	return cities, nil
+}
+
+func (r *CityRepository) GetCityByID(ctx context.Context, cityID string) (*model.City, error) {
+	query, args, err := r.psql.
+		Select(cityColumns...).
+		From(cityTableName).
+		Where(sq.Eq{"id": cityID}).
+		ToSql()
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to build sql query: %w", err)
+	}
+
+	city, err := scanCity(getQuerier(ctx, r.db).QueryRowContext(ctx, query, args...))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, model.ErrCityNotFound
+		}
+		return nil, fmt.Errorf("failed to get city: %w", err)
+	}
+
+	return city, nil
+}
+
+func (r *CityRepository) UpdateCity(ctx context.Context, cityID string, cityReq dto.CityUpdateRequest) (*model.City, error) {
+	queryBuilder := r.psql.
+		Update(cityTableName).
+		Where(sq.Eq{"id": cityID}).
+		Suffix("RETURNING id, name, region, timezone, active, created_at")
+
+	if cityReq.Name != nil {
+		queryBuilder = queryBuilder.Set("name", *cityReq.Name)
+	}
+
+	if cityReq.Region != nil {
+		queryBuilder = queryBuilder.Set("region", *cityReq.Region)
+	}
+
+	if cityReq.Timezone != nil {
+		queryBuilder = queryBuilder.Set("timezone", *cityReq.Timezone)
+	}
+
+	if cityReq.Active != nil {
+		queryBuilder = queryBuilder.Set("active", *cityReq.Active)
+	}
+
+	query, args, err := queryBuilder.ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build sql query: %w", err)
+	}
+
+	city, err := scanCity(getQuerier(ctx, r.db).QueryRowContext(ctx, query, args...))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, model.ErrCityNotFound
+		}
+		if isPQError(err, pqUniqueViolation) {
+			return nil, model.ErrCityAlreadyExists
+		}
+		return nil, fmt.Errorf("failed to update city: %w", err)
+	}
+
+	return city, nil
+}
+
+func (r *CityRepository) DeleteCity(ctx context.Context, cityID string) error {
+	query, args, err := r.psql.
+		Delete(cityTableName).
+		Where(sq.Eq{"id": cityID}).
+		ToSql()
+
+	if err != nil {
+		return fmt.Errorf("failed to build sql query: %w", err)
+	}
+
+	result, err := getQuerier(ctx, r.db).ExecContext(ctx, query, args...)
+	if err != nil {
+		if isPQError(err, pqForeignKeyViolation) {
+			return model.ErrCityInUse
+		}
+		return fmt.Errorf("failed to delete city: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return model.ErrCityNotFound
+	}
+
+	return nil
+}
+
+func (r *CityRepository) GetActiveCityNames(ctx context.Context) ([]string, error) {
+	query, args, err := r.psql.
+		Select("name").
+		From(cityTableName).
+		Where(sq.Eq{"active": true}).
+		ToSql()
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to build sql query: %w", err)
+	}
+
+	rows, err := getQuerier(ctx, r.db).QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query active cities: %w", err)
+	}
+	defer rows.Close()
+
+	var names []string
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return nil, fmt.Errorf("failed to scan city name: %w", err)
+		}
+		names = append(names, name)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating city rows: %w", err)
+	}
+
+	return names, nil
+}
+
+func scanCity(row rowScanner) (*model.City, error) {
+	var city model.City
+	err := row.Scan(&city.ID, &city.Name, &city.Region, &city.Timezone, &city.Active, &city.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	return &city, nil
+}
@@ -0,0 +1,140 @@
+package repository_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/kirillidk/pvz-service/internal/dto"
+	"github.com/kirillidk/pvz-service/internal/model"
+	"github.com/kirillidk/pvz-service/internal/repository"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+)
+
+var cityRowColumns = []string{"id", "name", "region", "timezone", "active", "created_at"}
+
+func TestCityRepository_CreateCity(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	cityRepo := repository.NewCityRepository(db)
+	ctx := context.Background()
+	testTime := time.Now()
+
+	t.Run("Success", func(t *testing.T) {
+		rows := sqlmock.NewRows(cityRowColumns).
+			AddRow("f0eebc99-9c0b-4ef8-bb6d-6bb9bd380a11", "Екатеринбург", "Свердловская область", "Asia/Yekaterinburg", true, testTime)
+
+		mock.ExpectQuery(`INSERT INTO cities`).
+			WithArgs("Екатеринбург", "Свердловская область", "Asia/Yekaterinburg", true, sqlmock.AnyArg()).
+			WillReturnRows(rows)
+
+		city, err := cityRepo.CreateCity(ctx, dto.CityCreateRequest{
+			Name:     "Екатеринбург",
+			Region:   "Свердловская область",
+			Timezone: "Asia/Yekaterinburg",
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, &model.City{
+			ID:        "f0eebc99-9c0b-4ef8-bb6d-6bb9bd380a11",
+			Name:      "Екатеринбург",
+			Region:    "Свердловская область",
+			Timezone:  "Asia/Yekaterinburg",
+			Active:    true,
+			CreatedAt: testTime,
+		}, city)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Duplicate Name", func(t *testing.T) {
+		mock.ExpectQuery(`INSERT INTO cities`).
+			WillReturnError(&pq.Error{Code: "23505"})
+
+		city, err := cityRepo.CreateCity(ctx, dto.CityCreateRequest{Name: "Москва", Timezone: "Europe/Moscow"})
+
+		assert.ErrorIs(t, err, model.ErrCityAlreadyExists)
+		assert.Nil(t, city)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestCityRepository_UpdateCity(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	cityRepo := repository.NewCityRepository(db)
+	ctx := context.Background()
+	active := false
+
+	mock.ExpectQuery(`UPDATE cities SET active = \$1 WHERE id = \$2`).
+		WithArgs(false, "f0eebc99-9c0b-4ef8-bb6d-6bb9bd380a11").
+		WillReturnRows(sqlmock.NewRows(cityRowColumns))
+
+	city, err := cityRepo.UpdateCity(ctx, "f0eebc99-9c0b-4ef8-bb6d-6bb9bd380a11", dto.CityUpdateRequest{Active: &active})
+
+	assert.ErrorIs(t, err, model.ErrCityNotFound)
+	assert.Nil(t, city)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestCityRepository_DeleteCity(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	cityRepo := repository.NewCityRepository(db)
+	ctx := context.Background()
+
+	tests := []struct {
+		name          string
+		mockBehavior  func()
+		expectedError error
+	}{
+		{
+			name: "Success",
+			mockBehavior: func() {
+				mock.ExpectExec(`DELETE FROM cities`).WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+		},
+		{
+			name: "Not Found",
+			mockBehavior: func() {
+				mock.ExpectExec(`DELETE FROM cities`).WillReturnResult(sqlmock.NewResult(0, 0))
+			},
+			expectedError: model.ErrCityNotFound,
+		},
+		{
+			name: "Used By PVZ",
+			mockBehavior: func() {
+				mock.ExpectExec(`DELETE FROM cities`).WillReturnError(&pq.Error{Code: "23503"})
+			},
+			expectedError: model.ErrCityInUse,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockBehavior()
+
+			err := cityRepo.DeleteCity(ctx, "f0eebc99-9c0b-4ef8-bb6d-6bb9bd380a11")
+
+			if tt.expectedError != nil {
+				assert.ErrorIs(t, err, tt.expectedError)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
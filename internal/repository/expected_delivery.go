@@ -0,0 +1,368 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/kirillidk/pvz-service/internal/model"
+)
+
+const (
+	expectedDeliveryTableName     = "expected_deliveries"
+	expectedDeliveryItemTableName = "expected_delivery_items"
+)
+
+var expectedDeliveryColumns = []string{"id", "pvz_id", "status", "reception_id", "created_at", "reconciled_at"}
+
+var expectedDeliveryItemColumns = []string{"barcode", "type", "product_id"}
+
+type ExpectedDeliveryRepositoryInterface interface {
+	CreateExpectedDelivery(ctx context.Context, delivery model.ExpectedDelivery) (*model.ExpectedDelivery, error)
+	GetExpectedDelivery(ctx context.Context, deliveryID string) (*model.ExpectedDelivery, error)
+	GetExpectedDeliveriesByPVZID(ctx context.Context, pvzID string) ([]model.ExpectedDelivery, error)
+	GetExpectedDeliveryByReceptionID(ctx context.Context, receptionID string) (*model.ExpectedDelivery, error)
+	LinkPendingExpectedDelivery(ctx context.Context, pvzID, receptionID string) (*model.ExpectedDelivery, error)
+	MatchExpectedDeliveryItem(ctx context.Context, receptionID, barcode, productID string) (*model.ExpectedDeliveryItem, error)
+	MarkExpectedDeliveryReconciled(ctx context.Context, deliveryID string, reconciledAt time.Time) (*model.ExpectedDelivery, error)
+	MarkExpectedDeliveryReceiving(ctx context.Context, deliveryID string) (*model.ExpectedDelivery, error)
+}
+
+type ExpectedDeliveryRepository struct {
+	db   *sql.DB
+	psql sq.StatementBuilderType
+}
+
+func NewExpectedDeliveryRepository(db *sql.DB) *ExpectedDeliveryRepository {
+	return &ExpectedDeliveryRepository{
+		db:   db,
+		psql: sq.StatementBuilder.PlaceholderFormat(sq.Dollar),
+	}
+}
+
+// CreateExpectedDelivery inserts a pending delivery together with its
+// items, so it should be run within a transaction.
+func (r *ExpectedDeliveryRepository) CreateExpectedDelivery(
+	ctx context.Context,
+	delivery model.ExpectedDelivery,
+) (*model.ExpectedDelivery, error) {
+	query, args, err := r.psql.
+		Insert(expectedDeliveryTableName).
+		Columns(expectedDeliveryColumns[1:]...).
+		Values(delivery.PVZID, model.ExpectedDeliveryStatusPending, nil, delivery.CreatedAt, nil).
+		Suffix("RETURNING " + columnList(expectedDeliveryColumns)).
+		ToSql()
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to build sql query: %w", err)
+	}
+
+	querier := getQuerier(ctx, r.db)
+
+	created, err := scanExpectedDelivery(querier.QueryRowContext(ctx, query, args...))
+	if err != nil {
+		if isPQError(err, pqForeignKeyViolation) {
+			return nil, model.ErrPVZNotFound
+		}
+		return nil, fmt.Errorf("failed to create expected delivery: %w", err)
+	}
+
+	insertBuilder := r.psql.
+		Insert(expectedDeliveryItemTableName).
+		Columns("delivery_id", "barcode", "type")
+
+	for _, item := range delivery.Items {
+		insertBuilder = insertBuilder.Values(created.ID, item.Barcode, item.Type)
+	}
+
+	query, args, err = insertBuilder.ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build sql query: %w", err)
+	}
+
+	if _, err := querier.ExecContext(ctx, query, args...); err != nil {
+		return nil, fmt.Errorf("failed to add items to expected delivery: %w", err)
+	}
+
+	if err := r.loadExpectedDeliveryItems(ctx, []*model.ExpectedDelivery{created}); err != nil {
+		return nil, err
+	}
+
+	return created, nil
+}
+
+func (r *ExpectedDeliveryRepository) GetExpectedDelivery(ctx context.Context, deliveryID string) (*model.ExpectedDelivery, error) {
+	query, args, err := r.psql.
+		Select(expectedDeliveryColumns...).
+		From(expectedDeliveryTableName).
+		Where(sq.Eq{"id": deliveryID}).
+		ToSql()
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to build sql query: %w", err)
+	}
+
+	return r.getExpectedDelivery(ctx, query, args)
+}
+
+// GetExpectedDeliveriesByPVZID returns the deliveries expected at the PVZ,
+// the newest first.
+func (r *ExpectedDeliveryRepository) GetExpectedDeliveriesByPVZID(ctx context.Context, pvzID string) ([]model.ExpectedDelivery, error) {
+	query, args, err := r.psql.
+		Select(expectedDeliveryColumns...).
+		From(expectedDeliveryTableName).
+		Where(sq.Eq{"pvz_id": pvzID}).
+		OrderBy("created_at DESC", "id").
+		ToSql()
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to build sql query: %w", err)
+	}
+
+	rows, err := getQuerier(ctx, r.db).QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query expected deliveries: %w", err)
+	}
+	defer rows.Close()
+
+	var deliveries []*model.ExpectedDelivery
+	for rows.Next() {
+		delivery, err := scanExpectedDelivery(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan expected delivery row: %w", err)
+		}
+		deliveries = append(deliveries, delivery)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating expected delivery rows: %w", err)
+	}
+
+	if err := r.loadExpectedDeliveryItems(ctx, deliveries); err != nil {
+		return nil, err
+	}
+
+	result := make([]model.ExpectedDelivery, 0, len(deliveries))
+	for _, delivery := range deliveries {
+		result = append(result, *delivery)
+	}
+
+	return result, nil
+}
+
+// GetExpectedDeliveryByReceptionID returns the delivery linked to the
+// reception or model.ErrExpectedDeliveryNotFound if there is none.
+func (r *ExpectedDeliveryRepository) GetExpectedDeliveryByReceptionID(
+	ctx context.Context,
+	receptionID string,
+) (*model.ExpectedDelivery, error) {
+	query, args, err := r.psql.
+		Select(expectedDeliveryColumns...).
+		From(expectedDeliveryTableName).
+		Where(sq.Eq{"reception_id": receptionID}).
+		ToSql()
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to build sql query: %w", err)
+	}
+
+	return r.getExpectedDelivery(ctx, query, args)
+}
+
+// LinkPendingExpectedDelivery links the oldest pending delivery of the PVZ
+// to the reception and marks it as being received. If the PVZ has no
+// pending delivery, model.ErrExpectedDeliveryNotFound is returned.
+func (r *ExpectedDeliveryRepository) LinkPendingExpectedDelivery(
+	ctx context.Context,
+	pvzID, receptionID string,
+) (*model.ExpectedDelivery, error) {
+	query, args, err := r.psql.
+		Update(expectedDeliveryTableName).
+		Set("status", model.ExpectedDeliveryStatusReceiving).
+		Set("reception_id", receptionID).
+		Where(sq.Expr(
+			"id = (SELECT id FROM "+expectedDeliveryTableName+
+				" WHERE pvz_id = ? AND status = ? ORDER BY created_at, id LIMIT 1 FOR UPDATE)",
+			pvzID, model.ExpectedDeliveryStatusPending,
+		)).
+		Suffix("RETURNING " + columnList(expectedDeliveryColumns)).
+		ToSql()
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to build sql query: %w", err)
+	}
+
+	return r.getExpectedDelivery(ctx, query, args)
+}
+
+// MatchExpectedDeliveryItem assigns the product to the unmatched item with
+// its barcode in the delivery linked to the reception. It returns nil if
+// there is no such item.
+func (r *ExpectedDeliveryRepository) MatchExpectedDeliveryItem(
+	ctx context.Context,
+	receptionID, barcode, productID string,
+) (*model.ExpectedDeliveryItem, error) {
+	query, args, err := r.psql.
+		Update(expectedDeliveryItemTableName).
+		Set("product_id", productID).
+		Where(sq.Eq{"barcode": barcode}).
+		Where(sq.Eq{"product_id": nil}).
+		Where(sq.Expr("delivery_id = (SELECT id FROM "+expectedDeliveryTableName+" WHERE reception_id = ?)", receptionID)).
+		Suffix("RETURNING " + columnList(expectedDeliveryItemColumns)).
+		ToSql()
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to build sql query: %w", err)
+	}
+
+	item, err := scanExpectedDeliveryItem(getQuerier(ctx, r.db).QueryRowContext(ctx, query, args...))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to match expected delivery item: %w", err)
+	}
+
+	return item, nil
+}
+
+func (r *ExpectedDeliveryRepository) MarkExpectedDeliveryReconciled(
+	ctx context.Context,
+	deliveryID string,
+	reconciledAt time.Time,
+) (*model.ExpectedDelivery, error) {
+	query, args, err := r.psql.
+		Update(expectedDeliveryTableName).
+		Set("status", model.ExpectedDeliveryStatusReconciled).
+		Set("reconciled_at", reconciledAt).
+		Where(sq.Eq{"id": deliveryID}).
+		Suffix("RETURNING " + columnList(expectedDeliveryColumns)).
+		ToSql()
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to build sql query: %w", err)
+	}
+
+	return r.getExpectedDelivery(ctx, query, args)
+}
+
+// MarkExpectedDeliveryReceiving returns a reconciled delivery to receiving
+// when its reception is reopened.
+func (r *ExpectedDeliveryRepository) MarkExpectedDeliveryReceiving(ctx context.Context, deliveryID string) (*model.ExpectedDelivery, error) {
+	query, args, err := r.psql.
+		Update(expectedDeliveryTableName).
+		Set("status", model.ExpectedDeliveryStatusReceiving).
+		Set("reconciled_at", nil).
+		Where(sq.Eq{"id": deliveryID}).
+		Suffix("RETURNING " + columnList(expectedDeliveryColumns)).
+		ToSql()
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to build sql query: %w", err)
+	}
+
+	return r.getExpectedDelivery(ctx, query, args)
+}
+
+func (r *ExpectedDeliveryRepository) getExpectedDelivery(ctx context.Context, query string, args []any) (*model.ExpectedDelivery, error) {
+	delivery, err := scanExpectedDelivery(getQuerier(ctx, r.db).QueryRowContext(ctx, query, args...))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, model.ErrExpectedDeliveryNotFound
+		}
+		return nil, fmt.Errorf("failed to get expected delivery: %w", err)
+	}
+
+	if err := r.loadExpectedDeliveryItems(ctx, []*model.ExpectedDelivery{delivery}); err != nil {
+		return nil, err
+	}
+
+	return delivery, nil
+}
+
+// loadExpectedDeliveryItems fills in the items of the deliveries ordered by
+// barcode.
+func (r *ExpectedDeliveryRepository) loadExpectedDeliveryItems(ctx context.Context, deliveries []*model.ExpectedDelivery) error {
+	if len(deliveries) == 0 {
+		return nil
+	}
+
+	byID := make(map[string]*model.ExpectedDelivery, len(deliveries))
+	deliveryIDs := make([]string, 0, len(deliveries))
+	for _, delivery := range deliveries {
+		delivery.Items = []model.ExpectedDeliveryItem{}
+		byID[delivery.ID] = delivery
+		deliveryIDs = append(deliveryIDs, delivery.ID)
+	}
+
+	query, args, err := r.psql.
+		Select(append([]string{"delivery_id"}, expectedDeliveryItemColumns...)...).
+		From(expectedDeliveryItemTableName).
+		Where(sq.Eq{"delivery_id": deliveryIDs}).
+		OrderBy("barcode").
+		ToSql()
+
+	if err != nil {
+		return fmt.Errorf("failed to build sql query: %w", err)
+	}
+
+	rows, err := getQuerier(ctx, r.db).QueryContext(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("failed to query expected delivery items: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			deliveryID string
+			item       model.ExpectedDeliveryItem
+			productID  sql.NullString
+		)
+		if err := rows.Scan(&deliveryID, &item.Barcode, &item.Type, &productID); err != nil {
+			return fmt.Errorf("failed to scan expected delivery item row: %w", err)
+		}
+		item.ProductID = productID.String
+		byID[deliveryID].Items = append(byID[deliveryID].Items, item)
+	}
+
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("error iterating expected delivery item rows: %w", err)
+	}
+
+	return nil
+}
+
+func scanExpectedDelivery(row rowScanner) (*model.ExpectedDelivery, error) {
+	var (
+		delivery     model.ExpectedDelivery
+		receptionID  sql.NullString
+		reconciledAt sql.NullTime
+	)
+
+	err := row.Scan(&delivery.ID, &delivery.PVZID, &delivery.Status, &receptionID, &delivery.CreatedAt, &reconciledAt)
+	if err != nil {
+		return nil, err
+	}
+
+	delivery.ReceptionID = receptionID.String
+	delivery.ReconciledAt = nullTimePtr(reconciledAt)
+
+	return &delivery, nil
+}
+
+func scanExpectedDeliveryItem(row rowScanner) (*model.ExpectedDeliveryItem, error) {
+	var (
+		item      model.ExpectedDeliveryItem
+		productID sql.NullString
+	)
+
+	if err := row.Scan(&item.Barcode, &item.Type, &productID); err != nil {
+		return nil, err
+	}
+
+	item.ProductID = productID.String
+
+	return &item, nil
+}
@@ -0,0 +1,326 @@
+package repository_test
+
+import (
+	"context"
+	"database/sql"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/kirillidk/pvz-service/internal/model"
+	"github.com/kirillidk/pvz-service/internal/repository"
+	"github.com/stretchr/testify/assert"
+)
+
+var expectedDeliveryRowColumns = []string{"id", "pvz_id", "status", "reception_id", "created_at", "reconciled_at"}
+
+const expectedDeliveryItemsQuery = `SELECT delivery_id, barcode, type, product_id FROM expected_delivery_items ` +
+	`WHERE delivery_id IN ($1) ORDER BY barcode`
+
+func TestExpectedDeliveryRepository_CreateExpectedDelivery(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	deliveryRepo := repository.NewExpectedDeliveryRepository(db)
+	ctx := context.Background()
+	now := time.Now()
+
+	deliveryID := "e0eebc99-9c0b-4ef8-bb6d-6bb9bd380a11"
+	pvzID := "b0eebc99-9c0b-4ef8-bb6d-6bb9bd380a11"
+	delivery := model.ExpectedDelivery{
+		PVZID: pvzID,
+		Items: []model.ExpectedDeliveryItem{
+			{Barcode: "4006381333931", Type: "electronics"},
+			{Barcode: "4006381333948", Type: "clothes"},
+		},
+		CreatedAt: now,
+	}
+
+	mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO expected_deliveries (pvz_id,status,reception_id,created_at,reconciled_at) `+
+		`VALUES ($1,$2,$3,$4,$5) RETURNING id, pvz_id, status, reception_id, created_at, reconciled_at`)).
+		WithArgs(pvzID, model.ExpectedDeliveryStatusPending, nil, now, nil).
+		WillReturnRows(sqlmock.NewRows(expectedDeliveryRowColumns).AddRow(deliveryID, pvzID, "pending", nil, now, nil))
+	mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO expected_delivery_items (delivery_id,barcode,type) VALUES ($1,$2,$3),($4,$5,$6)`)).
+		WithArgs(deliveryID, "4006381333931", "electronics", deliveryID, "4006381333948", "clothes").
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectQuery(regexp.QuoteMeta(expectedDeliveryItemsQuery)).
+		WithArgs(deliveryID).
+		WillReturnRows(sqlmock.NewRows([]string{"delivery_id", "barcode", "type", "product_id"}).
+			AddRow(deliveryID, "4006381333931", "electronics", nil).
+			AddRow(deliveryID, "4006381333948", "clothes", nil))
+
+	got, err := deliveryRepo.CreateExpectedDelivery(ctx, delivery)
+
+	assert.NoError(t, err)
+	assert.Equal(t, &model.ExpectedDelivery{
+		ID:        deliveryID,
+		PVZID:     pvzID,
+		Status:    model.ExpectedDeliveryStatusPending,
+		Items:     delivery.Items,
+		CreatedAt: now,
+	}, got)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestExpectedDeliveryRepository_GetExpectedDelivery(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	deliveryRepo := repository.NewExpectedDeliveryRepository(db)
+	ctx := context.Background()
+	now := time.Now()
+
+	deliveryID := "e0eebc99-9c0b-4ef8-bb6d-6bb9bd380a11"
+	pvzID := "b0eebc99-9c0b-4ef8-bb6d-6bb9bd380a11"
+	receptionID := "c0eebc99-9c0b-4ef8-bb6d-6bb9bd380a11"
+	productID := "d0eebc99-9c0b-4ef8-bb6d-6bb9bd380a11"
+
+	query := regexp.QuoteMeta(`SELECT id, pvz_id, status, reception_id, created_at, reconciled_at FROM expected_deliveries WHERE id = $1`)
+
+	tests := []struct {
+		name          string
+		mockBehavior  func()
+		expectedValue *model.ExpectedDelivery
+		expectedError error
+	}{
+		{
+			name: "Success",
+			mockBehavior: func() {
+				mock.ExpectQuery(query).
+					WithArgs(deliveryID).
+					WillReturnRows(sqlmock.NewRows(expectedDeliveryRowColumns).AddRow(deliveryID, pvzID, "receiving", receptionID, now, nil))
+				mock.ExpectQuery(regexp.QuoteMeta(expectedDeliveryItemsQuery)).
+					WithArgs(deliveryID).
+					WillReturnRows(sqlmock.NewRows([]string{"delivery_id", "barcode", "type", "product_id"}).
+						AddRow(deliveryID, "4006381333931", "electronics", productID).
+						AddRow(deliveryID, "4006381333948", "clothes", nil))
+			},
+			expectedValue: &model.ExpectedDelivery{
+				ID:          deliveryID,
+				PVZID:       pvzID,
+				Status:      model.ExpectedDeliveryStatusReceiving,
+				ReceptionID: receptionID,
+				Items: []model.ExpectedDeliveryItem{
+					{Barcode: "4006381333931", Type: "electronics", ProductID: productID},
+					{Barcode: "4006381333948", Type: "clothes"},
+				},
+				CreatedAt: now,
+			},
+		},
+		{
+			name: "Not Found",
+			mockBehavior: func() {
+				mock.ExpectQuery(query).
+					WithArgs(deliveryID).
+					WillReturnError(sql.ErrNoRows)
+			},
+			expectedError: model.ErrExpectedDeliveryNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockBehavior()
+
+			got, err := deliveryRepo.GetExpectedDelivery(ctx, deliveryID)
+
+			if tt.expectedError != nil {
+				assert.ErrorIs(t, err, tt.expectedError)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expectedValue, got)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestExpectedDeliveryRepository_LinkPendingExpectedDelivery(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	deliveryRepo := repository.NewExpectedDeliveryRepository(db)
+	ctx := context.Background()
+	now := time.Now()
+
+	deliveryID := "e0eebc99-9c0b-4ef8-bb6d-6bb9bd380a11"
+	pvzID := "b0eebc99-9c0b-4ef8-bb6d-6bb9bd380a11"
+	receptionID := "c0eebc99-9c0b-4ef8-bb6d-6bb9bd380a11"
+
+	query := regexp.QuoteMeta(`UPDATE expected_deliveries SET status = $1, reception_id = $2 ` +
+		`WHERE id = (SELECT id FROM expected_deliveries WHERE pvz_id = $3 AND status = $4 ORDER BY created_at, id LIMIT 1 FOR UPDATE) ` +
+		`RETURNING id, pvz_id, status, reception_id, created_at, reconciled_at`)
+
+	t.Run("Success", func(t *testing.T) {
+		mock.ExpectQuery(query).
+			WithArgs(model.ExpectedDeliveryStatusReceiving, receptionID, pvzID, model.ExpectedDeliveryStatusPending).
+			WillReturnRows(sqlmock.NewRows(expectedDeliveryRowColumns).AddRow(deliveryID, pvzID, "receiving", receptionID, now, nil))
+		mock.ExpectQuery(regexp.QuoteMeta(expectedDeliveryItemsQuery)).
+			WithArgs(deliveryID).
+			WillReturnRows(sqlmock.NewRows([]string{"delivery_id", "barcode", "type", "product_id"}))
+
+		got, err := deliveryRepo.LinkPendingExpectedDelivery(ctx, pvzID, receptionID)
+
+		assert.NoError(t, err)
+		assert.Equal(t, &model.ExpectedDelivery{
+			ID:          deliveryID,
+			PVZID:       pvzID,
+			Status:      model.ExpectedDeliveryStatusReceiving,
+			ReceptionID: receptionID,
+			Items:       []model.ExpectedDeliveryItem{},
+			CreatedAt:   now,
+		}, got)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("No Pending Delivery", func(t *testing.T) {
+		mock.ExpectQuery(query).
+			WithArgs(model.ExpectedDeliveryStatusReceiving, receptionID, pvzID, model.ExpectedDeliveryStatusPending).
+			WillReturnError(sql.ErrNoRows)
+
+		_, err := deliveryRepo.LinkPendingExpectedDelivery(ctx, pvzID, receptionID)
+
+		assert.ErrorIs(t, err, model.ErrExpectedDeliveryNotFound)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestExpectedDeliveryRepository_MatchExpectedDeliveryItem(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	deliveryRepo := repository.NewExpectedDeliveryRepository(db)
+	ctx := context.Background()
+
+	receptionID := "c0eebc99-9c0b-4ef8-bb6d-6bb9bd380a11"
+	productID := "d0eebc99-9c0b-4ef8-bb6d-6bb9bd380a11"
+	barcode := "4006381333931"
+
+	query := regexp.QuoteMeta(`UPDATE expected_delivery_items SET product_id = $1 ` +
+		`WHERE barcode = $2 AND product_id IS NULL AND delivery_id = (SELECT id FROM expected_deliveries WHERE reception_id = $3) ` +
+		`RETURNING barcode, type, product_id`)
+
+	tests := []struct {
+		name          string
+		mockBehavior  func()
+		expectedValue *model.ExpectedDeliveryItem
+	}{
+		{
+			name: "Matched",
+			mockBehavior: func() {
+				mock.ExpectQuery(query).
+					WithArgs(productID, barcode, receptionID).
+					WillReturnRows(sqlmock.NewRows([]string{"barcode", "type", "product_id"}).AddRow(barcode, "electronics", productID))
+			},
+			expectedValue: &model.ExpectedDeliveryItem{Barcode: barcode, Type: "electronics", ProductID: productID},
+		},
+		{
+			name: "Not In Delivery",
+			mockBehavior: func() {
+				mock.ExpectQuery(query).
+					WithArgs(productID, barcode, receptionID).
+					WillReturnError(sql.ErrNoRows)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockBehavior()
+
+			got, err := deliveryRepo.MatchExpectedDeliveryItem(ctx, receptionID, barcode, productID)
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedValue, got)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestExpectedDeliveryRepository_MarkExpectedDeliveryReconciled(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	deliveryRepo := repository.NewExpectedDeliveryRepository(db)
+	ctx := context.Background()
+	now := time.Now()
+
+	deliveryID := "e0eebc99-9c0b-4ef8-bb6d-6bb9bd380a11"
+	pvzID := "b0eebc99-9c0b-4ef8-bb6d-6bb9bd380a11"
+	receptionID := "c0eebc99-9c0b-4ef8-bb6d-6bb9bd380a11"
+
+	mock.ExpectQuery(regexp.QuoteMeta(`UPDATE expected_deliveries SET status = $1, reconciled_at = $2 WHERE id = $3 `+
+		`RETURNING id, pvz_id, status, reception_id, created_at, reconciled_at`)).
+		WithArgs(model.ExpectedDeliveryStatusReconciled, now, deliveryID).
+		WillReturnRows(sqlmock.NewRows(expectedDeliveryRowColumns).AddRow(deliveryID, pvzID, "reconciled", receptionID, now, now))
+	mock.ExpectQuery(regexp.QuoteMeta(expectedDeliveryItemsQuery)).
+		WithArgs(deliveryID).
+		WillReturnRows(sqlmock.NewRows([]string{"delivery_id", "barcode", "type", "product_id"}))
+
+	got, err := deliveryRepo.MarkExpectedDeliveryReconciled(ctx, deliveryID, now)
+
+	assert.NoError(t, err)
+	assert.Equal(t, &model.ExpectedDelivery{
+		ID:           deliveryID,
+		PVZID:        pvzID,
+		Status:       model.ExpectedDeliveryStatusReconciled,
+		ReceptionID:  receptionID,
+		Items:        []model.ExpectedDeliveryItem{},
+		CreatedAt:    now,
+		ReconciledAt: &now,
+	}, got)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestExpectedDeliveryRepository_MarkExpectedDeliveryReceiving(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	deliveryRepo := repository.NewExpectedDeliveryRepository(db)
+	ctx := context.Background()
+	now := time.Now()
+
+	deliveryID := "e0eebc99-9c0b-4ef8-bb6d-6bb9bd380a11"
+	pvzID := "b0eebc99-9c0b-4ef8-bb6d-6bb9bd380a11"
+	receptionID := "c0eebc99-9c0b-4ef8-bb6d-6bb9bd380a11"
+
+	mock.ExpectQuery(regexp.QuoteMeta(`UPDATE expected_deliveries SET status = $1, reconciled_at = $2 WHERE id = $3 `+
+		`RETURNING id, pvz_id, status, reception_id, created_at, reconciled_at`)).
+		WithArgs(model.ExpectedDeliveryStatusReceiving, nil, deliveryID).
+		WillReturnRows(sqlmock.NewRows(expectedDeliveryRowColumns).AddRow(deliveryID, pvzID, "receiving", receptionID, now, nil))
+	mock.ExpectQuery(regexp.QuoteMeta(expectedDeliveryItemsQuery)).
+		WithArgs(deliveryID).
+		WillReturnRows(sqlmock.NewRows([]string{"delivery_id", "barcode", "type", "product_id"}))
+
+	got, err := deliveryRepo.MarkExpectedDeliveryReceiving(ctx, deliveryID)
+
+	assert.NoError(t, err)
+	assert.Equal(t, &model.ExpectedDelivery{
+		ID:          deliveryID,
+		PVZID:       pvzID,
+		Status:      model.ExpectedDeliveryStatusReceiving,
+		ReceptionID: receptionID,
+		Items:       []model.ExpectedDeliveryItem{},
+		CreatedAt:   now,
+	}, got)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,98 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/kirillidk/pvz-service/internal/dto"
+	"github.com/kirillidk/pvz-service/internal/model"
+)
+
+type ExportRepositoryInterface interface {
+	ExportReceptionRows(ctx context.Context, filter dto.PVZFilterQuery, fn func(model.ReceptionExportRow) error) error
+}
+
+type ExportRepository struct {
+	db   *sql.DB
+	psql sq.StatementBuilderType
+}
+
+func NewExportRepository(db *sql.DB) *ExportRepository {
+	return &ExportRepository{
+		db:   db,
+		psql: sq.StatementBuilder.PlaceholderFormat(sq.Dollar),
+	}
+}
+
+// ExportReceptionRows calls fn for every product of the receptions that
+// match filter, in the order of GET /pvz. Rows are read one at a time, so
+// the result is never held in memory as a whole. An error returned by fn
+// stops the export.
+func (r *ExportRepository) ExportReceptionRows(
+	ctx context.Context,
+	filter dto.PVZFilterQuery,
+	fn func(model.ReceptionExportRow) error,
+) error {
+	queryBuilder := r.psql.
+		Select(
+			"p.id", "p.city", "p.address", "p.status",
+			"r.id", "r.date_time", "r.status", "r.closed_at",
+			"pr.id", "pr.date_time", "pr.type", "pr.serial_number", "pr.barcode",
+		).
+		From(pvzTableName + " p").
+		Join(receptionTableName + " r ON r.pvz_id = p.id").
+		Join(productTableName + " pr ON pr.reception_id = r.id")
+
+	for _, condition := range pvzFilterConditions(filter) {
+		queryBuilder = queryBuilder.Where(condition)
+	}
+
+	for _, condition := range receptionFilterConditions("r", filter.ReceptionFilter()) {
+		queryBuilder = queryBuilder.Where(condition)
+	}
+
+	query, args, err := queryBuilder.OrderBy(pvzRowsOrderBy(filter.Sort)...).ToSql()
+	if err != nil {
+		return fmt.Errorf("failed to build sql query: %w", err)
+	}
+
+	rows, err := getQuerier(ctx, r.db).QueryContext(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("failed to query export rows: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			row          model.ReceptionExportRow
+			closedAt     sql.NullTime
+			serialNumber sql.NullString
+			barcode      sql.NullString
+		)
+
+		err := rows.Scan(
+			&row.PVZID, &row.City, &row.Address, &row.PVZStatus,
+			&row.ReceptionID, &row.ReceptionDateTime, &row.ReceptionStatus, &closedAt,
+			&row.ProductID, &row.ProductDateTime, &row.ProductType, &serialNumber, &barcode,
+		)
+		if err != nil {
+			return fmt.Errorf("failed to scan export row: %w", err)
+		}
+
+		row.ReceptionClosedAt = nullTimePtr(closedAt)
+		row.SerialNumber = serialNumber.String
+		row.Barcode = barcode.String
+
+		if err := fn(row); err != nil {
+			return err
+		}
+	}
+
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("error iterating export rows: %w", err)
+	}
+
+	return nil
+}
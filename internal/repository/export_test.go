@@ -0,0 +1,127 @@
+package repository_test
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/kirillidk/pvz-service/internal/dto"
+	"github.com/kirillidk/pvz-service/internal/model"
+	"github.com/kirillidk/pvz-service/internal/repository"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExportRepository_ExportReceptionRows(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	exportRepo := repository.NewExportRepository(db)
+	ctx := context.Background()
+	testTime := time.Now()
+
+	pvzID := "b0eebc99-9c0b-4ef8-bb6d-6bb9bd380a11"
+	receptionID := "c0eebc99-9c0b-4ef8-bb6d-6bb9bd380a11"
+
+	exportColumns := []string{
+		"id", "city", "address", "status", "id", "date_time", "status", "closed_at",
+		"id", "date_time", "type", "serial_number", "barcode",
+	}
+	exportSelect := `SELECT p.id, p.city, p.address, p.status, r.id, r.date_time, r.status, r.closed_at, pr.id, pr.date_time, pr.type, pr.serial_number, pr.barcode FROM pvz p JOIN receptions r ON r.pvz_id = p.id JOIN products pr ON pr.reception_id = r.id`
+
+	tests := []struct {
+		name          string
+		filter        dto.PVZFilterQuery
+		mockBehavior  func()
+		expectedRows  []model.ReceptionExportRow
+		expectedError error
+	}{
+		{
+			name: "Success With Filters",
+			filter: dto.PVZFilterQuery{
+				City:        []string{"Казань"},
+				ProductType: "электроника",
+				Sort:        dto.PVZSortCity,
+			},
+			mockBehavior: func() {
+				rows := sqlmock.NewRows(exportColumns).
+					AddRow(pvzID, "Казань", "ул. Баумана, 1", "active", receptionID, testTime, "close", testTime, "d0eebc99-9c0b-4ef8-bb6d-6bb9bd380a11", testTime, "электроника", nil, "4006381333931").
+					AddRow(pvzID, "Казань", "ул. Баумана, 1", "active", receptionID, testTime, "close", testTime, "d0eebc99-9c0b-4ef8-bb6d-6bb9bd380a12", testTime, "обувь", "4601234567890", nil)
+
+				mock.ExpectQuery(regexp.QuoteMeta(exportSelect+` WHERE p.status <> $1 AND p.city IN ($2) AND EXISTS (SELECT 1 FROM products pr WHERE pr.reception_id = r.id AND pr.type = $3) ORDER BY p.city ASC, p.registration_date DESC, p.id, r.date_time DESC, r.id, pr.seq DESC`)).
+					WithArgs(model.PVZStatusArchived, "Казань", "электроника").
+					WillReturnRows(rows)
+			},
+			expectedRows: []model.ReceptionExportRow{
+				{
+					PVZID: pvzID, City: "Казань", Address: "ул. Баумана, 1", PVZStatus: model.PVZStatusActive,
+					ReceptionID: receptionID, ReceptionDateTime: testTime, ReceptionStatus: "close", ReceptionClosedAt: &testTime,
+					ProductID: "d0eebc99-9c0b-4ef8-bb6d-6bb9bd380a11", ProductDateTime: testTime, ProductType: "электроника",
+					Barcode: "4006381333931",
+				},
+				{
+					PVZID: pvzID, City: "Казань", Address: "ул. Баумана, 1", PVZStatus: model.PVZStatusActive,
+					ReceptionID: receptionID, ReceptionDateTime: testTime, ReceptionStatus: "close", ReceptionClosedAt: &testTime,
+					ProductID: "d0eebc99-9c0b-4ef8-bb6d-6bb9bd380a12", ProductDateTime: testTime, ProductType: "обувь",
+					SerialNumber: "4601234567890",
+				},
+			},
+		},
+		{
+			name:   "DB Error",
+			filter: dto.PVZFilterQuery{IncludeArchived: true},
+			mockBehavior: func() {
+				mock.ExpectQuery(regexp.QuoteMeta(exportSelect + ` ORDER BY p.registration_date DESC, p.id, r.date_time DESC, r.id, pr.seq DESC`)).
+					WillReturnError(errors.New("db error"))
+			},
+			expectedError: errors.New("failed to query export rows: db error"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockBehavior()
+
+			var exported []model.ReceptionExportRow
+			err := exportRepo.ExportReceptionRows(ctx, tt.filter, func(row model.ReceptionExportRow) error {
+				exported = append(exported, row)
+				return nil
+			})
+
+			if tt.expectedError != nil {
+				assert.Error(t, err)
+				assert.Contains(t, err.Error(), tt.expectedError.Error())
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expectedRows, exported)
+			}
+
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("there were unfulfilled expectations: %s", err)
+			}
+		})
+	}
+
+	t.Run("Callback Error Stops Export", func(t *testing.T) {
+		rows := sqlmock.NewRows(exportColumns).
+			AddRow(pvzID, "Москва", "", "active", receptionID, testTime, "in_progress", nil, "d0eebc99-9c0b-4ef8-bb6d-6bb9bd380a11", testTime, "обувь", nil, nil).
+			AddRow(pvzID, "Москва", "", "active", receptionID, testTime, "in_progress", nil, "d0eebc99-9c0b-4ef8-bb6d-6bb9bd380a12", testTime, "обувь", nil, nil)
+
+		mock.ExpectQuery(regexp.QuoteMeta(exportSelect)).WillReturnRows(rows)
+
+		calls := 0
+		writeErr := errors.New("client disconnected")
+		err := exportRepo.ExportReceptionRows(ctx, dto.PVZFilterQuery{}, func(row model.ReceptionExportRow) error {
+			calls++
+			return writeErr
+		})
+
+		assert.ErrorIs(t, err, writeErr)
+		assert.Equal(t, 1, calls)
+	})
+}
@@ -0,0 +1,282 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sync"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/kirillidk/pvz-service/internal/model"
+)
+
+const (
+	idempotencyKeyTableName = "idempotency_keys"
+)
+
+var idempotencyKeyColumns = []string{"scope", "key", "token", "request_hash", "status_code", "content_type", "response_body", "created_at", "expires_at"}
+
+type IdempotencyRepositoryInterface interface {
+	// Reserve claims the key for a new request. When an unexpired record
+	// already exists it is returned with reserved set to false. The
+	// expiry of a reserved record is a lease: if the request never
+	// completes, the key can be claimed again once it runs out.
+	Reserve(ctx context.Context, record model.IdempotencyRecord) (existing *model.IdempotencyRecord, reserved bool, err error)
+	// Complete stores the response of the reservation with the token and
+	// keeps the record until expiresAt. It returns
+	// model.ErrIdempotencyKeyReclaimed if the key was claimed again after
+	// the lease ran out.
+	Complete(ctx context.Context, scope, key, token string, statusCode int, contentType string, body []byte, expiresAt time.Time) error
+	// Release deletes the reservation with the token. A reservation made
+	// by another request is left intact.
+	Release(ctx context.Context, scope, key, token string) error
+	// DeleteExpired removes records expired by now and returns how many
+	// were removed.
+	DeleteExpired(ctx context.Context, now time.Time) (int64, error)
+}
+
+type IdempotencyRepository struct {
+	db   *sql.DB
+	psql sq.StatementBuilderType
+}
+
+func NewIdempotencyRepository(db *sql.DB) *IdempotencyRepository {
+	return &IdempotencyRepository{
+		db:   db,
+		psql: sq.StatementBuilder.PlaceholderFormat(sq.Dollar),
+	}
+}
+
+// Reserve inserts a pending record. An expired record with the same key is
+// replaced, an unexpired one is left intact and returned instead.
+func (r *IdempotencyRepository) Reserve(
+	ctx context.Context,
+	record model.IdempotencyRecord,
+) (*model.IdempotencyRecord, bool, error) {
+	query, args, err := r.psql.
+		Insert(idempotencyKeyTableName).
+		Columns("scope", "key", "token", "request_hash", "created_at", "expires_at").
+		Values(record.Scope, record.Key, record.Token, record.RequestHash, record.CreatedAt, record.ExpiresAt).
+		Suffix(
+			"ON CONFLICT (scope, key) DO UPDATE SET "+
+				"token = EXCLUDED.token, request_hash = EXCLUDED.request_hash, status_code = NULL, content_type = NULL, response_body = NULL, "+
+				"created_at = EXCLUDED.created_at, expires_at = EXCLUDED.expires_at "+
+				"WHERE "+idempotencyKeyTableName+".expires_at <= ? RETURNING scope",
+			record.CreatedAt,
+		).
+		ToSql()
+
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to build sql query: %w", err)
+	}
+
+	var scope string
+	err = r.db.QueryRowContext(ctx, query, args...).Scan(&scope)
+	if err == nil {
+		return nil, true, nil
+	}
+	if err != sql.ErrNoRows {
+		return nil, false, fmt.Errorf("failed to reserve idempotency key: %w", err)
+	}
+
+	existing, err := r.getIdempotencyRecord(ctx, record.Scope, record.Key)
+	if err != nil {
+		return nil, false, err
+	}
+
+	return existing, false, nil
+}
+
+func (r *IdempotencyRepository) Complete(
+	ctx context.Context,
+	scope, key, token string,
+	statusCode int,
+	contentType string,
+	body []byte,
+	expiresAt time.Time,
+) error {
+	query, args, err := r.psql.
+		Update(idempotencyKeyTableName).
+		Set("status_code", statusCode).
+		Set("content_type", contentType).
+		Set("response_body", body).
+		Set("expires_at", expiresAt).
+		Where(sq.Eq{"scope": scope, "key": key, "token": token}).
+		ToSql()
+
+	if err != nil {
+		return fmt.Errorf("failed to build sql query: %w", err)
+	}
+
+	result, err := r.db.ExecContext(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("failed to complete idempotency key: %w", err)
+	}
+
+	updated, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get affected rows: %w", err)
+	}
+
+	if updated == 0 {
+		return model.ErrIdempotencyKeyReclaimed
+	}
+
+	return nil
+}
+
+func (r *IdempotencyRepository) Release(ctx context.Context, scope, key, token string) error {
+	query, args, err := r.psql.
+		Delete(idempotencyKeyTableName).
+		Where(sq.Eq{"scope": scope, "key": key, "token": token}).
+		ToSql()
+
+	if err != nil {
+		return fmt.Errorf("failed to build sql query: %w", err)
+	}
+
+	if _, err := r.db.ExecContext(ctx, query, args...); err != nil {
+		return fmt.Errorf("failed to release idempotency key: %w", err)
+	}
+
+	return nil
+}
+
+func (r *IdempotencyRepository) DeleteExpired(ctx context.Context, now time.Time) (int64, error) {
+	query, args, err := r.psql.
+		Delete(idempotencyKeyTableName).
+		Where(sq.LtOrEq{"expires_at": now}).
+		ToSql()
+
+	if err != nil {
+		return 0, fmt.Errorf("failed to build sql query: %w", err)
+	}
+
+	result, err := r.db.ExecContext(ctx, query, args...)
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete expired idempotency keys: %w", err)
+	}
+
+	deleted, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get affected rows: %w", err)
+	}
+
+	return deleted, nil
+}
+
+func (r *IdempotencyRepository) getIdempotencyRecord(ctx context.Context, scope, key string) (*model.IdempotencyRecord, error) {
+	query, args, err := r.psql.
+		Select(idempotencyKeyColumns...).
+		From(idempotencyKeyTableName).
+		Where(sq.Eq{"scope": scope, "key": key}).
+		ToSql()
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to build sql query: %w", err)
+	}
+
+	var (
+		record      model.IdempotencyRecord
+		statusCode  sql.NullInt64
+		contentType sql.NullString
+	)
+
+	err = r.db.QueryRowContext(ctx, query, args...).Scan(
+		&record.Scope, &record.Key, &record.Token, &record.RequestHash, &statusCode, &contentType,
+		&record.ResponseBody, &record.CreatedAt, &record.ExpiresAt,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get idempotency key: %w", err)
+	}
+
+	record.StatusCode = int(statusCode.Int64)
+	record.ContentType = contentType.String
+
+	return &record, nil
+}
+
+// MemoryIdempotencyRepository keeps idempotency records in process memory.
+// It is meant for tests and single-instance setups; records are lost on
+// restart.
+type MemoryIdempotencyRepository struct {
+	mu      sync.Mutex
+	records map[[2]string]model.IdempotencyRecord
+}
+
+func NewMemoryIdempotencyRepository() *MemoryIdempotencyRepository {
+	return &MemoryIdempotencyRepository{
+		records: make(map[[2]string]model.IdempotencyRecord),
+	}
+}
+
+func (r *MemoryIdempotencyRepository) Reserve(
+	ctx context.Context,
+	record model.IdempotencyRecord,
+) (*model.IdempotencyRecord, bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	id := [2]string{record.Scope, record.Key}
+	if existing, ok := r.records[id]; ok && existing.ExpiresAt.After(record.CreatedAt) {
+		return &existing, false, nil
+	}
+
+	r.records[id] = record
+
+	return nil, true, nil
+}
+
+func (r *MemoryIdempotencyRepository) Complete(
+	ctx context.Context,
+	scope, key, token string,
+	statusCode int,
+	contentType string,
+	body []byte,
+	expiresAt time.Time,
+) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	id := [2]string{scope, key}
+	record, ok := r.records[id]
+	if !ok || record.Token != token {
+		return model.ErrIdempotencyKeyReclaimed
+	}
+
+	record.StatusCode = statusCode
+	record.ContentType = contentType
+	record.ResponseBody = body
+	record.ExpiresAt = expiresAt
+	r.records[id] = record
+
+	return nil
+}
+
+func (r *MemoryIdempotencyRepository) Release(ctx context.Context, scope, key, token string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	id := [2]string{scope, key}
+	if record, ok := r.records[id]; ok && record.Token == token {
+		delete(r.records, id)
+	}
+
+	return nil
+}
+
+func (r *MemoryIdempotencyRepository) DeleteExpired(ctx context.Context, now time.Time) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var deleted int64
+	for id, record := range r.records {
+		if !record.ExpiresAt.After(now) {
+			delete(r.records, id)
+			deleted++
+		}
+	}
+
+	return deleted, nil
+}
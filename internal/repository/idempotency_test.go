@@ -0,0 +1,260 @@
+package repository_test
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/kirillidk/pvz-service/internal/model"
+	"github.com/kirillidk/pvz-service/internal/repository"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIdempotencyRepository_Reserve(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	idempotencyRepo := repository.NewIdempotencyRepository(db)
+	ctx := context.Background()
+	now := time.Now()
+
+	record := model.IdempotencyRecord{
+		Scope:       "user:b0eebc99-9c0b-4ef8-bb6d-6bb9bd380a11",
+		Key:         "key-1",
+		Token:       "token-1",
+		RequestHash: "hash",
+		CreatedAt:   now,
+		ExpiresAt:   now.Add(time.Hour),
+	}
+
+	insertQuery := regexp.QuoteMeta(`INSERT INTO idempotency_keys (scope,key,token,request_hash,created_at,expires_at) VALUES ($1,$2,$3,$4,$5,$6) `+
+		`ON CONFLICT (scope, key) DO UPDATE SET token = EXCLUDED.token,`) + `.*` + regexp.QuoteMeta(`WHERE idempotency_keys.expires_at <= $7 RETURNING scope`)
+	selectQuery := regexp.QuoteMeta(`SELECT scope, key, token, request_hash, status_code, content_type, response_body, created_at, expires_at ` +
+		`FROM idempotency_keys WHERE key = $1 AND scope = $2`)
+
+	tests := []struct {
+		name             string
+		mockBehavior     func()
+		expectedReserved bool
+		expectedExisting *model.IdempotencyRecord
+		expectedError    error
+	}{
+		{
+			name: "Reserved",
+			mockBehavior: func() {
+				mock.ExpectQuery(insertQuery).
+					WithArgs(record.Scope, record.Key, record.Token, record.RequestHash, record.CreatedAt, record.ExpiresAt, record.CreatedAt).
+					WillReturnRows(sqlmock.NewRows([]string{"scope"}).AddRow(record.Scope))
+			},
+			expectedReserved: true,
+		},
+		{
+			name: "Existing Completed",
+			mockBehavior: func() {
+				mock.ExpectQuery(insertQuery).
+					WillReturnError(sql.ErrNoRows)
+
+				rows := sqlmock.NewRows([]string{"scope", "key", "token", "request_hash", "status_code", "content_type", "response_body", "created_at", "expires_at"}).
+					AddRow(record.Scope, record.Key, "token-0", "hash", 201, "application/json", []byte(`{}`), now, now.Add(time.Hour))
+
+				mock.ExpectQuery(selectQuery).
+					WithArgs(record.Key, record.Scope).
+					WillReturnRows(rows)
+			},
+			expectedExisting: &model.IdempotencyRecord{
+				Scope:        record.Scope,
+				Key:          record.Key,
+				Token:        "token-0",
+				RequestHash:  "hash",
+				StatusCode:   201,
+				ContentType:  "application/json",
+				ResponseBody: []byte(`{}`),
+				CreatedAt:    now,
+				ExpiresAt:    now.Add(time.Hour),
+			},
+		},
+		{
+			name: "Existing In Progress",
+			mockBehavior: func() {
+				mock.ExpectQuery(insertQuery).
+					WillReturnError(sql.ErrNoRows)
+
+				rows := sqlmock.NewRows([]string{"scope", "key", "token", "request_hash", "status_code", "content_type", "response_body", "created_at", "expires_at"}).
+					AddRow(record.Scope, record.Key, "token-0", "hash", nil, nil, nil, now, now.Add(time.Hour))
+
+				mock.ExpectQuery(selectQuery).
+					WithArgs(record.Key, record.Scope).
+					WillReturnRows(rows)
+			},
+			expectedExisting: &model.IdempotencyRecord{
+				Scope:       record.Scope,
+				Key:         record.Key,
+				Token:       "token-0",
+				RequestHash: "hash",
+				CreatedAt:   now,
+				ExpiresAt:   now.Add(time.Hour),
+			},
+		},
+		{
+			name: "DB Error",
+			mockBehavior: func() {
+				mock.ExpectQuery(insertQuery).
+					WillReturnError(errors.New("db error"))
+			},
+			expectedError: errors.New("failed to reserve idempotency key: db error"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockBehavior()
+
+			existing, reserved, err := idempotencyRepo.Reserve(ctx, record)
+
+			if tt.expectedError != nil {
+				assert.Error(t, err)
+				assert.Contains(t, err.Error(), tt.expectedError.Error())
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expectedReserved, reserved)
+				assert.Equal(t, tt.expectedExisting, existing)
+			}
+
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("there were unfulfilled expectations: %s", err)
+			}
+		})
+	}
+}
+
+func TestIdempotencyRepository_CompleteAndRelease(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	idempotencyRepo := repository.NewIdempotencyRepository(db)
+	ctx := context.Background()
+
+	expiresAt := time.Now().Add(time.Hour)
+	updateQuery := regexp.QuoteMeta(`UPDATE idempotency_keys SET status_code = $1, content_type = $2, response_body = $3, expires_at = $4 ` +
+		`WHERE key = $5 AND scope = $6 AND token = $7`)
+
+	mock.ExpectExec(updateQuery).
+		WithArgs(201, "application/json", []byte(`{}`), expiresAt, "key-1", "user:1", "token-1").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM idempotency_keys WHERE key = $1 AND scope = $2 AND token = $3`)).
+		WithArgs("key-2", "user:1", "token-2").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	assert.NoError(t, idempotencyRepo.Complete(ctx, "user:1", "key-1", "token-1", 201, "application/json", []byte(`{}`), expiresAt))
+	assert.NoError(t, idempotencyRepo.Release(ctx, "user:1", "key-2", "token-2"))
+
+	// The lease ran out and a retry reserved the key with another token.
+	mock.ExpectExec(updateQuery).
+		WithArgs(201, "application/json", []byte(`{}`), expiresAt, "key-1", "user:1", "token-1").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	err = idempotencyRepo.Complete(ctx, "user:1", "key-1", "token-1", 201, "application/json", []byte(`{}`), expiresAt)
+	assert.ErrorIs(t, err, model.ErrIdempotencyKeyReclaimed)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func TestIdempotencyRepository_DeleteExpired(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	idempotencyRepo := repository.NewIdempotencyRepository(db)
+	ctx := context.Background()
+	now := time.Now()
+	deleteQuery := regexp.QuoteMeta(`DELETE FROM idempotency_keys WHERE expires_at <= $1`)
+
+	mock.ExpectExec(deleteQuery).
+		WithArgs(now).
+		WillReturnResult(sqlmock.NewResult(0, 3))
+
+	deleted, err := idempotencyRepo.DeleteExpired(ctx, now)
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(3), deleted)
+
+	mock.ExpectExec(deleteQuery).
+		WithArgs(now).
+		WillReturnError(errors.New("db error"))
+
+	_, err = idempotencyRepo.DeleteExpired(ctx, now)
+
+	assert.EqualError(t, err, "failed to delete expired idempotency keys: db error")
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func TestMemoryIdempotencyRepository(t *testing.T) {
+	repo := repository.NewMemoryIdempotencyRepository()
+	ctx := context.Background()
+	now := time.Now()
+
+	record := model.IdempotencyRecord{Scope: "user:1", Key: "key-1", Token: "token-1", RequestHash: "hash", CreatedAt: now, ExpiresAt: now.Add(time.Minute)}
+
+	existing, reserved, err := repo.Reserve(ctx, record)
+	assert.NoError(t, err)
+	assert.True(t, reserved)
+	assert.Nil(t, existing)
+
+	existing, reserved, err = repo.Reserve(ctx, record)
+	assert.NoError(t, err)
+	assert.False(t, reserved)
+	assert.False(t, existing.Completed())
+
+	assert.NoError(t, repo.Complete(ctx, "user:1", "key-1", "token-1", 201, "application/json", []byte(`{}`), now.Add(time.Minute)))
+
+	existing, reserved, _ = repo.Reserve(ctx, record)
+	assert.False(t, reserved)
+	assert.Equal(t, 201, existing.StatusCode)
+
+	expired := record
+	expired.Token = "token-2"
+	expired.CreatedAt = now.Add(2 * time.Minute)
+	expired.ExpiresAt = now.Add(time.Hour)
+
+	_, reserved, _ = repo.Reserve(ctx, expired)
+	assert.True(t, reserved)
+
+	// The first reservation can no longer touch the record of the second.
+	err = repo.Complete(ctx, "user:1", "key-1", "token-1", 500, "", nil, now.Add(time.Minute))
+	assert.ErrorIs(t, err, model.ErrIdempotencyKeyReclaimed)
+	assert.NoError(t, repo.Release(ctx, "user:1", "key-1", "token-1"))
+
+	existing, reserved, _ = repo.Reserve(ctx, expired)
+	assert.False(t, reserved)
+	assert.Equal(t, "token-2", existing.Token)
+
+	assert.NoError(t, repo.Release(ctx, "user:1", "key-1", "token-2"))
+
+	_, reserved, _ = repo.Reserve(ctx, record)
+	assert.True(t, reserved)
+
+	deleted, err := repo.DeleteExpired(ctx, now.Add(2*time.Minute))
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), deleted)
+
+	_, reserved, _ = repo.Reserve(ctx, record)
+	assert.True(t, reserved)
+}
@@ -0,0 +1,242 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/kirillidk/pvz-service/internal/model"
+)
+
+const (
+	orderTableName = "orders"
+)
+
+var orderColumns = []string{
+	"id", "pvz_id", "recipient", "pickup_code_hash", "failed_attempts", "created_at", "issued_at", "code_regenerations",
+}
+
+type OrderRepositoryInterface interface {
+	CreateOrder(ctx context.Context, order model.Order) (*model.Order, error)
+	GetOrderForUpdate(ctx context.Context, orderID string) (*model.Order, error)
+	UpdatePickupCode(ctx context.Context, orderID, pickupCodeHash string) error
+	IncrementFailedAttempts(ctx context.Context, orderID string) (int, error)
+	MarkOrderIssued(ctx context.Context, orderID string, issuedAt time.Time) error
+}
+
+type OrderRepository struct {
+	db   *sql.DB
+	psql sq.StatementBuilderType
+}
+
+func NewOrderRepository(db *sql.DB) *OrderRepository {
+	return &OrderRepository{
+		db:   db,
+		psql: sq.StatementBuilder.PlaceholderFormat(sq.Dollar),
+	}
+}
+
+// CreateOrder inserts the order and attaches its products to it. A product
+// that already belongs to another order fails the whole call with
+// model.ErrProductInOrder, so it should be run within a transaction.
+func (r *OrderRepository) CreateOrder(ctx context.Context, order model.Order) (*model.Order, error) {
+	query, args, err := r.psql.
+		Insert(orderTableName).
+		Columns(orderColumns[1:]...).
+		Values(order.PVZID, order.Recipient, order.PickupCodeHash, 0, order.CreatedAt, nil, 0).
+		Suffix("RETURNING " + columnList(orderColumns)).
+		ToSql()
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to build sql query: %w", err)
+	}
+
+	querier := getQuerier(ctx, r.db)
+
+	created, err := scanOrder(querier.QueryRowContext(ctx, query, args...))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create order: %w", err)
+	}
+
+	query, args, err = r.psql.
+		Update(productTableName).
+		Set("order_id", created.ID).
+		Where(sq.Eq{"id": order.ProductIDs}).
+		Where(sq.Eq{"order_id": nil}).
+		ToSql()
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to build sql query: %w", err)
+	}
+
+	result, err := querier.ExecContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to attach products to order: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected != int64(len(order.ProductIDs)) {
+		return nil, model.ErrProductInOrder
+	}
+
+	created.ProductIDs = order.ProductIDs
+
+	return created, nil
+}
+
+// GetOrderForUpdate returns the order with its product IDs and locks the
+// order row until the end of the surrounding transaction.
+func (r *OrderRepository) GetOrderForUpdate(ctx context.Context, orderID string) (*model.Order, error) {
+	query, args, err := r.psql.
+		Select(orderColumns...).
+		From(orderTableName).
+		Where(sq.Eq{"id": orderID}).
+		Suffix("FOR UPDATE").
+		ToSql()
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to build sql query: %w", err)
+	}
+
+	querier := getQuerier(ctx, r.db)
+
+	order, err := scanOrder(querier.QueryRowContext(ctx, query, args...))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, model.ErrOrderNotFound
+		}
+		return nil, fmt.Errorf("failed to get order: %w", err)
+	}
+
+	query, args, err = r.psql.
+		Select("id").
+		From(productTableName).
+		Where(sq.Eq{"order_id": orderID}).
+		OrderBy("date_time ASC").
+		ToSql()
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to build sql query: %w", err)
+	}
+
+	rows, err := querier.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query order products: %w", err)
+	}
+	defer rows.Close()
+
+	order.ProductIDs = []string{}
+	for rows.Next() {
+		var productID string
+		if err := rows.Scan(&productID); err != nil {
+			return nil, fmt.Errorf("failed to scan order product row: %w", err)
+		}
+		order.ProductIDs = append(order.ProductIDs, productID)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating order product rows: %w", err)
+	}
+
+	return order, nil
+}
+
+// UpdatePickupCode replaces the pickup code of the order and resets its
+// failed attempts.
+func (r *OrderRepository) UpdatePickupCode(ctx context.Context, orderID, pickupCodeHash string) error {
+	query, args, err := r.psql.
+		Update(orderTableName).
+		Set("pickup_code_hash", pickupCodeHash).
+		Set("failed_attempts", 0).
+		Set("code_regenerations", sq.Expr("code_regenerations + 1")).
+		Where(sq.Eq{"id": orderID}).
+		ToSql()
+
+	if err != nil {
+		return fmt.Errorf("failed to build sql query: %w", err)
+	}
+
+	return r.execOrderUpdate(ctx, query, args)
+}
+
+// IncrementFailedAttempts counts a wrong pickup code and returns the new
+// number of failed attempts.
+func (r *OrderRepository) IncrementFailedAttempts(ctx context.Context, orderID string) (int, error) {
+	query, args, err := r.psql.
+		Update(orderTableName).
+		Set("failed_attempts", sq.Expr("failed_attempts + 1")).
+		Where(sq.Eq{"id": orderID}).
+		Suffix("RETURNING failed_attempts").
+		ToSql()
+
+	if err != nil {
+		return 0, fmt.Errorf("failed to build sql query: %w", err)
+	}
+
+	var failedAttempts int
+	if err := getQuerier(ctx, r.db).QueryRowContext(ctx, query, args...).Scan(&failedAttempts); err != nil {
+		if err == sql.ErrNoRows {
+			return 0, model.ErrOrderNotFound
+		}
+		return 0, fmt.Errorf("failed to update order: %w", err)
+	}
+
+	return failedAttempts, nil
+}
+
+func (r *OrderRepository) MarkOrderIssued(ctx context.Context, orderID string, issuedAt time.Time) error {
+	query, args, err := r.psql.
+		Update(orderTableName).
+		Set("issued_at", issuedAt).
+		Where(sq.Eq{"id": orderID}).
+		ToSql()
+
+	if err != nil {
+		return fmt.Errorf("failed to build sql query: %w", err)
+	}
+
+	return r.execOrderUpdate(ctx, query, args)
+}
+
+func (r *OrderRepository) execOrderUpdate(ctx context.Context, query string, args []any) error {
+	result, err := getQuerier(ctx, r.db).ExecContext(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("failed to update order: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return model.ErrOrderNotFound
+	}
+
+	return nil
+}
+
+func scanOrder(row rowScanner) (*model.Order, error) {
+	var (
+		order    model.Order
+		issuedAt sql.NullTime
+	)
+
+	err := row.Scan(
+		&order.ID, &order.PVZID, &order.Recipient, &order.PickupCodeHash,
+		&order.FailedAttempts, &order.CreatedAt, &issuedAt, &order.CodeRegenerations,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	order.IssuedAt = nullTimePtr(issuedAt)
+
+	return &order, nil
+}
@@ -0,0 +1,270 @@
+package repository_test
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/kirillidk/pvz-service/internal/model"
+	"github.com/kirillidk/pvz-service/internal/repository"
+	"github.com/stretchr/testify/assert"
+)
+
+var orderRowColumns = []string{"id", "pvz_id", "recipient", "pickup_code_hash", "failed_attempts", "created_at", "issued_at", "code_regenerations"}
+
+func TestOrderRepository_CreateOrder(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	orderRepo := repository.NewOrderRepository(db)
+	ctx := context.Background()
+	now := time.Now()
+
+	orderID := "e0eebc99-9c0b-4ef8-bb6d-6bb9bd380a11"
+	order := model.Order{
+		PVZID:          "b0eebc99-9c0b-4ef8-bb6d-6bb9bd380a11",
+		Recipient:      "+79990000000",
+		ProductIDs:     []string{"d0eebc99-9c0b-4ef8-bb6d-6bb9bd380a11", "d1eebc99-9c0b-4ef8-bb6d-6bb9bd380a11"},
+		CreatedAt:      now,
+		PickupCodeHash: "hash",
+	}
+
+	insertQuery := regexp.QuoteMeta(`INSERT INTO orders (pvz_id,recipient,pickup_code_hash,failed_attempts,created_at,issued_at,code_regenerations) ` +
+		`VALUES ($1,$2,$3,$4,$5,$6,$7) RETURNING id, pvz_id, recipient, pickup_code_hash, failed_attempts, created_at, issued_at, code_regenerations`)
+	attachQuery := regexp.QuoteMeta(`UPDATE products SET order_id = $1 WHERE id IN ($2,$3) AND order_id IS NULL`)
+
+	tests := []struct {
+		name          string
+		mockBehavior  func()
+		expectedValue *model.Order
+		expectedError error
+	}{
+		{
+			name: "Success",
+			mockBehavior: func() {
+				mock.ExpectQuery(insertQuery).
+					WithArgs(order.PVZID, order.Recipient, order.PickupCodeHash, 0, now, nil, 0).
+					WillReturnRows(sqlmock.NewRows(orderRowColumns).
+						AddRow(orderID, order.PVZID, order.Recipient, order.PickupCodeHash, 0, now, nil, 0))
+				mock.ExpectExec(attachQuery).
+					WithArgs(orderID, order.ProductIDs[0], order.ProductIDs[1]).
+					WillReturnResult(sqlmock.NewResult(0, 2))
+			},
+			expectedValue: &model.Order{
+				ID:             orderID,
+				PVZID:          order.PVZID,
+				Recipient:      order.Recipient,
+				ProductIDs:     order.ProductIDs,
+				CreatedAt:      now,
+				PickupCodeHash: order.PickupCodeHash,
+			},
+		},
+		{
+			name: "Product Already In Order",
+			mockBehavior: func() {
+				mock.ExpectQuery(insertQuery).
+					WillReturnRows(sqlmock.NewRows(orderRowColumns).
+						AddRow(orderID, order.PVZID, order.Recipient, order.PickupCodeHash, 0, now, nil, 0))
+				mock.ExpectExec(attachQuery).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+			expectedError: model.ErrProductInOrder,
+		},
+		{
+			name: "DB Error",
+			mockBehavior: func() {
+				mock.ExpectQuery(insertQuery).
+					WillReturnError(errors.New("db error"))
+			},
+			expectedError: errors.New("failed to create order: db error"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockBehavior()
+
+			created, err := orderRepo.CreateOrder(ctx, order)
+
+			if tt.expectedError != nil {
+				assert.Error(t, err)
+				assert.Contains(t, err.Error(), tt.expectedError.Error())
+				assert.Nil(t, created)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expectedValue, created)
+			}
+
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("there were unfulfilled expectations: %s", err)
+			}
+		})
+	}
+}
+
+func TestOrderRepository_GetOrderForUpdate(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	orderRepo := repository.NewOrderRepository(db)
+	ctx := context.Background()
+	now := time.Now()
+
+	orderID := "e0eebc99-9c0b-4ef8-bb6d-6bb9bd380a11"
+	selectQuery := regexp.QuoteMeta(`SELECT id, pvz_id, recipient, pickup_code_hash, failed_attempts, created_at, issued_at, code_regenerations FROM orders WHERE id = $1 FOR UPDATE`)
+	productsQuery := regexp.QuoteMeta(`SELECT id FROM products WHERE order_id = $1 ORDER BY date_time ASC`)
+
+	tests := []struct {
+		name          string
+		mockBehavior  func()
+		expectedValue *model.Order
+		expectedError error
+	}{
+		{
+			name: "Success",
+			mockBehavior: func() {
+				mock.ExpectQuery(selectQuery).
+					WithArgs(orderID).
+					WillReturnRows(sqlmock.NewRows(orderRowColumns).
+						AddRow(orderID, "b0eebc99-9c0b-4ef8-bb6d-6bb9bd380a11", "+79990000000", "hash", 2, now, nil, 1))
+				mock.ExpectQuery(productsQuery).
+					WithArgs(orderID).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow("d0eebc99-9c0b-4ef8-bb6d-6bb9bd380a11"))
+			},
+			expectedValue: &model.Order{
+				ID:                orderID,
+				PVZID:             "b0eebc99-9c0b-4ef8-bb6d-6bb9bd380a11",
+				Recipient:         "+79990000000",
+				ProductIDs:        []string{"d0eebc99-9c0b-4ef8-bb6d-6bb9bd380a11"},
+				FailedAttempts:    2,
+				CreatedAt:         now,
+				CodeRegenerations: 1,
+				PickupCodeHash:    "hash",
+			},
+		},
+		{
+			name: "Not Found",
+			mockBehavior: func() {
+				mock.ExpectQuery(selectQuery).
+					WithArgs(orderID).
+					WillReturnError(sql.ErrNoRows)
+			},
+			expectedError: model.ErrOrderNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockBehavior()
+
+			order, err := orderRepo.GetOrderForUpdate(ctx, orderID)
+
+			if tt.expectedError != nil {
+				assert.ErrorIs(t, err, tt.expectedError)
+				assert.Nil(t, order)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expectedValue, order)
+			}
+
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("there were unfulfilled expectations: %s", err)
+			}
+		})
+	}
+}
+
+func TestOrderRepository_UpdatePickupCode(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	orderRepo := repository.NewOrderRepository(db)
+	ctx := context.Background()
+
+	orderID := "e0eebc99-9c0b-4ef8-bb6d-6bb9bd380a11"
+	updateQuery := regexp.QuoteMeta(`UPDATE orders SET pickup_code_hash = $1, failed_attempts = $2, code_regenerations = code_regenerations + 1 WHERE id = $3`)
+
+	t.Run("Success", func(t *testing.T) {
+		mock.ExpectExec(updateQuery).
+			WithArgs("hash", 0, orderID).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+
+		assert.NoError(t, orderRepo.UpdatePickupCode(ctx, orderID, "hash"))
+	})
+
+	t.Run("Not Found", func(t *testing.T) {
+		mock.ExpectExec(updateQuery).
+			WithArgs("hash", 0, orderID).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+
+		assert.ErrorIs(t, orderRepo.UpdatePickupCode(ctx, orderID, "hash"), model.ErrOrderNotFound)
+	})
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func TestOrderRepository_IncrementFailedAttempts(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	orderRepo := repository.NewOrderRepository(db)
+	ctx := context.Background()
+
+	orderID := "e0eebc99-9c0b-4ef8-bb6d-6bb9bd380a11"
+	updateQuery := regexp.QuoteMeta(`UPDATE orders SET failed_attempts = failed_attempts + 1 WHERE id = $1 RETURNING failed_attempts`)
+
+	mock.ExpectQuery(updateQuery).
+		WithArgs(orderID).
+		WillReturnRows(sqlmock.NewRows([]string{"failed_attempts"}).AddRow(3))
+
+	failedAttempts, err := orderRepo.IncrementFailedAttempts(ctx, orderID)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 3, failedAttempts)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func TestOrderRepository_MarkOrderIssued(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	orderRepo := repository.NewOrderRepository(db)
+	ctx := context.Background()
+	now := time.Now()
+
+	orderID := "e0eebc99-9c0b-4ef8-bb6d-6bb9bd380a11"
+
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE orders SET issued_at = $1 WHERE id = $2`)).
+		WithArgs(now, orderID).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	assert.NoError(t, orderRepo.MarkOrderIssued(ctx, orderID, now))
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
@@ -3,10 +3,13 @@ package repository
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
+	"sort"
 	"time"
 
 	sq "github.com/Masterminds/squirrel"
+	"github.com/kirillidk/pvz-service/internal/dto"
 	"github.com/kirillidk/pvz-service/internal/model"
 )
 
@@ -14,11 +17,26 @@ const (
 	productTableName = "products"
 )
 
+// productProcessedExpr holds for products pr that have moved on from their
+// reception: stored, put in an order, issued, returned or transferred.
+const productProcessedExpr = "(pr.status <> 'received' OR pr.order_id IS NOT NULL OR pr.transfer_id IS NOT NULL OR " +
+	"EXISTS (SELECT 1 FROM " + transferProductTableName + " tp WHERE tp.product_id = pr.id))"
+
+var productColumns = []string{"id", "date_time", "type", "reception_id", "serial_number", "barcode", "status", "cell_id", "condition", "condition_note"}
+
 type ProductRepositoryInterface interface {
-	CreateProduct(ctx context.Context, productType string, receptionID string) (*model.Product, error)
+	CreateProduct(ctx context.Context, receptionID string, attrs dto.ProductAttributes) (*model.Product, error)
+	CreateProducts(ctx context.Context, receptionID string, items []dto.ProductAttributes) ([]model.Product, error)
 	GetLastProductInReception(ctx context.Context, receptionID string) (*model.Product, error)
 	DeleteProduct(ctx context.Context, productID string) error
 	GetProductsByReceptionID(ctx context.Context, receptionID string) ([]model.Product, error)
+	GetProductsByReceptionIDInInsertionOrder(ctx context.Context, receptionID string) ([]model.Product, error)
+	GetProductsByBarcodes(ctx context.Context, barcodes []string) ([]model.ProductLocation, error)
+	GetProductLocation(ctx context.Context, productID string) (*model.ProductLocation, error)
+	GetProductLocationForUpdate(ctx context.Context, productID string) (*model.ProductLocation, error)
+	UpdateProductStatus(ctx context.Context, productID string, status model.ProductStatus) (*model.Product, error)
+	UpdateProductCell(ctx context.Context, productID, cellID string) (*model.Product, error)
+	UpdateProductCondition(ctx context.Context, productID string, condition model.ProductCondition, note string) (*model.Product, error)
 }
 
 type ProductRepository struct {
@@ -33,35 +51,98 @@ func NewProductRepository(db *sql.DB) *ProductRepository {
 	}
 }
 
-func (r *ProductRepository) CreateProduct(ctx context.Context, productType string, receptionID string) (*model.Product, error) {
-	dateTime := time.Now()
+func (r *ProductRepository) CreateProduct(ctx context.Context, receptionID string, attrs dto.ProductAttributes) (*model.Product, error) {
+	dateTime := time.Now().UTC()
 
 	query, args, err := r.psql.
 		Insert(productTableName).
-		Columns("date_time", "type", "reception_id").
-		Values(dateTime, productType, receptionID).
-		Suffix("RETURNING id, date_time, type, reception_id").
+		Columns("date_time", "type", "reception_id", "serial_number", "barcode", "cell_id").
+		Values(
+			dateTime, attrs.Type, receptionID,
+			nullString(attrs.SerialNumber), nullString(attrs.Barcode), nullString(attrs.CellID),
+		).
+		Suffix("RETURNING " + columnList(productColumns)).
 		ToSql()
 
 	if err != nil {
 		return nil, fmt.Errorf("failed to build sql query: %w", err)
 	}
 
-	var product model.Product
-	err = r.db.QueryRowContext(ctx, query, args...).Scan(&product.ID, &product.DateTime, &product.Type, &product.ReceptionID)
+	product, err := scanProduct(getQuerier(ctx, r.db).QueryRowContext(ctx, query, args...))
 	if err != nil {
+		if isPQError(err, pqUniqueViolation) {
+			return nil, model.ErrDuplicateBarcode
+		}
 		return nil, fmt.Errorf("failed to create product: %w", err)
 	}
 
-	return &product, nil
+	return product, nil
+}
+
+// CreateProducts inserts items into the reception with a single statement
+// and returns them in the order given. The rows of a statement share
+// date_time; their seq values follow the order of items, so ordering by
+// seq, and with it deleting the last product, follows it too.
+func (r *ProductRepository) CreateProducts(ctx context.Context, receptionID string, items []dto.ProductAttributes) ([]model.Product, error) {
+	dateTime := time.Now().UTC()
+
+	queryBuilder := r.psql.
+		Insert(productTableName).
+		Columns("date_time", "type", "reception_id", "serial_number", "barcode", "cell_id")
+
+	for _, item := range items {
+		queryBuilder = queryBuilder.Values(
+			dateTime, item.Type, receptionID,
+			nullString(item.SerialNumber), nullString(item.Barcode), nullString(item.CellID),
+		)
+	}
+
+	query, args, err := queryBuilder.
+		Suffix("RETURNING " + columnList(productColumns) + ", seq").
+		ToSql()
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to build sql query: %w", err)
+	}
+
+	rows, err := getQuerier(ctx, r.db).QueryContext(ctx, query, args...)
+	if err != nil {
+		if isPQError(err, pqUniqueViolation) {
+			return nil, model.ErrDuplicateBarcode
+		}
+		return nil, fmt.Errorf("failed to create products: %w", err)
+	}
+	defer rows.Close()
+
+	products := make([]model.Product, 0, len(items))
+	seqs := make(map[string]int64, len(items))
+	for rows.Next() {
+		var seq int64
+		product, err := scanProduct(rows, &seq)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan product row: %w", err)
+		}
+		products = append(products, *product)
+		seqs[product.ID] = seq
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating product rows: %w", err)
+	}
+
+	sort.Slice(products, func(i, j int) bool {
+		return seqs[products[i].ID] < seqs[products[j].ID]
+	})
+
+	return products, nil
 }
 
 func (r *ProductRepository) GetLastProductInReception(ctx context.Context, receptionID string) (*model.Product, error) {
 	query, args, err := r.psql.
-		Select("id", "date_time", "type", "reception_id").
+		Select(productColumns...).
 		From(productTableName).
 		Where(sq.Eq{"reception_id": receptionID}).
-		OrderBy("date_time DESC").
+		OrderBy("seq DESC").
 		Limit(1).
 		ToSql()
 
@@ -69,8 +150,7 @@ func (r *ProductRepository) GetLastProductInReception(ctx context.Context, recep
 		return nil, fmt.Errorf("failed to build sql query: %w", err)
 	}
 
-	var product model.Product
-	err = r.db.QueryRowContext(ctx, query, args...).Scan(&product.ID, &product.DateTime, &product.Type, &product.ReceptionID)
+	product, err := scanProduct(getQuerier(ctx, r.db).QueryRowContext(ctx, query, args...))
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return nil, fmt.Errorf("no products found for this reception")
@@ -78,20 +158,24 @@ func (r *ProductRepository) GetLastProductInReception(ctx context.Context, recep
 		return nil, fmt.Errorf("failed to get last product: %w", err)
 	}
 
-	return &product, nil
+	return product, nil
 }
 
+// DeleteProduct deletes a product scanned by mistake. Products that have
+// moved on from their reception are part of the history of the PVZ and
+// are refused with model.ErrProductNotDeletable.
 func (r *ProductRepository) DeleteProduct(ctx context.Context, productID string) error {
 	query, args, err := r.psql.
-		Delete(productTableName).
-		Where(sq.Eq{"id": productID}).
+		Delete(productTableName + " pr").
+		Where(sq.Eq{"pr.id": productID}).
+		Where("NOT " + productProcessedExpr).
 		ToSql()
 
 	if err != nil {
 		return fmt.Errorf("failed to build sql query: %w", err)
 	}
 
-	result, err := r.db.ExecContext(ctx, query, args...)
+	result, err := getQuerier(ctx, r.db).ExecContext(ctx, query, args...)
 	if err != nil {
 		return fmt.Errorf("failed to delete product: %w", err)
 	}
@@ -102,25 +186,43 @@ func (r *ProductRepository) DeleteProduct(ctx context.Context, productID string)
 	}
 
 	if rowsAffected == 0 {
-		return fmt.Errorf("product not found")
+		if _, err := r.GetProductLocation(ctx, productID); err != nil {
+			if errors.Is(err, model.ErrProductNotFound) {
+				return fmt.Errorf("product not found")
+			}
+			return err
+		}
+		return model.ErrProductNotDeletable
 	}
 
 	return nil
 }
 
+// GetProductsByReceptionID lists the products of a reception, the last
+// scanned first, in the order deleting the last product removes them.
 func (r *ProductRepository) GetProductsByReceptionID(ctx context.Context, receptionID string) ([]model.Product, error) {
+	return r.getProductsByReceptionID(ctx, receptionID, "seq DESC")
+}
+
+// GetProductsByReceptionIDInInsertionOrder lists the products of a reception
+// in the order they were scanned, oldest first.
+func (r *ProductRepository) GetProductsByReceptionIDInInsertionOrder(ctx context.Context, receptionID string) ([]model.Product, error) {
+	return r.getProductsByReceptionID(ctx, receptionID, "seq ASC")
+}
+
+func (r *ProductRepository) getProductsByReceptionID(ctx context.Context, receptionID, orderBy string) ([]model.Product, error) {
 	query, args, err := r.psql.
-		Select("id", "date_time", "type", "reception_id").
+		Select(productColumns...).
 		From(productTableName).
 		Where(sq.Eq{"reception_id": receptionID}).
-		OrderBy("date_time DESC").
+		OrderBy(orderBy).
 		ToSql()
 
 	if err != nil {
 		return nil, fmt.Errorf("failed to build sql query: %w", err)
 	}
 
-	rows, err := r.db.QueryContext(ctx, query, args...)
+	rows, err := getQuerier(ctx, r.db).QueryContext(ctx, query, args...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to query products: %w", err)
 	}
@@ -128,11 +230,11 @@ func (r *ProductRepository) GetProductsByReceptionID(ctx context.Context, recept
 
 	var products []model.Product
 	for rows.Next() {
-		var product model.Product
-		if err := rows.Scan(&product.ID, &product.DateTime, &product.Type, &product.ReceptionID); err != nil {
+		product, err := scanProduct(rows)
+		if err != nil {
 			return nil, fmt.Errorf("failed to scan product row: %w", err)
 		}
-		products = append(products, product)
+		products = append(products, *product)
 	}
 
 	if err := rows.Err(); err != nil {
@@ -141,3 +243,206 @@ func (r *ProductRepository) GetProductsByReceptionID(ctx context.Context, recept
 
 	return products, nil
 }
+
+// GetProductsByBarcodes finds products with any of the barcodes in all
+// PVZs, the most recently received first.
+func (r *ProductRepository) GetProductsByBarcodes(ctx context.Context, barcodes []string) ([]model.ProductLocation, error) {
+	query, args, err := r.selectProductLocations().
+		Where(sq.Eq{"pr.barcode": barcodes}).
+		OrderBy("pr.seq DESC").
+		ToSql()
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to build sql query: %w", err)
+	}
+
+	rows, err := getQuerier(ctx, r.db).QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query products: %w", err)
+	}
+	defer rows.Close()
+
+	locations := []model.ProductLocation{}
+	for rows.Next() {
+		location, err := scanProductLocation(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan product row: %w", err)
+		}
+		locations = append(locations, *location)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating product rows: %w", err)
+	}
+
+	return locations, nil
+}
+
+// GetProductLocationForUpdate locks the product row until the end of the
+// current transaction, so that concurrent status changes do not interleave.
+func (r *ProductRepository) GetProductLocationForUpdate(ctx context.Context, productID string) (*model.ProductLocation, error) {
+	return r.getProductLocation(ctx, productID, true)
+}
+
+func (r *ProductRepository) GetProductLocation(ctx context.Context, productID string) (*model.ProductLocation, error) {
+	return r.getProductLocation(ctx, productID, false)
+}
+
+func (r *ProductRepository) getProductLocation(ctx context.Context, productID string, forUpdate bool) (*model.ProductLocation, error) {
+	queryBuilder := r.selectProductLocations().
+		Where(sq.Eq{"pr.id": productID})
+
+	if forUpdate {
+		queryBuilder = queryBuilder.Suffix("FOR UPDATE OF pr")
+	}
+
+	query, args, err := queryBuilder.ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build sql query: %w", err)
+	}
+
+	location, err := scanProductLocation(getQuerier(ctx, r.db).QueryRowContext(ctx, query, args...))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, model.ErrProductNotFound
+		}
+		return nil, fmt.Errorf("failed to get product: %w", err)
+	}
+
+	return location, nil
+}
+
+func (r *ProductRepository) UpdateProductStatus(ctx context.Context, productID string, status model.ProductStatus) (*model.Product, error) {
+	query, args, err := r.psql.
+		Update(productTableName).
+		Set("status", status).
+		Where(sq.Eq{"id": productID}).
+		Suffix("RETURNING " + columnList(productColumns)).
+		ToSql()
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to build sql query: %w", err)
+	}
+
+	product, err := scanProduct(getQuerier(ctx, r.db).QueryRowContext(ctx, query, args...))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, model.ErrProductNotFound
+		}
+		return nil, fmt.Errorf("failed to update product status: %w", err)
+	}
+
+	return product, nil
+}
+
+func (r *ProductRepository) UpdateProductCell(ctx context.Context, productID, cellID string) (*model.Product, error) {
+	query, args, err := r.psql.
+		Update(productTableName).
+		Set("cell_id", cellID).
+		Where(sq.Eq{"id": productID}).
+		Suffix("RETURNING " + columnList(productColumns)).
+		ToSql()
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to build sql query: %w", err)
+	}
+
+	product, err := scanProduct(getQuerier(ctx, r.db).QueryRowContext(ctx, query, args...))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, model.ErrProductNotFound
+		}
+		return nil, fmt.Errorf("failed to update product cell: %w", err)
+	}
+
+	return product, nil
+}
+
+func (r *ProductRepository) UpdateProductCondition(
+	ctx context.Context,
+	productID string,
+	condition model.ProductCondition,
+	note string,
+) (*model.Product, error) {
+	query, args, err := r.psql.
+		Update(productTableName).
+		Set("condition", condition).
+		Set("condition_note", nullString(note)).
+		Where(sq.Eq{"id": productID}).
+		Suffix("RETURNING " + columnList(productColumns)).
+		ToSql()
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to build sql query: %w", err)
+	}
+
+	product, err := scanProduct(getQuerier(ctx, r.db).QueryRowContext(ctx, query, args...))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, model.ErrProductNotFound
+		}
+		return nil, fmt.Errorf("failed to update product condition: %w", err)
+	}
+
+	return product, nil
+}
+
+// selectProductLocations selects products with the PVZ they are kept at:
+// the PVZ of the reception they arrived with by their last transfer, or of
+// the reception they were first received in.
+func (r *ProductRepository) selectProductLocations() sq.SelectBuilder {
+	return r.psql.
+		Select(prefixColumns("pr", productColumns)...).
+		Columns("r.pvz_id", "p.city", "r.status", "pr.order_id", "pr.transfer_id").
+		From(productTableName + " pr").
+		LeftJoin(productArrivalJoin).
+		Join(productReceptionJoin).
+		Join(pvzTableName + " p ON p.id = r.pvz_id")
+}
+
+func scanProductLocation(row rowScanner) (*model.ProductLocation, error) {
+	var (
+		location   model.ProductLocation
+		orderID    sql.NullString
+		transferID sql.NullString
+	)
+
+	product, err := scanProduct(row, &location.PVZID, &location.City, &location.ReceptionStatus, &orderID, &transferID)
+	if err != nil {
+		return nil, err
+	}
+
+	location.Product = *product
+	location.OrderID = orderID.String
+	location.TransferID = transferID.String
+
+	return &location, nil
+}
+
+// scanProduct scans the product columns followed by extra, which receives
+// any columns selected after them.
+func scanProduct(row rowScanner, extra ...any) (*model.Product, error) {
+	var (
+		product      model.Product
+		serialNumber sql.NullString
+		barcode      sql.NullString
+		cellID       sql.NullString
+		note         sql.NullString
+	)
+
+	dest := []any{
+		&product.ID, &product.DateTime, &product.Type, &product.ReceptionID, &serialNumber, &barcode, &product.Status, &cellID,
+		&product.Condition, &note,
+	}
+
+	if err := row.Scan(append(dest, extra...)...); err != nil {
+		return nil, err
+	}
+
+	product.SerialNumber = serialNumber.String
+	product.Barcode = barcode.String
+	product.CellID = cellID.String
+	product.ConditionNote = note.String
+
+	return &product, nil
+}
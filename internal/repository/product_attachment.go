@@ -0,0 +1,136 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/kirillidk/pvz-service/internal/model"
+)
+
+const (
+	productAttachmentTableName = "product_attachments"
+)
+
+var productAttachmentColumns = []string{"id", "product_id", "file_name", "content_type", "size", "storage_key", "created_at"}
+
+type ProductAttachmentRepositoryInterface interface {
+	CreateProductAttachment(ctx context.Context, attachment model.ProductAttachment) (*model.ProductAttachment, error)
+	GetProductAttachments(ctx context.Context, productID string) ([]model.ProductAttachment, error)
+	GetProductAttachment(ctx context.Context, productID, attachmentID string) (*model.ProductAttachment, error)
+}
+
+type ProductAttachmentRepository struct {
+	db   *sql.DB
+	psql sq.StatementBuilderType
+}
+
+func NewProductAttachmentRepository(db *sql.DB) *ProductAttachmentRepository {
+	return &ProductAttachmentRepository{
+		db:   db,
+		psql: sq.StatementBuilder.PlaceholderFormat(sq.Dollar),
+	}
+}
+
+func (r *ProductAttachmentRepository) CreateProductAttachment(
+	ctx context.Context,
+	attachment model.ProductAttachment,
+) (*model.ProductAttachment, error) {
+	query, args, err := r.psql.
+		Insert(productAttachmentTableName).
+		Columns(productAttachmentColumns[1:]...).
+		Values(
+			attachment.ProductID, attachment.FileName, attachment.ContentType, attachment.Size,
+			attachment.StorageKey, attachment.CreatedAt,
+		).
+		Suffix("RETURNING " + columnList(productAttachmentColumns)).
+		ToSql()
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to build sql query: %w", err)
+	}
+
+	created, err := scanProductAttachment(getQuerier(ctx, r.db).QueryRowContext(ctx, query, args...))
+	if err != nil {
+		if isPQError(err, pqForeignKeyViolation) {
+			return nil, model.ErrProductNotFound
+		}
+		return nil, fmt.Errorf("failed to create product attachment: %w", err)
+	}
+
+	return created, nil
+}
+
+func (r *ProductAttachmentRepository) GetProductAttachments(ctx context.Context, productID string) ([]model.ProductAttachment, error) {
+	query, args, err := r.psql.
+		Select(productAttachmentColumns...).
+		From(productAttachmentTableName).
+		Where(sq.Eq{"product_id": productID}).
+		OrderBy("created_at", "id").
+		ToSql()
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to build sql query: %w", err)
+	}
+
+	rows, err := getQuerier(ctx, r.db).QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query product attachments: %w", err)
+	}
+	defer rows.Close()
+
+	attachments := []model.ProductAttachment{}
+	for rows.Next() {
+		attachment, err := scanProductAttachment(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan product attachment row: %w", err)
+		}
+		attachments = append(attachments, *attachment)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating product attachment rows: %w", err)
+	}
+
+	return attachments, nil
+}
+
+func (r *ProductAttachmentRepository) GetProductAttachment(
+	ctx context.Context,
+	productID, attachmentID string,
+) (*model.ProductAttachment, error) {
+	query, args, err := r.psql.
+		Select(productAttachmentColumns...).
+		From(productAttachmentTableName).
+		Where(sq.Eq{"id": attachmentID, "product_id": productID}).
+		ToSql()
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to build sql query: %w", err)
+	}
+
+	attachment, err := scanProductAttachment(getQuerier(ctx, r.db).QueryRowContext(ctx, query, args...))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, model.ErrAttachmentNotFound
+		}
+		return nil, fmt.Errorf("failed to get product attachment: %w", err)
+	}
+
+	return attachment, nil
+}
+
+func scanProductAttachment(row rowScanner) (*model.ProductAttachment, error) {
+	var attachment model.ProductAttachment
+
+	err := row.Scan(
+		&attachment.ID, &attachment.ProductID, &attachment.FileName, &attachment.ContentType,
+		&attachment.Size, &attachment.StorageKey, &attachment.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &attachment, nil
+}
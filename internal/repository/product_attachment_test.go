@@ -0,0 +1,171 @@
+package repository_test
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/kirillidk/pvz-service/internal/model"
+	"github.com/kirillidk/pvz-service/internal/repository"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+)
+
+var attachmentRowColumns = []string{"id", "product_id", "file_name", "content_type", "size", "storage_key", "created_at"}
+
+func TestProductAttachmentRepository_CreateProductAttachment(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	attachmentRepo := repository.NewProductAttachmentRepository(db)
+	ctx := context.Background()
+	testTime := time.Now()
+
+	attachment := model.ProductAttachment{
+		ProductID:   "d0eebc99-9c0b-4ef8-bb6d-6bb9bd380a11",
+		FileName:    "box.jpg",
+		ContentType: "image/jpeg",
+		Size:        2048,
+		StorageKey:  "d0eebc99-9c0b-4ef8-bb6d-6bb9bd380a11/3f2a.jpg",
+		CreatedAt:   testTime,
+	}
+	insertQuery := regexp.QuoteMeta(`INSERT INTO product_attachments (product_id,file_name,content_type,size,storage_key,created_at) ` +
+		`VALUES ($1,$2,$3,$4,$5,$6) RETURNING id, product_id, file_name, content_type, size, storage_key, created_at`)
+
+	t.Run("Success", func(t *testing.T) {
+		mock.ExpectQuery(insertQuery).
+			WithArgs(attachment.ProductID, attachment.FileName, attachment.ContentType, attachment.Size, attachment.StorageKey, testTime).
+			WillReturnRows(sqlmock.NewRows(attachmentRowColumns).
+				AddRow("a0eebc99-9c0b-4ef8-bb6d-6bb9bd380a11", attachment.ProductID, attachment.FileName,
+					attachment.ContentType, attachment.Size, attachment.StorageKey, testTime))
+
+		created, err := attachmentRepo.CreateProductAttachment(ctx, attachment)
+
+		expected := attachment
+		expected.ID = "a0eebc99-9c0b-4ef8-bb6d-6bb9bd380a11"
+
+		assert.NoError(t, err)
+		assert.Equal(t, &expected, created)
+	})
+
+	t.Run("Product Not Found", func(t *testing.T) {
+		mock.ExpectQuery(insertQuery).
+			WillReturnError(&pq.Error{Code: "23503"})
+
+		created, err := attachmentRepo.CreateProductAttachment(ctx, attachment)
+
+		assert.ErrorIs(t, err, model.ErrProductNotFound)
+		assert.Nil(t, created)
+	})
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func TestProductAttachmentRepository_GetProductAttachments(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	attachmentRepo := repository.NewProductAttachmentRepository(db)
+	ctx := context.Background()
+	testTime := time.Now()
+
+	productID := "d0eebc99-9c0b-4ef8-bb6d-6bb9bd380a11"
+	selectQuery := regexp.QuoteMeta(`SELECT id, product_id, file_name, content_type, size, storage_key, created_at ` +
+		`FROM product_attachments WHERE product_id = $1 ORDER BY created_at, id`)
+
+	t.Run("Success", func(t *testing.T) {
+		mock.ExpectQuery(selectQuery).
+			WithArgs(productID).
+			WillReturnRows(sqlmock.NewRows(attachmentRowColumns).
+				AddRow("a0eebc99-9c0b-4ef8-bb6d-6bb9bd380a11", productID, "box.jpg", "image/jpeg", 2048, productID+"/3f2a.jpg", testTime).
+				AddRow("a1eebc99-9c0b-4ef8-bb6d-6bb9bd380a11", productID, "seal.png", "image/png", 1024, productID+"/9c1b.png", testTime))
+
+		attachments, err := attachmentRepo.GetProductAttachments(ctx, productID)
+
+		assert.NoError(t, err)
+		assert.Len(t, attachments, 2)
+		assert.Equal(t, "seal.png", attachments[1].FileName)
+	})
+
+	t.Run("Empty", func(t *testing.T) {
+		mock.ExpectQuery(selectQuery).
+			WithArgs(productID).
+			WillReturnRows(sqlmock.NewRows(attachmentRowColumns))
+
+		attachments, err := attachmentRepo.GetProductAttachments(ctx, productID)
+
+		assert.NoError(t, err)
+		assert.Equal(t, []model.ProductAttachment{}, attachments)
+	})
+
+	t.Run("DB Error", func(t *testing.T) {
+		mock.ExpectQuery(selectQuery).
+			WithArgs(productID).
+			WillReturnError(errors.New("db error"))
+
+		attachments, err := attachmentRepo.GetProductAttachments(ctx, productID)
+
+		assert.EqualError(t, err, "failed to query product attachments: db error")
+		assert.Nil(t, attachments)
+	})
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func TestProductAttachmentRepository_GetProductAttachment(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	attachmentRepo := repository.NewProductAttachmentRepository(db)
+	ctx := context.Background()
+	testTime := time.Now()
+
+	productID := "d0eebc99-9c0b-4ef8-bb6d-6bb9bd380a11"
+	attachmentID := "a0eebc99-9c0b-4ef8-bb6d-6bb9bd380a11"
+	selectQuery := regexp.QuoteMeta(`SELECT id, product_id, file_name, content_type, size, storage_key, created_at ` +
+		`FROM product_attachments WHERE id = $1 AND product_id = $2`)
+
+	t.Run("Success", func(t *testing.T) {
+		mock.ExpectQuery(selectQuery).
+			WithArgs(attachmentID, productID).
+			WillReturnRows(sqlmock.NewRows(attachmentRowColumns).
+				AddRow(attachmentID, productID, "box.jpg", "image/jpeg", 2048, productID+"/3f2a.jpg", testTime))
+
+		attachment, err := attachmentRepo.GetProductAttachment(ctx, productID, attachmentID)
+
+		assert.NoError(t, err)
+		assert.Equal(t, productID+"/3f2a.jpg", attachment.StorageKey)
+	})
+
+	t.Run("Not Found", func(t *testing.T) {
+		mock.ExpectQuery(selectQuery).
+			WithArgs(attachmentID, productID).
+			WillReturnError(sql.ErrNoRows)
+
+		attachment, err := attachmentRepo.GetProductAttachment(ctx, productID, attachmentID)
+
+		assert.ErrorIs(t, err, model.ErrAttachmentNotFound)
+		assert.Nil(t, attachment)
+	})
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
@@ -0,0 +1,109 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/kirillidk/pvz-service/internal/model"
+)
+
+const (
+	productStatusHistoryTableName = "product_status_history"
+)
+
+var productStatusHistoryColumns = []string{
+	"id", "product_id", "from_status", "to_status", "changed_at", "actor_id", "actor_type", "actor_role",
+}
+
+type ProductStatusRepositoryInterface interface {
+	CreateProductStatusChange(ctx context.Context, change model.ProductStatusChange) (*model.ProductStatusChange, error)
+	GetProductStatusHistory(ctx context.Context, productID string) ([]model.ProductStatusChange, error)
+}
+
+type ProductStatusRepository struct {
+	db   *sql.DB
+	psql sq.StatementBuilderType
+}
+
+func NewProductStatusRepository(db *sql.DB) *ProductStatusRepository {
+	return &ProductStatusRepository{
+		db:   db,
+		psql: sq.StatementBuilder.PlaceholderFormat(sq.Dollar),
+	}
+}
+
+func (r *ProductStatusRepository) CreateProductStatusChange(
+	ctx context.Context,
+	change model.ProductStatusChange,
+) (*model.ProductStatusChange, error) {
+	query, args, err := r.psql.
+		Insert(productStatusHistoryTableName).
+		Columns(productStatusHistoryColumns[1:]...).
+		Values(
+			change.ProductID, change.FromStatus, change.ToStatus, change.ChangedAt,
+			nullString(change.Actor.ID), change.Actor.Type, nullString(string(change.Actor.Role)),
+		).
+		Suffix("RETURNING id").
+		ToSql()
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to build sql query: %w", err)
+	}
+
+	if err := getQuerier(ctx, r.db).QueryRowContext(ctx, query, args...).Scan(&change.ID); err != nil {
+		return nil, fmt.Errorf("failed to create product status change: %w", err)
+	}
+
+	return &change, nil
+}
+
+// GetProductStatusHistory returns the status changes of the product, the
+// oldest first.
+func (r *ProductStatusRepository) GetProductStatusHistory(ctx context.Context, productID string) ([]model.ProductStatusChange, error) {
+	query, args, err := r.psql.
+		Select(productStatusHistoryColumns...).
+		From(productStatusHistoryTableName).
+		Where(sq.Eq{"product_id": productID}).
+		OrderBy("changed_at ASC", "id ASC").
+		ToSql()
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to build sql query: %w", err)
+	}
+
+	rows, err := getQuerier(ctx, r.db).QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query product status history: %w", err)
+	}
+	defer rows.Close()
+
+	history := []model.ProductStatusChange{}
+	for rows.Next() {
+		var (
+			change    model.ProductStatusChange
+			actorID   sql.NullString
+			actorRole sql.NullString
+		)
+
+		err := rows.Scan(
+			&change.ID, &change.ProductID, &change.FromStatus, &change.ToStatus, &change.ChangedAt,
+			&actorID, &change.Actor.Type, &actorRole,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan product status change row: %w", err)
+		}
+
+		change.Actor.ID = actorID.String
+		change.Actor.Role = model.UserRole(actorRole.String)
+
+		history = append(history, change)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating product status history rows: %w", err)
+	}
+
+	return history, nil
+}
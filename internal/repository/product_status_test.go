@@ -0,0 +1,150 @@
+package repository_test
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/kirillidk/pvz-service/internal/model"
+	"github.com/kirillidk/pvz-service/internal/repository"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProductStatusRepository_CreateProductStatusChange(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	productStatusRepo := repository.NewProductStatusRepository(db)
+	ctx := context.Background()
+	now := time.Now()
+
+	change := model.ProductStatusChange{
+		ProductID:  "d0eebc99-9c0b-4ef8-bb6d-6bb9bd380a11",
+		FromStatus: model.ProductStatusStored,
+		ToStatus:   model.ProductStatusIssued,
+		ChangedAt:  now,
+		Actor:      model.Actor{ID: "a0eebc99-9c0b-4ef8-bb6d-6bb9bd380a11", Type: model.UserActor, Role: model.EmployeeRole},
+	}
+
+	insertQuery := regexp.QuoteMeta(`INSERT INTO product_status_history (product_id,from_status,to_status,changed_at,actor_id,actor_type,actor_role) ` +
+		`VALUES ($1,$2,$3,$4,$5,$6,$7) RETURNING id`)
+
+	t.Run("Success", func(t *testing.T) {
+		mock.ExpectQuery(insertQuery).
+			WithArgs(change.ProductID, change.FromStatus, change.ToStatus, now, change.Actor.ID, change.Actor.Type, string(change.Actor.Role)).
+			WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(7))
+
+		created, err := productStatusRepo.CreateProductStatusChange(ctx, change)
+
+		assert.NoError(t, err)
+		assert.Equal(t, int64(7), created.ID)
+		assert.Equal(t, change.ToStatus, created.ToStatus)
+	})
+
+	t.Run("DB Error", func(t *testing.T) {
+		mock.ExpectQuery(insertQuery).
+			WillReturnError(errors.New("db error"))
+
+		created, err := productStatusRepo.CreateProductStatusChange(ctx, change)
+
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "failed to create product status change: db error")
+		assert.Nil(t, created)
+	})
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func TestProductStatusRepository_GetProductStatusHistory(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	productStatusRepo := repository.NewProductStatusRepository(db)
+	ctx := context.Background()
+	now := time.Now()
+
+	productID := "d0eebc99-9c0b-4ef8-bb6d-6bb9bd380a11"
+	selectQuery := regexp.QuoteMeta(`SELECT id, product_id, from_status, to_status, changed_at, actor_id, actor_type, actor_role ` +
+		`FROM product_status_history WHERE product_id = $1 ORDER BY changed_at ASC, id ASC`)
+	columns := []string{"id", "product_id", "from_status", "to_status", "changed_at", "actor_id", "actor_type", "actor_role"}
+
+	tests := []struct {
+		name          string
+		mockBehavior  func()
+		expectedValue []model.ProductStatusChange
+		expectedError error
+	}{
+		{
+			name: "Success",
+			mockBehavior: func() {
+				rows := sqlmock.NewRows(columns).
+					AddRow(1, productID, "received", "stored", now, "a0eebc99-9c0b-4ef8-bb6d-6bb9bd380a11", "user", "employee").
+					AddRow(2, productID, "stored", "issued", now.Add(time.Hour), nil, "api_key", nil)
+
+				mock.ExpectQuery(selectQuery).
+					WithArgs(productID).
+					WillReturnRows(rows)
+			},
+			expectedValue: []model.ProductStatusChange{
+				{
+					ID: 1, ProductID: productID, FromStatus: model.ProductStatusReceived, ToStatus: model.ProductStatusStored, ChangedAt: now,
+					Actor: model.Actor{ID: "a0eebc99-9c0b-4ef8-bb6d-6bb9bd380a11", Type: model.UserActor, Role: model.EmployeeRole},
+				},
+				{
+					ID: 2, ProductID: productID, FromStatus: model.ProductStatusStored, ToStatus: model.ProductStatusIssued, ChangedAt: now.Add(time.Hour),
+					Actor: model.Actor{Type: model.APIKeyActor},
+				},
+			},
+		},
+		{
+			name: "Empty",
+			mockBehavior: func() {
+				mock.ExpectQuery(selectQuery).
+					WithArgs(productID).
+					WillReturnRows(sqlmock.NewRows(columns))
+			},
+			expectedValue: []model.ProductStatusChange{},
+		},
+		{
+			name: "DB Error",
+			mockBehavior: func() {
+				mock.ExpectQuery(selectQuery).
+					WithArgs(productID).
+					WillReturnError(errors.New("db error"))
+			},
+			expectedError: errors.New("failed to query product status history: db error"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockBehavior()
+
+			history, err := productStatusRepo.GetProductStatusHistory(ctx, productID)
+
+			if tt.expectedError != nil {
+				assert.Error(t, err)
+				assert.Contains(t, err.Error(), tt.expectedError.Error())
+				assert.Nil(t, history)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expectedValue, history)
+			}
+
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("there were unfulfilled expectations: %s", err)
+			}
+		})
+	}
+}
@@ -3,17 +3,32 @@ package repository_test
 import (
 	"context"
 	"database/sql"
+	"database/sql/driver"
 	"errors"
 	"regexp"
 	"testing"
 	"time"
 
 	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/kirillidk/pvz-service/internal/dto"
 	"github.com/kirillidk/pvz-service/internal/model"
 	"github.com/kirillidk/pvz-service/internal/repository"
+	"github.com/lib/pq"
 	"github.com/stretchr/testify/assert"
 )
 
+var productRowColumns = []string{
+	"id", "date_time", "type", "reception_id", "serial_number", "barcode", "status", "cell_id", "condition", "condition_note",
+}
+
+const productColumnList = "id, date_time, type, reception_id, serial_number, barcode, status, cell_id, condition, condition_note"
+
+// productRow returns a product row with the columns added after the basic
+// ones left empty.
+func productRow(id string, dateTime time.Time, productType, receptionID string) []driver.Value {
+	return []driver.Value{id, dateTime, productType, receptionID, nil, nil, "", nil, "", nil}
+}
+
 func TestProductRepository_CreateProduct(t *testing.T) {
 	db, mock, err := sqlmock.New()
 	if err != nil {
@@ -37,11 +52,11 @@ func TestProductRepository_CreateProduct(t *testing.T) {
 			productType: "электроника",
 			receptionID: "c0eebc99-9c0b-4ef8-bb6d-6bb9bd380a11",
 			mockBehavior: func() {
-				rows := sqlmock.NewRows([]string{"id", "date_time", "type", "reception_id"}).
-					AddRow("d0eebc99-9c0b-4ef8-bb6d-6bb9bd380a11", time.Now(), "электроника", "c0eebc99-9c0b-4ef8-bb6d-6bb9bd380a11")
+				rows := sqlmock.NewRows(productRowColumns).
+					AddRow(productRow("d0eebc99-9c0b-4ef8-bb6d-6bb9bd380a11", time.Now(), "электроника", "c0eebc99-9c0b-4ef8-bb6d-6bb9bd380a11")...)
 
 				mock.ExpectQuery(`INSERT INTO products`).
-					WithArgs(sqlmock.AnyArg(), "электроника", "c0eebc99-9c0b-4ef8-bb6d-6bb9bd380a11").
+					WithArgs(sqlmock.AnyArg(), "электроника", "c0eebc99-9c0b-4ef8-bb6d-6bb9bd380a11", sqlmock.AnyArg(), sqlmock.AnyArg(), nil).
 					WillReturnRows(rows)
 			},
 			expectedResult: &model.Product{
@@ -57,11 +72,22 @@ func TestProductRepository_CreateProduct(t *testing.T) {
 			receptionID: "c0eebc99-9c0b-4ef8-bb6d-6bb9bd380a11",
 			mockBehavior: func() {
 				mock.ExpectQuery(`INSERT INTO products`).
-					WithArgs(sqlmock.AnyArg(), "электроника", "c0eebc99-9c0b-4ef8-bb6d-6bb9bd380a11").
+					WithArgs(sqlmock.AnyArg(), "электроника", "c0eebc99-9c0b-4ef8-bb6d-6bb9bd380a11", sqlmock.AnyArg(), sqlmock.AnyArg(), nil).
 					WillReturnError(errors.New("db error"))
 			},
 			expectedResult: nil,
 			expectedError:  errors.New("failed to create product: db error"),
+		}, {
+			name:        "Duplicate Barcode",
+			productType: "электроника",
+			receptionID: "c0eebc99-9c0b-4ef8-bb6d-6bb9bd380a11",
+			mockBehavior: func() {
+				mock.ExpectQuery(`INSERT INTO products`).
+					WithArgs(sqlmock.AnyArg(), "электроника", "c0eebc99-9c0b-4ef8-bb6d-6bb9bd380a11", sqlmock.AnyArg(), sqlmock.AnyArg(), nil).
+					WillReturnError(&pq.Error{Code: "23505"})
+			},
+			expectedResult: nil,
+			expectedError:  model.ErrDuplicateBarcode,
 		},
 	}
 
@@ -69,7 +95,7 @@ func TestProductRepository_CreateProduct(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			tt.mockBehavior()
 
-			product, err := productRepo.CreateProduct(ctx, tt.productType, tt.receptionID)
+			product, err := productRepo.CreateProduct(ctx, tt.receptionID, dto.ProductAttributes{Type: tt.productType})
 
 			if tt.expectedError != nil {
 				assert.Error(t, err)
@@ -90,6 +116,84 @@ func TestProductRepository_CreateProduct(t *testing.T) {
 	}
 }
 
+func TestProductRepository_CreateProducts(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	productRepo := repository.NewProductRepository(db)
+	ctx := context.Background()
+
+	receptionID := "c0eebc99-9c0b-4ef8-bb6d-6bb9bd380a11"
+	now := time.Now()
+
+	items := []dto.ProductAttributes{
+		{Type: "электроника"},
+		{Type: "обувь", SerialNumber: "SN-1", CellID: "e1eebc99-9c0b-4ef8-bb6d-6bb9bd380a11"},
+	}
+
+	tests := []struct {
+		name          string
+		mockBehavior  func()
+		expectedIDs   []string
+		expectedError error
+	}{
+		{
+			name: "Success",
+			mockBehavior: func() {
+				rows := sqlmock.NewRows([]string{"id", "date_time", "type", "reception_id", "serial_number", "barcode", "status", "cell_id", "condition", "condition_note", "seq"}).
+					AddRow("d0eebc99-9c0b-4ef8-bb6d-6bb9bd380a12", now, "обувь", receptionID, "SN-1", nil, "received", "e1eebc99-9c0b-4ef8-bb6d-6bb9bd380a11", "ok", nil, 8).
+					AddRow("d0eebc99-9c0b-4ef8-bb6d-6bb9bd380a11", now, "электроника", receptionID, nil, nil, "received", nil, "ok", nil, 7)
+
+				mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO products (date_time,type,reception_id,serial_number,barcode,cell_id) VALUES ($1,$2,$3,$4,$5,$6),($7,$8,$9,$10,$11,$12) RETURNING id, date_time, type, reception_id, serial_number, barcode, status, cell_id, condition, condition_note, seq`)).
+					WithArgs(
+						sqlmock.AnyArg(), "электроника", receptionID, sqlmock.AnyArg(), sqlmock.AnyArg(), nil,
+						sqlmock.AnyArg(), "обувь", receptionID, "SN-1", sqlmock.AnyArg(), "e1eebc99-9c0b-4ef8-bb6d-6bb9bd380a11",
+					).
+					WillReturnRows(rows)
+			},
+			expectedIDs: []string{"d0eebc99-9c0b-4ef8-bb6d-6bb9bd380a11", "d0eebc99-9c0b-4ef8-bb6d-6bb9bd380a12"},
+		},
+		{
+			name: "DB Error",
+			mockBehavior: func() {
+				mock.ExpectQuery(`INSERT INTO products`).
+					WillReturnError(errors.New("db error"))
+			},
+			expectedError: errors.New("failed to create products: db error"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockBehavior()
+
+			products, err := productRepo.CreateProducts(ctx, receptionID, items)
+
+			if tt.expectedError != nil {
+				assert.Error(t, err)
+				assert.Contains(t, err.Error(), tt.expectedError.Error())
+				assert.Nil(t, products)
+			} else {
+				assert.NoError(t, err)
+				ids := make([]string, 0, len(products))
+				for _, product := range products {
+					ids = append(ids, product.ID)
+				}
+				assert.Equal(t, tt.expectedIDs, ids)
+				assert.Equal(t, "SN-1", products[1].SerialNumber)
+				assert.Equal(t, "e1eebc99-9c0b-4ef8-bb6d-6bb9bd380a11", products[1].CellID)
+			}
+
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("there were unfulfilled expectations: %s", err)
+			}
+		})
+	}
+}
+
 func TestProductRepository_GetLastProductInReception(t *testing.T) {
 	db, mock, err := sqlmock.New()
 	if err != nil {
@@ -112,10 +216,10 @@ func TestProductRepository_GetLastProductInReception(t *testing.T) {
 			name:        "Success",
 			receptionID: "c0eebc99-9c0b-4ef8-bb6d-6bb9bd380a11",
 			mockBehavior: func() {
-				rows := sqlmock.NewRows([]string{"id", "date_time", "type", "reception_id"}).
-					AddRow("d0eebc99-9c0b-4ef8-bb6d-6bb9bd380a11", testTime, "электроника", "c0eebc99-9c0b-4ef8-bb6d-6bb9bd380a11")
+				rows := sqlmock.NewRows(productRowColumns).
+					AddRow(productRow("d0eebc99-9c0b-4ef8-bb6d-6bb9bd380a11", testTime, "электроника", "c0eebc99-9c0b-4ef8-bb6d-6bb9bd380a11")...)
 
-				mock.ExpectQuery(regexp.QuoteMeta(`SELECT id, date_time, type, reception_id FROM products WHERE reception_id = $1 ORDER BY date_time DESC LIMIT 1`)).
+				mock.ExpectQuery(regexp.QuoteMeta(`SELECT ` + productColumnList + ` FROM products WHERE reception_id = $1 ORDER BY seq DESC LIMIT 1`)).
 					WithArgs("c0eebc99-9c0b-4ef8-bb6d-6bb9bd380a11").
 					WillReturnRows(rows)
 			},
@@ -131,7 +235,7 @@ func TestProductRepository_GetLastProductInReception(t *testing.T) {
 			name:        "No Products Found",
 			receptionID: "c0eebc99-9c0b-4ef8-bb6d-6bb9bd380a11",
 			mockBehavior: func() {
-				mock.ExpectQuery(regexp.QuoteMeta(`SELECT id, date_time, type, reception_id FROM products WHERE reception_id = $1 ORDER BY date_time DESC LIMIT 1`)).
+				mock.ExpectQuery(regexp.QuoteMeta(`SELECT ` + productColumnList + ` FROM products WHERE reception_id = $1 ORDER BY seq DESC LIMIT 1`)).
 					WithArgs("c0eebc99-9c0b-4ef8-bb6d-6bb9bd380a11").
 					WillReturnError(sql.ErrNoRows)
 			},
@@ -142,7 +246,7 @@ func TestProductRepository_GetLastProductInReception(t *testing.T) {
 			name:        "DB Error",
 			receptionID: "c0eebc99-9c0b-4ef8-bb6d-6bb9bd380a11",
 			mockBehavior: func() {
-				mock.ExpectQuery(regexp.QuoteMeta(`SELECT id, date_time, type, reception_id FROM products WHERE reception_id = $1 ORDER BY date_time DESC LIMIT 1`)).
+				mock.ExpectQuery(regexp.QuoteMeta(`SELECT ` + productColumnList + ` FROM products WHERE reception_id = $1 ORDER BY seq DESC LIMIT 1`)).
 					WithArgs("c0eebc99-9c0b-4ef8-bb6d-6bb9bd380a11").
 					WillReturnError(errors.New("db error"))
 			},
@@ -183,6 +287,10 @@ func TestProductRepository_DeleteProduct(t *testing.T) {
 	productRepo := repository.NewProductRepository(db)
 	ctx := context.Background()
 
+	deleteQuery := regexp.QuoteMeta(`DELETE FROM products pr WHERE pr.id = $1 AND NOT (pr.status <> 'received' OR pr.order_id IS NOT NULL OR pr.transfer_id IS NOT NULL OR ` +
+		`EXISTS (SELECT 1 FROM transfer_products tp WHERE tp.product_id = pr.id))`)
+	locationQuery := regexp.QuoteMeta(`SELECT pr.id, pr.date_time`)
+
 	tests := []struct {
 		name          string
 		productID     string
@@ -193,7 +301,7 @@ func TestProductRepository_DeleteProduct(t *testing.T) {
 			name:      "Success",
 			productID: "d0eebc99-9c0b-4ef8-bb6d-6bb9bd380a11",
 			mockBehavior: func() {
-				mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM products WHERE id = $1`)).
+				mock.ExpectExec(deleteQuery).
 					WithArgs("d0eebc99-9c0b-4ef8-bb6d-6bb9bd380a11").
 					WillReturnResult(sqlmock.NewResult(0, 1))
 			},
@@ -203,17 +311,34 @@ func TestProductRepository_DeleteProduct(t *testing.T) {
 			name:      "Product Not Found",
 			productID: "d0eebc99-9c0b-4ef8-bb6d-6bb9bd380a11",
 			mockBehavior: func() {
-				mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM products WHERE id = $1`)).
+				mock.ExpectExec(deleteQuery).
 					WithArgs("d0eebc99-9c0b-4ef8-bb6d-6bb9bd380a11").
 					WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectQuery(locationQuery).
+					WithArgs("d0eebc99-9c0b-4ef8-bb6d-6bb9bd380a11").
+					WillReturnError(sql.ErrNoRows)
 			},
 			expectedError: errors.New("product not found"),
 		},
+		{
+			name:      "Product Already Processed",
+			productID: "d0eebc99-9c0b-4ef8-bb6d-6bb9bd380a11",
+			mockBehavior: func() {
+				mock.ExpectExec(deleteQuery).
+					WithArgs("d0eebc99-9c0b-4ef8-bb6d-6bb9bd380a11").
+					WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectQuery(locationQuery).
+					WithArgs("d0eebc99-9c0b-4ef8-bb6d-6bb9bd380a11").
+					WillReturnRows(sqlmock.NewRows([]string{"id", "date_time", "type", "reception_id", "serial_number", "barcode", "status", "cell_id", "condition", "condition_note", "pvz_id", "city", "reception_status", "order_id", "transfer_id"}).
+						AddRow("d0eebc99-9c0b-4ef8-bb6d-6bb9bd380a11", time.Now(), "электроника", "c0eebc99-9c0b-4ef8-bb6d-6bb9bd380a11", nil, nil, model.ProductStatusStored, nil, model.ProductConditionOK, "", "a0eebc99-9c0b-4ef8-bb6d-6bb9bd380a11", "Москва", "close", nil, nil))
+			},
+			expectedError: model.ErrProductNotDeletable,
+		},
 		{
 			name:      "DB Error",
 			productID: "d0eebc99-9c0b-4ef8-bb6d-6bb9bd380a11",
 			mockBehavior: func() {
-				mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM products WHERE id = $1`)).
+				mock.ExpectExec(deleteQuery).
 					WithArgs("d0eebc99-9c0b-4ef8-bb6d-6bb9bd380a11").
 					WillReturnError(errors.New("db error"))
 			},
@@ -263,11 +388,11 @@ func TestProductRepository_GetProductsByReceptionID(t *testing.T) {
 			name:        "Success",
 			receptionID: "c0eebc99-9c0b-4ef8-bb6d-6bb9bd380a11",
 			mockBehavior: func() {
-				rows := sqlmock.NewRows([]string{"id", "date_time", "type", "reception_id"}).
-					AddRow("d0eebc99-9c0b-4ef8-bb6d-6bb9bd380a11", testTime, "электроника", "c0eebc99-9c0b-4ef8-bb6d-6bb9bd380a11").
-					AddRow("d0eebc99-9c0b-4ef8-bb6d-6bb9bd380a12", testTime, "одежда", "c0eebc99-9c0b-4ef8-bb6d-6bb9bd380a11")
+				rows := sqlmock.NewRows(productRowColumns).
+					AddRow(productRow("d0eebc99-9c0b-4ef8-bb6d-6bb9bd380a11", testTime, "электроника", "c0eebc99-9c0b-4ef8-bb6d-6bb9bd380a11")...).
+					AddRow(productRow("d0eebc99-9c0b-4ef8-bb6d-6bb9bd380a12", testTime, "одежда", "c0eebc99-9c0b-4ef8-bb6d-6bb9bd380a11")...)
 
-				mock.ExpectQuery(regexp.QuoteMeta(`SELECT id, date_time, type, reception_id FROM products WHERE reception_id = $1 ORDER BY date_time DESC`)).
+				mock.ExpectQuery(regexp.QuoteMeta(`SELECT ` + productColumnList + ` FROM products WHERE reception_id = $1 ORDER BY seq DESC`)).
 					WithArgs("c0eebc99-9c0b-4ef8-bb6d-6bb9bd380a11").
 					WillReturnRows(rows)
 			},
@@ -291,9 +416,9 @@ func TestProductRepository_GetProductsByReceptionID(t *testing.T) {
 			name:        "Empty Result",
 			receptionID: "c0eebc99-9c0b-4ef8-bb6d-6bb9bd380a11",
 			mockBehavior: func() {
-				rows := sqlmock.NewRows([]string{"id", "date_time", "type", "reception_id"})
+				rows := sqlmock.NewRows(productRowColumns)
 
-				mock.ExpectQuery(regexp.QuoteMeta(`SELECT id, date_time, type, reception_id FROM products WHERE reception_id = $1 ORDER BY date_time DESC`)).
+				mock.ExpectQuery(regexp.QuoteMeta(`SELECT ` + productColumnList + ` FROM products WHERE reception_id = $1 ORDER BY seq DESC`)).
 					WithArgs("c0eebc99-9c0b-4ef8-bb6d-6bb9bd380a11").
 					WillReturnRows(rows)
 			},
@@ -304,7 +429,7 @@ func TestProductRepository_GetProductsByReceptionID(t *testing.T) {
 			name:        "DB Error",
 			receptionID: "c0eebc99-9c0b-4ef8-bb6d-6bb9bd380a11",
 			mockBehavior: func() {
-				mock.ExpectQuery(regexp.QuoteMeta(`SELECT id, date_time, type, reception_id FROM products WHERE reception_id = $1 ORDER BY date_time DESC`)).
+				mock.ExpectQuery(regexp.QuoteMeta(`SELECT ` + productColumnList + ` FROM products WHERE reception_id = $1 ORDER BY seq DESC`)).
 					WithArgs("c0eebc99-9c0b-4ef8-bb6d-6bb9bd380a11").
 					WillReturnError(errors.New("db error"))
 			},
@@ -341,3 +466,386 @@ func TestProductRepository_GetProductsByReceptionID(t *testing.T) {
 		})
 	}
 }
+
+func TestProductRepository_GetProductsByReceptionIDInInsertionOrder(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	productRepo := repository.NewProductRepository(db)
+	ctx := context.Background()
+	testTime := time.Now()
+
+	rows := sqlmock.NewRows([]string{"id", "date_time", "type", "reception_id", "serial_number", "barcode", "status", "cell_id", "condition", "condition_note"}).
+		AddRow("d0eebc99-9c0b-4ef8-bb6d-6bb9bd380a11", testTime, "электроника", "c0eebc99-9c0b-4ef8-bb6d-6bb9bd380a11", nil, nil, "received", nil, "ok", nil).
+		AddRow("d0eebc99-9c0b-4ef8-bb6d-6bb9bd380a12", testTime.Add(time.Minute), "одежда", "c0eebc99-9c0b-4ef8-bb6d-6bb9bd380a11", nil, nil, "received", nil, "ok", nil)
+
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT id, date_time, type, reception_id, serial_number, barcode, status, cell_id, condition, condition_note FROM products WHERE reception_id = $1 ORDER BY seq ASC`)).
+		WithArgs("c0eebc99-9c0b-4ef8-bb6d-6bb9bd380a11").
+		WillReturnRows(rows)
+
+	products, err := productRepo.GetProductsByReceptionIDInInsertionOrder(ctx, "c0eebc99-9c0b-4ef8-bb6d-6bb9bd380a11")
+
+	assert.NoError(t, err)
+	assert.Len(t, products, 2)
+	assert.Equal(t, "d0eebc99-9c0b-4ef8-bb6d-6bb9bd380a11", products[0].ID)
+	assert.Equal(t, "d0eebc99-9c0b-4ef8-bb6d-6bb9bd380a12", products[1].ID)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func TestProductRepository_GetProductsByBarcodes(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	productRepo := repository.NewProductRepository(db)
+	ctx := context.Background()
+	testTime := time.Now()
+
+	selectQuery := regexp.QuoteMeta(`SELECT pr.id, pr.date_time, pr.type, pr.reception_id, pr.serial_number, pr.barcode, pr.status, pr.cell_id, pr.condition, pr.condition_note, r.pvz_id, p.city, r.status, pr.order_id, pr.transfer_id ` +
+		`FROM products pr LEFT JOIN LATERAL (SELECT t.reception_id, t.received_at FROM transfers t JOIN transfer_products tp ON tp.transfer_id = t.id ` +
+		`WHERE tp.product_id = pr.id AND t.status = 'received' ORDER BY t.received_at DESC LIMIT 1) arrival ON TRUE ` +
+		`JOIN receptions r ON r.id = COALESCE(arrival.reception_id, pr.reception_id) JOIN pvz p ON p.id = r.pvz_id ` +
+		`WHERE pr.barcode IN ($1) ORDER BY pr.seq DESC`)
+
+	tests := []struct {
+		name          string
+		mockBehavior  func()
+		expectedValue []model.ProductLocation
+		expectedError error
+	}{
+		{
+			name: "Success",
+			mockBehavior: func() {
+				rows := sqlmock.NewRows([]string{"id", "date_time", "type", "reception_id", "serial_number", "barcode", "status", "cell_id", "condition", "condition_note", "pvz_id", "city", "status", "order_id", "transfer_id"}).
+					AddRow("d0eebc99-9c0b-4ef8-bb6d-6bb9bd380a11", testTime, "электроника", "c0eebc99-9c0b-4ef8-bb6d-6bb9bd380a11", nil, "4006381333931", "received", nil, "ok", nil,
+						"b0eebc99-9c0b-4ef8-bb6d-6bb9bd380a11", "Москва", "close", nil, nil)
+
+				mock.ExpectQuery(selectQuery).
+					WithArgs("4006381333931").
+					WillReturnRows(rows)
+			},
+			expectedValue: []model.ProductLocation{
+				{
+					Product: model.Product{
+						ID:          "d0eebc99-9c0b-4ef8-bb6d-6bb9bd380a11",
+						DateTime:    testTime,
+						Type:        "электроника",
+						ReceptionID: "c0eebc99-9c0b-4ef8-bb6d-6bb9bd380a11",
+						Barcode:     "4006381333931",
+						Status:      model.ProductStatusReceived,
+						Condition:   model.ProductConditionOK,
+					},
+					PVZID:           "b0eebc99-9c0b-4ef8-bb6d-6bb9bd380a11",
+					City:            "Москва",
+					ReceptionStatus: "close",
+				},
+			},
+		},
+		{
+			name: "Not Found",
+			mockBehavior: func() {
+				mock.ExpectQuery(selectQuery).
+					WithArgs("4006381333931").
+					WillReturnRows(sqlmock.NewRows([]string{"id", "date_time", "type", "reception_id", "serial_number", "barcode", "status", "cell_id", "condition", "condition_note", "pvz_id", "city", "status", "order_id", "transfer_id"}))
+			},
+			expectedValue: []model.ProductLocation{},
+		},
+		{
+			name: "DB Error",
+			mockBehavior: func() {
+				mock.ExpectQuery(selectQuery).
+					WithArgs("4006381333931").
+					WillReturnError(errors.New("db error"))
+			},
+			expectedError: errors.New("failed to query products: db error"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockBehavior()
+
+			locations, err := productRepo.GetProductsByBarcodes(ctx, []string{"4006381333931"})
+
+			if tt.expectedError != nil {
+				assert.Error(t, err)
+				assert.Contains(t, err.Error(), tt.expectedError.Error())
+				assert.Nil(t, locations)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expectedValue, locations)
+			}
+
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("there were unfulfilled expectations: %s", err)
+			}
+		})
+	}
+}
+
+func TestProductRepository_GetProductLocationForUpdate(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	productRepo := repository.NewProductRepository(db)
+	ctx := context.Background()
+	testTime := time.Now()
+
+	productID := "d0eebc99-9c0b-4ef8-bb6d-6bb9bd380a11"
+	selectQuery := regexp.QuoteMeta(`SELECT pr.id, pr.date_time, pr.type, pr.reception_id, pr.serial_number, pr.barcode, pr.status, pr.cell_id, pr.condition, pr.condition_note, r.pvz_id, p.city, r.status, pr.order_id, pr.transfer_id ` +
+		`FROM products pr LEFT JOIN LATERAL (SELECT t.reception_id, t.received_at FROM transfers t JOIN transfer_products tp ON tp.transfer_id = t.id ` +
+		`WHERE tp.product_id = pr.id AND t.status = 'received' ORDER BY t.received_at DESC LIMIT 1) arrival ON TRUE ` +
+		`JOIN receptions r ON r.id = COALESCE(arrival.reception_id, pr.reception_id) JOIN pvz p ON p.id = r.pvz_id ` +
+		`WHERE pr.id = $1 FOR UPDATE OF pr`)
+
+	tests := []struct {
+		name          string
+		mockBehavior  func()
+		expectedValue *model.ProductLocation
+		expectedError error
+	}{
+		{
+			name: "Success",
+			mockBehavior: func() {
+				rows := sqlmock.NewRows([]string{"id", "date_time", "type", "reception_id", "serial_number", "barcode", "status", "cell_id", "condition", "condition_note", "pvz_id", "city", "status", "order_id", "transfer_id"}).
+					AddRow(productID, testTime, "обувь", "c0eebc99-9c0b-4ef8-bb6d-6bb9bd380a11", nil, nil, "stored", nil, "ok", nil,
+						"b0eebc99-9c0b-4ef8-bb6d-6bb9bd380a11", "Москва", "close", "e0eebc99-9c0b-4ef8-bb6d-6bb9bd380a11", nil)
+
+				mock.ExpectQuery(selectQuery).
+					WithArgs(productID).
+					WillReturnRows(rows)
+			},
+			expectedValue: &model.ProductLocation{
+				Product: model.Product{
+					ID:          productID,
+					DateTime:    testTime,
+					Type:        "обувь",
+					ReceptionID: "c0eebc99-9c0b-4ef8-bb6d-6bb9bd380a11",
+					Status:      model.ProductStatusStored,
+					Condition:   model.ProductConditionOK,
+				},
+				PVZID:           "b0eebc99-9c0b-4ef8-bb6d-6bb9bd380a11",
+				City:            "Москва",
+				ReceptionStatus: "close",
+				OrderID:         "e0eebc99-9c0b-4ef8-bb6d-6bb9bd380a11",
+			},
+		},
+		{
+			name: "Not Found",
+			mockBehavior: func() {
+				mock.ExpectQuery(selectQuery).
+					WithArgs(productID).
+					WillReturnError(sql.ErrNoRows)
+			},
+			expectedError: model.ErrProductNotFound,
+		},
+		{
+			name: "DB Error",
+			mockBehavior: func() {
+				mock.ExpectQuery(selectQuery).
+					WithArgs(productID).
+					WillReturnError(errors.New("db error"))
+			},
+			expectedError: errors.New("failed to get product: db error"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockBehavior()
+
+			location, err := productRepo.GetProductLocationForUpdate(ctx, productID)
+
+			if tt.expectedError != nil {
+				assert.Error(t, err)
+				assert.Contains(t, err.Error(), tt.expectedError.Error())
+				assert.Nil(t, location)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expectedValue, location)
+			}
+
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("there were unfulfilled expectations: %s", err)
+			}
+		})
+	}
+}
+
+func TestProductRepository_UpdateProductStatus(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	productRepo := repository.NewProductRepository(db)
+	ctx := context.Background()
+	testTime := time.Now()
+
+	productID := "d0eebc99-9c0b-4ef8-bb6d-6bb9bd380a11"
+	updateQuery := regexp.QuoteMeta(`UPDATE products SET status = $1 WHERE id = $2 RETURNING id, date_time, type, reception_id, serial_number, barcode, status, cell_id, condition, condition_note`)
+
+	tests := []struct {
+		name          string
+		mockBehavior  func()
+		expectedError error
+	}{
+		{
+			name: "Success",
+			mockBehavior: func() {
+				rows := sqlmock.NewRows([]string{"id", "date_time", "type", "reception_id", "serial_number", "barcode", "status", "cell_id", "condition", "condition_note"}).
+					AddRow(productID, testTime, "обувь", "c0eebc99-9c0b-4ef8-bb6d-6bb9bd380a11", nil, nil, "issued", nil, "ok", nil)
+
+				mock.ExpectQuery(updateQuery).
+					WithArgs(model.ProductStatusIssued, productID).
+					WillReturnRows(rows)
+			},
+		},
+		{
+			name: "Not Found",
+			mockBehavior: func() {
+				mock.ExpectQuery(updateQuery).
+					WithArgs(model.ProductStatusIssued, productID).
+					WillReturnError(sql.ErrNoRows)
+			},
+			expectedError: model.ErrProductNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockBehavior()
+
+			product, err := productRepo.UpdateProductStatus(ctx, productID, model.ProductStatusIssued)
+
+			if tt.expectedError != nil {
+				assert.ErrorIs(t, err, tt.expectedError)
+				assert.Nil(t, product)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, model.ProductStatusIssued, product.Status)
+			}
+
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("there were unfulfilled expectations: %s", err)
+			}
+		})
+	}
+}
+
+func TestProductRepository_UpdateProductCell(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	productRepo := repository.NewProductRepository(db)
+	ctx := context.Background()
+	testTime := time.Now()
+
+	productID := "d0eebc99-9c0b-4ef8-bb6d-6bb9bd380a11"
+	cellID := "e1eebc99-9c0b-4ef8-bb6d-6bb9bd380a11"
+	updateQuery := regexp.QuoteMeta(`UPDATE products SET cell_id = $1 WHERE id = $2 RETURNING id, date_time, type, reception_id, serial_number, barcode, status, cell_id, condition, condition_note`)
+
+	t.Run("Success", func(t *testing.T) {
+		rows := sqlmock.NewRows([]string{"id", "date_time", "type", "reception_id", "serial_number", "barcode", "status", "cell_id", "condition", "condition_note"}).
+			AddRow(productID, testTime, "обувь", "c0eebc99-9c0b-4ef8-bb6d-6bb9bd380a11", nil, nil, "stored", cellID, "ok", nil)
+
+		mock.ExpectQuery(updateQuery).
+			WithArgs(cellID, productID).
+			WillReturnRows(rows)
+
+		product, err := productRepo.UpdateProductCell(ctx, productID, cellID)
+
+		assert.NoError(t, err)
+		assert.Equal(t, cellID, product.CellID)
+	})
+
+	t.Run("Not Found", func(t *testing.T) {
+		mock.ExpectQuery(updateQuery).
+			WithArgs(cellID, productID).
+			WillReturnError(sql.ErrNoRows)
+
+		product, err := productRepo.UpdateProductCell(ctx, productID, cellID)
+
+		assert.ErrorIs(t, err, model.ErrProductNotFound)
+		assert.Nil(t, product)
+	})
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func TestProductRepository_UpdateProductCondition(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	productRepo := repository.NewProductRepository(db)
+	ctx := context.Background()
+	testTime := time.Now()
+
+	productID := "d0eebc99-9c0b-4ef8-bb6d-6bb9bd380a11"
+	updateQuery := regexp.QuoteMeta(`UPDATE products SET condition = $1, condition_note = $2 WHERE id = $3 RETURNING id, date_time, type, reception_id, serial_number, barcode, status, cell_id, condition, condition_note`)
+
+	t.Run("Success", func(t *testing.T) {
+		rows := sqlmock.NewRows([]string{"id", "date_time", "type", "reception_id", "serial_number", "barcode", "status", "cell_id", "condition", "condition_note"}).
+			AddRow(productID, testTime, "обувь", "c0eebc99-9c0b-4ef8-bb6d-6bb9bd380a11", nil, nil, "received", nil, "damaged", "box torn")
+
+		mock.ExpectQuery(updateQuery).
+			WithArgs(model.ProductConditionDamaged, "box torn", productID).
+			WillReturnRows(rows)
+
+		product, err := productRepo.UpdateProductCondition(ctx, productID, model.ProductConditionDamaged, "box torn")
+
+		assert.NoError(t, err)
+		assert.Equal(t, model.ProductConditionDamaged, product.Condition)
+		assert.Equal(t, "box torn", product.ConditionNote)
+	})
+
+	t.Run("Note Cleared", func(t *testing.T) {
+		rows := sqlmock.NewRows([]string{"id", "date_time", "type", "reception_id", "serial_number", "barcode", "status", "cell_id", "condition", "condition_note"}).
+			AddRow(productID, testTime, "обувь", "c0eebc99-9c0b-4ef8-bb6d-6bb9bd380a11", nil, nil, "received", nil, "ok", nil)
+
+		mock.ExpectQuery(updateQuery).
+			WithArgs(model.ProductConditionOK, nil, productID).
+			WillReturnRows(rows)
+
+		product, err := productRepo.UpdateProductCondition(ctx, productID, model.ProductConditionOK, "")
+
+		assert.NoError(t, err)
+		assert.Equal(t, model.ProductConditionOK, product.Condition)
+		assert.Empty(t, product.ConditionNote)
+	})
+
+	t.Run("Not Found", func(t *testing.T) {
+		mock.ExpectQuery(updateQuery).
+			WithArgs(model.ProductConditionOpened, nil, productID).
+			WillReturnError(sql.ErrNoRows)
+
+		product, err := productRepo.UpdateProductCondition(ctx, productID, model.ProductConditionOpened, "")
+
+		assert.ErrorIs(t, err, model.ErrProductNotFound)
+		assert.Nil(t, product)
+	})
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
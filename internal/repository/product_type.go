@@ -0,0 +1,211 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/kirillidk/pvz-service/internal/dto"
+	"github.com/kirillidk/pvz-service/internal/model"
+)
+
+const (
+	productTypeTableName = "product_types"
+)
+
+var productTypeColumns = []string{"code", "name", "active", "requires_serial_number", "created_at", "storage_days"}
+
+type ProductTypeRepositoryInterface interface {
+	CreateProductType(ctx context.Context, productTypeReq dto.ProductTypeCreateRequest) (*model.ProductType, error)
+	GetProductTypeList(ctx context.Context) ([]model.ProductType, error)
+	GetProductTypeByCode(ctx context.Context, code string) (*model.ProductType, error)
+	UpdateProductType(ctx context.Context, code string, productTypeReq dto.ProductTypeUpdateRequest) (*model.ProductType, error)
+	DeleteProductType(ctx context.Context, code string) error
+}
+
+type ProductTypeRepository struct {
+	db   *sql.DB
+	psql sq.StatementBuilderType
+}
+
+func NewProductTypeRepository(db *sql.DB) *ProductTypeRepository {
+	return &ProductTypeRepository{
+		db:   db,
+		psql: sq.StatementBuilder.PlaceholderFormat(sq.Dollar),
+	}
+}
+
+func (r *ProductTypeRepository) CreateProductType(ctx context.Context, productTypeReq dto.ProductTypeCreateRequest) (*model.ProductType, error) {
+	active := true
+	if productTypeReq.Active != nil {
+		active = *productTypeReq.Active
+	}
+
+	query, args, err := r.psql.
+		Insert(productTypeTableName).
+		Columns(productTypeColumns...).
+		Values(productTypeReq.Code, productTypeReq.Name, active, productTypeReq.RequiresSerialNumber, time.Now().UTC(), productTypeReq.StorageDays).
+		Suffix("RETURNING " + columnList(productTypeColumns)).
+		ToSql()
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to build sql query: %w", err)
+	}
+
+	productType, err := scanProductType(getQuerier(ctx, r.db).QueryRowContext(ctx, query, args...))
+	if err != nil {
+		if isPQError(err, pqUniqueViolation) {
+			return nil, model.ErrProductTypeAlreadyExists
+		}
+		return nil, fmt.Errorf("failed to create product type: %w", err)
+	}
+
+	return productType, nil
+}
+
+func (r *ProductTypeRepository) GetProductTypeList(ctx context.Context) ([]model.ProductType, error) {
+	query, args, err := r.psql.
+		Select(productTypeColumns...).
+		From(productTypeTableName).
+		OrderBy("code").
+		ToSql()
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to build sql query: %w", err)
+	}
+
+	rows, err := getQuerier(ctx, r.db).QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query product types: %w", err)
+	}
+	defer rows.Close()
+
+	productTypes := make([]model.ProductType, 0)
+	for rows.Next() {
+		productType, err := scanProductType(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan product type row: %w", err)
+		}
+		productTypes = append(productTypes, *productType)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating product type rows: %w", err)
+	}
+
+	return productTypes, nil
+}
+
+func (r *ProductTypeRepository) GetProductTypeByCode(ctx context.Context, code string) (*model.ProductType, error) {
+	query, args, err := r.psql.
+		Select(productTypeColumns...).
+		From(productTypeTableName).
+		Where(sq.Eq{"code": code}).
+		ToSql()
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to build sql query: %w", err)
+	}
+
+	productType, err := scanProductType(getQuerier(ctx, r.db).QueryRowContext(ctx, query, args...))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, model.ErrProductTypeNotFound
+		}
+		return nil, fmt.Errorf("failed to get product type: %w", err)
+	}
+
+	return productType, nil
+}
+
+func (r *ProductTypeRepository) UpdateProductType(ctx context.Context, code string, productTypeReq dto.ProductTypeUpdateRequest) (*model.ProductType, error) {
+	queryBuilder := r.psql.
+		Update(productTypeTableName).
+		Where(sq.Eq{"code": code}).
+		Suffix("RETURNING " + columnList(productTypeColumns))
+
+	if productTypeReq.Name != nil {
+		queryBuilder = queryBuilder.Set("name", *productTypeReq.Name)
+	}
+
+	if productTypeReq.Active != nil {
+		queryBuilder = queryBuilder.Set("active", *productTypeReq.Active)
+	}
+
+	if productTypeReq.RequiresSerialNumber != nil {
+		queryBuilder = queryBuilder.Set("requires_serial_number", *productTypeReq.RequiresSerialNumber)
+	}
+
+	if productTypeReq.StorageDays.Set {
+		queryBuilder = queryBuilder.Set("storage_days", productTypeReq.StorageDays.Value)
+	}
+
+	query, args, err := queryBuilder.ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build sql query: %w", err)
+	}
+
+	productType, err := scanProductType(getQuerier(ctx, r.db).QueryRowContext(ctx, query, args...))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, model.ErrProductTypeNotFound
+		}
+		return nil, fmt.Errorf("failed to update product type: %w", err)
+	}
+
+	return productType, nil
+}
+
+func (r *ProductTypeRepository) DeleteProductType(ctx context.Context, code string) error {
+	query, args, err := r.psql.
+		Delete(productTypeTableName).
+		Where(sq.Eq{"code": code}).
+		ToSql()
+
+	if err != nil {
+		return fmt.Errorf("failed to build sql query: %w", err)
+	}
+
+	result, err := getQuerier(ctx, r.db).ExecContext(ctx, query, args...)
+	if err != nil {
+		if isPQError(err, pqForeignKeyViolation) {
+			return model.ErrProductTypeInUse
+		}
+		return fmt.Errorf("failed to delete product type: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return model.ErrProductTypeNotFound
+	}
+
+	return nil
+}
+
+func scanProductType(row rowScanner) (*model.ProductType, error) {
+	var (
+		productType model.ProductType
+		storageDays sql.NullInt32
+	)
+
+	err := row.Scan(
+		&productType.Code, &productType.Name, &productType.Active, &productType.RequiresSerialNumber,
+		&productType.CreatedAt, &storageDays,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if storageDays.Valid {
+		days := int(storageDays.Int32)
+		productType.StorageDays = &days
+	}
+
+	return &productType, nil
+}
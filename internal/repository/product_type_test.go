@@ -0,0 +1,87 @@
+package repository_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/kirillidk/pvz-service/internal/dto"
+	"github.com/kirillidk/pvz-service/internal/model"
+	"github.com/kirillidk/pvz-service/internal/repository"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+)
+
+var productTypeRowColumns = []string{"code", "name", "active", "requires_serial_number", "created_at", "storage_days"}
+
+func TestProductTypeRepository_CreateProductType(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	productTypeRepo := repository.NewProductTypeRepository(db)
+	ctx := context.Background()
+	testTime := time.Now()
+	storageDays := 30
+
+	t.Run("Success", func(t *testing.T) {
+		rows := sqlmock.NewRows(productTypeRowColumns).
+			AddRow("смартфоны", "Смартфоны", true, true, testTime, 30)
+
+		mock.ExpectQuery(`INSERT INTO product_types`).
+			WithArgs("смартфоны", "Смартфоны", true, true, sqlmock.AnyArg(), &storageDays).
+			WillReturnRows(rows)
+
+		productType, err := productTypeRepo.CreateProductType(ctx, dto.ProductTypeCreateRequest{
+			Code:                 "смартфоны",
+			Name:                 "Смартфоны",
+			RequiresSerialNumber: true,
+			StorageDays:          &storageDays,
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, &model.ProductType{
+			Code:                 "смартфоны",
+			Name:                 "Смартфоны",
+			Active:               true,
+			RequiresSerialNumber: true,
+			CreatedAt:            testTime,
+			StorageDays:          &storageDays,
+		}, productType)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Duplicate Code", func(t *testing.T) {
+		mock.ExpectQuery(`INSERT INTO product_types`).
+			WillReturnError(&pq.Error{Code: "23505"})
+
+		productType, err := productTypeRepo.CreateProductType(ctx, dto.ProductTypeCreateRequest{Code: "обувь", Name: "Обувь"})
+
+		assert.ErrorIs(t, err, model.ErrProductTypeAlreadyExists)
+		assert.Nil(t, productType)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestProductTypeRepository_DeleteProductType(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	productTypeRepo := repository.NewProductTypeRepository(db)
+	ctx := context.Background()
+
+	mock.ExpectExec(`DELETE FROM product_types`).
+		WithArgs("обувь").
+		WillReturnError(&pq.Error{Code: "23503"})
+
+	err = productTypeRepo.DeleteProductType(ctx, "обувь")
+
+	assert.ErrorIs(t, err, model.ErrProductTypeInUse)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
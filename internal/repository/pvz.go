@@ -4,6 +4,7 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
+	"math"
 	"time"
 
 	sq "github.com/Masterminds/squirrel"
@@ -13,12 +14,35 @@ import (
 
 const (
 	pvzTableName = "pvz"
+
+	earthRadiusMeters = 6371000.0
+	metersPerDegree   = 111320.0
+
+	// openNowCandidateLimit caps the nearest PVZs read for a search limited
+	// to open ones, so that the query stays bounded however many are closed.
+	openNowCandidateLimit = 500
 )
 
+// haversineDistance is the great-circle distance in meters between a PVZ
+// and the point given by its arguments: earth radius, latitude, latitude,
+// longitude. LEAST guards ASIN against rounding slightly above 1.
+const haversineDistance = "2 * ? * ASIN(LEAST(1, SQRT(" +
+	"POWER(SIN(RADIANS(p.latitude - ?) / 2), 2) + " +
+	"COS(RADIANS(?)) * COS(RADIANS(p.latitude)) * POWER(SIN(RADIANS(p.longitude - ?) / 2), 2))))"
+
+var pvzColumns = []string{
+	"id", "registration_date", "city", "address", "latitude", "longitude", "phone", "working_hours", "status",
+}
+
 type PVZRepositoryInterface interface {
 	CreatePVZ(ctx context.Context, pvzReq dto.PVZCreateRequest) (*model.PVZ, error)
 	GetPVZList(ctx context.Context, filter dto.PVZFilterQuery) ([]model.PVZ, error)
 	GetPVZByID(ctx context.Context, pvzID string) (*model.PVZ, error)
+	UpdatePVZ(ctx context.Context, pvzID string, pvzReq dto.PVZUpdateRequest) (*model.PVZ, error)
+	GetNearbyPVZList(ctx context.Context, query dto.PVZNearbyQuery) ([]model.NearbyPVZ, error)
+	GetPVZForUpdate(ctx context.Context, pvzID string) (*model.PVZ, error)
+	UpdatePVZStatus(ctx context.Context, pvzID string, status model.PVZStatus) (*model.PVZ, error)
+	StreamPVZList(ctx context.Context, filter dto.PVZFilterQuery, fn func(model.PVZ, *model.Reception, *model.Product) error) error
 }
 
 type PVZRepository struct {
@@ -34,42 +58,44 @@ func NewPVZRepository(db *sql.DB) *PVZRepository {
 }
 
 func (r *PVZRepository) CreatePVZ(ctx context.Context, pvzReq dto.PVZCreateRequest) (*model.PVZ, error) {
-	registrationDate := time.Now()
+	registrationDate := time.Now().UTC()
 
 	query, args, err := r.psql.
 		Insert(pvzTableName).
-		Columns("registration_date", "city").
-		Values(registrationDate, pvzReq.City).
-		Suffix("RETURNING id, registration_date, city").
+		Columns("registration_date", "city", "address", "latitude", "longitude", "phone", "working_hours").
+		Values(
+			registrationDate, pvzReq.City, pvzReq.Address, pvzReq.Latitude, pvzReq.Longitude,
+			pvzReq.Phone, pvzReq.WorkingHours,
+		).
+		Suffix("RETURNING " + columnList(pvzColumns)).
 		ToSql()
 
 	if err != nil {
 		return nil, fmt.Errorf("failed to build sql query: %w", err)
 	}
 
-	var createdPVZ model.PVZ
-	err = r.db.QueryRowContext(ctx, query, args...).Scan(&createdPVZ.ID, &createdPVZ.RegistrationDate, &createdPVZ.City)
+	createdPVZ, err := scanPVZ(getQuerier(ctx, r.db).QueryRowContext(ctx, query, args...))
 	if err != nil {
 		return nil, fmt.Errorf("failed to create PVZ: %w", err)
 	}
 
-	return &createdPVZ, nil
+	return createdPVZ, nil
 }
 
 func (r *PVZRepository) GetPVZList(ctx context.Context, filter dto.PVZFilterQuery) ([]model.PVZ, error) {
 	queryBuilder := r.psql.
-		Select("p.id", "p.registration_date", "p.city").
+		Select(prefixColumns("p", pvzColumns)...).
 		From(pvzTableName + " p")
 
-	if filter.StartDate != nil || filter.EndDate != nil {
-		queryBuilder = queryBuilder.Join("receptions r ON p.id = r.pvz_id")
+	for _, condition := range pvzFilterConditions(filter) {
+		queryBuilder = queryBuilder.Where(condition)
+	}
 
-		if filter.StartDate != nil {
-			queryBuilder = queryBuilder.Where(sq.GtOrEq{"r.date_time": filter.StartDate})
-		}
+	if receptionFilter := filter.ReceptionFilter(); !receptionFilter.IsEmpty() {
+		queryBuilder = queryBuilder.Join("receptions r ON p.id = r.pvz_id")
 
-		if filter.EndDate != nil {
-			queryBuilder = queryBuilder.Where(sq.LtOrEq{"r.date_time": filter.EndDate})
+		for _, condition := range receptionFilterConditions("r", receptionFilter) {
+			queryBuilder = queryBuilder.Where(condition)
 		}
 
 		queryBuilder = queryBuilder.GroupBy("p.id")
@@ -77,16 +103,16 @@ func (r *PVZRepository) GetPVZList(ctx context.Context, filter dto.PVZFilterQuer
 
 	offset := (filter.Page - 1) * filter.Limit
 	queryBuilder = queryBuilder.
+		OrderBy(pvzOrderBy(filter.Sort)...).
 		Offset(uint64(offset)).
-		Limit(uint64(filter.Limit)).
-		OrderBy("p.registration_date DESC")
+		Limit(uint64(filter.Limit))
 
 	query, args, err := queryBuilder.ToSql()
 	if err != nil {
 		return nil, fmt.Errorf("failed to build sql query: %w", err)
 	}
 
-	rows, err := r.db.QueryContext(ctx, query, args...)
+	rows, err := getQuerier(ctx, r.db).QueryContext(ctx, query, args...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to query pvz list: %w", err)
 	}
@@ -94,11 +120,11 @@ func (r *PVZRepository) GetPVZList(ctx context.Context, filter dto.PVZFilterQuer
 
 	var pvzList []model.PVZ
 	for rows.Next() {
-		var pvz model.PVZ
-		if err := rows.Scan(&pvz.ID, &pvz.RegistrationDate, &pvz.City); err != nil {
+		pvz, err := scanPVZ(rows)
+		if err != nil {
 			return nil, fmt.Errorf("failed to scan pvz row: %w", err)
 		}
-		pvzList = append(pvzList, pvz)
+		pvzList = append(pvzList, *pvz)
 	}
 
 	if err := rows.Err(); err != nil {
@@ -108,24 +134,389 @@ func (r *PVZRepository) GetPVZList(ctx context.Context, filter dto.PVZFilterQuer
 	return pvzList, nil
 }
 
+// StreamPVZList reads the PVZs matching filter joined with their receptions
+// and products, ignoring pagination, and passes the rows to fn one at a time.
+// The reception is nil for a PVZ without receptions and the product is nil
+// for a reception without products. Rows of a PVZ are adjacent, receptions
+// and products go from newest to oldest, as in GetPVZList.
+// Canceling ctx stops the query.
+func (r *PVZRepository) StreamPVZList(
+	ctx context.Context,
+	filter dto.PVZFilterQuery,
+	fn func(model.PVZ, *model.Reception, *model.Product) error,
+) error {
+	columns := append(prefixColumns("p", pvzColumns), prefixColumns("r", receptionColumns)...)
+	columns = append(columns, prefixColumns("pr", productColumns)...)
+
+	queryBuilder := r.psql.
+		Select(columns...).
+		From(pvzTableName + " p")
+
+	receptionFilter := filter.ReceptionFilter()
+	if receptionFilter.IsEmpty() {
+		queryBuilder = queryBuilder.LeftJoin(receptionTableName + " r ON r.pvz_id = p.id")
+	} else {
+		joinCondition, joinArgs, err := sq.And(receptionFilterConditions("r", receptionFilter)).ToSql()
+		if err != nil {
+			return fmt.Errorf("failed to build sql query: %w", err)
+		}
+
+		queryBuilder = queryBuilder.
+			LeftJoin(receptionTableName+" r ON r.pvz_id = p.id AND "+joinCondition, joinArgs...).
+			Where("r.id IS NOT NULL")
+	}
+
+	queryBuilder = queryBuilder.LeftJoin(productTableName + " pr ON pr.reception_id = r.id")
+
+	for _, condition := range pvzFilterConditions(filter) {
+		queryBuilder = queryBuilder.Where(condition)
+	}
+
+	query, args, err := queryBuilder.OrderBy(pvzRowsOrderBy(filter.Sort)...).ToSql()
+	if err != nil {
+		return fmt.Errorf("failed to build sql query: %w", err)
+	}
+
+	rows, err := getQuerier(ctx, r.db).QueryContext(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("failed to query pvz list: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			receptionID, receptionPVZID, receptionStatus           sql.NullString
+			receptionDateTime, closedAt, firstProduct, lastProduct sql.NullTime
+			productID, productType, productReceptionID, serial     sql.NullString
+			barcode, productStatus, cellID                         sql.NullString
+			condition, conditionNote                               sql.NullString
+			expectedCount                                          sql.NullInt64
+			productDateTime                                        sql.NullTime
+		)
+
+		pvz, err := scanPVZ(rows,
+			&receptionID, &receptionDateTime, &receptionPVZID, &receptionStatus, &closedAt, &firstProduct, &lastProduct,
+			&expectedCount,
+			&productID, &productDateTime, &productType, &productReceptionID, &serial, &barcode, &productStatus, &cellID,
+			&condition, &conditionNote,
+		)
+		if err != nil {
+			return fmt.Errorf("failed to scan pvz row: %w", err)
+		}
+
+		var reception *model.Reception
+		if receptionID.Valid {
+			reception = &model.Reception{
+				ID:             receptionID.String,
+				DateTime:       receptionDateTime.Time,
+				PVZID:          receptionPVZID.String,
+				Status:         receptionStatus.String,
+				ClosedAt:       nullTimePtr(closedAt),
+				FirstProductAt: nullTimePtr(firstProduct),
+				LastProductAt:  nullTimePtr(lastProduct),
+				ExpectedCount:  nullIntPtr(expectedCount),
+			}
+		}
+
+		var product *model.Product
+		if productID.Valid {
+			product = &model.Product{
+				ID:           productID.String,
+				DateTime:     productDateTime.Time,
+				Type:         productType.String,
+				ReceptionID:  productReceptionID.String,
+				SerialNumber: serial.String,
+				Barcode:      barcode.String,
+				Status:       model.ProductStatus(productStatus.String),
+				CellID:       cellID.String,
+
+				Condition:     model.ProductCondition(condition.String),
+				ConditionNote: conditionNote.String,
+			}
+		}
+
+		if err := fn(*pvz, reception, product); err != nil {
+			return err
+		}
+	}
+
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("error iterating pvz rows: %w", err)
+	}
+
+	return nil
+}
+
+// pvzFilterConditions builds the PVZ-level WHERE conditions of a list
+// filter for the pvz table aliased as p.
+func pvzFilterConditions(filter dto.PVZFilterQuery) []sq.Sqlizer {
+	var conditions []sq.Sqlizer
+
+	if !filter.IncludeArchived {
+		conditions = append(conditions, sq.NotEq{"p.status": model.PVZStatusArchived})
+	}
+
+	if len(filter.City) > 0 {
+		conditions = append(conditions, sq.Eq{"p.city": filter.City})
+	}
+
+	if len(filter.PVZIDs) > 0 {
+		conditions = append(conditions, sq.Eq{"p.id": filter.PVZIDs})
+	}
+
+	// The product count is summed over all receptions of the PVZ that match
+	// the reception filter, not required of a single reception.
+	if filter.MinProducts > 0 {
+		productCount := sq.Select("COUNT(*)").
+			From(productTableName + " mp").
+			Join(receptionTableName + " mr ON mr.id = mp.reception_id").
+			Where("mr.pvz_id = p.id")
+
+		for _, condition := range receptionFilterConditions("mr", filter.ReceptionFilter()) {
+			productCount = productCount.Where(condition)
+		}
+
+		conditions = append(conditions, sq.Expr("(?) >= ?", productCount, filter.MinProducts))
+	}
+
+	return conditions
+}
+
+// pvzRowsOrderBy orders the rows of PVZs joined with their receptions (r)
+// and products (pr): PVZs by sort, then receptions and products from newest
+// to oldest, the same order GetPVZList and the per-PVZ loading produce.
+// Products are ordered by seq, since a batch shares one date_time.
+func pvzRowsOrderBy(sort string) []string {
+	return append(pvzOrderBy(sort), "p.id", "r.date_time DESC", "r.id", "pr.seq DESC")
+}
+
+func pvzOrderBy(sort string) []string {
+	switch sort {
+	case dto.PVZSortCity:
+		return []string{"p.city ASC", "p.registration_date DESC"}
+	case dto.PVZSortLastReception:
+		return []string{
+			"(SELECT MAX(lr.date_time) FROM receptions lr WHERE lr.pvz_id = p.id) DESC NULLS LAST",
+			"p.registration_date DESC",
+		}
+	default:
+		return []string{"p.registration_date DESC"}
+	}
+}
+
 func (r *PVZRepository) GetPVZByID(ctx context.Context, pvzID string) (*model.PVZ, error) {
-	query, args, err := r.psql.
-		Select("id", "registration_date", "city").
+	return r.getPVZ(ctx, pvzID, false)
+}
+
+// GetPVZForUpdate locks the PVZ row until the end of the current
+// transaction, so that status changes and operations that depend on the
+// status do not interleave.
+func (r *PVZRepository) GetPVZForUpdate(ctx context.Context, pvzID string) (*model.PVZ, error) {
+	return r.getPVZ(ctx, pvzID, true)
+}
+
+func (r *PVZRepository) getPVZ(ctx context.Context, pvzID string, forUpdate bool) (*model.PVZ, error) {
+	queryBuilder := r.psql.
+		Select(pvzColumns...).
 		From(pvzTableName).
+		Where(sq.Eq{"id": pvzID})
+
+	if forUpdate {
+		queryBuilder = queryBuilder.Suffix("FOR UPDATE")
+	}
+
+	query, args, err := queryBuilder.ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build sql query: %w", err)
+	}
+
+	pvz, err := scanPVZ(getQuerier(ctx, r.db).QueryRowContext(ctx, query, args...))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, model.ErrPVZNotFound
+		}
+		return nil, fmt.Errorf("failed to get pvz: %w", err)
+	}
+
+	return pvz, nil
+}
+
+func (r *PVZRepository) UpdatePVZStatus(ctx context.Context, pvzID string, status model.PVZStatus) (*model.PVZ, error) {
+	query, args, err := r.psql.
+		Update(pvzTableName).
+		Set("status", status).
 		Where(sq.Eq{"id": pvzID}).
+		Suffix("RETURNING " + columnList(pvzColumns)).
 		ToSql()
 
 	if err != nil {
 		return nil, fmt.Errorf("failed to build sql query: %w", err)
 	}
 
-	var pvz model.PVZ
-	err = r.db.QueryRowContext(ctx, query, args...).Scan(&pvz.ID, &pvz.RegistrationDate, &pvz.City)
+	pvz, err := scanPVZ(getQuerier(ctx, r.db).QueryRowContext(ctx, query, args...))
 	if err != nil {
 		if err == sql.ErrNoRows {
-			return nil, fmt.Errorf("pvz not found")
+			return nil, model.ErrPVZNotFound
+		}
+		return nil, fmt.Errorf("failed to update pvz status: %w", err)
+	}
+
+	return pvz, nil
+}
+
+func (r *PVZRepository) UpdatePVZ(ctx context.Context, pvzID string, pvzReq dto.PVZUpdateRequest) (*model.PVZ, error) {
+	queryBuilder := r.psql.
+		Update(pvzTableName).
+		Where(sq.Eq{"id": pvzID}).
+		Suffix("RETURNING " + columnList(pvzColumns))
+
+	if pvzReq.Address != nil {
+		queryBuilder = queryBuilder.Set("address", *pvzReq.Address)
+	}
+
+	if pvzReq.Latitude != nil {
+		queryBuilder = queryBuilder.Set("latitude", *pvzReq.Latitude)
+	}
+
+	if pvzReq.Longitude != nil {
+		queryBuilder = queryBuilder.Set("longitude", *pvzReq.Longitude)
+	}
+
+	if pvzReq.Phone != nil {
+		queryBuilder = queryBuilder.Set("phone", *pvzReq.Phone)
+	}
+
+	if pvzReq.WorkingHours != nil {
+		queryBuilder = queryBuilder.Set("working_hours", pvzReq.WorkingHours)
+	}
+
+	query, args, err := queryBuilder.ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build sql query: %w", err)
+	}
+
+	pvz, err := scanPVZ(getQuerier(ctx, r.db).QueryRowContext(ctx, query, args...))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, model.ErrPVZNotFound
+		}
+		return nil, fmt.Errorf("failed to update pvz: %w", err)
+	}
+
+	return pvz, nil
+}
+
+// GetNearbyPVZList returns active PVZs within query.Radius meters of the given point
+// ordered by distance. PVZs whose city is missing from the catalog are
+// returned with an empty timezone. A bounding box on the coordinates narrows
+// the rows before the exact distance is computed. When query.OpenNow is set up to
+// openNowCandidateLimit nearest PVZs are returned instead of query.Limit,
+// since working hours are checked by the caller outside of SQL.
+func (r *PVZRepository) GetNearbyPVZList(ctx context.Context, query dto.PVZNearbyQuery) ([]model.NearbyPVZ, error) {
+	latitude, longitude := *query.Latitude, *query.Longitude
+	latitudeDelta := query.Radius / metersPerDegree
+
+	innerBuilder := r.psql.
+		Select(prefixColumns("p", pvzColumns)...).
+		Column(sq.Alias(sq.Expr(haversineDistance, earthRadiusMeters, latitude, latitude, longitude), "distance")).
+		Column("c.timezone").
+		From(pvzTableName + " p").
+		LeftJoin("cities c ON c.name = p.city").
+		Where(sq.Eq{"p.status": model.PVZStatusActive}).
+		Where(sq.Expr("p.latitude BETWEEN ? AND ?", latitude-latitudeDelta, latitude+latitudeDelta))
+
+	// Near the poles and across the antimeridian the longitude range of the
+	// box degenerates, so only the latitude range is used there.
+	if cos := math.Cos(latitude * math.Pi / 180); cos > 0.01 {
+		longitudeDelta := query.Radius / (metersPerDegree * cos)
+		if longitude-longitudeDelta >= -180 && longitude+longitudeDelta <= 180 {
+			innerBuilder = innerBuilder.Where(sq.Expr(
+				"p.longitude BETWEEN ? AND ?", longitude-longitudeDelta, longitude+longitudeDelta,
+			))
+		}
+	}
+
+	if len(query.PVZIDs) > 0 {
+		innerBuilder = innerBuilder.Where(sq.Eq{"p.id": query.PVZIDs})
+	}
+
+	queryBuilder := r.psql.
+		Select("*").
+		FromSelect(innerBuilder, "nearby").
+		Where(sq.LtOrEq{"distance": query.Radius}).
+		OrderBy("distance")
+
+	limit := uint64(query.Limit)
+	if query.OpenNow {
+		limit = openNowCandidateLimit
+	}
+	queryBuilder = queryBuilder.Limit(limit)
+
+	sqlQuery, args, err := queryBuilder.ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build sql query: %w", err)
+	}
+
+	rows, err := getQuerier(ctx, r.db).QueryContext(ctx, sqlQuery, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query nearby pvz list: %w", err)
+	}
+	defer rows.Close()
+
+	nearbyList := make([]model.NearbyPVZ, 0)
+	for rows.Next() {
+		var (
+			nearby   model.NearbyPVZ
+			timezone sql.NullString
+		)
+
+		pvz, err := scanPVZ(rows, &nearby.Distance, &timezone)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan pvz row: %w", err)
+		}
+
+		nearby.PVZ = *pvz
+		nearby.Timezone = timezone.String
+		nearbyList = append(nearbyList, nearby)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating pvz rows: %w", err)
+	}
+
+	return nearbyList, nil
+}
+
+// scanPVZ scans the pvzColumns of a row followed by any extra columns.
+func scanPVZ(row rowScanner, extra ...any) (*model.PVZ, error) {
+	var (
+		pvz                 model.PVZ
+		latitude, longitude sql.NullFloat64
+		workingHours        []byte
+	)
+
+	dest := []any{
+		&pvz.ID, &pvz.RegistrationDate, &pvz.City, &pvz.Address,
+		&latitude, &longitude, &pvz.Phone, &workingHours, &pvz.Status,
+	}
+
+	if err := row.Scan(append(dest, extra...)...); err != nil {
+		return nil, err
+	}
+
+	if latitude.Valid {
+		pvz.Latitude = &latitude.Float64
+	}
+
+	if longitude.Valid {
+		pvz.Longitude = &longitude.Float64
+	}
+
+	if workingHours != nil {
+		pvz.WorkingHours = &model.WorkingHours{}
+		if err := pvz.WorkingHours.Scan(workingHours); err != nil {
+			return nil, err
 		}
-		return nil, fmt.Errorf("failed to get pvz: %w", err)
 	}
 
 	return &pvz, nil
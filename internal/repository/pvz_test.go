@@ -15,6 +15,10 @@ import (
 	"github.com/stretchr/testify/assert"
 )
 
+var pvzRowColumns = []string{
+	"id", "registration_date", "city", "address", "latitude", "longitude", "phone", "working_hours", "status",
+}
+
 func TestPVZRepository_CreatePVZ(t *testing.T) {
 	db, mock, err := sqlmock.New()
 	if err != nil {
@@ -39,11 +43,11 @@ func TestPVZRepository_CreatePVZ(t *testing.T) {
 				City: "Москва",
 			},
 			mockBehavior: func() {
-				rows := sqlmock.NewRows([]string{"id", "registration_date", "city"}).
-					AddRow("b0eebc99-9c0b-4ef8-bb6d-6bb9bd380a11", testTime, "Москва")
+				rows := sqlmock.NewRows(pvzRowColumns).
+					AddRow("b0eebc99-9c0b-4ef8-bb6d-6bb9bd380a11", testTime, "Москва", "", nil, nil, "", nil, "active")
 
 				mock.ExpectQuery(`INSERT INTO pvz`).
-					WithArgs(sqlmock.AnyArg(), "Москва").
+					WithArgs(sqlmock.AnyArg(), "Москва", "", nil, nil, "", nil).
 					WillReturnRows(rows)
 			},
 			expectedPVZ: &model.PVZ{
@@ -60,7 +64,7 @@ func TestPVZRepository_CreatePVZ(t *testing.T) {
 			},
 			mockBehavior: func() {
 				mock.ExpectQuery(`INSERT INTO pvz`).
-					WithArgs(sqlmock.AnyArg(), "Москва").
+					WithArgs(sqlmock.AnyArg(), "Москва", "", nil, nil, "", nil).
 					WillReturnError(errors.New("db error"))
 			},
 			expectedPVZ:   nil,
@@ -117,11 +121,11 @@ func TestPVZRepository_GetPVZList(t *testing.T) {
 				Limit: 10,
 			},
 			mockBehavior: func() {
-				rows := sqlmock.NewRows([]string{"id", "registration_date", "city"}).
-					AddRow("b0eebc99-9c0b-4ef8-bb6d-6bb9bd380a11", testTime, "Москва").
-					AddRow("b0eebc99-9c0b-4ef8-bb6d-6bb9bd380a12", testTime, "Санкт-Петербург")
+				rows := sqlmock.NewRows(pvzRowColumns).
+					AddRow("b0eebc99-9c0b-4ef8-bb6d-6bb9bd380a11", testTime, "Москва", "", nil, nil, "", nil, "active").
+					AddRow("b0eebc99-9c0b-4ef8-bb6d-6bb9bd380a12", testTime, "Санкт-Петербург", "", nil, nil, "", nil, "active")
 
-				mock.ExpectQuery(regexp.QuoteMeta(`SELECT p.id, p.registration_date, p.city FROM pvz p ORDER BY p.registration_date DESC LIMIT 10 OFFSET 0`)).
+				mock.ExpectQuery(regexp.QuoteMeta(`SELECT p.id, p.registration_date, p.city, p.address, p.latitude, p.longitude, p.phone, p.working_hours, p.status FROM pvz p WHERE p.status <> $1 ORDER BY p.registration_date DESC LIMIT 10 OFFSET 0`)).
 					WillReturnRows(rows)
 			},
 			expectedValue: []model.PVZ{
@@ -147,11 +151,171 @@ func TestPVZRepository_GetPVZList(t *testing.T) {
 				EndDate:   &testTime,
 			},
 			mockBehavior: func() {
-				rows := sqlmock.NewRows([]string{"id", "registration_date", "city"}).
-					AddRow("b0eebc99-9c0b-4ef8-bb6d-6bb9bd380a11", testTime, "Москва")
+				rows := sqlmock.NewRows(pvzRowColumns).
+					AddRow("b0eebc99-9c0b-4ef8-bb6d-6bb9bd380a11", testTime, "Москва", "", nil, nil, "", nil, "active")
+
+				mock.ExpectQuery(regexp.QuoteMeta(`SELECT p.id, p.registration_date, p.city, p.address, p.latitude, p.longitude, p.phone, p.working_hours, p.status FROM pvz p JOIN receptions r ON p.id = r.pvz_id WHERE p.status <> $1 AND r.date_time >= $2 AND r.date_time <= $3 GROUP BY p.id ORDER BY p.registration_date DESC LIMIT 10 OFFSET 0`)).
+					WithArgs(model.PVZStatusArchived, testTime, testTime).
+					WillReturnRows(rows)
+			},
+			expectedValue: []model.PVZ{
+				{
+					ID:               "b0eebc99-9c0b-4ef8-bb6d-6bb9bd380a11",
+					RegistrationDate: testTime,
+					City:             "Москва",
+				},
+			},
+			expectedError: nil,
+		},
+		{
+			name: "Success Including Archived",
+			filter: dto.PVZFilterQuery{
+				Page:            1,
+				Limit:           10,
+				IncludeArchived: true,
+			},
+			mockBehavior: func() {
+				rows := sqlmock.NewRows(pvzRowColumns).
+					AddRow("b0eebc99-9c0b-4ef8-bb6d-6bb9bd380a11", testTime, "Москва", "", nil, nil, "", nil, "archived")
+
+				mock.ExpectQuery(regexp.QuoteMeta(`SELECT p.id, p.registration_date, p.city, p.address, p.latitude, p.longitude, p.phone, p.working_hours, p.status FROM pvz p ORDER BY p.registration_date DESC LIMIT 10 OFFSET 0`)).
+					WillReturnRows(rows)
+			},
+			expectedValue: []model.PVZ{
+				{
+					ID:               "b0eebc99-9c0b-4ef8-bb6d-6bb9bd380a11",
+					RegistrationDate: testTime,
+					City:             "Москва",
+					Status:           model.PVZStatusArchived,
+				},
+			},
+			expectedError: nil,
+		},
+		{
+			name: "Success Limited To PVZ IDs",
+			filter: dto.PVZFilterQuery{
+				Page:   1,
+				Limit:  10,
+				PVZIDs: []string{"b0eebc99-9c0b-4ef8-bb6d-6bb9bd380a11"},
+			},
+			mockBehavior: func() {
+				rows := sqlmock.NewRows(pvzRowColumns).
+					AddRow("b0eebc99-9c0b-4ef8-bb6d-6bb9bd380a11", testTime, "Москва", "", nil, nil, "", nil, "active")
+
+				mock.ExpectQuery(regexp.QuoteMeta(`SELECT p.id, p.registration_date, p.city, p.address, p.latitude, p.longitude, p.phone, p.working_hours, p.status FROM pvz p WHERE p.status <> $1 AND p.id IN ($2) ORDER BY p.registration_date DESC LIMIT 10 OFFSET 0`)).
+					WithArgs(model.PVZStatusArchived, "b0eebc99-9c0b-4ef8-bb6d-6bb9bd380a11").
+					WillReturnRows(rows)
+			},
+			expectedValue: []model.PVZ{
+				{
+					ID:               "b0eebc99-9c0b-4ef8-bb6d-6bb9bd380a11",
+					RegistrationDate: testTime,
+					City:             "Москва",
+					Status:           model.PVZStatusActive,
+				},
+			},
+			expectedError: nil,
+		},
+		{
+			name: "Success With City And Reception Filters",
+			filter: dto.PVZFilterQuery{
+				Page:             1,
+				Limit:            10,
+				City:             []string{"Казань"},
+				HasOpenReception: true,
+				ProductType:      "электроника",
+			},
+			mockBehavior: func() {
+				rows := sqlmock.NewRows(pvzRowColumns).
+					AddRow("b0eebc99-9c0b-4ef8-bb6d-6bb9bd380a11", testTime, "Казань", "", nil, nil, "", nil, "active")
+
+				mock.ExpectQuery(regexp.QuoteMeta(`SELECT p.id, p.registration_date, p.city, p.address, p.latitude, p.longitude, p.phone, p.working_hours, p.status FROM pvz p JOIN receptions r ON p.id = r.pvz_id WHERE p.status <> $1 AND p.city IN ($2) AND r.status = $3 AND EXISTS (SELECT 1 FROM products pr WHERE pr.reception_id = r.id AND pr.type = $4) GROUP BY p.id ORDER BY p.registration_date DESC LIMIT 10 OFFSET 0`)).
+					WithArgs(model.PVZStatusArchived, "Казань", "in_progress", "электроника").
+					WillReturnRows(rows)
+			},
+			expectedValue: []model.PVZ{
+				{
+					ID:               "b0eebc99-9c0b-4ef8-bb6d-6bb9bd380a11",
+					RegistrationDate: testTime,
+					City:             "Казань",
+				},
+			},
+			expectedError: nil,
+		},
+		{
+			name: "Success Sorted By City",
+			filter: dto.PVZFilterQuery{
+				Page:            1,
+				Limit:           10,
+				IncludeArchived: true,
+				Sort:            dto.PVZSortCity,
+			},
+			mockBehavior: func() {
+				rows := sqlmock.NewRows(pvzRowColumns).
+					AddRow("b0eebc99-9c0b-4ef8-bb6d-6bb9bd380a11", testTime, "Казань", "", nil, nil, "", nil, "active").
+					AddRow("b0eebc99-9c0b-4ef8-bb6d-6bb9bd380a12", testTime, "Москва", "", nil, nil, "", nil, "active")
+
+				mock.ExpectQuery(regexp.QuoteMeta(`SELECT p.id, p.registration_date, p.city, p.address, p.latitude, p.longitude, p.phone, p.working_hours, p.status FROM pvz p ORDER BY p.city ASC, p.registration_date DESC LIMIT 10 OFFSET 0`)).
+					WillReturnRows(rows)
+			},
+			expectedValue: []model.PVZ{
+				{
+					ID:               "b0eebc99-9c0b-4ef8-bb6d-6bb9bd380a11",
+					RegistrationDate: testTime,
+					City:             "Казань",
+				},
+				{
+					ID:               "b0eebc99-9c0b-4ef8-bb6d-6bb9bd380a12",
+					RegistrationDate: testTime,
+					City:             "Москва",
+				},
+			},
+			expectedError: nil,
+		},
+		{
+			name: "Success With Min Products Per PVZ",
+			filter: dto.PVZFilterQuery{
+				Page:            1,
+				Limit:           10,
+				IncludeArchived: true,
+				ProductType:     "обувь",
+				MinProducts:     5,
+			},
+			mockBehavior: func() {
+				rows := sqlmock.NewRows(pvzRowColumns).
+					AddRow("b0eebc99-9c0b-4ef8-bb6d-6bb9bd380a11", testTime, "Москва", "", nil, nil, "", nil, "active")
+
+				mock.ExpectQuery(regexp.QuoteMeta(`SELECT p.id, p.registration_date, p.city, p.address, p.latitude, p.longitude, p.phone, p.working_hours, p.status FROM pvz p `+
+					`JOIN receptions r ON p.id = r.pvz_id `+
+					`WHERE (SELECT COUNT(*) FROM products mp JOIN receptions mr ON mr.id = mp.reception_id WHERE mr.pvz_id = p.id `+
+					`AND EXISTS (SELECT 1 FROM products pr WHERE pr.reception_id = mr.id AND pr.type = $1)) >= $2 `+
+					`AND EXISTS (SELECT 1 FROM products pr WHERE pr.reception_id = r.id AND pr.type = $3) `+
+					`GROUP BY p.id ORDER BY p.registration_date DESC LIMIT 10 OFFSET 0`)).
+					WithArgs("обувь", int32(5), "обувь").
+					WillReturnRows(rows)
+			},
+			expectedValue: []model.PVZ{
+				{
+					ID:               "b0eebc99-9c0b-4ef8-bb6d-6bb9bd380a11",
+					RegistrationDate: testTime,
+					City:             "Москва",
+				},
+			},
+			expectedError: nil,
+		},
+		{
+			name: "Success Sorted By Last Reception",
+			filter: dto.PVZFilterQuery{
+				Page:            1,
+				Limit:           10,
+				IncludeArchived: true,
+				Sort:            dto.PVZSortLastReception,
+			},
+			mockBehavior: func() {
+				rows := sqlmock.NewRows(pvzRowColumns).
+					AddRow("b0eebc99-9c0b-4ef8-bb6d-6bb9bd380a11", testTime, "Москва", "", nil, nil, "", nil, "active")
 
-				mock.ExpectQuery(regexp.QuoteMeta(`SELECT p.id, p.registration_date, p.city FROM pvz p JOIN receptions r ON p.id = r.pvz_id WHERE r.date_time >= $1 AND r.date_time <= $2 GROUP BY p.id ORDER BY p.registration_date DESC LIMIT 10 OFFSET 0`)).
-					WithArgs(testTime, testTime).
+				mock.ExpectQuery(regexp.QuoteMeta(`SELECT p.id, p.registration_date, p.city, p.address, p.latitude, p.longitude, p.phone, p.working_hours, p.status FROM pvz p ORDER BY (SELECT MAX(lr.date_time) FROM receptions lr WHERE lr.pvz_id = p.id) DESC NULLS LAST, p.registration_date DESC LIMIT 10 OFFSET 0`)).
 					WillReturnRows(rows)
 			},
 			expectedValue: []model.PVZ{
@@ -170,9 +334,9 @@ func TestPVZRepository_GetPVZList(t *testing.T) {
 				Limit: 10,
 			},
 			mockBehavior: func() {
-				rows := sqlmock.NewRows([]string{"id", "registration_date", "city"})
+				rows := sqlmock.NewRows(pvzRowColumns)
 
-				mock.ExpectQuery(regexp.QuoteMeta(`SELECT p.id, p.registration_date, p.city FROM pvz p ORDER BY p.registration_date DESC LIMIT 10 OFFSET 0`)).
+				mock.ExpectQuery(regexp.QuoteMeta(`SELECT p.id, p.registration_date, p.city, p.address, p.latitude, p.longitude, p.phone, p.working_hours, p.status FROM pvz p WHERE p.status <> $1 ORDER BY p.registration_date DESC LIMIT 10 OFFSET 0`)).
 					WillReturnRows(rows)
 			},
 			expectedValue: []model.PVZ{},
@@ -185,7 +349,7 @@ func TestPVZRepository_GetPVZList(t *testing.T) {
 				Limit: 10,
 			},
 			mockBehavior: func() {
-				mock.ExpectQuery(regexp.QuoteMeta(`SELECT p.id, p.registration_date, p.city FROM pvz p ORDER BY p.registration_date DESC LIMIT 10 OFFSET 0`)).
+				mock.ExpectQuery(regexp.QuoteMeta(`SELECT p.id, p.registration_date, p.city, p.address, p.latitude, p.longitude, p.phone, p.working_hours, p.status FROM pvz p WHERE p.status <> $1 ORDER BY p.registration_date DESC LIMIT 10 OFFSET 0`)).
 					WillReturnError(errors.New("db error"))
 			},
 			expectedValue: nil,
@@ -243,10 +407,10 @@ func TestPVZRepository_GetPVZByID(t *testing.T) {
 			name:  "Success",
 			pvzID: "b0eebc99-9c0b-4ef8-bb6d-6bb9bd380a11",
 			mockBehavior: func() {
-				rows := sqlmock.NewRows([]string{"id", "registration_date", "city"}).
-					AddRow("b0eebc99-9c0b-4ef8-bb6d-6bb9bd380a11", testTime, "Москва")
+				rows := sqlmock.NewRows(pvzRowColumns).
+					AddRow("b0eebc99-9c0b-4ef8-bb6d-6bb9bd380a11", testTime, "Москва", "", nil, nil, "", nil, "active")
 
-				mock.ExpectQuery(regexp.QuoteMeta(`SELECT id, registration_date, city FROM pvz WHERE id = $1`)).
+				mock.ExpectQuery(regexp.QuoteMeta(`SELECT id, registration_date, city, address, latitude, longitude, phone, working_hours, status FROM pvz WHERE id = $1`)).
 					WithArgs("b0eebc99-9c0b-4ef8-bb6d-6bb9bd380a11").
 					WillReturnRows(rows)
 			},
@@ -261,7 +425,7 @@ func TestPVZRepository_GetPVZByID(t *testing.T) {
 			name:  "PVZ Not Found",
 			pvzID: "b0eebc99-9c0b-4ef8-bb6d-6bb9bd380a99",
 			mockBehavior: func() {
-				mock.ExpectQuery(regexp.QuoteMeta(`SELECT id, registration_date, city FROM pvz WHERE id = $1`)).
+				mock.ExpectQuery(regexp.QuoteMeta(`SELECT id, registration_date, city, address, latitude, longitude, phone, working_hours, status FROM pvz WHERE id = $1`)).
 					WithArgs("b0eebc99-9c0b-4ef8-bb6d-6bb9bd380a99").
 					WillReturnError(sql.ErrNoRows)
 			},
@@ -272,7 +436,7 @@ func TestPVZRepository_GetPVZByID(t *testing.T) {
 			name:  "DB Error",
 			pvzID: "b0eebc99-9c0b-4ef8-bb6d-6bb9bd380a11",
 			mockBehavior: func() {
-				mock.ExpectQuery(regexp.QuoteMeta(`SELECT id, registration_date, city FROM pvz WHERE id = $1`)).
+				mock.ExpectQuery(regexp.QuoteMeta(`SELECT id, registration_date, city, address, latitude, longitude, phone, working_hours, status FROM pvz WHERE id = $1`)).
 					WithArgs("b0eebc99-9c0b-4ef8-bb6d-6bb9bd380a11").
 					WillReturnError(errors.New("db error"))
 			},
@@ -304,3 +468,475 @@ func TestPVZRepository_GetPVZByID(t *testing.T) {
 		})
 	}
 }
+
+func TestPVZRepository_GetPVZForUpdate(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	pvzRepo := repository.NewPVZRepository(db)
+	testTime := time.Now()
+
+	rows := sqlmock.NewRows(pvzRowColumns).
+		AddRow("b0eebc99-9c0b-4ef8-bb6d-6bb9bd380a11", testTime, "Москва", "", nil, nil, "", nil, "suspended")
+
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT id, registration_date, city, address, latitude, longitude, phone, working_hours, status FROM pvz WHERE id = $1 FOR UPDATE`)).
+		WithArgs("b0eebc99-9c0b-4ef8-bb6d-6bb9bd380a11").
+		WillReturnRows(rows)
+
+	pvz, err := pvzRepo.GetPVZForUpdate(context.Background(), "b0eebc99-9c0b-4ef8-bb6d-6bb9bd380a11")
+
+	assert.NoError(t, err)
+	assert.Equal(t, model.PVZStatusSuspended, pvz.Status)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func TestPVZRepository_UpdatePVZStatus(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	pvzRepo := repository.NewPVZRepository(db)
+	ctx := context.Background()
+	testTime := time.Now()
+
+	tests := []struct {
+		name           string
+		pvzID          string
+		mockBehavior   func()
+		expectedStatus model.PVZStatus
+		expectedError  error
+	}{
+		{
+			name:  "Success",
+			pvzID: "b0eebc99-9c0b-4ef8-bb6d-6bb9bd380a11",
+			mockBehavior: func() {
+				rows := sqlmock.NewRows(pvzRowColumns).
+					AddRow("b0eebc99-9c0b-4ef8-bb6d-6bb9bd380a11", testTime, "Москва", "", nil, nil, "", nil, "suspended")
+
+				mock.ExpectQuery(regexp.QuoteMeta(`UPDATE pvz SET status = $1 WHERE id = $2 RETURNING id, registration_date, city, address, latitude, longitude, phone, working_hours, status`)).
+					WithArgs(model.PVZStatusSuspended, "b0eebc99-9c0b-4ef8-bb6d-6bb9bd380a11").
+					WillReturnRows(rows)
+			},
+			expectedStatus: model.PVZStatusSuspended,
+			expectedError:  nil,
+		},
+		{
+			name:  "PVZ Not Found",
+			pvzID: "b0eebc99-9c0b-4ef8-bb6d-6bb9bd380a99",
+			mockBehavior: func() {
+				mock.ExpectQuery(regexp.QuoteMeta(`UPDATE pvz SET status = $1 WHERE id = $2`)).
+					WithArgs(model.PVZStatusSuspended, "b0eebc99-9c0b-4ef8-bb6d-6bb9bd380a99").
+					WillReturnError(sql.ErrNoRows)
+			},
+			expectedError: model.ErrPVZNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockBehavior()
+
+			pvz, err := pvzRepo.UpdatePVZStatus(ctx, tt.pvzID, model.PVZStatusSuspended)
+
+			if tt.expectedError != nil {
+				assert.ErrorIs(t, err, tt.expectedError)
+				assert.Nil(t, pvz)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expectedStatus, pvz.Status)
+			}
+
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("there were unfulfilled expectations: %s", err)
+			}
+		})
+	}
+}
+
+func TestPVZRepository_UpdatePVZ(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	pvzRepo := repository.NewPVZRepository(db)
+	ctx := context.Background()
+	testTime := time.Now()
+
+	address := "ул. Тверская, 1"
+	latitude, longitude := 55.7575, 37.6136
+
+	tests := []struct {
+		name          string
+		pvzID         string
+		pvzReq        dto.PVZUpdateRequest
+		mockBehavior  func()
+		expectedPVZ   *model.PVZ
+		expectedError error
+	}{
+		{
+			name:  "Success",
+			pvzID: "b0eebc99-9c0b-4ef8-bb6d-6bb9bd380a11",
+			pvzReq: dto.PVZUpdateRequest{
+				Address:   &address,
+				Latitude:  &latitude,
+				Longitude: &longitude,
+			},
+			mockBehavior: func() {
+				rows := sqlmock.NewRows(pvzRowColumns).
+					AddRow(
+						"b0eebc99-9c0b-4ef8-bb6d-6bb9bd380a11", testTime, "Москва", address,
+						latitude, longitude, "+74951234567",
+						[]byte(`{"weekly":[{"day":"monday","open":"09:00","close":"21:00"}]}`), "active",
+					)
+
+				mock.ExpectQuery(regexp.QuoteMeta(`UPDATE pvz SET address = $1, latitude = $2, longitude = $3 WHERE id = $4 RETURNING id, registration_date, city, address, latitude, longitude, phone, working_hours, status`)).
+					WithArgs(address, latitude, longitude, "b0eebc99-9c0b-4ef8-bb6d-6bb9bd380a11").
+					WillReturnRows(rows)
+			},
+			expectedPVZ: &model.PVZ{
+				ID:               "b0eebc99-9c0b-4ef8-bb6d-6bb9bd380a11",
+				RegistrationDate: testTime,
+				City:             "Москва",
+				Address:          address,
+				Latitude:         &latitude,
+				Longitude:        &longitude,
+				Phone:            "+74951234567",
+				WorkingHours: &model.WorkingHours{
+					Weekly: []model.DailyHours{{Day: "monday", Open: "09:00", Close: "21:00"}},
+				},
+				Status: model.PVZStatusActive,
+			},
+			expectedError: nil,
+		},
+		{
+			name:  "PVZ Not Found",
+			pvzID: "b0eebc99-9c0b-4ef8-bb6d-6bb9bd380a99",
+			pvzReq: dto.PVZUpdateRequest{
+				Address: &address,
+			},
+			mockBehavior: func() {
+				mock.ExpectQuery(regexp.QuoteMeta(`UPDATE pvz SET address = $1 WHERE id = $2`)).
+					WithArgs(address, "b0eebc99-9c0b-4ef8-bb6d-6bb9bd380a99").
+					WillReturnError(sql.ErrNoRows)
+			},
+			expectedPVZ:   nil,
+			expectedError: model.ErrPVZNotFound,
+		},
+		{
+			name:  "DB Error",
+			pvzID: "b0eebc99-9c0b-4ef8-bb6d-6bb9bd380a11",
+			pvzReq: dto.PVZUpdateRequest{
+				Address: &address,
+			},
+			mockBehavior: func() {
+				mock.ExpectQuery(regexp.QuoteMeta(`UPDATE pvz SET address = $1 WHERE id = $2`)).
+					WithArgs(address, "b0eebc99-9c0b-4ef8-bb6d-6bb9bd380a11").
+					WillReturnError(errors.New("db error"))
+			},
+			expectedPVZ:   nil,
+			expectedError: errors.New("failed to update pvz: db error"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockBehavior()
+
+			pvz, err := pvzRepo.UpdatePVZ(ctx, tt.pvzID, tt.pvzReq)
+
+			if tt.expectedError != nil {
+				assert.Error(t, err)
+				assert.Contains(t, err.Error(), tt.expectedError.Error())
+				assert.Nil(t, pvz)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expectedPVZ, pvz)
+			}
+
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("there were unfulfilled expectations: %s", err)
+			}
+		})
+	}
+}
+
+func TestPVZRepository_GetNearbyPVZList(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	pvzRepo := repository.NewPVZRepository(db)
+	ctx := context.Background()
+	testTime := time.Now()
+
+	latitude, longitude := 55.75, 37.61
+	antimeridianLongitude := 179.99
+	pvzLatitude, pvzLongitude := 55.7575, 37.6136
+	nearbyColumns := append(append([]string{}, pvzRowColumns...), "distance", "timezone")
+	nearbySelect := `SELECT * FROM (SELECT p.id, p.registration_date, p.city, p.address, p.latitude, p.longitude, ` +
+		`p.phone, p.working_hours, p.status, (2 * $1 * ASIN(`
+
+	tests := []struct {
+		name          string
+		query         dto.PVZNearbyQuery
+		mockBehavior  func()
+		expectedValue []model.NearbyPVZ
+		expectedError error
+	}{
+		{
+			name: "Success",
+			query: dto.PVZNearbyQuery{
+				Latitude:  &latitude,
+				Longitude: &longitude,
+				Radius:    5000,
+				Limit:     10,
+			},
+			mockBehavior: func() {
+				rows := sqlmock.NewRows(nearbyColumns).
+					AddRow(
+						"b0eebc99-9c0b-4ef8-bb6d-6bb9bd380a11", testTime, "Москва", "", pvzLatitude, pvzLongitude,
+						"", nil, "active", 946.2, "Europe/Moscow",
+					).
+					AddRow(
+						"c0eebc99-9c0b-4ef8-bb6d-6bb9bd380a11", testTime, "Атлантида", "", pvzLatitude, pvzLongitude,
+						"", nil, "active", 1210.5, nil,
+					)
+
+				mock.ExpectQuery(regexp.QuoteMeta(nearbySelect)+`.+`+regexp.QuoteMeta(
+					`AS distance, c.timezone FROM pvz p LEFT JOIN cities c ON c.name = p.city `+
+						`WHERE p.status = $5 AND p.latitude BETWEEN $6 AND $7 AND p.longitude BETWEEN $8 AND $9) AS nearby `+
+						`WHERE distance <= $10 ORDER BY distance LIMIT 10`,
+				)).
+					WithArgs(
+						6371000.0, latitude, latitude, longitude, model.PVZStatusActive,
+						sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(),
+						5000.0,
+					).
+					WillReturnRows(rows)
+			},
+			expectedValue: []model.NearbyPVZ{
+				{
+					PVZ: model.PVZ{
+						ID:               "b0eebc99-9c0b-4ef8-bb6d-6bb9bd380a11",
+						RegistrationDate: testTime,
+						City:             "Москва",
+						Latitude:         &pvzLatitude,
+						Longitude:        &pvzLongitude,
+						Status:           model.PVZStatusActive,
+					},
+					Distance: 946.2,
+					Timezone: "Europe/Moscow",
+				},
+				{
+					PVZ: model.PVZ{
+						ID:               "c0eebc99-9c0b-4ef8-bb6d-6bb9bd380a11",
+						RegistrationDate: testTime,
+						City:             "Атлантида",
+						Latitude:         &pvzLatitude,
+						Longitude:        &pvzLongitude,
+						Status:           model.PVZStatusActive,
+					},
+					Distance: 1210.5,
+				},
+			},
+			expectedError: nil,
+		},
+		{
+			name: "Open Now With Candidate Limit",
+			query: dto.PVZNearbyQuery{
+				Latitude:  &latitude,
+				Longitude: &longitude,
+				Radius:    5000,
+				Limit:     10,
+				OpenNow:   true,
+			},
+			mockBehavior: func() {
+				mock.ExpectQuery(regexp.QuoteMeta(`WHERE distance <= $10 ORDER BY distance LIMIT 500`) + `$`).
+					WillReturnRows(sqlmock.NewRows(nearbyColumns))
+			},
+			expectedValue: []model.NearbyPVZ{},
+			expectedError: nil,
+		},
+		{
+			name: "Antimeridian Without Longitude Box",
+			query: dto.PVZNearbyQuery{
+				Latitude:  &latitude,
+				Longitude: &antimeridianLongitude,
+				Radius:    5000,
+				Limit:     10,
+			},
+			mockBehavior: func() {
+				mock.ExpectQuery(regexp.QuoteMeta(`WHERE p.status = $5 AND p.latitude BETWEEN $6 AND $7) AS nearby WHERE distance <= $8`)).
+					WillReturnRows(sqlmock.NewRows(nearbyColumns))
+			},
+			expectedValue: []model.NearbyPVZ{},
+			expectedError: nil,
+		},
+		{
+			name: "DB Error",
+			query: dto.PVZNearbyQuery{
+				Latitude:  &latitude,
+				Longitude: &longitude,
+				Radius:    5000,
+				Limit:     10,
+			},
+			mockBehavior: func() {
+				mock.ExpectQuery(`SELECT \* FROM`).
+					WillReturnError(errors.New("db error"))
+			},
+			expectedValue: nil,
+			expectedError: errors.New("failed to query nearby pvz list: db error"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockBehavior()
+
+			nearbyList, err := pvzRepo.GetNearbyPVZList(ctx, tt.query)
+
+			if tt.expectedError != nil {
+				assert.Error(t, err)
+				assert.Contains(t, err.Error(), tt.expectedError.Error())
+				assert.Nil(t, nearbyList)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expectedValue, nearbyList)
+			}
+
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("there were unfulfilled expectations: %s", err)
+			}
+		})
+	}
+}
+
+func TestPVZRepository_StreamPVZList(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	pvzRepo := repository.NewPVZRepository(db)
+	ctx := context.Background()
+	testTime := time.Now()
+
+	pvzID := "b0eebc99-9c0b-4ef8-bb6d-6bb9bd380a11"
+	receptionID := "c0eebc99-9c0b-4ef8-bb6d-6bb9bd380a11"
+
+	streamColumns := append(append(append([]string{}, pvzRowColumns...), receptionRowColumns...),
+		"id", "date_time", "type", "reception_id", "serial_number", "barcode", "status", "cell_id", "condition", "condition_note")
+	streamSelect := `SELECT p.id, p.registration_date, p.city, p.address, p.latitude, p.longitude, p.phone, p.working_hours, p.status, r.id, r.date_time, r.pvz_id, r.status, r.closed_at, r.first_product_at, r.last_product_at, r.expected_count, pr.id, pr.date_time, pr.type, pr.reception_id, pr.serial_number, pr.barcode, pr.status, pr.cell_id, pr.condition, pr.condition_note FROM pvz p`
+
+	type streamedRow struct {
+		pvzID       string
+		receptionID string
+		productID   string
+	}
+
+	tests := []struct {
+		name          string
+		filter        dto.PVZFilterQuery
+		mockBehavior  func()
+		expectedRows  []streamedRow
+		expectedError error
+	}{
+		{
+			name: "Success Without Reception Filters",
+			filter: dto.PVZFilterQuery{
+				Page:  1,
+				Limit: 10,
+			},
+			mockBehavior: func() {
+				rows := sqlmock.NewRows(streamColumns).
+					AddRow(pvzID, testTime, "Москва", "", nil, nil, "", nil, "active",
+						receptionID, testTime, pvzID, "close", testTime, testTime, testTime, 3,
+						"d0eebc99-9c0b-4ef8-bb6d-6bb9bd380a11", testTime, "обувь", receptionID, nil, nil, "received", nil, "damaged", "box torn").
+					AddRow("b0eebc99-9c0b-4ef8-bb6d-6bb9bd380a12", testTime, "Казань", "", nil, nil, "", nil, "active",
+						nil, nil, nil, nil, nil, nil, nil, nil,
+						nil, nil, nil, nil, nil, nil, nil, nil, nil, nil)
+
+				mock.ExpectQuery(regexp.QuoteMeta(streamSelect + ` LEFT JOIN receptions r ON r.pvz_id = p.id LEFT JOIN products pr ON pr.reception_id = r.id WHERE p.status <> $1 ORDER BY p.registration_date DESC, p.id, r.date_time DESC, r.id, pr.seq DESC`)).
+					WithArgs(model.PVZStatusArchived).
+					WillReturnRows(rows)
+			},
+			expectedRows: []streamedRow{
+				{pvzID: pvzID, receptionID: receptionID, productID: "d0eebc99-9c0b-4ef8-bb6d-6bb9bd380a11"},
+				{pvzID: "b0eebc99-9c0b-4ef8-bb6d-6bb9bd380a12"},
+			},
+		},
+		{
+			name: "Success With Reception Filters",
+			filter: dto.PVZFilterQuery{
+				StartDate: &testTime,
+				City:      []string{"Москва"},
+			},
+			mockBehavior: func() {
+				rows := sqlmock.NewRows(streamColumns).
+					AddRow(pvzID, testTime, "Москва", "", nil, nil, "", nil, "active",
+						receptionID, testTime, pvzID, "in_progress", nil, nil, nil, nil,
+						nil, nil, nil, nil, nil, nil, nil, nil, nil, nil)
+
+				mock.ExpectQuery(regexp.QuoteMeta(streamSelect+` LEFT JOIN receptions r ON r.pvz_id = p.id AND (r.date_time >= $1) LEFT JOIN products pr ON pr.reception_id = r.id WHERE r.id IS NOT NULL AND p.status <> $2 AND p.city IN ($3) ORDER BY`)).
+					WithArgs(testTime, model.PVZStatusArchived, "Москва").
+					WillReturnRows(rows)
+			},
+			expectedRows: []streamedRow{
+				{pvzID: pvzID, receptionID: receptionID},
+			},
+		},
+		{
+			name:   "DB Error",
+			filter: dto.PVZFilterQuery{IncludeArchived: true},
+			mockBehavior: func() {
+				mock.ExpectQuery(regexp.QuoteMeta(streamSelect)).
+					WillReturnError(errors.New("db error"))
+			},
+			expectedError: errors.New("failed to query pvz list: db error"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockBehavior()
+
+			var streamed []streamedRow
+			err := pvzRepo.StreamPVZList(ctx, tt.filter, func(pvz model.PVZ, reception *model.Reception, product *model.Product) error {
+				row := streamedRow{pvzID: pvz.ID}
+				if reception != nil {
+					row.receptionID = reception.ID
+				}
+				if product != nil {
+					row.productID = product.ID
+				}
+				streamed = append(streamed, row)
+				return nil
+			})
+
+			if tt.expectedError != nil {
+				assert.Error(t, err)
+				assert.Contains(t, err.Error(), tt.expectedError.Error())
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expectedRows, streamed)
+			}
+
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("there were unfulfilled expectations: %s", err)
+			}
+		})
+	}
+}
@@ -15,12 +15,25 @@ const (
 	receptionTableName = "receptions"
 )
 
+var receptionColumns = []string{
+	"id", "date_time", "pvz_id", "status", "closed_at", "first_product_at", "last_product_at", "expected_count",
+}
+
 type ReceptionRepositoryInterface interface {
 	CreateReception(ctx context.Context, receptionCreateReq dto.ReceptionCreateRequest) (*model.Reception, error)
 	HasOpenReception(ctx context.Context, pvzID string) (bool, error)
 	GetLastOpenReception(ctx context.Context, pvzID string) (*model.Reception, error)
+	GetLastOpenReceptionForUpdate(ctx context.Context, pvzID string) (*model.Reception, error)
+	GetReceptionByID(ctx context.Context, receptionID string) (*model.Reception, error)
+	GetReceptionForUpdate(ctx context.Context, receptionID string) (*model.Reception, error)
+	HasNewerReception(ctx context.Context, reception *model.Reception) (bool, error)
+	HasProcessedProducts(ctx context.Context, receptionID string) (bool, error)
 	CloseReception(ctx context.Context, receptionID string) (*model.Reception, error)
-	GetReceptionsByPVZID(ctx context.Context, pvzID string, startDate, endDate *time.Time) ([]model.Reception, error)
+	ReopenReception(ctx context.Context, receptionID string) (*model.Reception, error)
+	GetReceptionsByPVZID(ctx context.Context, pvzID string, filter dto.ReceptionFilter) ([]model.Reception, error)
+	SetExpectedCount(ctx context.Context, receptionID string, expectedCount int) (*model.Reception, error)
+	GetReceptionDiscrepancy(ctx context.Context, receptionID string) (*model.ReceptionDiscrepancy, error)
+	GetReceptionDiscrepancies(ctx context.Context, filter dto.DiscrepancyFilterQuery) ([]model.ReceptionDiscrepancy, error)
 }
 
 type ReceptionRepository struct {
@@ -45,25 +58,24 @@ func (r *ReceptionRepository) CreateReception(ctx context.Context, receptionCrea
 		return nil, fmt.Errorf("there is already an open reception for this PVZ")
 	}
 
-	dateTime := time.Now()
+	dateTime := time.Now().UTC()
 	query, args, err := r.psql.
 		Insert(receptionTableName).
-		Columns("date_time", "pvz_id", "status").
-		Values(dateTime, receptionCreateReq.PVZID, "in_progress").
-		Suffix("RETURNING id, date_time, pvz_id, status").
+		Columns("date_time", "pvz_id", "status", "expected_count").
+		Values(dateTime, receptionCreateReq.PVZID, "in_progress", receptionCreateReq.ExpectedCount).
+		Suffix("RETURNING " + columnList(receptionColumns)).
 		ToSql()
 
 	if err != nil {
 		return nil, fmt.Errorf("failed to build sql query: %w", err)
 	}
 
-	var reception model.Reception
-	err = r.db.QueryRowContext(ctx, query, args...).Scan(&reception.ID, &reception.DateTime, &reception.PVZID, &reception.Status)
+	reception, err := scanReception(getQuerier(ctx, r.db).QueryRowContext(ctx, query, args...))
 	if err != nil {
 		return nil, fmt.Errorf("failed to create reception: %w", err)
 	}
 
-	return &reception, nil
+	return reception, nil
 }
 
 func (r *ReceptionRepository) HasOpenReception(ctx context.Context, pvzID string) (bool, error) {
@@ -77,7 +89,7 @@ func (r *ReceptionRepository) HasOpenReception(ctx context.Context, pvzID string
 		return false, fmt.Errorf("failed to build sql query: %w", err)
 	}
 
-	err = r.db.QueryRowContext(ctx, query, pvzID).Scan(&exists)
+	err = getQuerier(ctx, r.db).QueryRowContext(ctx, query, pvzID).Scan(&exists)
 	if err != nil {
 		return false, fmt.Errorf("failed to check if open reception exists: %w", err)
 	}
@@ -86,44 +98,97 @@ func (r *ReceptionRepository) HasOpenReception(ctx context.Context, pvzID string
 }
 
 func (r *ReceptionRepository) GetLastOpenReception(ctx context.Context, pvzID string) (*model.Reception, error) {
-	query, args, err := r.psql.
-		Select("id", "date_time", "pvz_id", "status").
+	return r.getLastOpenReception(ctx, pvzID, false)
+}
+
+// GetLastOpenReceptionForUpdate locks the open reception until the end of
+// the current transaction. A concurrent close waits for the transaction to
+// finish; if the close wins, the reception is no longer open and
+// model.ErrNoOpenReception is returned.
+func (r *ReceptionRepository) GetLastOpenReceptionForUpdate(ctx context.Context, pvzID string) (*model.Reception, error) {
+	return r.getLastOpenReception(ctx, pvzID, true)
+}
+
+func (r *ReceptionRepository) getLastOpenReception(ctx context.Context, pvzID string, forUpdate bool) (*model.Reception, error) {
+	queryBuilder := r.psql.
+		Select(receptionColumns...).
 		From(receptionTableName).
 		Where(sq.Eq{"pvz_id": pvzID, "status": "in_progress"}).
 		OrderBy("date_time DESC").
-		Limit(1).
-		ToSql()
+		Limit(1)
 
+	if forUpdate {
+		queryBuilder = queryBuilder.Suffix("FOR UPDATE")
+	}
+
+	query, args, err := queryBuilder.ToSql()
 	if err != nil {
 		return nil, fmt.Errorf("failed to build sql query: %w", err)
 	}
 
-	var reception model.Reception
-	err = r.db.QueryRowContext(ctx, query, args...).Scan(&reception.ID, &reception.DateTime, &reception.PVZID, &reception.Status)
+	reception, err := scanReception(getQuerier(ctx, r.db).QueryRowContext(ctx, query, args...))
 	if err != nil {
 		if err == sql.ErrNoRows {
-			return nil, fmt.Errorf("no open reception found for this PVZ")
+			return nil, model.ErrNoOpenReception
 		}
 		return nil, fmt.Errorf("failed to get open reception: %w", err)
 	}
 
-	return &reception, nil
+	return reception, nil
+}
+
+func (r *ReceptionRepository) GetReceptionByID(ctx context.Context, receptionID string) (*model.Reception, error) {
+	return r.getReceptionByID(ctx, receptionID, false)
+}
+
+// GetReceptionForUpdate locks the reception until the end of the current
+// transaction.
+func (r *ReceptionRepository) GetReceptionForUpdate(ctx context.Context, receptionID string) (*model.Reception, error) {
+	return r.getReceptionByID(ctx, receptionID, true)
+}
+
+func (r *ReceptionRepository) getReceptionByID(ctx context.Context, receptionID string, forUpdate bool) (*model.Reception, error) {
+	queryBuilder := r.psql.
+		Select(receptionColumns...).
+		From(receptionTableName).
+		Where(sq.Eq{"id": receptionID})
+
+	if forUpdate {
+		queryBuilder = queryBuilder.Suffix("FOR UPDATE")
+	}
+
+	query, args, err := queryBuilder.ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build sql query: %w", err)
+	}
+
+	reception, err := scanReception(getQuerier(ctx, r.db).QueryRowContext(ctx, query, args...))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, model.ErrReceptionNotFound
+		}
+		return nil, fmt.Errorf("failed to get reception: %w", err)
+	}
+
+	return reception, nil
 }
 
 func (r *ReceptionRepository) CloseReception(ctx context.Context, receptionID string) (*model.Reception, error) {
 	query, args, err := r.psql.
 		Update(receptionTableName).
 		Set("status", "close").
+		Set("closed_at", time.Now().UTC()).
+		Set("first_product_at", sq.Expr("(SELECT MIN(date_time) FROM "+productTableName+" WHERE reception_id = "+receptionTableName+".id)")).
+		Set("last_product_at", sq.Expr("(SELECT MAX(date_time) FROM "+productTableName+" WHERE reception_id = "+receptionTableName+".id)")).
 		Where(sq.Eq{"id": receptionID, "status": "in_progress"}).
-		Suffix("RETURNING id, date_time, pvz_id, status").
+		Suffix("RETURNING " + columnList(receptionColumns)).
 		ToSql()
 
 	if err != nil {
 		return nil, fmt.Errorf("failed to build sql query: %w", err)
 	}
 
-	var reception model.Reception
-	err = r.db.QueryRowContext(ctx, query, args...).Scan(&reception.ID, &reception.DateTime, &reception.PVZID, &reception.Status)
+	reception, err := scanReception(getQuerier(ctx, r.db).QueryRowContext(ctx, query, args...))
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return nil, fmt.Errorf("reception not found or already closed")
@@ -131,21 +196,101 @@ func (r *ReceptionRepository) CloseReception(ctx context.Context, receptionID st
 		return nil, fmt.Errorf("failed to close reception: %w", err)
 	}
 
-	return &reception, nil
+	return reception, nil
 }
 
-func (r *ReceptionRepository) GetReceptionsByPVZID(ctx context.Context, pvzID string, startDate, endDate *time.Time) ([]model.Reception, error) {
-	queryBuilder := r.psql.
-		Select("id", "date_time", "pvz_id", "status").
+// ReopenReception puts a closed reception back in progress. The product
+// times and closed_at are kept and recalculated when the reception is
+// closed again; the reopening itself is kept in the status history.
+func (r *ReceptionRepository) ReopenReception(ctx context.Context, receptionID string) (*model.Reception, error) {
+	query, args, err := r.psql.
+		Update(receptionTableName).
+		Set("status", "in_progress").
+		Where(sq.Eq{"id": receptionID, "status": "close"}).
+		Suffix("RETURNING " + columnList(receptionColumns)).
+		ToSql()
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to build sql query: %w", err)
+	}
+
+	reception, err := scanReception(getQuerier(ctx, r.db).QueryRowContext(ctx, query, args...))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, model.ErrReceptionNotClosed
+		}
+		return nil, fmt.Errorf("failed to reopen reception: %w", err)
+	}
+
+	return reception, nil
+}
+
+// HasNewerReception reports whether another reception was started at the
+// PVZ of reception after it.
+func (r *ReceptionRepository) HasNewerReception(ctx context.Context, reception *model.Reception) (bool, error) {
+	var exists bool
+
+	subQuery := r.psql.
+		Select("1").
 		From(receptionTableName).
-		Where(sq.Eq{"pvz_id": pvzID})
+		Where(sq.Eq{"pvz_id": reception.PVZID}).
+		Where(sq.Gt{"date_time": reception.DateTime}).
+		Where(sq.NotEq{"id": reception.ID})
+
+	query, args, err := r.psql.
+		Select().
+		Column(sq.Expr("EXISTS(?)", subQuery)).
+		ToSql()
+
+	if err != nil {
+		return false, fmt.Errorf("failed to build sql query: %w", err)
+	}
+
+	err = getQuerier(ctx, r.db).QueryRowContext(ctx, query, args...).Scan(&exists)
+	if err != nil {
+		return false, fmt.Errorf("failed to check if newer reception exists: %w", err)
+	}
 
-	if startDate != nil {
-		queryBuilder = queryBuilder.Where(sq.GtOrEq{"date_time": startDate})
+	return exists, nil
+}
+
+// HasProcessedProducts reports whether any product of the reception has
+// moved on from it: stored, put in an order, issued, returned or
+// transferred.
+func (r *ReceptionRepository) HasProcessedProducts(ctx context.Context, receptionID string) (bool, error) {
+	var exists bool
+
+	subQuery := r.psql.
+		Select("1").
+		From(productTableName + " pr").
+		Where(sq.Eq{"pr.reception_id": receptionID}).
+		Where(productProcessedExpr)
+
+	query, args, err := r.psql.
+		Select().
+		Column(sq.Expr("EXISTS(?)", subQuery)).
+		ToSql()
+
+	if err != nil {
+		return false, fmt.Errorf("failed to build sql query: %w", err)
 	}
 
-	if endDate != nil {
-		queryBuilder = queryBuilder.Where(sq.LtOrEq{"date_time": endDate})
+	err = getQuerier(ctx, r.db).QueryRowContext(ctx, query, args...).Scan(&exists)
+	if err != nil {
+		return false, fmt.Errorf("failed to check processed products: %w", err)
+	}
+
+	return exists, nil
+}
+
+func (r *ReceptionRepository) GetReceptionsByPVZID(ctx context.Context, pvzID string, filter dto.ReceptionFilter) ([]model.Reception, error) {
+	queryBuilder := r.psql.
+		Select(receptionColumns...).
+		From(receptionTableName).
+		Where(sq.Eq{"pvz_id": pvzID})
+
+	for _, condition := range receptionFilterConditions(receptionTableName, filter) {
+		queryBuilder = queryBuilder.Where(condition)
 	}
 
 	query, args, err := queryBuilder.
@@ -156,7 +301,7 @@ func (r *ReceptionRepository) GetReceptionsByPVZID(ctx context.Context, pvzID st
 		return nil, fmt.Errorf("failed to build sql query: %w", err)
 	}
 
-	rows, err := r.db.QueryContext(ctx, query, args...)
+	rows, err := getQuerier(ctx, r.db).QueryContext(ctx, query, args...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to query receptions: %w", err)
 	}
@@ -164,11 +309,11 @@ func (r *ReceptionRepository) GetReceptionsByPVZID(ctx context.Context, pvzID st
 
 	var receptions []model.Reception
 	for rows.Next() {
-		var reception model.Reception
-		if err := rows.Scan(&reception.ID, &reception.DateTime, &reception.PVZID, &reception.Status); err != nil {
+		reception, err := scanReception(rows)
+		if err != nil {
 			return nil, fmt.Errorf("failed to scan reception row: %w", err)
 		}
-		receptions = append(receptions, reception)
+		receptions = append(receptions, *reception)
 	}
 
 	if err := rows.Err(); err != nil {
@@ -177,3 +322,193 @@ func (r *ReceptionRepository) GetReceptionsByPVZID(ctx context.Context, pvzID st
 
 	return receptions, nil
 }
+
+func (r *ReceptionRepository) SetExpectedCount(ctx context.Context, receptionID string, expectedCount int) (*model.Reception, error) {
+	query, args, err := r.psql.
+		Update(receptionTableName).
+		Set("expected_count", expectedCount).
+		Where(sq.Eq{"id": receptionID}).
+		Suffix("RETURNING " + columnList(receptionColumns)).
+		ToSql()
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to build sql query: %w", err)
+	}
+
+	reception, err := scanReception(getQuerier(ctx, r.db).QueryRowContext(ctx, query, args...))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, model.ErrReceptionNotFound
+		}
+		return nil, fmt.Errorf("failed to set expected count: %w", err)
+	}
+
+	return reception, nil
+}
+
+func (r *ReceptionRepository) GetReceptionDiscrepancy(ctx context.Context, receptionID string) (*model.ReceptionDiscrepancy, error) {
+	query, args, err := r.selectReceptionDiscrepancies().
+		Where(sq.Eq{"r.id": receptionID}).
+		ToSql()
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to build sql query: %w", err)
+	}
+
+	discrepancy, err := scanReceptionDiscrepancy(getQuerier(ctx, r.db).QueryRowContext(ctx, query, args...))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, model.ErrReceptionNotFound
+		}
+		return nil, fmt.Errorf("failed to get reception discrepancy: %w", err)
+	}
+
+	return discrepancy, nil
+}
+
+// GetReceptionDiscrepancies returns receptions whose product count differs
+// from the expected one or that have damaged or opened products, newest
+// first.
+func (r *ReceptionRepository) GetReceptionDiscrepancies(
+	ctx context.Context,
+	filter dto.DiscrepancyFilterQuery,
+) ([]model.ReceptionDiscrepancy, error) {
+	queryBuilder := r.selectReceptionDiscrepancies().
+		Having("(r.expected_count IS NOT NULL AND COUNT(pr.id) <> r.expected_count) OR COUNT(pr.id) FILTER (WHERE pr.condition <> 'ok') > 0")
+
+	if filter.PVZID != "" {
+		queryBuilder = queryBuilder.Where(sq.Eq{"r.pvz_id": filter.PVZID})
+	}
+
+	if filter.StartDate != nil {
+		queryBuilder = queryBuilder.Where(sq.GtOrEq{"r.date_time": filter.StartDate})
+	}
+
+	if filter.EndDate != nil {
+		queryBuilder = queryBuilder.Where(sq.LtOrEq{"r.date_time": filter.EndDate})
+	}
+
+	offset := (filter.Page - 1) * filter.Limit
+	query, args, err := queryBuilder.
+		OrderBy("r.date_time DESC", "r.id").
+		Offset(uint64(offset)).
+		Limit(uint64(filter.Limit)).
+		ToSql()
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to build sql query: %w", err)
+	}
+
+	rows, err := getQuerier(ctx, r.db).QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query reception discrepancies: %w", err)
+	}
+	defer rows.Close()
+
+	discrepancies := make([]model.ReceptionDiscrepancy, 0)
+	for rows.Next() {
+		discrepancy, err := scanReceptionDiscrepancy(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan reception discrepancy row: %w", err)
+		}
+		discrepancies = append(discrepancies, *discrepancy)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating reception discrepancy rows: %w", err)
+	}
+
+	return discrepancies, nil
+}
+
+func (r *ReceptionRepository) selectReceptionDiscrepancies() sq.SelectBuilder {
+	return r.psql.
+		Select(
+			"r.id", "r.pvz_id", "r.date_time", "r.status", "r.expected_count",
+			"COUNT(pr.id)",
+			"COUNT(pr.id) FILTER (WHERE pr.condition = 'damaged')",
+			"COUNT(pr.id) FILTER (WHERE pr.condition = 'opened')",
+		).
+		From(receptionTableName + " r").
+		LeftJoin(productTableName + " pr ON pr.reception_id = r.id").
+		GroupBy("r.id")
+}
+
+func scanReceptionDiscrepancy(row rowScanner) (*model.ReceptionDiscrepancy, error) {
+	var (
+		discrepancy   model.ReceptionDiscrepancy
+		expectedCount sql.NullInt64
+	)
+
+	err := row.Scan(
+		&discrepancy.ReceptionID, &discrepancy.PVZID, &discrepancy.DateTime, &discrepancy.Status, &expectedCount,
+		&discrepancy.ReceivedCount, &discrepancy.DamagedCount, &discrepancy.OpenedCount,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	discrepancy.ExpectedCount = nullIntPtr(expectedCount)
+	if discrepancy.ExpectedCount != nil {
+		difference := discrepancy.ReceivedCount - *discrepancy.ExpectedCount
+		discrepancy.Difference = &difference
+	}
+
+	return &discrepancy, nil
+}
+
+func scanReception(row rowScanner) (*model.Reception, error) {
+	var (
+		reception                     model.Reception
+		closedAt                      sql.NullTime
+		firstProductAt, lastProductAt sql.NullTime
+		expectedCount                 sql.NullInt64
+	)
+
+	err := row.Scan(
+		&reception.ID, &reception.DateTime, &reception.PVZID, &reception.Status,
+		&closedAt, &firstProductAt, &lastProductAt, &expectedCount,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	reception.ClosedAt = nullTimePtr(closedAt)
+	reception.FirstProductAt = nullTimePtr(firstProductAt)
+	reception.LastProductAt = nullTimePtr(lastProductAt)
+	reception.ExpectedCount = nullIntPtr(expectedCount)
+
+	return &reception, nil
+}
+
+// receptionFilterConditions builds the WHERE conditions of a reception
+// filter. Columns are qualified with alias so that the product subqueries
+// refer to the outer reception rather than to products.id.
+func receptionFilterConditions(alias string, filter dto.ReceptionFilter) []sq.Sqlizer {
+	column := func(name string) string {
+		return alias + "." + name
+	}
+
+	var conditions []sq.Sqlizer
+
+	if filter.StartDate != nil {
+		conditions = append(conditions, sq.GtOrEq{column("date_time"): filter.StartDate})
+	}
+
+	if filter.EndDate != nil {
+		conditions = append(conditions, sq.LtOrEq{column("date_time"): filter.EndDate})
+	}
+
+	if filter.InProgress {
+		conditions = append(conditions, sq.Eq{column("status"): "in_progress"})
+	}
+
+	if filter.ProductType != "" {
+		conditions = append(conditions, sq.Expr(
+			"EXISTS (SELECT 1 FROM "+productTableName+" pr WHERE pr.reception_id = "+column("id")+" AND pr.type = ?)",
+			filter.ProductType,
+		))
+	}
+
+	return conditions
+}
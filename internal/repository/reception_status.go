@@ -0,0 +1,116 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/kirillidk/pvz-service/internal/model"
+)
+
+const (
+	receptionStatusHistoryTableName = "reception_status_history"
+)
+
+var receptionStatusHistoryColumns = []string{
+	"id", "reception_id", "from_status", "to_status", "reason", "changed_at", "actor_id", "actor_type", "actor_role",
+}
+
+type ReceptionStatusRepositoryInterface interface {
+	CreateReceptionStatusChange(ctx context.Context, change model.ReceptionStatusChange) (*model.ReceptionStatusChange, error)
+	GetReceptionStatusHistory(ctx context.Context, receptionID string) ([]model.ReceptionStatusChange, error)
+}
+
+type ReceptionStatusRepository struct {
+	db   *sql.DB
+	psql sq.StatementBuilderType
+}
+
+func NewReceptionStatusRepository(db *sql.DB) *ReceptionStatusRepository {
+	return &ReceptionStatusRepository{
+		db:   db,
+		psql: sq.StatementBuilder.PlaceholderFormat(sq.Dollar),
+	}
+}
+
+func (r *ReceptionStatusRepository) CreateReceptionStatusChange(
+	ctx context.Context,
+	change model.ReceptionStatusChange,
+) (*model.ReceptionStatusChange, error) {
+	query, args, err := r.psql.
+		Insert(receptionStatusHistoryTableName).
+		Columns(receptionStatusHistoryColumns[1:]...).
+		Values(
+			change.ReceptionID, nullString(change.FromStatus), change.ToStatus, nullString(change.Reason), change.ChangedAt,
+			nullString(change.Actor.ID), change.Actor.Type, nullString(string(change.Actor.Role)),
+		).
+		Suffix("RETURNING id").
+		ToSql()
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to build sql query: %w", err)
+	}
+
+	if err := getQuerier(ctx, r.db).QueryRowContext(ctx, query, args...).Scan(&change.ID); err != nil {
+		return nil, fmt.Errorf("failed to create reception status change: %w", err)
+	}
+
+	return &change, nil
+}
+
+// GetReceptionStatusHistory returns the status changes of the reception,
+// the oldest first.
+func (r *ReceptionStatusRepository) GetReceptionStatusHistory(
+	ctx context.Context,
+	receptionID string,
+) ([]model.ReceptionStatusChange, error) {
+	query, args, err := r.psql.
+		Select(receptionStatusHistoryColumns...).
+		From(receptionStatusHistoryTableName).
+		Where(sq.Eq{"reception_id": receptionID}).
+		OrderBy("changed_at ASC", "id ASC").
+		ToSql()
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to build sql query: %w", err)
+	}
+
+	rows, err := getQuerier(ctx, r.db).QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query reception status history: %w", err)
+	}
+	defer rows.Close()
+
+	history := []model.ReceptionStatusChange{}
+	for rows.Next() {
+		var (
+			change     model.ReceptionStatusChange
+			fromStatus sql.NullString
+			reason     sql.NullString
+			actorID    sql.NullString
+			actorRole  sql.NullString
+		)
+
+		err := rows.Scan(
+			&change.ID, &change.ReceptionID, &fromStatus, &change.ToStatus, &reason, &change.ChangedAt,
+			&actorID, &change.Actor.Type, &actorRole,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan reception status change row: %w", err)
+		}
+
+		change.FromStatus = fromStatus.String
+		change.Reason = reason.String
+		change.Actor.ID = actorID.String
+		change.Actor.Role = model.UserRole(actorRole.String)
+
+		history = append(history, change)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating reception status history rows: %w", err)
+	}
+
+	return history, nil
+}
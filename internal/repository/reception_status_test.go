@@ -0,0 +1,175 @@
+package repository_test
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/kirillidk/pvz-service/internal/model"
+	"github.com/kirillidk/pvz-service/internal/repository"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReceptionStatusRepository_CreateReceptionStatusChange(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	receptionStatusRepo := repository.NewReceptionStatusRepository(db)
+	ctx := context.Background()
+	now := time.Now()
+
+	insertQuery := regexp.QuoteMeta(`INSERT INTO reception_status_history (reception_id,from_status,to_status,reason,changed_at,actor_id,actor_type,actor_role) ` +
+		`VALUES ($1,$2,$3,$4,$5,$6,$7,$8) RETURNING id`)
+
+	t.Run("Reopen", func(t *testing.T) {
+		change := model.ReceptionStatusChange{
+			ReceptionID: "c0eebc99-9c0b-4ef8-bb6d-6bb9bd380a11",
+			FromStatus:  "close",
+			ToStatus:    "in_progress",
+			Reason:      "products scanned after closing",
+			ChangedAt:   now,
+			Actor:       model.Actor{ID: "a0eebc99-9c0b-4ef8-bb6d-6bb9bd380a11", Type: model.UserActor, Role: model.ModeratorRole},
+		}
+
+		mock.ExpectQuery(insertQuery).
+			WithArgs(change.ReceptionID, change.FromStatus, change.ToStatus, change.Reason, now, change.Actor.ID, change.Actor.Type, string(change.Actor.Role)).
+			WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(3))
+
+		created, err := receptionStatusRepo.CreateReceptionStatusChange(ctx, change)
+
+		assert.NoError(t, err)
+		assert.Equal(t, int64(3), created.ID)
+		assert.Equal(t, change.Reason, created.Reason)
+	})
+
+	t.Run("Open", func(t *testing.T) {
+		change := model.ReceptionStatusChange{
+			ReceptionID: "c0eebc99-9c0b-4ef8-bb6d-6bb9bd380a11",
+			ToStatus:    "in_progress",
+			ChangedAt:   now,
+			Actor:       model.Actor{Type: model.APIKeyActor},
+		}
+
+		mock.ExpectQuery(insertQuery).
+			WithArgs(change.ReceptionID, nil, change.ToStatus, nil, now, nil, change.Actor.Type, nil).
+			WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+
+		created, err := receptionStatusRepo.CreateReceptionStatusChange(ctx, change)
+
+		assert.NoError(t, err)
+		assert.Equal(t, int64(1), created.ID)
+	})
+
+	t.Run("DB Error", func(t *testing.T) {
+		mock.ExpectQuery(insertQuery).
+			WillReturnError(errors.New("db error"))
+
+		created, err := receptionStatusRepo.CreateReceptionStatusChange(ctx, model.ReceptionStatusChange{})
+
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "failed to create reception status change: db error")
+		assert.Nil(t, created)
+	})
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func TestReceptionStatusRepository_GetReceptionStatusHistory(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	receptionStatusRepo := repository.NewReceptionStatusRepository(db)
+	ctx := context.Background()
+	now := time.Now()
+
+	receptionID := "c0eebc99-9c0b-4ef8-bb6d-6bb9bd380a11"
+	selectQuery := regexp.QuoteMeta(`SELECT id, reception_id, from_status, to_status, reason, changed_at, actor_id, actor_type, actor_role ` +
+		`FROM reception_status_history WHERE reception_id = $1 ORDER BY changed_at ASC, id ASC`)
+	columns := []string{"id", "reception_id", "from_status", "to_status", "reason", "changed_at", "actor_id", "actor_type", "actor_role"}
+
+	tests := []struct {
+		name          string
+		mockBehavior  func()
+		expectedValue []model.ReceptionStatusChange
+		expectedError error
+	}{
+		{
+			name: "Success",
+			mockBehavior: func() {
+				rows := sqlmock.NewRows(columns).
+					AddRow(1, receptionID, nil, "in_progress", nil, now, "a0eebc99-9c0b-4ef8-bb6d-6bb9bd380a11", "user", "employee").
+					AddRow(2, receptionID, "in_progress", "close", nil, now.Add(time.Hour), "a0eebc99-9c0b-4ef8-bb6d-6bb9bd380a11", "user", "employee").
+					AddRow(3, receptionID, "close", "in_progress", "products scanned after closing", now.Add(2*time.Hour), "f0eebc99-9c0b-4ef8-bb6d-6bb9bd380a11", "user", "moderator")
+
+				mock.ExpectQuery(selectQuery).
+					WithArgs(receptionID).
+					WillReturnRows(rows)
+			},
+			expectedValue: []model.ReceptionStatusChange{
+				{
+					ID: 1, ReceptionID: receptionID, ToStatus: "in_progress", ChangedAt: now,
+					Actor: model.Actor{ID: "a0eebc99-9c0b-4ef8-bb6d-6bb9bd380a11", Type: model.UserActor, Role: model.EmployeeRole},
+				},
+				{
+					ID: 2, ReceptionID: receptionID, FromStatus: "in_progress", ToStatus: "close", ChangedAt: now.Add(time.Hour),
+					Actor: model.Actor{ID: "a0eebc99-9c0b-4ef8-bb6d-6bb9bd380a11", Type: model.UserActor, Role: model.EmployeeRole},
+				},
+				{
+					ID: 3, ReceptionID: receptionID, FromStatus: "close", ToStatus: "in_progress", Reason: "products scanned after closing",
+					ChangedAt: now.Add(2 * time.Hour),
+					Actor:     model.Actor{ID: "f0eebc99-9c0b-4ef8-bb6d-6bb9bd380a11", Type: model.UserActor, Role: model.ModeratorRole},
+				},
+			},
+		},
+		{
+			name: "Empty",
+			mockBehavior: func() {
+				mock.ExpectQuery(selectQuery).
+					WithArgs(receptionID).
+					WillReturnRows(sqlmock.NewRows(columns))
+			},
+			expectedValue: []model.ReceptionStatusChange{},
+		},
+		{
+			name: "DB Error",
+			mockBehavior: func() {
+				mock.ExpectQuery(selectQuery).
+					WithArgs(receptionID).
+					WillReturnError(errors.New("db error"))
+			},
+			expectedError: errors.New("failed to query reception status history: db error"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockBehavior()
+
+			history, err := receptionStatusRepo.GetReceptionStatusHistory(ctx, receptionID)
+
+			if tt.expectedError != nil {
+				assert.Error(t, err)
+				assert.Contains(t, err.Error(), tt.expectedError.Error())
+				assert.Nil(t, history)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expectedValue, history)
+			}
+
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("there were unfulfilled expectations: %s", err)
+			}
+		})
+	}
+}
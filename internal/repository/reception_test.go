@@ -3,6 +3,7 @@ package repository_test
 import (
 	"context"
 	"database/sql"
+	"database/sql/driver"
 	"errors"
 	"regexp"
 	"testing"
@@ -15,6 +16,10 @@ import (
 	"github.com/stretchr/testify/assert"
 )
 
+var receptionRowColumns = []string{"id", "date_time", "pvz_id", "status", "closed_at", "first_product_at", "last_product_at", "expected_count"}
+
+var discrepancyRowColumns = []string{"id", "pvz_id", "date_time", "status", "expected_count", "count", "damaged", "opened"}
+
 func TestReceptionRepository_CreateReception(t *testing.T) {
 	db, mock, err := sqlmock.New()
 	if err != nil {
@@ -43,11 +48,11 @@ func TestReceptionRepository_CreateReception(t *testing.T) {
 					WithArgs("b0eebc99-9c0b-4ef8-bb6d-6bb9bd380a11").
 					WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(false))
 
-				rows := sqlmock.NewRows([]string{"id", "date_time", "pvz_id", "status"}).
-					AddRow("c0eebc99-9c0b-4ef8-bb6d-6bb9bd380a11", testTime, "b0eebc99-9c0b-4ef8-bb6d-6bb9bd380a11", "in_progress")
+				rows := sqlmock.NewRows(receptionRowColumns).
+					AddRow("c0eebc99-9c0b-4ef8-bb6d-6bb9bd380a11", testTime, "b0eebc99-9c0b-4ef8-bb6d-6bb9bd380a11", "in_progress", nil, nil, nil, nil)
 
 				mock.ExpectQuery(`INSERT INTO receptions`).
-					WithArgs(sqlmock.AnyArg(), "b0eebc99-9c0b-4ef8-bb6d-6bb9bd380a11", "in_progress").
+					WithArgs(sqlmock.AnyArg(), "b0eebc99-9c0b-4ef8-bb6d-6bb9bd380a11", "in_progress", nil).
 					WillReturnRows(rows)
 			},
 			expectedReception: &model.Reception{
@@ -58,6 +63,33 @@ func TestReceptionRepository_CreateReception(t *testing.T) {
 			},
 			expectedError: nil,
 		},
+		{
+			name: "Success With Expected Count",
+			receptionReq: dto.ReceptionCreateRequest{
+				PVZID:         "b0eebc99-9c0b-4ef8-bb6d-6bb9bd380a11",
+				ExpectedCount: intPtr(12),
+			},
+			mockBehavior: func() {
+				mock.ExpectQuery(`SELECT EXISTS`).
+					WithArgs("b0eebc99-9c0b-4ef8-bb6d-6bb9bd380a11").
+					WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(false))
+
+				rows := sqlmock.NewRows(receptionRowColumns).
+					AddRow("c0eebc99-9c0b-4ef8-bb6d-6bb9bd380a11", testTime, "b0eebc99-9c0b-4ef8-bb6d-6bb9bd380a11", "in_progress", nil, nil, nil, 12)
+
+				mock.ExpectQuery(`INSERT INTO receptions`).
+					WithArgs(sqlmock.AnyArg(), "b0eebc99-9c0b-4ef8-bb6d-6bb9bd380a11", "in_progress", 12).
+					WillReturnRows(rows)
+			},
+			expectedReception: &model.Reception{
+				ID:            "c0eebc99-9c0b-4ef8-bb6d-6bb9bd380a11",
+				DateTime:      testTime,
+				PVZID:         "b0eebc99-9c0b-4ef8-bb6d-6bb9bd380a11",
+				Status:        "in_progress",
+				ExpectedCount: intPtr(12),
+			},
+			expectedError: nil,
+		},
 		{
 			name: "Already Has Open Reception",
 			receptionReq: dto.ReceptionCreateRequest{
@@ -95,7 +127,7 @@ func TestReceptionRepository_CreateReception(t *testing.T) {
 					WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(false))
 
 				mock.ExpectQuery(`INSERT INTO receptions`).
-					WithArgs(sqlmock.AnyArg(), "b0eebc99-9c0b-4ef8-bb6d-6bb9bd380a11", "in_progress").
+					WithArgs(sqlmock.AnyArg(), "b0eebc99-9c0b-4ef8-bb6d-6bb9bd380a11", "in_progress", nil).
 					WillReturnError(errors.New("db error"))
 			},
 			expectedReception: nil,
@@ -118,6 +150,7 @@ func TestReceptionRepository_CreateReception(t *testing.T) {
 				assert.Equal(t, tt.expectedReception.ID, reception.ID)
 				assert.Equal(t, tt.expectedReception.PVZID, reception.PVZID)
 				assert.Equal(t, tt.expectedReception.Status, reception.Status)
+				assert.Equal(t, tt.expectedReception.ExpectedCount, reception.ExpectedCount)
 				assert.NotNil(t, reception.DateTime)
 			}
 
@@ -128,6 +161,49 @@ func TestReceptionRepository_CreateReception(t *testing.T) {
 	}
 }
 
+// utcTime matches a time argument written in UTC.
+type utcTime struct{}
+
+func (utcTime) Match(v driver.Value) bool {
+	t, ok := v.(time.Time)
+	return ok && t.Location() == time.UTC
+}
+
+func TestReceptionRepository_CreateReception_WritesUTC(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	// TIMESTAMP columns drop the offset, so a server outside UTC must
+	// still write UTC for stats to find the local day of the city.
+	local := time.Local
+	time.Local = time.FixedZone("MSK", 3*60*60)
+	defer func() { time.Local = local }()
+
+	receptionRepo := repository.NewReceptionRepository(db)
+	pvzID := "b0eebc99-9c0b-4ef8-bb6d-6bb9bd380a11"
+	dateTime := time.Date(2025, 4, 14, 21, 30, 0, 0, time.UTC)
+
+	mock.ExpectQuery(`SELECT EXISTS`).
+		WithArgs(pvzID).
+		WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(false))
+	mock.ExpectQuery(`INSERT INTO receptions`).
+		WithArgs(utcTime{}, pvzID, "in_progress", nil).
+		WillReturnRows(sqlmock.NewRows(receptionRowColumns).
+			AddRow("c0eebc99-9c0b-4ef8-bb6d-6bb9bd380a11", dateTime, pvzID, "in_progress", nil, nil, nil, nil))
+
+	reception, err := receptionRepo.CreateReception(context.Background(), dto.ReceptionCreateRequest{PVZID: pvzID})
+
+	assert.NoError(t, err)
+	assert.Equal(t, dateTime, reception.DateTime)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
 func TestReceptionRepository_HasOpenReception(t *testing.T) {
 	db, mock, err := sqlmock.New()
 	if err != nil {
@@ -219,10 +295,10 @@ func TestReceptionRepository_GetLastOpenReception(t *testing.T) {
 		{
 			name: "Success",
 			mockBehavior: func() {
-				rows := sqlmock.NewRows([]string{"id", "date_time", "pvz_id", "status"}).
-					AddRow("c0eebc99-9c0b-4ef8-bb6d-6bb9bd380a11", testTime, pvzID, "in_progress")
+				rows := sqlmock.NewRows(receptionRowColumns).
+					AddRow("c0eebc99-9c0b-4ef8-bb6d-6bb9bd380a11", testTime, pvzID, "in_progress", nil, nil, nil, nil)
 
-				mock.ExpectQuery(regexp.QuoteMeta(`SELECT id, date_time, pvz_id, status FROM receptions WHERE`)).
+				mock.ExpectQuery(regexp.QuoteMeta(`SELECT id, date_time, pvz_id, status, closed_at, first_product_at, last_product_at, expected_count FROM receptions WHERE`)).
 					WithArgs(pvzID, "in_progress").
 					WillReturnRows(rows)
 			},
@@ -237,7 +313,7 @@ func TestReceptionRepository_GetLastOpenReception(t *testing.T) {
 		{
 			name: "No Open Reception",
 			mockBehavior: func() {
-				mock.ExpectQuery(regexp.QuoteMeta(`SELECT id, date_time, pvz_id, status FROM receptions WHERE`)).
+				mock.ExpectQuery(regexp.QuoteMeta(`SELECT id, date_time, pvz_id, status, closed_at, first_product_at, last_product_at, expected_count FROM receptions WHERE`)).
 					WithArgs(pvzID, "in_progress").
 					WillReturnError(sql.ErrNoRows)
 			},
@@ -247,7 +323,7 @@ func TestReceptionRepository_GetLastOpenReception(t *testing.T) {
 		{
 			name: "DB Error",
 			mockBehavior: func() {
-				mock.ExpectQuery(regexp.QuoteMeta(`SELECT id, date_time, pvz_id, status FROM receptions WHERE`)).
+				mock.ExpectQuery(regexp.QuoteMeta(`SELECT id, date_time, pvz_id, status, closed_at, first_product_at, last_product_at, expected_count FROM receptions WHERE`)).
 					WithArgs(pvzID, "in_progress").
 					WillReturnError(errors.New("db error"))
 			},
@@ -280,6 +356,127 @@ func TestReceptionRepository_GetLastOpenReception(t *testing.T) {
 	}
 }
 
+func TestReceptionRepository_GetLastOpenReceptionForUpdate(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	receptionRepo := repository.NewReceptionRepository(db)
+	ctx := context.Background()
+	pvzID := "b0eebc99-9c0b-4ef8-bb6d-6bb9bd380a11"
+	expectedQuery := regexp.QuoteMeta(`SELECT id, date_time, pvz_id, status, closed_at, first_product_at, last_product_at, expected_count FROM receptions ` +
+		`WHERE pvz_id = $1 AND status = $2 ORDER BY date_time DESC LIMIT 1 FOR UPDATE`)
+
+	rows := sqlmock.NewRows(receptionRowColumns).
+		AddRow("c0eebc99-9c0b-4ef8-bb6d-6bb9bd380a11", time.Now(), pvzID, "in_progress", nil, nil, nil, nil)
+
+	mock.ExpectQuery(expectedQuery).
+		WithArgs(pvzID, "in_progress").
+		WillReturnRows(rows)
+
+	reception, err := receptionRepo.GetLastOpenReceptionForUpdate(ctx, pvzID)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "c0eebc99-9c0b-4ef8-bb6d-6bb9bd380a11", reception.ID)
+
+	// A reception closed concurrently no longer matches once the lock is
+	// acquired.
+	mock.ExpectQuery(expectedQuery).
+		WithArgs(pvzID, "in_progress").
+		WillReturnError(sql.ErrNoRows)
+
+	reception, err = receptionRepo.GetLastOpenReceptionForUpdate(ctx, pvzID)
+
+	assert.ErrorIs(t, err, model.ErrNoOpenReception)
+	assert.Nil(t, reception)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func TestReceptionRepository_GetReceptionByID(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	receptionRepo := repository.NewReceptionRepository(db)
+	ctx := context.Background()
+	receptionID := "c0eebc99-9c0b-4ef8-bb6d-6bb9bd380a11"
+	testTime := time.Now()
+
+	tests := []struct {
+		name              string
+		mockBehavior      func()
+		expectedReception *model.Reception
+		expectedError     error
+	}{
+		{
+			name: "Success",
+			mockBehavior: func() {
+				rows := sqlmock.NewRows(receptionRowColumns).
+					AddRow(receptionID, testTime, "b0eebc99-9c0b-4ef8-bb6d-6bb9bd380a11", "close", nil, nil, nil, nil)
+
+				mock.ExpectQuery(regexp.QuoteMeta(`SELECT id, date_time, pvz_id, status, closed_at, first_product_at, last_product_at, expected_count FROM receptions WHERE id = $1`)).
+					WithArgs(receptionID).
+					WillReturnRows(rows)
+			},
+			expectedReception: &model.Reception{
+				ID:       receptionID,
+				DateTime: testTime,
+				PVZID:    "b0eebc99-9c0b-4ef8-bb6d-6bb9bd380a11",
+				Status:   "close",
+			},
+			expectedError: nil,
+		},
+		{
+			name: "Reception Not Found",
+			mockBehavior: func() {
+				mock.ExpectQuery(regexp.QuoteMeta(`SELECT id, date_time, pvz_id, status, closed_at, first_product_at, last_product_at, expected_count FROM receptions WHERE id = $1`)).
+					WithArgs(receptionID).
+					WillReturnError(sql.ErrNoRows)
+			},
+			expectedReception: nil,
+			expectedError:     model.ErrReceptionNotFound,
+		},
+		{
+			name: "DB Error",
+			mockBehavior: func() {
+				mock.ExpectQuery(regexp.QuoteMeta(`SELECT id, date_time, pvz_id, status, closed_at, first_product_at, last_product_at, expected_count FROM receptions WHERE id = $1`)).
+					WithArgs(receptionID).
+					WillReturnError(errors.New("db error"))
+			},
+			expectedReception: nil,
+			expectedError:     errors.New("failed to get reception: db error"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockBehavior()
+
+			reception, err := receptionRepo.GetReceptionByID(ctx, receptionID)
+
+			if tt.expectedError != nil {
+				assert.Error(t, err)
+				assert.Contains(t, err.Error(), tt.expectedError.Error())
+				assert.Nil(t, reception)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expectedReception, reception)
+			}
+
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("there were unfulfilled expectations: %s", err)
+			}
+		})
+	}
+}
+
 func TestReceptionRepository_CloseReception(t *testing.T) {
 	db, mock, err := sqlmock.New()
 	if err != nil {
@@ -301,11 +498,11 @@ func TestReceptionRepository_CloseReception(t *testing.T) {
 		{
 			name: "Success",
 			mockBehavior: func() {
-				rows := sqlmock.NewRows([]string{"id", "date_time", "pvz_id", "status"}).
-					AddRow(receptionID, testTime, "b0eebc99-9c0b-4ef8-bb6d-6bb9bd380a11", "close")
+				rows := sqlmock.NewRows(receptionRowColumns).
+					AddRow(receptionID, testTime, "b0eebc99-9c0b-4ef8-bb6d-6bb9bd380a11", "close", testTime, testTime, testTime, nil)
 
-				mock.ExpectQuery(regexp.QuoteMeta(`UPDATE receptions SET status = $1 WHERE`)).
-					WithArgs("close", receptionID, "in_progress").
+				mock.ExpectQuery(regexp.QuoteMeta(`UPDATE receptions SET status = $1, closed_at = $2, first_product_at = (SELECT MIN(date_time) FROM products WHERE reception_id = receptions.id), last_product_at = (SELECT MAX(date_time) FROM products WHERE reception_id = receptions.id) WHERE`)).
+					WithArgs("close", sqlmock.AnyArg(), receptionID, "in_progress").
 					WillReturnRows(rows)
 			},
 			expectedReception: &model.Reception{
@@ -313,14 +510,15 @@ func TestReceptionRepository_CloseReception(t *testing.T) {
 				DateTime: testTime,
 				PVZID:    "b0eebc99-9c0b-4ef8-bb6d-6bb9bd380a11",
 				Status:   "close",
+				ClosedAt: &testTime,
 			},
 			expectedError: nil,
 		},
 		{
 			name: "Reception Not Found or Already Closed",
 			mockBehavior: func() {
-				mock.ExpectQuery(regexp.QuoteMeta(`UPDATE receptions SET status = $1 WHERE`)).
-					WithArgs("close", receptionID, "in_progress").
+				mock.ExpectQuery(regexp.QuoteMeta(`UPDATE receptions SET status = $1, closed_at = $2, first_product_at = (SELECT MIN(date_time) FROM products WHERE reception_id = receptions.id), last_product_at = (SELECT MAX(date_time) FROM products WHERE reception_id = receptions.id) WHERE`)).
+					WithArgs("close", sqlmock.AnyArg(), receptionID, "in_progress").
 					WillReturnError(sql.ErrNoRows)
 			},
 			expectedReception: nil,
@@ -329,8 +527,8 @@ func TestReceptionRepository_CloseReception(t *testing.T) {
 		{
 			name: "DB Error",
 			mockBehavior: func() {
-				mock.ExpectQuery(regexp.QuoteMeta(`UPDATE receptions SET status = $1 WHERE`)).
-					WithArgs("close", receptionID, "in_progress").
+				mock.ExpectQuery(regexp.QuoteMeta(`UPDATE receptions SET status = $1, closed_at = $2, first_product_at = (SELECT MIN(date_time) FROM products WHERE reception_id = receptions.id), last_product_at = (SELECT MAX(date_time) FROM products WHERE reception_id = receptions.id) WHERE`)).
+					WithArgs("close", sqlmock.AnyArg(), receptionID, "in_progress").
 					WillReturnError(errors.New("db error"))
 			},
 			expectedReception: nil,
@@ -353,6 +551,7 @@ func TestReceptionRepository_CloseReception(t *testing.T) {
 				assert.Equal(t, tt.expectedReception.ID, reception.ID)
 				assert.Equal(t, tt.expectedReception.PVZID, reception.PVZID)
 				assert.Equal(t, tt.expectedReception.Status, reception.Status)
+				assert.Equal(t, tt.expectedReception.ClosedAt, reception.ClosedAt)
 			}
 
 			if err := mock.ExpectationsWereMet(); err != nil {
@@ -376,22 +575,19 @@ func TestReceptionRepository_GetReceptionsByPVZID(t *testing.T) {
 
 	tests := []struct {
 		name               string
-		startDate          *time.Time
-		endDate            *time.Time
+		filter             dto.ReceptionFilter
 		mockBehavior       func()
 		expectedReceptions []model.Reception
 		expectedError      error
 	}{
 		{
-			name:      "Success Without Date Filters",
-			startDate: nil,
-			endDate:   nil,
+			name: "Success Without Date Filters",
 			mockBehavior: func() {
-				rows := sqlmock.NewRows([]string{"id", "date_time", "pvz_id", "status"}).
-					AddRow("c0eebc99-9c0b-4ef8-bb6d-6bb9bd380a11", testTime, pvzID, "in_progress").
-					AddRow("c0eebc99-9c0b-4ef8-bb6d-6bb9bd380a12", testTime.Add(-24*time.Hour), pvzID, "close")
+				rows := sqlmock.NewRows(receptionRowColumns).
+					AddRow("c0eebc99-9c0b-4ef8-bb6d-6bb9bd380a11", testTime, pvzID, "in_progress", nil, nil, nil, nil).
+					AddRow("c0eebc99-9c0b-4ef8-bb6d-6bb9bd380a12", testTime.Add(-24*time.Hour), pvzID, "close", nil, nil, nil, nil)
 
-				mock.ExpectQuery(regexp.QuoteMeta(`SELECT id, date_time, pvz_id, status FROM receptions WHERE`)).
+				mock.ExpectQuery(regexp.QuoteMeta(`SELECT id, date_time, pvz_id, status, closed_at, first_product_at, last_product_at, expected_count FROM receptions WHERE`)).
 					WithArgs(pvzID).
 					WillReturnRows(rows)
 			},
@@ -412,14 +608,13 @@ func TestReceptionRepository_GetReceptionsByPVZID(t *testing.T) {
 			expectedError: nil,
 		},
 		{
-			name:      "Success With Date Filters",
-			startDate: &testTime,
-			endDate:   &testTime,
+			name:   "Success With Date Filters",
+			filter: dto.ReceptionFilter{StartDate: &testTime, EndDate: &testTime},
 			mockBehavior: func() {
-				rows := sqlmock.NewRows([]string{"id", "date_time", "pvz_id", "status"}).
-					AddRow("c0eebc99-9c0b-4ef8-bb6d-6bb9bd380a11", testTime, pvzID, "in_progress")
+				rows := sqlmock.NewRows(receptionRowColumns).
+					AddRow("c0eebc99-9c0b-4ef8-bb6d-6bb9bd380a11", testTime, pvzID, "in_progress", nil, nil, nil, nil)
 
-				mock.ExpectQuery(regexp.QuoteMeta(`SELECT id, date_time, pvz_id, status FROM receptions WHERE`)).
+				mock.ExpectQuery(regexp.QuoteMeta(`SELECT id, date_time, pvz_id, status, closed_at, first_product_at, last_product_at, expected_count FROM receptions WHERE`)).
 					WithArgs(pvzID, testTime, testTime).
 					WillReturnRows(rows)
 			},
@@ -434,13 +629,35 @@ func TestReceptionRepository_GetReceptionsByPVZID(t *testing.T) {
 			expectedError: nil,
 		},
 		{
-			name:      "Empty Result",
-			startDate: nil,
-			endDate:   nil,
+			name: "Success With Product Filters",
+			filter: dto.ReceptionFilter{
+				InProgress:  true,
+				ProductType: "электроника",
+			},
 			mockBehavior: func() {
-				rows := sqlmock.NewRows([]string{"id", "date_time", "pvz_id", "status"})
+				rows := sqlmock.NewRows(receptionRowColumns).
+					AddRow("c0eebc99-9c0b-4ef8-bb6d-6bb9bd380a11", testTime, pvzID, "in_progress", nil, nil, nil, nil)
 
-				mock.ExpectQuery(regexp.QuoteMeta(`SELECT id, date_time, pvz_id, status FROM receptions WHERE`)).
+				mock.ExpectQuery(regexp.QuoteMeta(`SELECT id, date_time, pvz_id, status, closed_at, first_product_at, last_product_at, expected_count FROM receptions WHERE pvz_id = $1 AND receptions.status = $2 AND EXISTS (SELECT 1 FROM products pr WHERE pr.reception_id = receptions.id AND pr.type = $3) ORDER BY date_time DESC`)).
+					WithArgs(pvzID, "in_progress", "электроника").
+					WillReturnRows(rows)
+			},
+			expectedReceptions: []model.Reception{
+				{
+					ID:       "c0eebc99-9c0b-4ef8-bb6d-6bb9bd380a11",
+					DateTime: testTime,
+					PVZID:    pvzID,
+					Status:   "in_progress",
+				},
+			},
+			expectedError: nil,
+		},
+		{
+			name: "Empty Result",
+			mockBehavior: func() {
+				rows := sqlmock.NewRows(receptionRowColumns)
+
+				mock.ExpectQuery(regexp.QuoteMeta(`SELECT id, date_time, pvz_id, status, closed_at, first_product_at, last_product_at, expected_count FROM receptions WHERE`)).
 					WithArgs(pvzID).
 					WillReturnRows(rows)
 			},
@@ -448,11 +665,9 @@ func TestReceptionRepository_GetReceptionsByPVZID(t *testing.T) {
 			expectedError:      nil,
 		},
 		{
-			name:      "DB Error",
-			startDate: nil,
-			endDate:   nil,
+			name: "DB Error",
 			mockBehavior: func() {
-				mock.ExpectQuery(regexp.QuoteMeta(`SELECT id, date_time, pvz_id, status FROM receptions WHERE`)).
+				mock.ExpectQuery(regexp.QuoteMeta(`SELECT id, date_time, pvz_id, status, closed_at, first_product_at, last_product_at, expected_count FROM receptions WHERE`)).
 					WithArgs(pvzID).
 					WillReturnError(errors.New("db error"))
 			},
@@ -465,7 +680,7 @@ func TestReceptionRepository_GetReceptionsByPVZID(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			tt.mockBehavior()
 
-			receptions, err := receptionRepo.GetReceptionsByPVZID(ctx, pvzID, tt.startDate, tt.endDate)
+			receptions, err := receptionRepo.GetReceptionsByPVZID(ctx, pvzID, tt.filter)
 
 			if tt.expectedError != nil {
 				assert.Error(t, err)
@@ -489,3 +704,381 @@ func TestReceptionRepository_GetReceptionsByPVZID(t *testing.T) {
 		})
 	}
 }
+
+func TestReceptionRepository_SetExpectedCount(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	receptionRepo := repository.NewReceptionRepository(db)
+	ctx := context.Background()
+	testTime := time.Now()
+
+	receptionID := "c0eebc99-9c0b-4ef8-bb6d-6bb9bd380a11"
+	updateQuery := regexp.QuoteMeta(`UPDATE receptions SET expected_count = $1 WHERE id = $2 RETURNING id, date_time, pvz_id, status, closed_at, first_product_at, last_product_at, expected_count`)
+
+	t.Run("Success", func(t *testing.T) {
+		rows := sqlmock.NewRows(receptionRowColumns).
+			AddRow(receptionID, testTime, "b0eebc99-9c0b-4ef8-bb6d-6bb9bd380a11", "close", testTime, testTime, testTime, 5)
+
+		mock.ExpectQuery(updateQuery).
+			WithArgs(5, receptionID).
+			WillReturnRows(rows)
+
+		reception, err := receptionRepo.SetExpectedCount(ctx, receptionID, 5)
+
+		assert.NoError(t, err)
+		assert.Equal(t, intPtr(5), reception.ExpectedCount)
+	})
+
+	t.Run("Not Found", func(t *testing.T) {
+		mock.ExpectQuery(updateQuery).
+			WithArgs(5, receptionID).
+			WillReturnError(sql.ErrNoRows)
+
+		reception, err := receptionRepo.SetExpectedCount(ctx, receptionID, 5)
+
+		assert.ErrorIs(t, err, model.ErrReceptionNotFound)
+		assert.Nil(t, reception)
+	})
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func TestReceptionRepository_GetReceptionDiscrepancy(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	receptionRepo := repository.NewReceptionRepository(db)
+	ctx := context.Background()
+	testTime := time.Now()
+
+	receptionID := "c0eebc99-9c0b-4ef8-bb6d-6bb9bd380a11"
+	pvzID := "b0eebc99-9c0b-4ef8-bb6d-6bb9bd380a11"
+	selectQuery := regexp.QuoteMeta(`SELECT r.id, r.pvz_id, r.date_time, r.status, r.expected_count, COUNT(pr.id), ` +
+		`COUNT(pr.id) FILTER (WHERE pr.condition = 'damaged'), COUNT(pr.id) FILTER (WHERE pr.condition = 'opened') ` +
+		`FROM receptions r LEFT JOIN products pr ON pr.reception_id = r.id WHERE r.id = $1 GROUP BY r.id`)
+
+	t.Run("Success", func(t *testing.T) {
+		mock.ExpectQuery(selectQuery).
+			WithArgs(receptionID).
+			WillReturnRows(sqlmock.NewRows(discrepancyRowColumns).
+				AddRow(receptionID, pvzID, testTime, "close", 10, 8, 1, 0))
+
+		discrepancy, err := receptionRepo.GetReceptionDiscrepancy(ctx, receptionID)
+
+		assert.NoError(t, err)
+		assert.Equal(t, &model.ReceptionDiscrepancy{
+			ReceptionID:   receptionID,
+			PVZID:         pvzID,
+			DateTime:      testTime,
+			Status:        "close",
+			ExpectedCount: intPtr(10),
+			ReceivedCount: 8,
+			Difference:    intPtr(-2),
+			DamagedCount:  1,
+		}, discrepancy)
+	})
+
+	t.Run("Without Expected Count", func(t *testing.T) {
+		mock.ExpectQuery(selectQuery).
+			WithArgs(receptionID).
+			WillReturnRows(sqlmock.NewRows(discrepancyRowColumns).
+				AddRow(receptionID, pvzID, testTime, "in_progress", nil, 3, 0, 0))
+
+		discrepancy, err := receptionRepo.GetReceptionDiscrepancy(ctx, receptionID)
+
+		assert.NoError(t, err)
+		assert.Nil(t, discrepancy.ExpectedCount)
+		assert.Nil(t, discrepancy.Difference)
+		assert.Equal(t, 3, discrepancy.ReceivedCount)
+	})
+
+	t.Run("Not Found", func(t *testing.T) {
+		mock.ExpectQuery(selectQuery).
+			WithArgs(receptionID).
+			WillReturnError(sql.ErrNoRows)
+
+		discrepancy, err := receptionRepo.GetReceptionDiscrepancy(ctx, receptionID)
+
+		assert.ErrorIs(t, err, model.ErrReceptionNotFound)
+		assert.Nil(t, discrepancy)
+	})
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func TestReceptionRepository_GetReceptionDiscrepancies(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	receptionRepo := repository.NewReceptionRepository(db)
+	ctx := context.Background()
+	testTime := time.Now()
+
+	pvzID := "b0eebc99-9c0b-4ef8-bb6d-6bb9bd380a11"
+	selectQuery := `SELECT r.id, r.pvz_id, r.date_time, r.status, r.expected_count, COUNT(pr.id), ` +
+		`COUNT(pr.id) FILTER (WHERE pr.condition = 'damaged'), COUNT(pr.id) FILTER (WHERE pr.condition = 'opened') ` +
+		`FROM receptions r LEFT JOIN products pr ON pr.reception_id = r.id `
+	having := `GROUP BY r.id HAVING (r.expected_count IS NOT NULL AND COUNT(pr.id) <> r.expected_count) ` +
+		`OR COUNT(pr.id) FILTER (WHERE pr.condition <> 'ok') > 0 ORDER BY r.date_time DESC, r.id`
+
+	t.Run("Success", func(t *testing.T) {
+		mock.ExpectQuery(regexp.QuoteMeta(selectQuery + having + ` LIMIT 10 OFFSET 0`)).
+			WillReturnRows(sqlmock.NewRows(discrepancyRowColumns).
+				AddRow("c0eebc99-9c0b-4ef8-bb6d-6bb9bd380a12", pvzID, testTime, "close", nil, 4, 0, 2).
+				AddRow("c0eebc99-9c0b-4ef8-bb6d-6bb9bd380a11", pvzID, testTime.Add(-time.Hour), "close", 3, 5, 0, 0))
+
+		discrepancies, err := receptionRepo.GetReceptionDiscrepancies(ctx, dto.DiscrepancyFilterQuery{Page: 1, Limit: 10})
+
+		assert.NoError(t, err)
+		assert.Len(t, discrepancies, 2)
+		assert.Equal(t, 2, discrepancies[0].OpenedCount)
+		assert.Nil(t, discrepancies[0].Difference)
+		assert.Equal(t, intPtr(2), discrepancies[1].Difference)
+	})
+
+	t.Run("Filtered", func(t *testing.T) {
+		mock.ExpectQuery(regexp.QuoteMeta(selectQuery+`WHERE r.pvz_id = $1 AND r.date_time >= $2 AND r.date_time <= $3 `+having+` LIMIT 5 OFFSET 5`)).
+			WithArgs(pvzID, testTime, testTime).
+			WillReturnRows(sqlmock.NewRows(discrepancyRowColumns))
+
+		discrepancies, err := receptionRepo.GetReceptionDiscrepancies(ctx, dto.DiscrepancyFilterQuery{
+			PVZID:     pvzID,
+			StartDate: &testTime,
+			EndDate:   &testTime,
+			Page:      2,
+			Limit:     5,
+		})
+
+		assert.NoError(t, err)
+		assert.Empty(t, discrepancies)
+		assert.NotNil(t, discrepancies)
+	})
+
+	t.Run("DB Error", func(t *testing.T) {
+		mock.ExpectQuery(regexp.QuoteMeta(selectQuery)).
+			WillReturnError(errors.New("db error"))
+
+		discrepancies, err := receptionRepo.GetReceptionDiscrepancies(ctx, dto.DiscrepancyFilterQuery{Page: 1, Limit: 10})
+
+		assert.EqualError(t, err, "failed to query reception discrepancies: db error")
+		assert.Nil(t, discrepancies)
+	})
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func intPtr(n int) *int {
+	return &n
+}
+
+func TestReceptionRepository_GetReceptionForUpdate(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	receptionRepo := repository.NewReceptionRepository(db)
+	ctx := context.Background()
+	receptionID := "c0eebc99-9c0b-4ef8-bb6d-6bb9bd380a11"
+	expectedQuery := regexp.QuoteMeta(`SELECT id, date_time, pvz_id, status, closed_at, first_product_at, last_product_at, expected_count FROM receptions ` +
+		`WHERE id = $1 FOR UPDATE`)
+
+	rows := sqlmock.NewRows(receptionRowColumns).
+		AddRow(receptionID, time.Now(), "b0eebc99-9c0b-4ef8-bb6d-6bb9bd380a11", "close", time.Now(), nil, nil, nil)
+
+	mock.ExpectQuery(expectedQuery).
+		WithArgs(receptionID).
+		WillReturnRows(rows)
+
+	reception, err := receptionRepo.GetReceptionForUpdate(ctx, receptionID)
+
+	assert.NoError(t, err)
+	assert.Equal(t, receptionID, reception.ID)
+
+	mock.ExpectQuery(expectedQuery).
+		WithArgs(receptionID).
+		WillReturnError(sql.ErrNoRows)
+
+	reception, err = receptionRepo.GetReceptionForUpdate(ctx, receptionID)
+
+	assert.ErrorIs(t, err, model.ErrReceptionNotFound)
+	assert.Nil(t, reception)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func TestReceptionRepository_ReopenReception(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	receptionRepo := repository.NewReceptionRepository(db)
+	ctx := context.Background()
+	receptionID := "c0eebc99-9c0b-4ef8-bb6d-6bb9bd380a11"
+	testTime := time.Now()
+	expectedQuery := regexp.QuoteMeta(`UPDATE receptions SET status = $1 WHERE id = $2 AND status = $3 RETURNING ` +
+		`id, date_time, pvz_id, status, closed_at, first_product_at, last_product_at, expected_count`)
+
+	tests := []struct {
+		name              string
+		mockBehavior      func()
+		expectedReception *model.Reception
+		expectedError     error
+	}{
+		{
+			name: "Success",
+			mockBehavior: func() {
+				rows := sqlmock.NewRows(receptionRowColumns).
+					AddRow(receptionID, testTime, "b0eebc99-9c0b-4ef8-bb6d-6bb9bd380a11", "in_progress", testTime, testTime, testTime, nil)
+
+				mock.ExpectQuery(expectedQuery).
+					WithArgs("in_progress", receptionID, "close").
+					WillReturnRows(rows)
+			},
+			expectedReception: &model.Reception{
+				ID:             receptionID,
+				DateTime:       testTime,
+				PVZID:          "b0eebc99-9c0b-4ef8-bb6d-6bb9bd380a11",
+				Status:         "in_progress",
+				ClosedAt:       &testTime,
+				FirstProductAt: &testTime,
+				LastProductAt:  &testTime,
+			},
+		},
+		{
+			name: "Reception Not Closed",
+			mockBehavior: func() {
+				mock.ExpectQuery(expectedQuery).
+					WithArgs("in_progress", receptionID, "close").
+					WillReturnError(sql.ErrNoRows)
+			},
+			expectedError: model.ErrReceptionNotClosed,
+		},
+		{
+			name: "DB Error",
+			mockBehavior: func() {
+				mock.ExpectQuery(expectedQuery).
+					WithArgs("in_progress", receptionID, "close").
+					WillReturnError(errors.New("db error"))
+			},
+			expectedError: errors.New("failed to reopen reception: db error"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockBehavior()
+
+			reception, err := receptionRepo.ReopenReception(ctx, receptionID)
+
+			if tt.expectedError != nil {
+				assert.Error(t, err)
+				assert.Contains(t, err.Error(), tt.expectedError.Error())
+				assert.Nil(t, reception)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expectedReception, reception)
+			}
+
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("there were unfulfilled expectations: %s", err)
+			}
+		})
+	}
+}
+
+func TestReceptionRepository_HasNewerReception(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	receptionRepo := repository.NewReceptionRepository(db)
+	ctx := context.Background()
+	reception := &model.Reception{
+		ID:       "c0eebc99-9c0b-4ef8-bb6d-6bb9bd380a11",
+		PVZID:    "b0eebc99-9c0b-4ef8-bb6d-6bb9bd380a11",
+		DateTime: time.Now(),
+		Status:   "close",
+	}
+	expectedQuery := regexp.QuoteMeta(`SELECT EXISTS(SELECT 1 FROM receptions WHERE pvz_id = $1 AND date_time > $2 AND id <> $3)`)
+
+	mock.ExpectQuery(expectedQuery).
+		WithArgs(reception.PVZID, reception.DateTime, reception.ID).
+		WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(true))
+
+	result, err := receptionRepo.HasNewerReception(ctx, reception)
+
+	assert.NoError(t, err)
+	assert.True(t, result)
+
+	mock.ExpectQuery(expectedQuery).
+		WithArgs(reception.PVZID, reception.DateTime, reception.ID).
+		WillReturnError(errors.New("db error"))
+
+	_, err = receptionRepo.HasNewerReception(ctx, reception)
+
+	assert.EqualError(t, err, "failed to check if newer reception exists: db error")
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func TestReceptionRepository_HasProcessedProducts(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	receptionRepo := repository.NewReceptionRepository(db)
+	ctx := context.Background()
+	receptionID := "c0eebc99-9c0b-4ef8-bb6d-6bb9bd380a11"
+	expectedQuery := regexp.QuoteMeta(`SELECT EXISTS(SELECT 1 FROM products pr WHERE pr.reception_id = $1 AND ` +
+		`(pr.status <> 'received' OR pr.order_id IS NOT NULL OR pr.transfer_id IS NOT NULL OR ` +
+		`EXISTS (SELECT 1 FROM transfer_products tp WHERE tp.product_id = pr.id)))`)
+
+	mock.ExpectQuery(expectedQuery).
+		WithArgs(receptionID).
+		WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(true))
+
+	result, err := receptionRepo.HasProcessedProducts(ctx, receptionID)
+
+	assert.NoError(t, err)
+	assert.True(t, result)
+
+	mock.ExpectQuery(expectedQuery).
+		WithArgs(receptionID).
+		WillReturnError(errors.New("db error"))
+
+	_, err = receptionRepo.HasProcessedProducts(ctx, receptionID)
+
+	assert.EqualError(t, err, "failed to check processed products: db error")
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
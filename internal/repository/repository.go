@@ -1,19 +1,99 @@
 package repository
 
-import "database/sql"
+import (
+	"database/sql"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+const (
+	pqUniqueViolation     pq.ErrorCode = "23505"
+	pqForeignKeyViolation pq.ErrorCode = "23503"
+)
 
 type Repository struct {
-	UserRepository      *UserRepository
-	PVZRepository       *PVZRepository
-	ReceptionRepository *ReceptionRepository
-	ProductRepository   *ProductRepository
+	UserRepository              *UserRepository
+	PVZRepository               *PVZRepository
+	ReceptionRepository         *ReceptionRepository
+	ReceptionStatusRepository   *ReceptionStatusRepository
+	ProductRepository           *ProductRepository
+	ProductStatusRepository     *ProductStatusRepository
+	ProductAttachmentRepository *ProductAttachmentRepository
+	OrderRepository             *OrderRepository
+	ReturnTaskRepository        *ReturnTaskRepository
+	StorageCellRepository       *StorageCellRepository
+	TransferRepository          *TransferRepository
+	ExpectedDeliveryRepository  *ExpectedDeliveryRepository
+	APIKeyRepository            *APIKeyRepository
+	AuditRepository             *AuditRepository
+	CityRepository              *CityRepository
+	ProductTypeRepository       *ProductTypeRepository
+	StatsRepository             *StatsRepository
+	ExportRepository            *ExportRepository
+	IdempotencyRepository       *IdempotencyRepository
+	Transactor                  *Transactor
 }
 
 func NewRepository(db *sql.DB) *Repository {
 	return &Repository{
-		UserRepository:      NewUserRepository(db),
-		PVZRepository:       NewPVZRepository(db),
-		ReceptionRepository: NewReceptionRepository(db),
-		ProductRepository:   NewProductRepository(db),
+		UserRepository:              NewUserRepository(db),
+		PVZRepository:               NewPVZRepository(db),
+		ReceptionRepository:         NewReceptionRepository(db),
+		ReceptionStatusRepository:   NewReceptionStatusRepository(db),
+		ProductRepository:           NewProductRepository(db),
+		ProductStatusRepository:     NewProductStatusRepository(db),
+		ProductAttachmentRepository: NewProductAttachmentRepository(db),
+		OrderRepository:             NewOrderRepository(db),
+		ReturnTaskRepository:        NewReturnTaskRepository(db),
+		StorageCellRepository:       NewStorageCellRepository(db),
+		TransferRepository:          NewTransferRepository(db),
+		ExpectedDeliveryRepository:  NewExpectedDeliveryRepository(db),
+		APIKeyRepository:            NewAPIKeyRepository(db),
+		AuditRepository:             NewAuditRepository(db),
+		CityRepository:              NewCityRepository(db),
+		ProductTypeRepository:       NewProductTypeRepository(db),
+		StatsRepository:             NewStatsRepository(db),
+		ExportRepository:            NewExportRepository(db),
+		IdempotencyRepository:       NewIdempotencyRepository(db),
+		Transactor:                  NewTransactor(db),
+	}
+}
+
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+func nullTimePtr(t sql.NullTime) *time.Time {
+	if !t.Valid {
+		return nil
+	}
+	return &t.Time
+}
+
+func nullIntPtr(n sql.NullInt64) *int {
+	if !n.Valid {
+		return nil
+	}
+	value := int(n.Int64)
+	return &value
+}
+
+func isPQError(err error, code pq.ErrorCode) bool {
+	var pqErr *pq.Error
+	return errors.As(err, &pqErr) && pqErr.Code == code
+}
+
+func columnList(columns []string) string {
+	return strings.Join(columns, ", ")
+}
+
+func prefixColumns(alias string, columns []string) []string {
+	prefixed := make([]string, len(columns))
+	for i, column := range columns {
+		prefixed[i] = alias + "." + column
 	}
+	return prefixed
 }
@@ -0,0 +1,123 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/kirillidk/pvz-service/internal/model"
+)
+
+const (
+	returnTaskTableName = "return_tasks"
+)
+
+// storageDeadlineExpr is the end of the storage period of a product: the
+// period of its type, or the default one passed as the argument, counted
+// from its arrival at the current PVZ.
+const storageDeadlineExpr = "COALESCE(arrival.received_at, pr.date_time) + make_interval(days => COALESCE(pt.storage_days, ?))"
+
+// waitingProductStatuses are the statuses of products still kept at a PVZ.
+var waitingProductStatuses = []string{string(model.ProductStatusReceived), string(model.ProductStatusStored)}
+
+type ReturnTaskRepositoryInterface interface {
+	CreateOverdueReturnTasks(ctx context.Context, now time.Time, defaultStorageDays int) (int64, error)
+	GetReturnTasksByPVZID(ctx context.Context, pvzID string) ([]model.ReturnTask, error)
+}
+
+type ReturnTaskRepository struct {
+	db   *sql.DB
+	psql sq.StatementBuilderType
+}
+
+func NewReturnTaskRepository(db *sql.DB) *ReturnTaskRepository {
+	return &ReturnTaskRepository{
+		db:   db,
+		psql: sq.StatementBuilder.PlaceholderFormat(sq.Dollar),
+	}
+}
+
+// CreateOverdueReturnTasks creates a return task for every product whose
+// storage period has expired by now and returns the number of new tasks.
+// The task goes to the PVZ the product is at now. Products that already
+// have a task are skipped, so the call is safe to repeat.
+func (r *ReturnTaskRepository) CreateOverdueReturnTasks(ctx context.Context, now time.Time, defaultStorageDays int) (int64, error) {
+	overdue := r.psql.
+		Select("pr.id", "r.pvz_id").
+		Column(storageDeadlineExpr, defaultStorageDays).
+		Column("CAST(? AS TIMESTAMP)", now).
+		From(productTableName + " pr").
+		LeftJoin(productArrivalJoin).
+		Join(productReceptionJoin).
+		Join(productTypeTableName + " pt ON pt.code = pr.type").
+		Where(sq.Eq{"pr.status": waitingProductStatuses}).
+		Where(sq.Expr(storageDeadlineExpr+" <= ?", defaultStorageDays, now))
+
+	query, args, err := r.psql.
+		Insert(returnTaskTableName).
+		Columns("product_id", "pvz_id", "storage_deadline", "created_at").
+		Select(overdue).
+		Suffix("ON CONFLICT (product_id) DO NOTHING").
+		ToSql()
+
+	if err != nil {
+		return 0, fmt.Errorf("failed to build sql query: %w", err)
+	}
+
+	result, err := getQuerier(ctx, r.db).ExecContext(ctx, query, args...)
+	if err != nil {
+		return 0, fmt.Errorf("failed to create return tasks: %w", err)
+	}
+
+	created, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	return created, nil
+}
+
+// GetReturnTasksByPVZID returns the return tasks of the PVZ whose products
+// are still waiting there, the longest overdue first.
+func (r *ReturnTaskRepository) GetReturnTasksByPVZID(ctx context.Context, pvzID string) ([]model.ReturnTask, error) {
+	query, args, err := r.psql.
+		Select(prefixColumns("pr", productColumns)...).
+		Columns("t.id", "t.pvz_id", "t.storage_deadline", "t.created_at").
+		From(returnTaskTableName+" t").
+		Join(productTableName+" pr ON pr.id = t.product_id").
+		Where(sq.Eq{"t.pvz_id": pvzID}).
+		Where(sq.Eq{"pr.status": waitingProductStatuses}).
+		OrderBy("t.storage_deadline ASC", "t.id").
+		ToSql()
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to build sql query: %w", err)
+	}
+
+	rows, err := getQuerier(ctx, r.db).QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query return tasks: %w", err)
+	}
+	defer rows.Close()
+
+	tasks := []model.ReturnTask{}
+	for rows.Next() {
+		var task model.ReturnTask
+
+		product, err := scanProduct(rows, &task.ID, &task.PVZID, &task.StorageDeadline, &task.CreatedAt)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan return task row: %w", err)
+		}
+
+		task.Product = *product
+		tasks = append(tasks, task)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating return task rows: %w", err)
+	}
+
+	return tasks, nil
+}
@@ -0,0 +1,114 @@
+package repository_test
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/kirillidk/pvz-service/internal/model"
+	"github.com/kirillidk/pvz-service/internal/repository"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReturnTaskRepository_CreateOverdueReturnTasks(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	returnTaskRepo := repository.NewReturnTaskRepository(db)
+	ctx := context.Background()
+	now := time.Now()
+
+	insertQuery := regexp.QuoteMeta(`INSERT INTO return_tasks (product_id,pvz_id,storage_deadline,created_at) ` +
+		`SELECT pr.id, r.pvz_id, COALESCE(arrival.received_at, pr.date_time) + make_interval(days => COALESCE(pt.storage_days, $1)), CAST($2 AS TIMESTAMP) ` +
+		`FROM products pr LEFT JOIN LATERAL (SELECT t.reception_id, t.received_at FROM transfers t ` +
+		`JOIN transfer_products tp ON tp.transfer_id = t.id WHERE tp.product_id = pr.id AND t.status = 'received' ` +
+		`ORDER BY t.received_at DESC LIMIT 1) arrival ON TRUE ` +
+		`JOIN receptions r ON r.id = COALESCE(arrival.reception_id, pr.reception_id) JOIN product_types pt ON pt.code = pr.type ` +
+		`WHERE pr.status IN ($3,$4) AND COALESCE(arrival.received_at, pr.date_time) + make_interval(days => COALESCE(pt.storage_days, $5)) <= $6 ` +
+		`ON CONFLICT (product_id) DO NOTHING`)
+
+	t.Run("Success", func(t *testing.T) {
+		mock.ExpectExec(insertQuery).
+			WithArgs(14, now, "received", "stored", 14, now).
+			WillReturnResult(sqlmock.NewResult(0, 3))
+
+		created, err := returnTaskRepo.CreateOverdueReturnTasks(ctx, now, 14)
+
+		assert.NoError(t, err)
+		assert.Equal(t, int64(3), created)
+	})
+
+	t.Run("DB Error", func(t *testing.T) {
+		mock.ExpectExec(insertQuery).
+			WillReturnError(errors.New("db error"))
+
+		created, err := returnTaskRepo.CreateOverdueReturnTasks(ctx, now, 14)
+
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "failed to create return tasks: db error")
+		assert.Zero(t, created)
+	})
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func TestReturnTaskRepository_GetReturnTasksByPVZID(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	returnTaskRepo := repository.NewReturnTaskRepository(db)
+	ctx := context.Background()
+	testTime := time.Now()
+
+	pvzID := "b0eebc99-9c0b-4ef8-bb6d-6bb9bd380a11"
+	selectQuery := regexp.QuoteMeta(`SELECT pr.id, pr.date_time, pr.type, pr.reception_id, pr.serial_number, pr.barcode, pr.status, pr.cell_id, pr.condition, pr.condition_note, ` +
+		`t.id, t.pvz_id, t.storage_deadline, t.created_at ` +
+		`FROM return_tasks t JOIN products pr ON pr.id = t.product_id ` +
+		`WHERE t.pvz_id = $1 AND pr.status IN ($2,$3) ORDER BY t.storage_deadline ASC, t.id`)
+
+	rows := sqlmock.NewRows([]string{
+		"id", "date_time", "type", "reception_id", "serial_number", "barcode", "status", "cell_id", "condition", "condition_note",
+		"id", "pvz_id", "storage_deadline", "created_at",
+	}).
+		AddRow("d0eebc99-9c0b-4ef8-bb6d-6bb9bd380a11", testTime, "обувь", "c0eebc99-9c0b-4ef8-bb6d-6bb9bd380a11", nil, nil, "stored", nil, "ok", nil,
+			"f0eebc99-9c0b-4ef8-bb6d-6bb9bd380a11", pvzID, testTime.Add(14*24*time.Hour), testTime.Add(15*24*time.Hour))
+
+	mock.ExpectQuery(selectQuery).
+		WithArgs(pvzID, "received", "stored").
+		WillReturnRows(rows)
+
+	tasks, err := returnTaskRepo.GetReturnTasksByPVZID(ctx, pvzID)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []model.ReturnTask{
+		{
+			ID:    "f0eebc99-9c0b-4ef8-bb6d-6bb9bd380a11",
+			PVZID: pvzID,
+			Product: model.Product{
+				ID:          "d0eebc99-9c0b-4ef8-bb6d-6bb9bd380a11",
+				DateTime:    testTime,
+				Type:        "обувь",
+				ReceptionID: "c0eebc99-9c0b-4ef8-bb6d-6bb9bd380a11",
+				Status:      model.ProductStatusStored,
+				Condition:   model.ProductConditionOK,
+			},
+			StorageDeadline: testTime.Add(14 * 24 * time.Hour),
+			CreatedAt:       testTime.Add(15 * 24 * time.Hour),
+		},
+	}, tasks)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
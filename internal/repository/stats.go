@@ -0,0 +1,334 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sort"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/kirillidk/pvz-service/internal/dto"
+	"github.com/kirillidk/pvz-service/internal/model"
+)
+
+const statsDateFormat = "2006-01-02"
+
+// receptionDuration is the duration of a reception in seconds. It is NULL
+// for receptions in progress, including reopened ones that keep the time
+// they were last closed, and for receptions closed before close times were
+// recorded, so aggregates only cover closed ones with a known close time.
+const receptionDuration = "CASE WHEN r.status = 'close' THEN EXTRACT(EPOCH FROM r.closed_at - r.date_time) END"
+
+// statsCityJoin brings in the city c of the PVZ p, whose timezone defines
+// the day an event belongs to.
+const statsCityJoin = cityTableName + " c ON c.name = p.city"
+
+// localTime converts a timestamp column to the local time of the city c.
+// Timestamps are written in UTC whatever the zone of the server, since
+// TIMESTAMP columns drop the offset. PVZs of cities missing from the catalog fall back to UTC.
+func localTime(column string) string {
+	return "((" + column + " AT TIME ZONE 'UTC') AT TIME ZONE COALESCE(c.timezone, 'UTC'))"
+}
+
+type StatsRepositoryInterface interface {
+	GetDailyReceptionStats(ctx context.Context, query dto.DailyStatsQuery) ([]model.DailyReceptionStats, error)
+	GetReceptionThroughputStats(ctx context.Context, query dto.ReceptionThroughputQuery) ([]model.ReceptionThroughputStats, error)
+}
+
+type StatsRepository struct {
+	db   *sql.DB
+	psql sq.StatementBuilderType
+}
+
+func NewStatsRepository(db *sql.DB) *StatsRepository {
+	return &StatsRepository{
+		db:   db,
+		psql: sq.StatementBuilder.PlaceholderFormat(sq.Dollar),
+	}
+}
+
+// GetDailyReceptionStats aggregates receptions by PVZ and the day they were
+// started, for days from query.From to query.To inclusive. Days are taken in
+// the timezone of the PVZ city. Products are counted on the day of their
+// reception; products that arrived by a transfer count for the reception
+// they joined at the destination PVZ.
+func (r *StatsRepository) GetDailyReceptionStats(ctx context.Context, query dto.DailyStatsQuery) ([]model.DailyReceptionStats, error) {
+	receptionQuery, args, err := withStatsFilter(
+		r.psql.
+			Select("r.pvz_id", "DATE("+localTime("r.date_time")+") AS day", "COUNT(*)", "AVG("+receptionDuration+")").
+			From(receptionTableName+" r").
+			Join(pvzTableName+" p ON p.id = r.pvz_id").
+			LeftJoin(statsCityJoin),
+		query.From, query.To, query.PVZID,
+	).
+		GroupBy("r.pvz_id", "day").
+		OrderBy("day", "r.pvz_id").
+		ToSql()
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to build sql query: %w", err)
+	}
+
+	q := getQuerier(ctx, r.db)
+
+	rows, err := q.QueryContext(ctx, receptionQuery, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query reception stats: %w", err)
+	}
+	defer rows.Close()
+
+	stats := []model.DailyReceptionStats{}
+	index := make(map[string]int)
+	for rows.Next() {
+		var (
+			day             time.Time
+			averageDuration sql.NullFloat64
+			dayStats        model.DailyReceptionStats
+		)
+
+		if err := rows.Scan(&dayStats.PVZID, &day, &dayStats.ReceptionCount, &averageDuration); err != nil {
+			return nil, fmt.Errorf("failed to scan reception stats row: %w", err)
+		}
+
+		dayStats.Date = day.Format(statsDateFormat)
+		dayStats.ProductsByType = make(map[string]int64)
+		if averageDuration.Valid {
+			dayStats.AverageDurationSeconds = &averageDuration.Float64
+		}
+
+		index[dayStats.PVZID+dayStats.Date] = len(stats)
+		stats = append(stats, dayStats)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating reception stats rows: %w", err)
+	}
+
+	productQuery, args, err := withStatsFilter(
+		r.psql.
+			Select("r.pvz_id", "DATE("+localTime("r.date_time")+") AS day", "pr.type", "COUNT(*)").
+			From(productTableName+" pr").
+			LeftJoin(productArrivalJoin).
+			Join(productReceptionJoin).
+			Join(pvzTableName+" p ON p.id = r.pvz_id").
+			LeftJoin(statsCityJoin),
+		query.From, query.To, query.PVZID,
+	).
+		GroupBy("r.pvz_id", "day", "pr.type").
+		ToSql()
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to build sql query: %w", err)
+	}
+
+	productRows, err := q.QueryContext(ctx, productQuery, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query product stats: %w", err)
+	}
+	defer productRows.Close()
+
+	for productRows.Next() {
+		var (
+			pvzID       string
+			day         time.Time
+			productType string
+			count       int64
+		)
+
+		if err := productRows.Scan(&pvzID, &day, &productType, &count); err != nil {
+			return nil, fmt.Errorf("failed to scan product stats row: %w", err)
+		}
+
+		i, ok := index[pvzID+day.Format(statsDateFormat)]
+		if !ok {
+			continue
+		}
+
+		stats[i].ProductsByType[productType] = count
+		stats[i].ProductCount += count
+	}
+
+	if err := productRows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating product stats rows: %w", err)
+	}
+
+	if query.IncludeIssued {
+		return r.addIssuedCounts(ctx, query, stats, index)
+	}
+
+	return stats, nil
+}
+
+// addIssuedCounts sets the number of products issued per PVZ and day. A
+// product is counted for the PVZ it was kept at, which for transferred
+// products is the destination. Days with issued products but no receptions
+// are added to stats.
+func (r *StatsRepository) addIssuedCounts(
+	ctx context.Context,
+	query dto.DailyStatsQuery,
+	stats []model.DailyReceptionStats,
+	index map[string]int,
+) ([]model.DailyReceptionStats, error) {
+	for i := range stats {
+		stats[i].IssuedCount = new(int64)
+	}
+
+	queryBuilder := r.psql.
+		Select("r.pvz_id", "DATE("+localTime("h.changed_at")+") AS day", "COUNT(*)").
+		From(productStatusHistoryTableName + " h").
+		Join(productTableName + " pr ON pr.id = h.product_id").
+		LeftJoin(productArrivalJoin).
+		Join(productReceptionJoin).
+		Join(pvzTableName + " p ON p.id = r.pvz_id").
+		LeftJoin(statsCityJoin).
+		Where(sq.Eq{"h.to_status": model.ProductStatusIssued}).
+		Where(sq.GtOrEq{localTime("h.changed_at"): query.From}).
+		Where(sq.Lt{localTime("h.changed_at"): query.To.AddDate(0, 0, 1)})
+
+	if query.PVZID != "" {
+		queryBuilder = queryBuilder.Where(sq.Eq{"r.pvz_id": query.PVZID})
+	}
+
+	issuedQuery, args, err := queryBuilder.
+		GroupBy("r.pvz_id", "day").
+		ToSql()
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to build sql query: %w", err)
+	}
+
+	rows, err := getQuerier(ctx, r.db).QueryContext(ctx, issuedQuery, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query issued stats: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			pvzID string
+			day   time.Time
+			count int64
+		)
+
+		if err := rows.Scan(&pvzID, &day, &count); err != nil {
+			return nil, fmt.Errorf("failed to scan issued stats row: %w", err)
+		}
+
+		date := day.Format(statsDateFormat)
+		if i, ok := index[pvzID+date]; ok {
+			*stats[i].IssuedCount = count
+			continue
+		}
+
+		stats = append(stats, model.DailyReceptionStats{
+			Date:           date,
+			PVZID:          pvzID,
+			ProductsByType: make(map[string]int64),
+			IssuedCount:    &count,
+		})
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating issued stats rows: %w", err)
+	}
+
+	sort.SliceStable(stats, func(i, j int) bool {
+		if stats[i].Date != stats[j].Date {
+			return stats[i].Date < stats[j].Date
+		}
+		return stats[i].PVZID < stats[j].PVZID
+	})
+
+	return stats, nil
+}
+
+// GetReceptionThroughputStats computes duration percentiles and products
+// per minute over closed receptions started from query.From to query.To
+// inclusive in the local time of the PVZ city, grouped by PVZ or by city.
+// Transferred products count for the reception they joined at the
+// destination PVZ.
+func (r *StatsRepository) GetReceptionThroughputStats(
+	ctx context.Context,
+	query dto.ReceptionThroughputQuery,
+) ([]model.ReceptionThroughputStats, error) {
+	keyColumns := []string{"r.pvz_id", "p.city"}
+	groupColumns := []string{"p.city", "r.pvz_id"}
+	if query.GroupBy == dto.ThroughputGroupByCity {
+		keyColumns = []string{"'' AS pvz_id", "p.city"}
+		groupColumns = []string{"p.city"}
+	}
+
+	queryBuilder := r.psql.
+		Select(keyColumns...).
+		Columns(
+			"COUNT(*)",
+			"COALESCE(SUM(pc.product_count), 0)",
+			"PERCENTILE_CONT(0.5) WITHIN GROUP (ORDER BY "+receptionDuration+")",
+			"PERCENTILE_CONT(0.95) WITHIN GROUP (ORDER BY "+receptionDuration+")",
+			"SUM(pc.product_count) / NULLIF(SUM("+receptionDuration+") / 60, 0)",
+		).
+		From(receptionTableName + " r").
+		Join(pvzTableName + " p ON p.id = r.pvz_id").
+		LeftJoin(statsCityJoin).
+		LeftJoin("LATERAL (SELECT COUNT(*) AS product_count FROM " + productTableName + " pr " +
+			"LEFT JOIN " + productArrivalJoin + " WHERE " + productReceptionIDExpr + " = r.id) pc ON TRUE").
+		Where("r.status = 'close' AND r.closed_at IS NOT NULL")
+
+	sqlQuery, args, err := withStatsFilter(queryBuilder, query.From, query.To, "").
+		GroupBy(groupColumns...).
+		OrderBy(groupColumns...).
+		ToSql()
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to build sql query: %w", err)
+	}
+
+	rows, err := getQuerier(ctx, r.db).QueryContext(ctx, sqlQuery, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query reception throughput: %w", err)
+	}
+	defer rows.Close()
+
+	stats := []model.ReceptionThroughputStats{}
+	for rows.Next() {
+		var (
+			item              model.ReceptionThroughputStats
+			productsPerMinute sql.NullFloat64
+		)
+
+		err := rows.Scan(
+			&item.PVZID, &item.City, &item.ReceptionCount, &item.ProductCount,
+			&item.MedianDurationSeconds, &item.P95DurationSeconds, &productsPerMinute,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan reception throughput row: %w", err)
+		}
+
+		if productsPerMinute.Valid {
+			item.ProductsPerMinute = &productsPerMinute.Float64
+		}
+
+		stats = append(stats, item)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating reception throughput rows: %w", err)
+	}
+
+	return stats, nil
+}
+
+// withStatsFilter limits receptions to the days from..to inclusive in the
+// local time of their city. The query must join the city as c.
+func withStatsFilter(queryBuilder sq.SelectBuilder, from, to time.Time, pvzID string) sq.SelectBuilder {
+	queryBuilder = queryBuilder.
+		Where(sq.GtOrEq{localTime("r.date_time"): from}).
+		Where(sq.Lt{localTime("r.date_time"): to.AddDate(0, 0, 1)})
+
+	if pvzID != "" {
+		queryBuilder = queryBuilder.Where(sq.Eq{"r.pvz_id": pvzID})
+	}
+
+	return queryBuilder
+}
@@ -0,0 +1,298 @@
+package repository_test
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/kirillidk/pvz-service/internal/dto"
+	"github.com/kirillidk/pvz-service/internal/model"
+	"github.com/kirillidk/pvz-service/internal/repository"
+	"github.com/stretchr/testify/assert"
+)
+
+// statsArrivalJoin is the join that finds the reception a transferred
+// product joined at its destination PVZ.
+const statsArrivalJoin = `LEFT JOIN LATERAL (SELECT t.reception_id, t.received_at FROM transfers t JOIN transfer_products tp ON tp.transfer_id = t.id ` +
+	`WHERE tp.product_id = pr.id AND t.status = 'received' ORDER BY t.received_at DESC LIMIT 1) arrival ON TRUE`
+
+func TestStatsRepository_GetDailyReceptionStats(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	statsRepo := repository.NewStatsRepository(db)
+	ctx := context.Background()
+
+	pvzID := "b0eebc99-9c0b-4ef8-bb6d-6bb9bd380a11"
+	from := time.Date(2025, 4, 14, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2025, 4, 15, 0, 0, 0, 0, time.UTC)
+	averageDuration := 1800.0
+	destinationPVZID := "d0eebc99-9c0b-4ef8-bb6d-6bb9bd380a11"
+	issuedCount, noneIssued, transferredIssued := int64(4), int64(0), int64(1)
+
+	localDateTime := `((r.date_time AT TIME ZONE 'UTC') AT TIME ZONE COALESCE(c.timezone, 'UTC'))`
+	fromClause := ` FROM receptions r JOIN pvz p ON p.id = r.pvz_id LEFT JOIN cities c ON c.name = p.city`
+	whereClause := ` WHERE ` + localDateTime + ` >= $1 AND ` + localDateTime + ` < $2`
+	receptionStatsQuery := `SELECT r.pvz_id, DATE(` + localDateTime + `) AS day, COUNT(*), AVG(CASE WHEN r.status = 'close' THEN EXTRACT(EPOCH FROM r.closed_at - r.date_time) END)` + fromClause + whereClause
+	productStatsQuery := `SELECT r.pvz_id, DATE(` + localDateTime + `) AS day, pr.type, COUNT(*) FROM products pr ` + statsArrivalJoin +
+		` JOIN receptions r ON r.id = COALESCE(arrival.reception_id, pr.reception_id) JOIN pvz p ON p.id = r.pvz_id LEFT JOIN cities c ON c.name = p.city` + whereClause
+
+	tests := []struct {
+		name          string
+		query         dto.DailyStatsQuery
+		mockBehavior  func()
+		expectedValue []model.DailyReceptionStats
+		expectedError error
+	}{
+		{
+			name:  "Success For PVZ",
+			query: dto.DailyStatsQuery{From: from, To: to, PVZID: pvzID},
+			mockBehavior: func() {
+				mock.ExpectQuery(regexp.QuoteMeta(receptionStatsQuery+` AND r.pvz_id = $3 GROUP BY r.pvz_id, day ORDER BY day, r.pvz_id`)).
+					WithArgs(from, to.AddDate(0, 0, 1), pvzID).
+					WillReturnRows(sqlmock.NewRows([]string{"pvz_id", "day", "count", "avg"}).
+						AddRow(pvzID, from, 2, averageDuration).
+						AddRow(pvzID, to, 1, nil))
+
+				mock.ExpectQuery(regexp.QuoteMeta(productStatsQuery+` AND r.pvz_id = $3 GROUP BY r.pvz_id, day, pr.type`)).
+					WithArgs(from, to.AddDate(0, 0, 1), pvzID).
+					WillReturnRows(sqlmock.NewRows([]string{"pvz_id", "day", "type", "count"}).
+						AddRow(pvzID, from, "электроника", 3).
+						AddRow(pvzID, from, "обувь", 2))
+			},
+			expectedValue: []model.DailyReceptionStats{
+				{
+					Date:                   "2025-04-14",
+					PVZID:                  pvzID,
+					ReceptionCount:         2,
+					ProductCount:           5,
+					ProductsByType:         map[string]int64{"электроника": 3, "обувь": 2},
+					AverageDurationSeconds: &averageDuration,
+				},
+				{
+					Date:           "2025-04-15",
+					PVZID:          pvzID,
+					ReceptionCount: 1,
+					ProductsByType: map[string]int64{},
+				},
+			},
+		},
+		{
+			name:  "Success With Issued",
+			query: dto.DailyStatsQuery{From: from, To: to, IncludeIssued: true},
+			mockBehavior: func() {
+				mock.ExpectQuery(regexp.QuoteMeta(receptionStatsQuery+` GROUP BY r.pvz_id, day ORDER BY day, r.pvz_id`)).
+					WithArgs(from, to.AddDate(0, 0, 1)).
+					WillReturnRows(sqlmock.NewRows([]string{"pvz_id", "day", "count", "avg"}).
+						AddRow(pvzID, from, 1, nil))
+
+				mock.ExpectQuery(regexp.QuoteMeta(productStatsQuery+` GROUP BY r.pvz_id, day, pr.type`)).
+					WithArgs(from, to.AddDate(0, 0, 1)).
+					WillReturnRows(sqlmock.NewRows([]string{"pvz_id", "day", "type", "count"}))
+
+				localChangedAt := `((h.changed_at AT TIME ZONE 'UTC') AT TIME ZONE COALESCE(c.timezone, 'UTC'))`
+				mock.ExpectQuery(regexp.QuoteMeta(`SELECT r.pvz_id, DATE(`+localChangedAt+`) AS day, COUNT(*) FROM product_status_history h `+
+					`JOIN products pr ON pr.id = h.product_id `+statsArrivalJoin+` JOIN receptions r ON r.id = COALESCE(arrival.reception_id, pr.reception_id) `+
+					`JOIN pvz p ON p.id = r.pvz_id LEFT JOIN cities c ON c.name = p.city `+
+					`WHERE h.to_status = $1 AND `+localChangedAt+` >= $2 AND `+localChangedAt+` < $3 GROUP BY r.pvz_id, day`)).
+					WithArgs(model.ProductStatusIssued, from, to.AddDate(0, 0, 1)).
+					WillReturnRows(sqlmock.NewRows([]string{"pvz_id", "day", "count"}).
+						AddRow(pvzID, to, 4))
+			},
+			expectedValue: []model.DailyReceptionStats{
+				{
+					Date:           "2025-04-14",
+					PVZID:          pvzID,
+					ReceptionCount: 1,
+					ProductsByType: map[string]int64{},
+					IssuedCount:    &noneIssued,
+				},
+				{
+					Date:           "2025-04-15",
+					PVZID:          pvzID,
+					ProductsByType: map[string]int64{},
+					IssuedCount:    &issuedCount,
+				},
+			},
+		},
+		{
+			// The product was received at pvzID and transferred to
+			// destinationPVZID, where it was received into another reception
+			// and issued: it counts for the destination.
+			name:  "Transferred Product",
+			query: dto.DailyStatsQuery{From: from, To: to, PVZID: destinationPVZID, IncludeIssued: true},
+			mockBehavior: func() {
+				mock.ExpectQuery(regexp.QuoteMeta(receptionStatsQuery+` AND r.pvz_id = $3 GROUP BY r.pvz_id, day ORDER BY day, r.pvz_id`)).
+					WithArgs(from, to.AddDate(0, 0, 1), destinationPVZID).
+					WillReturnRows(sqlmock.NewRows([]string{"pvz_id", "day", "count", "avg"}).
+						AddRow(destinationPVZID, to, 1, nil))
+
+				mock.ExpectQuery(regexp.QuoteMeta(productStatsQuery+` AND r.pvz_id = $3 GROUP BY r.pvz_id, day, pr.type`)).
+					WithArgs(from, to.AddDate(0, 0, 1), destinationPVZID).
+					WillReturnRows(sqlmock.NewRows([]string{"pvz_id", "day", "type", "count"}).
+						AddRow(destinationPVZID, to, "обувь", 1))
+
+				mock.ExpectQuery(regexp.QuoteMeta(`FROM product_status_history h JOIN products pr ON pr.id = h.product_id `+statsArrivalJoin+
+					` JOIN receptions r ON r.id = COALESCE(arrival.reception_id, pr.reception_id) JOIN pvz p ON p.id = r.pvz_id`)+
+					`.*`+regexp.QuoteMeta(`AND r.pvz_id = $4 GROUP BY r.pvz_id, day`)).
+					WithArgs(model.ProductStatusIssued, from, to.AddDate(0, 0, 1), destinationPVZID).
+					WillReturnRows(sqlmock.NewRows([]string{"pvz_id", "day", "count"}).
+						AddRow(destinationPVZID, to, 1))
+			},
+			expectedValue: []model.DailyReceptionStats{
+				{
+					Date:           "2025-04-15",
+					PVZID:          destinationPVZID,
+					ReceptionCount: 1,
+					ProductCount:   1,
+					ProductsByType: map[string]int64{"обувь": 1},
+					IssuedCount:    &transferredIssued,
+				},
+			},
+		},
+		{
+			name:  "Empty Result",
+			query: dto.DailyStatsQuery{From: from, To: to},
+			mockBehavior: func() {
+				mock.ExpectQuery(regexp.QuoteMeta(receptionStatsQuery+` GROUP BY r.pvz_id, day ORDER BY day, r.pvz_id`)).
+					WithArgs(from, to.AddDate(0, 0, 1)).
+					WillReturnRows(sqlmock.NewRows([]string{"pvz_id", "day", "count", "avg"}))
+
+				mock.ExpectQuery(regexp.QuoteMeta(productStatsQuery+` GROUP BY r.pvz_id, day, pr.type`)).
+					WithArgs(from, to.AddDate(0, 0, 1)).
+					WillReturnRows(sqlmock.NewRows([]string{"pvz_id", "day", "type", "count"}))
+			},
+			expectedValue: []model.DailyReceptionStats{},
+		},
+		{
+			name:  "DB Error",
+			query: dto.DailyStatsQuery{From: from, To: to},
+			mockBehavior: func() {
+				mock.ExpectQuery(regexp.QuoteMeta(receptionStatsQuery)).
+					WillReturnError(errors.New("db error"))
+			},
+			expectedError: errors.New("failed to query reception stats: db error"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockBehavior()
+
+			stats, err := statsRepo.GetDailyReceptionStats(ctx, tt.query)
+
+			if tt.expectedError != nil {
+				assert.Error(t, err)
+				assert.Contains(t, err.Error(), tt.expectedError.Error())
+				assert.Nil(t, stats)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expectedValue, stats)
+			}
+
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("there were unfulfilled expectations: %s", err)
+			}
+		})
+	}
+}
+
+func TestStatsRepository_GetReceptionThroughputStats(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	statsRepo := repository.NewStatsRepository(db)
+	ctx := context.Background()
+
+	pvzID := "b0eebc99-9c0b-4ef8-bb6d-6bb9bd380a11"
+	from := time.Date(2025, 4, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2025, 4, 30, 0, 0, 0, 0, time.UTC)
+	productsPerMinute := 1.5
+
+	throughputColumns := []string{"pvz_id", "city", "count", "product_count", "median", "p95", "products_per_minute"}
+	aggregates := `COUNT(*), COALESCE(SUM(pc.product_count), 0), PERCENTILE_CONT(0.5) WITHIN GROUP (ORDER BY CASE WHEN r.status = 'close' THEN EXTRACT(EPOCH FROM r.closed_at - r.date_time) END), PERCENTILE_CONT(0.95) WITHIN GROUP (ORDER BY CASE WHEN r.status = 'close' THEN EXTRACT(EPOCH FROM r.closed_at - r.date_time) END), SUM(pc.product_count) / NULLIF(SUM(CASE WHEN r.status = 'close' THEN EXTRACT(EPOCH FROM r.closed_at - r.date_time) END) / 60, 0)`
+	localDateTime := `((r.date_time AT TIME ZONE 'UTC') AT TIME ZONE COALESCE(c.timezone, 'UTC'))`
+	fromClause := ` FROM receptions r JOIN pvz p ON p.id = r.pvz_id LEFT JOIN cities c ON c.name = p.city LEFT JOIN LATERAL (SELECT COUNT(*) AS product_count FROM products pr ` + statsArrivalJoin + ` WHERE COALESCE(arrival.reception_id, pr.reception_id) = r.id) pc ON TRUE WHERE r.status = 'close' AND r.closed_at IS NOT NULL AND ` + localDateTime + ` >= $1 AND ` + localDateTime + ` < $2`
+
+	tests := []struct {
+		name          string
+		query         dto.ReceptionThroughputQuery
+		mockBehavior  func()
+		expectedValue []model.ReceptionThroughputStats
+		expectedError error
+	}{
+		{
+			name:  "Success By PVZ",
+			query: dto.ReceptionThroughputQuery{From: from, To: to, GroupBy: dto.ThroughputGroupByPVZ},
+			mockBehavior: func() {
+				mock.ExpectQuery(regexp.QuoteMeta(`SELECT r.pvz_id, p.city, `+aggregates+fromClause+` GROUP BY p.city, r.pvz_id ORDER BY p.city, r.pvz_id`)).
+					WithArgs(from, to.AddDate(0, 0, 1)).
+					WillReturnRows(sqlmock.NewRows(throughputColumns).
+						AddRow(pvzID, "Москва", 4, 90, 1200.0, 3000.0, productsPerMinute))
+			},
+			expectedValue: []model.ReceptionThroughputStats{
+				{
+					PVZID:                 pvzID,
+					City:                  "Москва",
+					ReceptionCount:        4,
+					ProductCount:          90,
+					MedianDurationSeconds: 1200,
+					P95DurationSeconds:    3000,
+					ProductsPerMinute:     &productsPerMinute,
+				},
+			},
+		},
+		{
+			name:  "Success By City",
+			query: dto.ReceptionThroughputQuery{From: from, To: to, GroupBy: dto.ThroughputGroupByCity},
+			mockBehavior: func() {
+				mock.ExpectQuery(regexp.QuoteMeta(`SELECT '' AS pvz_id, p.city, `+aggregates+fromClause+` GROUP BY p.city ORDER BY p.city`)).
+					WithArgs(from, to.AddDate(0, 0, 1)).
+					WillReturnRows(sqlmock.NewRows(throughputColumns).
+						AddRow("", "Казань", 1, 0, 0.0, 0.0, nil))
+			},
+			expectedValue: []model.ReceptionThroughputStats{
+				{City: "Казань", ReceptionCount: 1},
+			},
+		},
+		{
+			name:  "DB Error",
+			query: dto.ReceptionThroughputQuery{From: from, To: to, GroupBy: dto.ThroughputGroupByPVZ},
+			mockBehavior: func() {
+				mock.ExpectQuery(regexp.QuoteMeta(`SELECT r.pvz_id, p.city`)).
+					WillReturnError(errors.New("db error"))
+			},
+			expectedError: errors.New("failed to query reception throughput: db error"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockBehavior()
+
+			stats, err := statsRepo.GetReceptionThroughputStats(ctx, tt.query)
+
+			if tt.expectedError != nil {
+				assert.Error(t, err)
+				assert.Contains(t, err.Error(), tt.expectedError.Error())
+				assert.Nil(t, stats)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expectedValue, stats)
+			}
+
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("there were unfulfilled expectations: %s", err)
+			}
+		})
+	}
+}
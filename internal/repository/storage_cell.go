@@ -0,0 +1,319 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/kirillidk/pvz-service/internal/dto"
+	"github.com/kirillidk/pvz-service/internal/model"
+)
+
+const (
+	storageCellTableName = "storage_cells"
+)
+
+var storageCellColumns = []string{"id", "pvz_id", "code", "capacity", "product_type", "created_at"}
+
+// storageCellOccupiedExpr counts the products still kept in the cell c,
+// that is, the ones waiting at the PVZ.
+const storageCellOccupiedExpr = "(SELECT COUNT(*) FROM products pr WHERE pr.cell_id = c.id AND pr.status IN ('received', 'stored'))"
+
+type StorageCellRepositoryInterface interface {
+	CreateStorageCell(ctx context.Context, pvzID string, cellReq dto.StorageCellCreateRequest) (*model.StorageCell, error)
+	GetStorageCellsByPVZID(ctx context.Context, pvzID string) ([]model.StorageCell, error)
+	GetStorageCellForUpdate(ctx context.Context, cellID string) (*model.StorageCell, error)
+	UpdateStorageCell(ctx context.Context, cellID string, cellReq dto.StorageCellUpdateRequest) (*model.StorageCell, error)
+	DeleteStorageCell(ctx context.Context, cellID string) error
+	CountProductsInCell(ctx context.Context, cellID string) (int, error)
+	GetStorageCellOccupancy(ctx context.Context, pvzID string) ([]model.StorageCellOccupancy, error)
+	GetLeastOccupiedCell(ctx context.Context, pvzID, productType string) (*model.StorageCell, error)
+}
+
+type StorageCellRepository struct {
+	db   *sql.DB
+	psql sq.StatementBuilderType
+}
+
+func NewStorageCellRepository(db *sql.DB) *StorageCellRepository {
+	return &StorageCellRepository{
+		db:   db,
+		psql: sq.StatementBuilder.PlaceholderFormat(sq.Dollar),
+	}
+}
+
+func (r *StorageCellRepository) CreateStorageCell(
+	ctx context.Context,
+	pvzID string,
+	cellReq dto.StorageCellCreateRequest,
+) (*model.StorageCell, error) {
+	query, args, err := r.psql.
+		Insert(storageCellTableName).
+		Columns(storageCellColumns[1:]...).
+		Values(pvzID, cellReq.Code, cellReq.Capacity, nullString(cellReq.ProductType), time.Now().UTC()).
+		Suffix("RETURNING " + columnList(storageCellColumns)).
+		ToSql()
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to build sql query: %w", err)
+	}
+
+	cell, err := scanStorageCell(getQuerier(ctx, r.db).QueryRowContext(ctx, query, args...))
+	if err != nil {
+		if isPQError(err, pqUniqueViolation) {
+			return nil, model.ErrStorageCellAlreadyExists
+		}
+		if isPQError(err, pqForeignKeyViolation) {
+			if cellReq.ProductType != "" {
+				return nil, model.ErrProductTypeNotFound
+			}
+			return nil, model.ErrPVZNotFound
+		}
+		return nil, fmt.Errorf("failed to create storage cell: %w", err)
+	}
+
+	return cell, nil
+}
+
+func (r *StorageCellRepository) GetStorageCellsByPVZID(ctx context.Context, pvzID string) ([]model.StorageCell, error) {
+	query, args, err := r.psql.
+		Select(storageCellColumns...).
+		From(storageCellTableName).
+		Where(sq.Eq{"pvz_id": pvzID}).
+		OrderBy("code").
+		ToSql()
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to build sql query: %w", err)
+	}
+
+	rows, err := getQuerier(ctx, r.db).QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query storage cells: %w", err)
+	}
+	defer rows.Close()
+
+	cells := []model.StorageCell{}
+	for rows.Next() {
+		cell, err := scanStorageCell(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan storage cell row: %w", err)
+		}
+		cells = append(cells, *cell)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating storage cell rows: %w", err)
+	}
+
+	return cells, nil
+}
+
+// GetStorageCellForUpdate locks the cell until the end of the surrounding
+// transaction, so that concurrent placements cannot overfill it.
+func (r *StorageCellRepository) GetStorageCellForUpdate(ctx context.Context, cellID string) (*model.StorageCell, error) {
+	query, args, err := r.psql.
+		Select(storageCellColumns...).
+		From(storageCellTableName).
+		Where(sq.Eq{"id": cellID}).
+		Suffix("FOR UPDATE").
+		ToSql()
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to build sql query: %w", err)
+	}
+
+	cell, err := scanStorageCell(getQuerier(ctx, r.db).QueryRowContext(ctx, query, args...))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, model.ErrStorageCellNotFound
+		}
+		return nil, fmt.Errorf("failed to get storage cell: %w", err)
+	}
+
+	return cell, nil
+}
+
+func (r *StorageCellRepository) UpdateStorageCell(
+	ctx context.Context,
+	cellID string,
+	cellReq dto.StorageCellUpdateRequest,
+) (*model.StorageCell, error) {
+	queryBuilder := r.psql.
+		Update(storageCellTableName).
+		Where(sq.Eq{"id": cellID}).
+		Suffix("RETURNING " + columnList(storageCellColumns))
+
+	if cellReq.Code != nil {
+		queryBuilder = queryBuilder.Set("code", *cellReq.Code)
+	}
+
+	if cellReq.Capacity != nil {
+		queryBuilder = queryBuilder.Set("capacity", *cellReq.Capacity)
+	}
+
+	if cellReq.ProductType != nil {
+		queryBuilder = queryBuilder.Set("product_type", nullString(*cellReq.ProductType))
+	}
+
+	query, args, err := queryBuilder.ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build sql query: %w", err)
+	}
+
+	cell, err := scanStorageCell(getQuerier(ctx, r.db).QueryRowContext(ctx, query, args...))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, model.ErrStorageCellNotFound
+		}
+		if isPQError(err, pqUniqueViolation) {
+			return nil, model.ErrStorageCellAlreadyExists
+		}
+		if isPQError(err, pqForeignKeyViolation) {
+			return nil, model.ErrProductTypeNotFound
+		}
+		return nil, fmt.Errorf("failed to update storage cell: %w", err)
+	}
+
+	return cell, nil
+}
+
+// DeleteStorageCell deletes the cell. Products that were kept in it lose
+// their cell.
+func (r *StorageCellRepository) DeleteStorageCell(ctx context.Context, cellID string) error {
+	query, args, err := r.psql.
+		Delete(storageCellTableName).
+		Where(sq.Eq{"id": cellID}).
+		ToSql()
+
+	if err != nil {
+		return fmt.Errorf("failed to build sql query: %w", err)
+	}
+
+	result, err := getQuerier(ctx, r.db).ExecContext(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("failed to delete storage cell: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return model.ErrStorageCellNotFound
+	}
+
+	return nil
+}
+
+// CountProductsInCell returns the number of products still kept in the cell.
+func (r *StorageCellRepository) CountProductsInCell(ctx context.Context, cellID string) (int, error) {
+	query, args, err := r.psql.
+		Select("COUNT(*)").
+		From(productTableName).
+		Where(sq.Eq{"cell_id": cellID}).
+		Where(sq.Eq{"status": waitingProductStatuses}).
+		ToSql()
+
+	if err != nil {
+		return 0, fmt.Errorf("failed to build sql query: %w", err)
+	}
+
+	var count int
+	if err := getQuerier(ctx, r.db).QueryRowContext(ctx, query, args...).Scan(&count); err != nil {
+		return 0, fmt.Errorf("failed to count products in storage cell: %w", err)
+	}
+
+	return count, nil
+}
+
+func (r *StorageCellRepository) GetStorageCellOccupancy(ctx context.Context, pvzID string) ([]model.StorageCellOccupancy, error) {
+	query, args, err := r.psql.
+		Select(prefixColumns("c", storageCellColumns)...).
+		Column(storageCellOccupiedExpr).
+		From(storageCellTableName + " c").
+		Where(sq.Eq{"c.pvz_id": pvzID}).
+		OrderBy("c.code").
+		ToSql()
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to build sql query: %w", err)
+	}
+
+	rows, err := getQuerier(ctx, r.db).QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query storage cell occupancy: %w", err)
+	}
+	defer rows.Close()
+
+	occupancy := []model.StorageCellOccupancy{}
+	for rows.Next() {
+		var cellOccupancy model.StorageCellOccupancy
+
+		cell, err := scanStorageCell(rows, &cellOccupancy.Occupied)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan storage cell row: %w", err)
+		}
+
+		cellOccupancy.StorageCell = *cell
+		cellOccupancy.Free = max(cell.Capacity-cellOccupancy.Occupied, 0)
+
+		occupancy = append(occupancy, cellOccupancy)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating storage cell rows: %w", err)
+	}
+
+	return occupancy, nil
+}
+
+// GetLeastOccupiedCell returns the cell of the PVZ with free space that is
+// the least full relative to its capacity. Cells dedicated to productType
+// are preferred to general ones; cells of other types are never returned.
+func (r *StorageCellRepository) GetLeastOccupiedCell(ctx context.Context, pvzID, productType string) (*model.StorageCell, error) {
+	query, args, err := r.psql.
+		Select(prefixColumns("c", storageCellColumns)...).
+		From(storageCellTableName+" c").
+		Where(sq.Eq{"c.pvz_id": pvzID}).
+		Where(sq.Or{sq.Eq{"c.product_type": productType}, sq.Eq{"c.product_type": nil}}).
+		Where(storageCellOccupiedExpr+" < c.capacity").
+		OrderBy("c.product_type IS NULL", storageCellOccupiedExpr+"::float / c.capacity", "c.code").
+		Limit(1).
+		ToSql()
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to build sql query: %w", err)
+	}
+
+	cell, err := scanStorageCell(getQuerier(ctx, r.db).QueryRowContext(ctx, query, args...))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, model.ErrStorageCellNotFound
+		}
+		return nil, fmt.Errorf("failed to get storage cell: %w", err)
+	}
+
+	return cell, nil
+}
+
+// scanStorageCell scans the storage cell columns followed by extra.
+func scanStorageCell(row rowScanner, extra ...any) (*model.StorageCell, error) {
+	var (
+		cell        model.StorageCell
+		productType sql.NullString
+	)
+
+	dest := []any{&cell.ID, &cell.PVZID, &cell.Code, &cell.Capacity, &productType, &cell.CreatedAt}
+
+	if err := row.Scan(append(dest, extra...)...); err != nil {
+		return nil, err
+	}
+
+	cell.ProductType = productType.String
+
+	return &cell, nil
+}
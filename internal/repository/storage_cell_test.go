@@ -0,0 +1,324 @@
+package repository_test
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/kirillidk/pvz-service/internal/dto"
+	"github.com/kirillidk/pvz-service/internal/model"
+	"github.com/kirillidk/pvz-service/internal/repository"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+)
+
+var storageCellRowColumns = []string{"id", "pvz_id", "code", "capacity", "product_type", "created_at"}
+
+func TestStorageCellRepository_CreateStorageCell(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	storageCellRepo := repository.NewStorageCellRepository(db)
+	ctx := context.Background()
+	testTime := time.Now()
+
+	pvzID := "b0eebc99-9c0b-4ef8-bb6d-6bb9bd380a11"
+	cellID := "e1eebc99-9c0b-4ef8-bb6d-6bb9bd380a11"
+	insertQuery := regexp.QuoteMeta(`INSERT INTO storage_cells (pvz_id,code,capacity,product_type,created_at) VALUES ($1,$2,$3,$4,$5) ` +
+		`RETURNING id, pvz_id, code, capacity, product_type, created_at`)
+
+	t.Run("Success", func(t *testing.T) {
+		rows := sqlmock.NewRows(storageCellRowColumns).
+			AddRow(cellID, pvzID, "A-01", 20, "обувь", testTime)
+
+		mock.ExpectQuery(insertQuery).
+			WithArgs(pvzID, "A-01", 20, "обувь", sqlmock.AnyArg()).
+			WillReturnRows(rows)
+
+		cell, err := storageCellRepo.CreateStorageCell(ctx, pvzID, dto.StorageCellCreateRequest{
+			Code:        "A-01",
+			Capacity:    20,
+			ProductType: "обувь",
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, &model.StorageCell{
+			ID:          cellID,
+			PVZID:       pvzID,
+			Code:        "A-01",
+			Capacity:    20,
+			ProductType: "обувь",
+			CreatedAt:   testTime,
+		}, cell)
+	})
+
+	t.Run("Duplicate Code", func(t *testing.T) {
+		mock.ExpectQuery(insertQuery).
+			WillReturnError(&pq.Error{Code: "23505"})
+
+		cell, err := storageCellRepo.CreateStorageCell(ctx, pvzID, dto.StorageCellCreateRequest{Code: "A-01", Capacity: 20})
+
+		assert.ErrorIs(t, err, model.ErrStorageCellAlreadyExists)
+		assert.Nil(t, cell)
+	})
+
+	t.Run("Unknown Product Type", func(t *testing.T) {
+		mock.ExpectQuery(insertQuery).
+			WillReturnError(&pq.Error{Code: "23503"})
+
+		cell, err := storageCellRepo.CreateStorageCell(ctx, pvzID, dto.StorageCellCreateRequest{
+			Code:        "A-02",
+			Capacity:    20,
+			ProductType: "мебель",
+		})
+
+		assert.ErrorIs(t, err, model.ErrProductTypeNotFound)
+		assert.Nil(t, cell)
+	})
+
+	t.Run("Unknown PVZ", func(t *testing.T) {
+		mock.ExpectQuery(insertQuery).
+			WithArgs(pvzID, "A-03", 20, nil, sqlmock.AnyArg()).
+			WillReturnError(&pq.Error{Code: "23503"})
+
+		cell, err := storageCellRepo.CreateStorageCell(ctx, pvzID, dto.StorageCellCreateRequest{Code: "A-03", Capacity: 20})
+
+		assert.ErrorIs(t, err, model.ErrPVZNotFound)
+		assert.Nil(t, cell)
+	})
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func TestStorageCellRepository_GetStorageCellForUpdate(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	storageCellRepo := repository.NewStorageCellRepository(db)
+	ctx := context.Background()
+	testTime := time.Now()
+
+	cellID := "e1eebc99-9c0b-4ef8-bb6d-6bb9bd380a11"
+	selectQuery := regexp.QuoteMeta(`SELECT id, pvz_id, code, capacity, product_type, created_at FROM storage_cells WHERE id = $1 FOR UPDATE`)
+
+	t.Run("Success", func(t *testing.T) {
+		rows := sqlmock.NewRows(storageCellRowColumns).
+			AddRow(cellID, "b0eebc99-9c0b-4ef8-bb6d-6bb9bd380a11", "A-01", 20, nil, testTime)
+
+		mock.ExpectQuery(selectQuery).
+			WithArgs(cellID).
+			WillReturnRows(rows)
+
+		cell, err := storageCellRepo.GetStorageCellForUpdate(ctx, cellID)
+
+		assert.NoError(t, err)
+		assert.Equal(t, &model.StorageCell{
+			ID:        cellID,
+			PVZID:     "b0eebc99-9c0b-4ef8-bb6d-6bb9bd380a11",
+			Code:      "A-01",
+			Capacity:  20,
+			CreatedAt: testTime,
+		}, cell)
+	})
+
+	t.Run("Not Found", func(t *testing.T) {
+		mock.ExpectQuery(selectQuery).
+			WithArgs(cellID).
+			WillReturnRows(sqlmock.NewRows(storageCellRowColumns))
+
+		cell, err := storageCellRepo.GetStorageCellForUpdate(ctx, cellID)
+
+		assert.ErrorIs(t, err, model.ErrStorageCellNotFound)
+		assert.Nil(t, cell)
+	})
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func TestStorageCellRepository_DeleteStorageCell(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	storageCellRepo := repository.NewStorageCellRepository(db)
+	ctx := context.Background()
+
+	cellID := "e1eebc99-9c0b-4ef8-bb6d-6bb9bd380a11"
+	deleteQuery := regexp.QuoteMeta(`DELETE FROM storage_cells WHERE id = $1`)
+
+	t.Run("Success", func(t *testing.T) {
+		mock.ExpectExec(deleteQuery).
+			WithArgs(cellID).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+
+		err := storageCellRepo.DeleteStorageCell(ctx, cellID)
+
+		assert.NoError(t, err)
+	})
+
+	t.Run("Not Found", func(t *testing.T) {
+		mock.ExpectExec(deleteQuery).
+			WithArgs(cellID).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+
+		err := storageCellRepo.DeleteStorageCell(ctx, cellID)
+
+		assert.ErrorIs(t, err, model.ErrStorageCellNotFound)
+	})
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func TestStorageCellRepository_CountProductsInCell(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	storageCellRepo := repository.NewStorageCellRepository(db)
+	ctx := context.Background()
+
+	cellID := "e1eebc99-9c0b-4ef8-bb6d-6bb9bd380a11"
+	countQuery := regexp.QuoteMeta(`SELECT COUNT(*) FROM products WHERE cell_id = $1 AND status IN ($2,$3)`)
+
+	t.Run("Success", func(t *testing.T) {
+		mock.ExpectQuery(countQuery).
+			WithArgs(cellID, "received", "stored").
+			WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(7))
+
+		count, err := storageCellRepo.CountProductsInCell(ctx, cellID)
+
+		assert.NoError(t, err)
+		assert.Equal(t, 7, count)
+	})
+
+	t.Run("DB Error", func(t *testing.T) {
+		mock.ExpectQuery(countQuery).
+			WillReturnError(errors.New("db error"))
+
+		count, err := storageCellRepo.CountProductsInCell(ctx, cellID)
+
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "failed to count products in storage cell: db error")
+		assert.Zero(t, count)
+	})
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func TestStorageCellRepository_GetStorageCellOccupancy(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	storageCellRepo := repository.NewStorageCellRepository(db)
+	ctx := context.Background()
+	testTime := time.Now()
+
+	pvzID := "b0eebc99-9c0b-4ef8-bb6d-6bb9bd380a11"
+	selectQuery := regexp.QuoteMeta(`SELECT c.id, c.pvz_id, c.code, c.capacity, c.product_type, c.created_at, ` +
+		`(SELECT COUNT(*) FROM products pr WHERE pr.cell_id = c.id AND pr.status IN ('received', 'stored')) ` +
+		`FROM storage_cells c WHERE c.pvz_id = $1 ORDER BY c.code`)
+
+	rows := sqlmock.NewRows(append(storageCellRowColumns, "occupied")).
+		AddRow("e1eebc99-9c0b-4ef8-bb6d-6bb9bd380a11", pvzID, "A-01", 20, "обувь", testTime, 5).
+		AddRow("e2eebc99-9c0b-4ef8-bb6d-6bb9bd380a11", pvzID, "A-02", 10, nil, testTime, 10)
+
+	mock.ExpectQuery(selectQuery).
+		WithArgs(pvzID).
+		WillReturnRows(rows)
+
+	occupancy, err := storageCellRepo.GetStorageCellOccupancy(ctx, pvzID)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []model.StorageCellOccupancy{
+		{
+			StorageCell: model.StorageCell{
+				ID: "e1eebc99-9c0b-4ef8-bb6d-6bb9bd380a11", PVZID: pvzID, Code: "A-01", Capacity: 20, ProductType: "обувь", CreatedAt: testTime,
+			},
+			Occupied: 5,
+			Free:     15,
+		},
+		{
+			StorageCell: model.StorageCell{
+				ID: "e2eebc99-9c0b-4ef8-bb6d-6bb9bd380a11", PVZID: pvzID, Code: "A-02", Capacity: 10, CreatedAt: testTime,
+			},
+			Occupied: 10,
+			Free:     0,
+		},
+	}, occupancy)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func TestStorageCellRepository_GetLeastOccupiedCell(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	storageCellRepo := repository.NewStorageCellRepository(db)
+	ctx := context.Background()
+	testTime := time.Now()
+
+	pvzID := "b0eebc99-9c0b-4ef8-bb6d-6bb9bd380a11"
+	occupiedExpr := `(SELECT COUNT(*) FROM products pr WHERE pr.cell_id = c.id AND pr.status IN ('received', 'stored'))`
+	selectQuery := regexp.QuoteMeta(`SELECT c.id, c.pvz_id, c.code, c.capacity, c.product_type, c.created_at ` +
+		`FROM storage_cells c WHERE c.pvz_id = $1 AND (c.product_type = $2 OR c.product_type IS NULL) ` +
+		`AND ` + occupiedExpr + ` < c.capacity ` +
+		`ORDER BY c.product_type IS NULL, ` + occupiedExpr + `::float / c.capacity, c.code LIMIT 1`)
+
+	t.Run("Success", func(t *testing.T) {
+		rows := sqlmock.NewRows(storageCellRowColumns).
+			AddRow("e1eebc99-9c0b-4ef8-bb6d-6bb9bd380a11", pvzID, "A-01", 20, "обувь", testTime)
+
+		mock.ExpectQuery(selectQuery).
+			WithArgs(pvzID, "обувь").
+			WillReturnRows(rows)
+
+		cell, err := storageCellRepo.GetLeastOccupiedCell(ctx, pvzID, "обувь")
+
+		assert.NoError(t, err)
+		assert.Equal(t, "A-01", cell.Code)
+	})
+
+	t.Run("No Free Cell", func(t *testing.T) {
+		mock.ExpectQuery(selectQuery).
+			WithArgs(pvzID, "обувь").
+			WillReturnRows(sqlmock.NewRows(storageCellRowColumns))
+
+		cell, err := storageCellRepo.GetLeastOccupiedCell(ctx, pvzID, "обувь")
+
+		assert.ErrorIs(t, err, model.ErrStorageCellNotFound)
+		assert.Nil(t, cell)
+	})
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
@@ -0,0 +1,63 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+)
+
+type TransactorInterface interface {
+	WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error
+}
+
+type txContextKey struct{}
+
+type Transactor struct {
+	db *sql.DB
+}
+
+func NewTransactor(db *sql.DB) *Transactor {
+	return &Transactor{
+		db: db,
+	}
+}
+
+// WithinTransaction runs fn inside a database transaction carried by the
+// context passed to fn. Repositories pick the transaction up from the context,
+// so every call made by fn with that context commits or rolls back together.
+// Nested calls join the outer transaction.
+func (t *Transactor) WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	if _, ok := ctx.Value(txContextKey{}).(*sql.Tx); ok {
+		return fn(ctx)
+	}
+
+	tx, err := t.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+
+	if err := fn(context.WithValue(ctx, txContextKey{}, tx)); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+type querier interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+}
+
+// getQuerier returns the transaction stored in ctx, falling back to db.
+func getQuerier(ctx context.Context, db *sql.DB) querier {
+	if tx, ok := ctx.Value(txContextKey{}).(*sql.Tx); ok {
+		return tx
+	}
+	return db
+}
@@ -0,0 +1,54 @@
+package repository_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/kirillidk/pvz-service/internal/repository"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTransactor_WithinTransaction(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	transactor := repository.NewTransactor(db)
+	productRepo := repository.NewProductRepository(db)
+	ctx := context.Background()
+
+	t.Run("Commit", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectExec(`DELETE FROM products`).WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+
+		err := transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+			return transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+				return productRepo.DeleteProduct(ctx, "d0eebc99-9c0b-4ef8-bb6d-6bb9bd380a11")
+			})
+		})
+
+		assert.NoError(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Rollback", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectExec(`DELETE FROM products`).WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectRollback()
+
+		err := transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+			if err := productRepo.DeleteProduct(ctx, "d0eebc99-9c0b-4ef8-bb6d-6bb9bd380a11"); err != nil {
+				return err
+			}
+			return errors.New("audit error")
+		})
+
+		assert.EqualError(t, err, "audit error")
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
@@ -0,0 +1,413 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/kirillidk/pvz-service/internal/model"
+)
+
+const (
+	transferTableName        = "transfers"
+	transferProductTableName = "transfer_products"
+)
+
+// productArrivalJoin joins, as arrival, the last received transfer of the
+// product pr: the reception it joined at its current PVZ and the time it
+// arrived there. Both are NULL for products that never moved.
+const productArrivalJoin = "LATERAL (SELECT t.reception_id, t.received_at FROM " + transferTableName + " t " +
+	"JOIN " + transferProductTableName + " tp ON tp.transfer_id = t.id " +
+	"WHERE tp.product_id = pr.id AND t.status = 'received' ORDER BY t.received_at DESC LIMIT 1) arrival ON TRUE"
+
+// productReceptionIDExpr is the reception the product pr is kept under at
+// its current PVZ. The query must join productArrivalJoin.
+const productReceptionIDExpr = "COALESCE(arrival.reception_id, pr.reception_id)"
+
+// productReceptionJoin joins, as r, the reception of productReceptionIDExpr.
+const productReceptionJoin = receptionTableName + " r ON r.id = " + productReceptionIDExpr
+
+var transferColumns = []string{
+	"id", "source_pvz_id", "destination_pvz_id", "status", "reception_id", "created_at", "shipped_at", "received_at",
+}
+
+type TransferRepositoryInterface interface {
+	CreateTransfer(ctx context.Context, transfer model.Transfer) (*model.Transfer, error)
+	GetTransfer(ctx context.Context, transferID string) (*model.Transfer, error)
+	GetTransferForUpdate(ctx context.Context, transferID string) (*model.Transfer, error)
+	GetTransfersByPVZID(ctx context.Context, pvzID string) ([]model.Transfer, error)
+	GetTransfersByProductID(ctx context.Context, productID string) ([]model.Transfer, error)
+	MarkTransferShipped(ctx context.Context, transferID string, shippedAt time.Time) error
+	MarkTransferReceived(ctx context.Context, transferID, receptionID string, receivedAt time.Time) error
+}
+
+type TransferRepository struct {
+	db   *sql.DB
+	psql sq.StatementBuilderType
+}
+
+func NewTransferRepository(db *sql.DB) *TransferRepository {
+	return &TransferRepository{
+		db:   db,
+		psql: sq.StatementBuilder.PlaceholderFormat(sq.Dollar),
+	}
+}
+
+// CreateTransfer inserts the transfer, records its products and reserves
+// them for it. A product already reserved for another transfer fails the
+// whole call with model.ErrProductInTransfer, so it should be run within a
+// transaction.
+func (r *TransferRepository) CreateTransfer(ctx context.Context, transfer model.Transfer) (*model.Transfer, error) {
+	query, args, err := r.psql.
+		Insert(transferTableName).
+		Columns(transferColumns[1:]...).
+		Values(transfer.SourcePVZID, transfer.DestinationPVZID, model.TransferStatusCreated, nil, transfer.CreatedAt, nil, nil).
+		Suffix("RETURNING " + columnList(transferColumns)).
+		ToSql()
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to build sql query: %w", err)
+	}
+
+	querier := getQuerier(ctx, r.db)
+
+	created, err := scanTransfer(querier.QueryRowContext(ctx, query, args...))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create transfer: %w", err)
+	}
+
+	insertBuilder := r.psql.
+		Insert(transferProductTableName).
+		Columns("transfer_id", "product_id")
+
+	for _, productID := range transfer.ProductIDs {
+		insertBuilder = insertBuilder.Values(created.ID, productID)
+	}
+
+	query, args, err = insertBuilder.ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build sql query: %w", err)
+	}
+
+	if _, err := querier.ExecContext(ctx, query, args...); err != nil {
+		return nil, fmt.Errorf("failed to add products to transfer: %w", err)
+	}
+
+	query, args, err = r.psql.
+		Update(productTableName).
+		Set("transfer_id", created.ID).
+		Where(sq.Eq{"id": transfer.ProductIDs}).
+		Where(sq.Eq{"transfer_id": nil}).
+		ToSql()
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to build sql query: %w", err)
+	}
+
+	result, err := querier.ExecContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to attach products to transfer: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected != int64(len(transfer.ProductIDs)) {
+		return nil, model.ErrProductInTransfer
+	}
+
+	created.ProductIDs = transfer.ProductIDs
+
+	return created, nil
+}
+
+func (r *TransferRepository) GetTransfer(ctx context.Context, transferID string) (*model.Transfer, error) {
+	return r.getTransfer(ctx, transferID, false)
+}
+
+// GetTransferForUpdate returns the transfer with its product IDs and locks
+// the transfer row until the end of the surrounding transaction.
+func (r *TransferRepository) GetTransferForUpdate(ctx context.Context, transferID string) (*model.Transfer, error) {
+	return r.getTransfer(ctx, transferID, true)
+}
+
+func (r *TransferRepository) getTransfer(ctx context.Context, transferID string, forUpdate bool) (*model.Transfer, error) {
+	queryBuilder := r.psql.
+		Select(transferColumns...).
+		From(transferTableName).
+		Where(sq.Eq{"id": transferID})
+
+	if forUpdate {
+		queryBuilder = queryBuilder.Suffix("FOR UPDATE")
+	}
+
+	query, args, err := queryBuilder.ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build sql query: %w", err)
+	}
+
+	transfer, err := scanTransfer(getQuerier(ctx, r.db).QueryRowContext(ctx, query, args...))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, model.ErrTransferNotFound
+		}
+		return nil, fmt.Errorf("failed to get transfer: %w", err)
+	}
+
+	if err := r.loadTransferProducts(ctx, []*model.Transfer{transfer}); err != nil {
+		return nil, err
+	}
+
+	return transfer, nil
+}
+
+// GetTransfersByPVZID returns the transfers leaving or arriving at the PVZ,
+// the newest first.
+func (r *TransferRepository) GetTransfersByPVZID(ctx context.Context, pvzID string) ([]model.Transfer, error) {
+	query, args, err := r.psql.
+		Select(transferColumns...).
+		From(transferTableName).
+		Where(sq.Or{sq.Eq{"source_pvz_id": pvzID}, sq.Eq{"destination_pvz_id": pvzID}}).
+		OrderBy("created_at DESC", "id").
+		ToSql()
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to build sql query: %w", err)
+	}
+
+	return r.queryTransfers(ctx, query, args)
+}
+
+// GetTransfersByProductID returns the transfers the product took part in,
+// the oldest first.
+func (r *TransferRepository) GetTransfersByProductID(ctx context.Context, productID string) ([]model.Transfer, error) {
+	query, args, err := r.psql.
+		Select(prefixColumns("t", transferColumns)...).
+		From(transferTableName+" t").
+		Join(transferProductTableName+" tp ON tp.transfer_id = t.id").
+		Where(sq.Eq{"tp.product_id": productID}).
+		OrderBy("t.created_at ASC", "t.id").
+		ToSql()
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to build sql query: %w", err)
+	}
+
+	return r.queryTransfers(ctx, query, args)
+}
+
+// MarkTransferShipped sends the transfer and its products on their way.
+// The products leave their storage cells and their return tasks are dropped.
+func (r *TransferRepository) MarkTransferShipped(ctx context.Context, transferID string, shippedAt time.Time) error {
+	query, args, err := r.psql.
+		Update(transferTableName).
+		Set("status", model.TransferStatusInTransit).
+		Set("shipped_at", shippedAt).
+		Where(sq.Eq{"id": transferID}).
+		ToSql()
+
+	if err != nil {
+		return fmt.Errorf("failed to build sql query: %w", err)
+	}
+
+	if err := r.execTransferUpdate(ctx, query, args); err != nil {
+		return err
+	}
+
+	query, args, err = r.psql.
+		Update(productTableName).
+		Set("status", model.ProductStatusInTransit).
+		Set("cell_id", nil).
+		Where(sq.Eq{"transfer_id": transferID}).
+		ToSql()
+
+	if err != nil {
+		return fmt.Errorf("failed to build sql query: %w", err)
+	}
+
+	if _, err := getQuerier(ctx, r.db).ExecContext(ctx, query, args...); err != nil {
+		return fmt.Errorf("failed to update transfer products: %w", err)
+	}
+
+	// The storage period starts over at the destination, so the return
+	// tasks of the products at the source PVZ are dropped.
+	query, args, err = r.psql.
+		Delete(returnTaskTableName).
+		Where(sq.Expr("product_id IN (SELECT id FROM "+productTableName+" WHERE transfer_id = ?)", transferID)).
+		ToSql()
+
+	if err != nil {
+		return fmt.Errorf("failed to build sql query: %w", err)
+	}
+
+	if _, err := getQuerier(ctx, r.db).ExecContext(ctx, query, args...); err != nil {
+		return fmt.Errorf("failed to delete return tasks of transfer products: %w", err)
+	}
+
+	return nil
+}
+
+// MarkTransferReceived completes the transfer: it is linked to the
+// reception of the destination PVZ and its products are released from it.
+// The products keep the reception they were first received in, so the
+// history of the source PVZ does not change; their current PVZ is resolved
+// through productArrivalJoin.
+func (r *TransferRepository) MarkTransferReceived(ctx context.Context, transferID, receptionID string, receivedAt time.Time) error {
+	query, args, err := r.psql.
+		Update(transferTableName).
+		Set("status", model.TransferStatusReceived).
+		Set("reception_id", receptionID).
+		Set("received_at", receivedAt).
+		Where(sq.Eq{"id": transferID}).
+		ToSql()
+
+	if err != nil {
+		return fmt.Errorf("failed to build sql query: %w", err)
+	}
+
+	if err := r.execTransferUpdate(ctx, query, args); err != nil {
+		return err
+	}
+
+	query, args, err = r.psql.
+		Update(productTableName).
+		Set("status", model.ProductStatusReceived).
+		Set("transfer_id", nil).
+		Where(sq.Eq{"transfer_id": transferID}).
+		ToSql()
+
+	if err != nil {
+		return fmt.Errorf("failed to build sql query: %w", err)
+	}
+
+	if _, err := getQuerier(ctx, r.db).ExecContext(ctx, query, args...); err != nil {
+		return fmt.Errorf("failed to update transfer products: %w", err)
+	}
+
+	return nil
+}
+
+func (r *TransferRepository) queryTransfers(ctx context.Context, query string, args []any) ([]model.Transfer, error) {
+	rows, err := getQuerier(ctx, r.db).QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query transfers: %w", err)
+	}
+	defer rows.Close()
+
+	var transfers []*model.Transfer
+	for rows.Next() {
+		transfer, err := scanTransfer(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan transfer row: %w", err)
+		}
+		transfers = append(transfers, transfer)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating transfer rows: %w", err)
+	}
+
+	if err := r.loadTransferProducts(ctx, transfers); err != nil {
+		return nil, err
+	}
+
+	result := make([]model.Transfer, 0, len(transfers))
+	for _, transfer := range transfers {
+		result = append(result, *transfer)
+	}
+
+	return result, nil
+}
+
+// loadTransferProducts fills in the product IDs of the transfers in the
+// order the products were received.
+func (r *TransferRepository) loadTransferProducts(ctx context.Context, transfers []*model.Transfer) error {
+	if len(transfers) == 0 {
+		return nil
+	}
+
+	byID := make(map[string]*model.Transfer, len(transfers))
+	transferIDs := make([]string, 0, len(transfers))
+	for _, transfer := range transfers {
+		transfer.ProductIDs = []string{}
+		byID[transfer.ID] = transfer
+		transferIDs = append(transferIDs, transfer.ID)
+	}
+
+	query, args, err := r.psql.
+		Select("tp.transfer_id", "tp.product_id").
+		From(transferProductTableName + " tp").
+		Join(productTableName + " pr ON pr.id = tp.product_id").
+		Where(sq.Eq{"tp.transfer_id": transferIDs}).
+		OrderBy("pr.seq").
+		ToSql()
+
+	if err != nil {
+		return fmt.Errorf("failed to build sql query: %w", err)
+	}
+
+	rows, err := getQuerier(ctx, r.db).QueryContext(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("failed to query transfer products: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var transferID, productID string
+		if err := rows.Scan(&transferID, &productID); err != nil {
+			return fmt.Errorf("failed to scan transfer product row: %w", err)
+		}
+		byID[transferID].ProductIDs = append(byID[transferID].ProductIDs, productID)
+	}
+
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("error iterating transfer product rows: %w", err)
+	}
+
+	return nil
+}
+
+func (r *TransferRepository) execTransferUpdate(ctx context.Context, query string, args []any) error {
+	result, err := getQuerier(ctx, r.db).ExecContext(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("failed to update transfer: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return model.ErrTransferNotFound
+	}
+
+	return nil
+}
+
+func scanTransfer(row rowScanner) (*model.Transfer, error) {
+	var (
+		transfer    model.Transfer
+		receptionID sql.NullString
+		shippedAt   sql.NullTime
+		receivedAt  sql.NullTime
+	)
+
+	err := row.Scan(
+		&transfer.ID, &transfer.SourcePVZID, &transfer.DestinationPVZID, &transfer.Status,
+		&receptionID, &transfer.CreatedAt, &shippedAt, &receivedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	transfer.ReceptionID = receptionID.String
+	transfer.ShippedAt = nullTimePtr(shippedAt)
+	transfer.ReceivedAt = nullTimePtr(receivedAt)
+
+	return &transfer, nil
+}
@@ -0,0 +1,309 @@
+package repository_test
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/kirillidk/pvz-service/internal/model"
+	"github.com/kirillidk/pvz-service/internal/repository"
+	"github.com/stretchr/testify/assert"
+)
+
+var transferRowColumns = []string{
+	"id", "source_pvz_id", "destination_pvz_id", "status", "reception_id", "created_at", "shipped_at", "received_at",
+}
+
+func TestTransferRepository_CreateTransfer(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	transferRepo := repository.NewTransferRepository(db)
+	ctx := context.Background()
+	now := time.Now()
+
+	transferID := "f0eebc99-9c0b-4ef8-bb6d-6bb9bd380a11"
+	transfer := model.Transfer{
+		SourcePVZID:      "b0eebc99-9c0b-4ef8-bb6d-6bb9bd380a11",
+		DestinationPVZID: "b1eebc99-9c0b-4ef8-bb6d-6bb9bd380a11",
+		ProductIDs:       []string{"d0eebc99-9c0b-4ef8-bb6d-6bb9bd380a11", "d1eebc99-9c0b-4ef8-bb6d-6bb9bd380a11"},
+		CreatedAt:        now,
+	}
+
+	insertQuery := regexp.QuoteMeta(`INSERT INTO transfers (source_pvz_id,destination_pvz_id,status,reception_id,created_at,shipped_at,received_at) ` +
+		`VALUES ($1,$2,$3,$4,$5,$6,$7) ` +
+		`RETURNING id, source_pvz_id, destination_pvz_id, status, reception_id, created_at, shipped_at, received_at`)
+	insertProductsQuery := regexp.QuoteMeta(`INSERT INTO transfer_products (transfer_id,product_id) VALUES ($1,$2),($3,$4)`)
+	attachQuery := regexp.QuoteMeta(`UPDATE products SET transfer_id = $1 WHERE id IN ($2,$3) AND transfer_id IS NULL`)
+
+	createdRow := func() *sqlmock.Rows {
+		return sqlmock.NewRows(transferRowColumns).
+			AddRow(transferID, transfer.SourcePVZID, transfer.DestinationPVZID, "created", nil, now, nil, nil)
+	}
+
+	tests := []struct {
+		name          string
+		mockBehavior  func()
+		expectedValue *model.Transfer
+		expectedError error
+	}{
+		{
+			name: "Success",
+			mockBehavior: func() {
+				mock.ExpectQuery(insertQuery).
+					WithArgs(transfer.SourcePVZID, transfer.DestinationPVZID, model.TransferStatusCreated, nil, now, nil, nil).
+					WillReturnRows(createdRow())
+				mock.ExpectExec(insertProductsQuery).
+					WithArgs(transferID, transfer.ProductIDs[0], transferID, transfer.ProductIDs[1]).
+					WillReturnResult(sqlmock.NewResult(0, 2))
+				mock.ExpectExec(attachQuery).
+					WithArgs(transferID, transfer.ProductIDs[0], transfer.ProductIDs[1]).
+					WillReturnResult(sqlmock.NewResult(0, 2))
+			},
+			expectedValue: &model.Transfer{
+				ID:               transferID,
+				SourcePVZID:      transfer.SourcePVZID,
+				DestinationPVZID: transfer.DestinationPVZID,
+				Status:           model.TransferStatusCreated,
+				ProductIDs:       transfer.ProductIDs,
+				CreatedAt:        now,
+			},
+		},
+		{
+			name: "Product Already In Transfer",
+			mockBehavior: func() {
+				mock.ExpectQuery(insertQuery).
+					WillReturnRows(createdRow())
+				mock.ExpectExec(insertProductsQuery).
+					WillReturnResult(sqlmock.NewResult(0, 2))
+				mock.ExpectExec(attachQuery).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+			expectedError: model.ErrProductInTransfer,
+		},
+		{
+			name: "Insert Error",
+			mockBehavior: func() {
+				mock.ExpectQuery(insertQuery).
+					WillReturnError(errors.New("db error"))
+			},
+			expectedError: errors.New("failed to create transfer: db error"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockBehavior()
+
+			created, err := transferRepo.CreateTransfer(ctx, transfer)
+
+			if tt.expectedError != nil {
+				assert.Error(t, err)
+				assert.Contains(t, err.Error(), tt.expectedError.Error())
+				assert.Nil(t, created)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expectedValue, created)
+			}
+
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestTransferRepository_GetTransferForUpdate(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	transferRepo := repository.NewTransferRepository(db)
+	ctx := context.Background()
+	now := time.Now()
+
+	transferID := "f0eebc99-9c0b-4ef8-bb6d-6bb9bd380a11"
+	selectQuery := regexp.QuoteMeta(`SELECT id, source_pvz_id, destination_pvz_id, status, reception_id, created_at, shipped_at, received_at ` +
+		`FROM transfers WHERE id = $1 FOR UPDATE`)
+	productsQuery := regexp.QuoteMeta(`SELECT tp.transfer_id, tp.product_id FROM transfer_products tp ` +
+		`JOIN products pr ON pr.id = tp.product_id WHERE tp.transfer_id IN ($1) ORDER BY pr.seq`)
+
+	t.Run("Success", func(t *testing.T) {
+		mock.ExpectQuery(selectQuery).
+			WithArgs(transferID).
+			WillReturnRows(sqlmock.NewRows(transferRowColumns).
+				AddRow(transferID, "b0eebc99-9c0b-4ef8-bb6d-6bb9bd380a11", "b1eebc99-9c0b-4ef8-bb6d-6bb9bd380a11", "in_transit", nil, now, now, nil))
+		mock.ExpectQuery(productsQuery).
+			WithArgs(transferID).
+			WillReturnRows(sqlmock.NewRows([]string{"transfer_id", "product_id"}).
+				AddRow(transferID, "d0eebc99-9c0b-4ef8-bb6d-6bb9bd380a11"))
+
+		transfer, err := transferRepo.GetTransferForUpdate(ctx, transferID)
+
+		assert.NoError(t, err)
+		assert.Equal(t, &model.Transfer{
+			ID:               transferID,
+			SourcePVZID:      "b0eebc99-9c0b-4ef8-bb6d-6bb9bd380a11",
+			DestinationPVZID: "b1eebc99-9c0b-4ef8-bb6d-6bb9bd380a11",
+			Status:           model.TransferStatusInTransit,
+			ProductIDs:       []string{"d0eebc99-9c0b-4ef8-bb6d-6bb9bd380a11"},
+			CreatedAt:        now,
+			ShippedAt:        &now,
+		}, transfer)
+	})
+
+	t.Run("Not Found", func(t *testing.T) {
+		mock.ExpectQuery(selectQuery).
+			WithArgs(transferID).
+			WillReturnRows(sqlmock.NewRows(transferRowColumns))
+
+		transfer, err := transferRepo.GetTransferForUpdate(ctx, transferID)
+
+		assert.ErrorIs(t, err, model.ErrTransferNotFound)
+		assert.Nil(t, transfer)
+	})
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func TestTransferRepository_GetTransfersByPVZID(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	transferRepo := repository.NewTransferRepository(db)
+	ctx := context.Background()
+	now := time.Now()
+
+	pvzID := "b0eebc99-9c0b-4ef8-bb6d-6bb9bd380a11"
+	selectQuery := regexp.QuoteMeta(`SELECT id, source_pvz_id, destination_pvz_id, status, reception_id, created_at, shipped_at, received_at ` +
+		`FROM transfers WHERE (source_pvz_id = $1 OR destination_pvz_id = $2) ORDER BY created_at DESC, id`)
+	productsQuery := regexp.QuoteMeta(`SELECT tp.transfer_id, tp.product_id FROM transfer_products tp ` +
+		`JOIN products pr ON pr.id = tp.product_id WHERE tp.transfer_id IN ($1,$2) ORDER BY pr.seq`)
+
+	mock.ExpectQuery(selectQuery).
+		WithArgs(pvzID, pvzID).
+		WillReturnRows(sqlmock.NewRows(transferRowColumns).
+			AddRow("f1eebc99-9c0b-4ef8-bb6d-6bb9bd380a11", "b1eebc99-9c0b-4ef8-bb6d-6bb9bd380a11", pvzID, "received",
+				"c0eebc99-9c0b-4ef8-bb6d-6bb9bd380a11", now, now, now).
+			AddRow("f0eebc99-9c0b-4ef8-bb6d-6bb9bd380a11", pvzID, "b1eebc99-9c0b-4ef8-bb6d-6bb9bd380a11", "created", nil, now, nil, nil))
+	mock.ExpectQuery(productsQuery).
+		WithArgs("f1eebc99-9c0b-4ef8-bb6d-6bb9bd380a11", "f0eebc99-9c0b-4ef8-bb6d-6bb9bd380a11").
+		WillReturnRows(sqlmock.NewRows([]string{"transfer_id", "product_id"}).
+			AddRow("f0eebc99-9c0b-4ef8-bb6d-6bb9bd380a11", "d0eebc99-9c0b-4ef8-bb6d-6bb9bd380a11").
+			AddRow("f1eebc99-9c0b-4ef8-bb6d-6bb9bd380a11", "d1eebc99-9c0b-4ef8-bb6d-6bb9bd380a11").
+			AddRow("f0eebc99-9c0b-4ef8-bb6d-6bb9bd380a11", "d2eebc99-9c0b-4ef8-bb6d-6bb9bd380a11"))
+
+	transfers, err := transferRepo.GetTransfersByPVZID(ctx, pvzID)
+
+	assert.NoError(t, err)
+	assert.Len(t, transfers, 2)
+	assert.Equal(t, "c0eebc99-9c0b-4ef8-bb6d-6bb9bd380a11", transfers[0].ReceptionID)
+	assert.Equal(t, []string{"d1eebc99-9c0b-4ef8-bb6d-6bb9bd380a11"}, transfers[0].ProductIDs)
+	assert.Equal(t, []string{"d0eebc99-9c0b-4ef8-bb6d-6bb9bd380a11", "d2eebc99-9c0b-4ef8-bb6d-6bb9bd380a11"}, transfers[1].ProductIDs)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func TestTransferRepository_MarkTransferShipped(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	transferRepo := repository.NewTransferRepository(db)
+	ctx := context.Background()
+	now := time.Now()
+
+	transferID := "f0eebc99-9c0b-4ef8-bb6d-6bb9bd380a11"
+	updateQuery := regexp.QuoteMeta(`UPDATE transfers SET status = $1, shipped_at = $2 WHERE id = $3`)
+	productsQuery := regexp.QuoteMeta(`UPDATE products SET status = $1, cell_id = $2 WHERE transfer_id = $3`)
+	returnTasksQuery := regexp.QuoteMeta(`DELETE FROM return_tasks WHERE product_id IN (SELECT id FROM products WHERE transfer_id = $1)`)
+
+	t.Run("Success", func(t *testing.T) {
+		mock.ExpectExec(updateQuery).
+			WithArgs(model.TransferStatusInTransit, now, transferID).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec(productsQuery).
+			WithArgs(model.ProductStatusInTransit, nil, transferID).
+			WillReturnResult(sqlmock.NewResult(0, 2))
+		mock.ExpectExec(returnTasksQuery).
+			WithArgs(transferID).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+
+		err := transferRepo.MarkTransferShipped(ctx, transferID, now)
+
+		assert.NoError(t, err)
+	})
+
+	t.Run("Not Found", func(t *testing.T) {
+		mock.ExpectExec(updateQuery).
+			WithArgs(model.TransferStatusInTransit, now, transferID).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+
+		err := transferRepo.MarkTransferShipped(ctx, transferID, now)
+
+		assert.ErrorIs(t, err, model.ErrTransferNotFound)
+	})
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func TestTransferRepository_MarkTransferReceived(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	transferRepo := repository.NewTransferRepository(db)
+	ctx := context.Background()
+	now := time.Now()
+
+	transferID := "f0eebc99-9c0b-4ef8-bb6d-6bb9bd380a11"
+	receptionID := "c0eebc99-9c0b-4ef8-bb6d-6bb9bd380a11"
+	updateQuery := regexp.QuoteMeta(`UPDATE transfers SET status = $1, reception_id = $2, received_at = $3 WHERE id = $4`)
+	productsQuery := regexp.QuoteMeta(`UPDATE products SET status = $1, transfer_id = $2 WHERE transfer_id = $3`)
+
+	t.Run("Success", func(t *testing.T) {
+		mock.ExpectExec(updateQuery).
+			WithArgs(model.TransferStatusReceived, receptionID, now, transferID).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec(productsQuery).
+			WithArgs(model.ProductStatusReceived, nil, transferID).
+			WillReturnResult(sqlmock.NewResult(0, 2))
+
+		err := transferRepo.MarkTransferReceived(ctx, transferID, receptionID, now)
+
+		assert.NoError(t, err)
+	})
+
+	t.Run("Not Found", func(t *testing.T) {
+		mock.ExpectExec(updateQuery).
+			WithArgs(model.TransferStatusReceived, receptionID, now, transferID).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+
+		err := transferRepo.MarkTransferReceived(ctx, transferID, receptionID, now)
+
+		assert.ErrorIs(t, err, model.ErrTransferNotFound)
+	})
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
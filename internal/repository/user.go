@@ -4,6 +4,7 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
+	"time"
 
 	sq "github.com/Masterminds/squirrel"
 	"github.com/kirillidk/pvz-service/internal/dto"
@@ -12,13 +13,22 @@ import (
 )
 
 const (
-	usertableName = "users"
+	usertableName         = "users"
+	recoveryCodeTableName = "user_recovery_codes"
 )
 
 type UserRepositoryInterface interface {
 	CreateUser(ctx context.Context, registerReq dto.RegisterRequest) (*model.User, error)
 	FindUserByEmail(ctx context.Context, email string) (*model.User, string, error)
 	UserExists(ctx context.Context, email string) (bool, error)
+	FindUserByID(ctx context.Context, userID string) (*model.User, error)
+	GetTOTPSecret(ctx context.Context, userID string) (string, error)
+	SetTOTPSecret(ctx context.Context, userID string, secret string, recoveryCodeHashes []string) error
+	EnableTOTP(ctx context.Context, userID string) error
+	UseRecoveryCode(ctx context.Context, userID string, codeHash string) (bool, error)
+	AcceptTOTPStep(ctx context.Context, userID string, step int64) (bool, error)
+	RecordTwoFactorFailure(ctx context.Context, userID string, maxAttempts int, lockedUntil time.Time) error
+	ResetTwoFactorFailures(ctx context.Context, userID string) error
 }
 
 type UserRepository struct {
@@ -64,7 +74,7 @@ func (r *UserRepository) FindUserByEmail(ctx context.Context, email string) (*mo
 	var passwordHash string
 
 	query, args, err := r.psql.
-		Select("id", "email", "password_hash", "role").
+		Select("id", "email", "password_hash", "role", "totp_enabled").
 		From(usertableName).
 		Where(sq.Eq{"email": email}).
 		ToSql()
@@ -73,7 +83,7 @@ func (r *UserRepository) FindUserByEmail(ctx context.Context, email string) (*mo
 		return nil, "", fmt.Errorf("failed to build sql query: %w", err)
 	}
 
-	err = r.db.QueryRowContext(ctx, query, args...).Scan(&user.ID, &user.Email, &passwordHash, &user.Role)
+	err = r.db.QueryRowContext(ctx, query, args...).Scan(&user.ID, &user.Email, &passwordHash, &user.Role, &user.TwoFactorEnabled)
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return nil, "", fmt.Errorf("user not found")
@@ -102,3 +112,239 @@ func (r *UserRepository) UserExists(ctx context.Context, email string) (bool, er
 
 	return exists, nil
 }
+
+func (r *UserRepository) FindUserByID(ctx context.Context, userID string) (*model.User, error) {
+	query, args, err := r.psql.
+		Select("id", "email", "role", "totp_enabled", "totp_locked_until").
+		From(usertableName).
+		Where(sq.Eq{"id": userID}).
+		ToSql()
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to build sql query: %w", err)
+	}
+
+	var (
+		user        model.User
+		lockedUntil sql.NullTime
+	)
+	err = r.db.QueryRowContext(ctx, query, args...).Scan(&user.ID, &user.Email, &user.Role, &user.TwoFactorEnabled, &lockedUntil)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("user not found")
+		}
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+
+	user.TwoFactorLockedUntil = nullTimePtr(lockedUntil)
+
+	return &user, nil
+}
+
+func (r *UserRepository) GetTOTPSecret(ctx context.Context, userID string) (string, error) {
+	query, args, err := r.psql.
+		Select("totp_secret").
+		From(usertableName).
+		Where(sq.Eq{"id": userID}).
+		ToSql()
+
+	if err != nil {
+		return "", fmt.Errorf("failed to build sql query: %w", err)
+	}
+
+	var secret sql.NullString
+	err = r.db.QueryRowContext(ctx, query, args...).Scan(&secret)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return "", fmt.Errorf("user not found")
+		}
+		return "", fmt.Errorf("failed to get totp secret: %w", err)
+	}
+
+	if !secret.Valid {
+		return "", fmt.Errorf("two-factor authentication is not set up")
+	}
+
+	return secret.String, nil
+}
+
+func (r *UserRepository) SetTOTPSecret(ctx context.Context, userID string, secret string, recoveryCodeHashes []string) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	query, args, err := r.psql.
+		Update(usertableName).
+		Set("totp_secret", secret).
+		Set("totp_enabled", false).
+		Where(sq.Eq{"id": userID}).
+		ToSql()
+
+	if err != nil {
+		return fmt.Errorf("failed to build sql query: %w", err)
+	}
+
+	if _, err := tx.ExecContext(ctx, query, args...); err != nil {
+		return fmt.Errorf("failed to set totp secret: %w", err)
+	}
+
+	query, args, err = r.psql.
+		Delete(recoveryCodeTableName).
+		Where(sq.Eq{"user_id": userID}).
+		ToSql()
+
+	if err != nil {
+		return fmt.Errorf("failed to build sql query: %w", err)
+	}
+
+	if _, err := tx.ExecContext(ctx, query, args...); err != nil {
+		return fmt.Errorf("failed to delete recovery codes: %w", err)
+	}
+
+	if len(recoveryCodeHashes) > 0 {
+		insertBuilder := r.psql.
+			Insert(recoveryCodeTableName).
+			Columns("user_id", "code_hash")
+
+		for _, codeHash := range recoveryCodeHashes {
+			insertBuilder = insertBuilder.Values(userID, codeHash)
+		}
+
+		query, args, err = insertBuilder.ToSql()
+		if err != nil {
+			return fmt.Errorf("failed to build sql query: %w", err)
+		}
+
+		if _, err := tx.ExecContext(ctx, query, args...); err != nil {
+			return fmt.Errorf("failed to store recovery codes: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+func (r *UserRepository) EnableTOTP(ctx context.Context, userID string) error {
+	query, args, err := r.psql.
+		Update(usertableName).
+		Set("totp_enabled", true).
+		Where(sq.And{sq.Eq{"id": userID}, sq.NotEq{"totp_secret": nil}}).
+		ToSql()
+
+	if err != nil {
+		return fmt.Errorf("failed to build sql query: %w", err)
+	}
+
+	result, err := r.db.ExecContext(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("failed to enable totp: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return fmt.Errorf("two-factor authentication is not set up")
+	}
+
+	return nil
+}
+
+func (r *UserRepository) UseRecoveryCode(ctx context.Context, userID string, codeHash string) (bool, error) {
+	query, args, err := r.psql.
+		Update(recoveryCodeTableName).
+		Set("used_at", time.Now().UTC()).
+		Where(sq.Eq{"user_id": userID, "code_hash": codeHash, "used_at": nil}).
+		ToSql()
+
+	if err != nil {
+		return false, fmt.Errorf("failed to build sql query: %w", err)
+	}
+
+	result, err := r.db.ExecContext(ctx, query, args...)
+	if err != nil {
+		return false, fmt.Errorf("failed to use recovery code: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	return rowsAffected > 0, nil
+}
+
+// AcceptTOTPStep records step as the last time step a TOTP code was
+// accepted for. It reports false when a code of this or a later step was
+// already accepted, so that a code cannot be used twice.
+func (r *UserRepository) AcceptTOTPStep(ctx context.Context, userID string, step int64) (bool, error) {
+	query, args, err := r.psql.
+		Update(usertableName).
+		Set("totp_last_step", step).
+		Where(sq.Eq{"id": userID}).
+		Where(sq.Or{sq.Eq{"totp_last_step": nil}, sq.Lt{"totp_last_step": step}}).
+		ToSql()
+
+	if err != nil {
+		return false, fmt.Errorf("failed to build sql query: %w", err)
+	}
+
+	result, err := r.db.ExecContext(ctx, query, args...)
+	if err != nil {
+		return false, fmt.Errorf("failed to accept totp code: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	return rowsAffected > 0, nil
+}
+
+// RecordTwoFactorFailure counts a wrong second-factor code. The failure
+// that reaches maxAttempts locks two-factor authentication until
+// lockedUntil and starts the count over.
+func (r *UserRepository) RecordTwoFactorFailure(ctx context.Context, userID string, maxAttempts int, lockedUntil time.Time) error {
+	query, args, err := r.psql.
+		Update(usertableName).
+		Set("totp_locked_until", sq.Expr("CASE WHEN totp_failed_attempts + 1 >= ? THEN ? ELSE totp_locked_until END", maxAttempts, lockedUntil)).
+		Set("totp_failed_attempts", sq.Expr("CASE WHEN totp_failed_attempts + 1 >= ? THEN 0 ELSE totp_failed_attempts + 1 END", maxAttempts)).
+		Where(sq.Eq{"id": userID}).
+		ToSql()
+
+	if err != nil {
+		return fmt.Errorf("failed to build sql query: %w", err)
+	}
+
+	if _, err := r.db.ExecContext(ctx, query, args...); err != nil {
+		return fmt.Errorf("failed to record two-factor failure: %w", err)
+	}
+
+	return nil
+}
+
+func (r *UserRepository) ResetTwoFactorFailures(ctx context.Context, userID string) error {
+	query, args, err := r.psql.
+		Update(usertableName).
+		Set("totp_failed_attempts", 0).
+		Where(sq.Eq{"id": userID}).
+		ToSql()
+
+	if err != nil {
+		return fmt.Errorf("failed to build sql query: %w", err)
+	}
+
+	if _, err := r.db.ExecContext(ctx, query, args...); err != nil {
+		return fmt.Errorf("failed to reset two-factor failures: %w", err)
+	}
+
+	return nil
+}
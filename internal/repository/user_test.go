@@ -6,6 +6,7 @@ import (
 	"errors"
 	"regexp"
 	"testing"
+	"time"
 
 	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/kirillidk/pvz-service/internal/dto"
@@ -114,10 +115,10 @@ func TestUserRepository_FindUserByEmail(t *testing.T) {
 			name:  "Success",
 			email: "test@example.com",
 			mockBehavior: func() {
-				rows := sqlmock.NewRows([]string{"id", "email", "password_hash", "role"}).
-					AddRow("123e4567-e89b-12d3-a456-426614174000", "test@example.com", "hashed_password", "employee")
+				rows := sqlmock.NewRows([]string{"id", "email", "password_hash", "role", "totp_enabled"}).
+					AddRow("123e4567-e89b-12d3-a456-426614174000", "test@example.com", "hashed_password", "employee", false)
 
-				mock.ExpectQuery(regexp.QuoteMeta(`SELECT id, email, password_hash, role FROM users WHERE email = $1`)).
+				mock.ExpectQuery(regexp.QuoteMeta(`SELECT id, email, password_hash, role, totp_enabled FROM users WHERE email = $1`)).
 					WithArgs("test@example.com").
 					WillReturnRows(rows)
 			},
@@ -133,7 +134,7 @@ func TestUserRepository_FindUserByEmail(t *testing.T) {
 			name:  "User Not Found",
 			email: "nonexistent@example.com",
 			mockBehavior: func() {
-				mock.ExpectQuery(regexp.QuoteMeta(`SELECT id, email, password_hash, role FROM users WHERE email = $1`)).
+				mock.ExpectQuery(regexp.QuoteMeta(`SELECT id, email, password_hash, role, totp_enabled FROM users WHERE email = $1`)).
 					WithArgs("nonexistent@example.com").
 					WillReturnError(sql.ErrNoRows)
 			},
@@ -145,7 +146,7 @@ func TestUserRepository_FindUserByEmail(t *testing.T) {
 			name:  "DB Error",
 			email: "test@example.com",
 			mockBehavior: func() {
-				mock.ExpectQuery(regexp.QuoteMeta(`SELECT id, email, password_hash, role FROM users WHERE email = $1`)).
+				mock.ExpectQuery(regexp.QuoteMeta(`SELECT id, email, password_hash, role, totp_enabled FROM users WHERE email = $1`)).
 					WithArgs("test@example.com").
 					WillReturnError(errors.New("db error"))
 			},
@@ -256,3 +257,61 @@ func TestUserRepository_UserExists(t *testing.T) {
 		})
 	}
 }
+
+func TestUserRepository_AcceptTOTPStep(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	userRepo := repository.NewUserRepository(db)
+	ctx := context.Background()
+	userID := "a0eebc99-9c0b-4ef8-bb6d-6bb9bd380a11"
+	updateQuery := regexp.QuoteMeta(`UPDATE users SET totp_last_step = $1 WHERE id = $2 AND (totp_last_step IS NULL OR totp_last_step < $3)`)
+
+	mock.ExpectExec(updateQuery).
+		WithArgs(int64(56666666), userID, int64(56666666)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	accepted, err := userRepo.AcceptTOTPStep(ctx, userID, 56666666)
+	assert.NoError(t, err)
+	assert.True(t, accepted)
+
+	mock.ExpectExec(updateQuery).
+		WithArgs(int64(56666666), userID, int64(56666666)).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	accepted, err = userRepo.AcceptTOTPStep(ctx, userID, 56666666)
+	assert.NoError(t, err)
+	assert.False(t, accepted)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func TestUserRepository_RecordTwoFactorFailure(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	userRepo := repository.NewUserRepository(db)
+	ctx := context.Background()
+	userID := "a0eebc99-9c0b-4ef8-bb6d-6bb9bd380a11"
+	lockedUntil := time.Now().Add(15 * time.Minute)
+
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE users SET `+
+		`totp_locked_until = CASE WHEN totp_failed_attempts + 1 >= $1 THEN $2 ELSE totp_locked_until END, `+
+		`totp_failed_attempts = CASE WHEN totp_failed_attempts + 1 >= $3 THEN 0 ELSE totp_failed_attempts + 1 END WHERE id = $4`)).
+		WithArgs(5, lockedUntil, 5, userID).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	assert.NoError(t, userRepo.RecordTwoFactorFailure(ctx, userID, 5, lockedUntil))
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
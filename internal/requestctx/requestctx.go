@@ -0,0 +1,34 @@
+// Package requestctx carries request-scoped metadata, such as the
+// authenticated actor and the request ID, from the HTTP layer to services.
+package requestctx
+
+import (
+	"context"
+
+	"github.com/kirillidk/pvz-service/internal/model"
+)
+
+type actorKey struct{}
+
+type requestIDKey struct{}
+
+func WithActor(ctx context.Context, actor model.Actor) context.Context {
+	return context.WithValue(ctx, actorKey{}, actor)
+}
+
+func ActorFromContext(ctx context.Context) model.Actor {
+	actor, ok := ctx.Value(actorKey{}).(model.Actor)
+	if !ok {
+		return model.Actor{Type: model.SystemActor}
+	}
+	return actor
+}
+
+func WithRequestID(ctx context.Context, requestID string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, requestID)
+}
+
+func RequestIDFromContext(ctx context.Context) string {
+	requestID, _ := ctx.Value(requestIDKey{}).(string)
+	return requestID
+}
@@ -0,0 +1,19 @@
+package route
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/kirillidk/pvz-service/internal/handler"
+	"github.com/kirillidk/pvz-service/internal/middleware"
+	"github.com/kirillidk/pvz-service/internal/model"
+)
+
+func SetupAPIKeyRoutes(router *gin.Engine, handler *handler.Handler, authMiddleware gin.HandlerFunc) {
+	apiKeyGroup := router.Group("/api-keys")
+	{
+		apiKeyGroup.Use(authMiddleware, middleware.UserOnlyMiddleware(), middleware.RoleMiddleware(model.ModeratorRole))
+
+		apiKeyGroup.GET("", handler.APIKeyHandler.GetAPIKeyList)
+		apiKeyGroup.POST("", handler.APIKeyHandler.CreateAPIKey)
+		apiKeyGroup.DELETE("/:apiKeyId", handler.APIKeyHandler.RevokeAPIKey)
+	}
+}
@@ -0,0 +1,17 @@
+package route
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/kirillidk/pvz-service/internal/handler"
+	"github.com/kirillidk/pvz-service/internal/middleware"
+	"github.com/kirillidk/pvz-service/internal/model"
+)
+
+func SetupAuditRoutes(router *gin.Engine, handler *handler.Handler, authMiddleware gin.HandlerFunc) {
+	auditGroup := router.Group("/audit")
+	{
+		auditGroup.Use(authMiddleware, middleware.UserOnlyMiddleware(), middleware.RoleMiddleware(model.ModeratorRole))
+
+		auditGroup.GET("", handler.AuditHandler.GetAuditLog)
+	}
+}
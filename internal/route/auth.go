@@ -3,10 +3,20 @@ package route
 import (
 	"github.com/gin-gonic/gin"
 	"github.com/kirillidk/pvz-service/internal/handler"
+	"github.com/kirillidk/pvz-service/internal/middleware"
 )
 
-func SetupAuthRoutes(router *gin.Engine, handler *handler.Handler) {
+func SetupAuthRoutes(router *gin.Engine, handler *handler.Handler, jwtSecret string) {
 	router.POST("/dummyLogin", handler.AuthHandler.DummyLogin)
 	router.POST("/register", handler.AuthHandler.Register)
 	router.POST("/login", handler.AuthHandler.Login)
+	router.POST("/login/2fa", handler.AuthHandler.VerifyTwoFactor)
+
+	twoFactorGroup := router.Group("/2fa")
+	{
+		twoFactorGroup.Use(middleware.TwoFactorEnrollmentMiddleware(jwtSecret))
+
+		twoFactorGroup.POST("/enroll", handler.AuthHandler.EnrollTwoFactor)
+		twoFactorGroup.POST("/confirm", handler.AuthHandler.ConfirmTwoFactor)
+	}
 }
@@ -0,0 +1,22 @@
+package route
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/kirillidk/pvz-service/internal/handler"
+	"github.com/kirillidk/pvz-service/internal/middleware"
+	"github.com/kirillidk/pvz-service/internal/model"
+)
+
+func SetupCityRoutes(router *gin.Engine, handler *handler.Handler, authMiddleware, idempotencyMiddleware gin.HandlerFunc) {
+	cityGroup := router.Group("/cities")
+	{
+		cityGroup.Use(authMiddleware, idempotencyMiddleware)
+
+		cityGroup.GET("", middleware.RoleMiddleware(model.EmployeeRole, model.ModeratorRole), handler.CityHandler.GetCityList)
+		cityGroup.GET("/:cityId", middleware.RoleMiddleware(model.EmployeeRole, model.ModeratorRole), handler.CityHandler.GetCity)
+
+		cityGroup.POST("", middleware.RoleMiddleware(model.ModeratorRole), handler.CityHandler.CreateCity)
+		cityGroup.PATCH("/:cityId", middleware.RoleMiddleware(model.ModeratorRole), handler.CityHandler.UpdateCity)
+		cityGroup.DELETE("/:cityId", middleware.RoleMiddleware(model.ModeratorRole), handler.CityHandler.DeleteCity)
+	}
+}
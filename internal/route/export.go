@@ -0,0 +1,17 @@
+package route
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/kirillidk/pvz-service/internal/handler"
+	"github.com/kirillidk/pvz-service/internal/middleware"
+	"github.com/kirillidk/pvz-service/internal/model"
+)
+
+func SetupExportRoutes(router *gin.Engine, handler *handler.Handler, authMiddleware gin.HandlerFunc) {
+	exportGroup := router.Group("/export")
+	{
+		exportGroup.Use(authMiddleware)
+
+		exportGroup.GET("/receptions", middleware.RoleMiddleware(model.EmployeeRole, model.ModeratorRole), handler.ExportHandler.ExportReceptions)
+	}
+}
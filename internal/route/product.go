@@ -7,11 +7,13 @@ import (
 	"github.com/kirillidk/pvz-service/internal/model"
 )
 
-func SetupProductRoutes(router *gin.Engine, handler *handler.Handler, jwtSecret string) {
+func SetupProductRoutes(router *gin.Engine, handler *handler.Handler, authMiddleware, idempotencyMiddleware gin.HandlerFunc) {
 	productGroup := router.Group("/products")
 	{
-		productGroup.Use(middleware.AuthMiddleware(jwtSecret))
+		productGroup.Use(authMiddleware, idempotencyMiddleware)
 
+		productGroup.GET("", middleware.RoleMiddleware(model.EmployeeRole, model.ModeratorRole), handler.ProductHandler.FindProducts)
 		productGroup.POST("", middleware.RoleMiddleware(model.EmployeeRole), handler.ProductHandler.CreateProduct)
+		productGroup.POST("/batch", middleware.RoleMiddleware(model.EmployeeRole), handler.ProductHandler.CreateProducts)
 	}
 }
@@ -0,0 +1,22 @@
+package route
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/kirillidk/pvz-service/internal/handler"
+	"github.com/kirillidk/pvz-service/internal/middleware"
+	"github.com/kirillidk/pvz-service/internal/model"
+)
+
+func SetupProductTypeRoutes(router *gin.Engine, handler *handler.Handler, authMiddleware, idempotencyMiddleware gin.HandlerFunc) {
+	productTypeGroup := router.Group("/product-types")
+	{
+		productTypeGroup.Use(authMiddleware, idempotencyMiddleware)
+
+		productTypeGroup.GET("", middleware.RoleMiddleware(model.EmployeeRole, model.ModeratorRole), handler.ProductTypeHandler.GetProductTypeList)
+		productTypeGroup.GET("/:code", middleware.RoleMiddleware(model.EmployeeRole, model.ModeratorRole), handler.ProductTypeHandler.GetProductType)
+
+		productTypeGroup.POST("", middleware.RoleMiddleware(model.ModeratorRole), handler.ProductTypeHandler.CreateProductType)
+		productTypeGroup.PATCH("/:code", middleware.RoleMiddleware(model.ModeratorRole), handler.ProductTypeHandler.UpdateProductType)
+		productTypeGroup.DELETE("/:code", middleware.RoleMiddleware(model.ModeratorRole), handler.ProductTypeHandler.DeleteProductType)
+	}
+}
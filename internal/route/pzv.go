@@ -7,15 +7,55 @@ import (
 	"github.com/kirillidk/pvz-service/internal/model"
 )
 
-func SetupPVZRoutes(router *gin.Engine, handler *handler.Handler, jwtSecret string) {
+func SetupPVZRoutes(router *gin.Engine, handler *handler.Handler, authMiddleware, idempotencyMiddleware gin.HandlerFunc) {
 	pvzGroup := router.Group("/pvz")
 	{
-		pvzGroup.Use(middleware.AuthMiddleware(jwtSecret))
+		pvzGroup.Use(authMiddleware, idempotencyMiddleware)
 
 		pvzGroup.GET("", middleware.RoleMiddleware(model.EmployeeRole, model.ModeratorRole), handler.PVZHandler.GetPVZList)
+		pvzGroup.GET("/nearby", middleware.RoleMiddleware(model.EmployeeRole, model.ModeratorRole), handler.PVZHandler.GetNearbyPVZList)
+		pvzGroup.GET("/stats/daily", middleware.RoleMiddleware(model.ModeratorRole), middleware.AllPVZAccessMiddleware(), handler.StatsHandler.GetDailyStats)
+		pvzGroup.GET("/stats/throughput", middleware.RoleMiddleware(model.ModeratorRole), middleware.AllPVZAccessMiddleware(), handler.StatsHandler.GetReceptionThroughput)
+		pvzGroup.GET("/:pvzId", middleware.RoleMiddleware(model.EmployeeRole, model.ModeratorRole), middleware.PVZAccessMiddleware(), handler.PVZHandler.GetPVZ)
+		pvzGroup.GET("/:pvzId/receptions/current", middleware.RoleMiddleware(model.EmployeeRole, model.ModeratorRole), middleware.PVZAccessMiddleware(), handler.ReceptionHandler.GetCurrentReception)
+		pvzGroup.GET("/:pvzId/products/:productId/history", middleware.RoleMiddleware(model.EmployeeRole, model.ModeratorRole), middleware.PVZAccessMiddleware(), handler.ProductLifecycleHandler.GetProductStatusHistory)
+		pvzGroup.GET("/:pvzId/stats/daily", middleware.RoleMiddleware(model.EmployeeRole, model.ModeratorRole), middleware.PVZAccessMiddleware(), handler.StatsHandler.GetPVZDailyStats)
+		pvzGroup.GET("/:pvzId/overdue", middleware.RoleMiddleware(model.EmployeeRole, model.ModeratorRole), middleware.PVZAccessMiddleware(), handler.OverdueHandler.GetOverdueProducts)
+		pvzGroup.GET("/:pvzId/cells", middleware.RoleMiddleware(model.EmployeeRole, model.ModeratorRole), middleware.PVZAccessMiddleware(), handler.StorageCellHandler.GetStorageCells)
+		pvzGroup.GET("/:pvzId/cells/occupancy", middleware.RoleMiddleware(model.EmployeeRole, model.ModeratorRole), middleware.PVZAccessMiddleware(), handler.StorageCellHandler.GetStorageCellOccupancy)
+		pvzGroup.GET("/:pvzId/transfers", middleware.RoleMiddleware(model.EmployeeRole, model.ModeratorRole), middleware.PVZAccessMiddleware(), handler.TransferHandler.GetTransfers)
+		pvzGroup.GET("/:pvzId/transfers/:transferId", middleware.RoleMiddleware(model.EmployeeRole, model.ModeratorRole), middleware.PVZAccessMiddleware(), handler.TransferHandler.GetTransfer)
+		pvzGroup.GET("/:pvzId/products/:productId/transfers", middleware.RoleMiddleware(model.EmployeeRole, model.ModeratorRole), middleware.PVZAccessMiddleware(), handler.TransferHandler.GetProductTransfers)
+		pvzGroup.GET("/:pvzId/expected-deliveries", middleware.RoleMiddleware(model.EmployeeRole, model.ModeratorRole), middleware.PVZAccessMiddleware(), handler.ExpectedDeliveryHandler.GetExpectedDeliveries)
+		pvzGroup.GET("/:pvzId/expected-deliveries/:deliveryId", middleware.RoleMiddleware(model.EmployeeRole, model.ModeratorRole), middleware.PVZAccessMiddleware(), handler.ExpectedDeliveryHandler.GetExpectedDelivery)
+		pvzGroup.GET("/:pvzId/receptions/:receptionId/discrepancies", middleware.RoleMiddleware(model.EmployeeRole, model.ModeratorRole), middleware.PVZAccessMiddleware(), handler.ReceptionHandler.GetDiscrepancyReport)
+		pvzGroup.GET("/:pvzId/receptions/:receptionId/history", middleware.RoleMiddleware(model.EmployeeRole, model.ModeratorRole), middleware.PVZAccessMiddleware(), handler.ReceptionHandler.GetReceptionStatusHistory)
+		pvzGroup.GET("/:pvzId/products/:productId/attachments", middleware.RoleMiddleware(model.EmployeeRole, model.ModeratorRole), middleware.PVZAccessMiddleware(), handler.ProductInspectionHandler.GetProductAttachments)
+		pvzGroup.GET("/:pvzId/products/:productId/attachments/:attachmentId", middleware.RoleMiddleware(model.EmployeeRole, model.ModeratorRole), middleware.PVZAccessMiddleware(), handler.ProductInspectionHandler.DownloadProductAttachment)
 
-		pvzGroup.POST("", middleware.RoleMiddleware(model.ModeratorRole), handler.PVZHandler.CreatePVZ)
-		pvzGroup.POST("/:pvzId/delete_last_product", middleware.RoleMiddleware(model.EmployeeRole), handler.ProductHandler.DeleteLastProduct)
-		pvzGroup.POST("/:pvzId/close_last_reception", middleware.RoleMiddleware(model.EmployeeRole), handler.ReceptionHandler.CloseLastReception)
+		pvzGroup.POST("", middleware.RoleMiddleware(model.ModeratorRole), middleware.AllPVZAccessMiddleware(), handler.PVZHandler.CreatePVZ)
+		pvzGroup.PATCH("/:pvzId", middleware.RoleMiddleware(model.ModeratorRole), middleware.PVZAccessMiddleware(), handler.PVZHandler.UpdatePVZ)
+		pvzGroup.POST("/:pvzId/suspend", middleware.RoleMiddleware(model.ModeratorRole), middleware.PVZAccessMiddleware(), handler.PVZHandler.SuspendPVZ)
+		pvzGroup.POST("/:pvzId/reopen", middleware.RoleMiddleware(model.ModeratorRole), middleware.PVZAccessMiddleware(), handler.PVZHandler.ReopenPVZ)
+		pvzGroup.POST("/:pvzId/archive", middleware.RoleMiddleware(model.ModeratorRole), middleware.PVZAccessMiddleware(), handler.PVZHandler.ArchivePVZ)
+		pvzGroup.POST("/:pvzId/cells", middleware.RoleMiddleware(model.ModeratorRole), middleware.PVZAccessMiddleware(), handler.StorageCellHandler.CreateStorageCell)
+		pvzGroup.PATCH("/:pvzId/cells/:cellId", middleware.RoleMiddleware(model.ModeratorRole), middleware.PVZAccessMiddleware(), handler.StorageCellHandler.UpdateStorageCell)
+		pvzGroup.DELETE("/:pvzId/cells/:cellId", middleware.RoleMiddleware(model.ModeratorRole), middleware.PVZAccessMiddleware(), handler.StorageCellHandler.DeleteStorageCell)
+		pvzGroup.POST("/:pvzId/delete_last_product", middleware.RoleMiddleware(model.EmployeeRole), middleware.PVZAccessMiddleware(), handler.ProductHandler.DeleteLastProduct)
+		pvzGroup.POST("/:pvzId/close_last_reception", middleware.RoleMiddleware(model.EmployeeRole), middleware.PVZAccessMiddleware(), handler.ReceptionHandler.CloseLastReception)
+		pvzGroup.POST("/:pvzId/products/:productId/store", middleware.RoleMiddleware(model.EmployeeRole), middleware.PVZAccessMiddleware(), handler.ProductLifecycleHandler.StoreProduct)
+		pvzGroup.POST("/:pvzId/products/:productId/issue", middleware.RoleMiddleware(model.EmployeeRole, model.ModeratorRole), middleware.PVZAccessMiddleware(), handler.ProductLifecycleHandler.IssueProduct)
+		pvzGroup.POST("/:pvzId/products/:productId/return", middleware.RoleMiddleware(model.EmployeeRole), middleware.PVZAccessMiddleware(), handler.ProductLifecycleHandler.ReturnProduct)
+		pvzGroup.PUT("/:pvzId/products/:productId/cell", middleware.RoleMiddleware(model.EmployeeRole), middleware.PVZAccessMiddleware(), handler.StorageCellHandler.AssignProductToCell)
+		pvzGroup.POST("/:pvzId/orders", middleware.RoleMiddleware(model.EmployeeRole), middleware.PVZAccessMiddleware(), handler.OrderHandler.CreateOrder)
+		pvzGroup.POST("/:pvzId/orders/:orderId/code", middleware.RoleMiddleware(model.EmployeeRole), middleware.PVZAccessMiddleware(), handler.OrderHandler.RegeneratePickupCode)
+		pvzGroup.POST("/:pvzId/issue", middleware.RoleMiddleware(model.EmployeeRole), middleware.PVZAccessMiddleware(), handler.OrderHandler.IssueOrder)
+		pvzGroup.POST("/:pvzId/transfers", middleware.RoleMiddleware(model.EmployeeRole), middleware.PVZAccessMiddleware(), handler.TransferHandler.CreateTransfer)
+		pvzGroup.POST("/:pvzId/transfers/:transferId/ship", middleware.RoleMiddleware(model.EmployeeRole), middleware.PVZAccessMiddleware(), handler.TransferHandler.ShipTransfer)
+		pvzGroup.POST("/:pvzId/transfers/:transferId/receive", middleware.RoleMiddleware(model.EmployeeRole), middleware.PVZAccessMiddleware(), handler.TransferHandler.ReceiveTransfer)
+		pvzGroup.POST("/:pvzId/expected-deliveries", middleware.RoleMiddleware(model.EmployeeRole, model.ModeratorRole), middleware.PVZAccessMiddleware(), handler.ExpectedDeliveryHandler.CreateExpectedDelivery)
+		pvzGroup.PUT("/:pvzId/receptions/:receptionId/manifest", middleware.RoleMiddleware(model.EmployeeRole), middleware.PVZAccessMiddleware(), handler.ReceptionHandler.SetExpectedCount)
+		pvzGroup.PUT("/:pvzId/products/:productId/condition", middleware.RoleMiddleware(model.EmployeeRole), middleware.PVZAccessMiddleware(), handler.ProductInspectionHandler.SetProductCondition)
+		pvzGroup.POST("/:pvzId/products/:productId/attachments", middleware.RoleMiddleware(model.EmployeeRole), middleware.PVZAccessMiddleware(), handler.ProductInspectionHandler.AddProductAttachment)
 	}
 }
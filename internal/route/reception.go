@@ -7,11 +7,14 @@ import (
 	"github.com/kirillidk/pvz-service/internal/model"
 )
 
-func SetupReceptionRoutes(router *gin.Engine, handler *handler.Handler, jwtSecret string) {
+func SetupReceptionRoutes(router *gin.Engine, handler *handler.Handler, authMiddleware, idempotencyMiddleware gin.HandlerFunc) {
 	receptionGroup := router.Group("/receptions")
 	{
-		receptionGroup.Use(middleware.AuthMiddleware(jwtSecret))
+		receptionGroup.Use(authMiddleware, idempotencyMiddleware)
 
 		receptionGroup.POST("", middleware.RoleMiddleware(model.EmployeeRole), handler.ReceptionHandler.CreateReception)
+		receptionGroup.GET("/discrepancies", middleware.RoleMiddleware(model.ModeratorRole), middleware.AllPVZAccessMiddleware(), handler.ReceptionHandler.GetDiscrepancies)
+		receptionGroup.GET("/:receptionId", middleware.RoleMiddleware(model.EmployeeRole, model.ModeratorRole), handler.ReceptionHandler.GetReception)
+		receptionGroup.POST("/:receptionId/reopen", middleware.RoleMiddleware(model.ModeratorRole), handler.ReceptionHandler.ReopenReception)
 	}
 }
@@ -5,9 +5,16 @@ import (
 	"github.com/kirillidk/pvz-service/internal/handler"
 )
 
-func SetupRoutes(router *gin.Engine, handler *handler.Handler, jwtSecret string) {
-	SetupAuthRoutes(router, handler)
-	SetupPVZRoutes(router, handler, jwtSecret)
-	SetupReceptionRoutes(router, handler, jwtSecret)
-	SetupProductRoutes(router, handler, jwtSecret)
+func SetupRoutes(router *gin.Engine, handler *handler.Handler, jwtSecret string, authMiddleware, idempotencyMiddleware gin.HandlerFunc) {
+	SetupAuthRoutes(router, handler, jwtSecret)
+	SetupPVZRoutes(router, handler, authMiddleware, idempotencyMiddleware)
+	SetupReceptionRoutes(router, handler, authMiddleware, idempotencyMiddleware)
+	SetupProductRoutes(router, handler, authMiddleware, idempotencyMiddleware)
+	// Idempotent replays would have to store the plain API key returned on
+	// creation, so API key routes do not support Idempotency-Key.
+	SetupAPIKeyRoutes(router, handler, authMiddleware)
+	SetupAuditRoutes(router, handler, authMiddleware)
+	SetupCityRoutes(router, handler, authMiddleware, idempotencyMiddleware)
+	SetupProductTypeRoutes(router, handler, authMiddleware, idempotencyMiddleware)
+	SetupExportRoutes(router, handler, authMiddleware)
 }
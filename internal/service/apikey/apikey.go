@@ -0,0 +1,138 @@
+package apikey
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/kirillidk/pvz-service/internal/dto"
+	"github.com/kirillidk/pvz-service/internal/model"
+	"github.com/kirillidk/pvz-service/internal/repository"
+)
+
+const (
+	keyPrefix     = "pvz_"
+	keyRandomSize = 32
+
+	// lastUsedInterval limits how often the last-used time of a key is
+	// written, so that a busy integration does not update its row on
+	// every request.
+	lastUsedInterval = time.Minute
+)
+
+type APIKeyServiceInterface interface {
+	CreateAPIKey(ctx context.Context, apiKeyReq dto.APIKeyCreateRequest) (*dto.APIKeyCreateResponse, error)
+	GetAPIKeyList(ctx context.Context) ([]model.APIKey, error)
+	RevokeAPIKey(ctx context.Context, apiKeyID string) (*model.APIKey, error)
+	Authenticate(ctx context.Context, rawKey string) (*model.APIKey, error)
+}
+
+type APIKeyService struct {
+	apiKeyRepository repository.APIKeyRepositoryInterface
+}
+
+func NewAPIKeyService(apiKeyRepo repository.APIKeyRepositoryInterface) *APIKeyService {
+	return &APIKeyService{
+		apiKeyRepository: apiKeyRepo,
+	}
+}
+
+func (s *APIKeyService) CreateAPIKey(ctx context.Context, apiKeyReq dto.APIKeyCreateRequest) (*dto.APIKeyCreateResponse, error) {
+	if apiKeyReq.ExpiresAt != nil && !apiKeyReq.ExpiresAt.After(time.Now()) {
+		return nil, errors.New("expiration time must be in the future")
+	}
+
+	rawKey, err := generateKey()
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate api key: %w", err)
+	}
+
+	apiKey, err := s.apiKeyRepository.CreateAPIKey(ctx, apiKeyReq, HashKey(rawKey))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create api key: %w", err)
+	}
+
+	return &dto.APIKeyCreateResponse{
+		APIKey: *apiKey,
+		Key:    rawKey,
+	}, nil
+}
+
+func (s *APIKeyService) GetAPIKeyList(ctx context.Context) ([]model.APIKey, error) {
+	apiKeys, err := s.apiKeyRepository.GetAPIKeyList(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get api key list: %w", err)
+	}
+
+	if apiKeys == nil {
+		apiKeys = []model.APIKey{}
+	}
+
+	return apiKeys, nil
+}
+
+func (s *APIKeyService) RevokeAPIKey(ctx context.Context, apiKeyID string) (*model.APIKey, error) {
+	apiKey, err := s.apiKeyRepository.RevokeAPIKey(ctx, apiKeyID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to revoke api key: %w", err)
+	}
+
+	return apiKey, nil
+}
+
+func (s *APIKeyService) Authenticate(ctx context.Context, rawKey string) (*model.APIKey, error) {
+	apiKey, err := s.apiKeyRepository.GetAPIKeyByHash(ctx, HashKey(rawKey))
+	if err != nil {
+		return nil, errors.New("invalid api key")
+	}
+
+	now := time.Now().UTC()
+
+	if apiKey.RevokedAt != nil {
+		return nil, errors.New("api key has been revoked")
+	}
+
+	if apiKey.ExpiresAt != nil && !apiKey.ExpiresAt.After(now) {
+		return nil, errors.New("api key has expired")
+	}
+
+	s.touch(ctx, apiKey, now)
+
+	return apiKey, nil
+}
+
+// touch records that the key was used at now. The time is informational,
+// so a failed write is logged and does not fail the request.
+func (s *APIKeyService) touch(ctx context.Context, apiKey *model.APIKey, now time.Time) {
+	if apiKey.LastUsedAt != nil && now.Sub(*apiKey.LastUsedAt) < lastUsedInterval {
+		return
+	}
+
+	if err := s.apiKeyRepository.UpdateLastUsed(ctx, apiKey.ID, now); err != nil {
+		log.Printf("failed to record usage of api key %s: %v", apiKey.ID, err)
+		return
+	}
+	apiKey.LastUsedAt = &now
+}
+
+// HashKey returns the hex-encoded SHA-256 digest under which a key is stored.
+// Keys carry 256 bits of entropy, so a fast unsalted hash is sufficient and
+// keeps lookups by hash possible.
+func HashKey(rawKey string) string {
+	sum := sha256.Sum256([]byte(rawKey))
+	return hex.EncodeToString(sum[:])
+}
+
+func generateKey() (string, error) {
+	buf := make([]byte, keyRandomSize)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+
+	return keyPrefix + hex.EncodeToString(buf), nil
+}
@@ -0,0 +1,182 @@
+package apikey_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kirillidk/pvz-service/internal/dto"
+	"github.com/kirillidk/pvz-service/internal/model"
+	"github.com/kirillidk/pvz-service/internal/service/apikey"
+)
+
+type MockAPIKeyRepository struct {
+	CreateAPIKeyFunc    func(ctx context.Context, req dto.APIKeyCreateRequest, keyHash string) (*model.APIKey, error)
+	GetAPIKeyByHashFunc func(ctx context.Context, keyHash string) (*model.APIKey, error)
+	GetAPIKeyListFunc   func(ctx context.Context) ([]model.APIKey, error)
+	RevokeAPIKeyFunc    func(ctx context.Context, apiKeyID string) (*model.APIKey, error)
+	UpdateLastUsedFunc  func(ctx context.Context, apiKeyID string, usedAt time.Time) error
+}
+
+func (m *MockAPIKeyRepository) CreateAPIKey(ctx context.Context, req dto.APIKeyCreateRequest, keyHash string) (*model.APIKey, error) {
+	return m.CreateAPIKeyFunc(ctx, req, keyHash)
+}
+
+func (m *MockAPIKeyRepository) GetAPIKeyByHash(ctx context.Context, keyHash string) (*model.APIKey, error) {
+	return m.GetAPIKeyByHashFunc(ctx, keyHash)
+}
+
+func (m *MockAPIKeyRepository) GetAPIKeyList(ctx context.Context) ([]model.APIKey, error) {
+	return m.GetAPIKeyListFunc(ctx)
+}
+
+func (m *MockAPIKeyRepository) RevokeAPIKey(ctx context.Context, apiKeyID string) (*model.APIKey, error) {
+	return m.RevokeAPIKeyFunc(ctx, apiKeyID)
+}
+
+func (m *MockAPIKeyRepository) UpdateLastUsed(ctx context.Context, apiKeyID string, usedAt time.Time) error {
+	return m.UpdateLastUsedFunc(ctx, apiKeyID, usedAt)
+}
+
+func TestAPIKeyService_CreateAPIKey(t *testing.T) {
+	past := time.Now().Add(-time.Hour)
+
+	tests := []struct {
+		name          string
+		mockRepo      *MockAPIKeyRepository
+		input         dto.APIKeyCreateRequest
+		expectedError bool
+	}{
+		{
+			name: "Success",
+			mockRepo: &MockAPIKeyRepository{
+				CreateAPIKeyFunc: func(ctx context.Context, req dto.APIKeyCreateRequest, keyHash string) (*model.APIKey, error) {
+					if len(keyHash) != 64 {
+						t.Errorf("expected sha256 hex hash, got %q", keyHash)
+					}
+					return &model.APIKey{ID: "key-id", Name: req.Name, Role: req.Role}, nil
+				},
+			},
+			input:         dto.APIKeyCreateRequest{Name: "partner", Role: model.EmployeeRole},
+			expectedError: false,
+		},
+		{
+			name:          "Expiration In The Past",
+			mockRepo:      &MockAPIKeyRepository{},
+			input:         dto.APIKeyCreateRequest{Name: "partner", Role: model.EmployeeRole, ExpiresAt: &past},
+			expectedError: true,
+		},
+		{
+			name: "Repository Error",
+			mockRepo: &MockAPIKeyRepository{
+				CreateAPIKeyFunc: func(ctx context.Context, req dto.APIKeyCreateRequest, keyHash string) (*model.APIKey, error) {
+					return nil, errors.New("repository error")
+				},
+			},
+			input:         dto.APIKeyCreateRequest{Name: "partner", Role: model.EmployeeRole},
+			expectedError: true,
+		},
+	}
+
+	for ttNum, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := apikey.NewAPIKeyService(tt.mockRepo)
+			got, err := s.CreateAPIKey(context.Background(), tt.input)
+
+			if (err != nil) != tt.expectedError {
+				t.Errorf("Test %v: APIKeyService.CreateAPIKey() error = %v, expectedError %v", ttNum, err, tt.expectedError)
+				return
+			}
+
+			if !tt.expectedError && !strings.HasPrefix(got.Key, "pvz_") {
+				t.Errorf("Test %v: APIKeyService.CreateAPIKey() returned unexpected key format %q", ttNum, got.Key)
+			}
+		})
+	}
+}
+
+func TestAPIKeyService_Authenticate(t *testing.T) {
+	past := time.Now().Add(-time.Hour)
+	recent := time.Now().Add(-time.Second)
+	rawKey := "pvz_test"
+
+	tests := []struct {
+		name          string
+		storedKey     *model.APIKey
+		lookupError   error
+		touchError    error
+		expectedError bool
+		expectTouch   bool
+	}{
+		{
+			name:          "Success",
+			storedKey:     &model.APIKey{ID: "key-id", Role: model.EmployeeRole},
+			expectedError: false,
+			expectTouch:   true,
+		},
+		{
+			name:          "Recently Used Key",
+			storedKey:     &model.APIKey{ID: "key-id", Role: model.EmployeeRole, LastUsedAt: &recent},
+			expectedError: false,
+			expectTouch:   false,
+		},
+		{
+			name:          "Last Used Update Fails",
+			storedKey:     &model.APIKey{ID: "key-id", Role: model.EmployeeRole, LastUsedAt: &past},
+			touchError:    errors.New("db error"),
+			expectedError: false,
+			expectTouch:   true,
+		},
+		{
+			name:          "Unknown Key",
+			lookupError:   model.ErrAPIKeyNotFound,
+			expectedError: true,
+		},
+		{
+			name:          "Expired Key",
+			storedKey:     &model.APIKey{ID: "key-id", Role: model.EmployeeRole, ExpiresAt: &past},
+			expectedError: true,
+		},
+		{
+			name:          "Revoked Key",
+			storedKey:     &model.APIKey{ID: "key-id", Role: model.EmployeeRole, RevokedAt: &past},
+			expectedError: true,
+		},
+	}
+
+	for ttNum, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			touched := false
+			mockRepo := &MockAPIKeyRepository{
+				GetAPIKeyByHashFunc: func(ctx context.Context, keyHash string) (*model.APIKey, error) {
+					if keyHash != apikey.HashKey(rawKey) {
+						t.Errorf("Test %v: unexpected key hash %q", ttNum, keyHash)
+					}
+					return tt.storedKey, tt.lookupError
+				},
+				UpdateLastUsedFunc: func(ctx context.Context, apiKeyID string, usedAt time.Time) error {
+					touched = true
+					return tt.touchError
+				},
+			}
+
+			s := apikey.NewAPIKeyService(mockRepo)
+			got, err := s.Authenticate(context.Background(), rawKey)
+
+			if (err != nil) != tt.expectedError {
+				t.Errorf("Test %v: APIKeyService.Authenticate() error = %v, expectedError %v", ttNum, err, tt.expectedError)
+				return
+			}
+
+			if touched != tt.expectTouch {
+				t.Errorf("Test %v: last used update = %v, expected %v", ttNum, touched, tt.expectTouch)
+			}
+
+			if !tt.expectedError && got.LastUsedAt == nil {
+				t.Errorf("Test %v: APIKeyService.Authenticate() did not set LastUsedAt", ttNum)
+			}
+		})
+	}
+}
@@ -0,0 +1,83 @@
+package audit
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/kirillidk/pvz-service/internal/dto"
+	"github.com/kirillidk/pvz-service/internal/model"
+	"github.com/kirillidk/pvz-service/internal/repository"
+	"github.com/kirillidk/pvz-service/internal/requestctx"
+)
+
+type AuditServiceInterface interface {
+	Record(ctx context.Context, action model.AuditAction, entityType model.AuditEntityType, entityID string, before, after any) error
+	GetAuditLog(ctx context.Context, filter dto.AuditFilterQuery) ([]model.AuditEntry, error)
+}
+
+type AuditService struct {
+	auditRepository repository.AuditRepositoryInterface
+}
+
+func NewAuditService(auditRepo repository.AuditRepositoryInterface) *AuditService {
+	return &AuditService{
+		auditRepository: auditRepo,
+	}
+}
+
+// Record appends an audit entry for the mutation of an entity. The actor and
+// request ID are taken from ctx. Callers run it in the same transaction as
+// the mutation, so a failure here rolls the mutation back.
+func (s *AuditService) Record(
+	ctx context.Context,
+	action model.AuditAction,
+	entityType model.AuditEntityType,
+	entityID string,
+	before, after any,
+) error {
+	beforeState, err := marshalState(before)
+	if err != nil {
+		return fmt.Errorf("failed to marshal before state: %w", err)
+	}
+
+	afterState, err := marshalState(after)
+	if err != nil {
+		return fmt.Errorf("failed to marshal after state: %w", err)
+	}
+
+	entry := model.AuditEntry{
+		// Postgres stores microseconds, the hash must match the stored value.
+		CreatedAt:  time.Now().UTC().Truncate(time.Microsecond),
+		Actor:      requestctx.ActorFromContext(ctx),
+		Action:     action,
+		EntityType: entityType,
+		EntityID:   entityID,
+		Before:     beforeState,
+		After:      afterState,
+		RequestID:  requestctx.RequestIDFromContext(ctx),
+	}
+
+	if _, err := s.auditRepository.CreateAuditEntry(ctx, entry); err != nil {
+		return fmt.Errorf("failed to record audit entry: %w", err)
+	}
+
+	return nil
+}
+
+func (s *AuditService) GetAuditLog(ctx context.Context, filter dto.AuditFilterQuery) ([]model.AuditEntry, error) {
+	entries, err := s.auditRepository.GetAuditLog(ctx, filter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get audit log: %w", err)
+	}
+
+	return entries, nil
+}
+
+func marshalState(state any) (json.RawMessage, error) {
+	if state == nil {
+		return nil, nil
+	}
+	return json.Marshal(state)
+}
@@ -0,0 +1,90 @@
+package audit_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/kirillidk/pvz-service/internal/dto"
+	"github.com/kirillidk/pvz-service/internal/model"
+	"github.com/kirillidk/pvz-service/internal/requestctx"
+	"github.com/kirillidk/pvz-service/internal/service/audit"
+)
+
+type MockAuditRepository struct {
+	CreateAuditEntryFunc func(ctx context.Context, entry model.AuditEntry) (*model.AuditEntry, error)
+	GetAuditLogFunc      func(ctx context.Context, filter dto.AuditFilterQuery) ([]model.AuditEntry, error)
+}
+
+func (m *MockAuditRepository) CreateAuditEntry(ctx context.Context, entry model.AuditEntry) (*model.AuditEntry, error) {
+	return m.CreateAuditEntryFunc(ctx, entry)
+}
+
+func (m *MockAuditRepository) GetAuditLog(ctx context.Context, filter dto.AuditFilterQuery) ([]model.AuditEntry, error) {
+	return m.GetAuditLogFunc(ctx, filter)
+}
+
+func TestAuditService_Record(t *testing.T) {
+	actor := model.Actor{ID: "c0eebc99-9c0b-4ef8-bb6d-6bb9bd380a11", Type: model.UserActor, Role: model.EmployeeRole}
+	ctx := requestctx.WithRequestID(requestctx.WithActor(context.Background(), actor), "req-1")
+	product := &model.Product{ID: "d0eebc99-9c0b-4ef8-bb6d-6bb9bd380a11", Type: "обувь"}
+
+	t.Run("Success", func(t *testing.T) {
+		var recorded model.AuditEntry
+		s := audit.NewAuditService(&MockAuditRepository{
+			CreateAuditEntryFunc: func(ctx context.Context, entry model.AuditEntry) (*model.AuditEntry, error) {
+				recorded = entry
+				return &entry, nil
+			},
+		})
+
+		err := s.Record(ctx, model.AuditActionDelete, model.AuditEntityProduct, product.ID, product, nil)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if recorded.Actor != actor {
+			t.Errorf("expected actor %v, got %v", actor, recorded.Actor)
+		}
+		if recorded.RequestID != "req-1" {
+			t.Errorf("expected request ID req-1, got %q", recorded.RequestID)
+		}
+		if recorded.Before == nil || recorded.After != nil {
+			t.Errorf("expected only before state, got before=%s after=%s", recorded.Before, recorded.After)
+		}
+		if recorded.CreatedAt.Location().String() != "UTC" {
+			t.Errorf("expected UTC timestamp, got %v", recorded.CreatedAt)
+		}
+	})
+
+	t.Run("No Actor In Context", func(t *testing.T) {
+		var recorded model.AuditEntry
+		s := audit.NewAuditService(&MockAuditRepository{
+			CreateAuditEntryFunc: func(ctx context.Context, entry model.AuditEntry) (*model.AuditEntry, error) {
+				recorded = entry
+				return &entry, nil
+			},
+		})
+
+		if err := s.Record(context.Background(), model.AuditActionCreate, model.AuditEntityPVZ, "pvz-id", nil, product); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if recorded.Actor.Type != model.SystemActor {
+			t.Errorf("expected system actor, got %v", recorded.Actor)
+		}
+	})
+
+	t.Run("Repository Error", func(t *testing.T) {
+		s := audit.NewAuditService(&MockAuditRepository{
+			CreateAuditEntryFunc: func(ctx context.Context, entry model.AuditEntry) (*model.AuditEntry, error) {
+				return nil, errors.New("db error")
+			},
+		})
+
+		err := s.Record(ctx, model.AuditActionCreate, model.AuditEntityPVZ, "pvz-id", nil, product)
+		if err == nil {
+			t.Error("expected error, got nil")
+		}
+	})
+}
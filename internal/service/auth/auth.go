@@ -4,7 +4,9 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"time"
 
+	"github.com/kirillidk/pvz-service/internal/config"
 	"github.com/kirillidk/pvz-service/internal/dto"
 	"github.com/kirillidk/pvz-service/internal/model"
 	"github.com/kirillidk/pvz-service/internal/repository"
@@ -13,18 +15,23 @@ import (
 
 type AuthServiceInterface interface {
 	Register(ctx context.Context, registerReq dto.RegisterRequest) (*model.User, error)
-	Login(ctx context.Context, loginReq dto.LoginRequest) (string, error)
+	Login(ctx context.Context, loginReq dto.LoginRequest) (*dto.LoginResponse, error)
+	VerifyTwoFactor(ctx context.Context, twoFactorReq dto.TwoFactorLoginRequest) (string, error)
+	EnrollTwoFactor(ctx context.Context, userID string) (*dto.TwoFactorEnrollResponse, error)
+	ConfirmTwoFactor(ctx context.Context, userID string, code string) (string, error)
 }
 
 type AuthService struct {
-	userRepository repository.UserRepositoryInterface
-	jwtSecret      string
+	userRepository  repository.UserRepositoryInterface
+	jwtSecret       string
+	twoFactorConfig config.TwoFactorConfig
 }
 
-func NewAuthService(userRepo repository.UserRepositoryInterface, jwtSecret string) *AuthService {
+func NewAuthService(userRepo repository.UserRepositoryInterface, jwtSecret string, twoFactorCfg config.TwoFactorConfig) *AuthService {
 	return &AuthService{
-		userRepository: userRepo,
-		jwtSecret:      jwtSecret,
+		userRepository:  userRepo,
+		jwtSecret:       jwtSecret,
+		twoFactorConfig: twoFactorCfg,
 	}
 }
 
@@ -46,21 +53,204 @@ func (s *AuthService) Register(ctx context.Context, registerReq dto.RegisterRequ
 	return user, nil
 }
 
-func (s *AuthService) Login(ctx context.Context, loginReq dto.LoginRequest) (string, error) {
+func (s *AuthService) Login(ctx context.Context, loginReq dto.LoginRequest) (*dto.LoginResponse, error) {
 	user, passwordHash, err := s.userRepository.FindUserByEmail(ctx, loginReq.Email)
 	if err != nil {
-		return "", errors.New("invalid email or password")
+		return nil, errors.New("invalid email or password")
 	}
 
 	err = bcrypt.CompareHashAndPassword([]byte(passwordHash), []byte(loginReq.Password))
 	if err != nil {
-		return "", errors.New("invalid email or password")
+		return nil, errors.New("invalid email or password")
 	}
 
-	token, err := GenerateToken(user.Role, s.jwtSecret)
+	if user.TwoFactorEnabled {
+		mfaToken, err := GenerateMFAToken(user.ID, user.Role, PurposeMFAVerify, s.jwtSecret)
+		if err != nil {
+			return nil, fmt.Errorf("failed to generate token: %w", err)
+		}
+
+		return &dto.LoginResponse{MFARequired: true, MFAToken: mfaToken}, nil
+	}
+
+	if user.Role == model.ModeratorRole && s.twoFactorConfig.RequiredForModerators {
+		mfaToken, err := GenerateMFAToken(user.ID, user.Role, PurposeMFAEnroll, s.jwtSecret)
+		if err != nil {
+			return nil, fmt.Errorf("failed to generate token: %w", err)
+		}
+
+		return &dto.LoginResponse{MFAEnrollmentRequired: true, MFAToken: mfaToken}, nil
+	}
+
+	token, err := GenerateUserToken(user.ID, user.Role, s.jwtSecret)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate token: %w", err)
+	}
+
+	return &dto.LoginResponse{Token: token}, nil
+}
+
+func (s *AuthService) VerifyTwoFactor(ctx context.Context, twoFactorReq dto.TwoFactorLoginRequest) (string, error) {
+	claims, err := ValidateMFAToken(twoFactorReq.MFAToken, s.jwtSecret, PurposeMFAVerify)
+	if err != nil {
+		return "", errors.New("invalid or expired MFA token")
+	}
+
+	user, err := s.userRepository.FindUserByID(ctx, claims.UserID)
+	if err != nil || !user.TwoFactorEnabled {
+		return "", errors.New("invalid or expired MFA token")
+	}
+
+	if twoFactorLocked(user) {
+		return "", model.ErrTwoFactorLocked
+	}
+
+	ok, err := s.checkSecondFactor(ctx, user.ID, twoFactorReq.Code)
+	if err != nil {
+		return "", err
+	}
+
+	if err := s.recordSecondFactorResult(ctx, user.ID, ok); err != nil {
+		return "", err
+	}
+
+	token, err := GenerateUserToken(user.ID, user.Role, s.jwtSecret)
 	if err != nil {
 		return "", fmt.Errorf("failed to generate token: %w", err)
 	}
 
 	return token, nil
 }
+
+func (s *AuthService) EnrollTwoFactor(ctx context.Context, userID string) (*dto.TwoFactorEnrollResponse, error) {
+	user, err := s.userRepository.FindUserByID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find user: %w", err)
+	}
+
+	if user.TwoFactorEnabled {
+		return nil, errors.New("two-factor authentication is already enabled")
+	}
+
+	secret, err := GenerateTOTPSecret()
+	if err != nil {
+		return nil, err
+	}
+
+	recoveryCodes, err := GenerateRecoveryCodes()
+	if err != nil {
+		return nil, err
+	}
+
+	recoveryCodeHashes := make([]string, 0, len(recoveryCodes))
+	for _, code := range recoveryCodes {
+		recoveryCodeHashes = append(recoveryCodeHashes, HashRecoveryCode(code))
+	}
+
+	if err := s.userRepository.SetTOTPSecret(ctx, user.ID, secret, recoveryCodeHashes); err != nil {
+		return nil, fmt.Errorf("failed to store two-factor secret: %w", err)
+	}
+
+	return &dto.TwoFactorEnrollResponse{
+		Secret:          secret,
+		ProvisioningURI: TOTPProvisioningURI(s.twoFactorConfig.Issuer, user.Email, secret),
+		RecoveryCodes:   recoveryCodes,
+	}, nil
+}
+
+func (s *AuthService) ConfirmTwoFactor(ctx context.Context, userID string, code string) (string, error) {
+	user, err := s.userRepository.FindUserByID(ctx, userID)
+	if err != nil {
+		return "", fmt.Errorf("failed to find user: %w", err)
+	}
+
+	if user.TwoFactorEnabled {
+		return "", errors.New("two-factor authentication is already enabled")
+	}
+
+	if twoFactorLocked(user) {
+		return "", model.ErrTwoFactorLocked
+	}
+
+	secret, err := s.userRepository.GetTOTPSecret(ctx, user.ID)
+	if err != nil {
+		return "", fmt.Errorf("failed to get two-factor secret: %w", err)
+	}
+
+	ok, err := s.acceptTOTPCode(ctx, user.ID, secret, code)
+	if err != nil {
+		return "", err
+	}
+
+	if err := s.recordSecondFactorResult(ctx, user.ID, ok); err != nil {
+		return "", err
+	}
+
+	if err := s.userRepository.EnableTOTP(ctx, user.ID); err != nil {
+		return "", fmt.Errorf("failed to enable two-factor authentication: %w", err)
+	}
+
+	token, err := GenerateUserToken(user.ID, user.Role, s.jwtSecret)
+	if err != nil {
+		return "", fmt.Errorf("failed to generate token: %w", err)
+	}
+
+	return token, nil
+}
+
+// checkSecondFactor accepts either a current TOTP code or an unused recovery code.
+func (s *AuthService) checkSecondFactor(ctx context.Context, userID string, code string) (bool, error) {
+	secret, err := s.userRepository.GetTOTPSecret(ctx, userID)
+	if err != nil {
+		return false, fmt.Errorf("failed to get two-factor secret: %w", err)
+	}
+
+	if _, ok := MatchTOTPStep(secret, code, time.Now()); ok {
+		return s.acceptTOTPCode(ctx, userID, secret, code)
+	}
+
+	used, err := s.userRepository.UseRecoveryCode(ctx, userID, HashRecoveryCode(code))
+	if err != nil {
+		return false, fmt.Errorf("failed to check recovery code: %w", err)
+	}
+
+	return used, nil
+}
+
+// acceptTOTPCode accepts a current TOTP code whose time step has not been
+// used yet, so that an intercepted code cannot be replayed.
+func (s *AuthService) acceptTOTPCode(ctx context.Context, userID, secret, code string) (bool, error) {
+	step, ok := MatchTOTPStep(secret, code, time.Now())
+	if !ok {
+		return false, nil
+	}
+
+	accepted, err := s.userRepository.AcceptTOTPStep(ctx, userID, step)
+	if err != nil {
+		return false, fmt.Errorf("failed to accept two-factor code: %w", err)
+	}
+
+	return accepted, nil
+}
+
+// recordSecondFactorResult counts wrong codes towards the lockout and
+// starts the count over after a correct one.
+func (s *AuthService) recordSecondFactorResult(ctx context.Context, userID string, ok bool) error {
+	if ok {
+		if err := s.userRepository.ResetTwoFactorFailures(ctx, userID); err != nil {
+			return fmt.Errorf("failed to reset two-factor failures: %w", err)
+		}
+		return nil
+	}
+
+	lockedUntil := time.Now().UTC().Add(s.twoFactorConfig.LockoutDuration)
+	if err := s.userRepository.RecordTwoFactorFailure(ctx, userID, s.twoFactorConfig.MaxAttempts, lockedUntil); err != nil {
+		return fmt.Errorf("failed to record two-factor failure: %w", err)
+	}
+
+	return errors.New("invalid two-factor code")
+}
+
+func twoFactorLocked(user *model.User) bool {
+	return user.TwoFactorLockedUntil != nil && time.Now().Before(*user.TwoFactorLockedUntil)
+}
@@ -4,8 +4,11 @@ import (
 	"context"
 	"errors"
 	"reflect"
+	"strings"
 	"testing"
+	"time"
 
+	"github.com/kirillidk/pvz-service/internal/config"
 	"github.com/kirillidk/pvz-service/internal/dto"
 	"github.com/kirillidk/pvz-service/internal/model"
 	"github.com/kirillidk/pvz-service/internal/service/auth"
@@ -16,6 +19,14 @@ type MockUserRepository struct {
 	CreateUserFunc      func(ctx context.Context, req dto.RegisterRequest) (*model.User, error)
 	FindUserByEmailFunc func(ctx context.Context, email string) (*model.User, string, error)
 	UserExistsFunc      func(ctx context.Context, email string) (bool, error)
+	FindUserByIDFunc    func(ctx context.Context, userID string) (*model.User, error)
+	GetTOTPSecretFunc   func(ctx context.Context, userID string) (string, error)
+	SetTOTPSecretFunc   func(ctx context.Context, userID string, secret string, recoveryCodeHashes []string) error
+	EnableTOTPFunc      func(ctx context.Context, userID string) error
+	UseRecoveryCodeFunc func(ctx context.Context, userID string, codeHash string) (bool, error)
+
+	AcceptTOTPStepFunc         func(ctx context.Context, userID string, step int64) (bool, error)
+	RecordTwoFactorFailureFunc func(ctx context.Context, userID string, maxAttempts int, lockedUntil time.Time) error
 }
 
 func (m *MockUserRepository) CreateUser(ctx context.Context, req dto.RegisterRequest) (*model.User, error) {
@@ -30,6 +41,44 @@ func (m *MockUserRepository) UserExists(ctx context.Context, email string) (bool
 	return m.UserExistsFunc(ctx, email)
 }
 
+func (m *MockUserRepository) FindUserByID(ctx context.Context, userID string) (*model.User, error) {
+	return m.FindUserByIDFunc(ctx, userID)
+}
+
+func (m *MockUserRepository) GetTOTPSecret(ctx context.Context, userID string) (string, error) {
+	return m.GetTOTPSecretFunc(ctx, userID)
+}
+
+func (m *MockUserRepository) SetTOTPSecret(ctx context.Context, userID string, secret string, recoveryCodeHashes []string) error {
+	return m.SetTOTPSecretFunc(ctx, userID, secret, recoveryCodeHashes)
+}
+
+func (m *MockUserRepository) EnableTOTP(ctx context.Context, userID string) error {
+	return m.EnableTOTPFunc(ctx, userID)
+}
+
+func (m *MockUserRepository) UseRecoveryCode(ctx context.Context, userID string, codeHash string) (bool, error) {
+	return m.UseRecoveryCodeFunc(ctx, userID, codeHash)
+}
+
+func (m *MockUserRepository) AcceptTOTPStep(ctx context.Context, userID string, step int64) (bool, error) {
+	if m.AcceptTOTPStepFunc == nil {
+		return true, nil
+	}
+	return m.AcceptTOTPStepFunc(ctx, userID, step)
+}
+
+func (m *MockUserRepository) RecordTwoFactorFailure(ctx context.Context, userID string, maxAttempts int, lockedUntil time.Time) error {
+	if m.RecordTwoFactorFailureFunc == nil {
+		return nil
+	}
+	return m.RecordTwoFactorFailureFunc(ctx, userID, maxAttempts, lockedUntil)
+}
+
+func (m *MockUserRepository) ResetTwoFactorFailures(ctx context.Context, userID string) error {
+	return nil
+}
+
 func TestAuthService_Register(t *testing.T) {
 	tests := []struct {
 		name          string
@@ -101,7 +150,7 @@ func TestAuthService_Register(t *testing.T) {
 
 	for tNum, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			s := auth.NewAuthService(tt.mockRepo, "secret")
+			s := auth.NewAuthService(tt.mockRepo, "secret", config.TwoFactorConfig{})
 			got, err := s.Register(context.Background(), tt.input)
 
 			if (err != nil) != tt.expectedError {
@@ -126,6 +175,8 @@ func TestAuthService_Login(t *testing.T) {
 		mockRepo      *MockUserRepository
 		input         dto.LoginRequest
 		jwtSecret     string
+		twoFactorCfg  config.TwoFactorConfig
+		expected      dto.LoginResponse
 		expectedError bool
 	}{
 		{
@@ -144,6 +195,47 @@ func TestAuthService_Login(t *testing.T) {
 				Password: "password123",
 			},
 			jwtSecret:     "test-secret",
+			expected:      dto.LoginResponse{Token: "non-empty"},
+			expectedError: false,
+		},
+		{
+			name: "Two-Factor Enabled",
+			mockRepo: &MockUserRepository{
+				FindUserByEmailFunc: func(ctx context.Context, email string) (*model.User, string, error) {
+					return &model.User{
+						ID:               "123e4567-e89b-12d3-a456-426614174000",
+						Email:            email,
+						Role:             model.ModeratorRole,
+						TwoFactorEnabled: true,
+					}, string(validPasswordHash), nil
+				},
+			},
+			input: dto.LoginRequest{
+				Email:    "test@example.com",
+				Password: "password123",
+			},
+			jwtSecret:     "test-secret",
+			expected:      dto.LoginResponse{MFARequired: true, MFAToken: "non-empty"},
+			expectedError: false,
+		},
+		{
+			name: "Two-Factor Mandatory For Moderators",
+			mockRepo: &MockUserRepository{
+				FindUserByEmailFunc: func(ctx context.Context, email string) (*model.User, string, error) {
+					return &model.User{
+						ID:    "123e4567-e89b-12d3-a456-426614174000",
+						Email: email,
+						Role:  model.ModeratorRole,
+					}, string(validPasswordHash), nil
+				},
+			},
+			input: dto.LoginRequest{
+				Email:    "test@example.com",
+				Password: "password123",
+			},
+			jwtSecret:     "test-secret",
+			twoFactorCfg:  config.TwoFactorConfig{RequiredForModerators: true},
+			expected:      dto.LoginResponse{MFAEnrollmentRequired: true, MFAToken: "non-empty"},
 			expectedError: false,
 		},
 		{
@@ -182,12 +274,206 @@ func TestAuthService_Login(t *testing.T) {
 
 	for ttNum, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			s := auth.NewAuthService(tt.mockRepo, tt.jwtSecret)
-			_, err := s.Login(context.Background(), tt.input)
+			s := auth.NewAuthService(tt.mockRepo, tt.jwtSecret, tt.twoFactorCfg)
+			got, err := s.Login(context.Background(), tt.input)
 
 			if (err != nil) != tt.expectedError {
 				t.Errorf("Test %v: AuthService.Login() error = %v, expectedError %v", ttNum, err, tt.expectedError)
+				return
+			}
+
+			if tt.expectedError {
+				return
+			}
+
+			if (got.Token != "") != (tt.expected.Token != "") ||
+				(got.MFAToken != "") != (tt.expected.MFAToken != "") ||
+				got.MFARequired != tt.expected.MFARequired ||
+				got.MFAEnrollmentRequired != tt.expected.MFAEnrollmentRequired {
+				t.Errorf("Test %v: AuthService.Login() = %+v, expected shape %+v", ttNum, got, tt.expected)
 			}
 		})
 	}
 }
+
+func TestAuthService_VerifyTwoFactor(t *testing.T) {
+	secret, err := auth.GenerateTOTPSecret()
+	if err != nil {
+		t.Fatalf("failed to generate secret: %v", err)
+	}
+
+	validCode, err := auth.GenerateTOTPCode(secret, time.Now())
+	if err != nil {
+		t.Fatalf("failed to generate code: %v", err)
+	}
+
+	userID := "123e4567-e89b-12d3-a456-426614174000"
+	verifyToken, _ := auth.GenerateMFAToken(userID, model.ModeratorRole, auth.PurposeMFAVerify, "test-secret")
+	enrollToken, _ := auth.GenerateMFAToken(userID, model.ModeratorRole, auth.PurposeMFAEnroll, "test-secret")
+
+	newMockRepo := func(recoveryCodeValid bool) *MockUserRepository {
+		return &MockUserRepository{
+			FindUserByIDFunc: func(ctx context.Context, id string) (*model.User, error) {
+				return &model.User{ID: id, Role: model.ModeratorRole, TwoFactorEnabled: true}, nil
+			},
+			GetTOTPSecretFunc: func(ctx context.Context, id string) (string, error) {
+				return secret, nil
+			},
+			UseRecoveryCodeFunc: func(ctx context.Context, id string, codeHash string) (bool, error) {
+				return recoveryCodeValid, nil
+			},
+		}
+	}
+
+	tests := []struct {
+		name          string
+		mockRepo      *MockUserRepository
+		input         dto.TwoFactorLoginRequest
+		expectedError bool
+	}{
+		{
+			name:          "Valid TOTP Code",
+			mockRepo:      newMockRepo(false),
+			input:         dto.TwoFactorLoginRequest{MFAToken: verifyToken, Code: validCode},
+			expectedError: false,
+		},
+		{
+			name:          "Valid Recovery Code",
+			mockRepo:      newMockRepo(true),
+			input:         dto.TwoFactorLoginRequest{MFAToken: verifyToken, Code: "abcde-12345"},
+			expectedError: false,
+		},
+		{
+			name:          "Invalid Code",
+			mockRepo:      newMockRepo(false),
+			input:         dto.TwoFactorLoginRequest{MFAToken: verifyToken, Code: "abcde-12345"},
+			expectedError: true,
+		},
+		{
+			name:          "Enrollment Token Rejected",
+			mockRepo:      newMockRepo(false),
+			input:         dto.TwoFactorLoginRequest{MFAToken: enrollToken, Code: validCode},
+			expectedError: true,
+		},
+		{
+			name: "Replayed TOTP Code",
+			mockRepo: func() *MockUserRepository {
+				repo := newMockRepo(false)
+				repo.AcceptTOTPStepFunc = func(ctx context.Context, id string, step int64) (bool, error) {
+					return false, nil
+				}
+				return repo
+			}(),
+			input:         dto.TwoFactorLoginRequest{MFAToken: verifyToken, Code: validCode},
+			expectedError: true,
+		},
+		{
+			name: "Locked",
+			mockRepo: func() *MockUserRepository {
+				repo := newMockRepo(false)
+				lockedUntil := time.Now().Add(time.Minute)
+				repo.FindUserByIDFunc = func(ctx context.Context, id string) (*model.User, error) {
+					return &model.User{ID: id, Role: model.ModeratorRole, TwoFactorEnabled: true, TwoFactorLockedUntil: &lockedUntil}, nil
+				}
+				return repo
+			}(),
+			input:         dto.TwoFactorLoginRequest{MFAToken: verifyToken, Code: validCode},
+			expectedError: true,
+		},
+	}
+
+	for ttNum, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := auth.NewAuthService(tt.mockRepo, "test-secret", config.TwoFactorConfig{})
+			token, err := s.VerifyTwoFactor(context.Background(), tt.input)
+
+			if (err != nil) != tt.expectedError {
+				t.Errorf("Test %v: AuthService.VerifyTwoFactor() error = %v, expectedError %v", ttNum, err, tt.expectedError)
+				return
+			}
+
+			if !tt.expectedError {
+				claims, err := auth.ValidateToken(token, "test-secret")
+				if err != nil {
+					t.Errorf("Test %v: issued token is not a valid access token: %v", ttNum, err)
+					return
+				}
+				if claims.UserID != userID {
+					t.Errorf("Test %v: token user ID = %v, expected %v", ttNum, claims.UserID, userID)
+				}
+			}
+		})
+	}
+}
+
+func TestAuthService_VerifyTwoFactor_CountsFailures(t *testing.T) {
+	userID := "123e4567-e89b-12d3-a456-426614174000"
+	verifyToken, _ := auth.GenerateMFAToken(userID, model.ModeratorRole, auth.PurposeMFAVerify, "test-secret")
+
+	var (
+		gotMaxAttempts int
+		gotLockedUntil time.Time
+	)
+	mockRepo := &MockUserRepository{
+		FindUserByIDFunc: func(ctx context.Context, id string) (*model.User, error) {
+			return &model.User{ID: id, Role: model.ModeratorRole, TwoFactorEnabled: true}, nil
+		},
+		GetTOTPSecretFunc: func(ctx context.Context, id string) (string, error) {
+			return auth.GenerateTOTPSecret()
+		},
+		UseRecoveryCodeFunc: func(ctx context.Context, id string, codeHash string) (bool, error) {
+			return false, nil
+		},
+		RecordTwoFactorFailureFunc: func(ctx context.Context, id string, maxAttempts int, lockedUntil time.Time) error {
+			gotMaxAttempts = maxAttempts
+			gotLockedUntil = lockedUntil
+			return nil
+		},
+	}
+
+	s := auth.NewAuthService(mockRepo, "test-secret", config.TwoFactorConfig{MaxAttempts: 5, LockoutDuration: 15 * time.Minute})
+	if _, err := s.VerifyTwoFactor(context.Background(), dto.TwoFactorLoginRequest{MFAToken: verifyToken, Code: "abcde-12345"}); err == nil {
+		t.Fatal("AuthService.VerifyTwoFactor() expected an error for a wrong code")
+	}
+
+	if gotMaxAttempts != 5 {
+		t.Errorf("Expected the failure to be counted against 5 attempts, got %d", gotMaxAttempts)
+	}
+	if time.Until(gotLockedUntil) < 14*time.Minute {
+		t.Errorf("Expected a lockout of 15 minutes, got until %v", gotLockedUntil)
+	}
+}
+
+func TestAuthService_EnrollTwoFactor(t *testing.T) {
+	var storedHashes []string
+
+	mockRepo := &MockUserRepository{
+		FindUserByIDFunc: func(ctx context.Context, id string) (*model.User, error) {
+			return &model.User{ID: id, Email: "moderator@example.com", Role: model.ModeratorRole}, nil
+		},
+		SetTOTPSecretFunc: func(ctx context.Context, id string, secret string, recoveryCodeHashes []string) error {
+			storedHashes = recoveryCodeHashes
+			return nil
+		},
+	}
+
+	s := auth.NewAuthService(mockRepo, "test-secret", config.TwoFactorConfig{Issuer: "PVZ Service"})
+	got, err := s.EnrollTwoFactor(context.Background(), "123e4567-e89b-12d3-a456-426614174000")
+	if err != nil {
+		t.Fatalf("AuthService.EnrollTwoFactor() error = %v", err)
+	}
+
+	if !strings.HasPrefix(got.ProvisioningURI, "otpauth://totp/") || !strings.Contains(got.ProvisioningURI, "secret="+got.Secret) {
+		t.Errorf("AuthService.EnrollTwoFactor() unexpected provisioning URI %q", got.ProvisioningURI)
+	}
+
+	if len(got.RecoveryCodes) == 0 || len(got.RecoveryCodes) != len(storedHashes) {
+		t.Fatalf("expected %d recovery code hashes to be stored, got %d", len(got.RecoveryCodes), len(storedHashes))
+	}
+
+	for i, code := range got.RecoveryCodes {
+		if storedHashes[i] != auth.HashRecoveryCode(code) || storedHashes[i] == code {
+			t.Errorf("recovery code %d is not stored hashed", i)
+		}
+	}
+}
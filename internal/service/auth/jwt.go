@@ -2,25 +2,52 @@ package auth
 
 import (
 	"fmt"
+	"slices"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
 	"github.com/kirillidk/pvz-service/internal/model"
 )
 
+type TokenPurpose string
+
+const (
+	// PurposeMFAVerify marks a token issued after a correct password for a user
+	// with 2FA enabled; it can only be exchanged for an access token at /login/2fa.
+	PurposeMFAVerify TokenPurpose = "mfa_verify"
+	// PurposeMFAEnroll marks a token issued to a user who must enroll in 2FA
+	// before getting an access token.
+	PurposeMFAEnroll TokenPurpose = "mfa_enroll"
+
+	accessTokenTTL = 24 * time.Hour
+	mfaTokenTTL    = 5 * time.Minute
+)
+
 type Claims struct {
-	Role model.UserRole `json:"role" binding:"required,oneof=employee moderator"`
+	Role    model.UserRole `json:"role" binding:"required,oneof=employee moderator"`
+	UserID  string         `json:"userId,omitempty"`
+	Purpose TokenPurpose   `json:"purpose,omitempty"`
 	jwt.RegisteredClaims
 }
 
 func GenerateToken(role model.UserRole, secret string) (string, error) {
-	claims := Claims{
-		Role: role,
-		RegisteredClaims: jwt.RegisteredClaims{
-			ExpiresAt: jwt.NewNumericDate(time.Now().Add(24 * time.Hour)),
-			IssuedAt:  jwt.NewNumericDate(time.Now()),
-			NotBefore: jwt.NewNumericDate(time.Now()),
-		},
+	return GenerateUserToken("", role, secret)
+}
+
+func GenerateUserToken(userID string, role model.UserRole, secret string) (string, error) {
+	return signToken(Claims{Role: role, UserID: userID}, accessTokenTTL, secret)
+}
+
+func GenerateMFAToken(userID string, role model.UserRole, purpose TokenPurpose, secret string) (string, error) {
+	return signToken(Claims{Role: role, UserID: userID, Purpose: purpose}, mfaTokenTTL, secret)
+}
+
+func signToken(claims Claims, ttl time.Duration, secret string) (string, error) {
+	now := time.Now()
+	claims.RegisteredClaims = jwt.RegisteredClaims{
+		ExpiresAt: jwt.NewNumericDate(now.Add(ttl)),
+		IssuedAt:  jwt.NewNumericDate(now),
+		NotBefore: jwt.NewNumericDate(now),
 	}
 
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
@@ -32,7 +59,35 @@ func GenerateToken(role model.UserRole, secret string) (string, error) {
 	return signedToken, nil
 }
 
+// ValidateToken accepts only access tokens; MFA tokens are rejected.
 func ValidateToken(tokenString, secret string) (*Claims, error) {
+	claims, err := parseToken(tokenString, secret)
+	if err != nil {
+		return nil, err
+	}
+
+	if claims.Purpose != "" {
+		return nil, fmt.Errorf("token is not an access token")
+	}
+
+	return claims, nil
+}
+
+// ValidateMFAToken accepts only MFA tokens issued for one of the given purposes.
+func ValidateMFAToken(tokenString, secret string, purposes ...TokenPurpose) (*Claims, error) {
+	claims, err := parseToken(tokenString, secret)
+	if err != nil {
+		return nil, err
+	}
+
+	if !slices.Contains(purposes, claims.Purpose) || claims.UserID == "" {
+		return nil, fmt.Errorf("token is not valid for this operation")
+	}
+
+	return claims, nil
+}
+
+func parseToken(tokenString, secret string) (*Claims, error) {
 	claims := &Claims{}
 
 	token, err := jwt.ParseWithClaims(tokenString, claims, func(token *jwt.Token) (any, error) {
@@ -124,3 +124,27 @@ func TestValidateToken_InvalidToken(t *testing.T) {
 		})
 	}
 }
+
+func TestMFATokenIsNotAccessToken(t *testing.T) {
+	mfaToken, err := auth.GenerateMFAToken("user-id", model.ModeratorRole, auth.PurposeMFAVerify, "test-secret")
+	if err != nil {
+		t.Fatalf("GenerateMFAToken() error = %v", err)
+	}
+
+	if _, err := auth.ValidateToken(mfaToken, "test-secret"); err == nil {
+		t.Errorf("ValidateToken() accepted an MFA token")
+	}
+
+	if _, err := auth.ValidateMFAToken(mfaToken, "test-secret", auth.PurposeMFAEnroll); err == nil {
+		t.Errorf("ValidateMFAToken() accepted a token issued for another purpose")
+	}
+
+	claims, err := auth.ValidateMFAToken(mfaToken, "test-secret", auth.PurposeMFAVerify)
+	if err != nil {
+		t.Fatalf("ValidateMFAToken() error = %v", err)
+	}
+
+	if claims.UserID != "user-id" {
+		t.Errorf("ValidateMFAToken() user ID = %v, expected user-id", claims.UserID)
+	}
+}
@@ -0,0 +1,97 @@
+package auth_test
+
+import (
+	"encoding/base32"
+	"testing"
+	"time"
+
+	"github.com/kirillidk/pvz-service/internal/service/auth"
+)
+
+func TestGenerateTOTPCode_RFC6238(t *testing.T) {
+	secret := base32.StdEncoding.WithPadding(base32.NoPadding).EncodeToString([]byte("12345678901234567890"))
+
+	tests := []struct {
+		unix     int64
+		expected string
+	}{
+		{unix: 59, expected: "287082"},
+		{unix: 1111111109, expected: "081804"},
+		{unix: 1234567890, expected: "005924"},
+		{unix: 2000000000, expected: "279037"},
+	}
+
+	for _, tt := range tests {
+		code, err := auth.GenerateTOTPCode(secret, time.Unix(tt.unix, 0))
+		if err != nil {
+			t.Fatalf("GenerateTOTPCode() error = %v", err)
+		}
+
+		if code != tt.expected {
+			t.Errorf("GenerateTOTPCode(%d) = %s, expected %s", tt.unix, code, tt.expected)
+		}
+	}
+}
+
+func TestValidateTOTPCode(t *testing.T) {
+	secret, err := auth.GenerateTOTPSecret()
+	if err != nil {
+		t.Fatalf("GenerateTOTPSecret() error = %v", err)
+	}
+
+	now := time.Now()
+	current, _ := auth.GenerateTOTPCode(secret, now)
+	previous, _ := auth.GenerateTOTPCode(secret, now.Add(-30*time.Second))
+
+	tests := []struct {
+		name     string
+		code     string
+		expected bool
+	}{
+		{name: "Current Code", code: current, expected: true},
+		{name: "Previous Period", code: previous, expected: true},
+		{name: "Wrong Length", code: "12345", expected: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := auth.ValidateTOTPCode(secret, tt.code, now); got != tt.expected {
+				t.Errorf("ValidateTOTPCode() = %v, expected %v", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestMatchTOTPStep(t *testing.T) {
+	secret, err := auth.GenerateTOTPSecret()
+	if err != nil {
+		t.Fatalf("GenerateTOTPSecret() error = %v", err)
+	}
+
+	now := time.Unix(1700000000, 0)
+	previous, _ := auth.GenerateTOTPCode(secret, now.Add(-30*time.Second))
+
+	step, ok := auth.MatchTOTPStep(secret, previous, now)
+	if !ok || step != now.Unix()/30-1 {
+		t.Errorf("MatchTOTPStep() = %d, %v, expected %d, true", step, ok, now.Unix()/30-1)
+	}
+}
+
+func TestRecoveryCodes(t *testing.T) {
+	codes, err := auth.GenerateRecoveryCodes()
+	if err != nil {
+		t.Fatalf("GenerateRecoveryCodes() error = %v", err)
+	}
+
+	seen := make(map[string]struct{}, len(codes))
+	for _, code := range codes {
+		if _, ok := seen[code]; ok {
+			t.Errorf("duplicate recovery code %s", code)
+		}
+		seen[code] = struct{}{}
+	}
+
+	if auth.HashRecoveryCode("ABCDE-12345") != auth.HashRecoveryCode("abcde12345") {
+		t.Errorf("HashRecoveryCode() must ignore case and dashes")
+	}
+}
@@ -0,0 +1,74 @@
+// Package catalogcache keeps small reference catalogs in memory.
+package catalogcache
+
+import (
+	"context"
+	"sync"
+)
+
+// Cache holds a catalog keyed by string. The catalog is loaded lazily and
+// dropped by Invalidate after every change.
+//
+// The cache is process-local: Invalidate only affects the instance it is
+// called on, so changes made by another instance of the service or directly
+// in the database are not seen until this instance changes the catalog
+// itself or restarts.
+type Cache[V any] struct {
+	load func(ctx context.Context) (map[string]V, error)
+
+	mu         sync.RWMutex
+	items      map[string]V
+	generation uint64
+}
+
+// New returns a cache filled by load on first use.
+func New[V any](load func(ctx context.Context) (map[string]V, error)) *Cache[V] {
+	return &Cache[V]{load: load}
+}
+
+// Get returns the catalog entry stored under key.
+func (c *Cache[V]) Get(ctx context.Context, key string) (V, bool, error) {
+	c.mu.RLock()
+	items := c.items
+	c.mu.RUnlock()
+
+	if items == nil {
+		var err error
+		items, err = c.reload(ctx)
+		if err != nil {
+			var zero V
+			return zero, false, err
+		}
+	}
+
+	item, ok := items[key]
+	return item, ok, nil
+}
+
+// Invalidate drops the cached catalog so that the next Get reloads it.
+func (c *Cache[V]) Invalidate() {
+	c.mu.Lock()
+	c.items = nil
+	c.generation++
+	c.mu.Unlock()
+}
+
+func (c *Cache[V]) reload(ctx context.Context) (map[string]V, error) {
+	c.mu.RLock()
+	generation := c.generation
+	c.mu.RUnlock()
+
+	items, err := c.load(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// Do not cache a catalog loaded concurrently with a change.
+	c.mu.Lock()
+	if c.generation == generation {
+		c.items = items
+	}
+	c.mu.Unlock()
+
+	return items, nil
+}
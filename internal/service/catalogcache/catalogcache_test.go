@@ -0,0 +1,94 @@
+package catalogcache_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/kirillidk/pvz-service/internal/service/catalogcache"
+)
+
+func TestCache_Get(t *testing.T) {
+	loads := 0
+	catalog := map[string]int{"a": 1}
+	cache := catalogcache.New(func(ctx context.Context) (map[string]int, error) {
+		loads++
+		result := make(map[string]int, len(catalog))
+		for k, v := range catalog {
+			result[k] = v
+		}
+		return result, nil
+	})
+
+	value, ok, err := cache.Get(context.Background(), "a")
+	if err != nil || !ok || value != 1 {
+		t.Errorf("Get(a) = %d, %v, %v, want 1, true, nil", value, ok, err)
+	}
+
+	if _, ok, _ := cache.Get(context.Background(), "b"); ok {
+		t.Errorf("Get(b) found an entry missing from the catalog")
+	}
+	if loads != 1 {
+		t.Errorf("loads = %d, want 1", loads)
+	}
+
+	catalog["b"] = 2
+	cache.Invalidate()
+
+	value, ok, err = cache.Get(context.Background(), "b")
+	if err != nil || !ok || value != 2 {
+		t.Errorf("Get(b) after Invalidate = %d, %v, %v, want 2, true, nil", value, ok, err)
+	}
+	if loads != 2 {
+		t.Errorf("loads = %d, want 2", loads)
+	}
+}
+
+func TestCache_InvalidateDuringLoad(t *testing.T) {
+	loads := 0
+	var cache *catalogcache.Cache[int]
+	cache = catalogcache.New(func(ctx context.Context) (map[string]int, error) {
+		loads++
+		if loads == 1 {
+			// A change made while the catalog is being read.
+			cache.Invalidate()
+		}
+		return map[string]int{"a": loads}, nil
+	})
+
+	if value, _, _ := cache.Get(context.Background(), "a"); value != 1 {
+		t.Errorf("first Get = %d, want 1", value)
+	}
+	if value, _, _ := cache.Get(context.Background(), "a"); value != 2 {
+		t.Errorf("second Get = %d, want 2, stale catalog was cached", value)
+	}
+}
+
+func TestCache_LoadError(t *testing.T) {
+	loadErr := errors.New("database error")
+	cache := catalogcache.New(func(ctx context.Context) (map[string]int, error) {
+		return nil, loadErr
+	})
+
+	if _, _, err := cache.Get(context.Background(), "a"); !errors.Is(err, loadErr) {
+		t.Errorf("Get error = %v, want %v", err, loadErr)
+	}
+}
+
+func TestLocations_Get(t *testing.T) {
+	var locations catalogcache.Locations
+
+	location, err := locations.Get("Europe/Moscow")
+	if err != nil || location.String() != "Europe/Moscow" {
+		t.Errorf("Get(Europe/Moscow) = %v, %v, want Europe/Moscow, nil", location, err)
+	}
+
+	cached, err := locations.Get("Europe/Moscow")
+	if err != nil || cached != location {
+		t.Errorf("Get(Europe/Moscow) again = %p, %v, want the cached %p", cached, err, location)
+	}
+
+	if _, err := locations.Get("Mars/Olympus"); err == nil {
+		t.Errorf("Get(Mars/Olympus) error = nil, want an error")
+	}
+}
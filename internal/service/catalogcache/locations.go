@@ -0,0 +1,27 @@
+package catalogcache
+
+import (
+	"sync"
+	"time"
+)
+
+// Locations caches time zone locations by name, since loading one parses
+// the time zone database every time. The zero value is ready to use.
+type Locations struct {
+	locations sync.Map
+}
+
+// Get returns the location of the named time zone.
+func (l *Locations) Get(name string) (*time.Location, error) {
+	if location, ok := l.locations.Load(name); ok {
+		return location.(*time.Location), nil
+	}
+
+	location, err := time.LoadLocation(name)
+	if err != nil {
+		return nil, err
+	}
+
+	l.locations.Store(name, location)
+	return location, nil
+}
@@ -0,0 +1,131 @@
+package city
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/kirillidk/pvz-service/internal/dto"
+	"github.com/kirillidk/pvz-service/internal/model"
+	"github.com/kirillidk/pvz-service/internal/repository"
+	"github.com/kirillidk/pvz-service/internal/service/catalogcache"
+)
+
+type CityServiceInterface interface {
+	CreateCity(ctx context.Context, cityReq dto.CityCreateRequest) (*model.City, error)
+	GetCityList(ctx context.Context) ([]model.City, error)
+	GetCityByID(ctx context.Context, cityID string) (*model.City, error)
+	UpdateCity(ctx context.Context, cityID string, cityReq dto.CityUpdateRequest) (*model.City, error)
+	DeleteCity(ctx context.Context, cityID string) error
+	IsCityAvailable(ctx context.Context, name string) (bool, error)
+}
+
+// CityService keeps the names of active cities in a process-local cache so
+// that PVZ creation does not hit the database. The cache is dropped after
+// every change made through the service.
+type CityService struct {
+	cityRepository repository.CityRepositoryInterface
+
+	activeCities *catalogcache.Cache[struct{}]
+}
+
+func NewCityService(cityRepo repository.CityRepositoryInterface) *CityService {
+	s := &CityService{
+		cityRepository: cityRepo,
+	}
+	s.activeCities = catalogcache.New(s.load)
+
+	return s
+}
+
+func (s *CityService) CreateCity(ctx context.Context, cityReq dto.CityCreateRequest) (*model.City, error) {
+	if err := validateTimezone(cityReq.Timezone); err != nil {
+		return nil, err
+	}
+
+	city, err := s.cityRepository.CreateCity(ctx, cityReq)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create city: %w", err)
+	}
+
+	s.activeCities.Invalidate()
+
+	return city, nil
+}
+
+func (s *CityService) GetCityList(ctx context.Context) ([]model.City, error) {
+	cities, err := s.cityRepository.GetCityList(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get city list: %w", err)
+	}
+
+	return cities, nil
+}
+
+func (s *CityService) GetCityByID(ctx context.Context, cityID string) (*model.City, error) {
+	city, err := s.cityRepository.GetCityByID(ctx, cityID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get city: %w", err)
+	}
+
+	return city, nil
+}
+
+func (s *CityService) UpdateCity(ctx context.Context, cityID string, cityReq dto.CityUpdateRequest) (*model.City, error) {
+	if cityReq.Name == nil && cityReq.Region == nil && cityReq.Timezone == nil && cityReq.Active == nil {
+		return nil, errors.New("no fields to update")
+	}
+
+	if cityReq.Timezone != nil {
+		if err := validateTimezone(*cityReq.Timezone); err != nil {
+			return nil, err
+		}
+	}
+
+	city, err := s.cityRepository.UpdateCity(ctx, cityID, cityReq)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update city: %w", err)
+	}
+
+	s.activeCities.Invalidate()
+
+	return city, nil
+}
+
+func (s *CityService) DeleteCity(ctx context.Context, cityID string) error {
+	if err := s.cityRepository.DeleteCity(ctx, cityID); err != nil {
+		return fmt.Errorf("failed to delete city: %w", err)
+	}
+
+	s.activeCities.Invalidate()
+
+	return nil
+}
+
+// IsCityAvailable reports whether a PVZ may be opened in the city.
+func (s *CityService) IsCityAvailable(ctx context.Context, name string) (bool, error) {
+	_, ok, err := s.activeCities.Get(ctx, name)
+	return ok, err
+}
+
+func (s *CityService) load(ctx context.Context) (map[string]struct{}, error) {
+	names, err := s.cityRepository.GetActiveCityNames(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load cities: %w", err)
+	}
+
+	activeCities := make(map[string]struct{}, len(names))
+	for _, name := range names {
+		activeCities[name] = struct{}{}
+	}
+
+	return activeCities, nil
+}
+
+func validateTimezone(timezone string) error {
+	if _, err := time.LoadLocation(timezone); err != nil {
+		return fmt.Errorf("unknown timezone %q", timezone)
+	}
+	return nil
+}
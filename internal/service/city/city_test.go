@@ -0,0 +1,153 @@
+package city_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/kirillidk/pvz-service/internal/dto"
+	"github.com/kirillidk/pvz-service/internal/model"
+	"github.com/kirillidk/pvz-service/internal/service/city"
+)
+
+type MockCityRepository struct {
+	CreateCityFunc         func(ctx context.Context, req dto.CityCreateRequest) (*model.City, error)
+	GetCityListFunc        func(ctx context.Context) ([]model.City, error)
+	GetCityByIDFunc        func(ctx context.Context, cityID string) (*model.City, error)
+	UpdateCityFunc         func(ctx context.Context, cityID string, req dto.CityUpdateRequest) (*model.City, error)
+	DeleteCityFunc         func(ctx context.Context, cityID string) error
+	GetActiveCityNamesFunc func(ctx context.Context) ([]string, error)
+}
+
+func (m *MockCityRepository) CreateCity(ctx context.Context, req dto.CityCreateRequest) (*model.City, error) {
+	return m.CreateCityFunc(ctx, req)
+}
+
+func (m *MockCityRepository) GetCityList(ctx context.Context) ([]model.City, error) {
+	return m.GetCityListFunc(ctx)
+}
+
+func (m *MockCityRepository) GetCityByID(ctx context.Context, cityID string) (*model.City, error) {
+	return m.GetCityByIDFunc(ctx, cityID)
+}
+
+func (m *MockCityRepository) UpdateCity(ctx context.Context, cityID string, req dto.CityUpdateRequest) (*model.City, error) {
+	return m.UpdateCityFunc(ctx, cityID, req)
+}
+
+func (m *MockCityRepository) DeleteCity(ctx context.Context, cityID string) error {
+	return m.DeleteCityFunc(ctx, cityID)
+}
+
+func (m *MockCityRepository) GetActiveCityNames(ctx context.Context) ([]string, error) {
+	return m.GetActiveCityNamesFunc(ctx)
+}
+
+func TestCityService_IsCityAvailable(t *testing.T) {
+	ctx := context.Background()
+	loads := 0
+	activeCities := []string{"Москва"}
+
+	repo := &MockCityRepository{
+		GetActiveCityNamesFunc: func(ctx context.Context) ([]string, error) {
+			loads++
+			return activeCities, nil
+		},
+		CreateCityFunc: func(ctx context.Context, req dto.CityCreateRequest) (*model.City, error) {
+			activeCities = append(activeCities, req.Name)
+			return &model.City{Name: req.Name, Timezone: req.Timezone, Active: true}, nil
+		},
+	}
+	s := city.NewCityService(repo)
+
+	for _, name := range []string{"Москва", "Москва"} {
+		available, err := s.IsCityAvailable(ctx, name)
+		if err != nil || !available {
+			t.Fatalf("expected %s to be available, got %v, %v", name, available, err)
+		}
+	}
+
+	available, err := s.IsCityAvailable(ctx, "Новосибирск")
+	if err != nil || available {
+		t.Fatalf("expected Новосибирск to be unavailable, got %v, %v", available, err)
+	}
+
+	if loads != 1 {
+		t.Errorf("expected cities to be loaded once, got %d", loads)
+	}
+
+	_, err = s.CreateCity(ctx, dto.CityCreateRequest{Name: "Новосибирск", Timezone: "Asia/Novosibirsk"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	available, err = s.IsCityAvailable(ctx, "Новосибирск")
+	if err != nil || !available {
+		t.Fatalf("expected Новосибирск to be available after creation, got %v, %v", available, err)
+	}
+
+	if loads != 2 {
+		t.Errorf("expected cache to be reloaded after creation, got %d loads", loads)
+	}
+}
+
+func TestCityService_CreateCity(t *testing.T) {
+	tests := []struct {
+		name          string
+		mockRepo      *MockCityRepository
+		input         dto.CityCreateRequest
+		expectedError error
+	}{
+		{
+			name: "Success",
+			mockRepo: &MockCityRepository{
+				CreateCityFunc: func(ctx context.Context, req dto.CityCreateRequest) (*model.City, error) {
+					return &model.City{Name: req.Name, Timezone: req.Timezone}, nil
+				},
+			},
+			input: dto.CityCreateRequest{Name: "Екатеринбург", Timezone: "Asia/Yekaterinburg"},
+		},
+		{
+			name:          "Unknown Timezone",
+			mockRepo:      &MockCityRepository{},
+			input:         dto.CityCreateRequest{Name: "Екатеринбург", Timezone: "Mars/Olympus"},
+			expectedError: errors.New(`unknown timezone "Mars/Olympus"`),
+		},
+		{
+			name: "Duplicate City",
+			mockRepo: &MockCityRepository{
+				CreateCityFunc: func(ctx context.Context, req dto.CityCreateRequest) (*model.City, error) {
+					return nil, model.ErrCityAlreadyExists
+				},
+			},
+			input:         dto.CityCreateRequest{Name: "Москва", Timezone: "Europe/Moscow"},
+			expectedError: model.ErrCityAlreadyExists,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := city.NewCityService(tt.mockRepo)
+			_, err := s.CreateCity(context.Background(), tt.input)
+
+			if tt.expectedError == nil {
+				if err != nil {
+					t.Errorf("unexpected error: %v", err)
+				}
+				return
+			}
+
+			if err == nil || (!errors.Is(err, tt.expectedError) && err.Error() != tt.expectedError.Error()) {
+				t.Errorf("expected error %v, got %v", tt.expectedError, err)
+			}
+		})
+	}
+}
+
+func TestCityService_UpdateCity_NoFields(t *testing.T) {
+	s := city.NewCityService(&MockCityRepository{})
+
+	if _, err := s.UpdateCity(context.Background(), "city-id", dto.CityUpdateRequest{}); err == nil {
+		t.Error("expected error for empty update")
+	}
+}
@@ -0,0 +1,297 @@
+package expecteddelivery
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/kirillidk/pvz-service/internal/dto"
+	"github.com/kirillidk/pvz-service/internal/model"
+	"github.com/kirillidk/pvz-service/internal/repository"
+	"github.com/kirillidk/pvz-service/internal/service/audit"
+	"github.com/kirillidk/pvz-service/internal/service/producttype"
+)
+
+type ExpectedDeliveryServiceInterface interface {
+	CreateExpectedDelivery(
+		ctx context.Context,
+		pvzID string,
+		deliveryCreateReq dto.ExpectedDeliveryCreateRequest,
+	) (*model.ExpectedDelivery, error)
+	GetExpectedDeliveries(ctx context.Context, pvzID string) ([]model.ExpectedDelivery, error)
+	GetExpectedDelivery(ctx context.Context, pvzID, deliveryID string) (*model.ExpectedDelivery, error)
+	LinkReception(ctx context.Context, reception *model.Reception) (*model.ExpectedDelivery, error)
+	MatchProduct(ctx context.Context, product model.Product) ([]string, error)
+	ReconcileReception(ctx context.Context, reception *model.Reception) (*model.DeliveryReconciliation, error)
+	ReopenReception(ctx context.Context, reception *model.Reception) error
+}
+
+type ExpectedDeliveryService struct {
+	expectedDeliveryRepository repository.ExpectedDeliveryRepositoryInterface
+	pvzRepository              repository.PVZRepositoryInterface
+	productRepository          repository.ProductRepositoryInterface
+	receptionRepository        repository.ReceptionRepositoryInterface
+	transactor                 repository.TransactorInterface
+	auditService               audit.AuditServiceInterface
+	productTypeService         producttype.ProductTypeServiceInterface
+}
+
+func NewExpectedDeliveryService(
+	expectedDeliveryRepo repository.ExpectedDeliveryRepositoryInterface,
+	pvzRepo repository.PVZRepositoryInterface,
+	productRepo repository.ProductRepositoryInterface,
+	receptionRepo repository.ReceptionRepositoryInterface,
+	transactor repository.TransactorInterface,
+	auditService audit.AuditServiceInterface,
+	productTypeService producttype.ProductTypeServiceInterface,
+) *ExpectedDeliveryService {
+	return &ExpectedDeliveryService{
+		expectedDeliveryRepository: expectedDeliveryRepo,
+		pvzRepository:              pvzRepo,
+		productRepository:          productRepo,
+		receptionRepository:        receptionRepo,
+		transactor:                 transactor,
+		auditService:               auditService,
+		productTypeService:         productTypeService,
+	}
+}
+
+// CreateExpectedDelivery records the parcels announced for the PVZ. If a
+// reception is already open at the PVZ without a delivery, the oldest
+// pending delivery is linked to it right away.
+func (s *ExpectedDeliveryService) CreateExpectedDelivery(
+	ctx context.Context,
+	pvzID string,
+	deliveryCreateReq dto.ExpectedDeliveryCreateRequest,
+) (*model.ExpectedDelivery, error) {
+	items := make([]model.ExpectedDeliveryItem, 0, len(deliveryCreateReq.Items))
+	barcodes := make(map[string]int, len(deliveryCreateReq.Items))
+	checkedTypes := make(map[string]bool)
+
+	for i, item := range deliveryCreateReq.Items {
+		if first, ok := barcodes[item.Barcode]; ok {
+			return nil, fmt.Errorf("item %d: barcode %s is already listed in item %d", i, item.Barcode, first)
+		}
+		barcodes[item.Barcode] = i
+
+		if !checkedTypes[item.Type] {
+			if _, err := s.productTypeService.GetActiveProductType(ctx, item.Type); err != nil {
+				return nil, fmt.Errorf("item %d: %w", i, err)
+			}
+			checkedTypes[item.Type] = true
+		}
+
+		items = append(items, model.ExpectedDeliveryItem{Barcode: item.Barcode, Type: item.Type})
+	}
+
+	var createdDelivery *model.ExpectedDelivery
+
+	err := s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		pvz, err := s.pvzRepository.GetPVZForUpdate(ctx, pvzID)
+		if err != nil {
+			return fmt.Errorf("failed to get PVZ: %w", err)
+		}
+
+		if pvz.Status != model.PVZStatusActive {
+			return model.ErrPVZNotActive
+		}
+
+		createdDelivery, err = s.expectedDeliveryRepository.CreateExpectedDelivery(ctx, model.ExpectedDelivery{
+			PVZID:     pvzID,
+			Items:     items,
+			CreatedAt: time.Now().UTC(),
+		})
+		if err != nil {
+			return fmt.Errorf("failed to create expected delivery: %w", err)
+		}
+
+		err = s.auditService.Record(ctx, model.AuditActionCreate, model.AuditEntityExpectedDelivery, createdDelivery.ID, nil, createdDelivery)
+		if err != nil {
+			return err
+		}
+
+		reception, err := s.receptionRepository.GetLastOpenReception(ctx, pvzID)
+		if err != nil {
+			if errors.Is(err, model.ErrNoOpenReception) {
+				return nil
+			}
+			return fmt.Errorf("failed to find open reception: %w", err)
+		}
+
+		linkedDelivery, err := s.LinkReception(ctx, reception)
+		if err != nil {
+			return err
+		}
+
+		if linkedDelivery != nil && linkedDelivery.ID == createdDelivery.ID {
+			createdDelivery = linkedDelivery
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return createdDelivery, nil
+}
+
+func (s *ExpectedDeliveryService) GetExpectedDeliveries(ctx context.Context, pvzID string) ([]model.ExpectedDelivery, error) {
+	deliveries, err := s.expectedDeliveryRepository.GetExpectedDeliveriesByPVZID(ctx, pvzID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get expected deliveries: %w", err)
+	}
+
+	return deliveries, nil
+}
+
+// GetExpectedDelivery returns a delivery of the PVZ. Deliveries of other
+// PVZs are reported as not found.
+func (s *ExpectedDeliveryService) GetExpectedDelivery(ctx context.Context, pvzID, deliveryID string) (*model.ExpectedDelivery, error) {
+	delivery, err := s.expectedDeliveryRepository.GetExpectedDelivery(ctx, deliveryID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get expected delivery: %w", err)
+	}
+
+	if delivery.PVZID != pvzID {
+		return nil, fmt.Errorf("failed to get expected delivery: %w", model.ErrExpectedDeliveryNotFound)
+	}
+
+	return delivery, nil
+}
+
+// LinkReception links the oldest pending delivery of the PVZ to its open
+// reception unless the reception already has one. It returns nil if no
+// delivery was linked.
+func (s *ExpectedDeliveryService) LinkReception(ctx context.Context, reception *model.Reception) (*model.ExpectedDelivery, error) {
+	_, err := s.expectedDeliveryRepository.GetExpectedDeliveryByReceptionID(ctx, reception.ID)
+	if err == nil {
+		return nil, nil
+	}
+	if !errors.Is(err, model.ErrExpectedDeliveryNotFound) {
+		return nil, fmt.Errorf("failed to get expected delivery: %w", err)
+	}
+
+	linkedDelivery, err := s.expectedDeliveryRepository.LinkPendingExpectedDelivery(ctx, reception.PVZID, reception.ID)
+	if err != nil {
+		if errors.Is(err, model.ErrExpectedDeliveryNotFound) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to link expected delivery: %w", err)
+	}
+
+	delivery := *linkedDelivery
+	delivery.Status = model.ExpectedDeliveryStatusPending
+	delivery.ReceptionID = ""
+
+	err = s.auditService.Record(ctx, model.AuditActionStatusChange, model.AuditEntityExpectedDelivery, linkedDelivery.ID, &delivery, linkedDelivery)
+	if err != nil {
+		return nil, err
+	}
+
+	return linkedDelivery, nil
+}
+
+// MatchProduct marks the item of the delivery linked to the product's
+// reception with the same barcode as received. It returns a warning when
+// the product was scanned with another type than the delivery lists.
+func (s *ExpectedDeliveryService) MatchProduct(ctx context.Context, product model.Product) ([]string, error) {
+	if product.Barcode == "" {
+		return nil, nil
+	}
+
+	item, err := s.expectedDeliveryRepository.MatchExpectedDeliveryItem(ctx, product.ReceptionID, product.Barcode, product.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to match expected delivery item: %w", err)
+	}
+
+	if item != nil && item.Type != product.Type {
+		return []string{
+			fmt.Sprintf("barcode %s is expected with product type %s, not %s", product.Barcode, item.Type, product.Type),
+		}, nil
+	}
+
+	return nil, nil
+}
+
+// ReconcileReception compares the closed reception with the delivery
+// linked to it and marks the delivery as reconciled. It returns nil if the
+// reception has no delivery. Only products scanned into the reception are
+// compared: a received transfer is linked to the reception, but its
+// products keep the reception of the source PVZ.
+func (s *ExpectedDeliveryService) ReconcileReception(
+	ctx context.Context,
+	reception *model.Reception,
+) (*model.DeliveryReconciliation, error) {
+	delivery, err := s.expectedDeliveryRepository.GetExpectedDeliveryByReceptionID(ctx, reception.ID)
+	if err != nil {
+		if errors.Is(err, model.ErrExpectedDeliveryNotFound) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get expected delivery: %w", err)
+	}
+
+	products, err := s.productRepository.GetProductsByReceptionID(ctx, reception.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get products for reception %s: %w", reception.ID, err)
+	}
+
+	reconciliation := &model.DeliveryReconciliation{
+		DeliveryID:    delivery.ID,
+		ExpectedCount: len(delivery.Items),
+		Missing:       []model.ExpectedDeliveryItem{},
+		Unexpected:    []model.Product{},
+	}
+
+	matched := make(map[string]bool, len(delivery.Items))
+	for _, item := range delivery.Items {
+		if item.ProductID == "" {
+			reconciliation.Missing = append(reconciliation.Missing, item)
+			continue
+		}
+		matched[item.ProductID] = true
+		reconciliation.MatchedCount++
+	}
+
+	for _, product := range products {
+		if !matched[product.ID] {
+			reconciliation.Unexpected = append(reconciliation.Unexpected, product)
+		}
+	}
+
+	reconciledDelivery, err := s.expectedDeliveryRepository.MarkExpectedDeliveryReconciled(ctx, delivery.ID, time.Now().UTC())
+	if err != nil {
+		return nil, fmt.Errorf("failed to reconcile expected delivery: %w", err)
+	}
+
+	err = s.auditService.Record(ctx, model.AuditActionStatusChange, model.AuditEntityExpectedDelivery, delivery.ID, delivery, reconciledDelivery)
+	if err != nil {
+		return nil, err
+	}
+
+	return reconciliation, nil
+}
+
+// ReopenReception returns the delivery reconciled against the reopened
+// reception to receiving, so that it is reconciled again on the next close.
+func (s *ExpectedDeliveryService) ReopenReception(ctx context.Context, reception *model.Reception) error {
+	delivery, err := s.expectedDeliveryRepository.GetExpectedDeliveryByReceptionID(ctx, reception.ID)
+	if err != nil {
+		if errors.Is(err, model.ErrExpectedDeliveryNotFound) {
+			return nil
+		}
+		return fmt.Errorf("failed to get expected delivery: %w", err)
+	}
+
+	if delivery.Status != model.ExpectedDeliveryStatusReconciled {
+		return nil
+	}
+
+	reopenedDelivery, err := s.expectedDeliveryRepository.MarkExpectedDeliveryReceiving(ctx, delivery.ID)
+	if err != nil {
+		return fmt.Errorf("failed to reopen expected delivery: %w", err)
+	}
+
+	return s.auditService.Record(ctx, model.AuditActionStatusChange, model.AuditEntityExpectedDelivery, delivery.ID, delivery, reopenedDelivery)
+}
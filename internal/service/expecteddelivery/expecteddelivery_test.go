@@ -0,0 +1,758 @@
+package expecteddelivery_test
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/kirillidk/pvz-service/internal/dto"
+	"github.com/kirillidk/pvz-service/internal/model"
+	"github.com/kirillidk/pvz-service/internal/service/expecteddelivery"
+)
+
+const (
+	pvzID       = "123e4567-e89b-12d3-a456-426614174003"
+	receptionID = "123e4567-e89b-12d3-a456-426614174002"
+	deliveryID  = "123e4567-e89b-12d3-a456-426614174030"
+)
+
+type MockExpectedDeliveryRepository struct {
+	CreateExpectedDeliveryFunc           func(ctx context.Context, delivery model.ExpectedDelivery) (*model.ExpectedDelivery, error)
+	GetExpectedDeliveryFunc              func(ctx context.Context, deliveryID string) (*model.ExpectedDelivery, error)
+	GetExpectedDeliveryByReceptionIDFunc func(ctx context.Context, receptionID string) (*model.ExpectedDelivery, error)
+	LinkPendingExpectedDeliveryFunc      func(ctx context.Context, pvzID, receptionID string) (*model.ExpectedDelivery, error)
+	MatchExpectedDeliveryItemFunc        func(ctx context.Context, receptionID, barcode, productID string) (*model.ExpectedDeliveryItem, error)
+
+	Reconciled string
+	Reopened   string
+}
+
+func (m *MockExpectedDeliveryRepository) CreateExpectedDelivery(
+	ctx context.Context,
+	delivery model.ExpectedDelivery,
+) (*model.ExpectedDelivery, error) {
+	return m.CreateExpectedDeliveryFunc(ctx, delivery)
+}
+
+func (m *MockExpectedDeliveryRepository) GetExpectedDelivery(ctx context.Context, deliveryID string) (*model.ExpectedDelivery, error) {
+	return m.GetExpectedDeliveryFunc(ctx, deliveryID)
+}
+
+func (m *MockExpectedDeliveryRepository) GetExpectedDeliveriesByPVZID(ctx context.Context, pvzID string) ([]model.ExpectedDelivery, error) {
+	return nil, nil
+}
+
+func (m *MockExpectedDeliveryRepository) GetExpectedDeliveryByReceptionID(
+	ctx context.Context,
+	receptionID string,
+) (*model.ExpectedDelivery, error) {
+	return m.GetExpectedDeliveryByReceptionIDFunc(ctx, receptionID)
+}
+
+func (m *MockExpectedDeliveryRepository) LinkPendingExpectedDelivery(
+	ctx context.Context,
+	pvzID, receptionID string,
+) (*model.ExpectedDelivery, error) {
+	return m.LinkPendingExpectedDeliveryFunc(ctx, pvzID, receptionID)
+}
+
+func (m *MockExpectedDeliveryRepository) MatchExpectedDeliveryItem(
+	ctx context.Context,
+	receptionID, barcode, productID string,
+) (*model.ExpectedDeliveryItem, error) {
+	return m.MatchExpectedDeliveryItemFunc(ctx, receptionID, barcode, productID)
+}
+
+func (m *MockExpectedDeliveryRepository) MarkExpectedDeliveryReconciled(
+	ctx context.Context,
+	deliveryID string,
+	reconciledAt time.Time,
+) (*model.ExpectedDelivery, error) {
+	m.Reconciled = deliveryID
+	return &model.ExpectedDelivery{ID: deliveryID, Status: model.ExpectedDeliveryStatusReconciled, ReconciledAt: &reconciledAt}, nil
+}
+
+func (m *MockExpectedDeliveryRepository) MarkExpectedDeliveryReceiving(ctx context.Context, deliveryID string) (*model.ExpectedDelivery, error) {
+	m.Reopened = deliveryID
+	return &model.ExpectedDelivery{ID: deliveryID, Status: model.ExpectedDeliveryStatusReceiving}, nil
+}
+
+type MockProductRepository struct {
+	GetProductsByReceptionIDFunc func(ctx context.Context, receptionID string) ([]model.Product, error)
+}
+
+func (m *MockProductRepository) CreateProduct(ctx context.Context, receptionID string, attrs dto.ProductAttributes) (*model.Product, error) {
+	return nil, nil
+}
+
+func (m *MockProductRepository) CreateProducts(ctx context.Context, receptionID string, items []dto.ProductAttributes) ([]model.Product, error) {
+	return nil, nil
+}
+
+func (m *MockProductRepository) GetLastProductInReception(ctx context.Context, receptionID string) (*model.Product, error) {
+	return nil, nil
+}
+
+func (m *MockProductRepository) DeleteProduct(ctx context.Context, productID string) error {
+	return nil
+}
+
+func (m *MockProductRepository) GetProductsByReceptionID(ctx context.Context, receptionID string) ([]model.Product, error) {
+	return m.GetProductsByReceptionIDFunc(ctx, receptionID)
+}
+
+func (m *MockProductRepository) GetProductsByReceptionIDInInsertionOrder(ctx context.Context, receptionID string) ([]model.Product, error) {
+	return nil, nil
+}
+
+func (m *MockProductRepository) GetProductsByBarcodes(ctx context.Context, barcodes []string) ([]model.ProductLocation, error) {
+	return nil, nil
+}
+
+func (m *MockProductRepository) GetProductLocation(ctx context.Context, productID string) (*model.ProductLocation, error) {
+	return nil, nil
+}
+
+func (m *MockProductRepository) GetProductLocationForUpdate(ctx context.Context, productID string) (*model.ProductLocation, error) {
+	return nil, nil
+}
+
+func (m *MockProductRepository) UpdateProductStatus(ctx context.Context, productID string, status model.ProductStatus) (*model.Product, error) {
+	return nil, nil
+}
+
+func (m *MockProductRepository) UpdateProductCell(ctx context.Context, productID, cellID string) (*model.Product, error) {
+	return nil, nil
+}
+
+func (m *MockProductRepository) UpdateProductCondition(
+	ctx context.Context,
+	productID string,
+	condition model.ProductCondition,
+	note string,
+) (*model.Product, error) {
+	return nil, nil
+}
+
+type MockPVZRepository struct {
+	GetPVZForUpdateFunc func(ctx context.Context, pvzID string) (*model.PVZ, error)
+}
+
+func (m *MockPVZRepository) CreatePVZ(ctx context.Context, req dto.PVZCreateRequest) (*model.PVZ, error) {
+	return nil, nil
+}
+
+func (m *MockPVZRepository) GetPVZList(ctx context.Context, filter dto.PVZFilterQuery) ([]model.PVZ, error) {
+	return nil, nil
+}
+
+func (m *MockPVZRepository) GetPVZByID(ctx context.Context, pvzID string) (*model.PVZ, error) {
+	return nil, nil
+}
+
+func (m *MockPVZRepository) UpdatePVZ(ctx context.Context, pvzID string, req dto.PVZUpdateRequest) (*model.PVZ, error) {
+	return nil, nil
+}
+
+func (m *MockPVZRepository) GetNearbyPVZList(ctx context.Context, query dto.PVZNearbyQuery) ([]model.NearbyPVZ, error) {
+	return nil, nil
+}
+
+func (m *MockPVZRepository) GetPVZForUpdate(ctx context.Context, pvzID string) (*model.PVZ, error) {
+	return m.GetPVZForUpdateFunc(ctx, pvzID)
+}
+
+func (m *MockPVZRepository) UpdatePVZStatus(ctx context.Context, pvzID string, status model.PVZStatus) (*model.PVZ, error) {
+	return nil, nil
+}
+
+func (m *MockPVZRepository) StreamPVZList(ctx context.Context, filter dto.PVZFilterQuery, fn func(model.PVZ, *model.Reception, *model.Product) error) error {
+	return nil
+}
+
+type MockReceptionRepository struct {
+	GetLastOpenReceptionFunc func(ctx context.Context, pvzID string) (*model.Reception, error)
+}
+
+func (m *MockReceptionRepository) CreateReception(ctx context.Context, req dto.ReceptionCreateRequest) (*model.Reception, error) {
+	return nil, nil
+}
+
+func (m *MockReceptionRepository) HasOpenReception(ctx context.Context, pvzID string) (bool, error) {
+	return false, nil
+}
+
+func (m *MockReceptionRepository) GetLastOpenReception(ctx context.Context, pvzID string) (*model.Reception, error) {
+	return m.GetLastOpenReceptionFunc(ctx, pvzID)
+}
+
+func (m *MockReceptionRepository) GetLastOpenReceptionForUpdate(ctx context.Context, pvzID string) (*model.Reception, error) {
+	return nil, nil
+}
+
+func (m *MockReceptionRepository) GetReceptionByID(ctx context.Context, receptionID string) (*model.Reception, error) {
+	return nil, nil
+}
+
+func (m *MockReceptionRepository) CloseReception(ctx context.Context, receptionID string) (*model.Reception, error) {
+	return nil, nil
+}
+
+func (m *MockReceptionRepository) GetReceptionForUpdate(ctx context.Context, receptionID string) (*model.Reception, error) {
+	return nil, nil
+}
+
+func (m *MockReceptionRepository) HasNewerReception(ctx context.Context, reception *model.Reception) (bool, error) {
+	return false, nil
+}
+
+func (m *MockReceptionRepository) HasProcessedProducts(ctx context.Context, receptionID string) (bool, error) {
+	return false, nil
+}
+
+func (m *MockReceptionRepository) ReopenReception(ctx context.Context, receptionID string) (*model.Reception, error) {
+	return nil, nil
+}
+
+func (m *MockReceptionRepository) GetReceptionsByPVZID(ctx context.Context, pvzID string, filter dto.ReceptionFilter) ([]model.Reception, error) {
+	return nil, nil
+}
+
+func (m *MockReceptionRepository) SetExpectedCount(ctx context.Context, receptionID string, expectedCount int) (*model.Reception, error) {
+	return nil, nil
+}
+
+func (m *MockReceptionRepository) GetReceptionDiscrepancy(ctx context.Context, receptionID string) (*model.ReceptionDiscrepancy, error) {
+	return nil, nil
+}
+
+func (m *MockReceptionRepository) GetReceptionDiscrepancies(
+	ctx context.Context,
+	filter dto.DiscrepancyFilterQuery,
+) ([]model.ReceptionDiscrepancy, error) {
+	return nil, nil
+}
+
+type MockTransactor struct{}
+
+func (m *MockTransactor) WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	return fn(ctx)
+}
+
+type MockAuditService struct {
+	Actions []model.AuditAction
+}
+
+func (m *MockAuditService) Record(
+	ctx context.Context,
+	action model.AuditAction,
+	entityType model.AuditEntityType,
+	entityID string,
+	before, after any,
+) error {
+	m.Actions = append(m.Actions, action)
+	return nil
+}
+
+func (m *MockAuditService) GetAuditLog(ctx context.Context, filter dto.AuditFilterQuery) ([]model.AuditEntry, error) {
+	return nil, nil
+}
+
+type MockProductTypeService struct{}
+
+func (m *MockProductTypeService) CreateProductType(ctx context.Context, req dto.ProductTypeCreateRequest) (*model.ProductType, error) {
+	return nil, nil
+}
+
+func (m *MockProductTypeService) GetProductTypeList(ctx context.Context) ([]model.ProductType, error) {
+	return nil, nil
+}
+
+func (m *MockProductTypeService) GetProductType(ctx context.Context, code string) (*model.ProductType, error) {
+	return nil, nil
+}
+
+func (m *MockProductTypeService) UpdateProductType(ctx context.Context, code string, req dto.ProductTypeUpdateRequest) (*model.ProductType, error) {
+	return nil, nil
+}
+
+func (m *MockProductTypeService) DeleteProductType(ctx context.Context, code string) error {
+	return nil
+}
+
+func (m *MockProductTypeService) GetActiveProductType(ctx context.Context, code string) (*model.ProductType, error) {
+	if code != "electronics" && code != "clothes" {
+		return nil, model.ErrProductTypeNotFound
+	}
+	return &model.ProductType{Code: code, Active: true}, nil
+}
+
+func activePVZ(ctx context.Context, pvzID string) (*model.PVZ, error) {
+	return &model.PVZ{ID: pvzID, Status: model.PVZStatusActive}, nil
+}
+
+func noDelivery(ctx context.Context, receptionID string) (*model.ExpectedDelivery, error) {
+	return nil, model.ErrExpectedDeliveryNotFound
+}
+
+func newService(
+	deliveryRepo *MockExpectedDeliveryRepository,
+	productRepo *MockProductRepository,
+	pvzRepo *MockPVZRepository,
+	receptionRepo *MockReceptionRepository,
+	auditService *MockAuditService,
+) *expecteddelivery.ExpectedDeliveryService {
+	return expecteddelivery.NewExpectedDeliveryService(
+		deliveryRepo, pvzRepo, productRepo, receptionRepo, &MockTransactor{}, auditService, &MockProductTypeService{},
+	)
+}
+
+func TestExpectedDeliveryService_CreateExpectedDelivery(t *testing.T) {
+	items := []dto.ExpectedDeliveryItemRequest{
+		{Barcode: "4006381333931", Type: "electronics"},
+		{Barcode: "4006381333948", Type: "clothes"},
+	}
+
+	tests := []struct {
+		name            string
+		items           []dto.ExpectedDeliveryItemRequest
+		getPVZ          func(ctx context.Context, pvzID string) (*model.PVZ, error)
+		openReception   *model.Reception
+		receptionLinked bool
+		expectedStatus  model.ExpectedDeliveryStatus
+		expectedActions []model.AuditAction
+		expectedError   error
+		expectedMsg     string
+	}{
+		{
+			name:            "Success",
+			items:           items,
+			expectedStatus:  model.ExpectedDeliveryStatusPending,
+			expectedActions: []model.AuditAction{model.AuditActionCreate},
+		},
+		{
+			name:            "Linked To Open Reception",
+			items:           items,
+			openReception:   &model.Reception{ID: receptionID, PVZID: pvzID, Status: "in_progress"},
+			expectedStatus:  model.ExpectedDeliveryStatusReceiving,
+			expectedActions: []model.AuditAction{model.AuditActionCreate, model.AuditActionStatusChange},
+		},
+		{
+			name:            "Open Reception Already Linked",
+			items:           items,
+			openReception:   &model.Reception{ID: receptionID, PVZID: pvzID, Status: "in_progress"},
+			receptionLinked: true,
+			expectedStatus:  model.ExpectedDeliveryStatusPending,
+			expectedActions: []model.AuditAction{model.AuditActionCreate},
+		},
+		{
+			name: "Duplicate Barcode",
+			items: []dto.ExpectedDeliveryItemRequest{
+				{Barcode: "4006381333931", Type: "electronics"},
+				{Barcode: "4006381333931", Type: "clothes"},
+			},
+			expectedMsg: "item 1: barcode 4006381333931 is already listed in item 0",
+		},
+		{
+			name:          "Unknown Product Type",
+			items:         []dto.ExpectedDeliveryItemRequest{{Barcode: "4006381333931", Type: "furniture"}},
+			expectedError: model.ErrProductTypeNotFound,
+		},
+		{
+			name:  "PVZ Not Active",
+			items: items,
+			getPVZ: func(ctx context.Context, pvzID string) (*model.PVZ, error) {
+				return &model.PVZ{ID: pvzID, Status: model.PVZStatusSuspended}, nil
+			},
+			expectedError: model.ErrPVZNotActive,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			getPVZ := tt.getPVZ
+			if getPVZ == nil {
+				getPVZ = activePVZ
+			}
+
+			var created *model.ExpectedDelivery
+			deliveryRepo := &MockExpectedDeliveryRepository{
+				CreateExpectedDeliveryFunc: func(ctx context.Context, delivery model.ExpectedDelivery) (*model.ExpectedDelivery, error) {
+					delivery.ID = deliveryID
+					delivery.Status = model.ExpectedDeliveryStatusPending
+					created = &delivery
+					return created, nil
+				},
+				GetExpectedDeliveryByReceptionIDFunc: func(ctx context.Context, receptionID string) (*model.ExpectedDelivery, error) {
+					if tt.receptionLinked {
+						return &model.ExpectedDelivery{ID: "223e4567-e89b-12d3-a456-426614174030", ReceptionID: receptionID}, nil
+					}
+					return nil, model.ErrExpectedDeliveryNotFound
+				},
+				LinkPendingExpectedDeliveryFunc: func(ctx context.Context, pvzID, receptionID string) (*model.ExpectedDelivery, error) {
+					linked := *created
+					linked.Status = model.ExpectedDeliveryStatusReceiving
+					linked.ReceptionID = receptionID
+					return &linked, nil
+				},
+			}
+			receptionRepo := &MockReceptionRepository{
+				GetLastOpenReceptionFunc: func(ctx context.Context, pvzID string) (*model.Reception, error) {
+					if tt.openReception == nil {
+						return nil, model.ErrNoOpenReception
+					}
+					return tt.openReception, nil
+				},
+			}
+			auditService := &MockAuditService{}
+
+			s := newService(deliveryRepo, &MockProductRepository{}, &MockPVZRepository{GetPVZForUpdateFunc: getPVZ}, receptionRepo, auditService)
+			got, err := s.CreateExpectedDelivery(context.Background(), pvzID, dto.ExpectedDeliveryCreateRequest{Items: tt.items})
+
+			if tt.expectedError != nil || tt.expectedMsg != "" {
+				if tt.expectedError != nil && !errors.Is(err, tt.expectedError) {
+					t.Errorf("CreateExpectedDelivery() error = %v, expected %v", err, tt.expectedError)
+				}
+				if tt.expectedMsg != "" && (err == nil || err.Error() != tt.expectedMsg) {
+					t.Errorf("CreateExpectedDelivery() error = %v, expected %q", err, tt.expectedMsg)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("CreateExpectedDelivery() unexpected error = %v", err)
+			}
+			if got.Status != tt.expectedStatus {
+				t.Errorf("Expected status %s, got %s", tt.expectedStatus, got.Status)
+			}
+			expectedItems := []model.ExpectedDeliveryItem{
+				{Barcode: "4006381333931", Type: "electronics"},
+				{Barcode: "4006381333948", Type: "clothes"},
+			}
+			if !reflect.DeepEqual(got.Items, expectedItems) {
+				t.Errorf("Expected items %v, got %v", expectedItems, got.Items)
+			}
+			if !reflect.DeepEqual(auditService.Actions, tt.expectedActions) {
+				t.Errorf("Expected audit actions %v, got %v", tt.expectedActions, auditService.Actions)
+			}
+		})
+	}
+}
+
+func TestExpectedDeliveryService_GetExpectedDelivery(t *testing.T) {
+	deliveryRepo := &MockExpectedDeliveryRepository{
+		GetExpectedDeliveryFunc: func(ctx context.Context, deliveryID string) (*model.ExpectedDelivery, error) {
+			return &model.ExpectedDelivery{ID: deliveryID, PVZID: pvzID}, nil
+		},
+	}
+	s := newService(deliveryRepo, &MockProductRepository{}, &MockPVZRepository{}, &MockReceptionRepository{}, &MockAuditService{})
+
+	if _, err := s.GetExpectedDelivery(context.Background(), pvzID, deliveryID); err != nil {
+		t.Errorf("GetExpectedDelivery() unexpected error = %v", err)
+	}
+
+	_, err := s.GetExpectedDelivery(context.Background(), "223e4567-e89b-12d3-a456-426614174003", deliveryID)
+	if !errors.Is(err, model.ErrExpectedDeliveryNotFound) {
+		t.Errorf("GetExpectedDelivery() error = %v, expected %v", err, model.ErrExpectedDeliveryNotFound)
+	}
+}
+
+func TestExpectedDeliveryService_LinkReception(t *testing.T) {
+	reception := &model.Reception{ID: receptionID, PVZID: pvzID, Status: "in_progress"}
+
+	t.Run("Links Oldest Pending Delivery", func(t *testing.T) {
+		var linkedPVZ, linkedReception string
+		deliveryRepo := &MockExpectedDeliveryRepository{
+			GetExpectedDeliveryByReceptionIDFunc: noDelivery,
+			LinkPendingExpectedDeliveryFunc: func(ctx context.Context, pvzID, receptionID string) (*model.ExpectedDelivery, error) {
+				linkedPVZ, linkedReception = pvzID, receptionID
+				return &model.ExpectedDelivery{
+					ID:          deliveryID,
+					PVZID:       pvzID,
+					Status:      model.ExpectedDeliveryStatusReceiving,
+					ReceptionID: receptionID,
+				}, nil
+			},
+		}
+		auditService := &MockAuditService{}
+		s := newService(deliveryRepo, &MockProductRepository{}, &MockPVZRepository{}, &MockReceptionRepository{}, auditService)
+
+		got, err := s.LinkReception(context.Background(), reception)
+		if err != nil {
+			t.Fatalf("LinkReception() unexpected error = %v", err)
+		}
+		if got == nil || got.ID != deliveryID {
+			t.Errorf("Expected delivery %s to be linked, got %v", deliveryID, got)
+		}
+		if linkedPVZ != pvzID || linkedReception != receptionID {
+			t.Errorf("Expected delivery of PVZ %s linked to %s, got %s and %s", pvzID, receptionID, linkedPVZ, linkedReception)
+		}
+		if !reflect.DeepEqual(auditService.Actions, []model.AuditAction{model.AuditActionStatusChange}) {
+			t.Errorf("Expected the link to be audited, got %v", auditService.Actions)
+		}
+	})
+
+	t.Run("No Pending Delivery", func(t *testing.T) {
+		deliveryRepo := &MockExpectedDeliveryRepository{
+			GetExpectedDeliveryByReceptionIDFunc: noDelivery,
+			LinkPendingExpectedDeliveryFunc: func(ctx context.Context, pvzID, receptionID string) (*model.ExpectedDelivery, error) {
+				return nil, model.ErrExpectedDeliveryNotFound
+			},
+		}
+		auditService := &MockAuditService{}
+		s := newService(deliveryRepo, &MockProductRepository{}, &MockPVZRepository{}, &MockReceptionRepository{}, auditService)
+
+		got, err := s.LinkReception(context.Background(), reception)
+		if err != nil || got != nil {
+			t.Errorf("LinkReception() = %v, %v, expected no delivery", got, err)
+		}
+		if len(auditService.Actions) != 0 {
+			t.Errorf("Expected nothing to be audited, got %v", auditService.Actions)
+		}
+	})
+
+	t.Run("Reception Already Linked", func(t *testing.T) {
+		deliveryRepo := &MockExpectedDeliveryRepository{
+			GetExpectedDeliveryByReceptionIDFunc: func(ctx context.Context, receptionID string) (*model.ExpectedDelivery, error) {
+				return &model.ExpectedDelivery{ID: deliveryID, ReceptionID: receptionID}, nil
+			},
+			LinkPendingExpectedDeliveryFunc: func(ctx context.Context, pvzID, receptionID string) (*model.ExpectedDelivery, error) {
+				t.Error("a reception must not be linked to a second delivery")
+				return nil, nil
+			},
+		}
+		s := newService(deliveryRepo, &MockProductRepository{}, &MockPVZRepository{}, &MockReceptionRepository{}, &MockAuditService{})
+
+		got, err := s.LinkReception(context.Background(), reception)
+		if err != nil || got != nil {
+			t.Errorf("LinkReception() = %v, %v, expected no delivery", got, err)
+		}
+	})
+}
+
+func TestExpectedDeliveryService_MatchProduct(t *testing.T) {
+	tests := []struct {
+		name             string
+		product          model.Product
+		item             *model.ExpectedDeliveryItem
+		expectedMatch    bool
+		expectedWarnings []string
+	}{
+		{
+			name:          "Matched",
+			product:       model.Product{ID: "123e4567-e89b-12d3-a456-426614174001", ReceptionID: receptionID, Type: "electronics", Barcode: "4006381333931"},
+			item:          &model.ExpectedDeliveryItem{Barcode: "4006381333931", Type: "electronics"},
+			expectedMatch: true,
+		},
+		{
+			name:          "Type Mismatch",
+			product:       model.Product{ID: "123e4567-e89b-12d3-a456-426614174001", ReceptionID: receptionID, Type: "electronics", Barcode: "4006381333931"},
+			item:          &model.ExpectedDeliveryItem{Barcode: "4006381333931", Type: "clothes"},
+			expectedMatch: true,
+			expectedWarnings: []string{
+				"barcode 4006381333931 is expected with product type clothes, not electronics",
+			},
+		},
+		{
+			name:          "Not In Delivery",
+			product:       model.Product{ID: "123e4567-e89b-12d3-a456-426614174001", ReceptionID: receptionID, Type: "electronics", Barcode: "4006381333931"},
+			expectedMatch: true,
+		},
+		{
+			name:    "Without Barcode",
+			product: model.Product{ID: "123e4567-e89b-12d3-a456-426614174001", ReceptionID: receptionID, Type: "electronics"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			matched := false
+			deliveryRepo := &MockExpectedDeliveryRepository{
+				MatchExpectedDeliveryItemFunc: func(ctx context.Context, receptionID, barcode, productID string) (*model.ExpectedDeliveryItem, error) {
+					matched = receptionID == tt.product.ReceptionID && barcode == tt.product.Barcode && productID == tt.product.ID
+					return tt.item, nil
+				},
+			}
+			s := newService(deliveryRepo, &MockProductRepository{}, &MockPVZRepository{}, &MockReceptionRepository{}, &MockAuditService{})
+
+			warnings, err := s.MatchProduct(context.Background(), tt.product)
+			if err != nil {
+				t.Fatalf("MatchProduct() unexpected error = %v", err)
+			}
+			if matched != tt.expectedMatch {
+				t.Errorf("Expected match attempt %v, got %v", tt.expectedMatch, matched)
+			}
+			if !reflect.DeepEqual(warnings, tt.expectedWarnings) {
+				t.Errorf("Expected warnings %v, got %v", tt.expectedWarnings, warnings)
+			}
+		})
+	}
+}
+
+func TestExpectedDeliveryService_ReconcileReception(t *testing.T) {
+	reception := &model.Reception{ID: receptionID, PVZID: pvzID, Status: "close"}
+
+	t.Run("Missing And Unexpected", func(t *testing.T) {
+		deliveryRepo := &MockExpectedDeliveryRepository{
+			GetExpectedDeliveryByReceptionIDFunc: func(ctx context.Context, receptionID string) (*model.ExpectedDelivery, error) {
+				return &model.ExpectedDelivery{
+					ID:          deliveryID,
+					PVZID:       pvzID,
+					Status:      model.ExpectedDeliveryStatusReceiving,
+					ReceptionID: receptionID,
+					Items: []model.ExpectedDeliveryItem{
+						{Barcode: "4006381333931", Type: "electronics", ProductID: "123e4567-e89b-12d3-a456-426614174001"},
+						{Barcode: "4006381333948", Type: "clothes"},
+					},
+				}, nil
+			},
+		}
+		productRepo := &MockProductRepository{
+			GetProductsByReceptionIDFunc: func(ctx context.Context, receptionID string) ([]model.Product, error) {
+				return []model.Product{
+					{ID: "123e4567-e89b-12d3-a456-426614174001", ReceptionID: receptionID, Barcode: "4006381333931"},
+					{ID: "123e4567-e89b-12d3-a456-426614174009", ReceptionID: receptionID, Barcode: "4006381333955"},
+				}, nil
+			},
+		}
+		auditService := &MockAuditService{}
+		s := newService(deliveryRepo, productRepo, &MockPVZRepository{}, &MockReceptionRepository{}, auditService)
+
+		got, err := s.ReconcileReception(context.Background(), reception)
+		if err != nil {
+			t.Fatalf("ReconcileReception() unexpected error = %v", err)
+		}
+
+		expected := &model.DeliveryReconciliation{
+			DeliveryID:    deliveryID,
+			ExpectedCount: 2,
+			MatchedCount:  1,
+			Missing:       []model.ExpectedDeliveryItem{{Barcode: "4006381333948", Type: "clothes"}},
+			Unexpected: []model.Product{
+				{ID: "123e4567-e89b-12d3-a456-426614174009", ReceptionID: receptionID, Barcode: "4006381333955"},
+			},
+		}
+		if !reflect.DeepEqual(got, expected) {
+			t.Errorf("ReconcileReception() = %v, expected %v", got, expected)
+		}
+		if deliveryRepo.Reconciled != deliveryID {
+			t.Errorf("Expected delivery %s to be marked reconciled, got %q", deliveryID, deliveryRepo.Reconciled)
+		}
+		if !reflect.DeepEqual(auditService.Actions, []model.AuditAction{model.AuditActionStatusChange}) {
+			t.Errorf("Expected the reconciliation to be audited, got %v", auditService.Actions)
+		}
+	})
+
+	t.Run("Transferred Products Not Unexpected", func(t *testing.T) {
+		deliveryRepo := &MockExpectedDeliveryRepository{
+			GetExpectedDeliveryByReceptionIDFunc: func(ctx context.Context, receptionID string) (*model.ExpectedDelivery, error) {
+				return &model.ExpectedDelivery{
+					ID:          deliveryID,
+					PVZID:       pvzID,
+					Status:      model.ExpectedDeliveryStatusReceiving,
+					ReceptionID: receptionID,
+					Items: []model.ExpectedDeliveryItem{
+						{Barcode: "4006381333931", Type: "electronics", ProductID: "123e4567-e89b-12d3-a456-426614174001"},
+					},
+				}, nil
+			},
+		}
+		// A product transferred into the PVZ keeps the reception of the
+		// source PVZ, so only the products scanned here are listed.
+		var requestedReceptionID string
+		productRepo := &MockProductRepository{
+			GetProductsByReceptionIDFunc: func(ctx context.Context, receptionID string) ([]model.Product, error) {
+				requestedReceptionID = receptionID
+				return []model.Product{
+					{ID: "123e4567-e89b-12d3-a456-426614174001", ReceptionID: receptionID, Barcode: "4006381333931"},
+				}, nil
+			},
+		}
+		s := newService(deliveryRepo, productRepo, &MockPVZRepository{}, &MockReceptionRepository{}, &MockAuditService{})
+
+		got, err := s.ReconcileReception(context.Background(), reception)
+		if err != nil {
+			t.Fatalf("ReconcileReception() unexpected error = %v", err)
+		}
+
+		expected := &model.DeliveryReconciliation{
+			DeliveryID:    deliveryID,
+			ExpectedCount: 1,
+			MatchedCount:  1,
+			Missing:       []model.ExpectedDeliveryItem{},
+			Unexpected:    []model.Product{},
+		}
+		if !reflect.DeepEqual(got, expected) {
+			t.Errorf("ReconcileReception() = %v, expected %v", got, expected)
+		}
+		if requestedReceptionID != reception.ID {
+			t.Errorf("Expected products of reception %s, got %q", reception.ID, requestedReceptionID)
+		}
+	})
+
+	t.Run("No Delivery", func(t *testing.T) {
+		deliveryRepo := &MockExpectedDeliveryRepository{GetExpectedDeliveryByReceptionIDFunc: noDelivery}
+		s := newService(deliveryRepo, &MockProductRepository{}, &MockPVZRepository{}, &MockReceptionRepository{}, &MockAuditService{})
+
+		got, err := s.ReconcileReception(context.Background(), reception)
+		if err != nil || got != nil {
+			t.Errorf("ReconcileReception() = %v, %v, expected no reconciliation", got, err)
+		}
+		if deliveryRepo.Reconciled != "" {
+			t.Errorf("Expected nothing to be reconciled, got %q", deliveryRepo.Reconciled)
+		}
+	})
+}
+
+func TestExpectedDeliveryService_ReopenReception(t *testing.T) {
+	reception := &model.Reception{ID: receptionID, PVZID: pvzID, Status: "in_progress"}
+
+	deliveryWithStatus := func(status model.ExpectedDeliveryStatus) func(ctx context.Context, receptionID string) (*model.ExpectedDelivery, error) {
+		return func(ctx context.Context, receptionID string) (*model.ExpectedDelivery, error) {
+			return &model.ExpectedDelivery{ID: deliveryID, PVZID: pvzID, Status: status, ReceptionID: receptionID}, nil
+		}
+	}
+
+	t.Run("Reconciled Delivery", func(t *testing.T) {
+		deliveryRepo := &MockExpectedDeliveryRepository{
+			GetExpectedDeliveryByReceptionIDFunc: deliveryWithStatus(model.ExpectedDeliveryStatusReconciled),
+		}
+		auditService := &MockAuditService{}
+		s := newService(deliveryRepo, &MockProductRepository{}, &MockPVZRepository{}, &MockReceptionRepository{}, auditService)
+
+		if err := s.ReopenReception(context.Background(), reception); err != nil {
+			t.Fatalf("ReopenReception() unexpected error = %v", err)
+		}
+		if deliveryRepo.Reopened != deliveryID {
+			t.Errorf("Expected delivery %s to be returned to receiving, got %q", deliveryID, deliveryRepo.Reopened)
+		}
+		if !reflect.DeepEqual(auditService.Actions, []model.AuditAction{model.AuditActionStatusChange}) {
+			t.Errorf("Expected the status change to be audited, got %v", auditService.Actions)
+		}
+	})
+
+	t.Run("Delivery Not Reconciled", func(t *testing.T) {
+		deliveryRepo := &MockExpectedDeliveryRepository{
+			GetExpectedDeliveryByReceptionIDFunc: deliveryWithStatus(model.ExpectedDeliveryStatusReceiving),
+		}
+		s := newService(deliveryRepo, &MockProductRepository{}, &MockPVZRepository{}, &MockReceptionRepository{}, &MockAuditService{})
+
+		if err := s.ReopenReception(context.Background(), reception); err != nil {
+			t.Fatalf("ReopenReception() unexpected error = %v", err)
+		}
+		if deliveryRepo.Reopened != "" {
+			t.Errorf("Expected nothing to be reopened, got %q", deliveryRepo.Reopened)
+		}
+	})
+
+	t.Run("No Delivery", func(t *testing.T) {
+		deliveryRepo := &MockExpectedDeliveryRepository{GetExpectedDeliveryByReceptionIDFunc: noDelivery}
+		s := newService(deliveryRepo, &MockProductRepository{}, &MockPVZRepository{}, &MockReceptionRepository{}, &MockAuditService{})
+
+		if err := s.ReopenReception(context.Background(), reception); err != nil {
+			t.Errorf("ReopenReception() unexpected error = %v", err)
+		}
+	})
+}
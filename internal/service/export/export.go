@@ -0,0 +1,92 @@
+package export
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/kirillidk/pvz-service/internal/dto"
+	"github.com/kirillidk/pvz-service/internal/model"
+	"github.com/kirillidk/pvz-service/internal/repository"
+	"github.com/kirillidk/pvz-service/internal/spreadsheet"
+)
+
+const exportTimeFormat = "2006-01-02 15:04:05"
+
+var receptionExportHeader = []string{
+	"ID ПВЗ", "Город", "Адрес", "Статус ПВЗ",
+	"ID приёмки", "Начало приёмки", "Статус приёмки", "Закрытие приёмки",
+	"ID товара", "Время добавления", "Тип товара", "Серийный номер", "Штрихкод",
+}
+
+type ExportServiceInterface interface {
+	ExportReceptions(ctx context.Context, query dto.ReceptionExportQuery, w io.Writer) error
+}
+
+type ExportService struct {
+	exportRepository repository.ExportRepositoryInterface
+}
+
+func NewExportService(exportRepo repository.ExportRepositoryInterface) *ExportService {
+	return &ExportService{
+		exportRepository: exportRepo,
+	}
+}
+
+// ExportReceptions writes one row per product to w in the requested format.
+// Nothing is written until the first row has been read, so a failing query
+// leaves w untouched and the caller can still report the error.
+func (s *ExportService) ExportReceptions(ctx context.Context, query dto.ReceptionExportQuery, w io.Writer) error {
+	var sheet spreadsheet.Writer
+
+	open := func() error {
+		if sheet != nil {
+			return nil
+		}
+
+		var err error
+		if sheet, err = spreadsheet.NewWriter(query.Format, w); err != nil {
+			return err
+		}
+
+		return sheet.WriteRow(receptionExportHeader)
+	}
+
+	err := s.exportRepository.ExportReceptionRows(ctx, query.PVZFilterQuery, func(row model.ReceptionExportRow) error {
+		if err := open(); err != nil {
+			return err
+		}
+		return sheet.WriteRow(receptionExportRecord(row))
+	})
+	if err != nil {
+		return fmt.Errorf("failed to export receptions: %w", err)
+	}
+
+	if err := open(); err != nil {
+		return fmt.Errorf("failed to export receptions: %w", err)
+	}
+
+	if err := sheet.Close(); err != nil {
+		return fmt.Errorf("failed to export receptions: %w", err)
+	}
+
+	return nil
+}
+
+func receptionExportRecord(row model.ReceptionExportRow) []string {
+	closedAt := ""
+	if row.ReceptionClosedAt != nil {
+		closedAt = formatTime(*row.ReceptionClosedAt)
+	}
+
+	return []string{
+		row.PVZID, row.City, row.Address, string(row.PVZStatus),
+		row.ReceptionID, formatTime(row.ReceptionDateTime), row.ReceptionStatus, closedAt,
+		row.ProductID, formatTime(row.ProductDateTime), row.ProductType, row.SerialNumber, row.Barcode,
+	}
+}
+
+func formatTime(t time.Time) string {
+	return t.Format(exportTimeFormat)
+}
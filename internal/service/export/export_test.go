@@ -0,0 +1,118 @@
+package export_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kirillidk/pvz-service/internal/dto"
+	"github.com/kirillidk/pvz-service/internal/model"
+	"github.com/kirillidk/pvz-service/internal/service/export"
+)
+
+type MockExportRepository struct {
+	ExportReceptionRowsFunc func(ctx context.Context, filter dto.PVZFilterQuery, fn func(model.ReceptionExportRow) error) error
+}
+
+func (m *MockExportRepository) ExportReceptionRows(ctx context.Context, filter dto.PVZFilterQuery, fn func(model.ReceptionExportRow) error) error {
+	return m.ExportReceptionRowsFunc(ctx, filter, fn)
+}
+
+func TestExportService_ExportReceptions(t *testing.T) {
+	ctx := context.Background()
+	testTime := time.Date(2025, 4, 15, 10, 30, 0, 0, time.UTC)
+
+	row := model.ReceptionExportRow{
+		PVZID:             "pvz-id",
+		City:              "Казань",
+		PVZStatus:         model.PVZStatusActive,
+		ReceptionID:       "reception-id",
+		ReceptionDateTime: testTime,
+		ReceptionStatus:   "in_progress",
+		ProductID:         "product-id",
+		ProductDateTime:   testTime,
+		ProductType:       "электроника",
+	}
+
+	header := "\xEF\xBB\xBFID ПВЗ,Город,Адрес,Статус ПВЗ,ID приёмки,Начало приёмки,Статус приёмки,Закрытие приёмки,ID товара,Время добавления,Тип товара,Серийный номер,Штрихкод\n"
+
+	tests := []struct {
+		name          string
+		mockRepo      *MockExportRepository
+		expected      string
+		expectedError bool
+	}{
+		{
+			name: "Success",
+			mockRepo: &MockExportRepository{
+				ExportReceptionRowsFunc: func(ctx context.Context, filter dto.PVZFilterQuery, fn func(model.ReceptionExportRow) error) error {
+					if filter.City[0] != "Казань" {
+						return errors.New("filter not propagated")
+					}
+					return fn(row)
+				},
+			},
+			expected: header + "pvz-id,Казань,,active,reception-id,2025-04-15 10:30:00,in_progress,,product-id,2025-04-15 10:30:00,электроника,,\n",
+		},
+		{
+			name: "No Rows",
+			mockRepo: &MockExportRepository{
+				ExportReceptionRowsFunc: func(ctx context.Context, filter dto.PVZFilterQuery, fn func(model.ReceptionExportRow) error) error {
+					return nil
+				},
+			},
+			expected: header,
+		},
+		{
+			name: "Query Error Writes Nothing",
+			mockRepo: &MockExportRepository{
+				ExportReceptionRowsFunc: func(ctx context.Context, filter dto.PVZFilterQuery, fn func(model.ReceptionExportRow) error) error {
+					return errors.New("db error")
+				},
+			},
+			expected:      "",
+			expectedError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			s := export.NewExportService(tt.mockRepo)
+
+			query := dto.ReceptionExportQuery{
+				PVZFilterQuery: dto.PVZFilterQuery{City: []string{"Казань"}},
+				Format:         "csv",
+			}
+			err := s.ExportReceptions(ctx, query, &buf)
+
+			if (err != nil) != tt.expectedError {
+				t.Fatalf("ExportService.ExportReceptions() error = %v, expectedError %v", err, tt.expectedError)
+			}
+
+			if buf.String() != tt.expected {
+				t.Errorf("ExportService.ExportReceptions() wrote %q, expected %q", buf.String(), tt.expected)
+			}
+		})
+	}
+
+	t.Run("XLSX", func(t *testing.T) {
+		var buf bytes.Buffer
+		s := export.NewExportService(&MockExportRepository{
+			ExportReceptionRowsFunc: func(ctx context.Context, filter dto.PVZFilterQuery, fn func(model.ReceptionExportRow) error) error {
+				return fn(row)
+			},
+		})
+
+		if err := s.ExportReceptions(ctx, dto.ReceptionExportQuery{Format: "xlsx"}, &buf); err != nil {
+			t.Fatalf("ExportService.ExportReceptions() error = %v", err)
+		}
+
+		if !strings.HasPrefix(buf.String(), "PK") {
+			t.Errorf("ExportService.ExportReceptions() did not produce a zip archive")
+		}
+	})
+}
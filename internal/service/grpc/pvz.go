@@ -5,18 +5,31 @@ import (
 
 	pvz_v1 "github.com/kirillidk/pvz-service/api/proto/pvz/pvz_v1"
 	"github.com/kirillidk/pvz-service/internal/dto"
+	"github.com/kirillidk/pvz-service/internal/model"
 	"github.com/kirillidk/pvz-service/internal/repository"
+	pvzservice "github.com/kirillidk/pvz-service/internal/service/pvz"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
+const (
+	defaultNearbyRadius = 5000
+	maxNearbyRadius     = 50000
+	defaultNearbyLimit  = 10
+	maxNearbyLimit      = 100
+)
+
 type PVZService struct {
 	pvzRepository repository.PVZRepositoryInterface
+	pvzService    pvzservice.PVZServiceInterface
 	pvz_v1.UnimplementedPVZServiceServer
 }
 
-func NewPVZService(pvzRepo repository.PVZRepositoryInterface) *PVZService {
+func NewPVZService(pvzRepo repository.PVZRepositoryInterface, pvzService pvzservice.PVZServiceInterface) *PVZService {
 	return &PVZService{
 		pvzRepository: pvzRepo,
+		pvzService:    pvzService,
 	}
 }
 
@@ -36,12 +49,98 @@ func (s *PVZService) GetPVZList(ctx context.Context, req *pvz_v1.GetPVZListReque
 	}
 
 	for _, pvz := range pvzList {
-		response.Pvzs = append(response.Pvzs, &pvz_v1.PVZ{
-			Id:               pvz.ID,
-			RegistrationDate: timestamppb.New(pvz.RegistrationDate),
-			City:             pvz.City,
+		response.Pvzs = append(response.Pvzs, toProtoPVZ(pvz))
+	}
+
+	return response, nil
+}
+
+func (s *PVZService) GetNearbyPVZList(ctx context.Context, req *pvz_v1.GetNearbyPVZListRequest) (*pvz_v1.GetNearbyPVZListResponse, error) {
+	query := dto.PVZNearbyQuery{
+		Latitude:  &req.Latitude,
+		Longitude: &req.Longitude,
+		Radius:    req.Radius,
+		Limit:     req.Limit,
+		OpenNow:   req.OpenNow,
+	}
+
+	if query.Radius == 0 {
+		query.Radius = defaultNearbyRadius
+	}
+	if query.Limit == 0 {
+		query.Limit = defaultNearbyLimit
+	}
+
+	switch {
+	case req.Latitude < -90 || req.Latitude > 90:
+		return nil, status.Error(codes.InvalidArgument, "latitude must be between -90 and 90")
+	case req.Longitude < -180 || req.Longitude > 180:
+		return nil, status.Error(codes.InvalidArgument, "longitude must be between -180 and 180")
+	case query.Radius < 0 || query.Radius > maxNearbyRadius:
+		return nil, status.Errorf(codes.InvalidArgument, "radius must be between 0 and %d meters", maxNearbyRadius)
+	case query.Limit < 0 || query.Limit > maxNearbyLimit:
+		return nil, status.Errorf(codes.InvalidArgument, "limit must be between 1 and %d", maxNearbyLimit)
+	}
+
+	nearbyList, err := s.pvzService.GetNearbyPVZList(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+
+	response := &pvz_v1.GetNearbyPVZListResponse{
+		Pvzs: make([]*pvz_v1.NearbyPVZ, 0, len(nearbyList)),
+	}
+
+	for _, nearby := range nearbyList {
+		response.Pvzs = append(response.Pvzs, &pvz_v1.NearbyPVZ{
+			Pvz:      toProtoPVZ(nearby.PVZ),
+			Distance: nearby.Distance,
 		})
 	}
 
 	return response, nil
 }
+
+func toProtoPVZ(pvz model.PVZ) *pvz_v1.PVZ {
+	return &pvz_v1.PVZ{
+		Id:               pvz.ID,
+		RegistrationDate: timestamppb.New(pvz.RegistrationDate),
+		City:             pvz.City,
+		Address:          pvz.Address,
+		Latitude:         pvz.Latitude,
+		Longitude:        pvz.Longitude,
+		Phone:            pvz.Phone,
+		WorkingHours:     toProtoWorkingHours(pvz.WorkingHours),
+		Status:           string(pvz.Status),
+	}
+}
+
+func toProtoWorkingHours(workingHours *model.WorkingHours) *pvz_v1.WorkingHours {
+	if workingHours == nil {
+		return nil
+	}
+
+	result := &pvz_v1.WorkingHours{
+		Weekly:     make([]*pvz_v1.DailyHours, 0, len(workingHours.Weekly)),
+		Exceptions: make([]*pvz_v1.HoursException, 0, len(workingHours.Exceptions)),
+	}
+
+	for _, day := range workingHours.Weekly {
+		result.Weekly = append(result.Weekly, &pvz_v1.DailyHours{
+			Day:   day.Day,
+			Open:  day.Open,
+			Close: day.Close,
+		})
+	}
+
+	for _, exception := range workingHours.Exceptions {
+		result.Exceptions = append(result.Exceptions, &pvz_v1.HoursException{
+			Date:   exception.Date,
+			Closed: exception.Closed,
+			Open:   exception.Open,
+			Close:  exception.Close,
+		})
+	}
+
+	return result
+}
@@ -11,6 +11,8 @@ import (
 	"github.com/kirillidk/pvz-service/internal/dto"
 	"github.com/kirillidk/pvz-service/internal/model"
 	grpcservice "github.com/kirillidk/pvz-service/internal/service/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
@@ -18,6 +20,12 @@ type MockPVZRepository struct {
 	CreatePVZFunc  func(ctx context.Context, req dto.PVZCreateRequest) (*model.PVZ, error)
 	GetPVZListFunc func(ctx context.Context, filter dto.PVZFilterQuery) ([]model.PVZ, error)
 	GetPVZByIDFunc func(ctx context.Context, pvzID string) (*model.PVZ, error)
+	UpdatePVZFunc  func(ctx context.Context, pvzID string, req dto.PVZUpdateRequest) (*model.PVZ, error)
+
+	GetNearbyPVZListFunc func(ctx context.Context, query dto.PVZNearbyQuery) ([]model.NearbyPVZ, error)
+	GetPVZForUpdateFunc  func(ctx context.Context, pvzID string) (*model.PVZ, error)
+	UpdatePVZStatusFunc  func(ctx context.Context, pvzID string, status model.PVZStatus) (*model.PVZ, error)
+	StreamPVZListFunc    func(ctx context.Context, filter dto.PVZFilterQuery, fn func(model.PVZ, *model.Reception, *model.Product) error) error
 }
 
 func (m *MockPVZRepository) CreatePVZ(ctx context.Context, req dto.PVZCreateRequest) (*model.PVZ, error) {
@@ -32,8 +40,61 @@ func (m *MockPVZRepository) GetPVZByID(ctx context.Context, pvzID string) (*mode
 	return m.GetPVZByIDFunc(ctx, pvzID)
 }
 
+func (m *MockPVZRepository) UpdatePVZ(ctx context.Context, pvzID string, req dto.PVZUpdateRequest) (*model.PVZ, error) {
+	return m.UpdatePVZFunc(ctx, pvzID, req)
+}
+
+func (m *MockPVZRepository) GetNearbyPVZList(ctx context.Context, query dto.PVZNearbyQuery) ([]model.NearbyPVZ, error) {
+	return m.GetNearbyPVZListFunc(ctx, query)
+}
+
+func (m *MockPVZRepository) GetPVZForUpdate(ctx context.Context, pvzID string) (*model.PVZ, error) {
+	return m.GetPVZForUpdateFunc(ctx, pvzID)
+}
+
+func (m *MockPVZRepository) UpdatePVZStatus(ctx context.Context, pvzID string, status model.PVZStatus) (*model.PVZ, error) {
+	return m.UpdatePVZStatusFunc(ctx, pvzID, status)
+}
+
+func (m *MockPVZRepository) StreamPVZList(ctx context.Context, filter dto.PVZFilterQuery, fn func(model.PVZ, *model.Reception, *model.Product) error) error {
+	return m.StreamPVZListFunc(ctx, filter, fn)
+}
+
+type MockPVZService struct {
+	GetNearbyPVZListFunc func(ctx context.Context, query dto.PVZNearbyQuery) ([]model.NearbyPVZ, error)
+}
+
+func (m *MockPVZService) CreatePVZ(ctx context.Context, req dto.PVZCreateRequest) (*model.PVZ, error) {
+	return nil, nil
+}
+
+func (m *MockPVZService) GetPVZList(ctx context.Context, filter dto.PVZFilterQuery) (*dto.PaginatedResponse, error) {
+	return nil, nil
+}
+
+func (m *MockPVZService) GetPVZ(ctx context.Context, pvzID string, query dto.PVZDetailsQuery) (*dto.PVZDetailsResponse, error) {
+	return nil, nil
+}
+
+func (m *MockPVZService) UpdatePVZ(ctx context.Context, pvzID string, req dto.PVZUpdateRequest) (*model.PVZ, error) {
+	return nil, nil
+}
+
+func (m *MockPVZService) GetNearbyPVZList(ctx context.Context, query dto.PVZNearbyQuery) ([]model.NearbyPVZ, error) {
+	return m.GetNearbyPVZListFunc(ctx, query)
+}
+
+func (m *MockPVZService) ChangePVZStatus(ctx context.Context, pvzID string, status model.PVZStatus) (*model.PVZ, error) {
+	return nil, nil
+}
+
+func (m *MockPVZService) StreamPVZList(ctx context.Context, filter dto.PVZFilterQuery, fn func(dto.PVZWithReceptionsResponse) error) error {
+	return nil
+}
+
 func TestPVZService_GetPVZList(t *testing.T) {
 	now := time.Now()
+	latitude, longitude := 55.7575, 37.6136
 
 	tests := []struct {
 		name          string
@@ -102,6 +163,51 @@ func TestPVZService_GetPVZList(t *testing.T) {
 			},
 			expectedError: false,
 		},
+		{
+			name: "Success - With Details",
+			mockRepo: &MockPVZRepository{
+				GetPVZListFunc: func(ctx context.Context, filter dto.PVZFilterQuery) ([]model.PVZ, error) {
+					return []model.PVZ{
+						{
+							ID:               "pvz-id-1",
+							RegistrationDate: now,
+							City:             "Москва",
+							Address:          "ул. Тверская, 1",
+							Latitude:         &latitude,
+							Longitude:        &longitude,
+							Phone:            "+74951234567",
+							WorkingHours: &model.WorkingHours{
+								Weekly: []model.DailyHours{{Day: "monday", Open: "09:00", Close: "21:00"}},
+								Exceptions: []model.HoursException{
+									{Date: "2025-05-01", Closed: true},
+								},
+							},
+						},
+					}, nil
+				},
+			},
+			request: &pvz_v1.GetPVZListRequest{},
+			expected: &pvz_v1.GetPVZListResponse{
+				Pvzs: []*pvz_v1.PVZ{
+					{
+						Id:               "pvz-id-1",
+						RegistrationDate: timestamppb.New(now),
+						City:             "Москва",
+						Address:          "ул. Тверская, 1",
+						Latitude:         &latitude,
+						Longitude:        &longitude,
+						Phone:            "+74951234567",
+						WorkingHours: &pvz_v1.WorkingHours{
+							Weekly: []*pvz_v1.DailyHours{{Day: "monday", Open: "09:00", Close: "21:00"}},
+							Exceptions: []*pvz_v1.HoursException{
+								{Date: "2025-05-01", Closed: true},
+							},
+						},
+					},
+				},
+			},
+			expectedError: false,
+		},
 		{
 			name: "Success - Empty PVZ List",
 			mockRepo: &MockPVZRepository{
@@ -158,7 +264,7 @@ func TestPVZService_GetPVZList(t *testing.T) {
 
 	for ttNum, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			s := grpcservice.NewPVZService(tt.mockRepo)
+			s := grpcservice.NewPVZService(tt.mockRepo, &MockPVZService{})
 
 			got, err := s.GetPVZList(context.Background(), tt.request)
 
@@ -175,3 +281,111 @@ func TestPVZService_GetPVZList(t *testing.T) {
 		})
 	}
 }
+
+func TestPVZService_GetNearbyPVZList(t *testing.T) {
+	now := time.Now()
+	latitude, longitude := 55.7575, 37.6136
+
+	tests := []struct {
+		name         string
+		mockService  *MockPVZService
+		request      *pvz_v1.GetNearbyPVZListRequest
+		expected     *pvz_v1.GetNearbyPVZListResponse
+		expectedCode codes.Code
+	}{
+		{
+			name: "Success - Defaults Applied",
+			mockService: &MockPVZService{
+				GetNearbyPVZListFunc: func(ctx context.Context, query dto.PVZNearbyQuery) ([]model.NearbyPVZ, error) {
+					if query.Radius != 5000 || query.Limit != 10 || !query.OpenNow {
+						t.Errorf("Unexpected query %+v", query)
+					}
+					return []model.NearbyPVZ{
+						{
+							PVZ: model.PVZ{
+								ID:               "pvz-id-1",
+								RegistrationDate: now,
+								City:             "Москва",
+								Latitude:         &latitude,
+								Longitude:        &longitude,
+							},
+							Distance: 420.5,
+						},
+					}, nil
+				},
+			},
+			request: &pvz_v1.GetNearbyPVZListRequest{
+				Latitude:  55.76,
+				Longitude: 37.61,
+				OpenNow:   true,
+			},
+			expected: &pvz_v1.GetNearbyPVZListResponse{
+				Pvzs: []*pvz_v1.NearbyPVZ{
+					{
+						Pvz: &pvz_v1.PVZ{
+							Id:               "pvz-id-1",
+							RegistrationDate: timestamppb.New(now),
+							City:             "Москва",
+							Latitude:         &latitude,
+							Longitude:        &longitude,
+						},
+						Distance: 420.5,
+					},
+				},
+			},
+			expectedCode: codes.OK,
+		},
+		{
+			name:        "Invalid Latitude",
+			mockService: &MockPVZService{},
+			request: &pvz_v1.GetNearbyPVZListRequest{
+				Latitude:  91,
+				Longitude: 37.61,
+			},
+			expected:     nil,
+			expectedCode: codes.InvalidArgument,
+		},
+		{
+			name:        "Radius Too Large",
+			mockService: &MockPVZService{},
+			request: &pvz_v1.GetNearbyPVZListRequest{
+				Latitude:  55.76,
+				Longitude: 37.61,
+				Radius:    100000,
+			},
+			expected:     nil,
+			expectedCode: codes.InvalidArgument,
+		},
+		{
+			name: "Service Error",
+			mockService: &MockPVZService{
+				GetNearbyPVZListFunc: func(ctx context.Context, query dto.PVZNearbyQuery) ([]model.NearbyPVZ, error) {
+					return nil, errors.New("service error")
+				},
+			},
+			request: &pvz_v1.GetNearbyPVZListRequest{
+				Latitude:  55.76,
+				Longitude: 37.61,
+			},
+			expected:     nil,
+			expectedCode: codes.Unknown,
+		},
+	}
+
+	for ttNum, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := grpcservice.NewPVZService(&MockPVZRepository{}, tt.mockService)
+
+			got, err := s.GetNearbyPVZList(context.Background(), tt.request)
+
+			if code := status.Code(err); code != tt.expectedCode {
+				t.Errorf("Test %v: PVZService.GetNearbyPVZList() code = %v, expected %v", ttNum, code, tt.expectedCode)
+				return
+			}
+
+			if !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("Test %v: PVZService.GetNearbyPVZList() = %v, expected %v", ttNum, got, tt.expected)
+			}
+		})
+	}
+}
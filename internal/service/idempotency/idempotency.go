@@ -0,0 +1,53 @@
+package idempotency
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/kirillidk/pvz-service/internal/config"
+	"github.com/kirillidk/pvz-service/internal/repository"
+	"github.com/kirillidk/pvz-service/internal/service/periodic"
+)
+
+type IdempotencyServiceInterface interface {
+	PurgeExpired(ctx context.Context) (int64, error)
+}
+
+type IdempotencyService struct {
+	idempotencyRepository repository.IdempotencyRepositoryInterface
+	idempotencyConfig     config.IdempotencyConfig
+}
+
+func NewIdempotencyService(idempotencyRepo repository.IdempotencyRepositoryInterface, idempotencyCfg config.IdempotencyConfig) *IdempotencyService {
+	return &IdempotencyService{
+		idempotencyRepository: idempotencyRepo,
+		idempotencyConfig:     idempotencyCfg,
+	}
+}
+
+// PurgeExpired deletes idempotency keys that can no longer be replayed and
+// returns how many were deleted.
+func (s *IdempotencyService) PurgeExpired(ctx context.Context) (int64, error) {
+	deleted, err := s.idempotencyRepository.DeleteExpired(ctx, time.Now().UTC())
+	if err != nil {
+		return 0, fmt.Errorf("failed to purge idempotency keys: %w", err)
+	}
+
+	return deleted, nil
+}
+
+// RunPurgeScheduler calls PurgeExpired at start and then every
+// PurgeInterval until ctx is done. Failures are logged and retried on the
+// next tick.
+func (s *IdempotencyService) RunPurgeScheduler(ctx context.Context) {
+	periodic.Run(ctx, s.idempotencyConfig.PurgeInterval, func(ctx context.Context) {
+		deleted, err := s.PurgeExpired(ctx)
+		if err != nil {
+			log.Printf("idempotency purge error: %v", err)
+		} else if deleted > 0 {
+			log.Printf("idempotency purge deleted %d expired keys", deleted)
+		}
+	})
+}
@@ -0,0 +1,111 @@
+package idempotency_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/kirillidk/pvz-service/internal/config"
+	"github.com/kirillidk/pvz-service/internal/model"
+	"github.com/kirillidk/pvz-service/internal/service/idempotency"
+)
+
+type MockIdempotencyRepository struct {
+	DeleteExpiredFunc func(ctx context.Context, now time.Time) (int64, error)
+}
+
+func (m *MockIdempotencyRepository) Reserve(ctx context.Context, record model.IdempotencyRecord) (*model.IdempotencyRecord, bool, error) {
+	return nil, false, nil
+}
+
+func (m *MockIdempotencyRepository) Complete(
+	ctx context.Context,
+	scope, key, token string,
+	statusCode int,
+	contentType string,
+	body []byte,
+	expiresAt time.Time,
+) error {
+	return nil
+}
+
+func (m *MockIdempotencyRepository) Release(ctx context.Context, scope, key, token string) error {
+	return nil
+}
+
+func (m *MockIdempotencyRepository) DeleteExpired(ctx context.Context, now time.Time) (int64, error) {
+	return m.DeleteExpiredFunc(ctx, now)
+}
+
+func TestIdempotencyService_PurgeExpired(t *testing.T) {
+	tests := []struct {
+		name          string
+		deleted       int64
+		repoErr       error
+		expectedError bool
+	}{
+		{name: "Success", deleted: 3},
+		{name: "Repository Error", repoErr: errors.New("db error"), expectedError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &MockIdempotencyRepository{
+				DeleteExpiredFunc: func(ctx context.Context, now time.Time) (int64, error) {
+					return tt.deleted, tt.repoErr
+				},
+			}
+
+			s := idempotency.NewIdempotencyService(repo, config.IdempotencyConfig{PurgeInterval: time.Hour})
+			deleted, err := s.PurgeExpired(context.Background())
+
+			if tt.expectedError {
+				if err == nil {
+					t.Error("PurgeExpired() expected an error")
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("PurgeExpired() unexpected error = %v", err)
+			}
+			if deleted != tt.deleted {
+				t.Errorf("PurgeExpired() = %d, expected %d", deleted, tt.deleted)
+			}
+		})
+	}
+}
+
+func TestIdempotencyService_RunPurgeScheduler(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	calls := 0
+	repo := &MockIdempotencyRepository{
+		DeleteExpiredFunc: func(ctx context.Context, now time.Time) (int64, error) {
+			calls++
+			if calls == 2 {
+				cancel()
+			}
+			return 0, nil
+		},
+	}
+
+	s := idempotency.NewIdempotencyService(repo, config.IdempotencyConfig{PurgeInterval: time.Millisecond})
+
+	done := make(chan struct{})
+	go func() {
+		s.RunPurgeScheduler(ctx)
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("RunPurgeScheduler() did not stop after the context was cancelled")
+	}
+
+	if calls != 2 {
+		t.Errorf("Expected 2 purge runs, got %d", calls)
+	}
+}
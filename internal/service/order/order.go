@@ -0,0 +1,271 @@
+package order
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/kirillidk/pvz-service/internal/config"
+	"github.com/kirillidk/pvz-service/internal/dto"
+	"github.com/kirillidk/pvz-service/internal/model"
+	"github.com/kirillidk/pvz-service/internal/notifier"
+	"github.com/kirillidk/pvz-service/internal/repository"
+	"github.com/kirillidk/pvz-service/internal/service/audit"
+	"github.com/kirillidk/pvz-service/internal/service/productlifecycle"
+)
+
+type OrderServiceInterface interface {
+	CreateOrder(ctx context.Context, pvzID string, orderCreateReq dto.OrderCreateRequest) (*model.Order, error)
+	RegeneratePickupCode(ctx context.Context, pvzID, orderID string) (*model.Order, error)
+	IssueOrder(ctx context.Context, pvzID string, orderIssueReq dto.OrderIssueRequest) (*dto.OrderIssueResponse, error)
+}
+
+type OrderService struct {
+	orderRepository         repository.OrderRepositoryInterface
+	productRepository       repository.ProductRepositoryInterface
+	productLifecycleService productlifecycle.ProductLifecycleServiceInterface
+	transactor              repository.TransactorInterface
+	auditService            audit.AuditServiceInterface
+	notifier                notifier.Notifier
+	pickupConfig            config.PickupConfig
+}
+
+func NewOrderService(
+	orderRepo repository.OrderRepositoryInterface,
+	productRepo repository.ProductRepositoryInterface,
+	productLifecycleService productlifecycle.ProductLifecycleServiceInterface,
+	transactor repository.TransactorInterface,
+	auditService audit.AuditServiceInterface,
+	pickupNotifier notifier.Notifier,
+	pickupCfg config.PickupConfig,
+) *OrderService {
+	return &OrderService{
+		orderRepository:         orderRepo,
+		productRepository:       productRepo,
+		productLifecycleService: productLifecycleService,
+		transactor:              transactor,
+		auditService:            auditService,
+		notifier:                pickupNotifier,
+		pickupConfig:            pickupCfg,
+	}
+}
+
+// CreateOrder groups products of the PVZ that are still waiting for their
+// customer into an order and sends its pickup code to the recipient.
+func (s *OrderService) CreateOrder(
+	ctx context.Context,
+	pvzID string,
+	orderCreateReq dto.OrderCreateRequest,
+) (*model.Order, error) {
+	seen := make(map[string]bool, len(orderCreateReq.ProductIDs))
+	for _, productID := range orderCreateReq.ProductIDs {
+		if seen[productID] {
+			return nil, fmt.Errorf("product %s is listed more than once", productID)
+		}
+		seen[productID] = true
+	}
+
+	var (
+		createdOrder *model.Order
+		code         string
+	)
+
+	err := s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		for _, productID := range orderCreateReq.ProductIDs {
+			location, err := s.productRepository.GetProductLocationForUpdate(ctx, productID)
+			if err != nil {
+				return fmt.Errorf("failed to get product: %w", err)
+			}
+
+			if location.PVZID != pvzID {
+				return fmt.Errorf("failed to get product: %w", model.ErrProductNotFound)
+			}
+
+			if location.OrderID != "" {
+				return model.ErrProductInOrder
+			}
+
+			if location.TransferID != "" {
+				return model.ErrProductInTransfer
+			}
+
+			if location.Status != model.ProductStatusReceived && location.Status != model.ProductStatusStored {
+				return fmt.Errorf("product %s is already %s", productID, location.Status)
+			}
+		}
+
+		var (
+			hash string
+			err  error
+		)
+		code, hash, err = generatePickupCode()
+		if err != nil {
+			return err
+		}
+
+		createdOrder, err = s.orderRepository.CreateOrder(ctx, model.Order{
+			PVZID:          pvzID,
+			Recipient:      orderCreateReq.Recipient,
+			ProductIDs:     orderCreateReq.ProductIDs,
+			CreatedAt:      time.Now().UTC(),
+			PickupCodeHash: hash,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to create order: %w", err)
+		}
+
+		return s.auditService.Record(ctx, model.AuditActionCreate, model.AuditEntityOrder, createdOrder.ID, nil, createdOrder)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.notifyPickupCode(ctx, createdOrder, code)
+
+	return createdOrder, nil
+}
+
+// RegeneratePickupCode replaces the pickup code of an order that has not
+// been issued yet, which also unlocks an order locked by wrong codes. A new
+// code may only be sent a limited number of times, so that the limit of
+// wrong codes cannot be bypassed by regenerating the code again and again.
+func (s *OrderService) RegeneratePickupCode(ctx context.Context, pvzID, orderID string) (*model.Order, error) {
+	var (
+		order *model.Order
+		code  string
+	)
+
+	err := s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		var err error
+		order, err = s.getPendingOrderForUpdate(ctx, pvzID, orderID)
+		if err != nil {
+			return err
+		}
+
+		if order.CodeRegenerations >= s.pickupConfig.MaxRegenerations {
+			return model.ErrPickupCodeResendLimit
+		}
+
+		var hash string
+		code, hash, err = generatePickupCode()
+		if err != nil {
+			return err
+		}
+
+		if err := s.orderRepository.UpdatePickupCode(ctx, orderID, hash); err != nil {
+			return fmt.Errorf("failed to update pickup code: %w", err)
+		}
+
+		before := *order
+		order.FailedAttempts = 0
+		order.CodeRegenerations++
+
+		return s.auditService.Record(ctx, model.AuditActionUpdate, model.AuditEntityOrder, orderID, before, order)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.notifyPickupCode(ctx, order, code)
+
+	return order, nil
+}
+
+// IssueOrder verifies the pickup code and issues all products of the order
+// in one transaction. Wrong codes are counted even though the call fails,
+// and once the limit is reached the order is locked until a new code is
+// generated.
+func (s *OrderService) IssueOrder(
+	ctx context.Context,
+	pvzID string,
+	orderIssueReq dto.OrderIssueRequest,
+) (*dto.OrderIssueResponse, error) {
+	var (
+		response  *dto.OrderIssueResponse
+		wrongCode bool
+	)
+
+	err := s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		order, err := s.getPendingOrderForUpdate(ctx, pvzID, orderIssueReq.OrderID)
+		if err != nil {
+			return err
+		}
+
+		if order.FailedAttempts >= s.pickupConfig.MaxAttempts {
+			return model.ErrPickupCodeLocked
+		}
+
+		if !checkPickupCode(order.PickupCodeHash, orderIssueReq.Code) {
+			if _, err := s.orderRepository.IncrementFailedAttempts(ctx, order.ID); err != nil {
+				return fmt.Errorf("failed to record failed attempt: %w", err)
+			}
+			// The transaction is committed so that the attempt is counted.
+			wrongCode = true
+			return nil
+		}
+
+		products, err := s.productLifecycleService.IssueOrderProducts(ctx, pvzID, order.ID, order.ProductIDs)
+		if err != nil {
+			return err
+		}
+
+		issuedAt := time.Now().UTC()
+		if err := s.orderRepository.MarkOrderIssued(ctx, order.ID, issuedAt); err != nil {
+			return fmt.Errorf("failed to mark order issued: %w", err)
+		}
+
+		before := *order
+		order.IssuedAt = &issuedAt
+
+		if err := s.auditService.Record(ctx, model.AuditActionIssue, model.AuditEntityOrder, order.ID, before, order); err != nil {
+			return err
+		}
+
+		response = &dto.OrderIssueResponse{Order: *order, Products: products}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if wrongCode {
+		return nil, model.ErrInvalidPickupCode
+	}
+
+	return response, nil
+}
+
+// getPendingOrderForUpdate locks an order of the PVZ that has not been
+// issued yet. Orders of other PVZs are reported as not found.
+func (s *OrderService) getPendingOrderForUpdate(ctx context.Context, pvzID, orderID string) (*model.Order, error) {
+	order, err := s.orderRepository.GetOrderForUpdate(ctx, orderID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get order: %w", err)
+	}
+
+	if order.PVZID != pvzID {
+		return nil, fmt.Errorf("failed to get order: %w", model.ErrOrderNotFound)
+	}
+
+	if order.IssuedAt != nil {
+		return nil, model.ErrOrderAlreadyIssued
+	}
+
+	return order, nil
+}
+
+// notifyPickupCode is called after the transaction is committed. A failed
+// notification does not undo the order: the code can be sent again with
+// RegeneratePickupCode.
+func (s *OrderService) notifyPickupCode(ctx context.Context, order *model.Order, code string) {
+	err := s.notifier.NotifyPickupCode(ctx, notifier.PickupCodeNotification{
+		OrderID:   order.ID,
+		PVZID:     order.PVZID,
+		Recipient: order.Recipient,
+		Code:      code,
+	})
+	if err != nil {
+		log.Printf("failed to send pickup code for order %s: %v", order.ID, err)
+	}
+}
@@ -0,0 +1,477 @@
+package order_test
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/kirillidk/pvz-service/internal/config"
+	"github.com/kirillidk/pvz-service/internal/dto"
+	"github.com/kirillidk/pvz-service/internal/model"
+	"github.com/kirillidk/pvz-service/internal/notifier"
+	"github.com/kirillidk/pvz-service/internal/service/order"
+	"golang.org/x/crypto/bcrypt"
+)
+
+type MockOrderRepository struct {
+	Order          *model.Order
+	CreatedOrder   *model.Order
+	PickupCodeHash string
+	Issued         bool
+
+	GetOrderForUpdateFunc func(ctx context.Context, orderID string) (*model.Order, error)
+}
+
+func (m *MockOrderRepository) CreateOrder(ctx context.Context, o model.Order) (*model.Order, error) {
+	o.ID = "123e4567-e89b-12d3-a456-426614174005"
+	m.CreatedOrder = &o
+	return &o, nil
+}
+
+func (m *MockOrderRepository) GetOrderForUpdate(ctx context.Context, orderID string) (*model.Order, error) {
+	return m.GetOrderForUpdateFunc(ctx, orderID)
+}
+
+func (m *MockOrderRepository) UpdatePickupCode(ctx context.Context, orderID, pickupCodeHash string) error {
+	m.PickupCodeHash = pickupCodeHash
+	return nil
+}
+
+func (m *MockOrderRepository) IncrementFailedAttempts(ctx context.Context, orderID string) (int, error) {
+	m.Order.FailedAttempts++
+	return m.Order.FailedAttempts, nil
+}
+
+func (m *MockOrderRepository) MarkOrderIssued(ctx context.Context, orderID string, issuedAt time.Time) error {
+	m.Issued = true
+	return nil
+}
+
+type MockProductRepository struct {
+	Locations map[string]*model.ProductLocation
+}
+
+func (m *MockProductRepository) CreateProduct(ctx context.Context, receptionID string, attrs dto.ProductAttributes) (*model.Product, error) {
+	return nil, nil
+}
+
+func (m *MockProductRepository) CreateProducts(ctx context.Context, receptionID string, items []dto.ProductAttributes) ([]model.Product, error) {
+	return nil, nil
+}
+
+func (m *MockProductRepository) GetLastProductInReception(ctx context.Context, receptionID string) (*model.Product, error) {
+	return nil, nil
+}
+
+func (m *MockProductRepository) DeleteProduct(ctx context.Context, productID string) error {
+	return nil
+}
+
+func (m *MockProductRepository) GetProductsByReceptionID(ctx context.Context, receptionID string) ([]model.Product, error) {
+	return nil, nil
+}
+
+func (m *MockProductRepository) GetProductsByReceptionIDInInsertionOrder(ctx context.Context, receptionID string) ([]model.Product, error) {
+	return nil, nil
+}
+
+func (m *MockProductRepository) GetProductsByBarcodes(ctx context.Context, barcodes []string) ([]model.ProductLocation, error) {
+	return nil, nil
+}
+
+func (m *MockProductRepository) GetProductLocation(ctx context.Context, productID string) (*model.ProductLocation, error) {
+	return m.GetProductLocationForUpdate(ctx, productID)
+}
+
+func (m *MockProductRepository) GetProductLocationForUpdate(ctx context.Context, productID string) (*model.ProductLocation, error) {
+	location, ok := m.Locations[productID]
+	if !ok {
+		return nil, model.ErrProductNotFound
+	}
+	return location, nil
+}
+
+func (m *MockProductRepository) UpdateProductStatus(ctx context.Context, productID string, status model.ProductStatus) (*model.Product, error) {
+	return nil, nil
+}
+
+func (m *MockProductRepository) UpdateProductCell(ctx context.Context, productID, cellID string) (*model.Product, error) {
+	return nil, nil
+}
+
+func (m *MockProductRepository) UpdateProductCondition(
+	ctx context.Context,
+	productID string,
+	condition model.ProductCondition,
+	note string,
+) (*model.Product, error) {
+	return nil, nil
+}
+
+type MockProductLifecycleService struct {
+	IssuedProductIDs []string
+}
+
+func (m *MockProductLifecycleService) ChangeProductStatus(
+	ctx context.Context,
+	pvzID, productID string,
+	status model.ProductStatus,
+) (*model.Product, error) {
+	return nil, nil
+}
+
+func (m *MockProductLifecycleService) GetProductStatusHistory(
+	ctx context.Context,
+	pvzID, productID string,
+) ([]model.ProductStatusChange, error) {
+	return nil, nil
+}
+
+func (m *MockProductLifecycleService) IssueOrderProducts(
+	ctx context.Context,
+	pvzID, orderID string,
+	productIDs []string,
+) ([]model.Product, error) {
+	m.IssuedProductIDs = productIDs
+
+	products := make([]model.Product, len(productIDs))
+	for i, id := range productIDs {
+		products[i] = model.Product{ID: id, Status: model.ProductStatusIssued}
+	}
+	return products, nil
+}
+
+type MockTransactor struct{}
+
+func (m *MockTransactor) WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	return fn(ctx)
+}
+
+type MockAuditService struct {
+	Actions []model.AuditAction
+}
+
+func (m *MockAuditService) Record(
+	ctx context.Context,
+	action model.AuditAction,
+	entityType model.AuditEntityType,
+	entityID string,
+	before, after any,
+) error {
+	m.Actions = append(m.Actions, action)
+	return nil
+}
+
+func (m *MockAuditService) GetAuditLog(ctx context.Context, filter dto.AuditFilterQuery) ([]model.AuditEntry, error) {
+	return nil, nil
+}
+
+type MockNotifier struct {
+	Notifications []notifier.PickupCodeNotification
+	Err           error
+}
+
+func (m *MockNotifier) NotifyPickupCode(ctx context.Context, notification notifier.PickupCodeNotification) error {
+	m.Notifications = append(m.Notifications, notification)
+	return m.Err
+}
+
+var pickupConfig = config.PickupConfig{MaxAttempts: 3, MaxRegenerations: 2}
+
+func TestOrderService_CreateOrder(t *testing.T) {
+	pvzID := "123e4567-e89b-12d3-a456-426614174003"
+	productID := "123e4567-e89b-12d3-a456-426614174001"
+
+	tests := []struct {
+		name          string
+		productIDs    []string
+		location      *model.ProductLocation
+		notifyErr     error
+		expectedError error
+		expectedMsg   string
+	}{
+		{
+			name:       "Success",
+			productIDs: []string{productID},
+			location: &model.ProductLocation{
+				Product: model.Product{ID: productID, Status: model.ProductStatusStored},
+				PVZID:   pvzID,
+			},
+		},
+		{
+			name:       "Notification Failure Keeps Order",
+			productIDs: []string{productID},
+			location: &model.ProductLocation{
+				Product: model.Product{ID: productID, Status: model.ProductStatusReceived},
+				PVZID:   pvzID,
+			},
+			notifyErr: errors.New("gateway is down"),
+		},
+		{
+			name:       "Product Of Another PVZ",
+			productIDs: []string{productID},
+			location: &model.ProductLocation{
+				Product: model.Product{ID: productID, Status: model.ProductStatusStored},
+				PVZID:   "223e4567-e89b-12d3-a456-426614174003",
+			},
+			expectedError: model.ErrProductNotFound,
+		},
+		{
+			name:       "Product Already In Order",
+			productIDs: []string{productID},
+			location: &model.ProductLocation{
+				Product: model.Product{ID: productID, Status: model.ProductStatusStored},
+				PVZID:   pvzID,
+				OrderID: "123e4567-e89b-12d3-a456-426614174006",
+			},
+			expectedError: model.ErrProductInOrder,
+		},
+		{
+			name:       "Product In Transfer",
+			productIDs: []string{productID},
+			location: &model.ProductLocation{
+				Product:    model.Product{ID: productID, Status: model.ProductStatusStored},
+				PVZID:      pvzID,
+				TransferID: "123e4567-e89b-12d3-a456-426614174008",
+			},
+			expectedError: model.ErrProductInTransfer,
+		},
+		{
+			name:       "Product Already Issued",
+			productIDs: []string{productID},
+			location: &model.ProductLocation{
+				Product: model.Product{ID: productID, Status: model.ProductStatusIssued},
+				PVZID:   pvzID,
+			},
+			expectedMsg: "product 123e4567-e89b-12d3-a456-426614174001 is already issued",
+		},
+		{
+			name:        "Duplicate Product",
+			productIDs:  []string{productID, productID},
+			expectedMsg: "product 123e4567-e89b-12d3-a456-426614174001 is listed more than once",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			orderRepo := &MockOrderRepository{}
+			productRepo := &MockProductRepository{Locations: map[string]*model.ProductLocation{}}
+			if tt.location != nil {
+				productRepo.Locations[productID] = tt.location
+			}
+			auditService := &MockAuditService{}
+			mockNotifier := &MockNotifier{Err: tt.notifyErr}
+
+			s := order.NewOrderService(
+				orderRepo, productRepo, &MockProductLifecycleService{}, &MockTransactor{}, auditService, mockNotifier, pickupConfig,
+			)
+
+			got, err := s.CreateOrder(context.Background(), pvzID, dto.OrderCreateRequest{
+				Recipient:  "+79990000000",
+				ProductIDs: tt.productIDs,
+			})
+
+			if tt.expectedError != nil || tt.expectedMsg != "" {
+				if tt.expectedError != nil && !errors.Is(err, tt.expectedError) {
+					t.Errorf("CreateOrder() error = %v, expected %v", err, tt.expectedError)
+				}
+				if tt.expectedMsg != "" && (err == nil || err.Error() != tt.expectedMsg) {
+					t.Errorf("CreateOrder() error = %v, expected %q", err, tt.expectedMsg)
+				}
+				if orderRepo.CreatedOrder != nil || len(mockNotifier.Notifications) != 0 {
+					t.Error("Expected no order to be created or notified")
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("CreateOrder() unexpected error = %v", err)
+			}
+			if got.ID == "" || got.PVZID != pvzID || !reflect.DeepEqual(got.ProductIDs, tt.productIDs) {
+				t.Errorf("CreateOrder() = %+v", got)
+			}
+
+			if len(mockNotifier.Notifications) != 1 {
+				t.Fatalf("Expected 1 notification, got %d", len(mockNotifier.Notifications))
+			}
+			notification := mockNotifier.Notifications[0]
+			if len(notification.Code) != 6 {
+				t.Errorf("Expected a 6 digit code, got %q", notification.Code)
+			}
+			if notification.Recipient != "+79990000000" || notification.OrderID != got.ID {
+				t.Errorf("Unexpected notification %+v", notification)
+			}
+
+			if err := bcrypt.CompareHashAndPassword([]byte(orderRepo.CreatedOrder.PickupCodeHash), []byte(notification.Code)); err != nil {
+				t.Errorf("Expected the stored hash to match the sent code: %v", err)
+			}
+			if orderRepo.CreatedOrder.PickupCodeHash == notification.Code {
+				t.Error("Expected the code not to be stored in plain text")
+			}
+
+			if !reflect.DeepEqual(auditService.Actions, []model.AuditAction{model.AuditActionCreate}) {
+				t.Errorf("Expected a create audit record, got %v", auditService.Actions)
+			}
+		})
+	}
+}
+
+func TestOrderService_IssueOrder(t *testing.T) {
+	pvzID := "123e4567-e89b-12d3-a456-426614174003"
+	orderID := "123e4567-e89b-12d3-a456-426614174005"
+	productIDs := []string{"123e4567-e89b-12d3-a456-426614174001", "123e4567-e89b-12d3-a456-426614174002"}
+
+	hash, err := bcrypt.GenerateFromPassword([]byte("042137"), bcrypt.MinCost)
+	if err != nil {
+		t.Fatalf("failed to hash code: %v", err)
+	}
+	issuedAt := time.Now()
+
+	tests := []struct {
+		name                   string
+		code                   string
+		order                  model.Order
+		expectedError          error
+		expectedFailedAttempts int
+	}{
+		{
+			name:  "Success",
+			code:  "042137",
+			order: model.Order{ID: orderID, PVZID: pvzID, ProductIDs: productIDs, FailedAttempts: 2},
+		},
+		{
+			name:                   "Wrong Code",
+			code:                   "000000",
+			order:                  model.Order{ID: orderID, PVZID: pvzID, ProductIDs: productIDs, FailedAttempts: 1},
+			expectedError:          model.ErrInvalidPickupCode,
+			expectedFailedAttempts: 2,
+		},
+		{
+			name:                   "Locked",
+			code:                   "042137",
+			order:                  model.Order{ID: orderID, PVZID: pvzID, ProductIDs: productIDs, FailedAttempts: 3},
+			expectedError:          model.ErrPickupCodeLocked,
+			expectedFailedAttempts: 3,
+		},
+		{
+			name:          "Already Issued",
+			code:          "042137",
+			order:         model.Order{ID: orderID, PVZID: pvzID, ProductIDs: productIDs, IssuedAt: &issuedAt},
+			expectedError: model.ErrOrderAlreadyIssued,
+		},
+		{
+			name:          "Order Of Another PVZ",
+			code:          "042137",
+			order:         model.Order{ID: orderID, PVZID: "223e4567-e89b-12d3-a456-426614174003", ProductIDs: productIDs},
+			expectedError: model.ErrOrderNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			o := tt.order
+			o.PickupCodeHash = string(hash)
+
+			orderRepo := &MockOrderRepository{Order: &o}
+			orderRepo.GetOrderForUpdateFunc = func(ctx context.Context, id string) (*model.Order, error) {
+				copied := *orderRepo.Order
+				return &copied, nil
+			}
+			lifecycleService := &MockProductLifecycleService{}
+			auditService := &MockAuditService{}
+
+			s := order.NewOrderService(
+				orderRepo, &MockProductRepository{}, lifecycleService, &MockTransactor{}, auditService, &MockNotifier{}, pickupConfig,
+			)
+
+			got, err := s.IssueOrder(context.Background(), pvzID, dto.OrderIssueRequest{OrderID: orderID, Code: tt.code})
+
+			if tt.expectedError != nil {
+				if !errors.Is(err, tt.expectedError) {
+					t.Errorf("IssueOrder() error = %v, expected %v", err, tt.expectedError)
+				}
+				if orderRepo.Issued || lifecycleService.IssuedProductIDs != nil {
+					t.Error("Expected nothing to be issued")
+				}
+				if tt.expectedFailedAttempts != 0 && orderRepo.Order.FailedAttempts != tt.expectedFailedAttempts {
+					t.Errorf("Expected %d failed attempts, got %d", tt.expectedFailedAttempts, orderRepo.Order.FailedAttempts)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("IssueOrder() unexpected error = %v", err)
+			}
+			if !orderRepo.Issued || got.Order.IssuedAt == nil {
+				t.Error("Expected the order to be marked issued")
+			}
+			if !reflect.DeepEqual(lifecycleService.IssuedProductIDs, productIDs) || len(got.Products) != len(productIDs) {
+				t.Errorf("Expected products %v to be issued, got %v", productIDs, lifecycleService.IssuedProductIDs)
+			}
+			if !reflect.DeepEqual(auditService.Actions, []model.AuditAction{model.AuditActionIssue}) {
+				t.Errorf("Expected an issue audit record, got %v", auditService.Actions)
+			}
+		})
+	}
+}
+
+func TestOrderService_RegeneratePickupCode(t *testing.T) {
+	pvzID := "123e4567-e89b-12d3-a456-426614174003"
+	orderID := "123e4567-e89b-12d3-a456-426614174005"
+
+	orderRepo := &MockOrderRepository{
+		GetOrderForUpdateFunc: func(ctx context.Context, id string) (*model.Order, error) {
+			return &model.Order{ID: id, PVZID: pvzID, Recipient: "+79990000000", FailedAttempts: 3, PickupCodeHash: "old"}, nil
+		},
+	}
+	mockNotifier := &MockNotifier{}
+
+	s := order.NewOrderService(
+		orderRepo, &MockProductRepository{}, &MockProductLifecycleService{}, &MockTransactor{}, &MockAuditService{}, mockNotifier, pickupConfig,
+	)
+
+	got, err := s.RegeneratePickupCode(context.Background(), pvzID, orderID)
+	if err != nil {
+		t.Fatalf("RegeneratePickupCode() unexpected error = %v", err)
+	}
+	if got.FailedAttempts != 0 {
+		t.Errorf("Expected failed attempts to be reset, got %d", got.FailedAttempts)
+	}
+	if got.CodeRegenerations != 1 {
+		t.Errorf("Expected 1 code regeneration, got %d", got.CodeRegenerations)
+	}
+
+	if len(mockNotifier.Notifications) != 1 {
+		t.Fatalf("Expected 1 notification, got %d", len(mockNotifier.Notifications))
+	}
+	code := mockNotifier.Notifications[0].Code
+	if err := bcrypt.CompareHashAndPassword([]byte(orderRepo.PickupCodeHash), []byte(code)); err != nil {
+		t.Errorf("Expected the new hash to match the sent code: %v", err)
+	}
+}
+
+func TestOrderService_RegeneratePickupCode_Limit(t *testing.T) {
+	pvzID := "123e4567-e89b-12d3-a456-426614174003"
+	orderID := "123e4567-e89b-12d3-a456-426614174005"
+
+	orderRepo := &MockOrderRepository{
+		GetOrderForUpdateFunc: func(ctx context.Context, id string) (*model.Order, error) {
+			return &model.Order{ID: id, PVZID: pvzID, FailedAttempts: 3, CodeRegenerations: 2, PickupCodeHash: "old"}, nil
+		},
+	}
+	mockNotifier := &MockNotifier{}
+
+	s := order.NewOrderService(
+		orderRepo, &MockProductRepository{}, &MockProductLifecycleService{}, &MockTransactor{}, &MockAuditService{}, mockNotifier, pickupConfig,
+	)
+
+	_, err := s.RegeneratePickupCode(context.Background(), pvzID, orderID)
+	if !errors.Is(err, model.ErrPickupCodeResendLimit) {
+		t.Errorf("RegeneratePickupCode() error = %v, expected %v", err, model.ErrPickupCodeResendLimit)
+	}
+	if orderRepo.PickupCodeHash != "" || len(mockNotifier.Notifications) != 0 {
+		t.Error("Expected no new code to be sent")
+	}
+}
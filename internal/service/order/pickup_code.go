@@ -0,0 +1,39 @@
+package order
+
+import (
+	"crypto/rand"
+	"fmt"
+	"math/big"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+// pickupCodeDigits is short enough to be dictated over the counter; the
+// attempt limit is what keeps it from being guessed.
+const pickupCodeDigits = 6
+
+// generatePickupCode returns a random numeric code and its bcrypt hash.
+func generatePickupCode() (code, hash string, err error) {
+	limit := big.NewInt(1)
+	for range pickupCodeDigits {
+		limit.Mul(limit, big.NewInt(10))
+	}
+
+	n, err := rand.Int(rand.Reader, limit)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to generate pickup code: %w", err)
+	}
+
+	code = fmt.Sprintf("%0*d", pickupCodeDigits, n)
+
+	hashed, err := bcrypt.GenerateFromPassword([]byte(code), bcrypt.DefaultCost)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to hash pickup code: %w", err)
+	}
+
+	return code, string(hashed), nil
+}
+
+func checkPickupCode(hash, code string) bool {
+	return bcrypt.CompareHashAndPassword([]byte(hash), []byte(code)) == nil
+}
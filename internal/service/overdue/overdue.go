@@ -0,0 +1,67 @@
+package overdue
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/kirillidk/pvz-service/internal/config"
+	"github.com/kirillidk/pvz-service/internal/model"
+	"github.com/kirillidk/pvz-service/internal/repository"
+	"github.com/kirillidk/pvz-service/internal/service/periodic"
+)
+
+type OverdueServiceInterface interface {
+	ScheduleReturns(ctx context.Context) (int64, error)
+	GetOverdueProducts(ctx context.Context, pvzID string) ([]model.ReturnTask, error)
+}
+
+type OverdueService struct {
+	returnTaskRepository repository.ReturnTaskRepositoryInterface
+	storageConfig        config.StorageConfig
+}
+
+func NewOverdueService(returnTaskRepo repository.ReturnTaskRepositoryInterface, storageCfg config.StorageConfig) *OverdueService {
+	return &OverdueService{
+		returnTaskRepository: returnTaskRepo,
+		storageConfig:        storageCfg,
+	}
+}
+
+// ScheduleReturns creates return tasks for products kept longer than the
+// storage period of their type and returns how many were created.
+func (s *OverdueService) ScheduleReturns(ctx context.Context) (int64, error) {
+	created, err := s.returnTaskRepository.CreateOverdueReturnTasks(ctx, time.Now().UTC(), s.storageConfig.DefaultDays)
+	if err != nil {
+		return 0, fmt.Errorf("failed to schedule returns: %w", err)
+	}
+
+	return created, nil
+}
+
+// GetOverdueProducts returns the open return tasks of the PVZ. A task is
+// closed once its product leaves the PVZ, whether it is issued after all
+// or returned to the sender.
+func (s *OverdueService) GetOverdueProducts(ctx context.Context, pvzID string) ([]model.ReturnTask, error) {
+	tasks, err := s.returnTaskRepository.GetReturnTasksByPVZID(ctx, pvzID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get overdue products: %w", err)
+	}
+
+	return tasks, nil
+}
+
+// RunReturnScheduler calls ScheduleReturns at start and then every
+// ReturnCheckInterval until ctx is done. Failures are logged and retried on
+// the next tick.
+func (s *OverdueService) RunReturnScheduler(ctx context.Context) {
+	periodic.Run(ctx, s.storageConfig.ReturnCheckInterval, func(ctx context.Context) {
+		created, err := s.ScheduleReturns(ctx)
+		if err != nil {
+			log.Printf("return scheduler error: %v", err)
+		} else if created > 0 {
+			log.Printf("return scheduler created %d return tasks", created)
+		}
+	})
+}
@@ -0,0 +1,102 @@
+package overdue_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/kirillidk/pvz-service/internal/config"
+	"github.com/kirillidk/pvz-service/internal/model"
+	"github.com/kirillidk/pvz-service/internal/service/overdue"
+)
+
+type MockReturnTaskRepository struct {
+	CreateOverdueReturnTasksFunc func(ctx context.Context, now time.Time, defaultStorageDays int) (int64, error)
+	GetReturnTasksByPVZIDFunc    func(ctx context.Context, pvzID string) ([]model.ReturnTask, error)
+}
+
+func (m *MockReturnTaskRepository) CreateOverdueReturnTasks(ctx context.Context, now time.Time, defaultStorageDays int) (int64, error) {
+	return m.CreateOverdueReturnTasksFunc(ctx, now, defaultStorageDays)
+}
+
+func (m *MockReturnTaskRepository) GetReturnTasksByPVZID(ctx context.Context, pvzID string) ([]model.ReturnTask, error) {
+	return m.GetReturnTasksByPVZIDFunc(ctx, pvzID)
+}
+
+func TestOverdueService_ScheduleReturns(t *testing.T) {
+	tests := []struct {
+		name          string
+		created       int64
+		repoErr       error
+		expectedError bool
+	}{
+		{name: "Success", created: 2},
+		{name: "Repository Error", repoErr: errors.New("db error"), expectedError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotDays int
+			repo := &MockReturnTaskRepository{
+				CreateOverdueReturnTasksFunc: func(ctx context.Context, now time.Time, defaultStorageDays int) (int64, error) {
+					gotDays = defaultStorageDays
+					return tt.created, tt.repoErr
+				},
+			}
+
+			s := overdue.NewOverdueService(repo, config.StorageConfig{DefaultDays: 14, ReturnCheckInterval: time.Hour})
+			created, err := s.ScheduleReturns(context.Background())
+
+			if tt.expectedError {
+				if err == nil {
+					t.Error("ScheduleReturns() expected an error")
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("ScheduleReturns() unexpected error = %v", err)
+			}
+			if created != tt.created {
+				t.Errorf("ScheduleReturns() = %d, expected %d", created, tt.created)
+			}
+			if gotDays != 14 {
+				t.Errorf("Expected the default storage period of 14 days, got %d", gotDays)
+			}
+		})
+	}
+}
+
+func TestOverdueService_RunReturnScheduler(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	calls := 0
+	repo := &MockReturnTaskRepository{
+		CreateOverdueReturnTasksFunc: func(ctx context.Context, now time.Time, defaultStorageDays int) (int64, error) {
+			calls++
+			if calls == 2 {
+				cancel()
+			}
+			return 0, nil
+		},
+	}
+
+	s := overdue.NewOverdueService(repo, config.StorageConfig{DefaultDays: 14, ReturnCheckInterval: time.Millisecond})
+
+	done := make(chan struct{})
+	go func() {
+		s.RunReturnScheduler(ctx)
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("RunReturnScheduler() did not stop after the context was cancelled")
+	}
+
+	if calls != 2 {
+		t.Errorf("Expected 2 scheduler runs, got %d", calls)
+	}
+}
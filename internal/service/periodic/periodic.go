@@ -0,0 +1,25 @@
+// Package periodic runs background jobs on a fixed interval.
+package periodic
+
+import (
+	"context"
+	"time"
+)
+
+// Run calls fn at start and then every interval until ctx is done. A call
+// that takes longer than interval delays the next one instead of
+// overlapping it.
+func Run(ctx context.Context, interval time.Duration, fn func(ctx context.Context)) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		fn(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
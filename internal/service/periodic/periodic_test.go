@@ -0,0 +1,49 @@
+package periodic_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/kirillidk/pvz-service/internal/service/periodic"
+)
+
+func TestRun(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	calls := 0
+	done := make(chan struct{})
+	go func() {
+		periodic.Run(ctx, time.Millisecond, func(ctx context.Context) {
+			calls++
+			if calls == 3 {
+				cancel()
+			}
+		})
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("Run() did not stop after the context was cancelled")
+	}
+
+	if calls != 3 {
+		t.Errorf("Expected 3 runs, got %d", calls)
+	}
+}
+
+func TestRun_CancelledContext(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	calls := 0
+	periodic.Run(ctx, time.Hour, func(ctx context.Context) {
+		calls++
+	})
+
+	if calls != 1 {
+		t.Errorf("Expected 1 run before stopping, got %d", calls)
+	}
+}
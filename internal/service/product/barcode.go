@@ -0,0 +1,38 @@
+package product
+
+import (
+	"fmt"
+	"regexp"
+
+	"github.com/kirillidk/pvz-service/internal/model"
+)
+
+// validateBarcode accepts EAN-13 codes with a correct check digit and, when
+// pattern is set, any code matching it, e.g. carrier tracking numbers.
+func validateBarcode(barcode string, pattern *regexp.Regexp) error {
+	if isEAN13(barcode) || (pattern != nil && pattern.MatchString(barcode)) {
+		return nil
+	}
+	return fmt.Errorf("%w: %q", model.ErrInvalidBarcode, barcode)
+}
+
+func isEAN13(code string) bool {
+	if len(code) != 13 {
+		return false
+	}
+
+	sum := 0
+	for i := 0; i < 12; i++ {
+		digit := code[i] - '0'
+		if digit > 9 {
+			return false
+		}
+		if i%2 == 1 {
+			digit *= 3
+		}
+		sum += int(digit)
+	}
+
+	check := code[12] - '0'
+	return check <= 9 && int(check) == (10-sum%10)%10
+}
@@ -3,61 +3,324 @@ package product
 import (
 	"context"
 	"fmt"
+	"regexp"
+	"slices"
+	"time"
 
 	"github.com/kirillidk/pvz-service/internal/dto"
 	"github.com/kirillidk/pvz-service/internal/model"
 	"github.com/kirillidk/pvz-service/internal/repository"
+	"github.com/kirillidk/pvz-service/internal/service/audit"
+	"github.com/kirillidk/pvz-service/internal/service/expecteddelivery"
+	"github.com/kirillidk/pvz-service/internal/service/producttype"
+	"github.com/kirillidk/pvz-service/internal/service/storagecell"
 )
 
 type ProductServiceInterface interface {
-	CreateProduct(ctx context.Context, req dto.ProductCreateRequest) (*model.Product, error)
+	CreateProduct(ctx context.Context, req dto.ProductCreateRequest) (*dto.ProductCreateResponse, error)
+	CreateProducts(ctx context.Context, req dto.ProductBatchCreateRequest) ([]dto.ProductCreateResponse, error)
+	FindProductsByBarcode(ctx context.Context, barcode string) ([]model.ProductLocation, error)
 	DeleteLastProduct(ctx context.Context, pvzID string) error
 }
 
 type ProductService struct {
-	productRepository   repository.ProductRepositoryInterface
-	receptionRepository repository.ReceptionRepositoryInterface
+	productRepository       repository.ProductRepositoryInterface
+	receptionRepository     repository.ReceptionRepositoryInterface
+	pvzRepository           repository.PVZRepositoryInterface
+	transactor              repository.TransactorInterface
+	auditService            audit.AuditServiceInterface
+	productTypeService      producttype.ProductTypeServiceInterface
+	storageCellService      storagecell.StorageCellServiceInterface
+	expectedDeliveryService expecteddelivery.ExpectedDeliveryServiceInterface
+	barcodePattern          *regexp.Regexp
 }
 
 func NewProductService(
 	productRepo repository.ProductRepositoryInterface,
 	receptionRepo repository.ReceptionRepositoryInterface,
+	pvzRepo repository.PVZRepositoryInterface,
+	transactor repository.TransactorInterface,
+	auditService audit.AuditServiceInterface,
+	productTypeService producttype.ProductTypeServiceInterface,
+	storageCellService storagecell.StorageCellServiceInterface,
+	expectedDeliveryService expecteddelivery.ExpectedDeliveryServiceInterface,
+	barcodePattern *regexp.Regexp,
 ) *ProductService {
 	return &ProductService{
-		productRepository:   productRepo,
-		receptionRepository: receptionRepo,
+		productRepository:       productRepo,
+		receptionRepository:     receptionRepo,
+		pvzRepository:           pvzRepo,
+		transactor:              transactor,
+		auditService:            auditService,
+		productTypeService:      productTypeService,
+		storageCellService:      storageCellService,
+		expectedDeliveryService: expectedDeliveryService,
+		barcodePattern:          barcodePattern,
 	}
 }
 
-func (s *ProductService) CreateProduct(ctx context.Context, req dto.ProductCreateRequest) (*model.Product, error) {
-	reception, err := s.receptionRepository.GetLastOpenReception(ctx, req.PVZID)
-	if err != nil {
-		return nil, fmt.Errorf("failed to find open reception: %w", err)
+// CreateProduct adds the product to the open reception of the PVZ. A
+// product created without a cell comes with a suggested one. A product
+// with a barcode is matched against the expected delivery of the reception.
+func (s *ProductService) CreateProduct(ctx context.Context, req dto.ProductCreateRequest) (*dto.ProductCreateResponse, error) {
+	attrs := req.Attributes()
+	if err := s.validateProduct(ctx, attrs, make(map[string]*model.ProductType)); err != nil {
+		return nil, err
 	}
 
-	product, err := s.productRepository.CreateProduct(ctx, req.Type, reception.ID)
+	var response *dto.ProductCreateResponse
+
+	err := s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		pvz, err := s.pvzRepository.GetPVZForUpdate(ctx, req.PVZID)
+		if err != nil {
+			return fmt.Errorf("failed to get PVZ: %w", err)
+		}
+
+		if pvz.Status != model.PVZStatusActive {
+			return model.ErrPVZNotActive
+		}
+
+		reception, err := s.receptionRepository.GetLastOpenReceptionForUpdate(ctx, req.PVZID)
+		if err != nil {
+			return fmt.Errorf("failed to find open reception: %w", err)
+		}
+
+		warnings, err := s.checkBarcodes(ctx, req.PVZID, reception.ID, req.AllowedPVZIDs, []dto.ProductAttributes{attrs})
+		if err != nil {
+			return err
+		}
+
+		if err := s.checkCells(ctx, req.PVZID, []dto.ProductAttributes{attrs}); err != nil {
+			return err
+		}
+
+		product, err := s.productRepository.CreateProduct(ctx, reception.ID, attrs)
+		if err != nil {
+			return fmt.Errorf("failed to create product: %w", err)
+		}
+
+		deliveryWarnings, err := s.expectedDeliveryService.MatchProduct(ctx, *product)
+		if err != nil {
+			return err
+		}
+
+		response = &dto.ProductCreateResponse{Product: *product, Warnings: append(warnings[0], deliveryWarnings...)}
+
+		if product.CellID == "" {
+			response.SuggestedCell, err = s.storageCellService.SuggestStorageCell(ctx, req.PVZID, product.Type)
+			if err != nil {
+				return err
+			}
+		}
+
+		return s.auditService.Record(ctx, model.AuditActionCreate, model.AuditEntityProduct, product.ID, nil, product)
+	})
 	if err != nil {
-		return nil, fmt.Errorf("failed to create product: %w", err)
+		return nil, err
 	}
 
-	return product, nil
+	return response, nil
 }
 
-func (s *ProductService) DeleteLastProduct(ctx context.Context, pvzID string) error {
-	reception, err := s.receptionRepository.GetLastOpenReception(ctx, pvzID)
+// CreateProducts adds all products of the batch to the open reception of the
+// PVZ in one transaction. The reception is locked for the duration, so it
+// cannot be closed half way; if it was closed first, the whole batch fails.
+func (s *ProductService) CreateProducts(ctx context.Context, req dto.ProductBatchCreateRequest) ([]dto.ProductCreateResponse, error) {
+	productTypes := make(map[string]*model.ProductType)
+	barcodes := make(map[string]int)
+	for i, item := range req.Products {
+		if err := s.validateProduct(ctx, item, productTypes); err != nil {
+			return nil, fmt.Errorf("product %d: %w", i, err)
+		}
+
+		if item.Barcode == "" {
+			continue
+		}
+		if first, ok := barcodes[item.Barcode]; ok {
+			return nil, fmt.Errorf("product %d: %w: same barcode as product %d", i, model.ErrDuplicateBarcode, first)
+		}
+		barcodes[item.Barcode] = i
+	}
+
+	var responses []dto.ProductCreateResponse
+
+	err := s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		pvz, err := s.pvzRepository.GetPVZForUpdate(ctx, req.PVZID)
+		if err != nil {
+			return fmt.Errorf("failed to get PVZ: %w", err)
+		}
+
+		if pvz.Status != model.PVZStatusActive {
+			return model.ErrPVZNotActive
+		}
+
+		reception, err := s.receptionRepository.GetLastOpenReceptionForUpdate(ctx, req.PVZID)
+		if err != nil {
+			return fmt.Errorf("failed to find open reception: %w", err)
+		}
+
+		warnings, err := s.checkBarcodes(ctx, req.PVZID, reception.ID, req.AllowedPVZIDs, req.Products)
+		if err != nil {
+			return err
+		}
+
+		if err := s.checkCells(ctx, req.PVZID, req.Products); err != nil {
+			return err
+		}
+
+		products, err := s.productRepository.CreateProducts(ctx, reception.ID, req.Products)
+		if err != nil {
+			return fmt.Errorf("failed to create products: %w", err)
+		}
+
+		responses = make([]dto.ProductCreateResponse, 0, len(products))
+		for i, product := range products {
+			deliveryWarnings, err := s.expectedDeliveryService.MatchProduct(ctx, product)
+			if err != nil {
+				return err
+			}
+
+			responses = append(responses, dto.ProductCreateResponse{Product: product, Warnings: append(warnings[i], deliveryWarnings...)})
+		}
+
+		return s.auditService.Record(ctx, model.AuditActionAddProducts, model.AuditEntityReception, reception.ID, nil, products)
+	})
 	if err != nil {
-		return fmt.Errorf("failed to find open reception: %w", err)
+		return nil, err
 	}
 
-	lastProduct, err := s.productRepository.GetLastProductInReception(ctx, reception.ID)
+	return responses, nil
+}
+
+// FindProductsByBarcode locates the products with the barcode across all PVZs.
+func (s *ProductService) FindProductsByBarcode(ctx context.Context, barcode string) ([]model.ProductLocation, error) {
+	locations, err := s.productRepository.GetProductsByBarcodes(ctx, []string{barcode})
 	if err != nil {
-		return fmt.Errorf("failed to get last product: %w", err)
+		return nil, fmt.Errorf("failed to find products: %w", err)
+	}
+
+	return locations, nil
+}
+
+// validateProduct checks the product against its type and the barcode
+// format. productTypes caches the types already looked up.
+func (s *ProductService) validateProduct(ctx context.Context, attrs dto.ProductAttributes, productTypes map[string]*model.ProductType) error {
+	productType, ok := productTypes[attrs.Type]
+	if !ok {
+		var err error
+		productType, err = s.productTypeService.GetActiveProductType(ctx, attrs.Type)
+		if err != nil {
+			return err
+		}
+		productTypes[attrs.Type] = productType
 	}
 
-	err = s.productRepository.DeleteProduct(ctx, lastProduct.ID)
+	if productType.RequiresSerialNumber && attrs.SerialNumber == "" {
+		return fmt.Errorf("%w: %s", model.ErrSerialNumberRequired, attrs.Type)
+	}
+
+	if attrs.Barcode != "" {
+		return validateBarcode(attrs.Barcode, s.barcodePattern)
+	}
+
+	return nil
+}
+
+// checkBarcodes rejects barcodes already scanned in the reception and
+// returns, for each item, warnings about barcodes received at other PVZs.
+// PVZs outside allowedPVZIDs, when it is not empty, are not named.
+func (s *ProductService) checkBarcodes(
+	ctx context.Context,
+	pvzID, receptionID string,
+	allowedPVZIDs []string,
+	items []dto.ProductAttributes,
+) ([][]string, error) {
+	warnings := make([][]string, len(items))
+
+	var barcodes []string
+	for _, item := range items {
+		if item.Barcode != "" {
+			barcodes = append(barcodes, item.Barcode)
+		}
+	}
+
+	if len(barcodes) == 0 {
+		return warnings, nil
+	}
+
+	locations, err := s.productRepository.GetProductsByBarcodes(ctx, barcodes)
 	if err != nil {
-		return fmt.Errorf("failed to delete product: %w", err)
+		return nil, fmt.Errorf("failed to check barcodes: %w", err)
+	}
+
+	otherPVZs := make(map[string][]model.ProductLocation)
+	for _, location := range locations {
+		if location.ReceptionID == receptionID {
+			return nil, fmt.Errorf("%w: %s", model.ErrDuplicateBarcode, location.Barcode)
+		}
+		if location.PVZID != pvzID {
+			otherPVZs[location.Barcode] = append(otherPVZs[location.Barcode], location)
+		}
+	}
+
+	for i, item := range items {
+		for _, location := range otherPVZs[item.Barcode] {
+			if len(allowedPVZIDs) > 0 && !slices.Contains(allowedPVZIDs, location.PVZID) {
+				warnings[i] = append(warnings[i], fmt.Sprintf("barcode %s was already received at another PVZ", item.Barcode))
+				continue
+			}
+
+			warnings[i] = append(warnings[i], fmt.Sprintf(
+				"barcode %s was already received at PVZ %s (%s) on %s",
+				item.Barcode, location.PVZID, location.City, location.DateTime.Format(time.DateOnly),
+			))
+		}
+	}
+
+	return warnings, nil
+}
+
+// checkCells checks that the items placed into cells fit there. The items
+// of each cell are checked together, in the order of first appearance.
+func (s *ProductService) checkCells(ctx context.Context, pvzID string, items []dto.ProductAttributes) error {
+	var cellIDs []string
+	productTypes := make(map[string][]string)
+	for _, item := range items {
+		if item.CellID == "" {
+			continue
+		}
+		if _, ok := productTypes[item.CellID]; !ok {
+			cellIDs = append(cellIDs, item.CellID)
+		}
+		productTypes[item.CellID] = append(productTypes[item.CellID], item.Type)
+	}
+
+	for _, cellID := range cellIDs {
+		if err := s.storageCellService.CheckCellPlacement(ctx, pvzID, cellID, productTypes[cellID]...); err != nil {
+			return err
+		}
 	}
 
 	return nil
 }
+
+func (s *ProductService) DeleteLastProduct(ctx context.Context, pvzID string) error {
+	return s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		reception, err := s.receptionRepository.GetLastOpenReceptionForUpdate(ctx, pvzID)
+		if err != nil {
+			return fmt.Errorf("failed to find open reception: %w", err)
+		}
+
+		lastProduct, err := s.productRepository.GetLastProductInReception(ctx, reception.ID)
+		if err != nil {
+			return fmt.Errorf("failed to get last product: %w", err)
+		}
+
+		err = s.productRepository.DeleteProduct(ctx, lastProduct.ID)
+		if err != nil {
+			return fmt.Errorf("failed to delete product: %w", err)
+		}
+
+		return s.auditService.Record(ctx, model.AuditActionDelete, model.AuditEntityProduct, lastProduct.ID, lastProduct, nil)
+	})
+}
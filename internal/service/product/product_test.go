@@ -3,7 +3,9 @@ package product_test
 import (
 	"context"
 	"errors"
+	"fmt"
 	"reflect"
+	"regexp"
 	"testing"
 	"time"
 
@@ -18,14 +20,17 @@ type MockRepositories struct {
 }
 
 type MockProductRepository struct {
-	CreateProductFunc             func(ctx context.Context, productType string, receptionID string) (*model.Product, error)
+	CreateProductFunc             func(ctx context.Context, receptionID string, attrs dto.ProductAttributes) (*model.Product, error)
 	GetLastProductInReceptionFunc func(ctx context.Context, receptionID string) (*model.Product, error)
 	DeleteProductFunc             func(ctx context.Context, productID string) error
 	GetProductsByReceptionIDFunc  func(ctx context.Context, receptionID string) ([]model.Product, error)
+
+	CreateProductsFunc        func(ctx context.Context, receptionID string, items []dto.ProductAttributes) ([]model.Product, error)
+	GetProductsByBarcodesFunc func(ctx context.Context, barcodes []string) ([]model.ProductLocation, error)
 }
 
-func (m *MockProductRepository) CreateProduct(ctx context.Context, productType string, receptionID string) (*model.Product, error) {
-	return m.CreateProductFunc(ctx, productType, receptionID)
+func (m *MockProductRepository) CreateProduct(ctx context.Context, receptionID string, attrs dto.ProductAttributes) (*model.Product, error) {
+	return m.CreateProductFunc(ctx, receptionID, attrs)
 }
 
 func (m *MockProductRepository) GetLastProductInReception(ctx context.Context, receptionID string) (*model.Product, error) {
@@ -40,12 +45,52 @@ func (m *MockProductRepository) GetProductsByReceptionID(ctx context.Context, re
 	return m.GetProductsByReceptionIDFunc(ctx, receptionID)
 }
 
+func (m *MockProductRepository) GetProductsByReceptionIDInInsertionOrder(ctx context.Context, receptionID string) ([]model.Product, error) {
+	return nil, nil
+}
+
+func (m *MockProductRepository) CreateProducts(ctx context.Context, receptionID string, items []dto.ProductAttributes) ([]model.Product, error) {
+	return m.CreateProductsFunc(ctx, receptionID, items)
+}
+
+func (m *MockProductRepository) GetProductsByBarcodes(ctx context.Context, barcodes []string) ([]model.ProductLocation, error) {
+	return m.GetProductsByBarcodesFunc(ctx, barcodes)
+}
+
+func (m *MockProductRepository) GetProductLocation(ctx context.Context, productID string) (*model.ProductLocation, error) {
+	return nil, nil
+}
+
+func (m *MockProductRepository) GetProductLocationForUpdate(ctx context.Context, productID string) (*model.ProductLocation, error) {
+	return nil, nil
+}
+
+func (m *MockProductRepository) UpdateProductStatus(ctx context.Context, productID string, status model.ProductStatus) (*model.Product, error) {
+	return nil, nil
+}
+
+func (m *MockProductRepository) UpdateProductCell(ctx context.Context, productID, cellID string) (*model.Product, error) {
+	return nil, nil
+}
+
+func (m *MockProductRepository) UpdateProductCondition(
+	ctx context.Context,
+	productID string,
+	condition model.ProductCondition,
+	note string,
+) (*model.Product, error) {
+	return nil, nil
+}
+
 type MockReceptionRepository struct {
 	CreateReceptionFunc      func(ctx context.Context, req dto.ReceptionCreateRequest) (*model.Reception, error)
 	HasOpenReceptionFunc     func(ctx context.Context, pvzID string) (bool, error)
 	GetLastOpenReceptionFunc func(ctx context.Context, pvzID string) (*model.Reception, error)
+	GetReceptionByIDFunc     func(ctx context.Context, receptionID string) (*model.Reception, error)
 	CloseReceptionFunc       func(ctx context.Context, receptionID string) (*model.Reception, error)
-	GetReceptionsByPVZIDFunc func(ctx context.Context, pvzID string, startDate, endDate *time.Time) ([]model.Reception, error)
+	GetReceptionsByPVZIDFunc func(ctx context.Context, pvzID string, filter dto.ReceptionFilter) ([]model.Reception, error)
+
+	GetLastOpenReceptionForUpdateFunc func(ctx context.Context, pvzID string) (*model.Reception, error)
 }
 
 func (m *MockReceptionRepository) CreateReception(ctx context.Context, req dto.ReceptionCreateRequest) (*model.Reception, error) {
@@ -60,12 +105,265 @@ func (m *MockReceptionRepository) GetLastOpenReception(ctx context.Context, pvzI
 	return m.GetLastOpenReceptionFunc(ctx, pvzID)
 }
 
+func (m *MockReceptionRepository) GetLastOpenReceptionForUpdate(ctx context.Context, pvzID string) (*model.Reception, error) {
+	return m.GetLastOpenReceptionForUpdateFunc(ctx, pvzID)
+}
+
+func (m *MockReceptionRepository) GetReceptionByID(ctx context.Context, receptionID string) (*model.Reception, error) {
+	return m.GetReceptionByIDFunc(ctx, receptionID)
+}
+
 func (m *MockReceptionRepository) CloseReception(ctx context.Context, receptionID string) (*model.Reception, error) {
 	return m.CloseReceptionFunc(ctx, receptionID)
 }
 
-func (m *MockReceptionRepository) GetReceptionsByPVZID(ctx context.Context, pvzID string, startDate, endDate *time.Time) ([]model.Reception, error) {
-	return m.GetReceptionsByPVZIDFunc(ctx, pvzID, startDate, endDate)
+func (m *MockReceptionRepository) GetReceptionForUpdate(ctx context.Context, receptionID string) (*model.Reception, error) {
+	return nil, nil
+}
+
+func (m *MockReceptionRepository) HasNewerReception(ctx context.Context, reception *model.Reception) (bool, error) {
+	return false, nil
+}
+
+func (m *MockReceptionRepository) HasProcessedProducts(ctx context.Context, receptionID string) (bool, error) {
+	return false, nil
+}
+
+func (m *MockReceptionRepository) ReopenReception(ctx context.Context, receptionID string) (*model.Reception, error) {
+	return nil, nil
+}
+
+func (m *MockReceptionRepository) GetReceptionsByPVZID(ctx context.Context, pvzID string, filter dto.ReceptionFilter) ([]model.Reception, error) {
+	return m.GetReceptionsByPVZIDFunc(ctx, pvzID, filter)
+}
+
+func (m *MockReceptionRepository) SetExpectedCount(ctx context.Context, receptionID string, expectedCount int) (*model.Reception, error) {
+	return nil, nil
+}
+
+func (m *MockReceptionRepository) GetReceptionDiscrepancy(ctx context.Context, receptionID string) (*model.ReceptionDiscrepancy, error) {
+	return nil, nil
+}
+
+func (m *MockReceptionRepository) GetReceptionDiscrepancies(
+	ctx context.Context,
+	filter dto.DiscrepancyFilterQuery,
+) ([]model.ReceptionDiscrepancy, error) {
+	return nil, nil
+}
+
+type MockPVZRepository struct {
+	CreatePVZFunc        func(ctx context.Context, req dto.PVZCreateRequest) (*model.PVZ, error)
+	GetPVZListFunc       func(ctx context.Context, filter dto.PVZFilterQuery) ([]model.PVZ, error)
+	GetPVZByIDFunc       func(ctx context.Context, pvzID string) (*model.PVZ, error)
+	UpdatePVZFunc        func(ctx context.Context, pvzID string, req dto.PVZUpdateRequest) (*model.PVZ, error)
+	GetNearbyPVZListFunc func(ctx context.Context, query dto.PVZNearbyQuery) ([]model.NearbyPVZ, error)
+	GetPVZForUpdateFunc  func(ctx context.Context, pvzID string) (*model.PVZ, error)
+	UpdatePVZStatusFunc  func(ctx context.Context, pvzID string, status model.PVZStatus) (*model.PVZ, error)
+	StreamPVZListFunc    func(ctx context.Context, filter dto.PVZFilterQuery, fn func(model.PVZ, *model.Reception, *model.Product) error) error
+}
+
+// newMockPVZRepository returns a repository that knows a single PVZ in the
+// given status.
+func newMockPVZRepository(status model.PVZStatus) *MockPVZRepository {
+	getPVZ := func(ctx context.Context, pvzID string) (*model.PVZ, error) {
+		return &model.PVZ{ID: pvzID, City: "Москва", Status: status}, nil
+	}
+
+	return &MockPVZRepository{
+		GetPVZByIDFunc:      getPVZ,
+		GetPVZForUpdateFunc: getPVZ,
+	}
+}
+
+func (m *MockPVZRepository) CreatePVZ(ctx context.Context, req dto.PVZCreateRequest) (*model.PVZ, error) {
+	return m.CreatePVZFunc(ctx, req)
+}
+
+func (m *MockPVZRepository) GetPVZList(ctx context.Context, filter dto.PVZFilterQuery) ([]model.PVZ, error) {
+	return m.GetPVZListFunc(ctx, filter)
+}
+
+func (m *MockPVZRepository) GetPVZByID(ctx context.Context, pvzID string) (*model.PVZ, error) {
+	return m.GetPVZByIDFunc(ctx, pvzID)
+}
+
+func (m *MockPVZRepository) UpdatePVZ(ctx context.Context, pvzID string, req dto.PVZUpdateRequest) (*model.PVZ, error) {
+	return m.UpdatePVZFunc(ctx, pvzID, req)
+}
+
+func (m *MockPVZRepository) GetNearbyPVZList(ctx context.Context, query dto.PVZNearbyQuery) ([]model.NearbyPVZ, error) {
+	return m.GetNearbyPVZListFunc(ctx, query)
+}
+
+func (m *MockPVZRepository) GetPVZForUpdate(ctx context.Context, pvzID string) (*model.PVZ, error) {
+	return m.GetPVZForUpdateFunc(ctx, pvzID)
+}
+
+func (m *MockPVZRepository) UpdatePVZStatus(ctx context.Context, pvzID string, status model.PVZStatus) (*model.PVZ, error) {
+	return m.UpdatePVZStatusFunc(ctx, pvzID, status)
+}
+
+func (m *MockPVZRepository) StreamPVZList(ctx context.Context, filter dto.PVZFilterQuery, fn func(model.PVZ, *model.Reception, *model.Product) error) error {
+	return m.StreamPVZListFunc(ctx, filter, fn)
+}
+
+type MockTransactor struct{}
+
+func (m *MockTransactor) WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	return fn(ctx)
+}
+
+type MockAuditService struct {
+	RecordFunc func(ctx context.Context, action model.AuditAction, entityType model.AuditEntityType, entityID string, before, after any) error
+}
+
+func (m *MockAuditService) Record(
+	ctx context.Context,
+	action model.AuditAction,
+	entityType model.AuditEntityType,
+	entityID string,
+	before, after any,
+) error {
+	if m.RecordFunc == nil {
+		return nil
+	}
+	return m.RecordFunc(ctx, action, entityType, entityID, before, after)
+}
+
+func (m *MockAuditService) GetAuditLog(ctx context.Context, filter dto.AuditFilterQuery) ([]model.AuditEntry, error) {
+	return nil, nil
+}
+
+type MockProductTypeService struct {
+	ProductTypes map[string]model.ProductType
+}
+
+func newMockProductTypeService() *MockProductTypeService {
+	return &MockProductTypeService{
+		ProductTypes: map[string]model.ProductType{
+			"electronics": {Code: "electronics", Name: "Electronics", Active: true},
+			"phones":      {Code: "phones", Name: "Phones", Active: true, RequiresSerialNumber: true},
+			"archived":    {Code: "archived", Name: "Archived", Active: false},
+		},
+	}
+}
+
+func (m *MockProductTypeService) CreateProductType(ctx context.Context, req dto.ProductTypeCreateRequest) (*model.ProductType, error) {
+	return nil, nil
+}
+
+func (m *MockProductTypeService) GetProductTypeList(ctx context.Context) ([]model.ProductType, error) {
+	return nil, nil
+}
+
+func (m *MockProductTypeService) GetProductType(ctx context.Context, code string) (*model.ProductType, error) {
+	return nil, nil
+}
+
+func (m *MockProductTypeService) UpdateProductType(ctx context.Context, code string, req dto.ProductTypeUpdateRequest) (*model.ProductType, error) {
+	return nil, nil
+}
+
+func (m *MockProductTypeService) DeleteProductType(ctx context.Context, code string) error {
+	return nil
+}
+
+func (m *MockProductTypeService) GetActiveProductType(ctx context.Context, code string) (*model.ProductType, error) {
+	productType, ok := m.ProductTypes[code]
+	if !ok || !productType.Active {
+		return nil, errors.New("product type is not available")
+	}
+	return &productType, nil
+}
+
+type MockStorageCellService struct {
+	CheckCellPlacementFunc func(ctx context.Context, pvzID, cellID string, productTypes ...string) error
+	SuggestStorageCellFunc func(ctx context.Context, pvzID, productType string) (*model.StorageCell, error)
+}
+
+func (m *MockStorageCellService) CreateStorageCell(ctx context.Context, pvzID string, req dto.StorageCellCreateRequest) (*model.StorageCell, error) {
+	return nil, nil
+}
+
+func (m *MockStorageCellService) GetStorageCells(ctx context.Context, pvzID string) ([]model.StorageCell, error) {
+	return nil, nil
+}
+
+func (m *MockStorageCellService) UpdateStorageCell(
+	ctx context.Context,
+	pvzID, cellID string,
+	req dto.StorageCellUpdateRequest,
+) (*model.StorageCell, error) {
+	return nil, nil
+}
+
+func (m *MockStorageCellService) DeleteStorageCell(ctx context.Context, pvzID, cellID string) error {
+	return nil
+}
+
+func (m *MockStorageCellService) GetStorageCellOccupancy(ctx context.Context, pvzID string) ([]model.StorageCellOccupancy, error) {
+	return nil, nil
+}
+
+func (m *MockStorageCellService) AssignProductToCell(ctx context.Context, pvzID, productID, cellID string) (*model.Product, error) {
+	return nil, nil
+}
+
+func (m *MockStorageCellService) CheckCellPlacement(ctx context.Context, pvzID, cellID string, productTypes ...string) error {
+	if m.CheckCellPlacementFunc == nil {
+		return nil
+	}
+	return m.CheckCellPlacementFunc(ctx, pvzID, cellID, productTypes...)
+}
+
+func (m *MockStorageCellService) SuggestStorageCell(ctx context.Context, pvzID, productType string) (*model.StorageCell, error) {
+	if m.SuggestStorageCellFunc == nil {
+		return nil, nil
+	}
+	return m.SuggestStorageCellFunc(ctx, pvzID, productType)
+}
+
+type MockExpectedDeliveryService struct {
+	MatchProductFunc func(ctx context.Context, product model.Product) ([]string, error)
+}
+
+func (m *MockExpectedDeliveryService) CreateExpectedDelivery(
+	ctx context.Context,
+	pvzID string,
+	req dto.ExpectedDeliveryCreateRequest,
+) (*model.ExpectedDelivery, error) {
+	return nil, nil
+}
+
+func (m *MockExpectedDeliveryService) GetExpectedDeliveries(ctx context.Context, pvzID string) ([]model.ExpectedDelivery, error) {
+	return nil, nil
+}
+
+func (m *MockExpectedDeliveryService) GetExpectedDelivery(ctx context.Context, pvzID, deliveryID string) (*model.ExpectedDelivery, error) {
+	return nil, nil
+}
+
+func (m *MockExpectedDeliveryService) LinkReception(ctx context.Context, reception *model.Reception) (*model.ExpectedDelivery, error) {
+	return nil, nil
+}
+
+func (m *MockExpectedDeliveryService) MatchProduct(ctx context.Context, product model.Product) ([]string, error) {
+	if m.MatchProductFunc == nil {
+		return nil, nil
+	}
+	return m.MatchProductFunc(ctx, product)
+}
+
+func (m *MockExpectedDeliveryService) ReconcileReception(
+	ctx context.Context,
+	reception *model.Reception,
+) (*model.DeliveryReconciliation, error) {
+	return nil, nil
+}
+
+func (m *MockExpectedDeliveryService) ReopenReception(ctx context.Context, reception *model.Reception) error {
+	return nil
 }
 
 func TestProductService_CreateProduct(t *testing.T) {
@@ -75,24 +373,24 @@ func TestProductService_CreateProduct(t *testing.T) {
 		name          string
 		mocks         MockRepositories
 		input         dto.ProductCreateRequest
-		expected      *model.Product
+		expected      *dto.ProductCreateResponse
 		expectedError bool
 	}{
 		{
 			name: "Success",
 			mocks: MockRepositories{
 				MockProductRepository: &MockProductRepository{
-					CreateProductFunc: func(ctx context.Context, productType string, receptionID string) (*model.Product, error) {
+					CreateProductFunc: func(ctx context.Context, receptionID string, attrs dto.ProductAttributes) (*model.Product, error) {
 						return &model.Product{
 							ID:          "123e4567-e89b-12d3-a456-426614174001",
 							DateTime:    now,
-							Type:        productType,
+							Type:        attrs.Type,
 							ReceptionID: receptionID,
 						}, nil
 					},
 				},
 				MockReceptionRepository: &MockReceptionRepository{
-					GetLastOpenReceptionFunc: func(ctx context.Context, pvzID string) (*model.Reception, error) {
+					GetLastOpenReceptionForUpdateFunc: func(ctx context.Context, pvzID string) (*model.Reception, error) {
 						return &model.Reception{
 							ID:       "123e4567-e89b-12d3-a456-426614174002",
 							DateTime: now,
@@ -106,11 +404,13 @@ func TestProductService_CreateProduct(t *testing.T) {
 				Type:  "electronics",
 				PVZID: "123e4567-e89b-12d3-a456-426614174003",
 			},
-			expected: &model.Product{
-				ID:          "123e4567-e89b-12d3-a456-426614174001",
-				DateTime:    now,
-				Type:        "electronics",
-				ReceptionID: "123e4567-e89b-12d3-a456-426614174002",
+			expected: &dto.ProductCreateResponse{
+				Product: model.Product{
+					ID:          "123e4567-e89b-12d3-a456-426614174001",
+					DateTime:    now,
+					Type:        "electronics",
+					ReceptionID: "123e4567-e89b-12d3-a456-426614174002",
+				},
 			},
 			expectedError: false,
 		},
@@ -119,7 +419,7 @@ func TestProductService_CreateProduct(t *testing.T) {
 			mocks: MockRepositories{
 				MockProductRepository: &MockProductRepository{},
 				MockReceptionRepository: &MockReceptionRepository{
-					GetLastOpenReceptionFunc: func(ctx context.Context, pvzID string) (*model.Reception, error) {
+					GetLastOpenReceptionForUpdateFunc: func(ctx context.Context, pvzID string) (*model.Reception, error) {
 						return nil, errors.New("no open reception found for this PVZ")
 					},
 				},
@@ -135,12 +435,12 @@ func TestProductService_CreateProduct(t *testing.T) {
 			name: "Product Creation Error",
 			mocks: MockRepositories{
 				MockProductRepository: &MockProductRepository{
-					CreateProductFunc: func(ctx context.Context, productType string, receptionID string) (*model.Product, error) {
+					CreateProductFunc: func(ctx context.Context, receptionID string, attrs dto.ProductAttributes) (*model.Product, error) {
 						return nil, errors.New("failed to create product")
 					},
 				},
 				MockReceptionRepository: &MockReceptionRepository{
-					GetLastOpenReceptionFunc: func(ctx context.Context, pvzID string) (*model.Reception, error) {
+					GetLastOpenReceptionForUpdateFunc: func(ctx context.Context, pvzID string) (*model.Reception, error) {
 						return &model.Reception{
 							ID:       "123e4567-e89b-12d3-a456-426614174002",
 							DateTime: now,
@@ -157,11 +457,83 @@ func TestProductService_CreateProduct(t *testing.T) {
 			expected:      nil,
 			expectedError: true,
 		},
+		{
+			name: "Inactive Product Type",
+			mocks: MockRepositories{
+				MockProductRepository:   &MockProductRepository{},
+				MockReceptionRepository: &MockReceptionRepository{},
+			},
+			input: dto.ProductCreateRequest{
+				Type:  "archived",
+				PVZID: "123e4567-e89b-12d3-a456-426614174003",
+			},
+			expected:      nil,
+			expectedError: true,
+		},
+		{
+			name: "Serial Number Required",
+			mocks: MockRepositories{
+				MockProductRepository:   &MockProductRepository{},
+				MockReceptionRepository: &MockReceptionRepository{},
+			},
+			input: dto.ProductCreateRequest{
+				Type:  "phones",
+				PVZID: "123e4567-e89b-12d3-a456-426614174003",
+			},
+			expected:      nil,
+			expectedError: true,
+		},
+		{
+			name: "Serial Number Provided",
+			mocks: MockRepositories{
+				MockProductRepository: &MockProductRepository{
+					CreateProductFunc: func(ctx context.Context, receptionID string, attrs dto.ProductAttributes) (*model.Product, error) {
+						return &model.Product{
+							ID:           "123e4567-e89b-12d3-a456-426614174001",
+							DateTime:     now,
+							Type:         attrs.Type,
+							ReceptionID:  receptionID,
+							SerialNumber: attrs.SerialNumber,
+						}, nil
+					},
+				},
+				MockReceptionRepository: &MockReceptionRepository{
+					GetLastOpenReceptionForUpdateFunc: func(ctx context.Context, pvzID string) (*model.Reception, error) {
+						return &model.Reception{ID: "123e4567-e89b-12d3-a456-426614174002", PVZID: pvzID, Status: "in_progress"}, nil
+					},
+				},
+			},
+			input: dto.ProductCreateRequest{
+				Type:         "phones",
+				PVZID:        "123e4567-e89b-12d3-a456-426614174003",
+				SerialNumber: "SN-001",
+			},
+			expected: &dto.ProductCreateResponse{
+				Product: model.Product{
+					ID:           "123e4567-e89b-12d3-a456-426614174001",
+					DateTime:     now,
+					Type:         "phones",
+					ReceptionID:  "123e4567-e89b-12d3-a456-426614174002",
+					SerialNumber: "SN-001",
+				},
+			},
+			expectedError: false,
+		},
 	}
 
 	for ttNum, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			s := product.NewProductService(tt.mocks.MockProductRepository, tt.mocks.MockReceptionRepository)
+			s := product.NewProductService(
+				tt.mocks.MockProductRepository,
+				tt.mocks.MockReceptionRepository,
+				newMockPVZRepository(model.PVZStatusActive),
+				&MockTransactor{},
+				&MockAuditService{},
+				newMockProductTypeService(),
+				&MockStorageCellService{},
+				&MockExpectedDeliveryService{},
+				nil,
+			)
 			got, err := s.CreateProduct(context.Background(), tt.input)
 
 			if (err != nil) != tt.expectedError {
@@ -175,6 +547,346 @@ func TestProductService_CreateProduct(t *testing.T) {
 	}
 }
 
+func TestProductService_CreateProduct_InactivePVZ(t *testing.T) {
+	for _, status := range []model.PVZStatus{model.PVZStatusSuspended, model.PVZStatusArchived} {
+		t.Run(string(status), func(t *testing.T) {
+			s := product.NewProductService(
+				&MockProductRepository{},
+				&MockReceptionRepository{},
+				newMockPVZRepository(status),
+				&MockTransactor{},
+				&MockAuditService{},
+				newMockProductTypeService(),
+				&MockStorageCellService{},
+				&MockExpectedDeliveryService{},
+				nil,
+			)
+			_, err := s.CreateProduct(context.Background(), dto.ProductCreateRequest{
+				Type:  "electronics",
+				PVZID: "123e4567-e89b-12d3-a456-426614174003",
+			})
+
+			if !errors.Is(err, model.ErrPVZNotActive) {
+				t.Errorf("ProductService.CreateProduct() error = %v, expected %v", err, model.ErrPVZNotActive)
+			}
+		})
+	}
+}
+
+func TestProductService_CreateProduct_Barcode(t *testing.T) {
+	now := time.Date(2025, 4, 10, 12, 0, 0, 0, time.UTC)
+	pvzID := "123e4567-e89b-12d3-a456-426614174003"
+	receptionID := "123e4567-e89b-12d3-a456-426614174002"
+
+	tests := []struct {
+		name             string
+		barcode          string
+		pattern          *regexp.Regexp
+		locations        []model.ProductLocation
+		allowedPVZIDs    []string
+		expectedWarnings []string
+		expectedError    error
+	}{
+		{
+			name:    "Valid EAN-13",
+			barcode: "4006381333931",
+		},
+		{
+			name:          "Invalid Check Digit",
+			barcode:       "4006381333932",
+			expectedError: model.ErrInvalidBarcode,
+		},
+		{
+			name:          "Not EAN-13 Without Pattern",
+			barcode:       "TRK123456",
+			expectedError: model.ErrInvalidBarcode,
+		},
+		{
+			name:    "Matches Pattern",
+			barcode: "TRK123456",
+			pattern: regexp.MustCompile(`^TRK\d{6}$`),
+		},
+		{
+			name:    "Already Scanned In Reception",
+			barcode: "4006381333931",
+			locations: []model.ProductLocation{
+				{Product: model.Product{ReceptionID: receptionID, Barcode: "4006381333931"}, PVZID: pvzID},
+			},
+			expectedError: model.ErrDuplicateBarcode,
+		},
+		{
+			name:    "Received At Another PVZ",
+			barcode: "4006381333931",
+			locations: []model.ProductLocation{
+				{
+					Product: model.Product{ReceptionID: "223e4567-e89b-12d3-a456-426614174002", Barcode: "4006381333931", DateTime: now},
+					PVZID:   "223e4567-e89b-12d3-a456-426614174003",
+					City:    "Казань",
+				},
+			},
+			expectedWarnings: []string{"barcode 4006381333931 was already received at PVZ 223e4567-e89b-12d3-a456-426614174003 (Казань) on 2025-04-10"},
+		},
+		{
+			name:    "Received At Inaccessible PVZ",
+			barcode: "4006381333931",
+			locations: []model.ProductLocation{
+				{
+					Product: model.Product{ReceptionID: "223e4567-e89b-12d3-a456-426614174002", Barcode: "4006381333931", DateTime: now},
+					PVZID:   "223e4567-e89b-12d3-a456-426614174003",
+					City:    "Казань",
+				},
+			},
+			allowedPVZIDs:    []string{pvzID},
+			expectedWarnings: []string{"barcode 4006381333931 was already received at another PVZ"},
+		},
+		{
+			name:    "Earlier Reception At Same PVZ",
+			barcode: "4006381333931",
+			locations: []model.ProductLocation{
+				{Product: model.Product{ReceptionID: "223e4567-e89b-12d3-a456-426614174002", Barcode: "4006381333931"}, PVZID: pvzID},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			productRepo := &MockProductRepository{
+				CreateProductFunc: func(ctx context.Context, receptionID string, attrs dto.ProductAttributes) (*model.Product, error) {
+					return &model.Product{ID: "123e4567-e89b-12d3-a456-426614174001", ReceptionID: receptionID, Type: attrs.Type, Barcode: attrs.Barcode}, nil
+				},
+				GetProductsByBarcodesFunc: func(ctx context.Context, barcodes []string) ([]model.ProductLocation, error) {
+					if !reflect.DeepEqual(barcodes, []string{tt.barcode}) {
+						t.Errorf("Expected lookup of %q, got %v", tt.barcode, barcodes)
+					}
+					return tt.locations, nil
+				},
+			}
+			receptionRepo := &MockReceptionRepository{
+				GetLastOpenReceptionForUpdateFunc: func(ctx context.Context, pvzID string) (*model.Reception, error) {
+					return &model.Reception{ID: receptionID, PVZID: pvzID, Status: "in_progress"}, nil
+				},
+			}
+
+			s := product.NewProductService(
+				productRepo,
+				receptionRepo,
+				newMockPVZRepository(model.PVZStatusActive),
+				&MockTransactor{},
+				&MockAuditService{},
+				newMockProductTypeService(),
+				&MockStorageCellService{},
+				&MockExpectedDeliveryService{},
+				tt.pattern,
+			)
+			got, err := s.CreateProduct(context.Background(), dto.ProductCreateRequest{
+				Type:          "electronics",
+				PVZID:         pvzID,
+				Barcode:       tt.barcode,
+				AllowedPVZIDs: tt.allowedPVZIDs,
+			})
+
+			if tt.expectedError != nil {
+				if !errors.Is(err, tt.expectedError) {
+					t.Errorf("ProductService.CreateProduct() error = %v, expected %v", err, tt.expectedError)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ProductService.CreateProduct() unexpected error = %v", err)
+			}
+			if got.Barcode != tt.barcode {
+				t.Errorf("Expected barcode %q, got %q", tt.barcode, got.Barcode)
+			}
+			if !reflect.DeepEqual(got.Warnings, tt.expectedWarnings) {
+				t.Errorf("Expected warnings %v, got %v", tt.expectedWarnings, got.Warnings)
+			}
+		})
+	}
+}
+
+func TestProductService_CreateProducts_DuplicateBarcodeInBatch(t *testing.T) {
+	s := product.NewProductService(
+		&MockProductRepository{},
+		&MockReceptionRepository{},
+		newMockPVZRepository(model.PVZStatusActive),
+		&MockTransactor{},
+		&MockAuditService{},
+		newMockProductTypeService(),
+		&MockStorageCellService{},
+		&MockExpectedDeliveryService{},
+		nil,
+	)
+	_, err := s.CreateProducts(context.Background(), dto.ProductBatchCreateRequest{
+		PVZID: "123e4567-e89b-12d3-a456-426614174003",
+		Products: []dto.ProductAttributes{
+			{Type: "electronics", Barcode: "4006381333931"},
+			{Type: "electronics"},
+			{Type: "electronics", Barcode: "4006381333931"},
+		},
+	})
+
+	if !errors.Is(err, model.ErrDuplicateBarcode) {
+		t.Errorf("ProductService.CreateProducts() error = %v, expected %v", err, model.ErrDuplicateBarcode)
+	}
+}
+
+func TestProductService_CreateProducts(t *testing.T) {
+	now := time.Now()
+	receptionID := "123e4567-e89b-12d3-a456-426614174002"
+
+	openReception := func(ctx context.Context, pvzID string) (*model.Reception, error) {
+		return &model.Reception{ID: receptionID, DateTime: now, PVZID: pvzID, Status: "in_progress"}, nil
+	}
+
+	createProducts := func(ctx context.Context, receptionID string, items []dto.ProductAttributes) ([]model.Product, error) {
+		products := make([]model.Product, 0, len(items))
+		for i, item := range items {
+			products = append(products, model.Product{
+				ID:           fmt.Sprintf("product-id-%d", i+1),
+				DateTime:     now.Add(time.Duration(i) * time.Microsecond),
+				Type:         item.Type,
+				ReceptionID:  receptionID,
+				SerialNumber: item.SerialNumber,
+			})
+		}
+		return products, nil
+	}
+
+	tests := []struct {
+		name            string
+		mocks           MockRepositories
+		input           []dto.ProductAttributes
+		expected        []dto.ProductCreateResponse
+		expectedAudited int
+		expectedError   bool
+	}{
+		{
+			name: "Success",
+			mocks: MockRepositories{
+				MockProductRepository:   &MockProductRepository{CreateProductsFunc: createProducts},
+				MockReceptionRepository: &MockReceptionRepository{GetLastOpenReceptionForUpdateFunc: openReception},
+			},
+			input: []dto.ProductAttributes{
+				{Type: "electronics"},
+				{Type: "phones", SerialNumber: "SN-001"},
+				{Type: "electronics"},
+			},
+			expected: []dto.ProductCreateResponse{
+				{Product: model.Product{ID: "product-id-1", DateTime: now, Type: "electronics", ReceptionID: receptionID}},
+				{Product: model.Product{ID: "product-id-2", DateTime: now.Add(time.Microsecond), Type: "phones", ReceptionID: receptionID, SerialNumber: "SN-001"}},
+				{Product: model.Product{ID: "product-id-3", DateTime: now.Add(2 * time.Microsecond), Type: "electronics", ReceptionID: receptionID}},
+			},
+			expectedAudited: 1,
+		},
+		{
+			name: "Inactive Product Type",
+			mocks: MockRepositories{
+				MockProductRepository:   &MockProductRepository{},
+				MockReceptionRepository: &MockReceptionRepository{},
+			},
+			input:         []dto.ProductAttributes{{Type: "electronics"}, {Type: "archived"}},
+			expectedError: true,
+		},
+		{
+			name: "Serial Number Required",
+			mocks: MockRepositories{
+				MockProductRepository:   &MockProductRepository{},
+				MockReceptionRepository: &MockReceptionRepository{},
+			},
+			input:         []dto.ProductAttributes{{Type: "electronics"}, {Type: "phones"}},
+			expectedError: true,
+		},
+		{
+			name: "Reception Closed",
+			mocks: MockRepositories{
+				MockProductRepository: &MockProductRepository{},
+				MockReceptionRepository: &MockReceptionRepository{
+					GetLastOpenReceptionForUpdateFunc: func(ctx context.Context, pvzID string) (*model.Reception, error) {
+						return nil, model.ErrNoOpenReception
+					},
+				},
+			},
+			input:         []dto.ProductAttributes{{Type: "electronics"}},
+			expectedError: true,
+		},
+		{
+			name: "Insert Error",
+			mocks: MockRepositories{
+				MockProductRepository: &MockProductRepository{
+					CreateProductsFunc: func(ctx context.Context, receptionID string, items []dto.ProductAttributes) ([]model.Product, error) {
+						return nil, errors.New("database error")
+					},
+				},
+				MockReceptionRepository: &MockReceptionRepository{GetLastOpenReceptionForUpdateFunc: openReception},
+			},
+			input:         []dto.ProductAttributes{{Type: "electronics"}},
+			expectedError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			audited := 0
+			s := product.NewProductService(
+				tt.mocks.MockProductRepository,
+				tt.mocks.MockReceptionRepository,
+				newMockPVZRepository(model.PVZStatusActive),
+				&MockTransactor{},
+				&MockAuditService{
+					RecordFunc: func(ctx context.Context, action model.AuditAction, entityType model.AuditEntityType, entityID string, before, after any) error {
+						if action != model.AuditActionAddProducts || entityType != model.AuditEntityReception {
+							t.Errorf("Unexpected audit entry %s %s", action, entityType)
+						}
+						audited++
+						return nil
+					},
+				},
+				newMockProductTypeService(),
+				&MockStorageCellService{},
+				&MockExpectedDeliveryService{},
+				nil,
+			)
+			got, err := s.CreateProducts(context.Background(), dto.ProductBatchCreateRequest{
+				PVZID:    "123e4567-e89b-12d3-a456-426614174003",
+				Products: tt.input,
+			})
+
+			if (err != nil) != tt.expectedError {
+				t.Errorf("ProductService.CreateProducts() error = %v, expectedError %v", err, tt.expectedError)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("ProductService.CreateProducts() = %v, expected %v", got, tt.expected)
+			}
+			if audited != tt.expectedAudited {
+				t.Errorf("Expected %d audit entries, got %d", tt.expectedAudited, audited)
+			}
+		})
+	}
+}
+
+func TestProductService_CreateProducts_InactivePVZ(t *testing.T) {
+	s := product.NewProductService(
+		&MockProductRepository{},
+		&MockReceptionRepository{},
+		newMockPVZRepository(model.PVZStatusSuspended),
+		&MockTransactor{},
+		&MockAuditService{},
+		newMockProductTypeService(),
+		&MockStorageCellService{},
+		&MockExpectedDeliveryService{},
+		nil,
+	)
+	_, err := s.CreateProducts(context.Background(), dto.ProductBatchCreateRequest{
+		PVZID:    "123e4567-e89b-12d3-a456-426614174003",
+		Products: []dto.ProductAttributes{{Type: "electronics"}},
+	})
+
+	if !errors.Is(err, model.ErrPVZNotActive) {
+		t.Errorf("Expected ErrPVZNotActive, got %v", err)
+	}
+}
+
 func TestProductService_DeleteLastProduct(t *testing.T) {
 	now := time.Now()
 
@@ -201,7 +913,7 @@ func TestProductService_DeleteLastProduct(t *testing.T) {
 					},
 				},
 				MockReceptionRepository: &MockReceptionRepository{
-					GetLastOpenReceptionFunc: func(ctx context.Context, pvzID string) (*model.Reception, error) {
+					GetLastOpenReceptionForUpdateFunc: func(ctx context.Context, pvzID string) (*model.Reception, error) {
 						return &model.Reception{
 							ID:       "123e4567-e89b-12d3-a456-426614174002",
 							DateTime: now,
@@ -218,7 +930,7 @@ func TestProductService_DeleteLastProduct(t *testing.T) {
 			name: "No Open Reception",
 			mocks: MockRepositories{
 				MockReceptionRepository: &MockReceptionRepository{
-					GetLastOpenReceptionFunc: func(ctx context.Context, pvzID string) (*model.Reception, error) {
+					GetLastOpenReceptionForUpdateFunc: func(ctx context.Context, pvzID string) (*model.Reception, error) {
 						return nil, errors.New("no open reception found for this PVZ")
 					},
 				},
@@ -238,7 +950,7 @@ func TestProductService_DeleteLastProduct(t *testing.T) {
 					},
 				},
 				MockReceptionRepository: &MockReceptionRepository{
-					GetLastOpenReceptionFunc: func(ctx context.Context, pvzID string) (*model.Reception, error) {
+					GetLastOpenReceptionForUpdateFunc: func(ctx context.Context, pvzID string) (*model.Reception, error) {
 						return &model.Reception{
 							ID:       "123e4567-e89b-12d3-a456-426614174002",
 							DateTime: now,
@@ -268,7 +980,7 @@ func TestProductService_DeleteLastProduct(t *testing.T) {
 					},
 				},
 				MockReceptionRepository: &MockReceptionRepository{
-					GetLastOpenReceptionFunc: func(ctx context.Context, pvzID string) (*model.Reception, error) {
+					GetLastOpenReceptionForUpdateFunc: func(ctx context.Context, pvzID string) (*model.Reception, error) {
 						return &model.Reception{
 							ID:       "123e4567-e89b-12d3-a456-426614174002",
 							DateTime: now,
@@ -285,7 +997,17 @@ func TestProductService_DeleteLastProduct(t *testing.T) {
 
 	for ttNum, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			s := product.NewProductService(tt.mocks.MockProductRepository, tt.mocks.MockReceptionRepository)
+			s := product.NewProductService(
+				tt.mocks.MockProductRepository,
+				tt.mocks.MockReceptionRepository,
+				newMockPVZRepository(model.PVZStatusActive),
+				&MockTransactor{},
+				&MockAuditService{},
+				newMockProductTypeService(),
+				&MockStorageCellService{},
+				&MockExpectedDeliveryService{},
+				nil,
+			)
 			err := s.DeleteLastProduct(context.Background(), tt.pvzID)
 
 			if (err != nil) != tt.expectedError {
@@ -294,3 +1016,299 @@ func TestProductService_DeleteLastProduct(t *testing.T) {
 		})
 	}
 }
+
+func TestProductService_DeleteLastProduct_Audit(t *testing.T) {
+	lastProduct := &model.Product{
+		ID:          "123e4567-e89b-12d3-a456-426614174001",
+		DateTime:    time.Now(),
+		Type:        "electronics",
+		ReceptionID: "123e4567-e89b-12d3-a456-426614174002",
+	}
+
+	mocks := MockRepositories{
+		MockProductRepository: &MockProductRepository{
+			GetLastProductInReceptionFunc: func(ctx context.Context, receptionID string) (*model.Product, error) {
+				return lastProduct, nil
+			},
+			DeleteProductFunc: func(ctx context.Context, productID string) error {
+				return nil
+			},
+		},
+		MockReceptionRepository: &MockReceptionRepository{
+			GetLastOpenReceptionForUpdateFunc: func(ctx context.Context, pvzID string) (*model.Reception, error) {
+				return &model.Reception{ID: lastProduct.ReceptionID, PVZID: pvzID, Status: "in_progress"}, nil
+			},
+		},
+	}
+
+	t.Run("Records Deleted Product", func(t *testing.T) {
+		var recordedBefore, recordedAfter any
+		auditService := &MockAuditService{
+			RecordFunc: func(ctx context.Context, action model.AuditAction, entityType model.AuditEntityType, entityID string, before, after any) error {
+				if action != model.AuditActionDelete || entityType != model.AuditEntityProduct || entityID != lastProduct.ID {
+					t.Errorf("unexpected audit entry: %s %s %s", action, entityType, entityID)
+				}
+				recordedBefore, recordedAfter = before, after
+				return nil
+			},
+		}
+
+		s := product.NewProductService(
+			mocks.MockProductRepository, mocks.MockReceptionRepository, newMockPVZRepository(model.PVZStatusActive),
+			&MockTransactor{}, auditService, newMockProductTypeService(), &MockStorageCellService{},
+			&MockExpectedDeliveryService{}, nil,
+		)
+		if err := s.DeleteLastProduct(context.Background(), "123e4567-e89b-12d3-a456-426614174003"); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if !reflect.DeepEqual(recordedBefore, lastProduct) || recordedAfter != nil {
+			t.Errorf("expected before = deleted product and empty after, got %v and %v", recordedBefore, recordedAfter)
+		}
+	})
+
+	t.Run("Audit Error", func(t *testing.T) {
+		auditService := &MockAuditService{
+			RecordFunc: func(ctx context.Context, action model.AuditAction, entityType model.AuditEntityType, entityID string, before, after any) error {
+				return errors.New("audit error")
+			},
+		}
+
+		s := product.NewProductService(
+			mocks.MockProductRepository, mocks.MockReceptionRepository, newMockPVZRepository(model.PVZStatusActive),
+			&MockTransactor{}, auditService, newMockProductTypeService(), &MockStorageCellService{},
+			&MockExpectedDeliveryService{}, nil,
+		)
+		if err := s.DeleteLastProduct(context.Background(), "123e4567-e89b-12d3-a456-426614174003"); err == nil {
+			t.Error("expected audit error to fail the operation")
+		}
+	})
+}
+
+func TestProductService_CreateProduct_StorageCell(t *testing.T) {
+	pvzID := "123e4567-e89b-12d3-a456-426614174003"
+	cellID := "123e4567-e89b-12d3-a456-426614174005"
+	suggested := &model.StorageCell{ID: cellID, PVZID: pvzID, Code: "A-01", Capacity: 10}
+
+	tests := []struct {
+		name              string
+		cellID            string
+		placementErr      error
+		expectedSuggested *model.StorageCell
+		expectedChecked   []string
+		expectedError     error
+	}{
+		{
+			name:              "Suggests Cell",
+			expectedSuggested: suggested,
+		},
+		{
+			name:            "Placed Into Cell",
+			cellID:          cellID,
+			expectedChecked: []string{"electronics"},
+		},
+		{
+			name:            "Cell Full",
+			cellID:          cellID,
+			placementErr:    model.ErrStorageCellFull,
+			expectedChecked: []string{"electronics"},
+			expectedError:   model.ErrStorageCellFull,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var checked []string
+			storageCellService := &MockStorageCellService{
+				CheckCellPlacementFunc: func(ctx context.Context, pvzID, cellID string, productTypes ...string) error {
+					checked = append(checked, productTypes...)
+					return tt.placementErr
+				},
+				SuggestStorageCellFunc: func(ctx context.Context, pvzID, productType string) (*model.StorageCell, error) {
+					return suggested, nil
+				},
+			}
+			productRepo := &MockProductRepository{
+				CreateProductFunc: func(ctx context.Context, receptionID string, attrs dto.ProductAttributes) (*model.Product, error) {
+					return &model.Product{ID: "123e4567-e89b-12d3-a456-426614174001", Type: attrs.Type, CellID: attrs.CellID}, nil
+				},
+			}
+			receptionRepo := &MockReceptionRepository{
+				GetLastOpenReceptionForUpdateFunc: func(ctx context.Context, pvzID string) (*model.Reception, error) {
+					return &model.Reception{ID: "123e4567-e89b-12d3-a456-426614174002", PVZID: pvzID, Status: "in_progress"}, nil
+				},
+			}
+
+			s := product.NewProductService(
+				productRepo,
+				receptionRepo,
+				newMockPVZRepository(model.PVZStatusActive),
+				&MockTransactor{},
+				&MockAuditService{},
+				newMockProductTypeService(),
+				storageCellService,
+				&MockExpectedDeliveryService{},
+				nil,
+			)
+			got, err := s.CreateProduct(context.Background(), dto.ProductCreateRequest{
+				Type:   "electronics",
+				PVZID:  pvzID,
+				CellID: tt.cellID,
+			})
+
+			if !reflect.DeepEqual(checked, tt.expectedChecked) {
+				t.Errorf("Expected placement check of %v, got %v", tt.expectedChecked, checked)
+			}
+			if tt.expectedError != nil {
+				if !errors.Is(err, tt.expectedError) {
+					t.Errorf("ProductService.CreateProduct() error = %v, expected %v", err, tt.expectedError)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ProductService.CreateProduct() unexpected error = %v", err)
+			}
+			if got.CellID != tt.cellID {
+				t.Errorf("Expected cell %q, got %q", tt.cellID, got.CellID)
+			}
+			if !reflect.DeepEqual(got.SuggestedCell, tt.expectedSuggested) {
+				t.Errorf("Expected suggested cell %v, got %v", tt.expectedSuggested, got.SuggestedCell)
+			}
+		})
+	}
+}
+
+func TestProductService_CreateProducts_StorageCells(t *testing.T) {
+	checked := make(map[string][]string)
+	storageCellService := &MockStorageCellService{
+		CheckCellPlacementFunc: func(ctx context.Context, pvzID, cellID string, productTypes ...string) error {
+			checked[cellID] = productTypes
+			if cellID == "cell-b" {
+				return model.ErrStorageCellFull
+			}
+			return nil
+		},
+	}
+	receptionRepo := &MockReceptionRepository{
+		GetLastOpenReceptionForUpdateFunc: func(ctx context.Context, pvzID string) (*model.Reception, error) {
+			return &model.Reception{ID: "123e4567-e89b-12d3-a456-426614174002", PVZID: pvzID, Status: "in_progress"}, nil
+		},
+	}
+
+	s := product.NewProductService(
+		&MockProductRepository{},
+		receptionRepo,
+		newMockPVZRepository(model.PVZStatusActive),
+		&MockTransactor{},
+		&MockAuditService{},
+		newMockProductTypeService(),
+		storageCellService,
+		&MockExpectedDeliveryService{},
+		nil,
+	)
+	_, err := s.CreateProducts(context.Background(), dto.ProductBatchCreateRequest{
+		PVZID: "123e4567-e89b-12d3-a456-426614174003",
+		Products: []dto.ProductAttributes{
+			{Type: "electronics", CellID: "cell-a"},
+			{Type: "phones", SerialNumber: "SN-001", CellID: "cell-b"},
+			{Type: "electronics"},
+			{Type: "electronics", CellID: "cell-a"},
+		},
+	})
+
+	if !errors.Is(err, model.ErrStorageCellFull) {
+		t.Errorf("ProductService.CreateProducts() error = %v, expected %v", err, model.ErrStorageCellFull)
+	}
+	expected := map[string][]string{
+		"cell-a": {"electronics", "electronics"},
+		"cell-b": {"phones"},
+	}
+	if !reflect.DeepEqual(checked, expected) {
+		t.Errorf("Expected placement checks %v, got %v", expected, checked)
+	}
+}
+
+func TestProductService_CreateProducts_ExpectedDelivery(t *testing.T) {
+	pvzID := "123e4567-e89b-12d3-a456-426614174003"
+	receptionID := "123e4567-e89b-12d3-a456-426614174002"
+
+	productRepo := &MockProductRepository{
+		CreateProductsFunc: func(ctx context.Context, receptionID string, items []dto.ProductAttributes) ([]model.Product, error) {
+			products := make([]model.Product, 0, len(items))
+			for i, item := range items {
+				products = append(products, model.Product{
+					ID:          fmt.Sprintf("123e4567-e89b-12d3-a456-42661417401%d", i),
+					ReceptionID: receptionID,
+					Type:        item.Type,
+					Barcode:     item.Barcode,
+				})
+			}
+			return products, nil
+		},
+		GetProductsByBarcodesFunc: func(ctx context.Context, barcodes []string) ([]model.ProductLocation, error) {
+			return nil, nil
+		},
+	}
+	receptionRepo := &MockReceptionRepository{
+		GetLastOpenReceptionForUpdateFunc: func(ctx context.Context, pvzID string) (*model.Reception, error) {
+			return &model.Reception{ID: receptionID, PVZID: pvzID, Status: "in_progress"}, nil
+		},
+	}
+
+	var matched []string
+	deliveryService := &MockExpectedDeliveryService{
+		MatchProductFunc: func(ctx context.Context, product model.Product) ([]string, error) {
+			matched = append(matched, product.ID)
+			if product.Barcode == "4006381333931" {
+				return []string{"barcode 4006381333931 is expected with product type clothes, not electronics"}, nil
+			}
+			return nil, nil
+		},
+	}
+
+	s := product.NewProductService(
+		productRepo,
+		receptionRepo,
+		newMockPVZRepository(model.PVZStatusActive),
+		&MockTransactor{},
+		&MockAuditService{},
+		newMockProductTypeService(),
+		&MockStorageCellService{},
+		deliveryService,
+		nil,
+	)
+	got, err := s.CreateProducts(context.Background(), dto.ProductBatchCreateRequest{
+		PVZID: pvzID,
+		Products: []dto.ProductAttributes{
+			{Type: "electronics", Barcode: "4006381333931"},
+			{Type: "electronics", Barcode: "4006381333948"},
+		},
+	})
+	if err != nil {
+		t.Fatalf("ProductService.CreateProducts() unexpected error = %v", err)
+	}
+
+	expectedMatched := []string{"123e4567-e89b-12d3-a456-426614174010", "123e4567-e89b-12d3-a456-426614174011"}
+	if !reflect.DeepEqual(matched, expectedMatched) {
+		t.Errorf("Expected products %v to be matched, got %v", expectedMatched, matched)
+	}
+
+	expectedWarnings := []string{"barcode 4006381333931 is expected with product type clothes, not electronics"}
+	if !reflect.DeepEqual(got[0].Warnings, expectedWarnings) {
+		t.Errorf("Expected warnings %v, got %v", expectedWarnings, got[0].Warnings)
+	}
+	if got[1].Warnings != nil {
+		t.Errorf("Expected no warnings, got %v", got[1].Warnings)
+	}
+
+	deliveryService.MatchProductFunc = func(ctx context.Context, product model.Product) ([]string, error) {
+		return nil, errors.New("database error")
+	}
+	_, err = s.CreateProducts(context.Background(), dto.ProductBatchCreateRequest{
+		PVZID:    pvzID,
+		Products: []dto.ProductAttributes{{Type: "electronics", Barcode: "4006381333931"}},
+	})
+	if err == nil {
+		t.Error("expected matching error to fail the batch")
+	}
+}
@@ -0,0 +1,245 @@
+package productinspection
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/kirillidk/pvz-service/internal/dto"
+	"github.com/kirillidk/pvz-service/internal/filestorage"
+	"github.com/kirillidk/pvz-service/internal/model"
+	"github.com/kirillidk/pvz-service/internal/repository"
+	"github.com/kirillidk/pvz-service/internal/service/audit"
+)
+
+// sniffLength is the number of bytes http.DetectContentType looks at.
+const sniffLength = 512
+
+// attachmentExtensions maps the accepted content types to the extension of
+// the stored file.
+var attachmentExtensions = map[string]string{
+	"image/jpeg": ".jpg",
+	"image/png":  ".png",
+	"image/webp": ".webp",
+}
+
+type ProductInspectionServiceInterface interface {
+	SetProductCondition(ctx context.Context, pvzID, productID string, conditionReq dto.ProductConditionRequest) (*model.Product, error)
+	AddProductAttachment(ctx context.Context, pvzID, productID, fileName string, content io.Reader) (*model.ProductAttachment, error)
+	GetProductAttachments(ctx context.Context, pvzID, productID string) ([]model.ProductAttachment, error)
+	OpenProductAttachment(ctx context.Context, pvzID, productID, attachmentID string) (*model.ProductAttachment, io.ReadCloser, error)
+}
+
+type ProductInspectionService struct {
+	productRepository    repository.ProductRepositoryInterface
+	attachmentRepository repository.ProductAttachmentRepositoryInterface
+	transactor           repository.TransactorInterface
+	auditService         audit.AuditServiceInterface
+	storage              filestorage.Storage
+	maxAttachmentSize    int64
+}
+
+func NewProductInspectionService(
+	productRepo repository.ProductRepositoryInterface,
+	attachmentRepo repository.ProductAttachmentRepositoryInterface,
+	transactor repository.TransactorInterface,
+	auditService audit.AuditServiceInterface,
+	storage filestorage.Storage,
+	maxAttachmentSize int64,
+) *ProductInspectionService {
+	return &ProductInspectionService{
+		productRepository:    productRepo,
+		attachmentRepository: attachmentRepo,
+		transactor:           transactor,
+		auditService:         auditService,
+		storage:              storage,
+		maxAttachmentSize:    maxAttachmentSize,
+	}
+}
+
+// SetProductCondition records the state a product of the PVZ arrived in.
+// It can be changed while the product is still at the PVZ.
+func (s *ProductInspectionService) SetProductCondition(
+	ctx context.Context,
+	pvzID, productID string,
+	conditionReq dto.ProductConditionRequest,
+) (*model.Product, error) {
+	var updatedProduct *model.Product
+
+	err := s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		location, err := s.productRepository.GetProductLocationForUpdate(ctx, productID)
+		if err != nil {
+			return fmt.Errorf("failed to get product: %w", err)
+		}
+
+		if err := location.CheckPVZ(pvzID); err != nil {
+			return err
+		}
+
+		product := location.Product
+
+		if product.Status != model.ProductStatusReceived && product.Status != model.ProductStatusStored {
+			return fmt.Errorf("cannot change condition of %s product", product.Status)
+		}
+
+		updatedProduct, err = s.productRepository.UpdateProductCondition(ctx, productID, conditionReq.Condition, conditionReq.Note)
+		if err != nil {
+			return fmt.Errorf("failed to update product condition: %w", err)
+		}
+
+		return s.auditService.Record(ctx, model.AuditActionUpdate, model.AuditEntityProduct, productID, product, updatedProduct)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return updatedProduct, nil
+}
+
+// AddProductAttachment stores content as a photo of the product. Only
+// JPEG, PNG and WebP images are accepted; the type is detected from the
+// content rather than trusted from the client.
+func (s *ProductInspectionService) AddProductAttachment(
+	ctx context.Context,
+	pvzID, productID, fileName string,
+	content io.Reader,
+) (*model.ProductAttachment, error) {
+	location, err := s.productRepository.GetProductLocation(ctx, productID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get product: %w", err)
+	}
+
+	if err := location.CheckPVZ(pvzID); err != nil {
+		return nil, err
+	}
+
+	head := make([]byte, sniffLength)
+	n, err := io.ReadFull(content, head)
+	if err != nil && !errors.Is(err, io.ErrUnexpectedEOF) && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("failed to read attachment: %w", err)
+	}
+	head = head[:n]
+
+	contentType := http.DetectContentType(head)
+	extension, ok := attachmentExtensions[contentType]
+	if !ok {
+		return nil, model.ErrUnsupportedAttachment
+	}
+
+	key, err := newStorageKey(productID, extension)
+	if err != nil {
+		return nil, err
+	}
+
+	// One byte over the limit is enough to tell that the file is too large.
+	limited := io.LimitReader(io.MultiReader(bytes.NewReader(head), content), s.maxAttachmentSize+1)
+
+	size, err := s.storage.Save(ctx, key, limited)
+	if err != nil {
+		return nil, fmt.Errorf("failed to save attachment: %w", err)
+	}
+
+	if size > s.maxAttachmentSize {
+		s.deleteFile(ctx, key)
+		return nil, model.ErrAttachmentTooLarge
+	}
+
+	var attachment *model.ProductAttachment
+
+	err = s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		attachment, err = s.attachmentRepository.CreateProductAttachment(ctx, model.ProductAttachment{
+			ProductID:   productID,
+			FileName:    fileName,
+			ContentType: contentType,
+			Size:        size,
+			StorageKey:  key,
+			CreatedAt:   time.Now().UTC(),
+		})
+		if err != nil {
+			return fmt.Errorf("failed to create attachment: %w", err)
+		}
+
+		return s.auditService.Record(ctx, model.AuditActionCreate, model.AuditEntityAttachment, attachment.ID, nil, attachment)
+	})
+	if err != nil {
+		s.deleteFile(ctx, key)
+		return nil, err
+	}
+
+	return attachment, nil
+}
+
+func (s *ProductInspectionService) GetProductAttachments(ctx context.Context, pvzID, productID string) ([]model.ProductAttachment, error) {
+	location, err := s.productRepository.GetProductLocation(ctx, productID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get product: %w", err)
+	}
+
+	if err := location.CheckPVZ(pvzID); err != nil {
+		return nil, err
+	}
+
+	attachments, err := s.attachmentRepository.GetProductAttachments(ctx, productID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get product attachments: %w", err)
+	}
+
+	return attachments, nil
+}
+
+// OpenProductAttachment returns the attachment with its content. The
+// caller must close the content.
+func (s *ProductInspectionService) OpenProductAttachment(
+	ctx context.Context,
+	pvzID, productID, attachmentID string,
+) (*model.ProductAttachment, io.ReadCloser, error) {
+	location, err := s.productRepository.GetProductLocation(ctx, productID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get product: %w", err)
+	}
+
+	if err := location.CheckPVZ(pvzID); err != nil {
+		return nil, nil, err
+	}
+
+	attachment, err := s.attachmentRepository.GetProductAttachment(ctx, productID, attachmentID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get attachment: %w", err)
+	}
+
+	content, err := s.storage.Open(ctx, attachment.StorageKey)
+	if err != nil {
+		if errors.Is(err, filestorage.ErrNotFound) {
+			return nil, nil, fmt.Errorf("failed to open attachment: %w", model.ErrAttachmentNotFound)
+		}
+		return nil, nil, fmt.Errorf("failed to open attachment: %w", err)
+	}
+
+	return attachment, content, nil
+}
+
+// deleteFile removes a file that was stored but is not referenced by an
+// attachment. A failure only leaves an unused file behind.
+func (s *ProductInspectionService) deleteFile(ctx context.Context, key string) {
+	if err := s.storage.Delete(ctx, key); err != nil {
+		log.Printf("failed to delete unused attachment %s: %v", key, err)
+	}
+}
+
+// newStorageKey places the attachments of a product under a directory of
+// their own. The file name is random, so that names given by clients never
+// reach the filesystem.
+func newStorageKey(productID, extension string) (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate attachment name: %w", err)
+	}
+	return productID + "/" + hex.EncodeToString(b) + extension, nil
+}
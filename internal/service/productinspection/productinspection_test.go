@@ -0,0 +1,351 @@
+package productinspection_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/kirillidk/pvz-service/internal/dto"
+	"github.com/kirillidk/pvz-service/internal/filestorage"
+	"github.com/kirillidk/pvz-service/internal/model"
+	"github.com/kirillidk/pvz-service/internal/service/productinspection"
+)
+
+type MockProductRepository struct {
+	GetProductLocationFunc     func(ctx context.Context, productID string, forUpdate bool) (*model.ProductLocation, error)
+	UpdateProductConditionFunc func(ctx context.Context, productID string, condition model.ProductCondition, note string) (*model.Product, error)
+}
+
+func (m *MockProductRepository) CreateProduct(ctx context.Context, receptionID string, attrs dto.ProductAttributes) (*model.Product, error) {
+	return nil, nil
+}
+
+func (m *MockProductRepository) CreateProducts(ctx context.Context, receptionID string, items []dto.ProductAttributes) ([]model.Product, error) {
+	return nil, nil
+}
+
+func (m *MockProductRepository) GetLastProductInReception(ctx context.Context, receptionID string) (*model.Product, error) {
+	return nil, nil
+}
+
+func (m *MockProductRepository) DeleteProduct(ctx context.Context, productID string) error {
+	return nil
+}
+
+func (m *MockProductRepository) GetProductsByReceptionID(ctx context.Context, receptionID string) ([]model.Product, error) {
+	return nil, nil
+}
+
+func (m *MockProductRepository) GetProductsByReceptionIDInInsertionOrder(ctx context.Context, receptionID string) ([]model.Product, error) {
+	return nil, nil
+}
+
+func (m *MockProductRepository) GetProductsByBarcodes(ctx context.Context, barcodes []string) ([]model.ProductLocation, error) {
+	return nil, nil
+}
+
+func (m *MockProductRepository) GetProductLocation(ctx context.Context, productID string) (*model.ProductLocation, error) {
+	return m.GetProductLocationFunc(ctx, productID, false)
+}
+
+func (m *MockProductRepository) GetProductLocationForUpdate(ctx context.Context, productID string) (*model.ProductLocation, error) {
+	return m.GetProductLocationFunc(ctx, productID, true)
+}
+
+func (m *MockProductRepository) UpdateProductStatus(ctx context.Context, productID string, status model.ProductStatus) (*model.Product, error) {
+	return nil, nil
+}
+
+func (m *MockProductRepository) UpdateProductCell(ctx context.Context, productID, cellID string) (*model.Product, error) {
+	return nil, nil
+}
+
+func (m *MockProductRepository) UpdateProductCondition(
+	ctx context.Context,
+	productID string,
+	condition model.ProductCondition,
+	note string,
+) (*model.Product, error) {
+	return m.UpdateProductConditionFunc(ctx, productID, condition, note)
+}
+
+type MockProductAttachmentRepository struct {
+	Created []model.ProductAttachment
+
+	CreateErr                error
+	GetProductAttachmentFunc func(ctx context.Context, productID, attachmentID string) (*model.ProductAttachment, error)
+}
+
+func (m *MockProductAttachmentRepository) CreateProductAttachment(
+	ctx context.Context,
+	attachment model.ProductAttachment,
+) (*model.ProductAttachment, error) {
+	if m.CreateErr != nil {
+		return nil, m.CreateErr
+	}
+	attachment.ID = "123e4567-e89b-12d3-a456-426614174020"
+	m.Created = append(m.Created, attachment)
+	return &attachment, nil
+}
+
+func (m *MockProductAttachmentRepository) GetProductAttachments(ctx context.Context, productID string) ([]model.ProductAttachment, error) {
+	return m.Created, nil
+}
+
+func (m *MockProductAttachmentRepository) GetProductAttachment(
+	ctx context.Context,
+	productID, attachmentID string,
+) (*model.ProductAttachment, error) {
+	return m.GetProductAttachmentFunc(ctx, productID, attachmentID)
+}
+
+type MockTransactor struct{}
+
+func (m *MockTransactor) WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	return fn(ctx)
+}
+
+type MockAuditService struct {
+	Entities []model.AuditEntityType
+}
+
+func (m *MockAuditService) Record(
+	ctx context.Context,
+	action model.AuditAction,
+	entityType model.AuditEntityType,
+	entityID string,
+	before, after any,
+) error {
+	m.Entities = append(m.Entities, entityType)
+	return nil
+}
+
+func (m *MockAuditService) GetAuditLog(ctx context.Context, filter dto.AuditFilterQuery) ([]model.AuditEntry, error) {
+	return nil, nil
+}
+
+const (
+	pvzID     = "123e4567-e89b-12d3-a456-426614174003"
+	productID = "123e4567-e89b-12d3-a456-426614174001"
+)
+
+// pngHeader is enough of a PNG file for its type to be detected.
+var pngHeader = []byte("\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR")
+
+func newProductRepository(status model.ProductStatus) *MockProductRepository {
+	return &MockProductRepository{
+		GetProductLocationFunc: func(ctx context.Context, id string, forUpdate bool) (*model.ProductLocation, error) {
+			return &model.ProductLocation{
+				Product: model.Product{ID: id, Status: status, Condition: model.ProductConditionOK},
+				PVZID:   pvzID,
+			}, nil
+		},
+		UpdateProductConditionFunc: func(ctx context.Context, id string, condition model.ProductCondition, note string) (*model.Product, error) {
+			return &model.Product{ID: id, Status: status, Condition: condition, ConditionNote: note}, nil
+		},
+	}
+}
+
+func TestProductInspectionService_SetProductCondition(t *testing.T) {
+	conditionReq := dto.ProductConditionRequest{Condition: model.ProductConditionDamaged, Note: "box torn"}
+
+	tests := []struct {
+		name            string
+		pvzID           string
+		status          model.ProductStatus
+		expectedError   error
+		expectedMessage string
+	}{
+		{
+			name:   "Received Product",
+			pvzID:  pvzID,
+			status: model.ProductStatusReceived,
+		},
+		{
+			name:   "Stored Product",
+			pvzID:  pvzID,
+			status: model.ProductStatusStored,
+		},
+		{
+			name:            "Issued Product",
+			pvzID:           pvzID,
+			status:          model.ProductStatusIssued,
+			expectedMessage: "cannot change condition of issued product",
+		},
+		{
+			name:          "Product Of Another PVZ",
+			pvzID:         "123e4567-e89b-12d3-a456-426614174009",
+			status:        model.ProductStatusReceived,
+			expectedError: model.ErrProductNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			auditService := &MockAuditService{}
+			s := productinspection.NewProductInspectionService(
+				newProductRepository(tt.status), &MockProductAttachmentRepository{}, &MockTransactor{}, auditService,
+				filestorage.NewLocalStorage(t.TempDir()), 1024,
+			)
+
+			product, err := s.SetProductCondition(context.Background(), tt.pvzID, productID, conditionReq)
+
+			switch {
+			case tt.expectedError != nil:
+				if !errors.Is(err, tt.expectedError) {
+					t.Errorf("SetProductCondition() error = %v, expected %v", err, tt.expectedError)
+				}
+			case tt.expectedMessage != "":
+				if err == nil || err.Error() != tt.expectedMessage {
+					t.Errorf("SetProductCondition() error = %v, expected %q", err, tt.expectedMessage)
+				}
+			default:
+				if err != nil {
+					t.Fatalf("SetProductCondition() unexpected error = %v", err)
+				}
+				if product.Condition != model.ProductConditionDamaged || product.ConditionNote != "box torn" {
+					t.Errorf("SetProductCondition() = %+v, expected damaged product with note", product)
+				}
+				if !reflect.DeepEqual(auditService.Entities, []model.AuditEntityType{model.AuditEntityProduct}) {
+					t.Errorf("Expected audit entities %v, got %v", []model.AuditEntityType{model.AuditEntityProduct}, auditService.Entities)
+				}
+			}
+		})
+	}
+}
+
+func TestProductInspectionService_AddProductAttachment(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Success", func(t *testing.T) {
+		storage := filestorage.NewLocalStorage(t.TempDir())
+		attachmentRepo := &MockProductAttachmentRepository{}
+		auditService := &MockAuditService{}
+		s := productinspection.NewProductInspectionService(
+			newProductRepository(model.ProductStatusReceived), attachmentRepo, &MockTransactor{}, auditService, storage, 1024,
+		)
+
+		attachment, err := s.AddProductAttachment(ctx, pvzID, productID, "box.png", bytes.NewReader(pngHeader))
+		if err != nil {
+			t.Fatalf("AddProductAttachment() unexpected error = %v", err)
+		}
+
+		if attachment.ContentType != "image/png" || attachment.Size != int64(len(pngHeader)) || attachment.FileName != "box.png" {
+			t.Errorf("AddProductAttachment() = %+v, expected a PNG of %d bytes", attachment, len(pngHeader))
+		}
+		if !strings.HasPrefix(attachment.StorageKey, productID+"/") || !strings.HasSuffix(attachment.StorageKey, ".png") {
+			t.Errorf("Expected storage key in the product directory, got %q", attachment.StorageKey)
+		}
+		if !reflect.DeepEqual(auditService.Entities, []model.AuditEntityType{model.AuditEntityAttachment}) {
+			t.Errorf("Expected audit entities %v, got %v", []model.AuditEntityType{model.AuditEntityAttachment}, auditService.Entities)
+		}
+
+		file, err := storage.Open(ctx, attachment.StorageKey)
+		if err != nil {
+			t.Fatalf("Open() unexpected error = %v", err)
+		}
+		defer file.Close()
+
+		stored, _ := io.ReadAll(file)
+		if !bytes.Equal(stored, pngHeader) {
+			t.Errorf("Expected stored content %q, got %q", pngHeader, stored)
+		}
+	})
+
+	t.Run("Unsupported Type", func(t *testing.T) {
+		attachmentRepo := &MockProductAttachmentRepository{}
+		s := productinspection.NewProductInspectionService(
+			newProductRepository(model.ProductStatusReceived), attachmentRepo, &MockTransactor{}, &MockAuditService{},
+			filestorage.NewLocalStorage(t.TempDir()), 1024,
+		)
+
+		_, err := s.AddProductAttachment(ctx, pvzID, productID, "note.txt", strings.NewReader("plain text"))
+		if !errors.Is(err, model.ErrUnsupportedAttachment) {
+			t.Errorf("AddProductAttachment() error = %v, expected %v", err, model.ErrUnsupportedAttachment)
+		}
+		if len(attachmentRepo.Created) != 0 {
+			t.Errorf("Expected no attachments to be created, got %d", len(attachmentRepo.Created))
+		}
+	})
+
+	t.Run("Too Large", func(t *testing.T) {
+		attachmentRepo := &MockProductAttachmentRepository{}
+		s := productinspection.NewProductInspectionService(
+			newProductRepository(model.ProductStatusReceived), attachmentRepo, &MockTransactor{}, &MockAuditService{},
+			filestorage.NewLocalStorage(t.TempDir()), 8,
+		)
+
+		_, err := s.AddProductAttachment(ctx, pvzID, productID, "box.png", bytes.NewReader(pngHeader))
+		if !errors.Is(err, model.ErrAttachmentTooLarge) {
+			t.Errorf("AddProductAttachment() error = %v, expected %v", err, model.ErrAttachmentTooLarge)
+		}
+		if len(attachmentRepo.Created) != 0 {
+			t.Errorf("Expected no attachments to be created, got %d", len(attachmentRepo.Created))
+		}
+	})
+
+	t.Run("Product Of Another PVZ", func(t *testing.T) {
+		s := productinspection.NewProductInspectionService(
+			newProductRepository(model.ProductStatusReceived), &MockProductAttachmentRepository{}, &MockTransactor{},
+			&MockAuditService{}, filestorage.NewLocalStorage(t.TempDir()), 1024,
+		)
+
+		_, err := s.AddProductAttachment(ctx, "123e4567-e89b-12d3-a456-426614174009", productID, "box.png", bytes.NewReader(pngHeader))
+		if !errors.Is(err, model.ErrProductNotFound) {
+			t.Errorf("AddProductAttachment() error = %v, expected %v", err, model.ErrProductNotFound)
+		}
+	})
+}
+
+func TestProductInspectionService_OpenProductAttachment(t *testing.T) {
+	ctx := context.Background()
+	storage := filestorage.NewLocalStorage(t.TempDir())
+	attachment := &model.ProductAttachment{
+		ID:          "123e4567-e89b-12d3-a456-426614174020",
+		ProductID:   productID,
+		FileName:    "box.png",
+		ContentType: "image/png",
+		StorageKey:  productID + "/box.png",
+	}
+
+	if _, err := storage.Save(ctx, attachment.StorageKey, bytes.NewReader(pngHeader)); err != nil {
+		t.Fatalf("Save() unexpected error = %v", err)
+	}
+
+	s := productinspection.NewProductInspectionService(
+		newProductRepository(model.ProductStatusStored),
+		&MockProductAttachmentRepository{
+			GetProductAttachmentFunc: func(ctx context.Context, productID, attachmentID string) (*model.ProductAttachment, error) {
+				if attachmentID != attachment.ID {
+					return nil, model.ErrAttachmentNotFound
+				}
+				return attachment, nil
+			},
+		},
+		&MockTransactor{}, &MockAuditService{}, storage, 1024,
+	)
+
+	t.Run("Success", func(t *testing.T) {
+		got, content, err := s.OpenProductAttachment(ctx, pvzID, productID, attachment.ID)
+		if err != nil {
+			t.Fatalf("OpenProductAttachment() unexpected error = %v", err)
+		}
+		defer content.Close()
+
+		data, _ := io.ReadAll(content)
+		if got != attachment || !bytes.Equal(data, pngHeader) {
+			t.Errorf("OpenProductAttachment() = %+v, %q, expected %+v, %q", got, data, attachment, pngHeader)
+		}
+	})
+
+	t.Run("Not Found", func(t *testing.T) {
+		_, _, err := s.OpenProductAttachment(ctx, pvzID, productID, "123e4567-e89b-12d3-a456-426614174021")
+		if !errors.Is(err, model.ErrAttachmentNotFound) {
+			t.Errorf("OpenProductAttachment() error = %v, expected %v", err, model.ErrAttachmentNotFound)
+		}
+	})
+}
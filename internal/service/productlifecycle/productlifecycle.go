@@ -0,0 +1,169 @@
+package productlifecycle
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/kirillidk/pvz-service/internal/model"
+	"github.com/kirillidk/pvz-service/internal/repository"
+	"github.com/kirillidk/pvz-service/internal/requestctx"
+	"github.com/kirillidk/pvz-service/internal/service/audit"
+)
+
+// receptionStatusClosed is the status of a reception that no longer
+// accepts products.
+const receptionStatusClosed = "close"
+
+type ProductLifecycleServiceInterface interface {
+	ChangeProductStatus(ctx context.Context, pvzID, productID string, status model.ProductStatus) (*model.Product, error)
+	GetProductStatusHistory(ctx context.Context, pvzID, productID string) ([]model.ProductStatusChange, error)
+	IssueOrderProducts(ctx context.Context, pvzID, orderID string, productIDs []string) ([]model.Product, error)
+}
+
+type ProductLifecycleService struct {
+	productRepository       repository.ProductRepositoryInterface
+	productStatusRepository repository.ProductStatusRepositoryInterface
+	transactor              repository.TransactorInterface
+	auditService            audit.AuditServiceInterface
+}
+
+func NewProductLifecycleService(
+	productRepo repository.ProductRepositoryInterface,
+	productStatusRepo repository.ProductStatusRepositoryInterface,
+	transactor repository.TransactorInterface,
+	auditService audit.AuditServiceInterface,
+) *ProductLifecycleService {
+	return &ProductLifecycleService{
+		productRepository:       productRepo,
+		productStatusRepository: productStatusRepo,
+		transactor:              transactor,
+		auditService:            auditService,
+	}
+}
+
+// ChangeProductStatus moves a product of the PVZ to status and records the
+// change with the acting user. Products are stored only once their
+// reception is closed, products of an order are issued only together with
+// it, and products of a transfer stay as they are until it is received.
+func (s *ProductLifecycleService) ChangeProductStatus(
+	ctx context.Context,
+	pvzID, productID string,
+	status model.ProductStatus,
+) (*model.Product, error) {
+	return s.changeStatus(ctx, pvzID, productID, "", status)
+}
+
+// IssueOrderProducts issues all products of the order at once: either every
+// product is issued or none is.
+func (s *ProductLifecycleService) IssueOrderProducts(
+	ctx context.Context,
+	pvzID, orderID string,
+	productIDs []string,
+) ([]model.Product, error) {
+	products := make([]model.Product, 0, len(productIDs))
+
+	err := s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		for _, productID := range productIDs {
+			product, err := s.changeStatus(ctx, pvzID, productID, orderID, model.ProductStatusIssued)
+			if err != nil {
+				return err
+			}
+			products = append(products, *product)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return products, nil
+}
+
+// changeStatus changes the product status on behalf of the order with
+// orderID, or of no order when it is empty.
+func (s *ProductLifecycleService) changeStatus(
+	ctx context.Context,
+	pvzID, productID, orderID string,
+	status model.ProductStatus,
+) (*model.Product, error) {
+	var updatedProduct *model.Product
+
+	err := s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		location, err := s.productRepository.GetProductLocationForUpdate(ctx, productID)
+		if err != nil {
+			return fmt.Errorf("failed to get product: %w", err)
+		}
+
+		if err := location.CheckPVZ(pvzID); err != nil {
+			return err
+		}
+
+		if location.TransferID != "" {
+			return model.ErrProductInTransfer
+		}
+
+		if status == model.ProductStatusIssued && location.OrderID != orderID {
+			return model.ErrProductInOrder
+		}
+
+		product := location.Product
+
+		if product.Status == status {
+			return fmt.Errorf("product is already %s", status)
+		}
+
+		if !product.Status.CanTransitionTo(status) {
+			return fmt.Errorf("cannot change product status from %s to %s", product.Status, status)
+		}
+
+		if status == model.ProductStatusStored && location.ReceptionStatus != receptionStatusClosed {
+			return errors.New("cannot store product before its reception is closed")
+		}
+
+		updatedProduct, err = s.productRepository.UpdateProductStatus(ctx, productID, status)
+		if err != nil {
+			return fmt.Errorf("failed to update product status: %w", err)
+		}
+
+		_, err = s.productStatusRepository.CreateProductStatusChange(ctx, model.ProductStatusChange{
+			ProductID:  productID,
+			FromStatus: product.Status,
+			ToStatus:   status,
+			ChangedAt:  time.Now().UTC(),
+			Actor:      requestctx.ActorFromContext(ctx),
+		})
+		if err != nil {
+			return fmt.Errorf("failed to record product status change: %w", err)
+		}
+
+		return s.auditService.Record(ctx, model.AuditActionStatusChange, model.AuditEntityProduct, productID, product, updatedProduct)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return updatedProduct, nil
+}
+
+func (s *ProductLifecycleService) GetProductStatusHistory(
+	ctx context.Context,
+	pvzID, productID string,
+) ([]model.ProductStatusChange, error) {
+	location, err := s.productRepository.GetProductLocation(ctx, productID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get product: %w", err)
+	}
+
+	if err := location.CheckPVZ(pvzID); err != nil {
+		return nil, err
+	}
+
+	history, err := s.productStatusRepository.GetProductStatusHistory(ctx, productID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get product status history: %w", err)
+	}
+
+	return history, nil
+}
@@ -0,0 +1,423 @@
+package productlifecycle_test
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/kirillidk/pvz-service/internal/dto"
+	"github.com/kirillidk/pvz-service/internal/model"
+	"github.com/kirillidk/pvz-service/internal/requestctx"
+	"github.com/kirillidk/pvz-service/internal/service/productlifecycle"
+)
+
+type MockProductRepository struct {
+	GetProductLocationFunc  func(ctx context.Context, productID string, forUpdate bool) (*model.ProductLocation, error)
+	UpdateProductStatusFunc func(ctx context.Context, productID string, status model.ProductStatus) (*model.Product, error)
+}
+
+func (m *MockProductRepository) CreateProduct(ctx context.Context, receptionID string, attrs dto.ProductAttributes) (*model.Product, error) {
+	return nil, nil
+}
+
+func (m *MockProductRepository) CreateProducts(ctx context.Context, receptionID string, items []dto.ProductAttributes) ([]model.Product, error) {
+	return nil, nil
+}
+
+func (m *MockProductRepository) GetLastProductInReception(ctx context.Context, receptionID string) (*model.Product, error) {
+	return nil, nil
+}
+
+func (m *MockProductRepository) DeleteProduct(ctx context.Context, productID string) error {
+	return nil
+}
+
+func (m *MockProductRepository) GetProductsByReceptionID(ctx context.Context, receptionID string) ([]model.Product, error) {
+	return nil, nil
+}
+
+func (m *MockProductRepository) GetProductsByReceptionIDInInsertionOrder(ctx context.Context, receptionID string) ([]model.Product, error) {
+	return nil, nil
+}
+
+func (m *MockProductRepository) GetProductsByBarcodes(ctx context.Context, barcodes []string) ([]model.ProductLocation, error) {
+	return nil, nil
+}
+
+func (m *MockProductRepository) GetProductLocation(ctx context.Context, productID string) (*model.ProductLocation, error) {
+	return m.GetProductLocationFunc(ctx, productID, false)
+}
+
+func (m *MockProductRepository) GetProductLocationForUpdate(ctx context.Context, productID string) (*model.ProductLocation, error) {
+	return m.GetProductLocationFunc(ctx, productID, true)
+}
+
+func (m *MockProductRepository) UpdateProductStatus(ctx context.Context, productID string, status model.ProductStatus) (*model.Product, error) {
+	return m.UpdateProductStatusFunc(ctx, productID, status)
+}
+
+func (m *MockProductRepository) UpdateProductCell(ctx context.Context, productID, cellID string) (*model.Product, error) {
+	return nil, nil
+}
+
+func (m *MockProductRepository) UpdateProductCondition(
+	ctx context.Context,
+	productID string,
+	condition model.ProductCondition,
+	note string,
+) (*model.Product, error) {
+	return nil, nil
+}
+
+type MockProductStatusRepository struct {
+	Changes []model.ProductStatusChange
+
+	GetProductStatusHistoryFunc func(ctx context.Context, productID string) ([]model.ProductStatusChange, error)
+}
+
+func (m *MockProductStatusRepository) CreateProductStatusChange(
+	ctx context.Context,
+	change model.ProductStatusChange,
+) (*model.ProductStatusChange, error) {
+	m.Changes = append(m.Changes, change)
+	return &change, nil
+}
+
+func (m *MockProductStatusRepository) GetProductStatusHistory(ctx context.Context, productID string) ([]model.ProductStatusChange, error) {
+	return m.GetProductStatusHistoryFunc(ctx, productID)
+}
+
+type MockTransactor struct{}
+
+func (m *MockTransactor) WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	return fn(ctx)
+}
+
+type MockAuditService struct {
+	Actions []model.AuditAction
+}
+
+func (m *MockAuditService) Record(
+	ctx context.Context,
+	action model.AuditAction,
+	entityType model.AuditEntityType,
+	entityID string,
+	before, after any,
+) error {
+	m.Actions = append(m.Actions, action)
+	return nil
+}
+
+func (m *MockAuditService) GetAuditLog(ctx context.Context, filter dto.AuditFilterQuery) ([]model.AuditEntry, error) {
+	return nil, nil
+}
+
+func TestProductLifecycleService_ChangeProductStatus(t *testing.T) {
+	pvzID := "123e4567-e89b-12d3-a456-426614174003"
+	productID := "123e4567-e89b-12d3-a456-426614174001"
+	actor := model.Actor{ID: "123e4567-e89b-12d3-a456-426614174009", Type: model.UserActor, Role: model.EmployeeRole}
+
+	tests := []struct {
+		name            string
+		status          model.ProductStatus
+		location        *model.ProductLocation
+		locationErr     error
+		expectedError   error
+		expectedMessage string
+	}{
+		{
+			name:   "Store After Reception Closed",
+			status: model.ProductStatusStored,
+			location: &model.ProductLocation{
+				Product:         model.Product{ID: productID, Status: model.ProductStatusReceived},
+				PVZID:           pvzID,
+				ReceptionStatus: "close",
+			},
+		},
+		{
+			name:   "Issue Stored Product",
+			status: model.ProductStatusIssued,
+			location: &model.ProductLocation{
+				Product:         model.Product{ID: productID, Status: model.ProductStatusStored},
+				PVZID:           pvzID,
+				ReceptionStatus: "close",
+			},
+		},
+		{
+			name:   "Return Stored Product",
+			status: model.ProductStatusReturnedToSender,
+			location: &model.ProductLocation{
+				Product:         model.Product{ID: productID, Status: model.ProductStatusStored},
+				PVZID:           pvzID,
+				ReceptionStatus: "close",
+			},
+		},
+		{
+			name:   "Store Before Reception Closed",
+			status: model.ProductStatusStored,
+			location: &model.ProductLocation{
+				Product:         model.Product{ID: productID, Status: model.ProductStatusReceived},
+				PVZID:           pvzID,
+				ReceptionStatus: "in_progress",
+			},
+			expectedMessage: "cannot store product before its reception is closed",
+		},
+		{
+			name:   "Issue Received Product",
+			status: model.ProductStatusIssued,
+			location: &model.ProductLocation{
+				Product:         model.Product{ID: productID, Status: model.ProductStatusReceived},
+				PVZID:           pvzID,
+				ReceptionStatus: "close",
+			},
+			expectedMessage: "cannot change product status from received to issued",
+		},
+		{
+			name:   "Already Issued",
+			status: model.ProductStatusIssued,
+			location: &model.ProductLocation{
+				Product:         model.Product{ID: productID, Status: model.ProductStatusIssued},
+				PVZID:           pvzID,
+				ReceptionStatus: "close",
+			},
+			expectedMessage: "product is already issued",
+		},
+		{
+			name:   "Product Of Another PVZ",
+			status: model.ProductStatusIssued,
+			location: &model.ProductLocation{
+				Product:         model.Product{ID: productID, Status: model.ProductStatusStored},
+				PVZID:           "223e4567-e89b-12d3-a456-426614174003",
+				ReceptionStatus: "close",
+			},
+			expectedError: model.ErrProductNotFound,
+		},
+		{
+			name:   "Issue Product Of Order",
+			status: model.ProductStatusIssued,
+			location: &model.ProductLocation{
+				Product:         model.Product{ID: productID, Status: model.ProductStatusStored},
+				PVZID:           pvzID,
+				ReceptionStatus: "close",
+				OrderID:         "123e4567-e89b-12d3-a456-426614174005",
+			},
+			expectedError: model.ErrProductInOrder,
+		},
+		{
+			name:   "Return Product Of Transfer",
+			status: model.ProductStatusReturnedToSender,
+			location: &model.ProductLocation{
+				Product:         model.Product{ID: productID, Status: model.ProductStatusStored},
+				PVZID:           pvzID,
+				ReceptionStatus: "close",
+				TransferID:      "123e4567-e89b-12d3-a456-426614174008",
+			},
+			expectedError: model.ErrProductInTransfer,
+		},
+		{
+			name:          "Product Not Found",
+			status:        model.ProductStatusIssued,
+			locationErr:   model.ErrProductNotFound,
+			expectedError: model.ErrProductNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			productRepo := &MockProductRepository{
+				GetProductLocationFunc: func(ctx context.Context, id string, forUpdate bool) (*model.ProductLocation, error) {
+					if !forUpdate {
+						t.Error("Expected the product to be locked for update")
+					}
+					return tt.location, tt.locationErr
+				},
+				UpdateProductStatusFunc: func(ctx context.Context, id string, status model.ProductStatus) (*model.Product, error) {
+					product := tt.location.Product
+					product.Status = status
+					return &product, nil
+				},
+			}
+			productStatusRepo := &MockProductStatusRepository{}
+			auditService := &MockAuditService{}
+
+			s := productlifecycle.NewProductLifecycleService(productRepo, productStatusRepo, &MockTransactor{}, auditService)
+
+			ctx := requestctx.WithActor(context.Background(), actor)
+			got, err := s.ChangeProductStatus(ctx, pvzID, productID, tt.status)
+
+			if tt.expectedError != nil || tt.expectedMessage != "" {
+				if tt.expectedError != nil && !errors.Is(err, tt.expectedError) {
+					t.Errorf("ChangeProductStatus() error = %v, expected %v", err, tt.expectedError)
+				}
+				if tt.expectedMessage != "" && (err == nil || err.Error() != tt.expectedMessage) {
+					t.Errorf("ChangeProductStatus() error = %v, expected %q", err, tt.expectedMessage)
+				}
+				if len(productStatusRepo.Changes) != 0 {
+					t.Errorf("Expected no status change to be recorded, got %v", productStatusRepo.Changes)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("ChangeProductStatus() unexpected error = %v", err)
+			}
+			if got.Status != tt.status {
+				t.Errorf("Expected status %s, got %s", tt.status, got.Status)
+			}
+
+			if len(productStatusRepo.Changes) != 1 {
+				t.Fatalf("Expected 1 recorded status change, got %d", len(productStatusRepo.Changes))
+			}
+			change := productStatusRepo.Changes[0]
+			if change.FromStatus != tt.location.Status || change.ToStatus != tt.status {
+				t.Errorf("Expected change %s -> %s, got %s -> %s", tt.location.Status, tt.status, change.FromStatus, change.ToStatus)
+			}
+			if change.Actor != actor {
+				t.Errorf("Expected actor %v, got %v", actor, change.Actor)
+			}
+			if change.ChangedAt.IsZero() {
+				t.Error("Expected the change time to be set")
+			}
+
+			if !reflect.DeepEqual(auditService.Actions, []model.AuditAction{model.AuditActionStatusChange}) {
+				t.Errorf("Expected a status change audit record, got %v", auditService.Actions)
+			}
+		})
+	}
+}
+
+func TestProductLifecycleService_IssueOrderProducts(t *testing.T) {
+	pvzID := "123e4567-e89b-12d3-a456-426614174003"
+	orderID := "123e4567-e89b-12d3-a456-426614174005"
+	productIDs := []string{"123e4567-e89b-12d3-a456-426614174001", "123e4567-e89b-12d3-a456-426614174002"}
+
+	tests := []struct {
+		name            string
+		secondStatus    model.ProductStatus
+		expectedChanges int
+		expectedMessage string
+	}{
+		{
+			name:            "Success",
+			secondStatus:    model.ProductStatusStored,
+			expectedChanges: 2,
+		},
+		{
+			name:            "Product Not Stored",
+			secondStatus:    model.ProductStatusReceived,
+			expectedChanges: 1,
+			expectedMessage: "cannot change product status from received to issued",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			statuses := map[string]model.ProductStatus{
+				productIDs[0]: model.ProductStatusStored,
+				productIDs[1]: tt.secondStatus,
+			}
+
+			productRepo := &MockProductRepository{
+				GetProductLocationFunc: func(ctx context.Context, id string, forUpdate bool) (*model.ProductLocation, error) {
+					return &model.ProductLocation{
+						Product:         model.Product{ID: id, Status: statuses[id]},
+						PVZID:           pvzID,
+						ReceptionStatus: "close",
+						OrderID:         orderID,
+					}, nil
+				},
+				UpdateProductStatusFunc: func(ctx context.Context, id string, status model.ProductStatus) (*model.Product, error) {
+					return &model.Product{ID: id, Status: status}, nil
+				},
+			}
+			productStatusRepo := &MockProductStatusRepository{}
+
+			s := productlifecycle.NewProductLifecycleService(productRepo, productStatusRepo, &MockTransactor{}, &MockAuditService{})
+			got, err := s.IssueOrderProducts(context.Background(), pvzID, orderID, productIDs)
+
+			// The mock transactor does not roll back, so the changes made before
+			// a failure are still visible here.
+			if len(productStatusRepo.Changes) != tt.expectedChanges {
+				t.Errorf("Expected %d recorded status changes, got %d", tt.expectedChanges, len(productStatusRepo.Changes))
+			}
+
+			if tt.expectedMessage != "" {
+				if err == nil || err.Error() != tt.expectedMessage {
+					t.Errorf("IssueOrderProducts() error = %v, expected %q", err, tt.expectedMessage)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("IssueOrderProducts() unexpected error = %v", err)
+			}
+
+			expected := []model.Product{
+				{ID: productIDs[0], Status: model.ProductStatusIssued},
+				{ID: productIDs[1], Status: model.ProductStatusIssued},
+			}
+			if !reflect.DeepEqual(got, expected) {
+				t.Errorf("IssueOrderProducts() = %v, expected %v", got, expected)
+			}
+		})
+	}
+}
+
+func TestProductLifecycleService_GetProductStatusHistory(t *testing.T) {
+	pvzID := "123e4567-e89b-12d3-a456-426614174003"
+	productID := "123e4567-e89b-12d3-a456-426614174001"
+	history := []model.ProductStatusChange{
+		{ID: 1, ProductID: productID, FromStatus: model.ProductStatusReceived, ToStatus: model.ProductStatusStored, ChangedAt: time.Now()},
+	}
+
+	tests := []struct {
+		name          string
+		locationPVZID string
+		expected      []model.ProductStatusChange
+		expectedError error
+	}{
+		{
+			name:          "Success",
+			locationPVZID: pvzID,
+			expected:      history,
+		},
+		{
+			name:          "Product Of Another PVZ",
+			locationPVZID: "223e4567-e89b-12d3-a456-426614174003",
+			expectedError: model.ErrProductNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			productRepo := &MockProductRepository{
+				GetProductLocationFunc: func(ctx context.Context, id string, forUpdate bool) (*model.ProductLocation, error) {
+					return &model.ProductLocation{Product: model.Product{ID: id}, PVZID: tt.locationPVZID}, nil
+				},
+			}
+			productStatusRepo := &MockProductStatusRepository{
+				GetProductStatusHistoryFunc: func(ctx context.Context, id string) ([]model.ProductStatusChange, error) {
+					return history, nil
+				},
+			}
+
+			s := productlifecycle.NewProductLifecycleService(productRepo, productStatusRepo, &MockTransactor{}, &MockAuditService{})
+			got, err := s.GetProductStatusHistory(context.Background(), pvzID, productID)
+
+			if tt.expectedError != nil {
+				if !errors.Is(err, tt.expectedError) {
+					t.Errorf("GetProductStatusHistory() error = %v, expected %v", err, tt.expectedError)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("GetProductStatusHistory() unexpected error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("GetProductStatusHistory() = %v, expected %v", got, tt.expected)
+			}
+		})
+	}
+}
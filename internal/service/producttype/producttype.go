@@ -0,0 +1,133 @@
+package producttype
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/kirillidk/pvz-service/internal/dto"
+	"github.com/kirillidk/pvz-service/internal/model"
+	"github.com/kirillidk/pvz-service/internal/repository"
+	"github.com/kirillidk/pvz-service/internal/service/catalogcache"
+)
+
+// Bounds of the storage period a product type may set, matching the create
+// request validation.
+const (
+	minStorageDays = 1
+	maxStorageDays = 365
+)
+
+type ProductTypeServiceInterface interface {
+	CreateProductType(ctx context.Context, productTypeReq dto.ProductTypeCreateRequest) (*model.ProductType, error)
+	GetProductTypeList(ctx context.Context) ([]model.ProductType, error)
+	GetProductType(ctx context.Context, code string) (*model.ProductType, error)
+	UpdateProductType(ctx context.Context, code string, productTypeReq dto.ProductTypeUpdateRequest) (*model.ProductType, error)
+	DeleteProductType(ctx context.Context, code string) error
+	GetActiveProductType(ctx context.Context, code string) (*model.ProductType, error)
+}
+
+// ProductTypeService keeps the catalog for product acceptance in a
+// process-local cache. The cache is dropped after every change made through
+// the service.
+type ProductTypeService struct {
+	productTypeRepository repository.ProductTypeRepositoryInterface
+
+	productTypes *catalogcache.Cache[model.ProductType]
+}
+
+func NewProductTypeService(productTypeRepo repository.ProductTypeRepositoryInterface) *ProductTypeService {
+	s := &ProductTypeService{
+		productTypeRepository: productTypeRepo,
+	}
+	s.productTypes = catalogcache.New(s.load)
+
+	return s
+}
+
+func (s *ProductTypeService) CreateProductType(ctx context.Context, productTypeReq dto.ProductTypeCreateRequest) (*model.ProductType, error) {
+	productType, err := s.productTypeRepository.CreateProductType(ctx, productTypeReq)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create product type: %w", err)
+	}
+
+	s.productTypes.Invalidate()
+
+	return productType, nil
+}
+
+func (s *ProductTypeService) GetProductTypeList(ctx context.Context) ([]model.ProductType, error) {
+	productTypes, err := s.productTypeRepository.GetProductTypeList(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get product type list: %w", err)
+	}
+
+	return productTypes, nil
+}
+
+func (s *ProductTypeService) GetProductType(ctx context.Context, code string) (*model.ProductType, error) {
+	productType, err := s.productTypeRepository.GetProductTypeByCode(ctx, code)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get product type: %w", err)
+	}
+
+	return productType, nil
+}
+
+func (s *ProductTypeService) UpdateProductType(ctx context.Context, code string, productTypeReq dto.ProductTypeUpdateRequest) (*model.ProductType, error) {
+	if productTypeReq.Name == nil && productTypeReq.Active == nil && productTypeReq.RequiresSerialNumber == nil &&
+		!productTypeReq.StorageDays.Set {
+		return nil, errors.New("no fields to update")
+	}
+
+	if days := productTypeReq.StorageDays.Value; days != nil && (*days < minStorageDays || *days > maxStorageDays) {
+		return nil, fmt.Errorf("storage days must be between %d and %d", minStorageDays, maxStorageDays)
+	}
+
+	productType, err := s.productTypeRepository.UpdateProductType(ctx, code, productTypeReq)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update product type: %w", err)
+	}
+
+	s.productTypes.Invalidate()
+
+	return productType, nil
+}
+
+func (s *ProductTypeService) DeleteProductType(ctx context.Context, code string) error {
+	if err := s.productTypeRepository.DeleteProductType(ctx, code); err != nil {
+		return fmt.Errorf("failed to delete product type: %w", err)
+	}
+
+	s.productTypes.Invalidate()
+
+	return nil
+}
+
+// GetActiveProductType returns the product type products may be accepted with.
+// Unknown and deactivated codes yield an error.
+func (s *ProductTypeService) GetActiveProductType(ctx context.Context, code string) (*model.ProductType, error) {
+	productType, ok, err := s.productTypes.Get(ctx, code)
+	if err != nil {
+		return nil, err
+	}
+	if !ok || !productType.Active {
+		return nil, fmt.Errorf("%w: %s", model.ErrProductTypeNotAvailable, code)
+	}
+
+	return &productType, nil
+}
+
+func (s *ProductTypeService) load(ctx context.Context) (map[string]model.ProductType, error) {
+	list, err := s.productTypeRepository.GetProductTypeList(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load product types: %w", err)
+	}
+
+	productTypes := make(map[string]model.ProductType, len(list))
+	for _, productType := range list {
+		productTypes[productType.Code] = productType
+	}
+
+	return productTypes, nil
+}
@@ -0,0 +1,146 @@
+package producttype_test
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/kirillidk/pvz-service/internal/dto"
+	"github.com/kirillidk/pvz-service/internal/model"
+	"github.com/kirillidk/pvz-service/internal/service/producttype"
+)
+
+type MockProductTypeRepository struct {
+	CreateProductTypeFunc    func(ctx context.Context, req dto.ProductTypeCreateRequest) (*model.ProductType, error)
+	GetProductTypeListFunc   func(ctx context.Context) ([]model.ProductType, error)
+	GetProductTypeByCodeFunc func(ctx context.Context, code string) (*model.ProductType, error)
+	UpdateProductTypeFunc    func(ctx context.Context, code string, req dto.ProductTypeUpdateRequest) (*model.ProductType, error)
+	DeleteProductTypeFunc    func(ctx context.Context, code string) error
+}
+
+func (m *MockProductTypeRepository) CreateProductType(ctx context.Context, req dto.ProductTypeCreateRequest) (*model.ProductType, error) {
+	return m.CreateProductTypeFunc(ctx, req)
+}
+
+func (m *MockProductTypeRepository) GetProductTypeList(ctx context.Context) ([]model.ProductType, error) {
+	return m.GetProductTypeListFunc(ctx)
+}
+
+func (m *MockProductTypeRepository) GetProductTypeByCode(ctx context.Context, code string) (*model.ProductType, error) {
+	return m.GetProductTypeByCodeFunc(ctx, code)
+}
+
+func (m *MockProductTypeRepository) UpdateProductType(ctx context.Context, code string, req dto.ProductTypeUpdateRequest) (*model.ProductType, error) {
+	return m.UpdateProductTypeFunc(ctx, code, req)
+}
+
+func (m *MockProductTypeRepository) DeleteProductType(ctx context.Context, code string) error {
+	return m.DeleteProductTypeFunc(ctx, code)
+}
+
+func TestProductTypeService_GetActiveProductType(t *testing.T) {
+	ctx := context.Background()
+	loads := 0
+	catalog := []model.ProductType{
+		{Code: "электроника", Name: "Электроника", Active: true},
+		{Code: "обувь", Name: "Обувь", Active: true},
+	}
+
+	repo := &MockProductTypeRepository{
+		GetProductTypeListFunc: func(ctx context.Context) ([]model.ProductType, error) {
+			loads++
+			return catalog, nil
+		},
+		UpdateProductTypeFunc: func(ctx context.Context, code string, req dto.ProductTypeUpdateRequest) (*model.ProductType, error) {
+			for i := range catalog {
+				if catalog[i].Code == code {
+					catalog[i].Active = *req.Active
+					return &catalog[i], nil
+				}
+			}
+			return nil, model.ErrProductTypeNotFound
+		},
+	}
+	s := producttype.NewProductTypeService(repo)
+
+	productType, err := s.GetActiveProductType(ctx, "обувь")
+	if err != nil || productType.Code != "обувь" {
+		t.Fatalf("expected обувь to be available, got %v, %v", productType, err)
+	}
+
+	if _, err := s.GetActiveProductType(ctx, "мебель"); err == nil {
+		t.Error("expected unknown product type to be rejected")
+	}
+
+	if loads != 1 {
+		t.Errorf("expected catalog to be loaded once, got %d", loads)
+	}
+
+	inactive := false
+	if _, err := s.UpdateProductType(ctx, "обувь", dto.ProductTypeUpdateRequest{Active: &inactive}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if _, err := s.GetActiveProductType(ctx, "обувь"); err == nil {
+		t.Error("expected deactivated product type to be rejected")
+	}
+
+	if loads != 2 {
+		t.Errorf("expected catalog to be reloaded after update, got %d loads", loads)
+	}
+}
+
+func TestProductTypeService_UpdateProductType_NoFields(t *testing.T) {
+	s := producttype.NewProductTypeService(&MockProductTypeRepository{})
+
+	if _, err := s.UpdateProductType(context.Background(), "обувь", dto.ProductTypeUpdateRequest{}); err == nil {
+		t.Error("expected error for empty update")
+	}
+}
+
+func TestProductTypeService_UpdateProductType_StorageDays(t *testing.T) {
+	tests := []struct {
+		name       string
+		body       string
+		wantErr    bool
+		wantCalled bool
+		wantDays   *int
+	}{
+		{name: "Clear", body: `{"storageDays": null}`, wantCalled: true},
+		{name: "Set", body: `{"storageDays": 30}`, wantCalled: true, wantDays: intPtr(30)},
+		{name: "Out of range", body: `{"storageDays": 0}`, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var req dto.ProductTypeUpdateRequest
+			if err := json.Unmarshal([]byte(tt.body), &req); err != nil {
+				t.Fatalf("failed to decode request: %v", err)
+			}
+
+			called := false
+			s := producttype.NewProductTypeService(&MockProductTypeRepository{
+				UpdateProductTypeFunc: func(ctx context.Context, code string, req dto.ProductTypeUpdateRequest) (*model.ProductType, error) {
+					called = true
+					if !req.StorageDays.Set || !reflect.DeepEqual(req.StorageDays.Value, tt.wantDays) {
+						t.Errorf("unexpected storage days: %+v", req.StorageDays)
+					}
+					return &model.ProductType{Code: code, StorageDays: req.StorageDays.Value}, nil
+				},
+			})
+
+			_, err := s.UpdateProductType(context.Background(), "обувь", req)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("UpdateProductType() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if called != tt.wantCalled {
+				t.Errorf("repository called = %v, want %v", called, tt.wantCalled)
+			}
+		})
+	}
+}
+
+func intPtr(v int) *int {
+	return &v
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"reflect"
+	"strings"
 	"testing"
 	"time"
 
@@ -22,6 +23,12 @@ type MockPVZRepository struct {
 	CreatePVZFunc  func(ctx context.Context, req dto.PVZCreateRequest) (*model.PVZ, error)
 	GetPVZListFunc func(ctx context.Context, filter dto.PVZFilterQuery) ([]model.PVZ, error)
 	GetPVZByIDFunc func(ctx context.Context, pvzID string) (*model.PVZ, error)
+	UpdatePVZFunc  func(ctx context.Context, pvzID string, req dto.PVZUpdateRequest) (*model.PVZ, error)
+
+	GetNearbyPVZListFunc func(ctx context.Context, query dto.PVZNearbyQuery) ([]model.NearbyPVZ, error)
+	GetPVZForUpdateFunc  func(ctx context.Context, pvzID string) (*model.PVZ, error)
+	UpdatePVZStatusFunc  func(ctx context.Context, pvzID string, status model.PVZStatus) (*model.PVZ, error)
+	StreamPVZListFunc    func(ctx context.Context, filter dto.PVZFilterQuery, fn func(model.PVZ, *model.Reception, *model.Product) error) error
 }
 
 func (m *MockPVZRepository) CreatePVZ(ctx context.Context, req dto.PVZCreateRequest) (*model.PVZ, error) {
@@ -36,12 +43,35 @@ func (m *MockPVZRepository) GetPVZByID(ctx context.Context, pvzID string) (*mode
 	return m.GetPVZByIDFunc(ctx, pvzID)
 }
 
+func (m *MockPVZRepository) UpdatePVZ(ctx context.Context, pvzID string, req dto.PVZUpdateRequest) (*model.PVZ, error) {
+	return m.UpdatePVZFunc(ctx, pvzID, req)
+}
+
+func (m *MockPVZRepository) GetNearbyPVZList(ctx context.Context, query dto.PVZNearbyQuery) ([]model.NearbyPVZ, error) {
+	return m.GetNearbyPVZListFunc(ctx, query)
+}
+
+func (m *MockPVZRepository) GetPVZForUpdate(ctx context.Context, pvzID string) (*model.PVZ, error) {
+	return m.GetPVZForUpdateFunc(ctx, pvzID)
+}
+
+func (m *MockPVZRepository) UpdatePVZStatus(ctx context.Context, pvzID string, status model.PVZStatus) (*model.PVZ, error) {
+	return m.UpdatePVZStatusFunc(ctx, pvzID, status)
+}
+
+func (m *MockPVZRepository) StreamPVZList(ctx context.Context, filter dto.PVZFilterQuery, fn func(model.PVZ, *model.Reception, *model.Product) error) error {
+	return m.StreamPVZListFunc(ctx, filter, fn)
+}
+
 type MockReceptionRepository struct {
 	CreateReceptionFunc      func(ctx context.Context, req dto.ReceptionCreateRequest) (*model.Reception, error)
 	HasOpenReceptionFunc     func(ctx context.Context, pvzID string) (bool, error)
 	GetLastOpenReceptionFunc func(ctx context.Context, pvzID string) (*model.Reception, error)
+	GetReceptionByIDFunc     func(ctx context.Context, receptionID string) (*model.Reception, error)
 	CloseReceptionFunc       func(ctx context.Context, receptionID string) (*model.Reception, error)
-	GetReceptionsByPVZIDFunc func(ctx context.Context, pvzID string, startDate, endDate *time.Time) ([]model.Reception, error)
+	GetReceptionsByPVZIDFunc func(ctx context.Context, pvzID string, filter dto.ReceptionFilter) ([]model.Reception, error)
+
+	GetLastOpenReceptionForUpdateFunc func(ctx context.Context, pvzID string) (*model.Reception, error)
 }
 
 func (m *MockReceptionRepository) CreateReception(ctx context.Context, req dto.ReceptionCreateRequest) (*model.Reception, error) {
@@ -56,23 +86,65 @@ func (m *MockReceptionRepository) GetLastOpenReception(ctx context.Context, pvzI
 	return m.GetLastOpenReceptionFunc(ctx, pvzID)
 }
 
+func (m *MockReceptionRepository) GetLastOpenReceptionForUpdate(ctx context.Context, pvzID string) (*model.Reception, error) {
+	return m.GetLastOpenReceptionForUpdateFunc(ctx, pvzID)
+}
+
+func (m *MockReceptionRepository) GetReceptionByID(ctx context.Context, receptionID string) (*model.Reception, error) {
+	return m.GetReceptionByIDFunc(ctx, receptionID)
+}
+
 func (m *MockReceptionRepository) CloseReception(ctx context.Context, pvzID string) (*model.Reception, error) {
 	return m.CloseReceptionFunc(ctx, pvzID)
 }
 
-func (m *MockReceptionRepository) GetReceptionsByPVZID(ctx context.Context, pvzID string, startDate, endDate *time.Time) ([]model.Reception, error) {
-	return m.GetReceptionsByPVZIDFunc(ctx, pvzID, startDate, endDate)
+func (m *MockReceptionRepository) GetReceptionForUpdate(ctx context.Context, receptionID string) (*model.Reception, error) {
+	return nil, nil
+}
+
+func (m *MockReceptionRepository) HasNewerReception(ctx context.Context, reception *model.Reception) (bool, error) {
+	return false, nil
+}
+
+func (m *MockReceptionRepository) HasProcessedProducts(ctx context.Context, receptionID string) (bool, error) {
+	return false, nil
+}
+
+func (m *MockReceptionRepository) ReopenReception(ctx context.Context, receptionID string) (*model.Reception, error) {
+	return nil, nil
+}
+
+func (m *MockReceptionRepository) GetReceptionsByPVZID(ctx context.Context, pvzID string, filter dto.ReceptionFilter) ([]model.Reception, error) {
+	return m.GetReceptionsByPVZIDFunc(ctx, pvzID, filter)
+}
+
+func (m *MockReceptionRepository) SetExpectedCount(ctx context.Context, receptionID string, expectedCount int) (*model.Reception, error) {
+	return nil, nil
+}
+
+func (m *MockReceptionRepository) GetReceptionDiscrepancy(ctx context.Context, receptionID string) (*model.ReceptionDiscrepancy, error) {
+	return nil, nil
+}
+
+func (m *MockReceptionRepository) GetReceptionDiscrepancies(
+	ctx context.Context,
+	filter dto.DiscrepancyFilterQuery,
+) ([]model.ReceptionDiscrepancy, error) {
+	return nil, nil
 }
 
 type MockProductRepository struct {
-	CreateProductFunc             func(ctx context.Context, productType string, receptionID string) (*model.Product, error)
+	CreateProductFunc             func(ctx context.Context, receptionID string, attrs dto.ProductAttributes) (*model.Product, error)
 	GetLastProductInReceptionFunc func(ctx context.Context, receptionID string) (*model.Product, error)
 	DeleteProductFunc             func(ctx context.Context, productID string) error
 	GetProductsByReceptionIDFunc  func(ctx context.Context, receptionID string) ([]model.Product, error)
+
+	CreateProductsFunc        func(ctx context.Context, receptionID string, items []dto.ProductAttributes) ([]model.Product, error)
+	GetProductsByBarcodesFunc func(ctx context.Context, barcodes []string) ([]model.ProductLocation, error)
 }
 
-func (m *MockProductRepository) CreateProduct(ctx context.Context, productType string, receptionID string) (*model.Product, error) {
-	return m.CreateProductFunc(ctx, productType, receptionID)
+func (m *MockProductRepository) CreateProduct(ctx context.Context, receptionID string, attrs dto.ProductAttributes) (*model.Product, error) {
+	return m.CreateProductFunc(ctx, receptionID, attrs)
 }
 func (m *MockProductRepository) GetLastProductInReception(ctx context.Context, receptionID string) (*model.Product, error) {
 	return m.GetLastProductInReceptionFunc(ctx, receptionID)
@@ -84,8 +156,108 @@ func (m *MockProductRepository) GetProductsByReceptionID(ctx context.Context, re
 	return m.GetProductsByReceptionIDFunc(ctx, receptionID)
 }
 
+func (m *MockProductRepository) GetProductsByReceptionIDInInsertionOrder(ctx context.Context, receptionID string) ([]model.Product, error) {
+	return nil, nil
+}
+
+func (m *MockProductRepository) CreateProducts(ctx context.Context, receptionID string, items []dto.ProductAttributes) ([]model.Product, error) {
+	return m.CreateProductsFunc(ctx, receptionID, items)
+}
+
+func (m *MockProductRepository) GetProductsByBarcodes(ctx context.Context, barcodes []string) ([]model.ProductLocation, error) {
+	return m.GetProductsByBarcodesFunc(ctx, barcodes)
+}
+
+func (m *MockProductRepository) GetProductLocation(ctx context.Context, productID string) (*model.ProductLocation, error) {
+	return nil, nil
+}
+
+func (m *MockProductRepository) GetProductLocationForUpdate(ctx context.Context, productID string) (*model.ProductLocation, error) {
+	return nil, nil
+}
+
+func (m *MockProductRepository) UpdateProductStatus(ctx context.Context, productID string, status model.ProductStatus) (*model.Product, error) {
+	return nil, nil
+}
+
+func (m *MockProductRepository) UpdateProductCell(ctx context.Context, productID, cellID string) (*model.Product, error) {
+	return nil, nil
+}
+
+func (m *MockProductRepository) UpdateProductCondition(
+	ctx context.Context,
+	productID string,
+	condition model.ProductCondition,
+	note string,
+) (*model.Product, error) {
+	return nil, nil
+}
+
+type MockTransactor struct{}
+
+func (m *MockTransactor) WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	return fn(ctx)
+}
+
+type MockAuditService struct {
+	RecordFunc func(ctx context.Context, action model.AuditAction, entityType model.AuditEntityType, entityID string, before, after any) error
+}
+
+func (m *MockAuditService) Record(
+	ctx context.Context,
+	action model.AuditAction,
+	entityType model.AuditEntityType,
+	entityID string,
+	before, after any,
+) error {
+	if m.RecordFunc == nil {
+		return nil
+	}
+	return m.RecordFunc(ctx, action, entityType, entityID, before, after)
+}
+
+func (m *MockAuditService) GetAuditLog(ctx context.Context, filter dto.AuditFilterQuery) ([]model.AuditEntry, error) {
+	return nil, nil
+}
+
+type MockCityService struct {
+	Cities map[string]struct{}
+}
+
+func newMockCityService() *MockCityService {
+	return &MockCityService{
+		Cities: map[string]struct{}{"Москва": {}, "Санкт-Петербург": {}, "Казань": {}},
+	}
+}
+
+func (m *MockCityService) CreateCity(ctx context.Context, req dto.CityCreateRequest) (*model.City, error) {
+	return nil, nil
+}
+
+func (m *MockCityService) GetCityList(ctx context.Context) ([]model.City, error) {
+	return nil, nil
+}
+
+func (m *MockCityService) GetCityByID(ctx context.Context, cityID string) (*model.City, error) {
+	return nil, nil
+}
+
+func (m *MockCityService) UpdateCity(ctx context.Context, cityID string, req dto.CityUpdateRequest) (*model.City, error) {
+	return nil, nil
+}
+
+func (m *MockCityService) DeleteCity(ctx context.Context, cityID string) error {
+	return nil
+}
+
+func (m *MockCityService) IsCityAvailable(ctx context.Context, name string) (bool, error) {
+	_, ok := m.Cities[name]
+	return ok, nil
+}
+
 func TestPVZService_CreatePVZ(t *testing.T) {
 	now := time.Now()
+	latitude := 55.7575
 
 	tests := []struct {
 		name          string
@@ -141,11 +313,7 @@ func TestPVZService_CreatePVZ(t *testing.T) {
 		{
 			name: "Invalid City",
 			mockRepos: &MockRepositories{
-				MockPVZRepository: &MockPVZRepository{
-					CreatePVZFunc: func(ctx context.Context, req dto.PVZCreateRequest) (*model.PVZ, error) {
-						return nil, errors.New("invalid city")
-					},
-				},
+				MockPVZRepository:       &MockPVZRepository{},
 				MockReceptionRepository: &MockReceptionRepository{},
 				MockProductRepository:   &MockProductRepository{},
 			},
@@ -156,6 +324,38 @@ func TestPVZService_CreatePVZ(t *testing.T) {
 			expected:      nil,
 			expectedError: true,
 		},
+		{
+			name: "Latitude Without Longitude",
+			mockRepos: &MockRepositories{
+				MockPVZRepository:       &MockPVZRepository{},
+				MockReceptionRepository: &MockReceptionRepository{},
+				MockProductRepository:   &MockProductRepository{},
+			},
+			input: dto.PVZCreateRequest{
+				RegistrationDate: now,
+				City:             "Москва",
+				Latitude:         &latitude,
+			},
+			expected:      nil,
+			expectedError: true,
+		},
+		{
+			name: "Invalid Working Hours",
+			mockRepos: &MockRepositories{
+				MockPVZRepository:       &MockPVZRepository{},
+				MockReceptionRepository: &MockReceptionRepository{},
+				MockProductRepository:   &MockProductRepository{},
+			},
+			input: dto.PVZCreateRequest{
+				RegistrationDate: now,
+				City:             "Москва",
+				WorkingHours: &model.WorkingHours{
+					Weekly: []model.DailyHours{{Day: "monday", Open: "21:00", Close: "09:00"}},
+				},
+			},
+			expected:      nil,
+			expectedError: true,
+		},
 	}
 
 	for ttNum, tt := range tests {
@@ -164,6 +364,9 @@ func TestPVZService_CreatePVZ(t *testing.T) {
 				tt.mockRepos.MockPVZRepository,
 				tt.mockRepos.MockReceptionRepository,
 				tt.mockRepos.MockProductRepository,
+				&MockTransactor{},
+				&MockAuditService{},
+				newMockCityService(),
 			)
 			got, err := s.CreatePVZ(context.Background(), tt.input)
 
@@ -178,6 +381,139 @@ func TestPVZService_CreatePVZ(t *testing.T) {
 	}
 }
 
+func TestPVZService_UpdatePVZ(t *testing.T) {
+	now := time.Now()
+	address := "ул. Тверская, 1"
+	latitude, longitude := 55.7575, 37.6136
+
+	existing := &model.PVZ{
+		ID:               "123e4567-e89b-12d3-a456-426614174000",
+		RegistrationDate: now,
+		City:             "Москва",
+		Status:           model.PVZStatusActive,
+	}
+
+	tests := []struct {
+		name          string
+		mockRepo      *MockPVZRepository
+		input         dto.PVZUpdateRequest
+		expected      *model.PVZ
+		expectedError error
+	}{
+		{
+			name: "Success",
+			mockRepo: &MockPVZRepository{
+				GetPVZForUpdateFunc: func(ctx context.Context, pvzID string) (*model.PVZ, error) {
+					return existing, nil
+				},
+				UpdatePVZFunc: func(ctx context.Context, pvzID string, req dto.PVZUpdateRequest) (*model.PVZ, error) {
+					return &model.PVZ{
+						ID:               pvzID,
+						RegistrationDate: now,
+						City:             "Москва",
+						Address:          *req.Address,
+						Latitude:         req.Latitude,
+						Longitude:        req.Longitude,
+					}, nil
+				},
+			},
+			input: dto.PVZUpdateRequest{
+				Address:   &address,
+				Latitude:  &latitude,
+				Longitude: &longitude,
+			},
+			expected: &model.PVZ{
+				ID:               "123e4567-e89b-12d3-a456-426614174000",
+				RegistrationDate: now,
+				City:             "Москва",
+				Address:          address,
+				Latitude:         &latitude,
+				Longitude:        &longitude,
+			},
+			expectedError: nil,
+		},
+		{
+			name:          "No Fields",
+			mockRepo:      &MockPVZRepository{},
+			input:         dto.PVZUpdateRequest{},
+			expected:      nil,
+			expectedError: errors.New("no fields to update"),
+		},
+		{
+			name:     "Longitude Without Latitude",
+			mockRepo: &MockPVZRepository{},
+			input: dto.PVZUpdateRequest{
+				Longitude: &longitude,
+			},
+			expected:      nil,
+			expectedError: errors.New("latitude and longitude must be set together"),
+		},
+		{
+			name: "Archived PVZ",
+			mockRepo: &MockPVZRepository{
+				GetPVZForUpdateFunc: func(ctx context.Context, pvzID string) (*model.PVZ, error) {
+					return &model.PVZ{ID: pvzID, City: "Москва", Status: model.PVZStatusArchived}, nil
+				},
+			},
+			input: dto.PVZUpdateRequest{
+				Address: &address,
+			},
+			expected:      nil,
+			expectedError: errors.New("archived PVZ cannot be modified"),
+		},
+		{
+			name: "PVZ Not Found",
+			mockRepo: &MockPVZRepository{
+				GetPVZForUpdateFunc: func(ctx context.Context, pvzID string) (*model.PVZ, error) {
+					return nil, model.ErrPVZNotFound
+				},
+			},
+			input: dto.PVZUpdateRequest{
+				Address: &address,
+			},
+			expected:      nil,
+			expectedError: model.ErrPVZNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var recorded model.AuditAction
+			s := service.NewPVZService(
+				tt.mockRepo,
+				&MockReceptionRepository{},
+				&MockProductRepository{},
+				&MockTransactor{},
+				&MockAuditService{
+					RecordFunc: func(ctx context.Context, action model.AuditAction, entityType model.AuditEntityType, entityID string, before, after any) error {
+						recorded = action
+						return nil
+					},
+				},
+				newMockCityService(),
+			)
+			got, err := s.UpdatePVZ(context.Background(), "123e4567-e89b-12d3-a456-426614174000", tt.input)
+
+			if tt.expectedError != nil {
+				if err == nil || !strings.Contains(err.Error(), tt.expectedError.Error()) {
+					t.Errorf("PVZService.UpdatePVZ() error = %v, expectedError %v", err, tt.expectedError)
+				}
+				return
+			}
+			if err != nil {
+				t.Errorf("PVZService.UpdatePVZ() unexpected error = %v", err)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("PVZService.UpdatePVZ() = %v, expected %v", got, tt.expected)
+			}
+			if recorded != model.AuditActionUpdate {
+				t.Errorf("PVZService.UpdatePVZ() recorded action = %v, expected %v", recorded, model.AuditActionUpdate)
+			}
+		})
+	}
+}
+
 func TestPVZService_GetPVZList(t *testing.T) {
 	now := time.Now()
 	startDate := now.Add(-24 * time.Hour)
@@ -239,7 +575,10 @@ func TestPVZService_GetPVZList(t *testing.T) {
 					},
 				},
 				MockReceptionRepository: &MockReceptionRepository{
-					GetReceptionsByPVZIDFunc: func(ctx context.Context, pvzID string, startDate, endDate *time.Time) ([]model.Reception, error) {
+					GetReceptionsByPVZIDFunc: func(ctx context.Context, pvzID string, filter dto.ReceptionFilter) ([]model.Reception, error) {
+						if filter.StartDate != &startDate || !filter.InProgress || filter.ProductType != "электроника" {
+							return nil, errors.New("reception filter not propagated")
+						}
 						if pvzID == "pvz-id-1" {
 							return []model.Reception{reception1}, nil
 						}
@@ -256,10 +595,12 @@ func TestPVZService_GetPVZList(t *testing.T) {
 				},
 			},
 			filter: dto.PVZFilterQuery{
-				Page:      1,
-				Limit:     10,
-				StartDate: &startDate,
-				EndDate:   &endDate,
+				Page:             1,
+				Limit:            10,
+				StartDate:        &startDate,
+				EndDate:          &endDate,
+				HasOpenReception: true,
+				ProductType:      "электроника",
 			},
 			expected: &dto.PaginatedResponse{
 				Data: []dto.PVZWithReceptionsResponse{
@@ -294,7 +635,7 @@ func TestPVZService_GetPVZList(t *testing.T) {
 					},
 				},
 				MockReceptionRepository: &MockReceptionRepository{
-					GetReceptionsByPVZIDFunc: func(ctx context.Context, pvzID string, startDate, endDate *time.Time) ([]model.Reception, error) {
+					GetReceptionsByPVZIDFunc: func(ctx context.Context, pvzID string, filter dto.ReceptionFilter) ([]model.Reception, error) {
 						return []model.Reception{reception1}, nil
 					},
 				},
@@ -370,7 +711,7 @@ func TestPVZService_GetPVZList(t *testing.T) {
 					},
 				},
 				MockReceptionRepository: &MockReceptionRepository{
-					GetReceptionsByPVZIDFunc: func(ctx context.Context, pvzID string, startDate, endDate *time.Time) ([]model.Reception, error) {
+					GetReceptionsByPVZIDFunc: func(ctx context.Context, pvzID string, filter dto.ReceptionFilter) ([]model.Reception, error) {
 						return nil, errors.New("reception repository error")
 					},
 				},
@@ -392,7 +733,7 @@ func TestPVZService_GetPVZList(t *testing.T) {
 					},
 				},
 				MockReceptionRepository: &MockReceptionRepository{
-					GetReceptionsByPVZIDFunc: func(ctx context.Context, pvzID string, startDate, endDate *time.Time) ([]model.Reception, error) {
+					GetReceptionsByPVZIDFunc: func(ctx context.Context, pvzID string, filter dto.ReceptionFilter) ([]model.Reception, error) {
 						return []model.Reception{reception1}, nil
 					},
 				},
@@ -418,7 +759,7 @@ func TestPVZService_GetPVZList(t *testing.T) {
 					},
 				},
 				MockReceptionRepository: &MockReceptionRepository{
-					GetReceptionsByPVZIDFunc: func(ctx context.Context, pvzID string, startDate, endDate *time.Time) ([]model.Reception, error) {
+					GetReceptionsByPVZIDFunc: func(ctx context.Context, pvzID string, filter dto.ReceptionFilter) ([]model.Reception, error) {
 						return []model.Reception{}, nil
 					},
 				},
@@ -447,7 +788,7 @@ func TestPVZService_GetPVZList(t *testing.T) {
 					},
 				},
 				MockReceptionRepository: &MockReceptionRepository{
-					GetReceptionsByPVZIDFunc: func(ctx context.Context, pvzID string, startDate, endDate *time.Time) ([]model.Reception, error) {
+					GetReceptionsByPVZIDFunc: func(ctx context.Context, pvzID string, filter dto.ReceptionFilter) ([]model.Reception, error) {
 						return []model.Reception{reception1}, nil
 					},
 				},
@@ -489,7 +830,7 @@ func TestPVZService_GetPVZList(t *testing.T) {
 					},
 				},
 				MockReceptionRepository: &MockReceptionRepository{
-					GetReceptionsByPVZIDFunc: func(ctx context.Context, pvzID string, startDate, endDate *time.Time) ([]model.Reception, error) {
+					GetReceptionsByPVZIDFunc: func(ctx context.Context, pvzID string, filter dto.ReceptionFilter) ([]model.Reception, error) {
 						return []model.Reception{reception2}, nil
 					},
 				},
@@ -526,6 +867,9 @@ func TestPVZService_GetPVZList(t *testing.T) {
 				tt.mockRepos.MockPVZRepository,
 				tt.mockRepos.MockReceptionRepository,
 				tt.mockRepos.MockProductRepository,
+				&MockTransactor{},
+				&MockAuditService{},
+				newMockCityService(),
 			)
 			got, err := s.GetPVZList(context.Background(), tt.filter)
 
@@ -542,3 +886,484 @@ func TestPVZService_GetPVZList(t *testing.T) {
 		})
 	}
 }
+
+func TestPVZService_StreamPVZList(t *testing.T) {
+	now := time.Now()
+
+	pvz1 := model.PVZ{ID: "pvz-id-1", RegistrationDate: now, City: "Москва"}
+	pvz2 := model.PVZ{ID: "pvz-id-2", RegistrationDate: now.Add(-1 * time.Hour), City: "Казань"}
+
+	reception1 := model.Reception{ID: "reception-id-1", DateTime: now, PVZID: pvz1.ID, Status: "in_progress"}
+	reception2 := model.Reception{ID: "reception-id-2", DateTime: now.Add(-24 * time.Hour), PVZID: pvz1.ID, Status: "close"}
+
+	product1 := model.Product{ID: "product-id-1", DateTime: now, Type: "электроника", ReceptionID: reception1.ID}
+	product2 := model.Product{ID: "product-id-2", DateTime: now, Type: "обувь", ReceptionID: reception1.ID}
+
+	tests := []struct {
+		name          string
+		streamFunc    func(ctx context.Context, filter dto.PVZFilterQuery, fn func(model.PVZ, *model.Reception, *model.Product) error) error
+		fnError       error
+		expected      []dto.PVZWithReceptionsResponse
+		expectedError bool
+	}{
+		{
+			name: "Groups Rows",
+			streamFunc: func(ctx context.Context, filter dto.PVZFilterQuery, fn func(model.PVZ, *model.Reception, *model.Product) error) error {
+				rows := []struct {
+					pvz       model.PVZ
+					reception *model.Reception
+					product   *model.Product
+				}{
+					{pvz1, &reception1, &product1},
+					{pvz1, &reception1, &product2},
+					{pvz1, &reception2, nil},
+					{pvz2, nil, nil},
+				}
+				for _, row := range rows {
+					if err := fn(row.pvz, row.reception, row.product); err != nil {
+						return err
+					}
+				}
+				return nil
+			},
+			expected: []dto.PVZWithReceptionsResponse{
+				{
+					PVZ: pvz1,
+					Receptions: []dto.ReceptionWithProductsResponse{
+						{Reception: reception1, Products: []model.Product{product1, product2}},
+						{Reception: reception2, Products: []model.Product{}},
+					},
+				},
+				{
+					PVZ:        pvz2,
+					Receptions: []dto.ReceptionWithProductsResponse{},
+				},
+			},
+		},
+		{
+			name: "No Rows",
+			streamFunc: func(ctx context.Context, filter dto.PVZFilterQuery, fn func(model.PVZ, *model.Reception, *model.Product) error) error {
+				return nil
+			},
+			expected: []dto.PVZWithReceptionsResponse{},
+		},
+		{
+			name: "Repository Error",
+			streamFunc: func(ctx context.Context, filter dto.PVZFilterQuery, fn func(model.PVZ, *model.Reception, *model.Product) error) error {
+				return errors.New("database error")
+			},
+			expected:      []dto.PVZWithReceptionsResponse{},
+			expectedError: true,
+		},
+		{
+			name: "Callback Error Stops Stream",
+			streamFunc: func(ctx context.Context, filter dto.PVZFilterQuery, fn func(model.PVZ, *model.Reception, *model.Product) error) error {
+				if err := fn(pvz1, nil, nil); err != nil {
+					return err
+				}
+				return fn(pvz2, nil, nil)
+			},
+			fnError:       errors.New("client disconnected"),
+			expected:      []dto.PVZWithReceptionsResponse{{PVZ: pvz1, Receptions: []dto.ReceptionWithProductsResponse{}}},
+			expectedError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := service.NewPVZService(
+				&MockPVZRepository{StreamPVZListFunc: tt.streamFunc},
+				&MockReceptionRepository{},
+				&MockProductRepository{},
+				&MockTransactor{},
+				&MockAuditService{},
+				newMockCityService(),
+			)
+
+			got := []dto.PVZWithReceptionsResponse{}
+			err := s.StreamPVZList(context.Background(), dto.PVZFilterQuery{}, func(pvz dto.PVZWithReceptionsResponse) error {
+				got = append(got, pvz)
+				return tt.fnError
+			})
+
+			if (err != nil) != tt.expectedError {
+				t.Errorf("PVZService.StreamPVZList() error = %v, expectedError %v", err, tt.expectedError)
+				return
+			}
+
+			if !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("PVZService.StreamPVZList() = %v, expected %v", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestPVZService_GetNearbyPVZList(t *testing.T) {
+	latitude, longitude := 55.7575, 37.6136
+
+	aroundTheClock := &model.WorkingHours{}
+	for _, day := range []string{"monday", "tuesday", "wednesday", "thursday", "friday", "saturday", "sunday"} {
+		aroundTheClock.Weekly = append(aroundTheClock.Weekly, model.DailyHours{Day: day, Open: "00:00", Close: "24:00"})
+	}
+
+	open1 := model.NearbyPVZ{
+		PVZ:      model.PVZ{ID: "pvz-id-1", City: "Москва", WorkingHours: aroundTheClock},
+		Distance: 100,
+		Timezone: "Europe/Moscow",
+	}
+	withoutHours := model.NearbyPVZ{
+		PVZ:      model.PVZ{ID: "pvz-id-2", City: "Москва"},
+		Distance: 200,
+		Timezone: "Europe/Moscow",
+	}
+	open2 := model.NearbyPVZ{
+		PVZ:      model.PVZ{ID: "pvz-id-3", City: "Москва", WorkingHours: aroundTheClock},
+		Distance: 300,
+		Timezone: "Europe/Moscow",
+	}
+	withoutCity := model.NearbyPVZ{
+		PVZ:      model.PVZ{ID: "pvz-id-4", City: "Атлантида", WorkingHours: aroundTheClock},
+		Distance: 400,
+	}
+
+	tests := []struct {
+		name          string
+		mockRepo      *MockPVZRepository
+		query         dto.PVZNearbyQuery
+		expected      []model.NearbyPVZ
+		expectedError bool
+	}{
+		{
+			name: "Success",
+			mockRepo: &MockPVZRepository{
+				GetNearbyPVZListFunc: func(ctx context.Context, query dto.PVZNearbyQuery) ([]model.NearbyPVZ, error) {
+					return []model.NearbyPVZ{open1, withoutHours, open2, withoutCity}, nil
+				},
+			},
+			query: dto.PVZNearbyQuery{
+				Latitude:  &latitude,
+				Longitude: &longitude,
+				Radius:    5000,
+				Limit:     10,
+			},
+			expected:      []model.NearbyPVZ{open1, withoutHours, open2, withoutCity},
+			expectedError: false,
+		},
+		{
+			name: "Open Now",
+			mockRepo: &MockPVZRepository{
+				GetNearbyPVZListFunc: func(ctx context.Context, query dto.PVZNearbyQuery) ([]model.NearbyPVZ, error) {
+					return []model.NearbyPVZ{open1, withoutHours, open2, withoutCity}, nil
+				},
+			},
+			query: dto.PVZNearbyQuery{
+				Latitude:  &latitude,
+				Longitude: &longitude,
+				Radius:    5000,
+				Limit:     10,
+				OpenNow:   true,
+			},
+			expected:      []model.NearbyPVZ{open1, open2},
+			expectedError: false,
+		},
+		{
+			name: "Open Now With Limit",
+			mockRepo: &MockPVZRepository{
+				GetNearbyPVZListFunc: func(ctx context.Context, query dto.PVZNearbyQuery) ([]model.NearbyPVZ, error) {
+					return []model.NearbyPVZ{open1, withoutHours, open2}, nil
+				},
+			},
+			query: dto.PVZNearbyQuery{
+				Latitude:  &latitude,
+				Longitude: &longitude,
+				Radius:    5000,
+				Limit:     1,
+				OpenNow:   true,
+			},
+			expected:      []model.NearbyPVZ{open1},
+			expectedError: false,
+		},
+		{
+			name: "Repository Error",
+			mockRepo: &MockPVZRepository{
+				GetNearbyPVZListFunc: func(ctx context.Context, query dto.PVZNearbyQuery) ([]model.NearbyPVZ, error) {
+					return nil, errors.New("repository error")
+				},
+			},
+			query: dto.PVZNearbyQuery{
+				Latitude:  &latitude,
+				Longitude: &longitude,
+				Radius:    5000,
+				Limit:     10,
+			},
+			expected:      nil,
+			expectedError: true,
+		},
+	}
+
+	for ttNum, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := service.NewPVZService(
+				tt.mockRepo,
+				&MockReceptionRepository{},
+				&MockProductRepository{},
+				&MockTransactor{},
+				&MockAuditService{},
+				newMockCityService(),
+			)
+			got, err := s.GetNearbyPVZList(context.Background(), tt.query)
+
+			if (err != nil) != tt.expectedError {
+				t.Errorf("Test %v: PVZService.GetNearbyPVZList() error = %v, expectedError %v", ttNum, err, tt.expectedError)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("Test %v: PVZService.GetNearbyPVZList() = %v, expected %v", ttNum, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestPVZService_ChangePVZStatus(t *testing.T) {
+	pvzWithStatus := func(status model.PVZStatus) func(ctx context.Context, pvzID string) (*model.PVZ, error) {
+		return func(ctx context.Context, pvzID string) (*model.PVZ, error) {
+			return &model.PVZ{ID: pvzID, City: "Москва", Status: status}, nil
+		}
+	}
+	updateStatus := func(ctx context.Context, pvzID string, status model.PVZStatus) (*model.PVZ, error) {
+		return &model.PVZ{ID: pvzID, City: "Москва", Status: status}, nil
+	}
+
+	tests := []struct {
+		name          string
+		mockRepo      *MockPVZRepository
+		receptionRepo *MockReceptionRepository
+		status        model.PVZStatus
+		expected      *model.PVZ
+		expectedError error
+	}{
+		{
+			name: "Suspend Active",
+			mockRepo: &MockPVZRepository{
+				GetPVZForUpdateFunc: pvzWithStatus(model.PVZStatusActive),
+				UpdatePVZStatusFunc: updateStatus,
+			},
+			receptionRepo: &MockReceptionRepository{},
+			status:        model.PVZStatusSuspended,
+			expected:      &model.PVZ{ID: "pvz-id-1", City: "Москва", Status: model.PVZStatusSuspended},
+			expectedError: nil,
+		},
+		{
+			name: "Reopen Suspended",
+			mockRepo: &MockPVZRepository{
+				GetPVZForUpdateFunc: pvzWithStatus(model.PVZStatusSuspended),
+				UpdatePVZStatusFunc: updateStatus,
+			},
+			receptionRepo: &MockReceptionRepository{},
+			status:        model.PVZStatusActive,
+			expected:      &model.PVZ{ID: "pvz-id-1", City: "Москва", Status: model.PVZStatusActive},
+			expectedError: nil,
+		},
+		{
+			name: "Archive Without Open Reception",
+			mockRepo: &MockPVZRepository{
+				GetPVZForUpdateFunc: pvzWithStatus(model.PVZStatusSuspended),
+				UpdatePVZStatusFunc: updateStatus,
+			},
+			receptionRepo: &MockReceptionRepository{
+				HasOpenReceptionFunc: func(ctx context.Context, pvzID string) (bool, error) {
+					return false, nil
+				},
+			},
+			status:        model.PVZStatusArchived,
+			expected:      &model.PVZ{ID: "pvz-id-1", City: "Москва", Status: model.PVZStatusArchived},
+			expectedError: nil,
+		},
+		{
+			name: "Archive With Open Reception",
+			mockRepo: &MockPVZRepository{
+				GetPVZForUpdateFunc: pvzWithStatus(model.PVZStatusActive),
+			},
+			receptionRepo: &MockReceptionRepository{
+				HasOpenReceptionFunc: func(ctx context.Context, pvzID string) (bool, error) {
+					return true, nil
+				},
+			},
+			status:        model.PVZStatusArchived,
+			expected:      nil,
+			expectedError: errors.New("cannot archive PVZ with a reception in progress"),
+		},
+		{
+			name: "Reopen Archived",
+			mockRepo: &MockPVZRepository{
+				GetPVZForUpdateFunc: pvzWithStatus(model.PVZStatusArchived),
+			},
+			receptionRepo: &MockReceptionRepository{},
+			status:        model.PVZStatusActive,
+			expected:      nil,
+			expectedError: errors.New("cannot change PVZ status from archived to active"),
+		},
+		{
+			name: "Same Status",
+			mockRepo: &MockPVZRepository{
+				GetPVZForUpdateFunc: pvzWithStatus(model.PVZStatusSuspended),
+			},
+			receptionRepo: &MockReceptionRepository{},
+			status:        model.PVZStatusSuspended,
+			expected:      nil,
+			expectedError: errors.New("pvz is already suspended"),
+		},
+		{
+			name: "PVZ Not Found",
+			mockRepo: &MockPVZRepository{
+				GetPVZForUpdateFunc: func(ctx context.Context, pvzID string) (*model.PVZ, error) {
+					return nil, model.ErrPVZNotFound
+				},
+			},
+			receptionRepo: &MockReceptionRepository{},
+			status:        model.PVZStatusSuspended,
+			expected:      nil,
+			expectedError: model.ErrPVZNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var recorded model.AuditAction
+			s := service.NewPVZService(
+				tt.mockRepo,
+				tt.receptionRepo,
+				&MockProductRepository{},
+				&MockTransactor{},
+				&MockAuditService{
+					RecordFunc: func(ctx context.Context, action model.AuditAction, entityType model.AuditEntityType, entityID string, before, after any) error {
+						recorded = action
+						return nil
+					},
+				},
+				newMockCityService(),
+			)
+			got, err := s.ChangePVZStatus(context.Background(), "pvz-id-1", tt.status)
+
+			if tt.expectedError != nil {
+				if err == nil || !strings.Contains(err.Error(), tt.expectedError.Error()) {
+					t.Errorf("PVZService.ChangePVZStatus() error = %v, expectedError %v", err, tt.expectedError)
+				}
+				return
+			}
+			if err != nil {
+				t.Errorf("PVZService.ChangePVZStatus() unexpected error = %v", err)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("PVZService.ChangePVZStatus() = %v, expected %v", got, tt.expected)
+			}
+			if recorded != model.AuditActionStatusChange {
+				t.Errorf("PVZService.ChangePVZStatus() recorded action = %v, expected %v", recorded, model.AuditActionStatusChange)
+			}
+		})
+	}
+}
+
+func TestPVZService_GetPVZ(t *testing.T) {
+	now := time.Now()
+
+	pvz := &model.PVZ{
+		ID:               "pvz-id-1",
+		RegistrationDate: now,
+		City:             "Москва",
+		Status:           model.PVZStatusActive,
+	}
+	reception := model.Reception{
+		ID:       "reception-id-1",
+		DateTime: now,
+		PVZID:    pvz.ID,
+		Status:   "in_progress",
+	}
+	product := model.Product{
+		ID:          "product-id-1",
+		DateTime:    now,
+		Type:        "electronics",
+		ReceptionID: reception.ID,
+	}
+
+	mockRepos := &MockRepositories{
+		MockPVZRepository: &MockPVZRepository{
+			GetPVZByIDFunc: func(ctx context.Context, pvzID string) (*model.PVZ, error) {
+				if pvzID != pvz.ID {
+					return nil, model.ErrPVZNotFound
+				}
+				return pvz, nil
+			},
+		},
+		MockReceptionRepository: &MockReceptionRepository{
+			GetReceptionsByPVZIDFunc: func(ctx context.Context, pvzID string, filter dto.ReceptionFilter) ([]model.Reception, error) {
+				return []model.Reception{reception}, nil
+			},
+		},
+		MockProductRepository: &MockProductRepository{
+			GetProductsByReceptionIDFunc: func(ctx context.Context, receptionID string) ([]model.Product, error) {
+				return []model.Product{product}, nil
+			},
+		},
+	}
+
+	tests := []struct {
+		name          string
+		pvzID         string
+		query         dto.PVZDetailsQuery
+		expected      *dto.PVZDetailsResponse
+		expectedError error
+	}{
+		{
+			name:  "Without Receptions",
+			pvzID: pvz.ID,
+			query: dto.PVZDetailsQuery{},
+			expected: &dto.PVZDetailsResponse{
+				PVZ: *pvz,
+			},
+			expectedError: nil,
+		},
+		{
+			name:  "With Receptions",
+			pvzID: pvz.ID,
+			query: dto.PVZDetailsQuery{IncludeReceptions: true},
+			expected: &dto.PVZDetailsResponse{
+				PVZ: *pvz,
+				Receptions: []dto.ReceptionWithProductsResponse{
+					{Reception: reception, Products: []model.Product{product}},
+				},
+			},
+			expectedError: nil,
+		},
+		{
+			name:          "PVZ Not Found",
+			pvzID:         "pvz-id-99",
+			query:         dto.PVZDetailsQuery{},
+			expected:      nil,
+			expectedError: model.ErrPVZNotFound,
+		},
+	}
+
+	for ttNum, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := service.NewPVZService(
+				mockRepos.MockPVZRepository,
+				mockRepos.MockReceptionRepository,
+				mockRepos.MockProductRepository,
+				&MockTransactor{},
+				&MockAuditService{},
+				newMockCityService(),
+			)
+			got, err := s.GetPVZ(context.Background(), tt.pvzID, tt.query)
+
+			if !errors.Is(err, tt.expectedError) {
+				t.Errorf("Test %v: PVZService.GetPVZ() error = %v, expectedError %v", ttNum, err, tt.expectedError)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("Test %v: PVZService.GetPVZ() = %v, expected %v", ttNum, got, tt.expected)
+			}
+		})
+	}
+}
@@ -2,45 +2,166 @@ package pvz
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"time"
 
 	"github.com/kirillidk/pvz-service/internal/dto"
 	"github.com/kirillidk/pvz-service/internal/model"
 	"github.com/kirillidk/pvz-service/internal/repository"
+	"github.com/kirillidk/pvz-service/internal/service/audit"
+	"github.com/kirillidk/pvz-service/internal/service/catalogcache"
+	"github.com/kirillidk/pvz-service/internal/service/city"
 )
 
 type PVZServiceInterface interface {
 	CreatePVZ(ctx context.Context, pvzReq dto.PVZCreateRequest) (*model.PVZ, error)
 	GetPVZList(ctx context.Context, filter dto.PVZFilterQuery) (*dto.PaginatedResponse, error)
+	StreamPVZList(ctx context.Context, filter dto.PVZFilterQuery, fn func(dto.PVZWithReceptionsResponse) error) error
+	GetPVZ(ctx context.Context, pvzID string, query dto.PVZDetailsQuery) (*dto.PVZDetailsResponse, error)
+	UpdatePVZ(ctx context.Context, pvzID string, pvzReq dto.PVZUpdateRequest) (*model.PVZ, error)
+	GetNearbyPVZList(ctx context.Context, query dto.PVZNearbyQuery) ([]model.NearbyPVZ, error)
+	ChangePVZStatus(ctx context.Context, pvzID string, status model.PVZStatus) (*model.PVZ, error)
 }
 
 type PVZService struct {
 	pvzRepository       repository.PVZRepositoryInterface
 	receptionRepository repository.ReceptionRepositoryInterface
 	productRepository   repository.ProductRepositoryInterface
+	transactor          repository.TransactorInterface
+	auditService        audit.AuditServiceInterface
+	cityService         city.CityServiceInterface
+	locations           catalogcache.Locations
 }
 
 func NewPVZService(
 	pvzRepo repository.PVZRepositoryInterface,
 	receptionRepo repository.ReceptionRepositoryInterface,
 	productRepo repository.ProductRepositoryInterface,
+	transactor repository.TransactorInterface,
+	auditService audit.AuditServiceInterface,
+	cityService city.CityServiceInterface,
 ) *PVZService {
 	return &PVZService{
 		pvzRepository:       pvzRepo,
 		receptionRepository: receptionRepo,
 		productRepository:   productRepo,
+		transactor:          transactor,
+		auditService:        auditService,
+		cityService:         cityService,
 	}
 }
 
 func (s *PVZService) CreatePVZ(ctx context.Context, pvzReq dto.PVZCreateRequest) (*model.PVZ, error) {
-	createdPVZ, err := s.pvzRepository.CreatePVZ(ctx, pvzReq)
+	if err := validateDetails(pvzReq.Latitude, pvzReq.Longitude, pvzReq.WorkingHours); err != nil {
+		return nil, err
+	}
+
+	available, err := s.cityService.IsCityAvailable(ctx, pvzReq.City)
+	if err != nil {
+		return nil, fmt.Errorf("failed to validate city: %w", err)
+	}
+	if !available {
+		return nil, fmt.Errorf("city %s is not available for PVZ", pvzReq.City)
+	}
+
+	var createdPVZ *model.PVZ
+
+	err = s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		var err error
+		createdPVZ, err = s.pvzRepository.CreatePVZ(ctx, pvzReq)
+		if err != nil {
+			return fmt.Errorf("failed to create PVZ: %w", err)
+		}
+
+		return s.auditService.Record(ctx, model.AuditActionCreate, model.AuditEntityPVZ, createdPVZ.ID, nil, createdPVZ)
+	})
 	if err != nil {
-		return nil, fmt.Errorf("failed to create PVZ: %w", err)
+		return nil, err
 	}
 
 	return createdPVZ, nil
 }
 
+func (s *PVZService) UpdatePVZ(ctx context.Context, pvzID string, pvzReq dto.PVZUpdateRequest) (*model.PVZ, error) {
+	if pvzReq.Address == nil && pvzReq.Latitude == nil && pvzReq.Longitude == nil &&
+		pvzReq.Phone == nil && pvzReq.WorkingHours == nil {
+		return nil, errors.New("no fields to update")
+	}
+
+	if err := validateDetails(pvzReq.Latitude, pvzReq.Longitude, pvzReq.WorkingHours); err != nil {
+		return nil, err
+	}
+
+	var updatedPVZ *model.PVZ
+
+	err := s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		pvz, err := s.pvzRepository.GetPVZForUpdate(ctx, pvzID)
+		if err != nil {
+			return fmt.Errorf("failed to get PVZ: %w", err)
+		}
+
+		if pvz.Status == model.PVZStatusArchived {
+			return errors.New("archived PVZ cannot be modified")
+		}
+
+		updatedPVZ, err = s.pvzRepository.UpdatePVZ(ctx, pvzID, pvzReq)
+		if err != nil {
+			return fmt.Errorf("failed to update PVZ: %w", err)
+		}
+
+		return s.auditService.Record(ctx, model.AuditActionUpdate, model.AuditEntityPVZ, pvzID, pvz, updatedPVZ)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return updatedPVZ, nil
+}
+
+// ChangePVZStatus moves the PVZ to the given status. Archiving is refused
+// while the PVZ has a reception in progress.
+func (s *PVZService) ChangePVZStatus(ctx context.Context, pvzID string, status model.PVZStatus) (*model.PVZ, error) {
+	var updatedPVZ *model.PVZ
+
+	err := s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		pvz, err := s.pvzRepository.GetPVZForUpdate(ctx, pvzID)
+		if err != nil {
+			return fmt.Errorf("failed to get PVZ: %w", err)
+		}
+
+		if pvz.Status == status {
+			return fmt.Errorf("pvz is already %s", status)
+		}
+
+		if !pvz.Status.CanTransitionTo(status) {
+			return fmt.Errorf("cannot change PVZ status from %s to %s", pvz.Status, status)
+		}
+
+		if status == model.PVZStatusArchived {
+			hasOpenReception, err := s.receptionRepository.HasOpenReception(ctx, pvzID)
+			if err != nil {
+				return fmt.Errorf("failed to check open receptions: %w", err)
+			}
+			if hasOpenReception {
+				return errors.New("cannot archive PVZ with a reception in progress")
+			}
+		}
+
+		updatedPVZ, err = s.pvzRepository.UpdatePVZStatus(ctx, pvzID, status)
+		if err != nil {
+			return fmt.Errorf("failed to update PVZ status: %w", err)
+		}
+
+		return s.auditService.Record(ctx, model.AuditActionStatusChange, model.AuditEntityPVZ, pvzID, pvz, updatedPVZ)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return updatedPVZ, nil
+}
+
 func (s *PVZService) GetPVZList(ctx context.Context, filter dto.PVZFilterQuery) (*dto.PaginatedResponse, error) {
 	pvzList, err := s.pvzRepository.GetPVZList(ctx, filter)
 	if err != nil {
@@ -52,32 +173,168 @@ func (s *PVZService) GetPVZList(ctx context.Context, filter dto.PVZFilterQuery)
 	}
 
 	for _, pvz := range pvzList {
-		receptions, err := s.receptionRepository.GetReceptionsByPVZID(ctx, pvz.ID, filter.StartDate, filter.EndDate)
+		receptions, err := s.getReceptionsWithProducts(ctx, pvz.ID, filter.ReceptionFilter())
 		if err != nil {
-			return nil, fmt.Errorf("failed to get receptions for PVZ %s: %w", pvz.ID, err)
+			return nil, err
 		}
 
-		pvzResponse := dto.PVZWithReceptionsResponse{
+		result.Data = append(result.Data, dto.PVZWithReceptionsResponse{
 			PVZ:        pvz,
-			Receptions: make([]dto.ReceptionWithProductsResponse, 0, len(receptions)),
-		}
+			Receptions: receptions,
+		})
+	}
 
-		for _, reception := range receptions {
-			products, err := s.productRepository.GetProductsByReceptionID(ctx, reception.ID)
-			if err != nil {
-				return nil, fmt.Errorf("failed to get products for reception %s: %w", reception.ID, err)
+	return result, nil
+}
+
+// StreamPVZList passes every PVZ matching filter to fn as soon as all its
+// receptions and products have been read, without pagination.
+func (s *PVZService) StreamPVZList(
+	ctx context.Context,
+	filter dto.PVZFilterQuery,
+	fn func(dto.PVZWithReceptionsResponse) error,
+) error {
+	var current *dto.PVZWithReceptionsResponse
+
+	err := s.pvzRepository.StreamPVZList(ctx, filter, func(pvz model.PVZ, reception *model.Reception, product *model.Product) error {
+		if current != nil && current.PVZ.ID != pvz.ID {
+			if err := fn(*current); err != nil {
+				return err
 			}
+			current = nil
+		}
 
-			receptionResponse := dto.ReceptionWithProductsResponse{
-				Reception: reception,
-				Products:  products,
+		if current == nil {
+			current = &dto.PVZWithReceptionsResponse{
+				PVZ:        pvz,
+				Receptions: []dto.ReceptionWithProductsResponse{},
 			}
+		}
+
+		if reception == nil {
+			return nil
+		}
+
+		last := len(current.Receptions) - 1
+		if last < 0 || current.Receptions[last].Reception.ID != reception.ID {
+			current.Receptions = append(current.Receptions, dto.ReceptionWithProductsResponse{
+				Reception: *reception,
+				Products:  []model.Product{},
+			})
+			last++
+		}
+
+		if product != nil {
+			current.Receptions[last].Products = append(current.Receptions[last].Products, *product)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to stream PVZ list: %w", err)
+	}
+
+	if current != nil {
+		return fn(*current)
+	}
+
+	return nil
+}
+
+func (s *PVZService) GetPVZ(ctx context.Context, pvzID string, query dto.PVZDetailsQuery) (*dto.PVZDetailsResponse, error) {
+	pvz, err := s.pvzRepository.GetPVZByID(ctx, pvzID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get PVZ: %w", err)
+	}
+
+	result := &dto.PVZDetailsResponse{PVZ: *pvz}
+
+	if query.IncludeReceptions {
+		result.Receptions, err = s.getReceptionsWithProducts(ctx, pvzID, dto.ReceptionFilter{})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return result, nil
+}
+
+func (s *PVZService) getReceptionsWithProducts(
+	ctx context.Context,
+	pvzID string,
+	filter dto.ReceptionFilter,
+) ([]dto.ReceptionWithProductsResponse, error) {
+	receptions, err := s.receptionRepository.GetReceptionsByPVZID(ctx, pvzID, filter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get receptions for PVZ %s: %w", pvzID, err)
+	}
 
-			pvzResponse.Receptions = append(pvzResponse.Receptions, receptionResponse)
+	result := make([]dto.ReceptionWithProductsResponse, 0, len(receptions))
+	for _, reception := range receptions {
+		products, err := s.productRepository.GetProductsByReceptionID(ctx, reception.ID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get products for reception %s: %w", reception.ID, err)
 		}
 
-		result.Data = append(result.Data, pvzResponse)
+		result = append(result, dto.ReceptionWithProductsResponse{
+			Reception: reception,
+			Products:  products,
+		})
+	}
+
+	return result, nil
+}
+
+func (s *PVZService) GetNearbyPVZList(ctx context.Context, query dto.PVZNearbyQuery) ([]model.NearbyPVZ, error) {
+	nearbyList, err := s.pvzRepository.GetNearbyPVZList(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get nearby PVZ list: %w", err)
+	}
+
+	if !query.OpenNow {
+		return nearbyList, nil
+	}
+
+	now := time.Now()
+	result := make([]model.NearbyPVZ, 0, query.Limit)
+	for _, nearby := range nearbyList {
+		if len(result) == int(query.Limit) {
+			break
+		}
+		if s.isOpenAt(nearby, now) {
+			result = append(result, nearby)
+		}
 	}
 
 	return result, nil
 }
+
+// isOpenAt reports whether the nearby PVZ is open at t in the local time of
+// its city. PVZs without working hours or without a known city timezone are
+// considered closed.
+func (s *PVZService) isOpenAt(nearby model.NearbyPVZ, t time.Time) bool {
+	if nearby.PVZ.WorkingHours == nil || nearby.Timezone == "" {
+		return false
+	}
+
+	location, err := s.locations.Get(nearby.Timezone)
+	if err != nil {
+		return false
+	}
+
+	return nearby.PVZ.WorkingHours.IsOpenAt(t.In(location))
+}
+
+func validateDetails(latitude, longitude *float64, workingHours *model.WorkingHours) error {
+	if (latitude == nil) != (longitude == nil) {
+		return errors.New("latitude and longitude must be set together")
+	}
+
+	if workingHours != nil {
+		if err := workingHours.Validate(); err != nil {
+			return fmt.Errorf("invalid working hours: %w", err)
+		}
+	}
+
+	return nil
+}
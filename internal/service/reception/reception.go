@@ -3,46 +3,394 @@ package reception
 import (
 	"context"
 	"fmt"
+	"strings"
+	"time"
 
+	"github.com/kirillidk/pvz-service/internal/config"
 	"github.com/kirillidk/pvz-service/internal/dto"
 	"github.com/kirillidk/pvz-service/internal/model"
 	"github.com/kirillidk/pvz-service/internal/repository"
+	"github.com/kirillidk/pvz-service/internal/requestctx"
+	"github.com/kirillidk/pvz-service/internal/service/audit"
+	"github.com/kirillidk/pvz-service/internal/service/expecteddelivery"
 )
 
 type ReceptionServiceInterface interface {
 	CreateReception(ctx context.Context, receptionCreateReq dto.ReceptionCreateRequest) (*model.Reception, error)
-	CloseLastReception(ctx context.Context, pvzID string) (*model.Reception, error)
+	CloseLastReception(ctx context.Context, pvzID string) (*dto.ReceptionCloseResponse, error)
+	ReopenReception(ctx context.Context, receptionID, reason string) (*model.Reception, error)
+	GetReception(ctx context.Context, receptionID string) (*dto.ReceptionWithProductsResponse, error)
+	GetCurrentReception(ctx context.Context, pvzID string) (*dto.ReceptionWithProductsResponse, error)
+	SetExpectedCount(ctx context.Context, pvzID, receptionID string, expectedCount int) (*model.Reception, error)
+	GetDiscrepancyReport(ctx context.Context, pvzID, receptionID string) (*dto.ReceptionDiscrepancyReport, error)
+	GetDiscrepancies(ctx context.Context, filter dto.DiscrepancyFilterQuery) ([]model.ReceptionDiscrepancy, error)
+	GetReceptionStatusHistory(ctx context.Context, pvzID, receptionID string) ([]model.ReceptionStatusChange, error)
 }
 
 type ReceptionService struct {
-	receptionRepository repository.ReceptionRepositoryInterface
+	receptionRepository       repository.ReceptionRepositoryInterface
+	receptionStatusRepository repository.ReceptionStatusRepositoryInterface
+	pvzRepository             repository.PVZRepositoryInterface
+	productRepository         repository.ProductRepositoryInterface
+	transactor                repository.TransactorInterface
+	auditService              audit.AuditServiceInterface
+	expectedDeliveryService   expecteddelivery.ExpectedDeliveryServiceInterface
+	receptionConfig           config.ReceptionConfig
 }
 
-func NewReceptionService(receptionRepo repository.ReceptionRepositoryInterface) *ReceptionService {
+func NewReceptionService(
+	receptionRepo repository.ReceptionRepositoryInterface,
+	receptionStatusRepo repository.ReceptionStatusRepositoryInterface,
+	pvzRepo repository.PVZRepositoryInterface,
+	productRepo repository.ProductRepositoryInterface,
+	transactor repository.TransactorInterface,
+	auditService audit.AuditServiceInterface,
+	expectedDeliveryService expecteddelivery.ExpectedDeliveryServiceInterface,
+	receptionCfg config.ReceptionConfig,
+) *ReceptionService {
 	return &ReceptionService{
-		receptionRepository: receptionRepo,
+		receptionRepository:       receptionRepo,
+		receptionStatusRepository: receptionStatusRepo,
+		pvzRepository:             pvzRepo,
+		productRepository:         productRepo,
+		transactor:                transactor,
+		auditService:              auditService,
+		expectedDeliveryService:   expectedDeliveryService,
+		receptionConfig:           receptionCfg,
 	}
 }
 
+// CreateReception opens a reception at the PVZ and links the oldest
+// pending expected delivery of the PVZ to it.
 func (s *ReceptionService) CreateReception(ctx context.Context, receptionCreateReq dto.ReceptionCreateRequest) (*model.Reception, error) {
-	reception, err := s.receptionRepository.CreateReception(ctx, receptionCreateReq)
+	var reception *model.Reception
+
+	err := s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		pvz, err := s.pvzRepository.GetPVZForUpdate(ctx, receptionCreateReq.PVZID)
+		if err != nil {
+			return fmt.Errorf("failed to get PVZ: %w", err)
+		}
+
+		if pvz.Status != model.PVZStatusActive {
+			return model.ErrPVZNotActive
+		}
+
+		reception, err = s.receptionRepository.CreateReception(ctx, receptionCreateReq)
+		if err != nil {
+			return fmt.Errorf("failed to create reception: %w", err)
+		}
+
+		err = s.recordStatusChange(ctx, reception.ID, "", reception.Status, "")
+		if err != nil {
+			return err
+		}
+
+		err = s.auditService.Record(ctx, model.AuditActionCreate, model.AuditEntityReception, reception.ID, nil, reception)
+		if err != nil {
+			return err
+		}
+
+		_, err = s.expectedDeliveryService.LinkReception(ctx, reception)
+		return err
+	})
 	if err != nil {
-		return nil, fmt.Errorf("failed to create reception: %w", err)
+		return nil, err
 	}
 
 	return reception, nil
 }
 
-func (s *ReceptionService) CloseLastReception(ctx context.Context, pvzID string) (*model.Reception, error) {
+// CloseLastReception closes the open reception of the PVZ. If an expected
+// delivery is linked to the reception, it is reconciled and the missing
+// and unexpected items are returned with the reception.
+func (s *ReceptionService) CloseLastReception(ctx context.Context, pvzID string) (*dto.ReceptionCloseResponse, error) {
+	var response *dto.ReceptionCloseResponse
+
+	err := s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		reception, err := s.receptionRepository.GetLastOpenReception(ctx, pvzID)
+		if err != nil {
+			return fmt.Errorf("failed to find open reception: %w", err)
+		}
+
+		closedReception, err := s.receptionRepository.CloseReception(ctx, reception.ID)
+		if err != nil {
+			return fmt.Errorf("failed to close reception: %w", err)
+		}
+
+		err = s.recordStatusChange(ctx, reception.ID, reception.Status, closedReception.Status, "")
+		if err != nil {
+			return err
+		}
+
+		err = s.auditService.Record(ctx, model.AuditActionClose, model.AuditEntityReception, reception.ID, reception, closedReception)
+		if err != nil {
+			return err
+		}
+
+		reconciliation, err := s.expectedDeliveryService.ReconcileReception(ctx, closedReception)
+		if err != nil {
+			return err
+		}
+
+		response = &dto.ReceptionCloseResponse{Reception: *closedReception, Reconciliation: reconciliation}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return response, nil
+}
+
+// ReopenReception puts a closed reception back in progress on a
+// moderator's request. Only the latest reception of the PVZ may be reopened,
+// only within the configured window after it was closed and only while all
+// its products are still just received. The reason is kept in the status
+// history of the reception.
+func (s *ReceptionService) ReopenReception(ctx context.Context, receptionID, reason string) (*model.Reception, error) {
+	reason = strings.TrimSpace(reason)
+	if reason == "" {
+		return nil, fmt.Errorf("reason is required to reopen a reception")
+	}
+
+	var reopenedReception *model.Reception
+
+	err := s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		reception, err := s.receptionRepository.GetReceptionByID(ctx, receptionID)
+		if err != nil {
+			return fmt.Errorf("failed to get reception: %w", err)
+		}
+
+		// The PVZ is locked first, as in CreateReception, so that no
+		// reception can be started while this one is being reopened.
+		pvz, err := s.pvzRepository.GetPVZForUpdate(ctx, reception.PVZID)
+		if err != nil {
+			return fmt.Errorf("failed to get PVZ: %w", err)
+		}
+
+		if pvz.Status != model.PVZStatusActive {
+			return model.ErrPVZNotActive
+		}
+
+		reception, err = s.receptionRepository.GetReceptionForUpdate(ctx, receptionID)
+		if err != nil {
+			return fmt.Errorf("failed to get reception: %w", err)
+		}
+
+		if reception.Status != "close" {
+			return model.ErrReceptionNotClosed
+		}
+
+		if reception.ClosedAt == nil || time.Since(*reception.ClosedAt) > s.receptionConfig.ReopenWindow {
+			return model.ErrReceptionReopenExpired
+		}
+
+		hasNewerReception, err := s.receptionRepository.HasNewerReception(ctx, reception)
+		if err != nil {
+			return fmt.Errorf("failed to check newer receptions: %w", err)
+		}
+
+		if hasNewerReception {
+			return model.ErrNewerReceptionExists
+		}
+
+		hasProcessedProducts, err := s.receptionRepository.HasProcessedProducts(ctx, receptionID)
+		if err != nil {
+			return fmt.Errorf("failed to check processed products: %w", err)
+		}
+
+		if hasProcessedProducts {
+			return model.ErrReceptionProcessed
+		}
+
+		reopenedReception, err = s.receptionRepository.ReopenReception(ctx, receptionID)
+		if err != nil {
+			return fmt.Errorf("failed to reopen reception: %w", err)
+		}
+
+		err = s.recordStatusChange(ctx, receptionID, reception.Status, reopenedReception.Status, reason)
+		if err != nil {
+			return err
+		}
+
+		err = s.auditService.Record(ctx, model.AuditActionStatusChange, model.AuditEntityReception, receptionID, reception, reopenedReception)
+		if err != nil {
+			return err
+		}
+
+		return s.expectedDeliveryService.ReopenReception(ctx, reopenedReception)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return reopenedReception, nil
+}
+
+func (s *ReceptionService) GetReception(ctx context.Context, receptionID string) (*dto.ReceptionWithProductsResponse, error) {
+	reception, err := s.receptionRepository.GetReceptionByID(ctx, receptionID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get reception: %w", err)
+	}
+
+	return s.withProducts(ctx, reception)
+}
+
+// GetCurrentReception returns the reception in progress at the PVZ together
+// with the products scanned so far.
+func (s *ReceptionService) GetCurrentReception(ctx context.Context, pvzID string) (*dto.ReceptionWithProductsResponse, error) {
 	reception, err := s.receptionRepository.GetLastOpenReception(ctx, pvzID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to find open reception: %w", err)
 	}
 
-	closedReception, err := s.receptionRepository.CloseReception(ctx, reception.ID)
+	return s.withProducts(ctx, reception)
+}
+
+// SetExpectedCount records the number of products in the courier's
+// manifest. It may be set after the reception is closed, as the manifest
+// does not always arrive with the parcels.
+func (s *ReceptionService) SetExpectedCount(
+	ctx context.Context,
+	pvzID, receptionID string,
+	expectedCount int,
+) (*model.Reception, error) {
+	var updatedReception *model.Reception
+
+	err := s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		reception, err := s.receptionRepository.GetReceptionByID(ctx, receptionID)
+		if err != nil {
+			return fmt.Errorf("failed to get reception: %w", err)
+		}
+
+		if err := checkReceptionPVZ(reception.PVZID, pvzID); err != nil {
+			return err
+		}
+
+		updatedReception, err = s.receptionRepository.SetExpectedCount(ctx, receptionID, expectedCount)
+		if err != nil {
+			return fmt.Errorf("failed to set expected count: %w", err)
+		}
+
+		return s.auditService.Record(ctx, model.AuditActionUpdate, model.AuditEntityReception, receptionID, reception, updatedReception)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return updatedReception, nil
+}
+
+// GetDiscrepancyReport compares the reception with its manifest and lists
+// the products that arrived damaged or opened.
+func (s *ReceptionService) GetDiscrepancyReport(
+	ctx context.Context,
+	pvzID, receptionID string,
+) (*dto.ReceptionDiscrepancyReport, error) {
+	discrepancy, err := s.receptionRepository.GetReceptionDiscrepancy(ctx, receptionID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get reception discrepancy: %w", err)
+	}
+
+	if err := checkReceptionPVZ(discrepancy.PVZID, pvzID); err != nil {
+		return nil, err
+	}
+
+	products, err := s.productRepository.GetProductsByReceptionID(ctx, receptionID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get products for reception %s: %w", receptionID, err)
+	}
+
+	report := &dto.ReceptionDiscrepancyReport{
+		ReceptionDiscrepancy: *discrepancy,
+		HasDiscrepancy:       discrepancy.HasDiscrepancy(),
+		Products:             []model.Product{},
+	}
+
+	for _, product := range products {
+		if product.Condition != model.ProductConditionOK {
+			report.Products = append(report.Products, product)
+		}
+	}
+
+	return report, nil
+}
+
+func (s *ReceptionService) GetDiscrepancies(
+	ctx context.Context,
+	filter dto.DiscrepancyFilterQuery,
+) ([]model.ReceptionDiscrepancy, error) {
+	if filter.StartDate != nil && filter.EndDate != nil && filter.EndDate.Before(*filter.StartDate) {
+		return nil, fmt.Errorf("%w: 'endDate' must not be before 'startDate'", model.ErrInvalidDateRange)
+	}
+
+	discrepancies, err := s.receptionRepository.GetReceptionDiscrepancies(ctx, filter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get reception discrepancies: %w", err)
+	}
+
+	return discrepancies, nil
+}
+
+func (s *ReceptionService) GetReceptionStatusHistory(
+	ctx context.Context,
+	pvzID, receptionID string,
+) ([]model.ReceptionStatusChange, error) {
+	reception, err := s.receptionRepository.GetReceptionByID(ctx, receptionID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get reception: %w", err)
+	}
+
+	if err := checkReceptionPVZ(reception.PVZID, pvzID); err != nil {
+		return nil, err
+	}
+
+	history, err := s.receptionStatusRepository.GetReceptionStatusHistory(ctx, receptionID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get reception status history: %w", err)
+	}
+
+	return history, nil
+}
+
+func (s *ReceptionService) recordStatusChange(ctx context.Context, receptionID, fromStatus, toStatus, reason string) error {
+	_, err := s.receptionStatusRepository.CreateReceptionStatusChange(ctx, model.ReceptionStatusChange{
+		ReceptionID: receptionID,
+		FromStatus:  fromStatus,
+		ToStatus:    toStatus,
+		Reason:      reason,
+		ChangedAt:   time.Now().UTC(),
+		Actor:       requestctx.ActorFromContext(ctx),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to record reception status change: %w", err)
+	}
+
+	return nil
+}
+
+func (s *ReceptionService) withProducts(ctx context.Context, reception *model.Reception) (*dto.ReceptionWithProductsResponse, error) {
+	products, err := s.productRepository.GetProductsByReceptionIDInInsertionOrder(ctx, reception.ID)
 	if err != nil {
-		return nil, fmt.Errorf("failed to close reception: %w", err)
+		return nil, fmt.Errorf("failed to get products for reception %s: %w", reception.ID, err)
+	}
+
+	if products == nil {
+		products = []model.Product{}
 	}
 
-	return closedReception, nil
+	return &dto.ReceptionWithProductsResponse{
+		Reception: *reception,
+		Products:  products,
+	}, nil
+}
+
+// checkReceptionPVZ reports receptions of other PVZs as not found, so that
+// a PVZ cannot operate on, or learn about, receptions of another one.
+func checkReceptionPVZ(receptionPVZID, pvzID string) error {
+	if receptionPVZID != pvzID {
+		return fmt.Errorf("failed to get reception: %w", model.ErrReceptionNotFound)
+	}
+	return nil
 }
@@ -7,6 +7,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/kirillidk/pvz-service/internal/config"
 	"github.com/kirillidk/pvz-service/internal/dto"
 	"github.com/kirillidk/pvz-service/internal/model"
 	"github.com/kirillidk/pvz-service/internal/service/reception"
@@ -16,8 +17,18 @@ type MockReceptionRepository struct {
 	CreateReceptionFunc      func(ctx context.Context, req dto.ReceptionCreateRequest) (*model.Reception, error)
 	HasOpenReceptionFunc     func(ctx context.Context, pvzID string) (bool, error)
 	GetLastOpenReceptionFunc func(ctx context.Context, pvzID string) (*model.Reception, error)
+	GetReceptionByIDFunc     func(ctx context.Context, receptionID string) (*model.Reception, error)
 	CloseReceptionFunc       func(ctx context.Context, receptionID string) (*model.Reception, error)
-	GetReceptionsByPVZIDFunc func(ctx context.Context, pvzID string, startDate, endDate *time.Time) ([]model.Reception, error)
+	GetReceptionsByPVZIDFunc func(ctx context.Context, pvzID string, filter dto.ReceptionFilter) ([]model.Reception, error)
+
+	GetLastOpenReceptionForUpdateFunc func(ctx context.Context, pvzID string) (*model.Reception, error)
+	SetExpectedCountFunc              func(ctx context.Context, receptionID string, expectedCount int) (*model.Reception, error)
+	GetReceptionDiscrepancyFunc       func(ctx context.Context, receptionID string) (*model.ReceptionDiscrepancy, error)
+	GetReceptionDiscrepanciesFunc     func(ctx context.Context, filter dto.DiscrepancyFilterQuery) ([]model.ReceptionDiscrepancy, error)
+	GetReceptionForUpdateFunc         func(ctx context.Context, receptionID string) (*model.Reception, error)
+	HasNewerReceptionFunc             func(ctx context.Context, reception *model.Reception) (bool, error)
+	HasProcessedProductsFunc          func(ctx context.Context, receptionID string) (bool, error)
+	ReopenReceptionFunc               func(ctx context.Context, receptionID string) (*model.Reception, error)
 }
 
 func (m *MockReceptionRepository) CreateReception(ctx context.Context, req dto.ReceptionCreateRequest) (*model.Reception, error) {
@@ -32,12 +43,264 @@ func (m *MockReceptionRepository) GetLastOpenReception(ctx context.Context, pvzI
 	return m.GetLastOpenReceptionFunc(ctx, pvzID)
 }
 
+func (m *MockReceptionRepository) GetLastOpenReceptionForUpdate(ctx context.Context, pvzID string) (*model.Reception, error) {
+	return m.GetLastOpenReceptionForUpdateFunc(ctx, pvzID)
+}
+
+func (m *MockReceptionRepository) GetReceptionByID(ctx context.Context, receptionID string) (*model.Reception, error) {
+	return m.GetReceptionByIDFunc(ctx, receptionID)
+}
+
 func (m *MockReceptionRepository) CloseReception(ctx context.Context, pvzID string) (*model.Reception, error) {
 	return m.CloseReceptionFunc(ctx, pvzID)
 }
 
-func (m *MockReceptionRepository) GetReceptionsByPVZID(ctx context.Context, pvzID string, startDate, endDate *time.Time) ([]model.Reception, error) {
-	return m.GetReceptionsByPVZIDFunc(ctx, pvzID, startDate, endDate)
+func (m *MockReceptionRepository) GetReceptionForUpdate(ctx context.Context, receptionID string) (*model.Reception, error) {
+	return m.GetReceptionForUpdateFunc(ctx, receptionID)
+}
+
+func (m *MockReceptionRepository) HasNewerReception(ctx context.Context, reception *model.Reception) (bool, error) {
+	return m.HasNewerReceptionFunc(ctx, reception)
+}
+
+func (m *MockReceptionRepository) HasProcessedProducts(ctx context.Context, receptionID string) (bool, error) {
+	return m.HasProcessedProductsFunc(ctx, receptionID)
+}
+
+func (m *MockReceptionRepository) ReopenReception(ctx context.Context, receptionID string) (*model.Reception, error) {
+	return m.ReopenReceptionFunc(ctx, receptionID)
+}
+
+func (m *MockReceptionRepository) GetReceptionsByPVZID(ctx context.Context, pvzID string, filter dto.ReceptionFilter) ([]model.Reception, error) {
+	return m.GetReceptionsByPVZIDFunc(ctx, pvzID, filter)
+}
+
+func (m *MockReceptionRepository) SetExpectedCount(ctx context.Context, receptionID string, expectedCount int) (*model.Reception, error) {
+	return m.SetExpectedCountFunc(ctx, receptionID, expectedCount)
+}
+
+func (m *MockReceptionRepository) GetReceptionDiscrepancy(ctx context.Context, receptionID string) (*model.ReceptionDiscrepancy, error) {
+	return m.GetReceptionDiscrepancyFunc(ctx, receptionID)
+}
+
+func (m *MockReceptionRepository) GetReceptionDiscrepancies(
+	ctx context.Context,
+	filter dto.DiscrepancyFilterQuery,
+) ([]model.ReceptionDiscrepancy, error) {
+	return m.GetReceptionDiscrepanciesFunc(ctx, filter)
+}
+
+type MockReceptionStatusRepository struct {
+	CreateReceptionStatusChangeFunc func(ctx context.Context, change model.ReceptionStatusChange) (*model.ReceptionStatusChange, error)
+	GetReceptionStatusHistoryFunc   func(ctx context.Context, receptionID string) ([]model.ReceptionStatusChange, error)
+}
+
+func (m *MockReceptionStatusRepository) CreateReceptionStatusChange(
+	ctx context.Context,
+	change model.ReceptionStatusChange,
+) (*model.ReceptionStatusChange, error) {
+	if m.CreateReceptionStatusChangeFunc == nil {
+		return &change, nil
+	}
+	return m.CreateReceptionStatusChangeFunc(ctx, change)
+}
+
+func (m *MockReceptionStatusRepository) GetReceptionStatusHistory(
+	ctx context.Context,
+	receptionID string,
+) ([]model.ReceptionStatusChange, error) {
+	return m.GetReceptionStatusHistoryFunc(ctx, receptionID)
+}
+
+type MockPVZRepository struct {
+	CreatePVZFunc        func(ctx context.Context, req dto.PVZCreateRequest) (*model.PVZ, error)
+	GetPVZListFunc       func(ctx context.Context, filter dto.PVZFilterQuery) ([]model.PVZ, error)
+	GetPVZByIDFunc       func(ctx context.Context, pvzID string) (*model.PVZ, error)
+	UpdatePVZFunc        func(ctx context.Context, pvzID string, req dto.PVZUpdateRequest) (*model.PVZ, error)
+	GetNearbyPVZListFunc func(ctx context.Context, query dto.PVZNearbyQuery) ([]model.NearbyPVZ, error)
+	GetPVZForUpdateFunc  func(ctx context.Context, pvzID string) (*model.PVZ, error)
+	UpdatePVZStatusFunc  func(ctx context.Context, pvzID string, status model.PVZStatus) (*model.PVZ, error)
+	StreamPVZListFunc    func(ctx context.Context, filter dto.PVZFilterQuery, fn func(model.PVZ, *model.Reception, *model.Product) error) error
+}
+
+// newMockPVZRepository returns a repository that knows a single PVZ in the
+// given status.
+func newMockPVZRepository(status model.PVZStatus) *MockPVZRepository {
+	getPVZ := func(ctx context.Context, pvzID string) (*model.PVZ, error) {
+		return &model.PVZ{ID: pvzID, City: "Москва", Status: status}, nil
+	}
+
+	return &MockPVZRepository{
+		GetPVZByIDFunc:      getPVZ,
+		GetPVZForUpdateFunc: getPVZ,
+	}
+}
+
+func (m *MockPVZRepository) CreatePVZ(ctx context.Context, req dto.PVZCreateRequest) (*model.PVZ, error) {
+	return m.CreatePVZFunc(ctx, req)
+}
+
+func (m *MockPVZRepository) GetPVZList(ctx context.Context, filter dto.PVZFilterQuery) ([]model.PVZ, error) {
+	return m.GetPVZListFunc(ctx, filter)
+}
+
+func (m *MockPVZRepository) GetPVZByID(ctx context.Context, pvzID string) (*model.PVZ, error) {
+	return m.GetPVZByIDFunc(ctx, pvzID)
+}
+
+func (m *MockPVZRepository) UpdatePVZ(ctx context.Context, pvzID string, req dto.PVZUpdateRequest) (*model.PVZ, error) {
+	return m.UpdatePVZFunc(ctx, pvzID, req)
+}
+
+func (m *MockPVZRepository) GetNearbyPVZList(ctx context.Context, query dto.PVZNearbyQuery) ([]model.NearbyPVZ, error) {
+	return m.GetNearbyPVZListFunc(ctx, query)
+}
+
+func (m *MockPVZRepository) GetPVZForUpdate(ctx context.Context, pvzID string) (*model.PVZ, error) {
+	return m.GetPVZForUpdateFunc(ctx, pvzID)
+}
+
+func (m *MockPVZRepository) UpdatePVZStatus(ctx context.Context, pvzID string, status model.PVZStatus) (*model.PVZ, error) {
+	return m.UpdatePVZStatusFunc(ctx, pvzID, status)
+}
+
+func (m *MockPVZRepository) StreamPVZList(ctx context.Context, filter dto.PVZFilterQuery, fn func(model.PVZ, *model.Reception, *model.Product) error) error {
+	return m.StreamPVZListFunc(ctx, filter, fn)
+}
+
+type MockProductRepository struct {
+	GetProductsByReceptionIDFunc                 func(ctx context.Context, receptionID string) ([]model.Product, error)
+	GetProductsByReceptionIDInInsertionOrderFunc func(ctx context.Context, receptionID string) ([]model.Product, error)
+}
+
+func (m *MockProductRepository) CreateProduct(ctx context.Context, receptionID string, attrs dto.ProductAttributes) (*model.Product, error) {
+	return nil, nil
+}
+
+func (m *MockProductRepository) GetLastProductInReception(ctx context.Context, receptionID string) (*model.Product, error) {
+	return nil, nil
+}
+
+func (m *MockProductRepository) DeleteProduct(ctx context.Context, productID string) error {
+	return nil
+}
+
+func (m *MockProductRepository) GetProductsByReceptionID(ctx context.Context, receptionID string) ([]model.Product, error) {
+	return m.GetProductsByReceptionIDFunc(ctx, receptionID)
+}
+
+func (m *MockProductRepository) GetProductsByReceptionIDInInsertionOrder(ctx context.Context, receptionID string) ([]model.Product, error) {
+	return m.GetProductsByReceptionIDInInsertionOrderFunc(ctx, receptionID)
+}
+
+func (m *MockProductRepository) CreateProducts(ctx context.Context, receptionID string, items []dto.ProductAttributes) ([]model.Product, error) {
+	return nil, nil
+}
+
+func (m *MockProductRepository) GetProductsByBarcodes(ctx context.Context, barcodes []string) ([]model.ProductLocation, error) {
+	return nil, nil
+}
+
+func (m *MockProductRepository) GetProductLocation(ctx context.Context, productID string) (*model.ProductLocation, error) {
+	return nil, nil
+}
+
+func (m *MockProductRepository) GetProductLocationForUpdate(ctx context.Context, productID string) (*model.ProductLocation, error) {
+	return nil, nil
+}
+
+func (m *MockProductRepository) UpdateProductStatus(ctx context.Context, productID string, status model.ProductStatus) (*model.Product, error) {
+	return nil, nil
+}
+
+func (m *MockProductRepository) UpdateProductCell(ctx context.Context, productID, cellID string) (*model.Product, error) {
+	return nil, nil
+}
+
+func (m *MockProductRepository) UpdateProductCondition(
+	ctx context.Context,
+	productID string,
+	condition model.ProductCondition,
+	note string,
+) (*model.Product, error) {
+	return nil, nil
+}
+
+type MockTransactor struct{}
+
+func (m *MockTransactor) WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	return fn(ctx)
+}
+
+type MockAuditService struct {
+	RecordFunc func(ctx context.Context, action model.AuditAction, entityType model.AuditEntityType, entityID string, before, after any) error
+}
+
+func (m *MockAuditService) Record(
+	ctx context.Context,
+	action model.AuditAction,
+	entityType model.AuditEntityType,
+	entityID string,
+	before, after any,
+) error {
+	if m.RecordFunc == nil {
+		return nil
+	}
+	return m.RecordFunc(ctx, action, entityType, entityID, before, after)
+}
+
+func (m *MockAuditService) GetAuditLog(ctx context.Context, filter dto.AuditFilterQuery) ([]model.AuditEntry, error) {
+	return nil, nil
+}
+
+type MockExpectedDeliveryService struct {
+	LinkReceptionFunc      func(ctx context.Context, reception *model.Reception) (*model.ExpectedDelivery, error)
+	ReconcileReceptionFunc func(ctx context.Context, reception *model.Reception) (*model.DeliveryReconciliation, error)
+	ReopenReceptionFunc    func(ctx context.Context, reception *model.Reception) error
+}
+
+func (m *MockExpectedDeliveryService) CreateExpectedDelivery(
+	ctx context.Context,
+	pvzID string,
+	req dto.ExpectedDeliveryCreateRequest,
+) (*model.ExpectedDelivery, error) {
+	return nil, nil
+}
+
+func (m *MockExpectedDeliveryService) GetExpectedDeliveries(ctx context.Context, pvzID string) ([]model.ExpectedDelivery, error) {
+	return nil, nil
+}
+
+func (m *MockExpectedDeliveryService) GetExpectedDelivery(ctx context.Context, pvzID, deliveryID string) (*model.ExpectedDelivery, error) {
+	return nil, nil
+}
+
+func (m *MockExpectedDeliveryService) LinkReception(ctx context.Context, reception *model.Reception) (*model.ExpectedDelivery, error) {
+	if m.LinkReceptionFunc == nil {
+		return nil, nil
+	}
+	return m.LinkReceptionFunc(ctx, reception)
+}
+
+func (m *MockExpectedDeliveryService) MatchProduct(ctx context.Context, product model.Product) ([]string, error) {
+	return nil, nil
+}
+
+func (m *MockExpectedDeliveryService) ReconcileReception(
+	ctx context.Context,
+	reception *model.Reception,
+) (*model.DeliveryReconciliation, error) {
+	if m.ReconcileReceptionFunc == nil {
+		return nil, nil
+	}
+	return m.ReconcileReceptionFunc(ctx, reception)
+}
+
+func (m *MockExpectedDeliveryService) ReopenReception(ctx context.Context, reception *model.Reception) error {
+	if m.ReopenReceptionFunc == nil {
+		return nil
+	}
+	return m.ReopenReceptionFunc(ctx, reception)
 }
 
 func TestReceptionService_CreateReception(t *testing.T) {
@@ -90,7 +353,10 @@ func TestReceptionService_CreateReception(t *testing.T) {
 
 	for ttNum, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			s := reception.NewReceptionService(tt.mockRepo)
+			s := reception.NewReceptionService(
+				tt.mockRepo, &MockReceptionStatusRepository{}, newMockPVZRepository(model.PVZStatusActive), &MockProductRepository{},
+				&MockTransactor{}, &MockAuditService{}, &MockExpectedDeliveryService{}, config.ReceptionConfig{},
+			)
 			got, err := s.CreateReception(context.Background(), tt.input)
 
 			if (err != nil) != tt.expectedError {
@@ -104,6 +370,24 @@ func TestReceptionService_CreateReception(t *testing.T) {
 	}
 }
 
+func TestReceptionService_CreateReception_InactivePVZ(t *testing.T) {
+	for _, status := range []model.PVZStatus{model.PVZStatusSuspended, model.PVZStatusArchived} {
+		t.Run(string(status), func(t *testing.T) {
+			s := reception.NewReceptionService(
+				&MockReceptionRepository{}, &MockReceptionStatusRepository{}, newMockPVZRepository(status), &MockProductRepository{},
+				&MockTransactor{}, &MockAuditService{}, &MockExpectedDeliveryService{}, config.ReceptionConfig{},
+			)
+			_, err := s.CreateReception(context.Background(), dto.ReceptionCreateRequest{
+				PVZID: "123e4567-e89b-12d3-a456-426614174000",
+			})
+
+			if !errors.Is(err, model.ErrPVZNotActive) {
+				t.Errorf("ReceptionService.CreateReception() error = %v, expected %v", err, model.ErrPVZNotActive)
+			}
+		})
+	}
+}
+
 func TestReceptionService_CloseLastReception(t *testing.T) {
 	now := time.Now()
 
@@ -111,7 +395,7 @@ func TestReceptionService_CloseLastReception(t *testing.T) {
 		name          string
 		mockRepo      *MockReceptionRepository
 		pvzID         string
-		expected      *model.Reception
+		expected      *dto.ReceptionCloseResponse
 		expectedError bool
 	}{
 		{
@@ -135,11 +419,13 @@ func TestReceptionService_CloseLastReception(t *testing.T) {
 				},
 			},
 			pvzID: "123e4567-e89b-12d3-a456-426614174000",
-			expected: &model.Reception{
-				ID:       "123e4567-e89b-12d3-a456-426614174000",
-				DateTime: now,
-				PVZID:    "123e4567-e89b-12d3-a456-426614174000",
-				Status:   "close",
+			expected: &dto.ReceptionCloseResponse{
+				Reception: model.Reception{
+					ID:       "123e4567-e89b-12d3-a456-426614174000",
+					DateTime: now,
+					PVZID:    "123e4567-e89b-12d3-a456-426614174000",
+					Status:   "close",
+				},
 			},
 			expectedError: false,
 		},
@@ -177,7 +463,10 @@ func TestReceptionService_CloseLastReception(t *testing.T) {
 
 	for ttNum, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			s := reception.NewReceptionService(tt.mockRepo)
+			s := reception.NewReceptionService(
+				tt.mockRepo, &MockReceptionStatusRepository{}, newMockPVZRepository(model.PVZStatusActive), &MockProductRepository{},
+				&MockTransactor{}, &MockAuditService{}, &MockExpectedDeliveryService{}, config.ReceptionConfig{},
+			)
 			got, err := s.CloseLastReception(context.Background(), tt.pvzID)
 
 			if (err != nil) != tt.expectedError {
@@ -190,3 +479,673 @@ func TestReceptionService_CloseLastReception(t *testing.T) {
 		})
 	}
 }
+
+func TestReceptionService_GetReception(t *testing.T) {
+	now := time.Now()
+
+	closedReception := &model.Reception{
+		ID:       "123e4567-e89b-12d3-a456-426614174001",
+		DateTime: now,
+		PVZID:    "123e4567-e89b-12d3-a456-426614174000",
+		Status:   "close",
+	}
+	products := []model.Product{
+		{ID: "product-id-1", DateTime: now, Type: "electronics", ReceptionID: closedReception.ID},
+		{ID: "product-id-2", DateTime: now.Add(time.Minute), Type: "shoes", ReceptionID: closedReception.ID},
+	}
+
+	tests := []struct {
+		name          string
+		mockRepo      *MockReceptionRepository
+		productRepo   *MockProductRepository
+		expected      *dto.ReceptionWithProductsResponse
+		expectedError error
+	}{
+		{
+			name: "Success",
+			mockRepo: &MockReceptionRepository{
+				GetReceptionByIDFunc: func(ctx context.Context, receptionID string) (*model.Reception, error) {
+					return closedReception, nil
+				},
+			},
+			productRepo: &MockProductRepository{
+				GetProductsByReceptionIDInInsertionOrderFunc: func(ctx context.Context, receptionID string) ([]model.Product, error) {
+					return products, nil
+				},
+			},
+			expected: &dto.ReceptionWithProductsResponse{
+				Reception: *closedReception,
+				Products:  products,
+			},
+			expectedError: nil,
+		},
+		{
+			name: "Empty Reception",
+			mockRepo: &MockReceptionRepository{
+				GetReceptionByIDFunc: func(ctx context.Context, receptionID string) (*model.Reception, error) {
+					return closedReception, nil
+				},
+			},
+			productRepo: &MockProductRepository{
+				GetProductsByReceptionIDInInsertionOrderFunc: func(ctx context.Context, receptionID string) ([]model.Product, error) {
+					return nil, nil
+				},
+			},
+			expected: &dto.ReceptionWithProductsResponse{
+				Reception: *closedReception,
+				Products:  []model.Product{},
+			},
+			expectedError: nil,
+		},
+		{
+			name: "Reception Not Found",
+			mockRepo: &MockReceptionRepository{
+				GetReceptionByIDFunc: func(ctx context.Context, receptionID string) (*model.Reception, error) {
+					return nil, model.ErrReceptionNotFound
+				},
+			},
+			productRepo:   &MockProductRepository{},
+			expected:      nil,
+			expectedError: model.ErrReceptionNotFound,
+		},
+	}
+
+	for ttNum, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := reception.NewReceptionService(
+				tt.mockRepo, &MockReceptionStatusRepository{}, newMockPVZRepository(model.PVZStatusActive), tt.productRepo,
+				&MockTransactor{}, &MockAuditService{}, &MockExpectedDeliveryService{}, config.ReceptionConfig{},
+			)
+			got, err := s.GetReception(context.Background(), closedReception.ID)
+
+			if !errors.Is(err, tt.expectedError) {
+				t.Errorf("Test %v: ReceptionService.GetReception() error = %v, expectedError %v", ttNum, err, tt.expectedError)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("Test %v: ReceptionService.GetReception() = %v, expected %v", ttNum, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestReceptionService_GetCurrentReception(t *testing.T) {
+	openReception := &model.Reception{
+		ID:       "123e4567-e89b-12d3-a456-426614174001",
+		DateTime: time.Now(),
+		PVZID:    "123e4567-e89b-12d3-a456-426614174000",
+		Status:   "in_progress",
+	}
+
+	t.Run("Success", func(t *testing.T) {
+		s := reception.NewReceptionService(
+			&MockReceptionRepository{
+				GetLastOpenReceptionFunc: func(ctx context.Context, pvzID string) (*model.Reception, error) {
+					return openReception, nil
+				},
+			},
+			&MockReceptionStatusRepository{},
+			newMockPVZRepository(model.PVZStatusActive),
+			&MockProductRepository{
+				GetProductsByReceptionIDInInsertionOrderFunc: func(ctx context.Context, receptionID string) ([]model.Product, error) {
+					return nil, nil
+				},
+			},
+			&MockTransactor{},
+			&MockAuditService{},
+			&MockExpectedDeliveryService{},
+			config.ReceptionConfig{},
+		)
+
+		got, err := s.GetCurrentReception(context.Background(), openReception.PVZID)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		expected := &dto.ReceptionWithProductsResponse{Reception: *openReception, Products: []model.Product{}}
+		if !reflect.DeepEqual(got, expected) {
+			t.Errorf("ReceptionService.GetCurrentReception() = %v, expected %v", got, expected)
+		}
+	})
+
+	t.Run("No Open Reception", func(t *testing.T) {
+		s := reception.NewReceptionService(
+			&MockReceptionRepository{
+				GetLastOpenReceptionFunc: func(ctx context.Context, pvzID string) (*model.Reception, error) {
+					return nil, model.ErrNoOpenReception
+				},
+			},
+			&MockReceptionStatusRepository{},
+			newMockPVZRepository(model.PVZStatusActive),
+			&MockProductRepository{},
+			&MockTransactor{},
+			&MockAuditService{},
+			&MockExpectedDeliveryService{},
+			config.ReceptionConfig{},
+		)
+
+		_, err := s.GetCurrentReception(context.Background(), openReception.PVZID)
+		if !errors.Is(err, model.ErrNoOpenReception) {
+			t.Errorf("ReceptionService.GetCurrentReception() error = %v, expected %v", err, model.ErrNoOpenReception)
+		}
+	})
+}
+
+func TestReceptionService_SetExpectedCount(t *testing.T) {
+	pvzID := "123e4567-e89b-12d3-a456-426614174000"
+	closedReception := &model.Reception{
+		ID:       "123e4567-e89b-12d3-a456-426614174001",
+		DateTime: time.Now(),
+		PVZID:    pvzID,
+		Status:   "close",
+	}
+
+	newRepo := func() *MockReceptionRepository {
+		return &MockReceptionRepository{
+			GetReceptionByIDFunc: func(ctx context.Context, receptionID string) (*model.Reception, error) {
+				return closedReception, nil
+			},
+			SetExpectedCountFunc: func(ctx context.Context, receptionID string, expectedCount int) (*model.Reception, error) {
+				updated := *closedReception
+				updated.ExpectedCount = &expectedCount
+				return &updated, nil
+			},
+		}
+	}
+
+	t.Run("Success", func(t *testing.T) {
+		var audited bool
+		s := reception.NewReceptionService(
+			newRepo(), &MockReceptionStatusRepository{}, newMockPVZRepository(model.PVZStatusActive),
+			&MockProductRepository{}, &MockTransactor{},
+			&MockAuditService{
+				RecordFunc: func(ctx context.Context, action model.AuditAction, entityType model.AuditEntityType, entityID string, before, after any) error {
+					audited = action == model.AuditActionUpdate && entityType == model.AuditEntityReception && entityID == closedReception.ID
+					return nil
+				},
+			},
+			&MockExpectedDeliveryService{}, config.ReceptionConfig{},
+		)
+
+		got, err := s.SetExpectedCount(context.Background(), pvzID, closedReception.ID, 7)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if got.ExpectedCount == nil || *got.ExpectedCount != 7 {
+			t.Errorf("ReceptionService.SetExpectedCount() expected count = %v, expected 7", got.ExpectedCount)
+		}
+		if !audited {
+			t.Error("ReceptionService.SetExpectedCount() did not record an audit entry")
+		}
+	})
+
+	t.Run("Reception Of Another PVZ", func(t *testing.T) {
+		repo := newRepo()
+		repo.SetExpectedCountFunc = func(ctx context.Context, receptionID string, expectedCount int) (*model.Reception, error) {
+			t.Fatal("SetExpectedCount must not be called for a reception of another PVZ")
+			return nil, nil
+		}
+
+		s := reception.NewReceptionService(
+			repo, &MockReceptionStatusRepository{}, newMockPVZRepository(model.PVZStatusActive), &MockProductRepository{},
+			&MockTransactor{}, &MockAuditService{}, &MockExpectedDeliveryService{}, config.ReceptionConfig{},
+		)
+
+		_, err := s.SetExpectedCount(context.Background(), "123e4567-e89b-12d3-a456-426614174009", closedReception.ID, 7)
+		if !errors.Is(err, model.ErrReceptionNotFound) {
+			t.Errorf("ReceptionService.SetExpectedCount() error = %v, expected %v", err, model.ErrReceptionNotFound)
+		}
+	})
+}
+
+func TestReceptionService_GetDiscrepancyReport(t *testing.T) {
+	pvzID := "123e4567-e89b-12d3-a456-426614174000"
+	receptionID := "123e4567-e89b-12d3-a456-426614174001"
+	expectedCount := 3
+	difference := -1
+
+	discrepancy := &model.ReceptionDiscrepancy{
+		ReceptionID:   receptionID,
+		PVZID:         pvzID,
+		DateTime:      time.Now(),
+		Status:        "close",
+		ExpectedCount: &expectedCount,
+		ReceivedCount: 2,
+		Difference:    &difference,
+		DamagedCount:  1,
+	}
+	damaged := model.Product{ID: "p2", ReceptionID: receptionID, Condition: model.ProductConditionDamaged, ConditionNote: "box torn"}
+
+	newService := func() *reception.ReceptionService {
+		return reception.NewReceptionService(
+			&MockReceptionRepository{
+				GetReceptionDiscrepancyFunc: func(ctx context.Context, id string) (*model.ReceptionDiscrepancy, error) {
+					return discrepancy, nil
+				},
+			},
+			&MockReceptionStatusRepository{},
+			newMockPVZRepository(model.PVZStatusActive),
+			&MockProductRepository{
+				GetProductsByReceptionIDFunc: func(ctx context.Context, id string) ([]model.Product, error) {
+					return []model.Product{
+						{ID: "p1", ReceptionID: receptionID, Condition: model.ProductConditionOK},
+						damaged,
+					}, nil
+				},
+			},
+			&MockTransactor{},
+			&MockAuditService{},
+			&MockExpectedDeliveryService{},
+			config.ReceptionConfig{},
+		)
+	}
+
+	t.Run("Success", func(t *testing.T) {
+		got, err := newService().GetDiscrepancyReport(context.Background(), pvzID, receptionID)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		expected := &dto.ReceptionDiscrepancyReport{
+			ReceptionDiscrepancy: *discrepancy,
+			HasDiscrepancy:       true,
+			Products:             []model.Product{damaged},
+		}
+		if !reflect.DeepEqual(got, expected) {
+			t.Errorf("ReceptionService.GetDiscrepancyReport() = %v, expected %v", got, expected)
+		}
+	})
+
+	t.Run("Reception Of Another PVZ", func(t *testing.T) {
+		_, err := newService().GetDiscrepancyReport(context.Background(), "123e4567-e89b-12d3-a456-426614174009", receptionID)
+		if !errors.Is(err, model.ErrReceptionNotFound) {
+			t.Errorf("ReceptionService.GetDiscrepancyReport() error = %v, expected %v", err, model.ErrReceptionNotFound)
+		}
+	})
+}
+
+func TestReceptionService_GetDiscrepancies(t *testing.T) {
+	t.Run("Invalid Date Range", func(t *testing.T) {
+		s := reception.NewReceptionService(
+			&MockReceptionRepository{}, &MockReceptionStatusRepository{}, newMockPVZRepository(model.PVZStatusActive), &MockProductRepository{},
+			&MockTransactor{}, &MockAuditService{}, &MockExpectedDeliveryService{}, config.ReceptionConfig{},
+		)
+
+		start := time.Now()
+		end := start.Add(-time.Hour)
+
+		_, err := s.GetDiscrepancies(context.Background(), dto.DiscrepancyFilterQuery{StartDate: &start, EndDate: &end, Page: 1, Limit: 10})
+		if !errors.Is(err, model.ErrInvalidDateRange) {
+			t.Errorf("ReceptionService.GetDiscrepancies() error = %v, expected %v", err, model.ErrInvalidDateRange)
+		}
+	})
+
+	t.Run("Success", func(t *testing.T) {
+		expected := []model.ReceptionDiscrepancy{{ReceptionID: "123e4567-e89b-12d3-a456-426614174001", OpenedCount: 1}}
+
+		s := reception.NewReceptionService(
+			&MockReceptionRepository{
+				GetReceptionDiscrepanciesFunc: func(ctx context.Context, filter dto.DiscrepancyFilterQuery) ([]model.ReceptionDiscrepancy, error) {
+					return expected, nil
+				},
+			},
+			&MockReceptionStatusRepository{},
+			newMockPVZRepository(model.PVZStatusActive), &MockProductRepository{}, &MockTransactor{}, &MockAuditService{},
+			&MockExpectedDeliveryService{}, config.ReceptionConfig{},
+		)
+
+		got, err := s.GetDiscrepancies(context.Background(), dto.DiscrepancyFilterQuery{Page: 1, Limit: 10})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !reflect.DeepEqual(got, expected) {
+			t.Errorf("ReceptionService.GetDiscrepancies() = %v, expected %v", got, expected)
+		}
+	})
+}
+
+func TestReceptionService_CreateReception_LinksExpectedDelivery(t *testing.T) {
+	pvzID := "123e4567-e89b-12d3-a456-426614174000"
+	created := &model.Reception{ID: "123e4567-e89b-12d3-a456-426614174001", PVZID: pvzID, Status: "in_progress"}
+
+	repo := &MockReceptionRepository{
+		CreateReceptionFunc: func(ctx context.Context, req dto.ReceptionCreateRequest) (*model.Reception, error) {
+			return created, nil
+		},
+	}
+
+	var linked *model.Reception
+	deliveryService := &MockExpectedDeliveryService{
+		LinkReceptionFunc: func(ctx context.Context, reception *model.Reception) (*model.ExpectedDelivery, error) {
+			linked = reception
+			return &model.ExpectedDelivery{ID: "123e4567-e89b-12d3-a456-426614174030", ReceptionID: reception.ID}, nil
+		},
+	}
+
+	s := reception.NewReceptionService(
+		repo, &MockReceptionStatusRepository{}, newMockPVZRepository(model.PVZStatusActive), &MockProductRepository{},
+		&MockTransactor{}, &MockAuditService{}, deliveryService, config.ReceptionConfig{},
+	)
+
+	got, err := s.CreateReception(context.Background(), dto.ReceptionCreateRequest{PVZID: pvzID})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(got, created) {
+		t.Errorf("Expected reception %v, got %v", created, got)
+	}
+	if linked != created {
+		t.Errorf("Expected the created reception to be linked, got %v", linked)
+	}
+
+	deliveryService.LinkReceptionFunc = func(ctx context.Context, reception *model.Reception) (*model.ExpectedDelivery, error) {
+		return nil, errors.New("database error")
+	}
+	if _, err := s.CreateReception(context.Background(), dto.ReceptionCreateRequest{PVZID: pvzID}); err == nil {
+		t.Error("expected linking error to fail the operation")
+	}
+}
+
+func TestReceptionService_CloseLastReception_Reconciliation(t *testing.T) {
+	pvzID := "123e4567-e89b-12d3-a456-426614174000"
+	closedReception := &model.Reception{ID: "123e4567-e89b-12d3-a456-426614174001", PVZID: pvzID, Status: "close"}
+	reconciliation := &model.DeliveryReconciliation{
+		DeliveryID:    "123e4567-e89b-12d3-a456-426614174030",
+		ExpectedCount: 2,
+		MatchedCount:  1,
+		Missing:       []model.ExpectedDeliveryItem{{Barcode: "4006381333948", Type: "electronics"}},
+		Unexpected:    []model.Product{},
+	}
+
+	repo := &MockReceptionRepository{
+		GetLastOpenReceptionFunc: func(ctx context.Context, pvzID string) (*model.Reception, error) {
+			return &model.Reception{ID: closedReception.ID, PVZID: pvzID, Status: "in_progress"}, nil
+		},
+		CloseReceptionFunc: func(ctx context.Context, receptionID string) (*model.Reception, error) {
+			return closedReception, nil
+		},
+	}
+
+	var reconciled *model.Reception
+	deliveryService := &MockExpectedDeliveryService{
+		ReconcileReceptionFunc: func(ctx context.Context, reception *model.Reception) (*model.DeliveryReconciliation, error) {
+			reconciled = reception
+			return reconciliation, nil
+		},
+	}
+
+	s := reception.NewReceptionService(
+		repo, &MockReceptionStatusRepository{}, newMockPVZRepository(model.PVZStatusActive), &MockProductRepository{},
+		&MockTransactor{}, &MockAuditService{}, deliveryService, config.ReceptionConfig{},
+	)
+
+	got, err := s.CloseLastReception(context.Background(), pvzID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := &dto.ReceptionCloseResponse{Reception: *closedReception, Reconciliation: reconciliation}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("Expected %v, got %v", expected, got)
+	}
+	if reconciled != closedReception {
+		t.Errorf("Expected the closed reception to be reconciled, got %v", reconciled)
+	}
+
+	deliveryService.ReconcileReceptionFunc = func(ctx context.Context, reception *model.Reception) (*model.DeliveryReconciliation, error) {
+		return nil, errors.New("database error")
+	}
+	if _, err := s.CloseLastReception(context.Background(), pvzID); err == nil {
+		t.Error("expected reconciliation error to fail the operation")
+	}
+}
+
+func TestReceptionService_CloseLastReception_RecordsStatusChange(t *testing.T) {
+	pvzID := "123e4567-e89b-12d3-a456-426614174000"
+	openReception := &model.Reception{ID: "123e4567-e89b-12d3-a456-426614174001", PVZID: pvzID, Status: "in_progress"}
+
+	repo := &MockReceptionRepository{
+		GetLastOpenReceptionFunc: func(ctx context.Context, pvzID string) (*model.Reception, error) {
+			return openReception, nil
+		},
+		CloseReceptionFunc: func(ctx context.Context, receptionID string) (*model.Reception, error) {
+			return &model.Reception{ID: receptionID, PVZID: pvzID, Status: "close"}, nil
+		},
+	}
+
+	var recorded []model.ReceptionStatusChange
+	statusRepo := &MockReceptionStatusRepository{
+		CreateReceptionStatusChangeFunc: func(ctx context.Context, change model.ReceptionStatusChange) (*model.ReceptionStatusChange, error) {
+			recorded = append(recorded, change)
+			return &change, nil
+		},
+	}
+
+	s := reception.NewReceptionService(
+		repo, statusRepo, newMockPVZRepository(model.PVZStatusActive), &MockProductRepository{},
+		&MockTransactor{}, &MockAuditService{}, &MockExpectedDeliveryService{}, config.ReceptionConfig{},
+	)
+
+	if _, err := s.CloseLastReception(context.Background(), pvzID); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(recorded) != 1 {
+		t.Fatalf("Expected 1 status change, got %d", len(recorded))
+	}
+	change := recorded[0]
+	if change.ReceptionID != openReception.ID || change.FromStatus != "in_progress" || change.ToStatus != "close" {
+		t.Errorf("Unexpected status change %+v", change)
+	}
+	if change.Actor.Type != model.SystemActor {
+		t.Errorf("Expected system actor without a request actor, got %v", change.Actor.Type)
+	}
+}
+
+func TestReceptionService_ReopenReception(t *testing.T) {
+	pvzID := "123e4567-e89b-12d3-a456-426614174000"
+	receptionID := "123e4567-e89b-12d3-a456-426614174001"
+	cfg := config.ReceptionConfig{ReopenWindow: time.Hour}
+
+	closedAt := func(ago time.Duration) *time.Time {
+		t := time.Now().Add(-ago)
+		return &t
+	}
+
+	newRepo := func(reception *model.Reception, hasNewer, hasProcessed bool) *MockReceptionRepository {
+		getReception := func(ctx context.Context, id string) (*model.Reception, error) {
+			if reception == nil {
+				return nil, model.ErrReceptionNotFound
+			}
+			return reception, nil
+		}
+
+		return &MockReceptionRepository{
+			GetReceptionByIDFunc:      getReception,
+			GetReceptionForUpdateFunc: getReception,
+			HasNewerReceptionFunc: func(ctx context.Context, reception *model.Reception) (bool, error) {
+				return hasNewer, nil
+			},
+			HasProcessedProductsFunc: func(ctx context.Context, id string) (bool, error) {
+				return hasProcessed, nil
+			},
+			ReopenReceptionFunc: func(ctx context.Context, id string) (*model.Reception, error) {
+				return &model.Reception{ID: id, PVZID: reception.PVZID, DateTime: reception.DateTime, Status: "in_progress", ClosedAt: reception.ClosedAt}, nil
+			},
+		}
+	}
+
+	t.Run("Success", func(t *testing.T) {
+		closedReception := &model.Reception{ID: receptionID, PVZID: pvzID, Status: "close", ClosedAt: closedAt(10 * time.Minute)}
+
+		var recorded model.ReceptionStatusChange
+		statusRepo := &MockReceptionStatusRepository{
+			CreateReceptionStatusChangeFunc: func(ctx context.Context, change model.ReceptionStatusChange) (*model.ReceptionStatusChange, error) {
+				recorded = change
+				return &change, nil
+			},
+		}
+
+		var auditedAction model.AuditAction
+		auditService := &MockAuditService{
+			RecordFunc: func(ctx context.Context, action model.AuditAction, entityType model.AuditEntityType, entityID string, before, after any) error {
+				if entityType == model.AuditEntityReception && entityID == receptionID {
+					auditedAction = action
+				}
+				return nil
+			},
+		}
+
+		var reopenedDelivery *model.Reception
+		deliveryService := &MockExpectedDeliveryService{
+			ReopenReceptionFunc: func(ctx context.Context, reception *model.Reception) error {
+				reopenedDelivery = reception
+				return nil
+			},
+		}
+
+		s := reception.NewReceptionService(
+			newRepo(closedReception, false, false), statusRepo, newMockPVZRepository(model.PVZStatusActive), &MockProductRepository{},
+			&MockTransactor{}, auditService, deliveryService, cfg,
+		)
+
+		got, err := s.ReopenReception(context.Background(), receptionID, "  products scanned after closing  ")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if got.Status != "in_progress" {
+			t.Errorf("Expected an open reception, got %+v", got)
+		}
+		if recorded.FromStatus != "close" || recorded.ToStatus != "in_progress" || recorded.Reason != "products scanned after closing" {
+			t.Errorf("Unexpected status change %+v", recorded)
+		}
+		if auditedAction != model.AuditActionStatusChange {
+			t.Errorf("Expected %v to be audited, got %v", model.AuditActionStatusChange, auditedAction)
+		}
+		if reopenedDelivery != got {
+			t.Errorf("Expected the expected delivery of the reopened reception to be reopened, got %v", reopenedDelivery)
+		}
+	})
+
+	tests := []struct {
+		name      string
+		reception *model.Reception
+		hasNewer  bool
+		processed bool
+		pvzStatus model.PVZStatus
+		reason    string
+		expected  error
+	}{
+		{
+			name:      "Reception Not Found",
+			pvzStatus: model.PVZStatusActive,
+			reason:    "wrong reception closed",
+			expected:  model.ErrReceptionNotFound,
+		},
+		{
+			name:      "Reception In Progress",
+			reception: &model.Reception{ID: receptionID, PVZID: pvzID, Status: "in_progress"},
+			pvzStatus: model.PVZStatusActive,
+			reason:    "wrong reception closed",
+			expected:  model.ErrReceptionNotClosed,
+		},
+		{
+			name:      "Window Expired",
+			reception: &model.Reception{ID: receptionID, PVZID: pvzID, Status: "close", ClosedAt: closedAt(2 * time.Hour)},
+			pvzStatus: model.PVZStatusActive,
+			reason:    "wrong reception closed",
+			expected:  model.ErrReceptionReopenExpired,
+		},
+		{
+			name:      "Newer Reception Exists",
+			reception: &model.Reception{ID: receptionID, PVZID: pvzID, Status: "close", ClosedAt: closedAt(time.Minute)},
+			hasNewer:  true,
+			pvzStatus: model.PVZStatusActive,
+			reason:    "wrong reception closed",
+			expected:  model.ErrNewerReceptionExists,
+		},
+		{
+			name:      "Products Processed",
+			reception: &model.Reception{ID: receptionID, PVZID: pvzID, Status: "close", ClosedAt: closedAt(time.Minute)},
+			processed: true,
+			pvzStatus: model.PVZStatusActive,
+			reason:    "wrong reception closed",
+			expected:  model.ErrReceptionProcessed,
+		},
+		{
+			name:      "PVZ Not Active",
+			reception: &model.Reception{ID: receptionID, PVZID: pvzID, Status: "close", ClosedAt: closedAt(time.Minute)},
+			pvzStatus: model.PVZStatusSuspended,
+			reason:    "wrong reception closed",
+			expected:  model.ErrPVZNotActive,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := reception.NewReceptionService(
+				newRepo(tt.reception, tt.hasNewer, tt.processed), &MockReceptionStatusRepository{}, newMockPVZRepository(tt.pvzStatus), &MockProductRepository{},
+				&MockTransactor{}, &MockAuditService{}, &MockExpectedDeliveryService{}, cfg,
+			)
+
+			_, err := s.ReopenReception(context.Background(), receptionID, tt.reason)
+			if !errors.Is(err, tt.expected) {
+				t.Errorf("ReceptionService.ReopenReception() error = %v, expected %v", err, tt.expected)
+			}
+		})
+	}
+
+	t.Run("Blank Reason", func(t *testing.T) {
+		s := reception.NewReceptionService(
+			&MockReceptionRepository{}, &MockReceptionStatusRepository{}, newMockPVZRepository(model.PVZStatusActive), &MockProductRepository{},
+			&MockTransactor{}, &MockAuditService{}, &MockExpectedDeliveryService{}, cfg,
+		)
+
+		if _, err := s.ReopenReception(context.Background(), receptionID, "   "); err == nil {
+			t.Error("expected an error for a blank reason")
+		}
+	})
+}
+
+func TestReceptionService_GetReceptionStatusHistory(t *testing.T) {
+	pvzID := "123e4567-e89b-12d3-a456-426614174000"
+	receptionID := "123e4567-e89b-12d3-a456-426614174001"
+	history := []model.ReceptionStatusChange{
+		{ID: 1, ReceptionID: receptionID, ToStatus: "in_progress"},
+		{ID: 2, ReceptionID: receptionID, FromStatus: "in_progress", ToStatus: "close"},
+	}
+
+	s := reception.NewReceptionService(
+		&MockReceptionRepository{
+			GetReceptionByIDFunc: func(ctx context.Context, id string) (*model.Reception, error) {
+				return &model.Reception{ID: id, PVZID: pvzID, Status: "close"}, nil
+			},
+		},
+		&MockReceptionStatusRepository{
+			GetReceptionStatusHistoryFunc: func(ctx context.Context, id string) ([]model.ReceptionStatusChange, error) {
+				return history, nil
+			},
+		},
+		newMockPVZRepository(model.PVZStatusActive), &MockProductRepository{}, &MockTransactor{}, &MockAuditService{},
+		&MockExpectedDeliveryService{}, config.ReceptionConfig{},
+	)
+
+	t.Run("Success", func(t *testing.T) {
+		got, err := s.GetReceptionStatusHistory(context.Background(), pvzID, receptionID)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !reflect.DeepEqual(got, history) {
+			t.Errorf("ReceptionService.GetReceptionStatusHistory() = %v, expected %v", got, history)
+		}
+	})
+
+	t.Run("Reception Of Another PVZ", func(t *testing.T) {
+		_, err := s.GetReceptionStatusHistory(context.Background(), "123e4567-e89b-12d3-a456-426614174099", receptionID)
+		if !errors.Is(err, model.ErrReceptionNotFound) {
+			t.Errorf("expected %v, got %v", model.ErrReceptionNotFound, err)
+		}
+	})
+}
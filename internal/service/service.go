@@ -1,25 +1,129 @@
 package service
 
 import (
+	"fmt"
+	"log"
+	"regexp"
+
+	"github.com/kirillidk/pvz-service/internal/config"
+	"github.com/kirillidk/pvz-service/internal/filestorage"
+	"github.com/kirillidk/pvz-service/internal/notifier"
 	"github.com/kirillidk/pvz-service/internal/repository"
+	"github.com/kirillidk/pvz-service/internal/service/apikey"
+	"github.com/kirillidk/pvz-service/internal/service/audit"
 	"github.com/kirillidk/pvz-service/internal/service/auth"
+	"github.com/kirillidk/pvz-service/internal/service/city"
+	"github.com/kirillidk/pvz-service/internal/service/expecteddelivery"
+	"github.com/kirillidk/pvz-service/internal/service/export"
+	"github.com/kirillidk/pvz-service/internal/service/idempotency"
+	"github.com/kirillidk/pvz-service/internal/service/order"
+	"github.com/kirillidk/pvz-service/internal/service/overdue"
 	"github.com/kirillidk/pvz-service/internal/service/product"
+	"github.com/kirillidk/pvz-service/internal/service/productinspection"
+	"github.com/kirillidk/pvz-service/internal/service/productlifecycle"
+	"github.com/kirillidk/pvz-service/internal/service/producttype"
 	"github.com/kirillidk/pvz-service/internal/service/pvz"
 	"github.com/kirillidk/pvz-service/internal/service/reception"
+	"github.com/kirillidk/pvz-service/internal/service/stats"
+	"github.com/kirillidk/pvz-service/internal/service/storagecell"
+	"github.com/kirillidk/pvz-service/internal/service/transfer"
 )
 
 type Service struct {
-	AuthService      *auth.AuthService
-	PVZService       *pvz.PVZService
-	ReceptionService *reception.ReceptionService
-	ProductService   *product.ProductService
+	AuthService              *auth.AuthService
+	PVZService               *pvz.PVZService
+	ReceptionService         *reception.ReceptionService
+	ProductService           *product.ProductService
+	ProductLifecycleService  *productlifecycle.ProductLifecycleService
+	ProductInspectionService *productinspection.ProductInspectionService
+	OrderService             *order.OrderService
+	OverdueService           *overdue.OverdueService
+	StorageCellService       *storagecell.StorageCellService
+	TransferService          *transfer.TransferService
+	ExpectedDeliveryService  *expecteddelivery.ExpectedDeliveryService
+	APIKeyService            *apikey.APIKeyService
+	AuditService             *audit.AuditService
+	CityService              *city.CityService
+	ProductTypeService       *producttype.ProductTypeService
+	StatsService             *stats.StatsService
+	ExportService            *export.ExportService
+	IdempotencyService       *idempotency.IdempotencyService
 }
 
-func NewService(repository *repository.Repository, jwtSecret string) *Service {
-	return &Service{
-		AuthService:      auth.NewAuthService(repository.UserRepository, jwtSecret),
-		PVZService:       pvz.NewPVZService(repository.PVZRepository, repository.ReceptionRepository, repository.ProductRepository),
-		ReceptionService: reception.NewReceptionService(repository.ReceptionRepository),
-		ProductService:   product.NewProductService(repository.ProductRepository, repository.ReceptionRepository),
+// NewService wires the services together. It fails on configuration values
+// that cannot be used, so that the process stops at startup.
+func NewService(repository *repository.Repository, cfg *config.Config) (*Service, error) {
+	auditService := audit.NewAuditService(repository.AuditRepository)
+	cityService := city.NewCityService(repository.CityRepository)
+	productTypeService := producttype.NewProductTypeService(repository.ProductTypeRepository)
+
+	var barcodePattern *regexp.Regexp
+	if cfg.Barcode.Pattern != "" {
+		var err error
+		barcodePattern, err = regexp.Compile(cfg.Barcode.Pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid barcode pattern: %w", err)
+		}
 	}
+
+	productLifecycleService := productlifecycle.NewProductLifecycleService(
+		repository.ProductRepository, repository.ProductStatusRepository, repository.Transactor, auditService,
+	)
+
+	storageCellService := storagecell.NewStorageCellService(
+		repository.StorageCellRepository, repository.ProductRepository, repository.Transactor, auditService,
+	)
+
+	// Pickup codes are only logged until an SMS gateway is connected.
+	pickupNotifier, err := notifier.New(cfg.Pickup.Notifier, log.Default())
+	if err != nil {
+		return nil, fmt.Errorf("failed to create pickup notifier: %w", err)
+	}
+
+	expectedDeliveryService := expecteddelivery.NewExpectedDeliveryService(
+		repository.ExpectedDeliveryRepository, repository.PVZRepository, repository.ProductRepository,
+		repository.ReceptionRepository, repository.Transactor, auditService, productTypeService,
+	)
+
+	attachmentStorage := filestorage.NewLocalStorage(cfg.Attachment.Dir)
+
+	return &Service{
+		AuthService: auth.NewAuthService(repository.UserRepository, cfg.JWT.JWTSecret, cfg.TwoFactor),
+		PVZService: pvz.NewPVZService(
+			repository.PVZRepository, repository.ReceptionRepository, repository.ProductRepository,
+			repository.Transactor, auditService, cityService,
+		),
+		ReceptionService: reception.NewReceptionService(
+			repository.ReceptionRepository, repository.ReceptionStatusRepository, repository.PVZRepository,
+			repository.ProductRepository, repository.Transactor, auditService, expectedDeliveryService, cfg.Reception,
+		),
+		ProductService: product.NewProductService(
+			repository.ProductRepository, repository.ReceptionRepository, repository.PVZRepository,
+			repository.Transactor, auditService, productTypeService, storageCellService, expectedDeliveryService,
+			barcodePattern,
+		),
+		ProductLifecycleService: productLifecycleService,
+		ProductInspectionService: productinspection.NewProductInspectionService(
+			repository.ProductRepository, repository.ProductAttachmentRepository, repository.Transactor, auditService,
+			attachmentStorage, int64(cfg.Attachment.MaxSizeMB)<<20,
+		),
+		OrderService: order.NewOrderService(
+			repository.OrderRepository, repository.ProductRepository, productLifecycleService,
+			repository.Transactor, auditService, pickupNotifier, cfg.Pickup,
+		),
+		OverdueService:     overdue.NewOverdueService(repository.ReturnTaskRepository, cfg.Storage),
+		StorageCellService: storageCellService,
+		TransferService: transfer.NewTransferService(
+			repository.TransferRepository, repository.ProductRepository, repository.ProductStatusRepository,
+			repository.PVZRepository, repository.ReceptionRepository, repository.Transactor, auditService,
+		),
+		ExpectedDeliveryService: expectedDeliveryService,
+		APIKeyService:           apikey.NewAPIKeyService(repository.APIKeyRepository),
+		AuditService:            auditService,
+		CityService:             cityService,
+		ProductTypeService:      productTypeService,
+		StatsService:            stats.NewStatsService(repository.StatsRepository, repository.PVZRepository),
+		ExportService:           export.NewExportService(repository.ExportRepository),
+		IdempotencyService:      idempotency.NewIdempotencyService(repository.IdempotencyRepository, cfg.Idempotency),
+	}, nil
 }
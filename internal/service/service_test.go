@@ -0,0 +1,55 @@
+package service_test
+
+import (
+	"testing"
+
+	"github.com/kirillidk/pvz-service/internal/config"
+	"github.com/kirillidk/pvz-service/internal/repository"
+	"github.com/kirillidk/pvz-service/internal/service"
+)
+
+func TestNewService(t *testing.T) {
+	tests := []struct {
+		name        string
+		modify      func(cfg *config.Config)
+		expectedErr bool
+	}{
+		{
+			name:   "Valid Config",
+			modify: func(cfg *config.Config) {},
+		},
+		{
+			name:   "Barcode Pattern",
+			modify: func(cfg *config.Config) { cfg.Barcode.Pattern = `^[A-Z]{2}\d{9}[A-Z]{2}$` },
+		},
+		{
+			name:        "Invalid Barcode Pattern",
+			modify:      func(cfg *config.Config) { cfg.Barcode.Pattern = `[A-Z` },
+			expectedErr: true,
+		},
+		{
+			name:        "Unknown Notifier",
+			modify:      func(cfg *config.Config) { cfg.Pickup.Notifier = "sms" },
+			expectedErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := config.NewConfig()
+			tt.modify(cfg)
+
+			serv, err := service.NewService(repository.NewRepository(nil), cfg)
+
+			if tt.expectedErr {
+				if err == nil || serv != nil {
+					t.Errorf("NewService() = %v, %v, expected an error", serv, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Errorf("NewService() unexpected error = %v", err)
+			}
+		})
+	}
+}
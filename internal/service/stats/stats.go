@@ -0,0 +1,98 @@
+package stats
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/kirillidk/pvz-service/internal/dto"
+	"github.com/kirillidk/pvz-service/internal/model"
+	"github.com/kirillidk/pvz-service/internal/repository"
+)
+
+// maxStatsDays bounds the requested period so that a single request cannot
+// aggregate the whole reception history.
+const maxStatsDays = 366
+
+type StatsServiceInterface interface {
+	GetDailyStats(ctx context.Context, query dto.DailyStatsQuery) ([]model.DailyReceptionStats, error)
+	GetPVZDailyStats(ctx context.Context, pvzID string, query dto.DailyStatsQuery) ([]model.DailyReceptionStats, error)
+	GetReceptionThroughput(ctx context.Context, query dto.ReceptionThroughputQuery) ([]model.ReceptionThroughputStats, error)
+}
+
+type StatsService struct {
+	statsRepository repository.StatsRepositoryInterface
+	pvzRepository   repository.PVZRepositoryInterface
+}
+
+func NewStatsService(
+	statsRepo repository.StatsRepositoryInterface,
+	pvzRepo repository.PVZRepositoryInterface,
+) *StatsService {
+	return &StatsService{
+		statsRepository: statsRepo,
+		pvzRepository:   pvzRepo,
+	}
+}
+
+func (s *StatsService) GetDailyStats(ctx context.Context, query dto.DailyStatsQuery) ([]model.DailyReceptionStats, error) {
+	if err := validatePeriod(query.From, query.To); err != nil {
+		return nil, err
+	}
+
+	query.PVZID = ""
+
+	stats, err := s.statsRepository.GetDailyReceptionStats(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get daily stats: %w", err)
+	}
+
+	return stats, nil
+}
+
+func (s *StatsService) GetPVZDailyStats(ctx context.Context, pvzID string, query dto.DailyStatsQuery) ([]model.DailyReceptionStats, error) {
+	if err := validatePeriod(query.From, query.To); err != nil {
+		return nil, err
+	}
+
+	if _, err := s.pvzRepository.GetPVZByID(ctx, pvzID); err != nil {
+		return nil, fmt.Errorf("failed to get PVZ: %w", err)
+	}
+
+	query.PVZID = pvzID
+
+	stats, err := s.statsRepository.GetDailyReceptionStats(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get daily stats: %w", err)
+	}
+
+	return stats, nil
+}
+
+func (s *StatsService) GetReceptionThroughput(
+	ctx context.Context,
+	query dto.ReceptionThroughputQuery,
+) ([]model.ReceptionThroughputStats, error) {
+	if err := validatePeriod(query.From, query.To); err != nil {
+		return nil, err
+	}
+
+	stats, err := s.statsRepository.GetReceptionThroughputStats(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get reception throughput: %w", err)
+	}
+
+	return stats, nil
+}
+
+func validatePeriod(from, to time.Time) error {
+	if to.Before(from) {
+		return fmt.Errorf("%w: 'to' must not be before 'from'", model.ErrInvalidDateRange)
+	}
+
+	if to.Sub(from).Hours()/24 >= maxStatsDays {
+		return fmt.Errorf("%w: period must not exceed %d days", model.ErrInvalidDateRange, maxStatsDays)
+	}
+
+	return nil
+}
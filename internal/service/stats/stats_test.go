@@ -0,0 +1,227 @@
+package stats_test
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/kirillidk/pvz-service/internal/dto"
+	"github.com/kirillidk/pvz-service/internal/model"
+	"github.com/kirillidk/pvz-service/internal/service/stats"
+)
+
+type MockPVZRepository struct {
+	CreatePVZFunc  func(ctx context.Context, req dto.PVZCreateRequest) (*model.PVZ, error)
+	GetPVZListFunc func(ctx context.Context, filter dto.PVZFilterQuery) ([]model.PVZ, error)
+	GetPVZByIDFunc func(ctx context.Context, pvzID string) (*model.PVZ, error)
+	UpdatePVZFunc  func(ctx context.Context, pvzID string, req dto.PVZUpdateRequest) (*model.PVZ, error)
+
+	GetNearbyPVZListFunc func(ctx context.Context, query dto.PVZNearbyQuery) ([]model.NearbyPVZ, error)
+	GetPVZForUpdateFunc  func(ctx context.Context, pvzID string) (*model.PVZ, error)
+	UpdatePVZStatusFunc  func(ctx context.Context, pvzID string, status model.PVZStatus) (*model.PVZ, error)
+	StreamPVZListFunc    func(ctx context.Context, filter dto.PVZFilterQuery, fn func(model.PVZ, *model.Reception, *model.Product) error) error
+}
+
+func (m *MockPVZRepository) CreatePVZ(ctx context.Context, req dto.PVZCreateRequest) (*model.PVZ, error) {
+	return m.CreatePVZFunc(ctx, req)
+}
+
+func (m *MockPVZRepository) GetPVZList(ctx context.Context, filter dto.PVZFilterQuery) ([]model.PVZ, error) {
+	return m.GetPVZListFunc(ctx, filter)
+}
+
+func (m *MockPVZRepository) GetPVZByID(ctx context.Context, pvzID string) (*model.PVZ, error) {
+	return m.GetPVZByIDFunc(ctx, pvzID)
+}
+
+func (m *MockPVZRepository) UpdatePVZ(ctx context.Context, pvzID string, req dto.PVZUpdateRequest) (*model.PVZ, error) {
+	return m.UpdatePVZFunc(ctx, pvzID, req)
+}
+
+func (m *MockPVZRepository) GetNearbyPVZList(ctx context.Context, query dto.PVZNearbyQuery) ([]model.NearbyPVZ, error) {
+	return m.GetNearbyPVZListFunc(ctx, query)
+}
+
+func (m *MockPVZRepository) GetPVZForUpdate(ctx context.Context, pvzID string) (*model.PVZ, error) {
+	return m.GetPVZForUpdateFunc(ctx, pvzID)
+}
+
+func (m *MockPVZRepository) UpdatePVZStatus(ctx context.Context, pvzID string, status model.PVZStatus) (*model.PVZ, error) {
+	return m.UpdatePVZStatusFunc(ctx, pvzID, status)
+}
+
+func (m *MockPVZRepository) StreamPVZList(ctx context.Context, filter dto.PVZFilterQuery, fn func(model.PVZ, *model.Reception, *model.Product) error) error {
+	return m.StreamPVZListFunc(ctx, filter, fn)
+}
+
+type MockStatsRepository struct {
+	GetDailyReceptionStatsFunc      func(ctx context.Context, query dto.DailyStatsQuery) ([]model.DailyReceptionStats, error)
+	GetReceptionThroughputStatsFunc func(ctx context.Context, query dto.ReceptionThroughputQuery) ([]model.ReceptionThroughputStats, error)
+}
+
+func (m *MockStatsRepository) GetDailyReceptionStats(ctx context.Context, query dto.DailyStatsQuery) ([]model.DailyReceptionStats, error) {
+	return m.GetDailyReceptionStatsFunc(ctx, query)
+}
+
+func (m *MockStatsRepository) GetReceptionThroughputStats(ctx context.Context, query dto.ReceptionThroughputQuery) ([]model.ReceptionThroughputStats, error) {
+	return m.GetReceptionThroughputStatsFunc(ctx, query)
+}
+
+func TestStatsService_GetDailyStats(t *testing.T) {
+	ctx := context.Background()
+	from := time.Date(2025, 4, 1, 0, 0, 0, 0, time.UTC)
+
+	dailyStats := []model.DailyReceptionStats{
+		{Date: "2025-04-01", PVZID: "pvz-id", ReceptionCount: 1, ProductsByType: map[string]int64{}},
+	}
+
+	tests := []struct {
+		name          string
+		query         dto.DailyStatsQuery
+		mockStatsRepo *MockStatsRepository
+		expected      []model.DailyReceptionStats
+		expectedError error
+	}{
+		{
+			name:  "Success",
+			query: dto.DailyStatsQuery{From: from, To: from.AddDate(0, 0, 6), PVZID: "ignored"},
+			mockStatsRepo: &MockStatsRepository{
+				GetDailyReceptionStatsFunc: func(ctx context.Context, query dto.DailyStatsQuery) ([]model.DailyReceptionStats, error) {
+					if query.PVZID != "" {
+						return nil, errors.New("unexpected pvz filter")
+					}
+					return dailyStats, nil
+				},
+			},
+			expected: dailyStats,
+		},
+		{
+			name:          "To Before From",
+			query:         dto.DailyStatsQuery{From: from, To: from.AddDate(0, 0, -1)},
+			mockStatsRepo: &MockStatsRepository{},
+			expectedError: model.ErrInvalidDateRange,
+		},
+		{
+			name:          "Period Too Long",
+			query:         dto.DailyStatsQuery{From: from, To: from.AddDate(1, 1, 0)},
+			mockStatsRepo: &MockStatsRepository{},
+			expectedError: model.ErrInvalidDateRange,
+		},
+		{
+			name:  "Repository Error",
+			query: dto.DailyStatsQuery{From: from, To: from},
+			mockStatsRepo: &MockStatsRepository{
+				GetDailyReceptionStatsFunc: func(ctx context.Context, query dto.DailyStatsQuery) ([]model.DailyReceptionStats, error) {
+					return nil, errors.New("db error")
+				},
+			},
+			expectedError: errors.New("failed to get daily stats: db error"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := stats.NewStatsService(tt.mockStatsRepo, &MockPVZRepository{})
+
+			got, err := s.GetDailyStats(ctx, tt.query)
+
+			if tt.expectedError != nil {
+				if err == nil || (!errors.Is(err, tt.expectedError) && err.Error() != tt.expectedError.Error()) {
+					t.Errorf("StatsService.GetDailyStats() error = %v, expectedError %v", err, tt.expectedError)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Errorf("StatsService.GetDailyStats() unexpected error = %v", err)
+			}
+
+			if !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("StatsService.GetDailyStats() = %v, expected %v", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestStatsService_GetPVZDailyStats(t *testing.T) {
+	ctx := context.Background()
+	from := time.Date(2025, 4, 1, 0, 0, 0, 0, time.UTC)
+	query := dto.DailyStatsQuery{From: from, To: from.AddDate(0, 0, 6)}
+
+	t.Run("Success", func(t *testing.T) {
+		pvzRepo := &MockPVZRepository{
+			GetPVZByIDFunc: func(ctx context.Context, pvzID string) (*model.PVZ, error) {
+				return &model.PVZ{ID: pvzID}, nil
+			},
+		}
+		statsRepo := &MockStatsRepository{
+			GetDailyReceptionStatsFunc: func(ctx context.Context, query dto.DailyStatsQuery) ([]model.DailyReceptionStats, error) {
+				return []model.DailyReceptionStats{{Date: "2025-04-01", PVZID: query.PVZID}}, nil
+			},
+		}
+
+		got, err := stats.NewStatsService(statsRepo, pvzRepo).GetPVZDailyStats(ctx, "pvz-id", query)
+		if err != nil {
+			t.Fatalf("StatsService.GetPVZDailyStats() unexpected error = %v", err)
+		}
+
+		expected := []model.DailyReceptionStats{{Date: "2025-04-01", PVZID: "pvz-id"}}
+		if !reflect.DeepEqual(got, expected) {
+			t.Errorf("StatsService.GetPVZDailyStats() = %v, expected %v", got, expected)
+		}
+	})
+
+	t.Run("PVZ Not Found", func(t *testing.T) {
+		pvzRepo := &MockPVZRepository{
+			GetPVZByIDFunc: func(ctx context.Context, pvzID string) (*model.PVZ, error) {
+				return nil, model.ErrPVZNotFound
+			},
+		}
+
+		got, err := stats.NewStatsService(&MockStatsRepository{}, pvzRepo).GetPVZDailyStats(ctx, "pvz-id", query)
+		if !errors.Is(err, model.ErrPVZNotFound) {
+			t.Errorf("StatsService.GetPVZDailyStats() error = %v, expected %v", err, model.ErrPVZNotFound)
+		}
+		if got != nil {
+			t.Errorf("StatsService.GetPVZDailyStats() = %v, expected nil", got)
+		}
+	})
+}
+
+func TestStatsService_GetReceptionThroughput(t *testing.T) {
+	ctx := context.Background()
+	from := time.Date(2025, 4, 1, 0, 0, 0, 0, time.UTC)
+	productsPerMinute := 2.0
+
+	throughput := []model.ReceptionThroughputStats{
+		{City: "Казань", ReceptionCount: 3, ProductCount: 60, MedianDurationSeconds: 600, P95DurationSeconds: 900, ProductsPerMinute: &productsPerMinute},
+	}
+
+	t.Run("Success", func(t *testing.T) {
+		statsRepo := &MockStatsRepository{
+			GetReceptionThroughputStatsFunc: func(ctx context.Context, query dto.ReceptionThroughputQuery) ([]model.ReceptionThroughputStats, error) {
+				return throughput, nil
+			},
+		}
+
+		query := dto.ReceptionThroughputQuery{From: from, To: from.AddDate(0, 0, 29), GroupBy: dto.ThroughputGroupByCity}
+		got, err := stats.NewStatsService(statsRepo, &MockPVZRepository{}).GetReceptionThroughput(ctx, query)
+		if err != nil {
+			t.Fatalf("StatsService.GetReceptionThroughput() unexpected error = %v", err)
+		}
+
+		if !reflect.DeepEqual(got, throughput) {
+			t.Errorf("StatsService.GetReceptionThroughput() = %v, expected %v", got, throughput)
+		}
+	})
+
+	t.Run("Invalid Period", func(t *testing.T) {
+		query := dto.ReceptionThroughputQuery{From: from, To: from.AddDate(0, 0, -1)}
+		_, err := stats.NewStatsService(&MockStatsRepository{}, &MockPVZRepository{}).GetReceptionThroughput(ctx, query)
+		if !errors.Is(err, model.ErrInvalidDateRange) {
+			t.Errorf("StatsService.GetReceptionThroughput() error = %v, expected %v", err, model.ErrInvalidDateRange)
+		}
+	})
+}
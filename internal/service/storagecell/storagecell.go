@@ -0,0 +1,261 @@
+package storagecell
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/kirillidk/pvz-service/internal/dto"
+	"github.com/kirillidk/pvz-service/internal/model"
+	"github.com/kirillidk/pvz-service/internal/repository"
+	"github.com/kirillidk/pvz-service/internal/service/audit"
+)
+
+type StorageCellServiceInterface interface {
+	CreateStorageCell(ctx context.Context, pvzID string, cellReq dto.StorageCellCreateRequest) (*model.StorageCell, error)
+	GetStorageCells(ctx context.Context, pvzID string) ([]model.StorageCell, error)
+	UpdateStorageCell(ctx context.Context, pvzID, cellID string, cellReq dto.StorageCellUpdateRequest) (*model.StorageCell, error)
+	DeleteStorageCell(ctx context.Context, pvzID, cellID string) error
+	GetStorageCellOccupancy(ctx context.Context, pvzID string) ([]model.StorageCellOccupancy, error)
+	AssignProductToCell(ctx context.Context, pvzID, productID, cellID string) (*model.Product, error)
+	CheckCellPlacement(ctx context.Context, pvzID, cellID string, productTypes ...string) error
+	SuggestStorageCell(ctx context.Context, pvzID, productType string) (*model.StorageCell, error)
+}
+
+type StorageCellService struct {
+	storageCellRepository repository.StorageCellRepositoryInterface
+	productRepository     repository.ProductRepositoryInterface
+	transactor            repository.TransactorInterface
+	auditService          audit.AuditServiceInterface
+}
+
+func NewStorageCellService(
+	storageCellRepo repository.StorageCellRepositoryInterface,
+	productRepo repository.ProductRepositoryInterface,
+	transactor repository.TransactorInterface,
+	auditService audit.AuditServiceInterface,
+) *StorageCellService {
+	return &StorageCellService{
+		storageCellRepository: storageCellRepo,
+		productRepository:     productRepo,
+		transactor:            transactor,
+		auditService:          auditService,
+	}
+}
+
+func (s *StorageCellService) CreateStorageCell(
+	ctx context.Context,
+	pvzID string,
+	cellReq dto.StorageCellCreateRequest,
+) (*model.StorageCell, error) {
+	var cell *model.StorageCell
+
+	err := s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		var err error
+		cell, err = s.storageCellRepository.CreateStorageCell(ctx, pvzID, cellReq)
+		if err != nil {
+			return fmt.Errorf("failed to create storage cell: %w", err)
+		}
+
+		return s.auditService.Record(ctx, model.AuditActionCreate, model.AuditEntityStorageCell, cell.ID, nil, cell)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return cell, nil
+}
+
+func (s *StorageCellService) GetStorageCells(ctx context.Context, pvzID string) ([]model.StorageCell, error) {
+	cells, err := s.storageCellRepository.GetStorageCellsByPVZID(ctx, pvzID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get storage cells: %w", err)
+	}
+
+	return cells, nil
+}
+
+// UpdateStorageCell changes the cell. The capacity cannot drop below the
+// number of products kept in the cell, and the product type cannot be
+// changed while the cell holds products of another type.
+func (s *StorageCellService) UpdateStorageCell(
+	ctx context.Context,
+	pvzID, cellID string,
+	cellReq dto.StorageCellUpdateRequest,
+) (*model.StorageCell, error) {
+	if cellReq.Code == nil && cellReq.Capacity == nil && cellReq.ProductType == nil {
+		return nil, errors.New("no fields to update")
+	}
+
+	var updatedCell *model.StorageCell
+
+	err := s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		cell, err := s.getStorageCellForUpdate(ctx, pvzID, cellID)
+		if err != nil {
+			return err
+		}
+
+		occupied, err := s.storageCellRepository.CountProductsInCell(ctx, cellID)
+		if err != nil {
+			return err
+		}
+
+		if cellReq.Capacity != nil && *cellReq.Capacity < occupied {
+			return fmt.Errorf("cannot reduce capacity below the %d products kept in the cell", occupied)
+		}
+
+		if cellReq.ProductType != nil && *cellReq.ProductType != "" && *cellReq.ProductType != cell.ProductType && occupied > 0 {
+			return errors.New("cannot change product type of a cell that is not empty")
+		}
+
+		updatedCell, err = s.storageCellRepository.UpdateStorageCell(ctx, cellID, cellReq)
+		if err != nil {
+			return fmt.Errorf("failed to update storage cell: %w", err)
+		}
+
+		return s.auditService.Record(ctx, model.AuditActionUpdate, model.AuditEntityStorageCell, cellID, cell, updatedCell)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return updatedCell, nil
+}
+
+// DeleteStorageCell deletes an empty cell. Issued and returned products
+// that were kept in it lose their cell.
+func (s *StorageCellService) DeleteStorageCell(ctx context.Context, pvzID, cellID string) error {
+	return s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		cell, err := s.getStorageCellForUpdate(ctx, pvzID, cellID)
+		if err != nil {
+			return err
+		}
+
+		occupied, err := s.storageCellRepository.CountProductsInCell(ctx, cellID)
+		if err != nil {
+			return err
+		}
+
+		if occupied > 0 {
+			return fmt.Errorf("cannot delete a cell with %d products kept in it", occupied)
+		}
+
+		if err := s.storageCellRepository.DeleteStorageCell(ctx, cellID); err != nil {
+			return fmt.Errorf("failed to delete storage cell: %w", err)
+		}
+
+		return s.auditService.Record(ctx, model.AuditActionDelete, model.AuditEntityStorageCell, cellID, cell, nil)
+	})
+}
+
+func (s *StorageCellService) GetStorageCellOccupancy(ctx context.Context, pvzID string) ([]model.StorageCellOccupancy, error) {
+	occupancy, err := s.storageCellRepository.GetStorageCellOccupancy(ctx, pvzID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get storage cell occupancy: %w", err)
+	}
+
+	return occupancy, nil
+}
+
+// AssignProductToCell moves a product waiting at the PVZ to the cell.
+func (s *StorageCellService) AssignProductToCell(ctx context.Context, pvzID, productID, cellID string) (*model.Product, error) {
+	var updatedProduct *model.Product
+
+	err := s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		location, err := s.productRepository.GetProductLocationForUpdate(ctx, productID)
+		if err != nil {
+			return fmt.Errorf("failed to get product: %w", err)
+		}
+
+		if location.PVZID != pvzID {
+			return fmt.Errorf("failed to get product: %w", model.ErrProductNotFound)
+		}
+
+		product := location.Product
+
+		if product.Status != model.ProductStatusReceived && product.Status != model.ProductStatusStored {
+			return fmt.Errorf("cannot place a product that is %s", product.Status)
+		}
+
+		if product.CellID == cellID {
+			return errors.New("product is already in this cell")
+		}
+
+		if err := s.CheckCellPlacement(ctx, pvzID, cellID, product.Type); err != nil {
+			return err
+		}
+
+		updatedProduct, err = s.productRepository.UpdateProductCell(ctx, productID, cellID)
+		if err != nil {
+			return fmt.Errorf("failed to update product cell: %w", err)
+		}
+
+		return s.auditService.Record(ctx, model.AuditActionUpdate, model.AuditEntityProduct, productID, product, updatedProduct)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return updatedProduct, nil
+}
+
+// CheckCellPlacement checks that products of productTypes, one per entry,
+// can be placed into the cell of the PVZ. The cell stays locked until the
+// end of the surrounding transaction, so the products must be placed
+// within it.
+func (s *StorageCellService) CheckCellPlacement(ctx context.Context, pvzID, cellID string, productTypes ...string) error {
+	return s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		cell, err := s.getStorageCellForUpdate(ctx, pvzID, cellID)
+		if err != nil {
+			return err
+		}
+
+		if cell.ProductType != "" {
+			for _, productType := range productTypes {
+				if productType != cell.ProductType {
+					return fmt.Errorf("%w: %s only takes products of type %s", model.ErrStorageCellTypeMismatch, cell.Code, cell.ProductType)
+				}
+			}
+		}
+
+		occupied, err := s.storageCellRepository.CountProductsInCell(ctx, cellID)
+		if err != nil {
+			return err
+		}
+
+		if occupied+len(productTypes) > cell.Capacity {
+			return fmt.Errorf("%w: %s", model.ErrStorageCellFull, cell.Code)
+		}
+
+		return nil
+	})
+}
+
+// SuggestStorageCell returns the least full cell of the PVZ that fits a
+// product of productType, or nil when no cell has room for it.
+func (s *StorageCellService) SuggestStorageCell(ctx context.Context, pvzID, productType string) (*model.StorageCell, error) {
+	cell, err := s.storageCellRepository.GetLeastOccupiedCell(ctx, pvzID, productType)
+	if err != nil {
+		if errors.Is(err, model.ErrStorageCellNotFound) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to suggest storage cell: %w", err)
+	}
+
+	return cell, nil
+}
+
+// getStorageCellForUpdate locks the cell, reporting cells of other PVZs as
+// not found.
+func (s *StorageCellService) getStorageCellForUpdate(ctx context.Context, pvzID, cellID string) (*model.StorageCell, error) {
+	cell, err := s.storageCellRepository.GetStorageCellForUpdate(ctx, cellID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get storage cell: %w", err)
+	}
+
+	if cell.PVZID != pvzID {
+		return nil, fmt.Errorf("failed to get storage cell: %w", model.ErrStorageCellNotFound)
+	}
+
+	return cell, nil
+}
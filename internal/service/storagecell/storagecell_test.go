@@ -0,0 +1,417 @@
+package storagecell_test
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/kirillidk/pvz-service/internal/dto"
+	"github.com/kirillidk/pvz-service/internal/model"
+	"github.com/kirillidk/pvz-service/internal/service/storagecell"
+)
+
+const (
+	pvzID     = "123e4567-e89b-12d3-a456-426614174003"
+	cellID    = "123e4567-e89b-12d3-a456-426614174005"
+	productID = "123e4567-e89b-12d3-a456-426614174001"
+)
+
+type MockStorageCellRepository struct {
+	CreateStorageCellFunc       func(ctx context.Context, pvzID string, req dto.StorageCellCreateRequest) (*model.StorageCell, error)
+	GetStorageCellsByPVZIDFunc  func(ctx context.Context, pvzID string) ([]model.StorageCell, error)
+	GetStorageCellForUpdateFunc func(ctx context.Context, cellID string) (*model.StorageCell, error)
+	UpdateStorageCellFunc       func(ctx context.Context, cellID string, req dto.StorageCellUpdateRequest) (*model.StorageCell, error)
+	DeleteStorageCellFunc       func(ctx context.Context, cellID string) error
+	CountProductsInCellFunc     func(ctx context.Context, cellID string) (int, error)
+	GetStorageCellOccupancyFunc func(ctx context.Context, pvzID string) ([]model.StorageCellOccupancy, error)
+	GetLeastOccupiedCellFunc    func(ctx context.Context, pvzID, productType string) (*model.StorageCell, error)
+}
+
+func (m *MockStorageCellRepository) CreateStorageCell(ctx context.Context, pvzID string, req dto.StorageCellCreateRequest) (*model.StorageCell, error) {
+	return m.CreateStorageCellFunc(ctx, pvzID, req)
+}
+
+func (m *MockStorageCellRepository) GetStorageCellsByPVZID(ctx context.Context, pvzID string) ([]model.StorageCell, error) {
+	return m.GetStorageCellsByPVZIDFunc(ctx, pvzID)
+}
+
+func (m *MockStorageCellRepository) GetStorageCellForUpdate(ctx context.Context, cellID string) (*model.StorageCell, error) {
+	return m.GetStorageCellForUpdateFunc(ctx, cellID)
+}
+
+func (m *MockStorageCellRepository) UpdateStorageCell(ctx context.Context, cellID string, req dto.StorageCellUpdateRequest) (*model.StorageCell, error) {
+	return m.UpdateStorageCellFunc(ctx, cellID, req)
+}
+
+func (m *MockStorageCellRepository) DeleteStorageCell(ctx context.Context, cellID string) error {
+	return m.DeleteStorageCellFunc(ctx, cellID)
+}
+
+func (m *MockStorageCellRepository) CountProductsInCell(ctx context.Context, cellID string) (int, error) {
+	return m.CountProductsInCellFunc(ctx, cellID)
+}
+
+func (m *MockStorageCellRepository) GetStorageCellOccupancy(ctx context.Context, pvzID string) ([]model.StorageCellOccupancy, error) {
+	return m.GetStorageCellOccupancyFunc(ctx, pvzID)
+}
+
+func (m *MockStorageCellRepository) GetLeastOccupiedCell(ctx context.Context, pvzID, productType string) (*model.StorageCell, error) {
+	return m.GetLeastOccupiedCellFunc(ctx, pvzID, productType)
+}
+
+// newMockStorageCellRepository returns a repository with a single cell of
+// the PVZ holding occupied products.
+func newMockStorageCellRepository(cell model.StorageCell, occupied int) *MockStorageCellRepository {
+	return &MockStorageCellRepository{
+		GetStorageCellForUpdateFunc: func(ctx context.Context, id string) (*model.StorageCell, error) {
+			if id != cell.ID {
+				return nil, model.ErrStorageCellNotFound
+			}
+			return &cell, nil
+		},
+		CountProductsInCellFunc: func(ctx context.Context, cellID string) (int, error) {
+			return occupied, nil
+		},
+		UpdateStorageCellFunc: func(ctx context.Context, cellID string, req dto.StorageCellUpdateRequest) (*model.StorageCell, error) {
+			updated := cell
+			if req.Capacity != nil {
+				updated.Capacity = *req.Capacity
+			}
+			return &updated, nil
+		},
+		DeleteStorageCellFunc: func(ctx context.Context, cellID string) error {
+			return nil
+		},
+	}
+}
+
+type MockProductRepository struct {
+	GetProductLocationForUpdateFunc func(ctx context.Context, productID string) (*model.ProductLocation, error)
+	UpdateProductCellFunc           func(ctx context.Context, productID, cellID string) (*model.Product, error)
+}
+
+func (m *MockProductRepository) CreateProduct(ctx context.Context, receptionID string, attrs dto.ProductAttributes) (*model.Product, error) {
+	return nil, nil
+}
+
+func (m *MockProductRepository) CreateProducts(ctx context.Context, receptionID string, items []dto.ProductAttributes) ([]model.Product, error) {
+	return nil, nil
+}
+
+func (m *MockProductRepository) GetLastProductInReception(ctx context.Context, receptionID string) (*model.Product, error) {
+	return nil, nil
+}
+
+func (m *MockProductRepository) DeleteProduct(ctx context.Context, productID string) error {
+	return nil
+}
+
+func (m *MockProductRepository) GetProductsByReceptionID(ctx context.Context, receptionID string) ([]model.Product, error) {
+	return nil, nil
+}
+
+func (m *MockProductRepository) GetProductsByReceptionIDInInsertionOrder(ctx context.Context, receptionID string) ([]model.Product, error) {
+	return nil, nil
+}
+
+func (m *MockProductRepository) GetProductsByBarcodes(ctx context.Context, barcodes []string) ([]model.ProductLocation, error) {
+	return nil, nil
+}
+
+func (m *MockProductRepository) GetProductLocation(ctx context.Context, productID string) (*model.ProductLocation, error) {
+	return nil, nil
+}
+
+func (m *MockProductRepository) GetProductLocationForUpdate(ctx context.Context, productID string) (*model.ProductLocation, error) {
+	return m.GetProductLocationForUpdateFunc(ctx, productID)
+}
+
+func (m *MockProductRepository) UpdateProductStatus(ctx context.Context, productID string, status model.ProductStatus) (*model.Product, error) {
+	return nil, nil
+}
+
+func (m *MockProductRepository) UpdateProductCell(ctx context.Context, productID, cellID string) (*model.Product, error) {
+	return m.UpdateProductCellFunc(ctx, productID, cellID)
+}
+
+func (m *MockProductRepository) UpdateProductCondition(
+	ctx context.Context,
+	productID string,
+	condition model.ProductCondition,
+	note string,
+) (*model.Product, error) {
+	return nil, nil
+}
+
+type MockTransactor struct{}
+
+func (m *MockTransactor) WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	return fn(ctx)
+}
+
+type MockAuditService struct {
+	Recorded []model.AuditEntityType
+}
+
+func (m *MockAuditService) Record(
+	ctx context.Context,
+	action model.AuditAction,
+	entityType model.AuditEntityType,
+	entityID string,
+	before, after any,
+) error {
+	m.Recorded = append(m.Recorded, entityType)
+	return nil
+}
+
+func (m *MockAuditService) GetAuditLog(ctx context.Context, filter dto.AuditFilterQuery) ([]model.AuditEntry, error) {
+	return nil, nil
+}
+
+func TestStorageCellService_CheckCellPlacement(t *testing.T) {
+	tests := []struct {
+		name          string
+		cell          model.StorageCell
+		occupied      int
+		pvzID         string
+		productTypes  []string
+		expectedError error
+		errorMessage  string
+	}{
+		{
+			name:         "Fits",
+			cell:         model.StorageCell{ID: cellID, PVZID: pvzID, Code: "A-01", Capacity: 10},
+			occupied:     8,
+			pvzID:        pvzID,
+			productTypes: []string{"обувь", "одежда"},
+		},
+		{
+			name:          "Full",
+			cell:          model.StorageCell{ID: cellID, PVZID: pvzID, Code: "A-01", Capacity: 10},
+			occupied:      9,
+			pvzID:         pvzID,
+			productTypes:  []string{"обувь", "обувь"},
+			expectedError: model.ErrStorageCellFull,
+		},
+		{
+			name:         "Wrong Product Type",
+			cell:         model.StorageCell{ID: cellID, PVZID: pvzID, Code: "A-01", Capacity: 10, ProductType: "обувь"},
+			pvzID:        pvzID,
+			productTypes: []string{"обувь", "одежда"},
+			errorMessage: "storage cell does not take products of this type: A-01 only takes products of type обувь",
+		},
+		{
+			name:          "Cell Of Another PVZ",
+			cell:          model.StorageCell{ID: cellID, PVZID: "223e4567-e89b-12d3-a456-426614174003", Code: "A-01", Capacity: 10},
+			pvzID:         pvzID,
+			productTypes:  []string{"обувь"},
+			expectedError: model.ErrStorageCellNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := storagecell.NewStorageCellService(
+				newMockStorageCellRepository(tt.cell, tt.occupied), &MockProductRepository{}, &MockTransactor{}, &MockAuditService{},
+			)
+			err := s.CheckCellPlacement(context.Background(), tt.pvzID, cellID, tt.productTypes...)
+
+			switch {
+			case tt.expectedError != nil:
+				if !errors.Is(err, tt.expectedError) {
+					t.Errorf("CheckCellPlacement() error = %v, expected %v", err, tt.expectedError)
+				}
+			case tt.errorMessage != "":
+				if err == nil || err.Error() != tt.errorMessage {
+					t.Errorf("CheckCellPlacement() error = %v, expected %q", err, tt.errorMessage)
+				}
+			case err != nil:
+				t.Errorf("CheckCellPlacement() unexpected error = %v", err)
+			}
+		})
+	}
+}
+
+func TestStorageCellService_UpdateStorageCell(t *testing.T) {
+	cell := model.StorageCell{ID: cellID, PVZID: pvzID, Code: "A-01", Capacity: 10, ProductType: "обувь"}
+	smaller, larger := 4, 20
+	otherType := "одежда"
+
+	tests := []struct {
+		name          string
+		req           dto.StorageCellUpdateRequest
+		occupied      int
+		expectedError bool
+	}{
+		{name: "No Fields", req: dto.StorageCellUpdateRequest{}, expectedError: true},
+		{name: "Grow Capacity", req: dto.StorageCellUpdateRequest{Capacity: &larger}, occupied: 5},
+		{name: "Capacity Below Occupancy", req: dto.StorageCellUpdateRequest{Capacity: &smaller}, occupied: 5, expectedError: true},
+		{name: "Change Type Of Empty Cell", req: dto.StorageCellUpdateRequest{ProductType: &otherType}},
+		{name: "Change Type Of Occupied Cell", req: dto.StorageCellUpdateRequest{ProductType: &otherType}, occupied: 1, expectedError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			auditService := &MockAuditService{}
+			s := storagecell.NewStorageCellService(
+				newMockStorageCellRepository(cell, tt.occupied), &MockProductRepository{}, &MockTransactor{}, auditService,
+			)
+			_, err := s.UpdateStorageCell(context.Background(), pvzID, cellID, tt.req)
+
+			if (err != nil) != tt.expectedError {
+				t.Errorf("UpdateStorageCell() error = %v, expectedError %v", err, tt.expectedError)
+				return
+			}
+			if !tt.expectedError && !reflect.DeepEqual(auditService.Recorded, []model.AuditEntityType{model.AuditEntityStorageCell}) {
+				t.Errorf("Expected the storage cell change to be audited, got %v", auditService.Recorded)
+			}
+		})
+	}
+}
+
+func TestStorageCellService_DeleteStorageCell(t *testing.T) {
+	cell := model.StorageCell{ID: cellID, PVZID: pvzID, Code: "A-01", Capacity: 10}
+
+	tests := []struct {
+		name          string
+		occupied      int
+		expectedError bool
+	}{
+		{name: "Empty Cell"},
+		{name: "Occupied Cell", occupied: 3, expectedError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			deleted := false
+			repo := newMockStorageCellRepository(cell, tt.occupied)
+			repo.DeleteStorageCellFunc = func(ctx context.Context, cellID string) error {
+				deleted = true
+				return nil
+			}
+
+			s := storagecell.NewStorageCellService(repo, &MockProductRepository{}, &MockTransactor{}, &MockAuditService{})
+			err := s.DeleteStorageCell(context.Background(), pvzID, cellID)
+
+			if (err != nil) != tt.expectedError {
+				t.Errorf("DeleteStorageCell() error = %v, expectedError %v", err, tt.expectedError)
+			}
+			if deleted == tt.expectedError {
+				t.Errorf("Expected deleted = %v, got %v", !tt.expectedError, deleted)
+			}
+		})
+	}
+}
+
+func TestStorageCellService_AssignProductToCell(t *testing.T) {
+	cell := model.StorageCell{ID: cellID, PVZID: pvzID, Code: "A-01", Capacity: 10}
+
+	tests := []struct {
+		name          string
+		location      model.ProductLocation
+		expectedError bool
+	}{
+		{
+			name: "Success",
+			location: model.ProductLocation{
+				Product: model.Product{ID: productID, Type: "обувь", Status: model.ProductStatusStored},
+				PVZID:   pvzID,
+			},
+		},
+		{
+			name: "Product Of Another PVZ",
+			location: model.ProductLocation{
+				Product: model.Product{ID: productID, Type: "обувь", Status: model.ProductStatusStored},
+				PVZID:   "223e4567-e89b-12d3-a456-426614174003",
+			},
+			expectedError: true,
+		},
+		{
+			name: "Issued Product",
+			location: model.ProductLocation{
+				Product: model.Product{ID: productID, Type: "обувь", Status: model.ProductStatusIssued},
+				PVZID:   pvzID,
+			},
+			expectedError: true,
+		},
+		{
+			name: "Already In Cell",
+			location: model.ProductLocation{
+				Product: model.Product{ID: productID, Type: "обувь", Status: model.ProductStatusReceived, CellID: cellID},
+				PVZID:   pvzID,
+			},
+			expectedError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			productRepo := &MockProductRepository{
+				GetProductLocationForUpdateFunc: func(ctx context.Context, productID string) (*model.ProductLocation, error) {
+					location := tt.location
+					return &location, nil
+				},
+				UpdateProductCellFunc: func(ctx context.Context, productID, cellID string) (*model.Product, error) {
+					product := tt.location.Product
+					product.CellID = cellID
+					return &product, nil
+				},
+			}
+
+			auditService := &MockAuditService{}
+			s := storagecell.NewStorageCellService(
+				newMockStorageCellRepository(cell, 2), productRepo, &MockTransactor{}, auditService,
+			)
+			product, err := s.AssignProductToCell(context.Background(), pvzID, productID, cellID)
+
+			if (err != nil) != tt.expectedError {
+				t.Errorf("AssignProductToCell() error = %v, expectedError %v", err, tt.expectedError)
+				return
+			}
+			if tt.expectedError {
+				return
+			}
+			if product.CellID != cellID {
+				t.Errorf("Expected product in cell %s, got %q", cellID, product.CellID)
+			}
+			if !reflect.DeepEqual(auditService.Recorded, []model.AuditEntityType{model.AuditEntityProduct}) {
+				t.Errorf("Expected the product change to be audited, got %v", auditService.Recorded)
+			}
+		})
+	}
+}
+
+func TestStorageCellService_SuggestStorageCell(t *testing.T) {
+	tests := []struct {
+		name          string
+		cell          *model.StorageCell
+		repoErr       error
+		expectedError bool
+	}{
+		{name: "Suggests Cell", cell: &model.StorageCell{ID: cellID, PVZID: pvzID, Code: "A-01", Capacity: 10}},
+		{name: "No Free Cell", repoErr: model.ErrStorageCellNotFound},
+		{name: "Repository Error", repoErr: errors.New("db error"), expectedError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &MockStorageCellRepository{
+				GetLeastOccupiedCellFunc: func(ctx context.Context, pvzID, productType string) (*model.StorageCell, error) {
+					return tt.cell, tt.repoErr
+				},
+			}
+
+			s := storagecell.NewStorageCellService(repo, &MockProductRepository{}, &MockTransactor{}, &MockAuditService{})
+			cell, err := s.SuggestStorageCell(context.Background(), pvzID, "обувь")
+
+			if (err != nil) != tt.expectedError {
+				t.Errorf("SuggestStorageCell() error = %v, expectedError %v", err, tt.expectedError)
+				return
+			}
+			if !reflect.DeepEqual(cell, tt.cell) {
+				t.Errorf("SuggestStorageCell() = %v, expected %v", cell, tt.cell)
+			}
+		})
+	}
+}
@@ -0,0 +1,289 @@
+package transfer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/kirillidk/pvz-service/internal/dto"
+	"github.com/kirillidk/pvz-service/internal/model"
+	"github.com/kirillidk/pvz-service/internal/repository"
+	"github.com/kirillidk/pvz-service/internal/requestctx"
+	"github.com/kirillidk/pvz-service/internal/service/audit"
+)
+
+type TransferServiceInterface interface {
+	CreateTransfer(ctx context.Context, pvzID string, transferCreateReq dto.TransferCreateRequest) (*model.Transfer, error)
+	ShipTransfer(ctx context.Context, pvzID, transferID string) (*model.Transfer, error)
+	ReceiveTransfer(ctx context.Context, pvzID, transferID string) (*model.Transfer, error)
+	GetTransfers(ctx context.Context, pvzID string) ([]model.Transfer, error)
+	GetTransfer(ctx context.Context, pvzID, transferID string) (*model.Transfer, error)
+	GetProductTransfers(ctx context.Context, pvzID, productID string) ([]model.Transfer, error)
+}
+
+type TransferService struct {
+	transferRepository      repository.TransferRepositoryInterface
+	productRepository       repository.ProductRepositoryInterface
+	productStatusRepository repository.ProductStatusRepositoryInterface
+	pvzRepository           repository.PVZRepositoryInterface
+	receptionRepository     repository.ReceptionRepositoryInterface
+	transactor              repository.TransactorInterface
+	auditService            audit.AuditServiceInterface
+}
+
+func NewTransferService(
+	transferRepo repository.TransferRepositoryInterface,
+	productRepo repository.ProductRepositoryInterface,
+	productStatusRepo repository.ProductStatusRepositoryInterface,
+	pvzRepo repository.PVZRepositoryInterface,
+	receptionRepo repository.ReceptionRepositoryInterface,
+	transactor repository.TransactorInterface,
+	auditService audit.AuditServiceInterface,
+) *TransferService {
+	return &TransferService{
+		transferRepository:      transferRepo,
+		productRepository:       productRepo,
+		productStatusRepository: productStatusRepo,
+		pvzRepository:           pvzRepo,
+		receptionRepository:     receptionRepo,
+		transactor:              transactor,
+		auditService:            auditService,
+	}
+}
+
+// CreateTransfer reserves stored products of the PVZ for forwarding to
+// another active PVZ. Products of an order cannot be transferred.
+func (s *TransferService) CreateTransfer(
+	ctx context.Context,
+	pvzID string,
+	transferCreateReq dto.TransferCreateRequest,
+) (*model.Transfer, error) {
+	if transferCreateReq.DestinationPVZID == pvzID {
+		return nil, errors.New("cannot transfer products to the same PVZ")
+	}
+
+	seen := make(map[string]bool, len(transferCreateReq.ProductIDs))
+	for _, productID := range transferCreateReq.ProductIDs {
+		if seen[productID] {
+			return nil, fmt.Errorf("product %s is listed more than once", productID)
+		}
+		seen[productID] = true
+	}
+
+	var createdTransfer *model.Transfer
+
+	err := s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		destination, err := s.pvzRepository.GetPVZByID(ctx, transferCreateReq.DestinationPVZID)
+		if err != nil {
+			return fmt.Errorf("failed to get destination PVZ: %w", err)
+		}
+
+		if destination.Status != model.PVZStatusActive {
+			return fmt.Errorf("destination %w", model.ErrPVZNotActive)
+		}
+
+		for _, productID := range transferCreateReq.ProductIDs {
+			location, err := s.productRepository.GetProductLocationForUpdate(ctx, productID)
+			if err != nil {
+				return fmt.Errorf("failed to get product: %w", err)
+			}
+
+			if location.PVZID != pvzID {
+				return fmt.Errorf("failed to get product: %w", model.ErrProductNotFound)
+			}
+
+			if location.OrderID != "" {
+				return model.ErrProductInOrder
+			}
+
+			if location.TransferID != "" {
+				return model.ErrProductInTransfer
+			}
+
+			if location.Status != model.ProductStatusStored {
+				return fmt.Errorf("product %s is %s, only stored products can be transferred", productID, location.Status)
+			}
+		}
+
+		createdTransfer, err = s.transferRepository.CreateTransfer(ctx, model.Transfer{
+			SourcePVZID:      pvzID,
+			DestinationPVZID: transferCreateReq.DestinationPVZID,
+			ProductIDs:       transferCreateReq.ProductIDs,
+			CreatedAt:        time.Now().UTC(),
+		})
+		if err != nil {
+			return fmt.Errorf("failed to create transfer: %w", err)
+		}
+
+		return s.auditService.Record(ctx, model.AuditActionCreate, model.AuditEntityTransfer, createdTransfer.ID, nil, createdTransfer)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return createdTransfer, nil
+}
+
+// ShipTransfer sends the products of the transfer from the source PVZ.
+func (s *TransferService) ShipTransfer(ctx context.Context, pvzID, transferID string) (*model.Transfer, error) {
+	return s.advanceTransfer(ctx, pvzID, transferID, model.TransferStatusInTransit)
+}
+
+// ReceiveTransfer accepts the products of the transfer at the destination
+// PVZ into its open reception. The destination must be active.
+func (s *TransferService) ReceiveTransfer(ctx context.Context, pvzID, transferID string) (*model.Transfer, error) {
+	return s.advanceTransfer(ctx, pvzID, transferID, model.TransferStatusReceived)
+}
+
+// advanceTransfer moves the transfer to status on behalf of the PVZ
+// responsible for that step and records the status change of each of its
+// products.
+func (s *TransferService) advanceTransfer(
+	ctx context.Context,
+	pvzID, transferID string,
+	status model.TransferStatus,
+) (*model.Transfer, error) {
+	var updatedTransfer *model.Transfer
+
+	err := s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		transfer, err := s.transferRepository.GetTransferForUpdate(ctx, transferID)
+		if err != nil {
+			return fmt.Errorf("failed to get transfer: %w", err)
+		}
+
+		if err := checkTransferPVZ(transfer, pvzID); err != nil {
+			return err
+		}
+
+		now := time.Now().UTC()
+		updated := *transfer
+		updated.Status = status
+
+		var fromStatus, toStatus model.ProductStatus
+
+		switch status {
+		case model.TransferStatusInTransit:
+			if transfer.SourcePVZID != pvzID {
+				return errors.New("only the source PVZ can ship the transfer")
+			}
+			if transfer.Status != model.TransferStatusCreated {
+				return fmt.Errorf("cannot ship a transfer that is %s", transfer.Status)
+			}
+
+			if err := s.transferRepository.MarkTransferShipped(ctx, transferID, now); err != nil {
+				return fmt.Errorf("failed to ship transfer: %w", err)
+			}
+
+			updated.ShippedAt = &now
+			fromStatus, toStatus = model.ProductStatusStored, model.ProductStatusInTransit
+		case model.TransferStatusReceived:
+			if transfer.DestinationPVZID != pvzID {
+				return errors.New("only the destination PVZ can receive the transfer")
+			}
+			if transfer.Status != model.TransferStatusInTransit {
+				return fmt.Errorf("cannot receive a transfer that is %s", transfer.Status)
+			}
+
+			pvz, err := s.pvzRepository.GetPVZForUpdate(ctx, pvzID)
+			if err != nil {
+				return fmt.Errorf("failed to get PVZ: %w", err)
+			}
+
+			if pvz.Status != model.PVZStatusActive {
+				return model.ErrPVZNotActive
+			}
+
+			reception, err := s.receptionRepository.GetLastOpenReceptionForUpdate(ctx, pvzID)
+			if err != nil {
+				return fmt.Errorf("failed to find open reception: %w", err)
+			}
+
+			if err := s.transferRepository.MarkTransferReceived(ctx, transferID, reception.ID, now); err != nil {
+				return fmt.Errorf("failed to receive transfer: %w", err)
+			}
+
+			updated.ReceptionID = reception.ID
+			updated.ReceivedAt = &now
+			fromStatus, toStatus = model.ProductStatusInTransit, model.ProductStatusReceived
+		}
+
+		actor := requestctx.ActorFromContext(ctx)
+		for _, productID := range transfer.ProductIDs {
+			_, err := s.productStatusRepository.CreateProductStatusChange(ctx, model.ProductStatusChange{
+				ProductID:  productID,
+				FromStatus: fromStatus,
+				ToStatus:   toStatus,
+				ChangedAt:  now,
+				Actor:      actor,
+			})
+			if err != nil {
+				return fmt.Errorf("failed to record product status change: %w", err)
+			}
+		}
+
+		updatedTransfer = &updated
+
+		return s.auditService.Record(ctx, model.AuditActionStatusChange, model.AuditEntityTransfer, transferID, transfer, updatedTransfer)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return updatedTransfer, nil
+}
+
+// GetTransfers returns the transfers leaving or arriving at the PVZ.
+func (s *TransferService) GetTransfers(ctx context.Context, pvzID string) ([]model.Transfer, error) {
+	transfers, err := s.transferRepository.GetTransfersByPVZID(ctx, pvzID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get transfers: %w", err)
+	}
+
+	return transfers, nil
+}
+
+func (s *TransferService) GetTransfer(ctx context.Context, pvzID, transferID string) (*model.Transfer, error) {
+	transfer, err := s.transferRepository.GetTransfer(ctx, transferID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get transfer: %w", err)
+	}
+
+	if err := checkTransferPVZ(transfer, pvzID); err != nil {
+		return nil, err
+	}
+
+	return transfer, nil
+}
+
+// GetProductTransfers traces the product across PVZs. Both the PVZ keeping
+// the product and the PVZs it passed through may see its transfers.
+func (s *TransferService) GetProductTransfers(ctx context.Context, pvzID, productID string) ([]model.Transfer, error) {
+	location, err := s.productRepository.GetProductLocation(ctx, productID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get product: %w", err)
+	}
+
+	transfers, err := s.transferRepository.GetTransfersByProductID(ctx, productID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get product transfers: %w", err)
+	}
+
+	visible := location.PVZID == pvzID || slices.ContainsFunc(transfers, func(transfer model.Transfer) bool {
+		return transfer.SourcePVZID == pvzID || transfer.DestinationPVZID == pvzID
+	})
+	if !visible {
+		return nil, fmt.Errorf("failed to get product: %w", model.ErrProductNotFound)
+	}
+
+	return transfers, nil
+}
+
+// checkTransferPVZ reports transfers between other PVZs as not found.
+func checkTransferPVZ(transfer *model.Transfer, pvzID string) error {
+	if transfer.SourcePVZID != pvzID && transfer.DestinationPVZID != pvzID {
+		return fmt.Errorf("failed to get transfer: %w", model.ErrTransferNotFound)
+	}
+	return nil
+}
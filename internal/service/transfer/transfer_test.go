@@ -0,0 +1,599 @@
+package transfer_test
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/kirillidk/pvz-service/internal/dto"
+	"github.com/kirillidk/pvz-service/internal/model"
+	"github.com/kirillidk/pvz-service/internal/service/transfer"
+)
+
+const (
+	sourcePVZID      = "123e4567-e89b-12d3-a456-426614174003"
+	destinationPVZID = "223e4567-e89b-12d3-a456-426614174003"
+	transferID       = "123e4567-e89b-12d3-a456-426614174008"
+	productID        = "123e4567-e89b-12d3-a456-426614174001"
+	receptionID      = "223e4567-e89b-12d3-a456-426614174002"
+)
+
+type MockTransferRepository struct {
+	CreateTransferFunc          func(ctx context.Context, transfer model.Transfer) (*model.Transfer, error)
+	GetTransferFunc             func(ctx context.Context, transferID string) (*model.Transfer, error)
+	GetTransfersByPVZIDFunc     func(ctx context.Context, pvzID string) ([]model.Transfer, error)
+	GetTransfersByProductIDFunc func(ctx context.Context, productID string) ([]model.Transfer, error)
+
+	Shipped  bool
+	Received string
+}
+
+func (m *MockTransferRepository) CreateTransfer(ctx context.Context, transfer model.Transfer) (*model.Transfer, error) {
+	return m.CreateTransferFunc(ctx, transfer)
+}
+
+func (m *MockTransferRepository) GetTransfer(ctx context.Context, transferID string) (*model.Transfer, error) {
+	return m.GetTransferFunc(ctx, transferID)
+}
+
+func (m *MockTransferRepository) GetTransferForUpdate(ctx context.Context, transferID string) (*model.Transfer, error) {
+	return m.GetTransferFunc(ctx, transferID)
+}
+
+func (m *MockTransferRepository) GetTransfersByPVZID(ctx context.Context, pvzID string) ([]model.Transfer, error) {
+	return m.GetTransfersByPVZIDFunc(ctx, pvzID)
+}
+
+func (m *MockTransferRepository) GetTransfersByProductID(ctx context.Context, productID string) ([]model.Transfer, error) {
+	return m.GetTransfersByProductIDFunc(ctx, productID)
+}
+
+func (m *MockTransferRepository) MarkTransferShipped(ctx context.Context, transferID string, shippedAt time.Time) error {
+	m.Shipped = true
+	return nil
+}
+
+func (m *MockTransferRepository) MarkTransferReceived(ctx context.Context, transferID, receptionID string, receivedAt time.Time) error {
+	m.Received = receptionID
+	return nil
+}
+
+type MockProductRepository struct {
+	GetProductLocationFunc func(ctx context.Context, productID string) (*model.ProductLocation, error)
+}
+
+func (m *MockProductRepository) CreateProduct(ctx context.Context, receptionID string, attrs dto.ProductAttributes) (*model.Product, error) {
+	return nil, nil
+}
+
+func (m *MockProductRepository) CreateProducts(ctx context.Context, receptionID string, items []dto.ProductAttributes) ([]model.Product, error) {
+	return nil, nil
+}
+
+func (m *MockProductRepository) GetLastProductInReception(ctx context.Context, receptionID string) (*model.Product, error) {
+	return nil, nil
+}
+
+func (m *MockProductRepository) DeleteProduct(ctx context.Context, productID string) error {
+	return nil
+}
+
+func (m *MockProductRepository) GetProductsByReceptionID(ctx context.Context, receptionID string) ([]model.Product, error) {
+	return nil, nil
+}
+
+func (m *MockProductRepository) GetProductsByReceptionIDInInsertionOrder(ctx context.Context, receptionID string) ([]model.Product, error) {
+	return nil, nil
+}
+
+func (m *MockProductRepository) GetProductsByBarcodes(ctx context.Context, barcodes []string) ([]model.ProductLocation, error) {
+	return nil, nil
+}
+
+func (m *MockProductRepository) GetProductLocation(ctx context.Context, productID string) (*model.ProductLocation, error) {
+	return m.GetProductLocationFunc(ctx, productID)
+}
+
+func (m *MockProductRepository) GetProductLocationForUpdate(ctx context.Context, productID string) (*model.ProductLocation, error) {
+	return m.GetProductLocationFunc(ctx, productID)
+}
+
+func (m *MockProductRepository) UpdateProductStatus(ctx context.Context, productID string, status model.ProductStatus) (*model.Product, error) {
+	return nil, nil
+}
+
+func (m *MockProductRepository) UpdateProductCell(ctx context.Context, productID, cellID string) (*model.Product, error) {
+	return nil, nil
+}
+
+func (m *MockProductRepository) UpdateProductCondition(
+	ctx context.Context,
+	productID string,
+	condition model.ProductCondition,
+	note string,
+) (*model.Product, error) {
+	return nil, nil
+}
+
+type MockProductStatusRepository struct {
+	Changes []model.ProductStatusChange
+}
+
+func (m *MockProductStatusRepository) CreateProductStatusChange(
+	ctx context.Context,
+	change model.ProductStatusChange,
+) (*model.ProductStatusChange, error) {
+	m.Changes = append(m.Changes, change)
+	return &change, nil
+}
+
+func (m *MockProductStatusRepository) GetProductStatusHistory(ctx context.Context, productID string) ([]model.ProductStatusChange, error) {
+	return nil, nil
+}
+
+type MockPVZRepository struct {
+	GetPVZByIDFunc      func(ctx context.Context, pvzID string) (*model.PVZ, error)
+	GetPVZForUpdateFunc func(ctx context.Context, pvzID string) (*model.PVZ, error)
+}
+
+func (m *MockPVZRepository) CreatePVZ(ctx context.Context, req dto.PVZCreateRequest) (*model.PVZ, error) {
+	return nil, nil
+}
+
+func (m *MockPVZRepository) GetPVZList(ctx context.Context, filter dto.PVZFilterQuery) ([]model.PVZ, error) {
+	return nil, nil
+}
+
+func (m *MockPVZRepository) GetPVZByID(ctx context.Context, pvzID string) (*model.PVZ, error) {
+	return m.GetPVZByIDFunc(ctx, pvzID)
+}
+
+func (m *MockPVZRepository) UpdatePVZ(ctx context.Context, pvzID string, req dto.PVZUpdateRequest) (*model.PVZ, error) {
+	return nil, nil
+}
+
+func (m *MockPVZRepository) GetNearbyPVZList(ctx context.Context, query dto.PVZNearbyQuery) ([]model.NearbyPVZ, error) {
+	return nil, nil
+}
+
+func (m *MockPVZRepository) GetPVZForUpdate(ctx context.Context, pvzID string) (*model.PVZ, error) {
+	return m.GetPVZForUpdateFunc(ctx, pvzID)
+}
+
+func (m *MockPVZRepository) UpdatePVZStatus(ctx context.Context, pvzID string, status model.PVZStatus) (*model.PVZ, error) {
+	return nil, nil
+}
+
+func (m *MockPVZRepository) StreamPVZList(ctx context.Context, filter dto.PVZFilterQuery, fn func(model.PVZ, *model.Reception, *model.Product) error) error {
+	return nil
+}
+
+type MockReceptionRepository struct {
+	GetLastOpenReceptionForUpdateFunc func(ctx context.Context, pvzID string) (*model.Reception, error)
+}
+
+func (m *MockReceptionRepository) CreateReception(ctx context.Context, req dto.ReceptionCreateRequest) (*model.Reception, error) {
+	return nil, nil
+}
+
+func (m *MockReceptionRepository) HasOpenReception(ctx context.Context, pvzID string) (bool, error) {
+	return false, nil
+}
+
+func (m *MockReceptionRepository) GetLastOpenReception(ctx context.Context, pvzID string) (*model.Reception, error) {
+	return nil, nil
+}
+
+func (m *MockReceptionRepository) GetLastOpenReceptionForUpdate(ctx context.Context, pvzID string) (*model.Reception, error) {
+	return m.GetLastOpenReceptionForUpdateFunc(ctx, pvzID)
+}
+
+func (m *MockReceptionRepository) GetReceptionByID(ctx context.Context, receptionID string) (*model.Reception, error) {
+	return nil, nil
+}
+
+func (m *MockReceptionRepository) CloseReception(ctx context.Context, receptionID string) (*model.Reception, error) {
+	return nil, nil
+}
+
+func (m *MockReceptionRepository) GetReceptionForUpdate(ctx context.Context, receptionID string) (*model.Reception, error) {
+	return nil, nil
+}
+
+func (m *MockReceptionRepository) HasNewerReception(ctx context.Context, reception *model.Reception) (bool, error) {
+	return false, nil
+}
+
+func (m *MockReceptionRepository) HasProcessedProducts(ctx context.Context, receptionID string) (bool, error) {
+	return false, nil
+}
+
+func (m *MockReceptionRepository) ReopenReception(ctx context.Context, receptionID string) (*model.Reception, error) {
+	return nil, nil
+}
+
+func (m *MockReceptionRepository) GetReceptionsByPVZID(ctx context.Context, pvzID string, filter dto.ReceptionFilter) ([]model.Reception, error) {
+	return nil, nil
+}
+
+func (m *MockReceptionRepository) SetExpectedCount(ctx context.Context, receptionID string, expectedCount int) (*model.Reception, error) {
+	return nil, nil
+}
+
+func (m *MockReceptionRepository) GetReceptionDiscrepancy(ctx context.Context, receptionID string) (*model.ReceptionDiscrepancy, error) {
+	return nil, nil
+}
+
+func (m *MockReceptionRepository) GetReceptionDiscrepancies(
+	ctx context.Context,
+	filter dto.DiscrepancyFilterQuery,
+) ([]model.ReceptionDiscrepancy, error) {
+	return nil, nil
+}
+
+type MockTransactor struct{}
+
+func (m *MockTransactor) WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	return fn(ctx)
+}
+
+type MockAuditService struct {
+	Actions []model.AuditAction
+}
+
+func (m *MockAuditService) Record(
+	ctx context.Context,
+	action model.AuditAction,
+	entityType model.AuditEntityType,
+	entityID string,
+	before, after any,
+) error {
+	m.Actions = append(m.Actions, action)
+	return nil
+}
+
+func (m *MockAuditService) GetAuditLog(ctx context.Context, filter dto.AuditFilterQuery) ([]model.AuditEntry, error) {
+	return nil, nil
+}
+
+func activePVZ(ctx context.Context, pvzID string) (*model.PVZ, error) {
+	return &model.PVZ{ID: pvzID, Status: model.PVZStatusActive}, nil
+}
+
+func TestTransferService_CreateTransfer(t *testing.T) {
+	tests := []struct {
+		name          string
+		destination   string
+		productIDs    []string
+		location      *model.ProductLocation
+		getPVZ        func(ctx context.Context, pvzID string) (*model.PVZ, error)
+		expectedError error
+		expectedMsg   string
+	}{
+		{
+			name:        "Success",
+			destination: destinationPVZID,
+			productIDs:  []string{productID},
+			location: &model.ProductLocation{
+				Product: model.Product{ID: productID, Status: model.ProductStatusStored},
+				PVZID:   sourcePVZID,
+			},
+		},
+		{
+			name:        "Same PVZ",
+			destination: sourcePVZID,
+			productIDs:  []string{productID},
+			expectedMsg: "cannot transfer products to the same PVZ",
+		},
+		{
+			name:        "Duplicate Product",
+			destination: destinationPVZID,
+			productIDs:  []string{productID, productID},
+			expectedMsg: "product 123e4567-e89b-12d3-a456-426614174001 is listed more than once",
+		},
+		{
+			name:        "Destination Not Active",
+			destination: destinationPVZID,
+			productIDs:  []string{productID},
+			getPVZ: func(ctx context.Context, pvzID string) (*model.PVZ, error) {
+				return &model.PVZ{ID: pvzID, Status: model.PVZStatusSuspended}, nil
+			},
+			expectedError: model.ErrPVZNotActive,
+		},
+		{
+			name:        "Received Product",
+			destination: destinationPVZID,
+			productIDs:  []string{productID},
+			location: &model.ProductLocation{
+				Product: model.Product{ID: productID, Status: model.ProductStatusReceived},
+				PVZID:   sourcePVZID,
+			},
+			expectedMsg: "product 123e4567-e89b-12d3-a456-426614174001 is received, only stored products can be transferred",
+		},
+		{
+			name:        "Product Of Another PVZ",
+			destination: destinationPVZID,
+			productIDs:  []string{productID},
+			location: &model.ProductLocation{
+				Product: model.Product{ID: productID, Status: model.ProductStatusStored},
+				PVZID:   destinationPVZID,
+			},
+			expectedError: model.ErrProductNotFound,
+		},
+		{
+			name:        "Product In Order",
+			destination: destinationPVZID,
+			productIDs:  []string{productID},
+			location: &model.ProductLocation{
+				Product: model.Product{ID: productID, Status: model.ProductStatusStored},
+				PVZID:   sourcePVZID,
+				OrderID: "123e4567-e89b-12d3-a456-426614174006",
+			},
+			expectedError: model.ErrProductInOrder,
+		},
+		{
+			name:        "Product Already In Transfer",
+			destination: destinationPVZID,
+			productIDs:  []string{productID},
+			location: &model.ProductLocation{
+				Product:    model.Product{ID: productID, Status: model.ProductStatusStored},
+				PVZID:      sourcePVZID,
+				TransferID: "323e4567-e89b-12d3-a456-426614174008",
+			},
+			expectedError: model.ErrProductInTransfer,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			getPVZ := tt.getPVZ
+			if getPVZ == nil {
+				getPVZ = activePVZ
+			}
+
+			transferRepo := &MockTransferRepository{
+				CreateTransferFunc: func(ctx context.Context, transfer model.Transfer) (*model.Transfer, error) {
+					transfer.ID = transferID
+					transfer.Status = model.TransferStatusCreated
+					return &transfer, nil
+				},
+			}
+			productRepo := &MockProductRepository{
+				GetProductLocationFunc: func(ctx context.Context, productID string) (*model.ProductLocation, error) {
+					return tt.location, nil
+				},
+			}
+			auditService := &MockAuditService{}
+
+			s := transfer.NewTransferService(
+				transferRepo, productRepo, &MockProductStatusRepository{}, &MockPVZRepository{GetPVZByIDFunc: getPVZ},
+				&MockReceptionRepository{}, &MockTransactor{}, auditService,
+			)
+			got, err := s.CreateTransfer(context.Background(), sourcePVZID, dto.TransferCreateRequest{
+				DestinationPVZID: tt.destination,
+				ProductIDs:       tt.productIDs,
+			})
+
+			if tt.expectedError != nil || tt.expectedMsg != "" {
+				if tt.expectedError != nil && !errors.Is(err, tt.expectedError) {
+					t.Errorf("CreateTransfer() error = %v, expected %v", err, tt.expectedError)
+				}
+				if tt.expectedMsg != "" && (err == nil || err.Error() != tt.expectedMsg) {
+					t.Errorf("CreateTransfer() error = %v, expected %q", err, tt.expectedMsg)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("CreateTransfer() unexpected error = %v", err)
+			}
+			if got.SourcePVZID != sourcePVZID || got.DestinationPVZID != destinationPVZID {
+				t.Errorf("Expected transfer %s -> %s, got %s -> %s", sourcePVZID, destinationPVZID, got.SourcePVZID, got.DestinationPVZID)
+			}
+			if !reflect.DeepEqual(auditService.Actions, []model.AuditAction{model.AuditActionCreate}) {
+				t.Errorf("Expected the transfer to be audited, got %v", auditService.Actions)
+			}
+		})
+	}
+}
+
+func TestTransferService_ShipTransfer(t *testing.T) {
+	tests := []struct {
+		name        string
+		pvzID       string
+		status      model.TransferStatus
+		expectedMsg string
+		expectedErr error
+	}{
+		{name: "Success", pvzID: sourcePVZID, status: model.TransferStatusCreated},
+		{name: "Shipped By Destination", pvzID: destinationPVZID, status: model.TransferStatusCreated, expectedMsg: "only the source PVZ can ship the transfer"},
+		{name: "Already Shipped", pvzID: sourcePVZID, status: model.TransferStatusInTransit, expectedMsg: "cannot ship a transfer that is in_transit"},
+		{name: "Unrelated PVZ", pvzID: "323e4567-e89b-12d3-a456-426614174003", status: model.TransferStatusCreated, expectedErr: model.ErrTransferNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			transferRepo := &MockTransferRepository{
+				GetTransferFunc: func(ctx context.Context, id string) (*model.Transfer, error) {
+					return &model.Transfer{
+						ID: id, SourcePVZID: sourcePVZID, DestinationPVZID: destinationPVZID, Status: tt.status, ProductIDs: []string{productID},
+					}, nil
+				},
+			}
+			productStatusRepo := &MockProductStatusRepository{}
+
+			s := transfer.NewTransferService(
+				transferRepo, &MockProductRepository{}, productStatusRepo, &MockPVZRepository{},
+				&MockReceptionRepository{}, &MockTransactor{}, &MockAuditService{},
+			)
+			got, err := s.ShipTransfer(context.Background(), tt.pvzID, transferID)
+
+			if tt.expectedMsg != "" || tt.expectedErr != nil {
+				if tt.expectedMsg != "" && (err == nil || err.Error() != tt.expectedMsg) {
+					t.Errorf("ShipTransfer() error = %v, expected %q", err, tt.expectedMsg)
+				}
+				if tt.expectedErr != nil && !errors.Is(err, tt.expectedErr) {
+					t.Errorf("ShipTransfer() error = %v, expected %v", err, tt.expectedErr)
+				}
+				if transferRepo.Shipped {
+					t.Error("Expected the transfer not to be shipped")
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("ShipTransfer() unexpected error = %v", err)
+			}
+			if got.Status != model.TransferStatusInTransit || got.ShippedAt == nil {
+				t.Errorf("Expected a shipped transfer, got %+v", got)
+			}
+			if !transferRepo.Shipped {
+				t.Error("Expected the transfer to be shipped")
+			}
+			if len(productStatusRepo.Changes) != 1 || productStatusRepo.Changes[0].ToStatus != model.ProductStatusInTransit {
+				t.Errorf("Expected the product to go in transit, got %v", productStatusRepo.Changes)
+			}
+		})
+	}
+}
+
+func TestTransferService_ReceiveTransfer(t *testing.T) {
+	tests := []struct {
+		name         string
+		pvzID        string
+		status       model.TransferStatus
+		pvzStatus    model.PVZStatus
+		receptionErr error
+		expectedMsg  string
+		expectedErr  error
+	}{
+		{name: "Success", pvzID: destinationPVZID, status: model.TransferStatusInTransit},
+		{name: "Destination Not Active", pvzID: destinationPVZID, status: model.TransferStatusInTransit, pvzStatus: model.PVZStatusSuspended, expectedErr: model.ErrPVZNotActive},
+		{name: "Received By Source", pvzID: sourcePVZID, status: model.TransferStatusInTransit, expectedMsg: "only the destination PVZ can receive the transfer"},
+		{name: "Not Shipped", pvzID: destinationPVZID, status: model.TransferStatusCreated, expectedMsg: "cannot receive a transfer that is created"},
+		{name: "No Open Reception", pvzID: destinationPVZID, status: model.TransferStatusInTransit, receptionErr: model.ErrNoOpenReception, expectedErr: model.ErrNoOpenReception},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			transferRepo := &MockTransferRepository{
+				GetTransferFunc: func(ctx context.Context, id string) (*model.Transfer, error) {
+					return &model.Transfer{
+						ID: id, SourcePVZID: sourcePVZID, DestinationPVZID: destinationPVZID, Status: tt.status, ProductIDs: []string{productID},
+					}, nil
+				},
+			}
+			receptionRepo := &MockReceptionRepository{
+				GetLastOpenReceptionForUpdateFunc: func(ctx context.Context, pvzID string) (*model.Reception, error) {
+					if tt.receptionErr != nil {
+						return nil, tt.receptionErr
+					}
+					return &model.Reception{ID: receptionID, PVZID: pvzID, Status: "in_progress"}, nil
+				},
+			}
+			productStatusRepo := &MockProductStatusRepository{}
+			var lockedPVZID string
+			pvzRepo := &MockPVZRepository{
+				GetPVZForUpdateFunc: func(ctx context.Context, pvzID string) (*model.PVZ, error) {
+					lockedPVZID = pvzID
+					status := model.PVZStatusActive
+					if tt.pvzStatus != "" {
+						status = tt.pvzStatus
+					}
+					return &model.PVZ{ID: pvzID, Status: status}, nil
+				},
+			}
+
+			s := transfer.NewTransferService(
+				transferRepo, &MockProductRepository{}, productStatusRepo, pvzRepo,
+				receptionRepo, &MockTransactor{}, &MockAuditService{},
+			)
+			got, err := s.ReceiveTransfer(context.Background(), tt.pvzID, transferID)
+
+			if tt.expectedMsg != "" || tt.expectedErr != nil {
+				if tt.expectedMsg != "" && (err == nil || err.Error() != tt.expectedMsg) {
+					t.Errorf("ReceiveTransfer() error = %v, expected %q", err, tt.expectedMsg)
+				}
+				if tt.expectedErr != nil && !errors.Is(err, tt.expectedErr) {
+					t.Errorf("ReceiveTransfer() error = %v, expected %v", err, tt.expectedErr)
+				}
+				if transferRepo.Received != "" {
+					t.Error("Expected the transfer not to be received")
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("ReceiveTransfer() unexpected error = %v", err)
+			}
+			if got.Status != model.TransferStatusReceived || got.ReceptionID != receptionID || got.ReceivedAt == nil {
+				t.Errorf("Expected a transfer received into %s, got %+v", receptionID, got)
+			}
+			if transferRepo.Received != receptionID {
+				t.Errorf("Expected the products to join reception %s, got %q", receptionID, transferRepo.Received)
+			}
+			if lockedPVZID != destinationPVZID {
+				t.Errorf("Expected destination PVZ %s to be locked, got %q", destinationPVZID, lockedPVZID)
+			}
+			if len(productStatusRepo.Changes) != 1 || productStatusRepo.Changes[0].ToStatus != model.ProductStatusReceived {
+				t.Errorf("Expected the product to be received, got %v", productStatusRepo.Changes)
+			}
+		})
+	}
+}
+
+func TestTransferService_GetProductTransfers(t *testing.T) {
+	transfers := []model.Transfer{
+		{ID: transferID, SourcePVZID: sourcePVZID, DestinationPVZID: destinationPVZID, Status: model.TransferStatusReceived, ProductIDs: []string{productID}},
+	}
+
+	tests := []struct {
+		name        string
+		pvzID       string
+		expectedErr error
+	}{
+		{name: "Current PVZ", pvzID: destinationPVZID},
+		{name: "Former PVZ", pvzID: sourcePVZID},
+		{name: "Unrelated PVZ", pvzID: "323e4567-e89b-12d3-a456-426614174003", expectedErr: model.ErrProductNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			transferRepo := &MockTransferRepository{
+				GetTransfersByProductIDFunc: func(ctx context.Context, productID string) ([]model.Transfer, error) {
+					return transfers, nil
+				},
+			}
+			productRepo := &MockProductRepository{
+				GetProductLocationFunc: func(ctx context.Context, productID string) (*model.ProductLocation, error) {
+					return &model.ProductLocation{
+						Product: model.Product{ID: productID, Status: model.ProductStatusReceived},
+						PVZID:   destinationPVZID,
+					}, nil
+				},
+			}
+
+			s := transfer.NewTransferService(
+				transferRepo, productRepo, &MockProductStatusRepository{}, &MockPVZRepository{},
+				&MockReceptionRepository{}, &MockTransactor{}, &MockAuditService{},
+			)
+			got, err := s.GetProductTransfers(context.Background(), tt.pvzID, productID)
+
+			if tt.expectedErr != nil {
+				if !errors.Is(err, tt.expectedErr) {
+					t.Errorf("GetProductTransfers() error = %v, expected %v", err, tt.expectedErr)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("GetProductTransfers() unexpected error = %v", err)
+			}
+			if !reflect.DeepEqual(got, transfers) {
+				t.Errorf("GetProductTransfers() = %v, expected %v", got, transfers)
+			}
+		})
+	}
+}
@@ -0,0 +1,52 @@
+package spreadsheet
+
+import (
+	"encoding/csv"
+	"io"
+)
+
+// utf8BOM makes Excel detect the encoding, without it Cyrillic text is
+// shown as mojibake.
+const utf8BOM = "\xEF\xBB\xBF"
+
+type CSVWriter struct {
+	writer *csv.Writer
+}
+
+func NewCSVWriter(w io.Writer) (*CSVWriter, error) {
+	if _, err := io.WriteString(w, utf8BOM); err != nil {
+		return nil, err
+	}
+
+	return &CSVWriter{writer: csv.NewWriter(w)}, nil
+}
+
+// WriteRow writes values as one record. Values that a spreadsheet would
+// take for a formula are prefixed with an apostrophe, so that opening an
+// export cannot run a formula planted in, say, a serial number.
+func (w *CSVWriter) WriteRow(values []string) error {
+	record := make([]string, len(values))
+	for i, value := range values {
+		record[i] = escapeFormula(value)
+	}
+
+	return w.writer.Write(record)
+}
+
+func (w *CSVWriter) Close() error {
+	w.writer.Flush()
+	return w.writer.Error()
+}
+
+func escapeFormula(value string) string {
+	if value == "" {
+		return value
+	}
+
+	switch value[0] {
+	case '=', '+', '-', '@':
+		return "'" + value
+	}
+
+	return value
+}
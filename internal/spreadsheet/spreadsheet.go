@@ -0,0 +1,41 @@
+// Package spreadsheet writes tabular data row by row, so that exports of any
+// size can be streamed without holding them in memory.
+package spreadsheet
+
+import (
+	"fmt"
+	"io"
+)
+
+const (
+	FormatCSV  = "csv"
+	FormatXLSX = "xlsx"
+)
+
+type Writer interface {
+	WriteRow(values []string) error
+	// Close flushes buffered data. It does not close the underlying writer.
+	Close() error
+}
+
+func NewWriter(format string, w io.Writer) (Writer, error) {
+	switch format {
+	case FormatCSV:
+		return NewCSVWriter(w)
+	case FormatXLSX:
+		return NewXLSXWriter(w)
+	default:
+		return nil, fmt.Errorf("unsupported format %q", format)
+	}
+}
+
+func ContentType(format string) string {
+	switch format {
+	case FormatCSV:
+		return "text/csv; charset=utf-8"
+	case FormatXLSX:
+		return "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+	default:
+		return "application/octet-stream"
+	}
+}
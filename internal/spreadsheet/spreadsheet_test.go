@@ -0,0 +1,130 @@
+package spreadsheet_test
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"io"
+	"testing"
+
+	"github.com/kirillidk/pvz-service/internal/spreadsheet"
+)
+
+var testRows = [][]string{
+	{"Город", "Тип товара"},
+	{"Москва", "электроника"},
+	{"Казань", `обувь, "детская" <&>`},
+}
+
+func TestCSVWriter(t *testing.T) {
+	var buf bytes.Buffer
+
+	w, err := spreadsheet.NewWriter(spreadsheet.FormatCSV, &buf)
+	if err != nil {
+		t.Fatalf("NewWriter() error = %v", err)
+	}
+	for _, row := range testRows {
+		if err := w.WriteRow(row); err != nil {
+			t.Fatalf("WriteRow() error = %v", err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+
+	expected := "\xEF\xBB\xBFГород,Тип товара\nМосква,электроника\nКазань,\"обувь, \"\"детская\"\" <&>\"\n"
+	if buf.String() != expected {
+		t.Errorf("CSV output = %q, expected %q", buf.String(), expected)
+	}
+}
+
+func TestCSVWriter_EscapesFormulas(t *testing.T) {
+	var buf bytes.Buffer
+
+	w, err := spreadsheet.NewWriter(spreadsheet.FormatCSV, &buf)
+	if err != nil {
+		t.Fatalf("NewWriter() error = %v", err)
+	}
+	if err := w.WriteRow([]string{"=HYPERLINK(\"http://x\")", "+1", "-1", "@SUM(A1)", "a=b", ""}); err != nil {
+		t.Fatalf("WriteRow() error = %v", err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+
+	expected := "\xEF\xBB\xBF\"'=HYPERLINK(\"\"http://x\"\")\",'+1,'-1,'@SUM(A1),a=b,\n"
+	if buf.String() != expected {
+		t.Errorf("CSV output = %q, expected %q", buf.String(), expected)
+	}
+}
+
+func TestXLSXWriter(t *testing.T) {
+	var buf bytes.Buffer
+
+	w, err := spreadsheet.NewWriter(spreadsheet.FormatXLSX, &buf)
+	if err != nil {
+		t.Fatalf("NewWriter() error = %v", err)
+	}
+	for _, row := range testRows {
+		if err := w.WriteRow(row); err != nil {
+			t.Fatalf("WriteRow() error = %v", err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+
+	archive, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatalf("output is not a zip archive: %v", err)
+	}
+
+	var sheet []byte
+	names := make(map[string]bool)
+	for _, file := range archive.File {
+		names[file.Name] = true
+		if file.Name != "xl/worksheets/sheet1.xml" {
+			continue
+		}
+		r, err := file.Open()
+		if err != nil {
+			t.Fatalf("failed to open sheet: %v", err)
+		}
+		sheet, _ = io.ReadAll(r)
+		r.Close()
+	}
+
+	for _, name := range []string{"[Content_Types].xml", "_rels/.rels", "xl/workbook.xml", "xl/_rels/workbook.xml.rels"} {
+		if !names[name] {
+			t.Errorf("workbook part %s is missing", name)
+		}
+	}
+
+	var parsed struct {
+		Rows []struct {
+			Cells []struct {
+				Text string `xml:"is>t"`
+			} `xml:"c"`
+		} `xml:"sheetData>row"`
+	}
+	if err := xml.Unmarshal(sheet, &parsed); err != nil {
+		t.Fatalf("sheet is not valid XML: %v", err)
+	}
+
+	if len(parsed.Rows) != len(testRows) {
+		t.Fatalf("sheet has %d rows, expected %d", len(parsed.Rows), len(testRows))
+	}
+	for i, row := range parsed.Rows {
+		for j, cell := range row.Cells {
+			if cell.Text != testRows[i][j] {
+				t.Errorf("cell %d:%d = %q, expected %q", i, j, cell.Text, testRows[i][j])
+			}
+		}
+	}
+}
+
+func TestNewWriter_UnsupportedFormat(t *testing.T) {
+	if _, err := spreadsheet.NewWriter("pdf", io.Discard); err == nil {
+		t.Error("NewWriter() expected error for unsupported format")
+	}
+}
@@ -0,0 +1,104 @@
+package spreadsheet
+
+import (
+	"archive/zip"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strconv"
+)
+
+// The parts of a minimal workbook with a single sheet. Cells are written as
+// inline strings, so no shared string table or styles are needed.
+const (
+	xlsxContentTypes = xml.Header + `<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types">` +
+		`<Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/>` +
+		`<Default Extension="xml" ContentType="application/xml"/>` +
+		`<Override PartName="/xl/workbook.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/>` +
+		`<Override PartName="/xl/worksheets/sheet1.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/>` +
+		`</Types>`
+
+	xlsxRootRels = xml.Header + `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+		`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="xl/workbook.xml"/>` +
+		`</Relationships>`
+
+	xlsxWorkbook = xml.Header + `<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" ` +
+		`xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships">` +
+		`<sheets><sheet name="Sheet1" sheetId="1" r:id="rId1"/></sheets>` +
+		`</workbook>`
+
+	xlsxWorkbookRels = xml.Header + `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+		`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet1.xml"/>` +
+		`</Relationships>`
+
+	xlsxSheetStart = xml.Header + `<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData>`
+	xlsxSheetEnd   = `</sheetData></worksheet>`
+)
+
+type XLSXWriter struct {
+	archive *zip.Writer
+	sheet   io.Writer
+	rows    int
+}
+
+func NewXLSXWriter(w io.Writer) (*XLSXWriter, error) {
+	archive := zip.NewWriter(w)
+
+	parts := []struct{ name, content string }{
+		{"[Content_Types].xml", xlsxContentTypes},
+		{"_rels/.rels", xlsxRootRels},
+		{"xl/workbook.xml", xlsxWorkbook},
+		{"xl/_rels/workbook.xml.rels", xlsxWorkbookRels},
+	}
+
+	for _, part := range parts {
+		partWriter, err := archive.Create(part.name)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create %s: %w", part.name, err)
+		}
+		if _, err := io.WriteString(partWriter, part.content); err != nil {
+			return nil, fmt.Errorf("failed to write %s: %w", part.name, err)
+		}
+	}
+
+	// The sheet must be the last part: it stays open while rows are added.
+	sheet, err := archive.Create("xl/worksheets/sheet1.xml")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create sheet: %w", err)
+	}
+	if _, err := io.WriteString(sheet, xlsxSheetStart); err != nil {
+		return nil, fmt.Errorf("failed to write sheet: %w", err)
+	}
+
+	return &XLSXWriter{archive: archive, sheet: sheet}, nil
+}
+
+func (w *XLSXWriter) WriteRow(values []string) error {
+	w.rows++
+
+	if _, err := io.WriteString(w.sheet, `<row r="`+strconv.Itoa(w.rows)+`">`); err != nil {
+		return err
+	}
+
+	for _, value := range values {
+		if _, err := io.WriteString(w.sheet, `<c t="inlineStr"><is><t xml:space="preserve">`); err != nil {
+			return err
+		}
+		if err := xml.EscapeText(w.sheet, []byte(value)); err != nil {
+			return err
+		}
+		if _, err := io.WriteString(w.sheet, `</t></is></c>`); err != nil {
+			return err
+		}
+	}
+
+	_, err := io.WriteString(w.sheet, `</row>`)
+	return err
+}
+
+func (w *XLSXWriter) Close() error {
+	if _, err := io.WriteString(w.sheet, xlsxSheetEnd); err != nil {
+		return err
+	}
+	return w.archive.Close()
+}
@@ -0,0 +1,29 @@
+CREATE TABLE IF NOT EXISTS audit_log (
+    id BIGSERIAL PRIMARY KEY,
+    created_at TIMESTAMP NOT NULL,
+    actor_id VARCHAR(64),
+    actor_type VARCHAR(20) NOT NULL,
+    actor_role VARCHAR(20),
+    action VARCHAR(50) NOT NULL,
+    entity_type VARCHAR(50) NOT NULL,
+    entity_id UUID NOT NULL,
+    before_state JSON,
+    after_state JSON,
+    request_id VARCHAR(64),
+    prev_hash CHAR(64) NOT NULL,
+    hash CHAR(64) NOT NULL UNIQUE
+);
+
+CREATE INDEX IF NOT EXISTS idx_audit_log_entity ON audit_log (entity_type, entity_id);
+CREATE INDEX IF NOT EXISTS idx_audit_log_actor_id ON audit_log (actor_id);
+CREATE INDEX IF NOT EXISTS idx_audit_log_created_at ON audit_log (created_at);
+
+CREATE OR REPLACE FUNCTION audit_log_immutable() RETURNS TRIGGER AS $$
+BEGIN
+    RAISE EXCEPTION 'audit_log is append-only';
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER audit_log_no_update_delete
+    BEFORE UPDATE OR DELETE ON audit_log
+    FOR EACH ROW EXECUTE FUNCTION audit_log_immutable();
@@ -0,0 +1,6 @@
+ALTER TABLE pvz DROP CONSTRAINT IF EXISTS pvz_city_fkey;
+
+ALTER TABLE pvz
+    ADD CONSTRAINT pvz_city_check CHECK (city IN ('Москва', 'Санкт-Петербург', 'Казань'));
+
+DROP TABLE IF EXISTS cities;
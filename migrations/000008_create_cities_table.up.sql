@@ -0,0 +1,21 @@
+CREATE EXTENSION IF NOT EXISTS "uuid-ossp";
+
+CREATE TABLE IF NOT EXISTS cities (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    name VARCHAR(50) NOT NULL UNIQUE,
+    region VARCHAR(100) NOT NULL DEFAULT '',
+    timezone VARCHAR(64) NOT NULL,
+    active BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+INSERT INTO cities (name, region, timezone) VALUES
+    ('Москва', 'Москва', 'Europe/Moscow'),
+    ('Санкт-Петербург', 'Санкт-Петербург', 'Europe/Moscow'),
+    ('Казань', 'Республика Татарстан', 'Europe/Moscow')
+ON CONFLICT (name) DO NOTHING;
+
+ALTER TABLE pvz DROP CONSTRAINT IF EXISTS pvz_city_check;
+
+ALTER TABLE pvz
+    ADD CONSTRAINT pvz_city_fkey FOREIGN KEY (city) REFERENCES cities (name) ON UPDATE CASCADE;
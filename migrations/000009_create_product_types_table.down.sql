@@ -0,0 +1,8 @@
+ALTER TABLE products
+    DROP CONSTRAINT IF EXISTS products_type_fkey,
+    DROP COLUMN IF EXISTS serial_number;
+
+ALTER TABLE products
+    ADD CONSTRAINT products_type_check CHECK (type IN ('электроника', 'одежда', 'обувь'));
+
+DROP TABLE IF EXISTS product_types;
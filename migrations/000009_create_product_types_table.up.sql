@@ -0,0 +1,19 @@
+CREATE TABLE IF NOT EXISTS product_types (
+    code VARCHAR(20) PRIMARY KEY,
+    name VARCHAR(100) NOT NULL,
+    active BOOLEAN NOT NULL DEFAULT TRUE,
+    requires_serial_number BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+INSERT INTO product_types (code, name, requires_serial_number) VALUES
+    ('электроника', 'Электроника', FALSE),
+    ('одежда', 'Одежда', FALSE),
+    ('обувь', 'Обувь', FALSE)
+ON CONFLICT (code) DO NOTHING;
+
+ALTER TABLE products DROP CONSTRAINT IF EXISTS products_type_check;
+
+ALTER TABLE products
+    ADD COLUMN IF NOT EXISTS serial_number VARCHAR(100),
+    ADD CONSTRAINT products_type_fkey FOREIGN KEY (type) REFERENCES product_types (code);
@@ -0,0 +1,6 @@
+ALTER TABLE pvz
+    DROP COLUMN IF EXISTS working_hours,
+    DROP COLUMN IF EXISTS phone,
+    DROP COLUMN IF EXISTS longitude,
+    DROP COLUMN IF EXISTS latitude,
+    DROP COLUMN IF EXISTS address;
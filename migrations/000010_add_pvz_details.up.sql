@@ -0,0 +1,6 @@
+ALTER TABLE pvz
+    ADD COLUMN IF NOT EXISTS address VARCHAR(255) NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS latitude DOUBLE PRECISION CHECK (latitude BETWEEN -90 AND 90),
+    ADD COLUMN IF NOT EXISTS longitude DOUBLE PRECISION CHECK (longitude BETWEEN -180 AND 180),
+    ADD COLUMN IF NOT EXISTS phone VARCHAR(20) NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS working_hours JSONB;
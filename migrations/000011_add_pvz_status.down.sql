@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_pvz_status;
+
+ALTER TABLE pvz DROP COLUMN IF EXISTS status;
@@ -0,0 +1,5 @@
+ALTER TABLE pvz
+    ADD COLUMN IF NOT EXISTS status VARCHAR(20) NOT NULL DEFAULT 'active'
+        CHECK (status IN ('active', 'suspended', 'archived'));
+
+CREATE INDEX IF NOT EXISTS idx_pvz_status ON pvz (status);
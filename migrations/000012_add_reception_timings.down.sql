@@ -0,0 +1,6 @@
+DROP INDEX IF EXISTS idx_receptions_date_time;
+
+ALTER TABLE receptions
+    DROP COLUMN IF EXISTS last_product_at,
+    DROP COLUMN IF EXISTS first_product_at,
+    DROP COLUMN IF EXISTS closed_at;
@@ -0,0 +1,18 @@
+ALTER TABLE receptions
+    ADD COLUMN IF NOT EXISTS closed_at TIMESTAMP,
+    ADD COLUMN IF NOT EXISTS first_product_at TIMESTAMP,
+    ADD COLUMN IF NOT EXISTS last_product_at TIMESTAMP;
+
+-- The close time of receptions closed before this migration is unknown, so
+-- closed_at stays NULL for them and they are left out of timing statistics.
+UPDATE receptions r
+SET first_product_at = p.first_product_at,
+    last_product_at = p.last_product_at
+FROM (
+    SELECT reception_id, MIN(date_time) AS first_product_at, MAX(date_time) AS last_product_at
+    FROM products
+    GROUP BY reception_id
+) p
+WHERE p.reception_id = r.id AND r.status = 'close';
+
+CREATE INDEX IF NOT EXISTS idx_receptions_date_time ON receptions (date_time);
@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_products_reception_barcode;
+
+DROP INDEX IF EXISTS idx_products_barcode;
+
+ALTER TABLE products DROP COLUMN IF EXISTS barcode;
\ No newline at end of file
@@ -0,0 +1,5 @@
+ALTER TABLE products ADD COLUMN IF NOT EXISTS barcode VARCHAR(64);
+
+CREATE INDEX IF NOT EXISTS idx_products_barcode ON products (barcode) WHERE barcode IS NOT NULL;
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_products_reception_barcode ON products (reception_id, barcode) WHERE barcode IS NOT NULL;
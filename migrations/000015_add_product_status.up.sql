@@ -0,0 +1,17 @@
+ALTER TABLE products
+    ADD COLUMN IF NOT EXISTS status VARCHAR(30) NOT NULL DEFAULT 'received'
+        CHECK (status IN ('received', 'stored', 'issued', 'returned_to_sender'));
+
+CREATE TABLE IF NOT EXISTS product_status_history (
+    id BIGSERIAL PRIMARY KEY,
+    product_id UUID NOT NULL REFERENCES products(id) ON DELETE CASCADE,
+    from_status VARCHAR(30) NOT NULL,
+    to_status VARCHAR(30) NOT NULL,
+    changed_at TIMESTAMP NOT NULL,
+    actor_id VARCHAR(64),
+    actor_type VARCHAR(20) NOT NULL,
+    actor_role VARCHAR(20)
+);
+
+CREATE INDEX IF NOT EXISTS idx_product_status_history_product_id ON product_status_history (product_id);
+CREATE INDEX IF NOT EXISTS idx_product_status_history_to_status ON product_status_history (to_status, changed_at);
@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_products_order_id;
+
+ALTER TABLE products DROP COLUMN IF EXISTS order_id;
+
+DROP TABLE IF EXISTS orders;
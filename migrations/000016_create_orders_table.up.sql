@@ -0,0 +1,15 @@
+CREATE TABLE IF NOT EXISTS orders (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    pvz_id UUID NOT NULL REFERENCES pvz(id),
+    recipient VARCHAR(255) NOT NULL,
+    pickup_code_hash VARCHAR(255) NOT NULL,
+    failed_attempts INTEGER NOT NULL DEFAULT 0,
+    created_at TIMESTAMP NOT NULL,
+    issued_at TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_orders_pvz_id ON orders (pvz_id);
+
+ALTER TABLE products ADD COLUMN IF NOT EXISTS order_id UUID REFERENCES orders(id);
+
+CREATE INDEX IF NOT EXISTS idx_products_order_id ON products (order_id) WHERE order_id IS NOT NULL;
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS return_tasks;
+
+ALTER TABLE product_types DROP COLUMN IF EXISTS storage_days;
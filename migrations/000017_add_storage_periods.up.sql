@@ -0,0 +1,12 @@
+ALTER TABLE product_types
+    ADD COLUMN IF NOT EXISTS storage_days INTEGER CHECK (storage_days > 0);
+
+CREATE TABLE IF NOT EXISTS return_tasks (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    product_id UUID NOT NULL UNIQUE REFERENCES products(id) ON DELETE CASCADE,
+    pvz_id UUID NOT NULL REFERENCES pvz(id),
+    storage_deadline TIMESTAMP NOT NULL,
+    created_at TIMESTAMP NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_return_tasks_pvz_id ON return_tasks (pvz_id, storage_deadline);
@@ -0,0 +1,14 @@
+CREATE TABLE IF NOT EXISTS storage_cells (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    pvz_id UUID NOT NULL REFERENCES pvz(id),
+    code VARCHAR(20) NOT NULL,
+    capacity INTEGER NOT NULL CHECK (capacity > 0),
+    product_type VARCHAR(20) REFERENCES product_types(code),
+    created_at TIMESTAMP NOT NULL,
+    UNIQUE (pvz_id, code)
+);
+
+ALTER TABLE products
+    ADD COLUMN IF NOT EXISTS cell_id UUID REFERENCES storage_cells(id) ON DELETE SET NULL;
+
+CREATE INDEX IF NOT EXISTS idx_products_cell_id ON products (cell_id) WHERE cell_id IS NOT NULL;
@@ -0,0 +1,11 @@
+ALTER TABLE products DROP CONSTRAINT IF EXISTS products_status_check;
+ALTER TABLE products ADD CONSTRAINT products_status_check
+    CHECK (status IN ('received', 'stored', 'issued', 'returned_to_sender'));
+
+DROP INDEX IF EXISTS idx_products_transfer_id;
+
+ALTER TABLE products DROP COLUMN IF EXISTS transfer_id;
+
+DROP TABLE IF EXISTS transfer_products;
+
+DROP TABLE IF EXISTS transfers;
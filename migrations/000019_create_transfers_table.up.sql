@@ -0,0 +1,30 @@
+CREATE TABLE IF NOT EXISTS transfers (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    source_pvz_id UUID NOT NULL REFERENCES pvz(id),
+    destination_pvz_id UUID NOT NULL REFERENCES pvz(id),
+    status VARCHAR(20) NOT NULL DEFAULT 'created' CHECK (status IN ('created', 'in_transit', 'received')),
+    reception_id UUID REFERENCES receptions(id),
+    created_at TIMESTAMP NOT NULL,
+    shipped_at TIMESTAMP,
+    received_at TIMESTAMP,
+    CHECK (source_pvz_id <> destination_pvz_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_transfers_source_pvz_id ON transfers (source_pvz_id, created_at);
+CREATE INDEX IF NOT EXISTS idx_transfers_destination_pvz_id ON transfers (destination_pvz_id, created_at);
+
+CREATE TABLE IF NOT EXISTS transfer_products (
+    transfer_id UUID NOT NULL REFERENCES transfers(id) ON DELETE CASCADE,
+    product_id UUID NOT NULL REFERENCES products(id) ON DELETE CASCADE,
+    PRIMARY KEY (transfer_id, product_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_transfer_products_product_id ON transfer_products (product_id);
+
+ALTER TABLE products ADD COLUMN IF NOT EXISTS transfer_id UUID REFERENCES transfers(id);
+
+CREATE INDEX IF NOT EXISTS idx_products_transfer_id ON products (transfer_id) WHERE transfer_id IS NOT NULL;
+
+ALTER TABLE products DROP CONSTRAINT IF EXISTS products_status_check;
+ALTER TABLE products ADD CONSTRAINT products_status_check
+    CHECK (status IN ('received', 'stored', 'issued', 'returned_to_sender', 'in_transit'));
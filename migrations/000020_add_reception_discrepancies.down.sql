@@ -0,0 +1,9 @@
+DROP TABLE IF EXISTS product_attachments;
+
+DROP INDEX IF EXISTS idx_products_condition;
+
+ALTER TABLE products
+    DROP COLUMN IF EXISTS condition_note,
+    DROP COLUMN IF EXISTS condition;
+
+ALTER TABLE receptions DROP COLUMN IF EXISTS expected_count;
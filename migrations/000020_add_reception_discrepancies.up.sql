@@ -0,0 +1,21 @@
+ALTER TABLE receptions
+    ADD COLUMN IF NOT EXISTS expected_count INTEGER CHECK (expected_count >= 0);
+
+ALTER TABLE products
+    ADD COLUMN IF NOT EXISTS condition VARCHAR(20) NOT NULL DEFAULT 'ok'
+        CHECK (condition IN ('ok', 'damaged', 'opened')),
+    ADD COLUMN IF NOT EXISTS condition_note TEXT;
+
+CREATE INDEX IF NOT EXISTS idx_products_condition ON products (reception_id) WHERE condition <> 'ok';
+
+CREATE TABLE IF NOT EXISTS product_attachments (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    product_id UUID NOT NULL REFERENCES products(id) ON DELETE CASCADE,
+    file_name VARCHAR(255) NOT NULL,
+    content_type VARCHAR(100) NOT NULL,
+    size BIGINT NOT NULL,
+    storage_key VARCHAR(255) NOT NULL UNIQUE,
+    created_at TIMESTAMP NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_product_attachments_product_id ON product_attachments (product_id);
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS expected_delivery_items;
+
+DROP TABLE IF EXISTS expected_deliveries;
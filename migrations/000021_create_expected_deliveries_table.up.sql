@@ -0,0 +1,20 @@
+CREATE TABLE IF NOT EXISTS expected_deliveries (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    pvz_id UUID NOT NULL REFERENCES pvz(id),
+    status VARCHAR(20) NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'receiving', 'reconciled')),
+    reception_id UUID UNIQUE REFERENCES receptions(id),
+    created_at TIMESTAMP NOT NULL,
+    reconciled_at TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_expected_deliveries_pvz_id ON expected_deliveries (pvz_id, created_at);
+
+CREATE TABLE IF NOT EXISTS expected_delivery_items (
+    delivery_id UUID NOT NULL REFERENCES expected_deliveries(id) ON DELETE CASCADE,
+    barcode VARCHAR(64) NOT NULL,
+    type VARCHAR(20) NOT NULL,
+    product_id UUID REFERENCES products(id) ON DELETE SET NULL,
+    PRIMARY KEY (delivery_id, barcode)
+);
+
+CREATE INDEX IF NOT EXISTS idx_expected_delivery_items_product_id ON expected_delivery_items (product_id) WHERE product_id IS NOT NULL;
@@ -0,0 +1,19 @@
+CREATE TABLE IF NOT EXISTS reception_status_history (
+    id BIGSERIAL PRIMARY KEY,
+    reception_id UUID NOT NULL REFERENCES receptions(id) ON DELETE CASCADE,
+    from_status VARCHAR(20),
+    to_status VARCHAR(20) NOT NULL,
+    reason TEXT,
+    changed_at TIMESTAMP NOT NULL,
+    actor_id VARCHAR(64),
+    actor_type VARCHAR(20) NOT NULL,
+    actor_role VARCHAR(20)
+);
+
+CREATE INDEX IF NOT EXISTS idx_reception_status_history_reception_id ON reception_status_history (reception_id);
+
+INSERT INTO reception_status_history (reception_id, from_status, to_status, changed_at, actor_type)
+SELECT id, NULL, 'in_progress', date_time, 'system' FROM receptions;
+
+INSERT INTO reception_status_history (reception_id, from_status, to_status, changed_at, actor_type)
+SELECT id, 'in_progress', 'close', closed_at, 'system' FROM receptions WHERE status = 'close' AND closed_at IS NOT NULL;
@@ -0,0 +1 @@
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS code_regenerations INTEGER NOT NULL DEFAULT 0;
@@ -0,0 +1,4 @@
+ALTER TABLE users
+    DROP COLUMN IF EXISTS totp_locked_until,
+    DROP COLUMN IF EXISTS totp_failed_attempts,
+    DROP COLUMN IF EXISTS totp_last_step;
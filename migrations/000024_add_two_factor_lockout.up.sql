@@ -0,0 +1,4 @@
+ALTER TABLE users
+    ADD COLUMN IF NOT EXISTS totp_last_step BIGINT,
+    ADD COLUMN IF NOT EXISTS totp_failed_attempts INTEGER NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS totp_locked_until TIMESTAMP;
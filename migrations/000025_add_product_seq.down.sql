@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_products_reception_seq;
+
+ALTER TABLE products DROP COLUMN IF EXISTS seq;
+
+DROP SEQUENCE IF EXISTS products_seq_seq;
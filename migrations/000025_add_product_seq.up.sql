@@ -0,0 +1,16 @@
+ALTER TABLE products ADD COLUMN IF NOT EXISTS seq BIGINT;
+
+CREATE SEQUENCE IF NOT EXISTS products_seq_seq OWNED BY products.seq;
+
+UPDATE products
+SET seq = numbered.n
+FROM (SELECT id, ROW_NUMBER() OVER (ORDER BY date_time, id) AS n FROM products) AS numbered
+WHERE products.id = numbered.id;
+
+SELECT setval('products_seq_seq', COALESCE((SELECT MAX(seq) FROM products), 0) + 1, false);
+
+ALTER TABLE products
+    ALTER COLUMN seq SET DEFAULT nextval('products_seq_seq'),
+    ALTER COLUMN seq SET NOT NULL;
+
+CREATE INDEX IF NOT EXISTS idx_products_reception_seq ON products (reception_id, seq);
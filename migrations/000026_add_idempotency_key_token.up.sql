@@ -0,0 +1 @@
+ALTER TABLE idempotency_keys ADD COLUMN IF NOT EXISTS token VARCHAR(64) NOT NULL DEFAULT '';
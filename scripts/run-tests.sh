@@ -5,9 +5,27 @@ set -e
 go test -cover ./cmd/integration
 go test -cover ./internal/handler
 go test -cover ./internal/middleware
+go test -cover ./internal/model
+go test -cover ./internal/notifier
 go test -cover ./internal/repository
+go test -cover ./internal/service/apikey
+go test -cover ./internal/service/audit
 go test -cover ./internal/service/auth
+go test -cover ./internal/service/city
+go test -cover ./internal/service/export
+go test -cover ./internal/service/idempotency
 go test -cover ./internal/service/grpc
+go test -cover ./internal/service/order
+go test -cover ./internal/service/overdue
+go test -cover ./internal/service/storagecell
+go test -cover ./internal/service/transfer
+go test -cover ./internal/service/productinspection
+go test -cover ./internal/filestorage
+go test -cover ./internal/service/expecteddelivery
 go test -cover ./internal/service/product
+go test -cover ./internal/service/productlifecycle
+go test -cover ./internal/service/producttype
 go test -cover ./internal/service/pvz
-go test -cover ./internal/service/reception
\ No newline at end of file
+go test -cover ./internal/service/reception
+go test -cover ./internal/service/stats
+go test -cover ./internal/spreadsheet
\ No newline at end of file